@@ -0,0 +1,355 @@
+// Package charts renders report visualizations as SVG images
+package charts
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"strings"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// Chart dimensions and margins, in pixels
+const (
+	width        = 640
+	height       = 360
+	marginLeft   = 64
+	marginRight  = 24
+	marginTop    = 48
+	marginBottom = 72
+	// labelLength is the longest axis label before it is shortened
+	labelLength = 14
+	// yTicks is how many gridlines the value axis has
+	yTicks = 5
+)
+
+// ErrNoData is returned for visualizations with nothing to draw
+var ErrNoData = errors.New("no chart data")
+
+// palette colors bars, lines and pie slices in turn
+var palette = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+// Supported reports whether a visualization type can be rendered
+func Supported(visualizationType string) bool {
+	switch visualizationType {
+	case "bar_chart", "histogram", "time_series", "pie_chart":
+		return true
+	}
+	return false
+}
+
+// RenderSVG draws a visualization as an SVG image. Bar charts and pie charts
+// take "labels" and "values", time series "timestamps" and "values", and
+// histograms "bucket_edges" and "counts".
+func RenderSVG(v schemas.Visualization) ([]byte, error) {
+	data, ok := v.Data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("visualization %q: %w", v.Title, ErrNoData)
+	}
+
+	var body string
+	var err error
+	switch v.Type {
+	case "bar_chart":
+		var labels []string
+		var values []float64
+		if labels, values, err = series(data, "labels", "values"); err == nil {
+			body = barChart(labels, values, false)
+		}
+	case "histogram":
+		body, err = histogramChart(data)
+	case "time_series":
+		var labels []string
+		var values []float64
+		if labels, values, err = series(data, "timestamps", "values"); err == nil {
+			body = lineChart(labels, values)
+		}
+	case "pie_chart":
+		var labels []string
+		var values []float64
+		if labels, values, err = series(data, "labels", "values"); err == nil {
+			body = pieChart(labels, values)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported visualization type %q", v.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", v.Type, v.Title, err)
+	}
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif">`+"\n", width, height, width, height)
+	fmt.Fprintf(&svg, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+	fmt.Fprintf(&svg, `<text x="%d" y="28" font-size="16" font-weight="bold" text-anchor="middle">%s</text>`+"\n", width/2, html.EscapeString(v.Title))
+	svg.WriteString(body)
+	svg.WriteString("</svg>\n")
+	return []byte(svg.String()), nil
+}
+
+// barChart draws one bar per label; adjacent bars touch for histograms
+func barChart(labels []string, values []float64, adjacent bool) string {
+	var svg strings.Builder
+	top := niceMax(values)
+	svg.WriteString(valueAxis(top))
+
+	plotWidth := float64(width - marginLeft - marginRight)
+	slot := plotWidth / float64(len(values))
+	gap := slot * 0.2
+	if adjacent {
+		gap = 1
+	}
+	for i, value := range values {
+		barHeight := value / top * float64(height-marginTop-marginBottom)
+		x := float64(marginLeft) + float64(i)*slot + gap/2
+		y := float64(height-marginBottom) - barHeight
+		color := palette[0]
+		if !adjacent {
+			color = palette[i%len(palette)]
+		}
+		fmt.Fprintf(&svg, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s: %s</title></rect>`+"\n",
+			x, y, slot-gap, barHeight, color, html.EscapeString(labels[i]), formatValue(value))
+	}
+	svg.WriteString(categoryLabels(labels, slot))
+	return svg.String()
+}
+
+// histogramChart draws a histogram's buckets as adjacent bars labelled by their lower edge
+func histogramChart(data map[string]interface{}) (string, error) {
+	edges, ok := numbers(data["bucket_edges"])
+	if !ok {
+		return "", fmt.Errorf("bucket_edges must be a list of numbers")
+	}
+	counts, ok := numbers(data["counts"])
+	if !ok {
+		return "", fmt.Errorf("counts must be a list of numbers")
+	}
+	if len(counts) == 0 {
+		return "", ErrNoData
+	}
+	if len(edges) < len(counts) {
+		return "", fmt.Errorf("need a bucket edge for every count")
+	}
+	labels := make([]string, len(counts))
+	for i := range counts {
+		labels[i] = formatValue(edges[i])
+	}
+	return barChart(labels, counts, true), nil
+}
+
+// lineChart draws values as a line over evenly spaced points in time
+func lineChart(labels []string, values []float64) string {
+	var svg strings.Builder
+	top := niceMax(values)
+	svg.WriteString(valueAxis(top))
+
+	plotWidth := float64(width - marginLeft - marginRight)
+	plotHeight := float64(height - marginTop - marginBottom)
+	step := 0.0
+	if len(values) > 1 {
+		step = plotWidth / float64(len(values)-1)
+	}
+	points := make([]string, len(values))
+	for i, value := range values {
+		x := float64(marginLeft) + float64(i)*step
+		if len(values) == 1 {
+			x = float64(marginLeft) + plotWidth/2
+		}
+		y := float64(height-marginBottom) - value/top*plotHeight
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+		fmt.Fprintf(&svg, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"><title>%s: %s</title></circle>`+"\n",
+			x, y, palette[0], html.EscapeString(labels[i]), formatValue(value))
+	}
+	fmt.Fprintf(&svg, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n", strings.Join(points, " "), palette[0])
+
+	// Label the first, middle and last points so long series stay readable
+	for _, i := range []int{0, len(values) / 2, len(values) - 1} {
+		if i > 0 && i == len(values)/2 && len(values) < 3 {
+			continue
+		}
+		x := float64(marginLeft) + float64(i)*step
+		if len(values) == 1 {
+			x = float64(marginLeft) + plotWidth/2
+		}
+		fmt.Fprintf(&svg, `<text x="%.1f" y="%d" font-size="11" text-anchor="middle">%s</text>`+"\n",
+			x, height-marginBottom+18, html.EscapeString(shorten(labels[i], 20)))
+	}
+	return svg.String()
+}
+
+// pieChart draws each value's share of the total as a slice, with a legend
+func pieChart(labels []string, values []float64) string {
+	var svg strings.Builder
+	total := 0.0
+	for _, value := range values {
+		total += math.Max(0, value)
+	}
+	cx, cy, r := 200.0, float64(height+marginTop-16)/2, 120.0
+	if total == 0 {
+		fmt.Fprintf(&svg, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="#eeeeee"/>`+"\n", cx, cy, r)
+		return svg.String()
+	}
+
+	angle := -math.Pi / 2
+	for i, value := range values {
+		if value <= 0 {
+			continue
+		}
+		color := palette[i%len(palette)]
+		share := value / total
+		if share >= 1 {
+			fmt.Fprintf(&svg, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s"/>`+"\n", cx, cy, r, color)
+			break
+		}
+		end := angle + share*2*math.Pi
+		largeArc := 0
+		if share > 0.5 {
+			largeArc = 1
+		}
+		fmt.Fprintf(&svg, `<path d="M %.1f %.1f L %.1f %.1f A %.1f %.1f 0 %d 1 %.1f %.1f Z" fill="%s" stroke="#ffffff"><title>%s: %s</title></path>`+"\n",
+			cx, cy, cx+r*math.Cos(angle), cy+r*math.Sin(angle), r, r, largeArc, cx+r*math.Cos(end), cy+r*math.Sin(end),
+			color, html.EscapeString(labels[i]), formatValue(value))
+		angle = end
+	}
+
+	for i, label := range labels {
+		y := marginTop + 20 + i*22
+		if y > height-16 {
+			break
+		}
+		fmt.Fprintf(&svg, `<rect x="380" y="%d" width="12" height="12" fill="%s"/>`+"\n", y-10, palette[i%len(palette)])
+		fmt.Fprintf(&svg, `<text x="400" y="%d" font-size="12">%s (%.0f%%)</text>`+"\n",
+			y, html.EscapeString(shorten(label, 28)), math.Max(0, values[i])/total*100)
+	}
+	return svg.String()
+}
+
+// valueAxis draws the horizontal gridlines and their values
+func valueAxis(top float64) string {
+	var svg strings.Builder
+	plotHeight := float64(height - marginTop - marginBottom)
+	for i := 0; i <= yTicks; i++ {
+		value := top * float64(i) / yTicks
+		y := float64(height-marginBottom) - plotHeight*float64(i)/yTicks
+		fmt.Fprintf(&svg, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#dddddd"/>`+"\n", marginLeft, y, width-marginRight, y)
+		fmt.Fprintf(&svg, `<text x="%d" y="%.1f" font-size="11" text-anchor="end">%s</text>`+"\n", marginLeft-6, y+4, formatValue(value))
+	}
+	return svg.String()
+}
+
+// categoryLabels writes a label under each bar, slanted when they would overlap
+func categoryLabels(labels []string, slot float64) string {
+	var svg strings.Builder
+	slanted := slot < 60
+	for i, label := range labels {
+		x := float64(marginLeft) + (float64(i)+0.5)*slot
+		y := height - marginBottom + 16
+		if slanted {
+			fmt.Fprintf(&svg, `<text x="%.1f" y="%d" font-size="11" text-anchor="end" transform="rotate(-40 %.1f %d)">%s</text>`+"\n",
+				x, y, x, y, html.EscapeString(shorten(label, labelLength)))
+		} else {
+			fmt.Fprintf(&svg, `<text x="%.1f" y="%d" font-size="11" text-anchor="middle">%s</text>`+"\n",
+				x, y, html.EscapeString(shorten(label, labelLength)))
+		}
+	}
+	return svg.String()
+}
+
+// series reads matching label and value lists from chart data
+func series(data map[string]interface{}, labelKey, valueKey string) ([]string, []float64, error) {
+	labels, ok := stringsOf(data[labelKey])
+	if !ok {
+		return nil, nil, fmt.Errorf("%s must be a list of strings", labelKey)
+	}
+	values, ok := numbers(data[valueKey])
+	if !ok {
+		return nil, nil, fmt.Errorf("%s must be a list of numbers", valueKey)
+	}
+	if len(values) == 0 {
+		return nil, nil, ErrNoData
+	}
+	if len(labels) != len(values) {
+		return nil, nil, fmt.Errorf("%d %s for %d %s", len(labels), labelKey, len(values), valueKey)
+	}
+	return labels, values, nil
+}
+
+// numbers reads a list of numbers, as built in Go or decoded from JSON
+func numbers(value interface{}) ([]float64, bool) {
+	switch v := value.(type) {
+	case []float64:
+		return v, true
+	case []int:
+		out := make([]float64, len(v))
+		for i, n := range v {
+			out[i] = float64(n)
+		}
+		return out, true
+	case []interface{}:
+		out := make([]float64, len(v))
+		for i, item := range v {
+			switch n := item.(type) {
+			case float64:
+				out[i] = n
+			case int:
+				out[i] = float64(n)
+			default:
+				return nil, false
+			}
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// stringsOf reads a list of strings, as built in Go or decoded from JSON
+func stringsOf(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		out := make([]string, len(v))
+		for i, item := range v {
+			out[i] = fmt.Sprint(item)
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// niceMax rounds the largest value up to 1, 2 or 5 times a power of ten, so
+// gridlines fall on round numbers
+func niceMax(values []float64) float64 {
+	largest := 0.0
+	for _, value := range values {
+		largest = math.Max(largest, value)
+	}
+	if largest <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(largest)))
+	for _, step := range []float64{1, 2, 5, 10} {
+		if largest <= step*magnitude {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// formatValue prints a number without needless decimals
+func formatValue(value float64) string {
+	if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.3g", value)
+}
+
+// shorten truncates a label to n characters with an ellipsis
+func shorten(label string, n int) string {
+	runes := []rune(label)
+	if len(runes) <= n {
+		return label
+	}
+	return string(runes[:n-1]) + "…"
+}
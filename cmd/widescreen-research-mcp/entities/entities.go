@@ -0,0 +1,194 @@
+// Package entities finds the people, organizations, locations and products
+// named in text, with capitalization heuristics
+package entities
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// Entity types
+const (
+	Person       = "person"
+	Organization = "organization"
+	Location     = "location"
+	Product      = "product"
+)
+
+var (
+	// capitalizedRun matches runs of capitalized words, allowing "of", "de" and "&" inside names
+	capitalizedRun = regexp.MustCompile(`\b[A-Z][\w'&-]*(?:[ \t]+(?:(?:of|de|du|van|von|&)[ \t]+)?[A-Z0-9][\w'&-]*)*`)
+
+	// productPattern matches product names with a lowercase prefix or a version number, e.g. iPhone 15 or GPT-4
+	productPattern = regexp.MustCompile(`\b(?:[a-z]+[A-Z][\w-]*(?:\s+\d[\w.]*)?|[A-Z][A-Za-z]*-\d[\w.]*|[A-Z][a-z]+\s+\d{1,2}(?:\.\d+)?(?:\s+(?:Pro|Max|Ultra|Plus))?)\b`)
+)
+
+// organizationSuffixes end organization names
+var organizationSuffixes = map[string]bool{
+	"inc": true, "inc.": true, "corp": true, "corp.": true, "corporation": true, "ltd": true, "ltd.": true,
+	"llc": true, "plc": true, "gmbh": true, "ag": true, "group": true, "holdings": true, "company": true,
+	"co.": true, "university": true, "institute": true, "foundation": true, "association": true,
+	"agency": true, "bank": true, "labs": true, "technologies": true, "systems": true, "partners": true,
+	"capital": true, "ventures": true, "ministry": true, "department": true, "commission": true, "council": true,
+}
+
+// personTitles come before people's names
+var personTitles = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "professor": true, "ceo": true,
+	"cfo": true, "cto": true, "founder": true, "president": true, "chairman": true, "chairwoman": true,
+	"director": true, "senator": true, "minister": true, "said": true, "says": true,
+}
+
+// locationPrepositions come before place names
+var locationPrepositions = map[string]bool{"in": true, "from": true, "across": true, "near": true, "throughout": true}
+
+// knownLocations are places capitalized runs are checked against
+var knownLocations = map[string]bool{
+	"africa": true, "asia": true, "europe": true, "north america": true, "south america": true, "latin america": true,
+	"middle east": true, "australia": true, "united states": true, "us": true, "usa": true, "uk": true,
+	"united kingdom": true, "canada": true, "mexico": true, "brazil": true, "china": true, "japan": true, "india": true,
+	"germany": true, "france": true, "italy": true, "spain": true, "russia": true, "ukraine": true, "israel": true,
+	"south korea": true, "korea": true, "taiwan": true, "singapore": true, "switzerland": true, "netherlands": true,
+	"ireland": true, "sweden": true, "norway": true, "new york": true, "san francisco": true, "london": true,
+	"paris": true, "berlin": true, "tokyo": true, "beijing": true, "shanghai": true, "silicon valley": true,
+	"california": true, "texas": true, "washington": true, "seattle": true, "boston": true, "toronto": true,
+}
+
+// leadingWords are capitalized only because they start a sentence, and are dropped from runs
+var leadingWords = map[string]bool{
+	"the": true, "a": true, "an": true, "this": true, "that": true, "these": true, "those": true, "in": true,
+	"on": true, "at": true, "for": true, "and": true, "but": true, "or": true, "as": true, "by": true, "with": true,
+	"from": true, "its": true, "their": true, "his": true, "her": true, "our": true, "it": true, "they": true,
+	"we": true, "he": true, "she": true, "after": true, "before": true, "while": true, "however": true,
+	"according": true, "since": true, "during": true, "also": true, "both": true, "each": true, "all": true,
+	"some": true, "many": true, "most": true, "other": true, "new": true, "overall": true, "despite": true,
+	"january": true, "february": true, "march": true, "april": true, "may": true, "june": true, "july": true,
+	"august": true, "september": true, "october": true, "november": true, "december": true,
+	"monday": true, "tuesday": true, "wednesday": true, "thursday": true, "friday": true, "saturday": true, "sunday": true,
+}
+
+// InText finds the entities named in a piece of text
+func InText(text string) []schemas.Entity {
+	var entities []schemas.Entity
+	taken := make([]bool, len(text))
+
+	for _, loc := range productPattern.FindAllStringIndex(text, -1) {
+		entities = append(entities, schemas.Entity{Name: text[loc[0]:loc[1]], Type: Product})
+		for i := loc[0]; i < loc[1]; i++ {
+			taken[i] = true
+		}
+	}
+
+	for _, loc := range capitalizedRun.FindAllStringIndex(text, -1) {
+		if taken[loc[0]] {
+			continue
+		}
+		words := strings.Fields(text[loc[0]:loc[1]])
+		previous := previousWord(text, loc[0])
+		for len(words) > 0 {
+			first := strings.ToLower(strings.TrimRight(words[0], ".,"))
+			if !leadingWords[first] && !personTitles[first] {
+				break
+			}
+			previous = first
+			words = words[1:]
+		}
+		if len(words) == 0 {
+			continue
+		}
+		name := strings.TrimRight(strings.Join(words, " "), ".,;:'")
+		if entityType := classifyEntity(name, words, previous); entityType != "" {
+			entities = append(entities, schemas.Entity{Name: name, Type: entityType})
+		}
+	}
+	return entities
+}
+
+// classifyEntity decides what a capitalized run names from its words and the
+// word before it, or returns "" when it is probably not an entity
+func classifyEntity(name string, words []string, previous string) string {
+	lower := strings.ToLower(name)
+	last := strings.ToLower(words[len(words)-1])
+
+	switch {
+	case knownLocations[lower]:
+		return Location
+	case organizationSuffixes[last]:
+		return Organization
+	case len(words) > 2 && words[1] == "of" && organizationSuffixes[strings.ToLower(words[0])]:
+		// Names like Bank of England and University of Tokyo
+		return Organization
+	case len(words) == 1 && isAcronym(name):
+		return Organization
+	case anyInnerCapital(words):
+		// Names like OpenAI and Google DeepMind
+		return Organization
+	case personTitles[previous] && len(words) <= 3:
+		return Person
+	case locationPrepositions[previous] && len(words) <= 3:
+		return Location
+	case len(words) >= 2 && len(words) <= 3 && allNameLike(words):
+		return Person
+	}
+	return ""
+}
+
+// previousWord returns the lowercased word before offset in text
+func previousWord(text string, offset int) string {
+	fields := strings.Fields(text[:offset])
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(strings.Trim(fields[len(fields)-1], ".,;:()"))
+}
+
+// isAcronym reports whether a word is two to six capital letters, like NASA or IBM
+func isAcronym(word string) bool {
+	if len(word) < 2 || len(word) > 6 {
+		return false
+	}
+	for _, r := range word {
+		if !unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// anyInnerCapital reports whether a word has a capital after a lowercase letter, like OpenAI
+func anyInnerCapital(words []string) bool {
+	for _, word := range words {
+		sawLower := false
+		for _, r := range word {
+			if unicode.IsLower(r) {
+				sawLower = true
+			} else if unicode.IsUpper(r) && sawLower {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allNameLike reports whether every word looks like part of a person's name: a
+// capital followed by lowercase letters, or an initial
+func allNameLike(words []string) bool {
+	for _, word := range words {
+		runes := []rune(strings.TrimRight(word, ".,'"))
+		if len(runes) == 1 && unicode.IsUpper(runes[0]) {
+			continue
+		}
+		if len(runes) < 2 || !unicode.IsUpper(runes[0]) {
+			return false
+		}
+		for _, r := range runes[1:] {
+			if !unicode.IsLower(r) && r != '-' && r != '\'' {
+				return false
+			}
+		}
+	}
+	return true
+}
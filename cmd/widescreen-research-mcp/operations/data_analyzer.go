@@ -2,20 +2,29 @@ package operations
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
 )
 
 // DataAnalyzer performs analysis on research findings
-type DataAnalyzer struct{}
+type DataAnalyzer struct {
+	// rubric scores the quality of each drone's data
+	rubric QualityRubric
+
+	// SessionsCollection is the Firestore collection stored sessions are
+	// streamed from, which is tenant-prefixed when tenants share the orchestrator
+	SessionsCollection string
+}
 
 // NewDataAnalyzer creates a new data analyzer
 func NewDataAnalyzer() *DataAnalyzer {
-	return &DataAnalyzer{}
+	return &DataAnalyzer{rubric: LoadQualityRubric(), SessionsCollection: sessionsCollection}
 }
 
 // Execute analyzes research data
@@ -25,14 +34,25 @@ func (da *DataAnalyzer) Execute(ctx context.Context, params map[string]interface
 	
 	if data, ok := params["data"].([]interface{}); ok {
 		for _, d := range data {
-			if result, ok := d.(schemas.DroneResult); ok {
-				droneResults = append(droneResults, result)
+			switch v := d.(type) {
+			case schemas.DroneResult:
+				droneResults = append(droneResults, v)
+			case map[string]interface{}:
+				// Results passed through the MCP tool arrive as JSON objects
+				if result, err := decodeDroneResult(v); err == nil {
+					droneResults = append(droneResults, result)
+				}
 			}
 		}
 	}
 
+	// Sessions too large to pass as data are streamed from Firestore instead
+	if sessionID, ok := params["session_id"].(string); ok && sessionID != "" && len(droneResults) == 0 {
+		return da.streamSession(ctx, sessionID)
+	}
+
 	if len(droneResults) == 0 {
-		return nil, fmt.Errorf("no data provided for analysis")
+		return nil, mcperrors.InvalidInput("no data provided for analysis")
 	}
 
 	// Get analysis type
@@ -47,19 +67,49 @@ func (da *DataAnalyzer) Execute(ctx context.Context, params map[string]interface
 		additionalParams = ap
 	}
 
+	// The quality rubric can be tuned per analysis
+	rubric, err := da.rubric.withOverrides(params)
+	if err != nil {
+		return nil, err
+	}
+	da = &DataAnalyzer{rubric: rubric}
+
+	// Export settings, checked before the analysis runs
+	export, err := parseExport(params)
+	if err != nil {
+		return nil, err
+	}
+
 	// Perform analysis based on type
+	var response *schemas.DataAnalysisResponse
 	switch analysisType {
-	case "comprehensive":
-		return da.comprehensiveAnalysis(ctx, droneResults, additionalParams)
 	case "statistical":
-		return da.statisticalAnalysis(ctx, droneResults, additionalParams)
+		response, err = da.statisticalAnalysis(ctx, droneResults, additionalParams)
 	case "pattern":
-		return da.patternAnalysis(ctx, droneResults, additionalParams)
+		response, err = da.patternAnalysis(ctx, droneResults, additionalParams)
 	case "summary":
-		return da.summaryAnalysis(ctx, droneResults, additionalParams)
+		response, err = da.summaryAnalysis(ctx, droneResults, additionalParams)
+	case "entities":
+		response, err = da.entityAnalysis(ctx, droneResults, additionalParams)
+	case "sentiment":
+		response, err = da.sentimentAnalysis(ctx, droneResults, additionalParams)
+	case "keywords":
+		response, err = da.keywordAnalysis(ctx, droneResults, additionalParams)
+	case "quality":
+		response, err = da.qualityAnalysis(ctx, droneResults, additionalParams)
 	default:
-		return da.comprehensiveAnalysis(ctx, droneResults, additionalParams)
+		response, err = da.comprehensiveAnalysis(ctx, droneResults, additionalParams)
 	}
+	if err != nil || export == nil {
+		return response, err
+	}
+
+	// Write the analysis to files for notebooks
+	response.Exports, err = exportAnalysis(ctx, response, droneResults, export)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export analysis: %w", err)
+	}
+	return response, nil
 }
 
 // comprehensiveAnalysis performs comprehensive data analysis
@@ -71,7 +121,11 @@ func (da *DataAnalyzer) comprehensiveAnalysis(ctx context.Context, results []sch
 		Patterns:       da.identifyPatterns(results),
 		Statistics:     da.calculateStatistics(results),
 		Visualizations: da.generateVisualizations(results),
+		Entities:       extractEntities(results),
+		Quality:        da.assessQuality(results),
 	}
+	terms, phrases := extractKeywords(results)
+	response.Keywords = append(terms[:min(10, len(terms))], phrases[:min(10, len(phrases))]...)
 
 	return response, nil
 }
@@ -80,14 +134,40 @@ func (da *DataAnalyzer) comprehensiveAnalysis(ctx context.Context, results []sch
 func (da *DataAnalyzer) statisticalAnalysis(ctx context.Context, results []schemas.DroneResult, params map[string]interface{}) (*schemas.DataAnalysisResponse, error) {
 	stats := da.calculateDetailedStatistics(results)
 	
+	insights := []string{
+		fmt.Sprintf("Total data points analyzed: %d", len(results)),
+		fmt.Sprintf("Success rate: %.2f%% (95%% CI %.2f%%-%.2f%%)", stats["success_rate"].(float64)*100,
+			stats["success_rate_ci_low"].(float64)*100, stats["success_rate_ci_high"].(float64)*100),
+		fmt.Sprintf("Average processing time: %.2f seconds", stats["avg_processing_time"].(float64)),
+	}
+	if distribution, ok := stats["processing_time_distribution"].(map[string]interface{}); ok {
+		insights = append(insights, fmt.Sprintf("Processing time median %.2fs, interquartile range %.2fs-%.2fs",
+			distribution["median"], distribution["p25"], distribution["p75"]))
+	}
+	if r, ok := stats["time_volume_correlation"].(float64); ok {
+		insights = append(insights, fmt.Sprintf("Processing time and data volume show %s correlation (r = %.2f)", correlationStrength(r), r))
+	}
+	if drones := stats["processing_time_outliers"].([]string); len(drones) > 0 {
+		insights = append(insights, fmt.Sprintf("Processing time outliers: %s", strings.Join(drones, ", ")))
+	}
+	if drones := stats["data_volume_outliers"].([]string); len(drones) > 0 {
+		insights = append(insights, fmt.Sprintf("Data volume outliers: %s", strings.Join(drones, ", ")))
+	}
+
+	var seconds []float64
+	for _, result := range results {
+		if result.Status == "completed" && result.ProcessingTime > 0 {
+			seconds = append(seconds, result.ProcessingTime.Seconds())
+		}
+	}
+	timeChart := histogram(seconds)
+	timeChart.Title = "Processing Time Distribution (seconds)"
+
 	return &schemas.DataAnalysisResponse{
-		Summary:    "Statistical analysis of research data",
-		Statistics: stats,
-		Insights: []string{
-			fmt.Sprintf("Total data points analyzed: %d", len(results)),
-			fmt.Sprintf("Success rate: %.2f%%", stats["success_rate"].(float64)*100),
-			fmt.Sprintf("Average processing time: %.2f seconds", stats["avg_processing_time"].(float64)),
-		},
+		Summary:        "Statistical analysis of research data",
+		Statistics:     stats,
+		Insights:       insights,
+		Visualizations: []schemas.Visualization{timeChart},
 	}, nil
 }
 
@@ -110,8 +190,234 @@ func (da *DataAnalyzer) summaryAnalysis(ctx context.Context, results []schemas.D
 	}, nil
 }
 
+// entityAnalysis counts the people, organizations, locations and products the results mention
+func (da *DataAnalyzer) entityAnalysis(ctx context.Context, results []schemas.DroneResult, params map[string]interface{}) (*schemas.DataAnalysisResponse, error) {
+	entities := extractEntities(results)
+
+	byType := make(map[string][]string)
+	counts := make(map[string]int)
+	for _, entity := range entities {
+		if len(byType[entity.Type]) < 5 {
+			byType[entity.Type] = append(byType[entity.Type], fmt.Sprintf("%s (%d)", entity.Name, entity.Mentions))
+		}
+		counts[entity.Type]++
+	}
+
+	stats := map[string]interface{}{"total_entities": len(entities)}
+	for entityType, count := range counts {
+		stats[entityType+"_count"] = count
+	}
+
+	insights := []string{}
+	for _, group := range []struct{ entityType, label string }{
+		{EntityPerson, "people"},
+		{EntityOrganization, "organizations"},
+		{EntityLocation, "locations"},
+		{EntityProduct, "products"},
+	} {
+		if names := byType[group.entityType]; len(names) > 0 {
+			insights = append(insights, fmt.Sprintf("Most mentioned %s: %s", group.label, strings.Join(names, ", ")))
+		}
+	}
+
+	return &schemas.DataAnalysisResponse{
+		Summary:    fmt.Sprintf("Found %d distinct entities across %d research results", len(entities), len(results)),
+		Insights:   insights,
+		Statistics: stats,
+		Entities:   entities,
+	}, nil
+}
+
+// sentimentAnalysis scores the sentiment of the collected content by sub-query and by source
+func (da *DataAnalyzer) sentimentAnalysis(ctx context.Context, results []schemas.DroneResult, params map[string]interface{}) (*schemas.DataAnalysisResponse, error) {
+	scores, textScores := analyzeSentiment(results)
+
+	distribution := map[string]int{SentimentPositive: 0, SentimentNeutral: 0, SentimentNegative: 0}
+	total := 0.0
+	for _, score := range textScores {
+		distribution[sentimentLabel(score)]++
+		total += score
+	}
+	mean := 0.0
+	if len(textScores) > 0 {
+		mean = total / float64(len(textScores))
+	}
+
+	stats := map[string]interface{}{
+		"scored_results": len(textScores),
+		"mean_score":     mean,
+		"positive":       distribution[SentimentPositive],
+		"neutral":        distribution[SentimentNeutral],
+		"negative":       distribution[SentimentNegative],
+	}
+	if len(textScores) > 0 {
+		for label, count := range distribution {
+			stats[label+"_share"] = float64(count) / float64(len(textScores))
+		}
+	}
+
+	insights := []string{fmt.Sprintf("Overall sentiment: %s (%.2f)", sentimentLabel(mean), mean)}
+	var queryLabels []string
+	var queryValues []float64
+	for _, score := range scores {
+		if score.Kind == "query" {
+			queryLabels = append(queryLabels, score.Subject)
+			queryValues = append(queryValues, score.Score)
+		}
+	}
+	if len(queryLabels) > 1 {
+		// Scores are sorted from most positive to most negative
+		insights = append(insights,
+			fmt.Sprintf("Most positive sub-query: %s (%.2f)", queryLabels[0], queryValues[0]),
+			fmt.Sprintf("Most negative sub-query: %s (%.2f)", queryLabels[len(queryLabels)-1], queryValues[len(queryValues)-1]))
+	}
+
+	return &schemas.DataAnalysisResponse{
+		Summary:    fmt.Sprintf("Sentiment of %d research results: %d positive, %d neutral, %d negative", len(textScores), distribution[SentimentPositive], distribution[SentimentNeutral], distribution[SentimentNegative]),
+		Insights:   insights,
+		Statistics: stats,
+		Sentiment:  scores,
+		Visualizations: []schemas.Visualization{
+			{
+				Type:  "bar_chart",
+				Title: "Sentiment Distribution",
+				Data: map[string]interface{}{
+					"labels": []string{"Positive", "Neutral", "Negative"},
+					"values": []int{distribution[SentimentPositive], distribution[SentimentNeutral], distribution[SentimentNegative]},
+				},
+			},
+			{
+				Type:  "bar_chart",
+				Title: "Sentiment by Sub-Query",
+				Data: map[string]interface{}{
+					"labels": queryLabels,
+					"values": queryValues,
+				},
+			},
+		},
+	}, nil
+}
+
+// keywordAnalysis ranks the terms and key phrases that characterize the collected content
+func (da *DataAnalyzer) keywordAnalysis(ctx context.Context, results []schemas.DroneResult, params map[string]interface{}) (*schemas.DataAnalysisResponse, error) {
+	limit := defaultKeywordLimit
+	if l, ok := params["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	terms, phrases := extractKeywords(results)
+	stats := map[string]interface{}{
+		"distinct_terms":   len(terms),
+		"distinct_phrases": len(phrases),
+	}
+	terms = terms[:min(limit, len(terms))]
+	phrases = phrases[:min(limit, len(phrases))]
+
+	insights := []string{}
+	if len(terms) > 0 {
+		insights = append(insights, fmt.Sprintf("Key terms: %s", strings.Join(keywordTerms(terms, 10), ", ")))
+	}
+	if len(phrases) > 0 {
+		insights = append(insights, fmt.Sprintf("Key phrases: %s", strings.Join(keywordTerms(phrases, 10), ", ")))
+	}
+
+	labels := make([]string, 0, len(terms))
+	values := make([]float64, 0, len(terms))
+	for _, term := range terms {
+		labels = append(labels, term.Term)
+		values = append(values, term.Score)
+	}
+
+	return &schemas.DataAnalysisResponse{
+		Summary:    fmt.Sprintf("Extracted %d key terms and %d key phrases from %d research results", len(terms), len(phrases), len(results)),
+		Insights:   insights,
+		Statistics: stats,
+		Keywords:   append(terms, phrases...),
+		Visualizations: []schemas.Visualization{
+			{
+				Type:  "bar_chart",
+				Title: "Top Keywords",
+				Data: map[string]interface{}{
+					"labels": labels,
+					"values": values,
+				},
+			},
+		},
+	}, nil
+}
+
+// qualityAnalysis scores each drone's data against the quality rubric, criterion by criterion
+func (da *DataAnalyzer) qualityAnalysis(ctx context.Context, results []schemas.DroneResult, params map[string]interface{}) (*schemas.DataAnalysisResponse, error) {
+	qualities := da.assessQuality(results)
+
+	criterionTotals := make(map[string]float64)
+	criterionCounts := make(map[string]int)
+	var scored []schemas.DroneQuality
+	for _, quality := range qualities {
+		for criterion, value := range quality.Criteria {
+			criterionTotals[criterion] += value
+			criterionCounts[criterion]++
+		}
+		if len(quality.Criteria) > 0 {
+			scored = append(scored, quality)
+		}
+	}
+
+	stats := map[string]interface{}{
+		"data_quality_score": da.assessDataQuality(results),
+		"scored_results":     len(scored),
+		"rubric":             da.rubric,
+	}
+	for criterion, total := range criterionTotals {
+		stats["avg_"+criterion] = total / float64(criterionCounts[criterion])
+	}
+
+	insights := []string{fmt.Sprintf("Data quality score: %.2f/10 across %d results with data", stats["data_quality_score"], len(scored))}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].Score < scored[j].Score })
+	for _, quality := range scored[:min(3, len(scored))] {
+		if quality.Score < 10 && len(quality.Notes) > 0 {
+			insights = append(insights, fmt.Sprintf("Drone %s scored %.2f/10: %s", quality.DroneID, quality.Score, strings.Join(quality.Notes, ", ")))
+		}
+	}
+
+	labels := make([]string, len(qualities))
+	values := make([]float64, len(qualities))
+	for i, quality := range qualities {
+		labels[i] = quality.DroneID
+		values[i] = quality.Score
+	}
+
+	return &schemas.DataAnalysisResponse{
+		Summary:    fmt.Sprintf("Data quality of %d research results", len(results)),
+		Insights:   insights,
+		Statistics: stats,
+		Quality:    qualities,
+		Visualizations: []schemas.Visualization{
+			{
+				Type:  "bar_chart",
+				Title: "Data Quality by Drone",
+				Data: map[string]interface{}{
+					"labels": labels,
+					"values": values,
+				},
+			},
+		},
+	}, nil
+}
+
 // Helper methods
 
+// decodeDroneResult converts a drone result given as a JSON object
+func decodeDroneResult(data map[string]interface{}) (schemas.DroneResult, error) {
+	var result schemas.DroneResult
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(encoded, &result)
+	return result, err
+}
+
 func (da *DataAnalyzer) generateSummary(results []schemas.DroneResult) string {
 	successCount := 0
 	totalDataPoints := 0
@@ -148,33 +454,27 @@ func (da *DataAnalyzer) extractInsights(results []schemas.DroneResult) []string
 	avgTime, minTime, maxTime := da.analyzeProcessingTimes(results)
 	insights = append(insights, fmt.Sprintf("Processing times - Avg: %.2fs, Min: %.2fs, Max: %.2fs", 
 		avgTime.Seconds(), minTime.Seconds(), maxTime.Seconds()))
+
+	// Summarize what the content is about
+	terms, phrases := extractKeywords(results)
+	if len(terms) > 0 {
+		insights = append(insights, fmt.Sprintf("Key terms: %s", strings.Join(keywordTerms(terms, 5), ", ")))
+	}
+	if len(phrases) > 0 {
+		insights = append(insights, fmt.Sprintf("Key phrases: %s", strings.Join(keywordTerms(phrases, 5), ", ")))
+	}
 	
 	return insights
 }
 
+// identifyPatterns runs the built-in and registered pattern detectors
 func (da *DataAnalyzer) identifyPatterns(results []schemas.DroneResult) []schemas.Pattern {
 	patterns := []schemas.Pattern{}
-	
-	// Pattern: Successful completion clustering
-	if pattern := da.identifyCompletionPattern(results); pattern != nil {
-		patterns = append(patterns, *pattern)
-	}
-	
-	// Pattern: Data volume distribution
-	if pattern := da.identifyDataVolumePattern(results); pattern != nil {
-		patterns = append(patterns, *pattern)
-	}
-	
-	// Pattern: Error patterns
-	if pattern := da.identifyErrorPattern(results); pattern != nil {
-		patterns = append(patterns, *pattern)
-	}
-	
-	// Pattern: Source diversity
-	if pattern := da.identifySourceDiversityPattern(results); pattern != nil {
-		patterns = append(patterns, *pattern)
+	for _, detector := range da.detectors() {
+		if pattern := runDetector(detector, results); pattern != nil {
+			patterns = append(patterns, *pattern)
+		}
 	}
-	
 	return patterns
 }
 
@@ -257,26 +557,14 @@ func (da *DataAnalyzer) countSuccessful(results []schemas.DroneResult) int {
 	return count
 }
 
+// assessDataQuality is the average quality score, out of 10, of the results that collected data
 func (da *DataAnalyzer) assessDataQuality(results []schemas.DroneResult) float64 {
-	// Simple quality assessment based on completeness and data volume
 	totalScore := 0.0
 	validResults := 0
 	
-	for _, result := range results {
-		if result.Status == "completed" && len(result.Data) > 0 {
-			score := 10.0
-			
-			// Deduct points for missing data
-			if len(result.Data) < 5 {
-				score -= 2.0
-			}
-			
-			// Deduct points for errors
-			if result.Error != "" {
-				score -= 3.0
-			}
-			
-			totalScore += score
+	for i, quality := range da.assessQuality(results) {
+		if results[i].Status == "completed" && len(results[i].Data) > 0 {
+			totalScore += quality.Score
 			validResults++
 		}
 	}
@@ -525,10 +813,48 @@ func (da *DataAnalyzer) calculateDetailedStatistics(results []schemas.DroneResul
 		stats["data_volume_min"] = volumes[0]
 		stats["data_volume_max"] = volumes[len(volumes)-1]
 	}
+
+	// Uncertainty of the success rate
+	low, high := wilsonInterval(stats["successful_results"].(int), len(results))
+	stats["success_rate_ci_low"] = low
+	stats["success_rate_ci_high"] = high
+
+	// Distributions of processing time and data volume over the completed drones
+	var droneIDs []string
+	var seconds, points []float64
+	for _, result := range results {
+		if result.Status == "completed" && result.ProcessingTime > 0 {
+			droneIDs = append(droneIDs, result.DroneID)
+			seconds = append(seconds, result.ProcessingTime.Seconds())
+			points = append(points, float64(len(result.Data)))
+		}
+	}
+	if len(seconds) > 0 {
+		stats["processing_time_distribution"] = describe(seconds)
+		stats["data_volume_distribution"] = describe(points)
+	}
+
+	// Whether drones that collect more take longer
+	if r, ok := pearson(seconds, points); ok {
+		stats["time_volume_correlation"] = r
+	}
+
+	// Drones far slower, faster or more or less productive than the rest
+	stats["processing_time_outliers"] = outlierDrones(droneIDs, seconds)
+	stats["data_volume_outliers"] = outlierDrones(droneIDs, points)
 	
 	return stats
 }
 
+// outlierDrones names the drones whose values are outliers
+func outlierDrones(droneIDs []string, values []float64) []string {
+	drones := []string{}
+	for _, i := range outliers(values) {
+		drones = append(drones, droneIDs[i])
+	}
+	return drones
+}
+
 func (da *DataAnalyzer) identifyDetailedPatterns(results []schemas.DroneResult) []schemas.Pattern {
 	patterns := da.identifyPatterns(results)
 	
@@ -577,7 +903,7 @@ func (da *DataAnalyzer) identifyTimePattern(results []schemas.DroneResult) *sche
 }
 
 func (da *DataAnalyzer) identifyPerformancePattern(results []schemas.DroneResult) *schemas.Pattern {
-	avg, min, max := da.analyzeProcessingTimes(results)
+	avg, _, max := da.analyzeProcessingTimes(results)
 	
 	if max > avg*3 { // Some drones took much longer
 		return &schemas.Pattern{
@@ -0,0 +1,117 @@
+package operations
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/entities"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// Entity types
+const (
+	EntityPerson       = entities.Person
+	EntityOrganization = entities.Organization
+	EntityLocation     = entities.Location
+	EntityProduct      = entities.Product
+)
+
+// extractEntities finds the people, organizations, locations and products the
+// completed drone results mention, counting mentions across the session. Drones
+// may list entities themselves under "entities" as {"name", "type"} objects;
+// the rest are found in the text of their data with capitalization heuristics.
+func extractEntities(results []schemas.DroneResult) []schemas.Entity {
+	found := make(map[string]*schemas.Entity)
+	add := func(name, entityType, droneID string) {
+		name = strings.TrimSpace(strings.TrimRight(name, ".,;:"))
+		if name == "" {
+			return
+		}
+		key := entityType + "|" + strings.ToLower(name)
+		entity := found[key]
+		if entity == nil {
+			entity = &schemas.Entity{Name: name, Type: entityType}
+			found[key] = entity
+		}
+		entity.Mentions++
+		if !containsDrone(entity.Drones, droneID) {
+			entity.Drones = append(entity.Drones, droneID)
+		}
+	}
+
+	for _, result := range results {
+		if result.Status != "completed" {
+			continue
+		}
+		if listed, ok := result.Data["entities"].([]interface{}); ok {
+			for _, item := range listed {
+				if v, ok := item.(map[string]interface{}); ok {
+					name, _ := v["name"].(string)
+					entityType, _ := v["type"].(string)
+					switch entityType {
+					case EntityPerson, EntityOrganization, EntityLocation, EntityProduct:
+						add(name, entityType, result.DroneID)
+					}
+				}
+			}
+		}
+		for _, text := range dataText(result.Data) {
+			for _, entity := range entities.InText(text) {
+				add(entity.Name, entity.Type, result.DroneID)
+			}
+		}
+	}
+
+	sorted := make([]schemas.Entity, 0, len(found))
+	for _, entity := range found {
+		sorted = append(sorted, *entity)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Mentions != sorted[j].Mentions {
+			return sorted[i].Mentions > sorted[j].Mentions
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// dataText returns the prose in a drone's result data, skipping URLs and the entities it listed
+func dataText(data map[string]interface{}) []string {
+	var texts []string
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case string:
+			if !strings.HasPrefix(v, "http://") && !strings.HasPrefix(v, "https://") && strings.ContainsRune(v, ' ') {
+				texts = append(texts, v)
+			}
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if key != "entities" && key != "sources" {
+					walk(v[key])
+				}
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(data)
+	return texts
+}
+
+// containsDrone reports whether droneIDs contains droneID
+func containsDrone(droneIDs []string, droneID string) bool {
+	for _, id := range droneIDs {
+		if id == droneID {
+			return true
+		}
+	}
+	return false
+}
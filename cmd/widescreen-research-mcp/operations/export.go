@@ -0,0 +1,259 @@
+package operations
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/charts"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/encryption"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+	"google.golang.org/api/googleapi"
+	storage "google.golang.org/api/storage/v1"
+)
+
+// Export formats
+const (
+	ExportCSV   = "csv"
+	ExportJSONL = "jsonl"
+)
+
+// exportTable is one exported file: the rows of a table with named columns
+type exportTable struct {
+	name    string
+	columns []string
+	rows    [][]interface{}
+}
+
+// parseExport reads the export parameter, returning nil if no export was asked for
+func parseExport(params map[string]interface{}) (*schemas.AnalysisExport, error) {
+	raw, ok := params["export"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, mcperrors.InvalidInput("invalid export: %w", err)
+	}
+	var export schemas.AnalysisExport
+	if err := json.Unmarshal(encoded, &export); err != nil {
+		return nil, mcperrors.InvalidInput("invalid export: %w", err)
+	}
+
+	if export.Format == "" {
+		export.Format = ExportCSV
+	}
+	if export.Format != ExportCSV && export.Format != ExportJSONL {
+		return nil, mcperrors.InvalidInput("unknown export format %q, expected csv or jsonl", export.Format)
+	}
+	if export.Destination == "" {
+		export.Destination = fmt.Sprintf("reports/analysis_%s", time.Now().UTC().Format("20060102T150405Z"))
+	}
+	return &export, nil
+}
+
+// exportAnalysis writes the analysis' statistics and patterns and the
+// per-drone metrics as files in the export's format, and its charts as SVG
+// images, returning where they went
+func exportAnalysis(ctx context.Context, response *schemas.DataAnalysisResponse, results []schemas.DroneResult, export *schemas.AnalysisExport) ([]string, error) {
+	tables := []exportTable{statisticsTable(response.Statistics), patternsTable(response.Patterns), dronesTable(results)}
+
+	var uploader *storage.Service
+	if strings.HasPrefix(export.Destination, "gs://") {
+		var err error
+		uploader, err = storage.NewService(ctx, gcp.EmulatorOptions()...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Cloud Storage client: %w", err)
+		}
+	}
+	save := func(name, contentType string, data []byte) (string, error) {
+		if uploader != nil {
+			return uploadExport(ctx, uploader, export.Destination, name, contentType, data)
+		}
+		return writeExport(export.Destination, name, data)
+	}
+
+	var locations []string
+	for _, table := range tables {
+		var data []byte
+		var err error
+		contentType := "text/csv"
+		if export.Format == ExportJSONL {
+			data, err = table.jsonl()
+			contentType = "application/x-ndjson"
+		} else {
+			data, err = table.csv()
+		}
+		if err != nil {
+			return locations, fmt.Errorf("failed to encode %s: %w", table.name, err)
+		}
+
+		name := table.name + "." + export.Format
+		location, err := save(name, contentType, data)
+		if err != nil {
+			return locations, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		locations = append(locations, location)
+	}
+
+	for i := range response.Visualizations {
+		visualization := &response.Visualizations[i]
+		if !charts.Supported(visualization.Type) {
+			continue
+		}
+		image, err := charts.RenderSVG(*visualization)
+		if errors.Is(err, charts.ErrNoData) {
+			continue
+		}
+		if err != nil {
+			return locations, fmt.Errorf("failed to render chart: %w", err)
+		}
+		name := fmt.Sprintf("chart_%d.svg", i+1)
+		location, err := save(name, "image/svg+xml", image)
+		if err != nil {
+			return locations, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		visualization.Image = location
+		locations = append(locations, location)
+	}
+	return locations, nil
+}
+
+// writeExport saves an export file in a local directory
+func writeExport(dir, name string, data []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	filePath := filepath.Join(dir, name)
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+// uploadExport saves an export file under a gs://bucket/prefix destination
+func uploadExport(ctx context.Context, service *storage.Service, destination, name, contentType string, data []byte) (string, error) {
+	bucket, prefix, _ := strings.Cut(strings.TrimPrefix(destination, "gs://"), "/")
+	if bucket == "" {
+		return "", mcperrors.InvalidInput("destination %q has no bucket", destination)
+	}
+	object := path.Join(prefix, name)
+	insert := service.Objects.Insert(bucket, &storage.Object{Name: object}).
+		Media(bytes.NewReader(data), googleapi.ContentType(contentType))
+	// Exports hold research data, so they use the customer-managed key when one is configured
+	if key := encryption.LoadConfig().StorageKey; key != "" {
+		insert = insert.KmsKeyName(key)
+	}
+	_, err := insert.Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("gs://%s/%s", bucket, object), nil
+}
+
+// statisticsTable lists the statistics as metric/value rows, with nested
+// statistics such as distributions flattened to dotted names
+func statisticsTable(stats map[string]interface{}) exportTable {
+	table := exportTable{name: "statistics", columns: []string{"metric", "value"}}
+	var flatten func(prefix string, values map[string]interface{})
+	flatten = func(prefix string, values map[string]interface{}) {
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if nested, ok := values[key].(map[string]interface{}); ok {
+				flatten(prefix+key+".", nested)
+				continue
+			}
+			table.rows = append(table.rows, []interface{}{prefix + key, values[key]})
+		}
+	}
+	flatten("", stats)
+	return table
+}
+
+// patternsTable lists the patterns the analysis found
+func patternsTable(patterns []schemas.Pattern) exportTable {
+	table := exportTable{name: "patterns", columns: []string{"name", "description", "frequency", "confidence"}}
+	for _, pattern := range patterns {
+		table.rows = append(table.rows, []interface{}{pattern.Name, pattern.Description, pattern.Frequency, pattern.Confidence})
+	}
+	return table
+}
+
+// dronesTable lists each drone's outcome and metrics
+func dronesTable(results []schemas.DroneResult) exportTable {
+	table := exportTable{name: "drones", columns: []string{"drone_id", "task_id", "status", "data_points", "processing_seconds", "completed_at", "error"}}
+	for _, result := range results {
+		completedAt := ""
+		if !result.CompletedAt.IsZero() {
+			completedAt = result.CompletedAt.UTC().Format(time.RFC3339)
+		}
+		table.rows = append(table.rows, []interface{}{
+			result.DroneID, result.TaskID, result.Status, len(result.Data),
+			result.ProcessingTime.Seconds(), completedAt, result.Error,
+		})
+	}
+	return table
+}
+
+// csv encodes the table with a header row. Values that are lists or objects
+// are written as JSON.
+func (t exportTable) csv() ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(t.columns); err != nil {
+		return nil, err
+	}
+	for _, row := range t.rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			switch v := value.(type) {
+			case string:
+				record[i] = v
+			case int, int64, float64, bool:
+				record[i] = fmt.Sprint(v)
+			default:
+				encoded, err := json.Marshal(v)
+				if err != nil {
+					return nil, err
+				}
+				record[i] = string(encoded)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// jsonl encodes the table as one JSON object per row, keyed by column
+func (t exportTable) jsonl() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, row := range t.rows {
+		object := make(map[string]interface{}, len(row))
+		for i, value := range row {
+			object[t.columns[i]] = value
+		}
+		if err := encoder.Encode(object); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -12,6 +12,9 @@ import (
 	runpb "cloud.google.com/go/run/apiv2/runpb"
 	"github.com/google/uuid"
 	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+	"github.com/spawn-mcp/coordinator/pkg/types"
 	"google.golang.org/protobuf/types/known/durationpb"
 )
 
@@ -22,12 +25,15 @@ type GCPProvisioner struct {
 	runClient       *run.ServicesClient
 	pubsubClient    *pubsub.Client
 	firestoreClient *firestore.Client
+
+	// Tenant prefixes the names of provisioned resources when tenants share the orchestrator
+	Tenant string
 }
 
 // NewGCPProvisioner creates a new GCP provisioner
 func NewGCPProvisioner() *GCPProvisioner {
 	return &GCPProvisioner{
-		projectID: os.Getenv("GOOGLE_CLOUD_PROJECT"),
+		projectID: gcp.ProjectID(),
 		region:    getEnvOrDefault("GOOGLE_CLOUD_REGION", "us-central1"),
 	}
 }
@@ -42,7 +48,7 @@ func (gp *GCPProvisioner) Execute(ctx context.Context, params map[string]interfa
 	// Parse request parameters
 	resourceType, ok := params["resource_type"].(string)
 	if !ok {
-		return nil, fmt.Errorf("resource_type parameter is required")
+		return nil, mcperrors.InvalidInput("resource_type parameter is required")
 	}
 
 	count := 1
@@ -66,6 +72,7 @@ func (gp *GCPProvisioner) Execute(ctx context.Context, params map[string]interfa
 		Count:        count,
 		Region:       region,
 		Config:       config,
+		Scaling:      scalingFromConfig(config),
 	}
 
 	// Provision based on resource type
@@ -77,8 +84,17 @@ func (gp *GCPProvisioner) Execute(ctx context.Context, params map[string]interfa
 	case "firestore":
 		return gp.provisionFirestore(ctx, request)
 	default:
-		return nil, fmt.Errorf("unsupported resource type: %s", request.ResourceType)
+		return nil, mcperrors.InvalidInput("unsupported resource type: %s", request.ResourceType)
+	}
+}
+
+// tenantName prefixes a resource name with its tenant and separator, the way
+// the orchestrator names tenants' services, topics and collections
+func tenantName(tenant, separator, name string) string {
+	if tenant == "" {
+		return name
 	}
+	return tenant + separator + name
 }
 
 // provisionCloudRun provisions Cloud Run services
@@ -86,7 +102,7 @@ func (gp *GCPProvisioner) provisionCloudRun(ctx context.Context, request *schema
 	resources := make([]schemas.GCPResource, 0, request.Count)
 
 	for i := 0; i < request.Count; i++ {
-		resourceID := fmt.Sprintf("service-%s-%d", uuid.New().String()[:8], i)
+		resourceID := tenantName(gp.Tenant, "-", fmt.Sprintf("service-%s-%d", uuid.New().String()[:8], i))
 		
 		// Extract configuration
 		image := "gcr.io/cloudrun/hello" // Default image
@@ -128,6 +144,9 @@ func (gp *GCPProvisioner) provisionCloudRun(ctx context.Context, request *schema
 				Timeout:                      &durationpb.Duration{Seconds: timeout},
 			},
 		}
+		if err := gcp.ApplyScaling(service, request.Scaling); err != nil {
+			return nil, fmt.Errorf("failed to configure service scaling: %w", err)
+		}
 
 		// Add environment variables if provided
 		if envVars, ok := request.Config["env_vars"].(map[string]interface{}); ok {
@@ -174,12 +193,33 @@ func (gp *GCPProvisioner) provisionCloudRun(ctx context.Context, request *schema
 	}, nil
 }
 
+// scalingFromConfig reads Cloud Run scaling overrides from a provisioning config
+func scalingFromConfig(config map[string]interface{}) types.ScalingConfig {
+	var scaling types.ScalingConfig
+	if v, ok := config["min_instances"].(float64); ok {
+		scaling.MinInstances = int(v)
+	}
+	if v, ok := config["max_instances"].(float64); ok {
+		scaling.MaxInstances = int(v)
+	}
+	if v, ok := config["concurrency"].(float64); ok {
+		scaling.Concurrency = int(v)
+	}
+	if v, ok := config["cpu_allocation"].(string); ok {
+		scaling.CPUAllocation = v
+	}
+	if v, ok := config["execution_environment"].(string); ok {
+		scaling.ExecutionEnvironment = v
+	}
+	return scaling
+}
+
 // provisionPubSub provisions Pub/Sub topics and subscriptions
 func (gp *GCPProvisioner) provisionPubSub(ctx context.Context, request *schemas.GCPProvisionRequest) (*schemas.GCPProvisionResponse, error) {
 	resources := make([]schemas.GCPResource, 0, request.Count)
 
 	for i := 0; i < request.Count; i++ {
-		topicID := fmt.Sprintf("topic-%s-%d", uuid.New().String()[:8], i)
+		topicID := tenantName(gp.Tenant, "-", fmt.Sprintf("topic-%s-%d", uuid.New().String()[:8], i))
 		
 		// Create topic
 		topic, err := gp.pubsubClient.CreateTopic(ctx, topicID)
@@ -226,7 +266,7 @@ func (gp *GCPProvisioner) provisionFirestore(ctx context.Context, request *schem
 	}
 
 	for i := 0; i < request.Count; i++ {
-		collectionID := fmt.Sprintf("%s-%s-%d", collectionPrefix, uuid.New().String()[:8], i)
+		collectionID := tenantName(gp.Tenant, "_", fmt.Sprintf("%s-%s-%d", collectionPrefix, uuid.New().String()[:8], i))
 		
 		// Create initial document to establish collection
 		doc := gp.firestoreClient.Collection(collectionID).Doc("_init")
@@ -0,0 +1,188 @@
+package operations
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// Keyword kinds
+const (
+	KeywordTerm   = "term"
+	KeywordPhrase = "phrase"
+)
+
+const (
+	// defaultKeywordLimit is how many terms and phrases a keyword analysis returns
+	defaultKeywordLimit = 20
+	// maxPhraseWords is the longest key phrase; longer runs are rarely meaningful
+	maxPhraseWords = 4
+)
+
+// keywordStopWords separate key phrases and are never keywords themselves
+var keywordStopWords = map[string]bool{
+	"a": true, "about": true, "above": true, "after": true, "again": true, "all": true, "also": true, "am": true,
+	"an": true, "and": true, "any": true, "are": true, "as": true, "at": true, "be": true, "because": true,
+	"been": true, "before": true, "being": true, "between": true, "both": true, "but": true, "by": true,
+	"can": true, "could": true, "did": true, "do": true, "does": true, "during": true, "each": true, "few": true,
+	"for": true, "from": true, "further": true, "had": true, "has": true, "have": true, "having": true,
+	"he": true, "her": true, "here": true, "his": true, "how": true, "however": true, "i": true, "if": true,
+	"in": true, "into": true, "is": true, "it": true, "it's": true, "its": true, "just": true, "may": true,
+	"might": true, "more": true, "most": true, "much": true, "must": true, "no": true, "nor": true, "not": true,
+	"of": true, "on": true, "once": true, "only": true, "or": true, "other": true, "our": true, "out": true,
+	"over": true, "own": true, "per": true, "same": true, "she": true, "should": true, "since": true, "so": true,
+	"some": true, "such": true, "than": true, "that": true, "the": true, "their": true, "them": true,
+	"then": true, "there": true, "these": true, "they": true, "this": true, "those": true, "through": true,
+	"to": true, "too": true, "under": true, "until": true, "up": true, "very": true, "was": true, "we": true,
+	"were": true, "what": true, "when": true, "where": true, "which": true, "while": true, "who": true,
+	"whom": true, "why": true, "will": true, "with": true, "within": true, "would": true, "yet": true,
+	"you": true, "your": true, "according": true, "including": true, "across": true, "among": true,
+	"said": true, "says": true, "new": true, "many": true, "several": true, "using": true, "used": true,
+}
+
+// extractKeywords ranks the words of the collected content by TF-IDF, treating
+// each result as a document, and its key phrases by RAKE. Both lists are sorted
+// best first and scored relative to their top entry.
+func extractKeywords(results []schemas.DroneResult) (terms, phrases []schemas.Keyword) {
+	termScores := make(map[string]float64)
+	termDocuments := make(map[string]int)
+	phraseCounts := make(map[string]int)
+	phraseDocuments := make(map[string]int)
+	wordFrequency := make(map[string]int)
+	wordDegree := make(map[string]int)
+
+	var documents []map[string]int
+	for _, result := range results {
+		counts := make(map[string]int)
+		seenPhrases := make(map[string]bool)
+		for _, text := range dataText(result.Data) {
+			for _, candidate := range candidatePhrases(text) {
+				for _, word := range candidate {
+					counts[word]++
+					// RAKE: a word's degree is the length of the phrases it appears in
+					wordFrequency[word]++
+					wordDegree[word] += len(candidate)
+				}
+				if len(candidate) > 1 && len(candidate) <= maxPhraseWords {
+					phrase := strings.Join(candidate, " ")
+					phraseCounts[phrase]++
+					if !seenPhrases[phrase] {
+						seenPhrases[phrase] = true
+						phraseDocuments[phrase]++
+					}
+				}
+			}
+		}
+		if len(counts) > 0 {
+			documents = append(documents, counts)
+			for word := range counts {
+				termDocuments[word]++
+			}
+		}
+	}
+
+	// TF-IDF summed over the documents, with smoothed IDF so terms in every
+	// document still count
+	for _, counts := range documents {
+		total := 0
+		for _, count := range counts {
+			total += count
+		}
+		for word, count := range counts {
+			idf := math.Log(float64(1+len(documents))/float64(1+termDocuments[word])) + 1
+			termScores[word] += float64(count) / float64(total) * idf
+		}
+	}
+	for word, score := range termScores {
+		terms = append(terms, schemas.Keyword{Term: word, Kind: KeywordTerm, Score: score, Documents: termDocuments[word]})
+	}
+
+	// RAKE scores a phrase by the degree-to-frequency ratio of its words;
+	// repeated phrases are boosted so one long sentence fragment does not win
+	for phrase, count := range phraseCounts {
+		score := 0.0
+		for _, word := range strings.Fields(phrase) {
+			score += float64(wordDegree[word]) / float64(wordFrequency[word])
+		}
+		score *= 1 + math.Log(float64(count))
+		phrases = append(phrases, schemas.Keyword{Term: phrase, Kind: KeywordPhrase, Score: score, Documents: phraseDocuments[phrase]})
+	}
+
+	return rankKeywords(terms), rankKeywords(phrases)
+}
+
+// candidatePhrases splits text into runs of content words, broken at stop
+// words and punctuation
+func candidatePhrases(text string) [][]string {
+	var phrases [][]string
+	var current []string
+	var word strings.Builder
+	endPhrase := func() {
+		if len(current) > 0 {
+			phrases = append(phrases, current)
+			current = nil
+		}
+	}
+	endWord := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := strings.Trim(word.String(), "'-")
+		word.Reset()
+		if !isKeywordWord(w) {
+			endPhrase()
+			return
+		}
+		current = append(current, w)
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' || r == '-':
+			word.WriteRune(r)
+		case unicode.IsSpace(r):
+			endWord()
+		default:
+			endWord()
+			endPhrase()
+		}
+	}
+	endWord()
+	endPhrase()
+	return phrases
+}
+
+// isKeywordWord reports whether a word can be part of a keyword: not a stop
+// word, a number or too short to mean anything
+func isKeywordWord(word string) bool {
+	return len([]rune(word)) >= 3 && !keywordStopWords[word] && strings.ContainsFunc(word, unicode.IsLetter)
+}
+
+// rankKeywords sorts keywords best first and scales their scores so the best is 1
+func rankKeywords(keywords []schemas.Keyword) []schemas.Keyword {
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Score != keywords[j].Score {
+			return keywords[i].Score > keywords[j].Score
+		}
+		return keywords[i].Term < keywords[j].Term
+	})
+	if len(keywords) > 0 && keywords[0].Score > 0 {
+		top := keywords[0].Score
+		for i := range keywords {
+			keywords[i].Score = math.Round(keywords[i].Score/top*1000) / 1000
+		}
+	}
+	return keywords
+}
+
+// keywordTerms lists the first n keywords' terms
+func keywordTerms(keywords []schemas.Keyword, n int) []string {
+	terms := make([]string, 0, n)
+	for _, keyword := range keywords[:min(n, len(keywords))] {
+		terms = append(terms, keyword.Term)
+	}
+	return terms
+}
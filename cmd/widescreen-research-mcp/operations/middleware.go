@@ -0,0 +1,99 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+	"github.com/spawn-mcp/coordinator/pkg/usage"
+)
+
+// LoggingMiddleware logs the outcome and duration of every operation call,
+// with the call's correlation ID
+func LoggingMiddleware() Middleware {
+	return func(operation *Operation, next OperationHandler) OperationHandler {
+		return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			start := time.Now()
+			result, err := next(ctx, params)
+			correlationID := mcperrors.CorrelationID(ctx)
+			if err != nil {
+				log.Printf("Operation %s [%s] failed after %v: %v", operation.Name, correlationID, time.Since(start).Round(time.Millisecond), err)
+			} else {
+				log.Printf("Operation %s [%s] completed in %v", operation.Name, correlationID, time.Since(start).Round(time.Millisecond))
+			}
+			return result, err
+		}
+	}
+}
+
+// RecoveryMiddleware turns a panic in an operation into an MCP-5004 error with
+// the stack trace, and reports it to Cloud Error Reporting
+func RecoveryMiddleware() Middleware {
+	return func(operation *Operation, next OperationHandler) OperationHandler {
+		return func(ctx context.Context, params map[string]interface{}) (result interface{}, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					result, err = nil, mcperrors.Recover(ctx, r, "operation "+operation.Name)
+				}
+			}()
+			return next(ctx, params)
+		}
+	}
+}
+
+// ValidationMiddleware rejects calls that leave out one of the operation's
+// RequiredParams, before its handler runs
+func ValidationMiddleware() Middleware {
+	return func(operation *Operation, next OperationHandler) OperationHandler {
+		if len(operation.RequiredParams) == 0 {
+			return next
+		}
+		return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			for _, name := range operation.RequiredParams {
+				if value, ok := params[name]; !ok || value == nil || value == "" {
+					return nil, mcperrors.InvalidInput("%s is required", name)
+				}
+			}
+			return next(ctx, params)
+		}
+	}
+}
+
+// TimeoutMiddleware cancels the context of calls that run longer than the
+// operation's Timeout, or than defaultTimeout when the operation sets none.
+// The handler must watch its context for the timeout to take effect.
+func TimeoutMiddleware(defaultTimeout time.Duration) Middleware {
+	return func(operation *Operation, next OperationHandler) OperationHandler {
+		timeout := operation.Timeout
+		if timeout == 0 {
+			timeout = defaultTimeout
+		}
+		if timeout <= 0 {
+			return next
+		}
+		return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			result, err := next(ctx, params)
+			if err != nil && ctx.Err() == context.DeadlineExceeded {
+				return nil, fmt.Errorf("operation %s timed out after %v: %w", operation.Name, timeout, err)
+			}
+			return result, err
+		}
+	}
+}
+
+// MetricsMiddleware records the calls, errors and durations of every operation in recorder
+func MetricsMiddleware(recorder *usage.Recorder) Middleware {
+	return func(operation *Operation, next OperationHandler) OperationHandler {
+		return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			start := time.Now()
+			result, err := next(ctx, params)
+			recorder.Record(operation.Name, start, err)
+			return result, err
+		}
+	}
+}
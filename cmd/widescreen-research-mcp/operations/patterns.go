@@ -0,0 +1,180 @@
+package operations
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// PatternDetector finds one kind of pattern in drone results. Detect returns
+// nil when the results do not show the pattern.
+type PatternDetector interface {
+	Name() string
+	Detect(results []schemas.DroneResult) *schemas.Pattern
+}
+
+// funcDetector adapts a function to a PatternDetector
+type funcDetector struct {
+	name   string
+	detect func(results []schemas.DroneResult) *schemas.Pattern
+}
+
+// NewPatternDetector creates a detector from a function
+func NewPatternDetector(name string, detect func(results []schemas.DroneResult) *schemas.Pattern) PatternDetector {
+	return funcDetector{name: name, detect: detect}
+}
+
+// Name implements PatternDetector
+func (d funcDetector) Name() string {
+	return d.name
+}
+
+// Detect implements PatternDetector
+func (d funcDetector) Detect(results []schemas.DroneResult) *schemas.Pattern {
+	return d.detect(results)
+}
+
+var (
+	// patternDetectors are the detectors registered in addition to the built-in ones
+	patternDetectors   []PatternDetector
+	patternDetectorsMu sync.RWMutex
+)
+
+// builtinDetectorNames are reserved for the analyzer's own detectors
+var builtinDetectorNames = map[string]bool{"completion": true, "data_volume": true, "errors": true, "source_diversity": true}
+
+// RegisterPatternDetector adds a detector that every analysis runs after the
+// built-in ones, e.g. one flagging results that mention regulators. Names must
+// be unique.
+func RegisterPatternDetector(detector PatternDetector) error {
+	patternDetectorsMu.Lock()
+	defer patternDetectorsMu.Unlock()
+
+	name := detector.Name()
+	if name == "" {
+		return fmt.Errorf("pattern detector name is required")
+	}
+	if builtinDetectorNames[name] {
+		return fmt.Errorf("pattern detector %q is built in", name)
+	}
+	for _, registered := range patternDetectors {
+		if registered.Name() == name {
+			return fmt.Errorf("pattern detector %q is already registered", name)
+		}
+	}
+	patternDetectors = append(patternDetectors, detector)
+	return nil
+}
+
+// UnregisterPatternDetector removes a registered detector, reporting whether it was registered
+func UnregisterPatternDetector(name string) bool {
+	patternDetectorsMu.Lock()
+	defer patternDetectorsMu.Unlock()
+
+	for i, registered := range patternDetectors {
+		if registered.Name() == name {
+			patternDetectors = append(patternDetectors[:i], patternDetectors[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// PatternDetectors lists the names of the built-in and registered detectors, in the order they run
+func PatternDetectors() []string {
+	var names []string
+	for _, detector := range NewDataAnalyzer().detectors() {
+		names = append(names, detector.Name())
+	}
+	return names
+}
+
+// detectors returns the built-in detectors followed by the registered ones
+func (da *DataAnalyzer) detectors() []PatternDetector {
+	detectors := []PatternDetector{
+		NewPatternDetector("completion", da.identifyCompletionPattern),
+		NewPatternDetector("data_volume", da.identifyDataVolumePattern),
+		NewPatternDetector("errors", da.identifyErrorPattern),
+		NewPatternDetector("source_diversity", da.identifySourceDiversityPattern),
+	}
+
+	patternDetectorsMu.RLock()
+	defer patternDetectorsMu.RUnlock()
+	return append(detectors, patternDetectors...)
+}
+
+// runDetector runs a detector, so a failing plugged-in detector does not fail the analysis
+func runDetector(detector PatternDetector, results []schemas.DroneResult) (pattern *schemas.Pattern) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Warning: Pattern detector %s failed: %v", detector.Name(), r)
+			pattern = nil
+		}
+	}()
+	return detector.Detect(results)
+}
+
+// NewMentionDetector creates a detector for results whose text mentions any of
+// the terms, case-insensitively. It reports a pattern once at least minShare
+// of the completed results mention one, e.g. a regulatory-mention detector
+// with terms like "SEC", "FDA" and "GDPR".
+func NewMentionDetector(name, description string, terms []string, minShare float64) PatternDetector {
+	lowered := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" {
+			lowered = append(lowered, term)
+		}
+	}
+
+	return NewPatternDetector(name, func(results []schemas.DroneResult) *schemas.Pattern {
+		completed, mentioning := 0, 0
+		for _, result := range results {
+			if result.Status != "completed" {
+				continue
+			}
+			completed++
+			if mentionsAny(strings.ToLower(strings.Join(dataText(result.Data), " ")), lowered) {
+				mentioning++
+			}
+		}
+		if completed == 0 || mentioning == 0 {
+			return nil
+		}
+		share := float64(mentioning) / float64(completed)
+		if share < minShare {
+			return nil
+		}
+		return &schemas.Pattern{
+			Name:        name,
+			Description: description,
+			Frequency:   mentioning,
+			Confidence:  share,
+		}
+	})
+}
+
+// mentionsAny reports whether lowercase text contains any of the terms as whole words
+func mentionsAny(text string, terms []string) bool {
+	for _, term := range terms {
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], term)
+			if i < 0 {
+				break
+			}
+			start, end := offset+i, offset+i+len(term)
+			if (start == 0 || !isWordByte(text[start-1])) && (end == len(text) || !isWordByte(text[end])) {
+				return true
+			}
+			offset = start + 1
+		}
+	}
+	return false
+}
+
+// isWordByte reports whether b is part of a word, for whole-word matching
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80
+}
@@ -0,0 +1,204 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+)
+
+// Quality criteria
+const (
+	QualityCompleteness = "completeness"
+	QualitySources      = "sources"
+	QualityRecency      = "recency"
+	QualityErrors       = "errors"
+)
+
+// QualityRubric sets how a drone result's data quality is scored. Each
+// criterion scores between 0 and 1, and the weighted average is scaled to 0-10.
+type QualityRubric struct {
+	// Criterion weights; they need not sum to 1
+	CompletenessWeight float64 `json:"completeness_weight"`
+	SourcesWeight      float64 `json:"sources_weight"`
+	RecencyWeight      float64 `json:"recency_weight"`
+	ErrorsWeight       float64 `json:"errors_weight"`
+	// TargetDataPoints is how many data points make a result complete
+	TargetDataPoints int `json:"target_data_points"`
+	// TargetSources is how many distinct sources make a result well sourced
+	TargetSources int `json:"target_sources"`
+	// StaleAfterDays is the age after which sources count as less recent
+	StaleAfterDays int `json:"stale_after_days"`
+}
+
+// LoadQualityRubric reads the data quality rubric from the environment
+func LoadQualityRubric() QualityRubric {
+	return QualityRubric{
+		CompletenessWeight: envWeight("QUALITY_WEIGHT_COMPLETENESS", 0.4),
+		SourcesWeight:      envWeight("QUALITY_WEIGHT_SOURCES", 0.3),
+		RecencyWeight:      envWeight("QUALITY_WEIGHT_RECENCY", 0.1),
+		ErrorsWeight:       envWeight("QUALITY_WEIGHT_ERRORS", 0.2),
+		TargetDataPoints:   envCount("QUALITY_TARGET_DATA_POINTS", 5),
+		TargetSources:      envCount("QUALITY_TARGET_SOURCES", 3),
+		StaleAfterDays:     envCount("QUALITY_STALE_AFTER_DAYS", 365),
+	}
+}
+
+// envWeight reads a non-negative weight from the environment
+func envWeight(key string, defaultValue float64) float64 {
+	if v, err := strconv.ParseFloat(getEnvOrDefault(key, ""), 64); err == nil && v >= 0 {
+		return v
+	}
+	return defaultValue
+}
+
+// envCount reads a positive count from the environment
+func envCount(key string, defaultValue int) int {
+	if v, err := strconv.Atoi(getEnvOrDefault(key, "")); err == nil && v > 0 {
+		return v
+	}
+	return defaultValue
+}
+
+// withOverrides applies the rubric fields given in the quality_rubric
+// parameter, keeping the others
+func (r QualityRubric) withOverrides(params map[string]interface{}) (QualityRubric, error) {
+	raw, ok := params["quality_rubric"]
+	if !ok || raw == nil {
+		return r, nil
+	}
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return r, mcperrors.InvalidInput("invalid quality_rubric: %w", err)
+	}
+	if err := json.Unmarshal(encoded, &r); err != nil {
+		return r, mcperrors.InvalidInput("invalid quality_rubric: %w", err)
+	}
+	return r, r.validate()
+}
+
+// validate checks the weights can be averaged and the targets are reachable
+func (r QualityRubric) validate() error {
+	weights := []float64{r.CompletenessWeight, r.SourcesWeight, r.RecencyWeight, r.ErrorsWeight}
+	total := 0.0
+	for _, weight := range weights {
+		if weight < 0 {
+			return mcperrors.InvalidInput("invalid quality_rubric: weights cannot be negative")
+		}
+		total += weight
+	}
+	if total == 0 {
+		return mcperrors.InvalidInput("invalid quality_rubric: at least one weight must be positive")
+	}
+	if r.TargetDataPoints < 1 || r.TargetSources < 1 || r.StaleAfterDays < 1 {
+		return mcperrors.InvalidInput("invalid quality_rubric: targets and stale_after_days must be positive")
+	}
+	return nil
+}
+
+// score rates one result. Failed and empty results score 0. Recency is left
+// out, and the other weights rescaled, when the result's sources have no dates.
+func (r QualityRubric) score(result schemas.DroneResult, now time.Time) schemas.DroneQuality {
+	quality := schemas.DroneQuality{DroneID: result.DroneID, Criteria: make(map[string]float64)}
+	if result.Status != "completed" || len(result.Data) == 0 {
+		quality.Notes = append(quality.Notes, "no data collected")
+		return quality
+	}
+
+	sources, published := qualitySources(result)
+	criteria := map[string]float64{
+		QualityCompleteness: math.Min(1, float64(len(result.Data))/float64(r.TargetDataPoints)),
+		QualitySources:      math.Min(1, float64(sources)/float64(r.TargetSources)),
+		QualityErrors:       1,
+	}
+	weights := map[string]float64{
+		QualityCompleteness: r.CompletenessWeight,
+		QualitySources:      r.SourcesWeight,
+		QualityErrors:       r.ErrorsWeight,
+	}
+	if result.Error != "" {
+		criteria[QualityErrors] = 0
+	}
+	if len(published) > 0 {
+		// Sources lose recency in proportion to how far past stale they are
+		stale := float64(r.StaleAfterDays) * 24
+		total := 0.0
+		for _, date := range published {
+			age := now.Sub(date).Hours()
+			total += math.Min(1, stale/math.Max(age, 1))
+		}
+		criteria[QualityRecency] = total / float64(len(published))
+		weights[QualityRecency] = r.RecencyWeight
+	}
+	// Note the shortfalls that cost the result points
+	if criteria[QualityCompleteness] < 1 && r.CompletenessWeight > 0 {
+		quality.Notes = append(quality.Notes, fmt.Sprintf("%d of %d target data points", len(result.Data), r.TargetDataPoints))
+	}
+	if criteria[QualitySources] < 1 && r.SourcesWeight > 0 {
+		quality.Notes = append(quality.Notes, fmt.Sprintf("%d of %d target sources", sources, r.TargetSources))
+	}
+	if criteria[QualityErrors] == 0 && r.ErrorsWeight > 0 {
+		quality.Notes = append(quality.Notes, "reported an error")
+	}
+	if recency, ok := criteria[QualityRecency]; ok && recency < 1 && r.RecencyWeight > 0 {
+		quality.Notes = append(quality.Notes, fmt.Sprintf("sources older than %d days", r.StaleAfterDays))
+	}
+
+	weighted, totalWeight := 0.0, 0.0
+	for criterion, value := range criteria {
+		weighted += value * weights[criterion]
+		totalWeight += weights[criterion]
+	}
+	if totalWeight > 0 {
+		quality.Score = math.Round(weighted/totalWeight*1000) / 100
+	}
+	quality.Criteria = criteria
+	return quality
+}
+
+// qualitySources counts the distinct sources a result cites under "sources",
+// as URLs or as objects with a url and publication date, and returns the dates given
+func qualitySources(result schemas.DroneResult) (int, []time.Time) {
+	seen := make(map[string]bool)
+	var published []time.Time
+	items, _ := result.Data["sources"].([]interface{})
+	for _, item := range items {
+		var url string
+		switch v := item.(type) {
+		case string:
+			url = v
+		case map[string]interface{}:
+			url, _ = v["url"].(string)
+			for _, field := range []string{"published_at", "published", "date"} {
+				if date, ok := v[field].(string); ok {
+					for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+						if t, err := time.Parse(layout, strings.TrimSpace(date)); err == nil {
+							published = append(published, t)
+							break
+						}
+					}
+					break
+				}
+			}
+		}
+		if url != "" {
+			seen[url] = true
+		}
+	}
+	return len(seen), published
+}
+
+// assessQuality scores every result against the analyzer's rubric
+func (da *DataAnalyzer) assessQuality(results []schemas.DroneResult) []schemas.DroneQuality {
+	now := time.Now()
+	qualities := make([]schemas.DroneQuality, len(results))
+	for i, result := range results {
+		qualities[i] = da.rubric.score(result, now)
+	}
+	return qualities
+}
@@ -2,8 +2,10 @@ package operations
 
 import (
 	"context"
-	"fmt"
 	"sync"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
 )
 
 // Operation represents a single operation that can be performed
@@ -11,14 +13,24 @@ type Operation struct {
 	Name        string
 	Description string
 	Handler     OperationHandler
+	// RequiredParams are the parameters ValidationMiddleware rejects calls without
+	RequiredParams []string
+	// Timeout bounds the operation under TimeoutMiddleware; zero uses the
+	// middleware's default and a negative value means no timeout
+	Timeout time.Duration
 }
 
 // OperationHandler is the function signature for operation handlers
 type OperationHandler func(ctx context.Context, params map[string]interface{}) (interface{}, error)
 
+// Middleware wraps the handler of an operation, to run code around every call
+// of it, such as logging or a timeout
+type Middleware func(operation *Operation, next OperationHandler) OperationHandler
+
 // OperationRegistry manages all available operations
 type OperationRegistry struct {
 	operations map[string]*Operation
+	middleware []Middleware
 	mu         sync.RWMutex
 }
 
@@ -55,12 +67,27 @@ func (r *OperationRegistry) ListOperations() map[string]*Operation {
 	return ops
 }
 
-// Execute executes an operation by name
+// Use adds middleware that every operation runs through. The first middleware
+// added is the outermost, so it sees each call first and its result last.
+func (r *OperationRegistry) Use(middleware ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// Execute executes an operation by name through the registry's middleware
 func (r *OperationRegistry) Execute(ctx context.Context, name string, params map[string]interface{}) (interface{}, error) {
-	op := r.GetOperation(name)
+	r.mu.RLock()
+	op := r.operations[name]
+	middleware := r.middleware
+	r.mu.RUnlock()
 	if op == nil {
-		return nil, fmt.Errorf("operation not found: %s", name)
+		return nil, mcperrors.InvalidInput("unknown operation: %s", name)
 	}
-	
-	return op.Handler(ctx, params)
+
+	handler := op.Handler
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](op, handler)
+	}
+	return handler(ctx, params)
 }
\ No newline at end of file
@@ -0,0 +1,186 @@
+package operations
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// Sentiment labels
+const (
+	SentimentPositive = "positive"
+	SentimentNeutral  = "neutral"
+	SentimentNegative = "negative"
+)
+
+// neutralBand is how far from zero a score must be to count as positive or negative
+const neutralBand = 0.1
+
+// sentimentLexicon scores words that carry sentiment in market and brand research
+var sentimentLexicon = map[string]float64{
+	// Positive
+	"good": 1, "great": 1.5, "excellent": 2, "outstanding": 2, "strong": 1, "stronger": 1, "strongest": 1.5,
+	"growth": 1, "grow": 1, "grew": 1, "growing": 1, "gain": 1, "gains": 1, "gained": 1, "profit": 1, "profitable": 1.5,
+	"success": 1.5, "successful": 1.5, "win": 1, "wins": 1, "won": 1, "leader": 1, "leading": 1, "innovative": 1.5,
+	"innovation": 1, "popular": 1, "praised": 1.5, "praise": 1.5, "love": 2, "loved": 2, "loves": 2, "like": 0.5,
+	"liked": 1, "positive": 1, "improve": 1, "improved": 1, "improvement": 1, "record": 0.5, "surge": 1.5,
+	"surged": 1.5, "beat": 1, "exceeded": 1.5, "reliable": 1, "trusted": 1, "satisfied": 1.5, "recommend": 1.5,
+	"recommended": 1.5, "impressive": 1.5, "robust": 1, "benefit": 1, "benefits": 1, "opportunity": 1,
+	"optimistic": 1.5, "upgrade": 1, "upgraded": 1, "rally": 1, "rallied": 1, "boost": 1, "boosted": 1,
+	// Negative
+	"bad": -1, "poor": -1.5, "terrible": -2, "awful": -2, "weak": -1, "weaker": -1, "decline": -1, "declined": -1,
+	"declining": -1, "loss": -1, "losses": -1, "lost": -1, "drop": -1, "dropped": -1, "fall": -1, "fell": -1,
+	"falling": -1, "risk": -0.5, "risks": -0.5, "risky": -1, "concern": -1, "concerns": -1, "concerned": -1,
+	"problem": -1, "problems": -1, "issue": -0.5, "issues": -0.5, "failure": -1.5, "failed": -1.5, "fail": -1.5,
+	"lawsuit": -1.5, "scandal": -2, "controversy": -1.5, "controversial": -1, "criticized": -1.5, "criticism": -1.5,
+	"complaint": -1.5, "complaints": -1.5, "hate": -2, "hated": -2, "negative": -1, "layoffs": -1.5, "fraud": -2,
+	"breach": -1.5, "recall": -1, "slow": -0.5, "slowdown": -1, "downgrade": -1, "downgraded": -1, "struggle": -1,
+	"struggling": -1, "unreliable": -1.5, "disappointing": -1.5, "disappointed": -1.5, "pessimistic": -1.5,
+	"expensive": -0.5, "plunge": -1.5, "plunged": -1.5, "bankruptcy": -2, "fine": -0.5, "fined": -1.5,
+}
+
+// negators flip the sentiment of the next few words
+var negators = map[string]bool{"not": true, "no": true, "never": true, "without": true, "hardly": true, "isn't": true, "wasn't": true, "aren't": true, "didn't": true, "doesn't": true, "don't": true, "can't": true, "won't": true}
+
+// intensifiers strengthen the next sentiment word
+var intensifiers = map[string]float64{"very": 1.5, "highly": 1.5, "extremely": 2, "significantly": 1.5, "strongly": 1.5, "slightly": 0.5, "somewhat": 0.5}
+
+// scoreSentiment rates text between -1 (negative) and 1 (positive). It also
+// returns how many sentiment words it found; text with none scores 0.
+func scoreSentiment(text string) (float64, int) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+
+	total, found := 0.0, 0
+	negateFor := 0
+	intensity := 1.0
+	for _, word := range words {
+		if negators[word] {
+			negateFor = 3
+			continue
+		}
+		if factor, ok := intensifiers[word]; ok {
+			intensity = factor
+			continue
+		}
+		if value, ok := sentimentLexicon[word]; ok {
+			value *= intensity
+			if negateFor > 0 {
+				value = -value
+			}
+			total += value
+			found++
+		}
+		intensity = 1
+		if negateFor > 0 {
+			negateFor--
+		}
+	}
+	if found == 0 {
+		return 0, 0
+	}
+	// Squash the average so a few strong words do not saturate the score
+	return math.Tanh(total / float64(found)), found
+}
+
+// sentimentLabel turns a score into positive, neutral or negative
+func sentimentLabel(score float64) string {
+	switch {
+	case score > neutralBand:
+		return SentimentPositive
+	case score < -neutralBand:
+		return SentimentNegative
+	}
+	return SentimentNeutral
+}
+
+// sentimentAccumulator averages the scores of the texts about one sub-query or source
+type sentimentAccumulator struct {
+	total float64
+	texts int
+}
+
+// analyzeSentiment scores the text of each completed result and each finding,
+// and averages the scores by sub-query and by source. Results are grouped by
+// their "query" or "subject" data, or else by drone.
+func analyzeSentiment(results []schemas.DroneResult) (scores []schemas.SentimentScore, textScores []float64) {
+	queries := make(map[string]*sentimentAccumulator)
+	sources := make(map[string]*sentimentAccumulator)
+	add := func(groups map[string]*sentimentAccumulator, key string, score float64) {
+		if groups[key] == nil {
+			groups[key] = &sentimentAccumulator{}
+		}
+		groups[key].total += score
+		groups[key].texts++
+	}
+
+	for _, result := range results {
+		if result.Status != "completed" {
+			continue
+		}
+		query, _ := result.Data["query"].(string)
+		if query == "" {
+			query, _ = result.Data["subject"].(string)
+		}
+		if query == "" {
+			query = result.DroneID
+		}
+
+		// Content without sentiment words counts as neutral
+		score, _ := scoreSentiment(strings.Join(dataText(result.Data), " "))
+		textScores = append(textScores, score)
+		add(queries, query, score)
+
+		// Sources cited by the whole result share its score; those backing a
+		// finding get that finding's own score
+		if cited, ok := result.Data["sources"].([]interface{}); ok {
+			for _, source := range cited {
+				if url, ok := source.(string); ok {
+					add(sources, url, score)
+				}
+			}
+		}
+		findings, _ := result.Data["findings"].([]interface{})
+		for _, item := range findings {
+			finding, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			claim, _ := finding["claim"].(string)
+			url, _ := finding["url"].(string)
+			if findingScore, found := scoreSentiment(claim); found > 0 && url != "" {
+				add(sources, url, findingScore)
+			}
+		}
+	}
+
+	for _, group := range []struct {
+		kind   string
+		groups map[string]*sentimentAccumulator
+	}{{"query", queries}, {"source", sources}} {
+		for subject, acc := range group.groups {
+			score := acc.total / float64(acc.texts)
+			scores = append(scores, schemas.SentimentScore{
+				Subject: subject,
+				Kind:    group.kind,
+				Score:   score,
+				Label:   sentimentLabel(score),
+				Texts:   acc.texts,
+			})
+		}
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Kind != scores[j].Kind {
+			return scores[i].Kind < scores[j].Kind
+		}
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].Subject < scores[j].Subject
+	})
+	return scores, textScores
+}
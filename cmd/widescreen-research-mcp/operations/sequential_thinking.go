@@ -6,6 +6,7 @@ import (
 
 	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/orchestrator"
 	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
 )
 
 // SequentialThinking implements sequential thinking style reasoning
@@ -25,7 +26,7 @@ func (st *SequentialThinking) Execute(ctx context.Context, params map[string]int
 	// Extract parameters
 	problem, ok := params["problem"].(string)
 	if !ok {
-		return nil, fmt.Errorf("problem parameter is required")
+		return nil, mcperrors.InvalidInput("problem parameter is required")
 	}
 
 	contextStr := ""
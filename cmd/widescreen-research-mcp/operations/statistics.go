@@ -0,0 +1,189 @@
+package operations
+
+import (
+	"math"
+	"sort"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+const (
+	// confidenceZ is the normal quantile for 95% confidence intervals
+	confidenceZ = 1.96
+	// outlierFence is how many interquartile ranges past the quartiles a value must lie to be an outlier
+	outlierFence = 1.5
+	// minOutlierSample is the fewest values outliers are looked for in
+	minOutlierSample = 4
+	// histogramBuckets is how many bars distribution charts have
+	histogramBuckets = 10
+)
+
+// describe summarizes the distribution of a sample: its spread, quartiles and
+// shape. It returns nil for an empty sample.
+func describe(values []float64) map[string]interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mean, stdDev := meanStdDev(sorted)
+	summary := map[string]interface{}{
+		"count":   len(sorted),
+		"mean":    mean,
+		"std_dev": stdDev,
+		"min":     sorted[0],
+		"p25":     percentile(sorted, 0.25),
+		"median":  percentile(sorted, 0.5),
+		"p75":     percentile(sorted, 0.75),
+		"p90":     percentile(sorted, 0.9),
+		"max":     sorted[len(sorted)-1],
+	}
+	// Skewness says which way the tail leans, e.g. a few slow drones. It is the
+	// adjusted Fisher-Pearson coefficient, which goes with the sample standard deviation.
+	if stdDev > 0 && len(sorted) > 2 {
+		skew := 0.0
+		for _, v := range sorted {
+			skew += math.Pow((v-mean)/stdDev, 3)
+		}
+		n := float64(len(sorted))
+		summary["skewness"] = skew * n / ((n - 1) * (n - 2))
+	}
+	// The mean's 95% confidence interval, by the normal approximation
+	if len(sorted) > 1 {
+		margin := confidenceZ * stdDev / math.Sqrt(float64(len(sorted)))
+		summary["mean_ci_low"] = mean - margin
+		summary["mean_ci_high"] = mean + margin
+	}
+	return summary
+}
+
+// meanStdDev returns a sample's mean and sample standard deviation
+func meanStdDev(values []float64) (mean, stdDev float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	for _, v := range values {
+		stdDev += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(stdDev / float64(len(values)-1))
+}
+
+// percentile interpolates the p-th quantile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	position := p * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
+
+// wilsonInterval is the 95% Wilson score interval of a success rate, which
+// stays within 0 and 1 and is sound for the small samples drone swarms give
+func wilsonInterval(successes, total int) (low, high float64) {
+	if total == 0 {
+		return 0, 0
+	}
+	n := float64(total)
+	p := float64(successes) / n
+	z2 := confidenceZ * confidenceZ
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	margin := confidenceZ * math.Sqrt(p*(1-p)/n+z2/(4*n*n)) / (1 + z2/n)
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// pearson returns the correlation coefficient of paired samples, or false if
+// there are fewer than three pairs or either sample is constant
+func pearson(x, y []float64) (float64, bool) {
+	if len(x) != len(y) || len(x) < 3 {
+		return 0, false
+	}
+	meanX, sdX := meanStdDev(x)
+	meanY, sdY := meanStdDev(y)
+	if sdX == 0 || sdY == 0 {
+		return 0, false
+	}
+	covariance := 0.0
+	for i := range x {
+		covariance += (x[i] - meanX) * (y[i] - meanY)
+	}
+	covariance /= float64(len(x) - 1)
+	return covariance / (sdX * sdY), true
+}
+
+// correlationStrength describes a correlation coefficient in words
+func correlationStrength(r float64) string {
+	direction := "positive"
+	if r < 0 {
+		direction = "negative"
+	}
+	switch abs := math.Abs(r); {
+	case abs >= 0.7:
+		return "strong " + direction
+	case abs >= 0.4:
+		return "moderate " + direction
+	case abs >= 0.2:
+		return "weak " + direction
+	default:
+		return "no meaningful"
+	}
+}
+
+// outliers returns the indices of values outside Tukey's fences, 1.5
+// interquartile ranges beyond the quartiles
+func outliers(values []float64) []int {
+	if len(values) < minOutlierSample {
+		return nil
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	q1, q3 := percentile(sorted, 0.25), percentile(sorted, 0.75)
+	iqr := q3 - q1
+	low, high := q1-outlierFence*iqr, q3+outlierFence*iqr
+
+	var indices []int
+	for i, v := range values {
+		if v < low || v > high {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// histogram counts values into equal-width buckets for a bar chart
+func histogram(values []float64) schemas.Visualization {
+	chart := schemas.Visualization{Type: "histogram"}
+	if len(values) == 0 {
+		return chart
+	}
+	lowest, highest := values[0], values[0]
+	for _, v := range values {
+		lowest, highest = math.Min(lowest, v), math.Max(highest, v)
+	}
+	buckets := min(histogramBuckets, len(values))
+	width := (highest - lowest) / float64(buckets)
+	if width == 0 {
+		buckets, width = 1, 1
+	}
+
+	counts := make([]int, buckets)
+	edges := make([]float64, buckets+1)
+	for i := range edges {
+		edges[i] = lowest + float64(i)*width
+	}
+	for _, v := range values {
+		bucket := min(int((v-lowest)/width), buckets-1)
+		counts[bucket]++
+	}
+	chart.Data = map[string]interface{}{"bucket_edges": edges, "counts": counts}
+	return chart
+}
@@ -0,0 +1,225 @@
+package operations
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// approxEqual reports whether two floats agree to within 1e-6
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   map[string]float64
+		// absent are the keys the summary must not have
+		absent []string
+	}{
+		{
+			name:   "right skewed sample",
+			values: []float64{10, 1, 4, 2, 3},
+			want: map[string]float64{
+				"count":        5,
+				"mean":         4,
+				"std_dev":      3.5355339,
+				"min":          1,
+				"p25":          2,
+				"median":       3,
+				"p75":          4,
+				"p90":          7.6,
+				"max":          10,
+				"skewness":     1.6970563,
+				"mean_ci_low":  4 - 3.0990321,
+				"mean_ci_high": 4 + 3.0990321,
+			},
+		},
+		{
+			name:   "symmetric sample",
+			values: []float64{1, 2, 3},
+			want:   map[string]float64{"mean": 2, "std_dev": 1, "skewness": 0},
+		},
+		{
+			name:   "left skewed sample",
+			values: []float64{-10, -1, -4, -2, -3},
+			want:   map[string]float64{"mean": -4, "skewness": -1.6970563},
+		},
+		{
+			name:   "constant sample",
+			values: []float64{7, 7, 7},
+			want:   map[string]float64{"mean": 7, "std_dev": 0, "mean_ci_low": 7, "mean_ci_high": 7},
+			absent: []string{"skewness"},
+		},
+		{
+			name:   "single value",
+			values: []float64{5},
+			want:   map[string]float64{"count": 1, "mean": 5, "std_dev": 0, "median": 5, "p90": 5},
+			absent: []string{"skewness", "mean_ci_low", "mean_ci_high"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := describe(tt.values)
+			for key, want := range tt.want {
+				var got float64
+				switch v := summary[key].(type) {
+				case float64:
+					got = v
+				case int:
+					got = float64(v)
+				default:
+					t.Errorf("summary[%q] = %#v, want %v", key, summary[key], want)
+					continue
+				}
+				if !approxEqual(got, want) {
+					t.Errorf("summary[%q] = %v, want %v", key, got, want)
+				}
+			}
+			for _, key := range tt.absent {
+				if _, ok := summary[key]; ok {
+					t.Errorf("summary has %q, want it absent", key)
+				}
+			}
+		})
+	}
+
+	if summary := describe(nil); summary != nil {
+		t.Errorf("describe(nil) = %v, want nil", summary)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{10, 20, 30, 40}
+	tests := []struct {
+		sorted []float64
+		p      float64
+		want   float64
+	}{
+		{sorted, 0, 10},
+		{sorted, 1, 40},
+		{sorted, 0.5, 25},
+		{sorted, 1.0 / 3, 20},
+		{sorted, 0.9, 37},
+		{[]float64{3}, 0.75, 3},
+		{nil, 0.5, 0},
+	}
+	for _, tt := range tests {
+		if got := percentile(tt.sorted, tt.p); !approxEqual(got, tt.want) {
+			t.Errorf("percentile(%v, %v) = %v, want %v", tt.sorted, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestWilsonInterval(t *testing.T) {
+	tests := []struct {
+		successes, total int
+		low, high        float64
+	}{
+		{0, 0, 0, 0},
+		{5, 10, 0.2365896, 0.7634104},
+		{10, 10, 0.7224598, 1},
+		{0, 4, 0, 0.4899000},
+	}
+	for _, tt := range tests {
+		low, high := wilsonInterval(tt.successes, tt.total)
+		if !approxEqual(low, tt.low) || !approxEqual(high, tt.high) {
+			t.Errorf("wilsonInterval(%d, %d) = (%v, %v), want (%v, %v)", tt.successes, tt.total, low, high, tt.low, tt.high)
+		}
+	}
+}
+
+func TestPearson(t *testing.T) {
+	tests := []struct {
+		name   string
+		x, y   []float64
+		want   float64
+		wantOK bool
+	}{
+		{"perfect positive", []float64{1, 2, 3}, []float64{2, 4, 6}, 1, true},
+		{"perfect negative", []float64{1, 2, 3, 4}, []float64{8, 6, 4, 2}, -1, true},
+		{"partial", []float64{1, 2, 3, 4, 5}, []float64{2, 4, 5, 4, 5}, 0.7745967, true},
+		{"constant sample", []float64{1, 2, 3}, []float64{5, 5, 5}, 0, false},
+		{"too few pairs", []float64{1, 2}, []float64{2, 4}, 0, false},
+		{"unpaired", []float64{1, 2, 3}, []float64{2, 4}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := pearson(tt.x, tt.y)
+		if ok != tt.wantOK || !approxEqual(got, tt.want) {
+			t.Errorf("%s: pearson() = (%v, %v), want (%v, %v)", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestOutliers(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   []int
+	}{
+		{"high outlier", []float64{1, 2, 100, 3, 4}, []int{2}},
+		{"both tails", []float64{-50, 10, 11, 12, 13, 14, 90}, []int{0, 6}},
+		{"none", []float64{1, 2, 3, 4, 5}, nil},
+		{"constant", []float64{10, 10, 10, 10}, nil},
+		{"too small a sample", []float64{1, 2, 100}, nil},
+	}
+	for _, tt := range tests {
+		if got := outliers(tt.values); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: outliers(%v) = %v, want %v", tt.name, tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []float64
+		wantEdges []float64
+		wantCount []int
+	}{
+		{"one value per bucket", []float64{0, 5, 10}, []float64{0, 10.0 / 3, 20.0 / 3, 10}, []int{1, 1, 1}},
+		{"maximum in the last bucket", []float64{0, 0, 1, 4}, []float64{0, 1, 2, 3, 4}, []int{2, 1, 0, 1}},
+		{"constant values", []float64{5, 5, 5}, []float64{5, 6}, []int{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chart := histogram(tt.values)
+			if chart.Type != "histogram" {
+				t.Errorf("chart type = %q, want histogram", chart.Type)
+			}
+			data, ok := chart.Data.(map[string]interface{})
+			if !ok {
+				t.Fatalf("chart data = %#v, want a map", chart.Data)
+			}
+			edges, _ := data["bucket_edges"].([]float64)
+			if len(edges) != len(tt.wantEdges) {
+				t.Fatalf("bucket edges = %v, want %v", edges, tt.wantEdges)
+			}
+			for i := range edges {
+				if !approxEqual(edges[i], tt.wantEdges[i]) {
+					t.Errorf("bucket edges = %v, want %v", edges, tt.wantEdges)
+					break
+				}
+			}
+			if counts := data["counts"]; !reflect.DeepEqual(counts, tt.wantCount) {
+				t.Errorf("counts = %v, want %v", counts, tt.wantCount)
+			}
+		})
+	}
+
+	// Ten buckets at most
+	values := make([]float64, 100)
+	for i := range values {
+		values[i] = float64(i)
+	}
+	counts, _ := histogram(values).Data.(map[string]interface{})["counts"].([]int)
+	if len(counts) != histogramBuckets {
+		t.Errorf("histogram of 100 values has %d buckets, want %d", len(counts), histogramBuckets)
+	}
+
+	if chart := histogram(nil); chart.Data != nil {
+		t.Errorf("histogram(nil) data = %v, want nil", chart.Data)
+	}
+}
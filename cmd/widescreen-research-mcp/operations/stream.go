@@ -0,0 +1,379 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/encryption"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	// sessionsCollection and resultsCollection are where the orchestrator
+	// stores each session's drone results, as research_sessions/{id}/results
+	sessionsCollection = "research_sessions"
+	resultsCollection  = "results"
+
+	// reservoirSize is how many values running statistics keep to estimate quantiles
+	reservoirSize = 1000
+	// maxTrackedSources caps the distinct sources counted, so memory stays bounded
+	maxTrackedSources = 10000
+)
+
+// ResultIterator yields drone results one at a time. Next returns
+// iterator.Done once there are no more results.
+type ResultIterator interface {
+	Next(ctx context.Context) (schemas.DroneResult, error)
+}
+
+// sliceIterator yields results already in memory
+type sliceIterator struct {
+	results []schemas.DroneResult
+}
+
+// NewSliceIterator iterates over results already in memory
+func NewSliceIterator(results []schemas.DroneResult) ResultIterator {
+	return &sliceIterator{results: results}
+}
+
+// Next implements ResultIterator
+func (it *sliceIterator) Next(ctx context.Context) (schemas.DroneResult, error) {
+	if len(it.results) == 0 {
+		return schemas.DroneResult{}, iterator.Done
+	}
+	result := it.results[0]
+	it.results = it.results[1:]
+	return result, nil
+}
+
+// channelIterator yields results as they arrive on a channel, such as a
+// ResearchQueue's result channel
+type channelIterator struct {
+	results <-chan schemas.DroneResult
+}
+
+// NewChannelIterator iterates over results as they arrive, until the channel is closed
+func NewChannelIterator(results <-chan schemas.DroneResult) ResultIterator {
+	return &channelIterator{results: results}
+}
+
+// Next implements ResultIterator
+func (it *channelIterator) Next(ctx context.Context) (schemas.DroneResult, error) {
+	select {
+	case <-ctx.Done():
+		return schemas.DroneResult{}, ctx.Err()
+	case result, ok := <-it.results:
+		if !ok {
+			return schemas.DroneResult{}, iterator.Done
+		}
+		return result, nil
+	}
+}
+
+// firestoreIterator yields a session's results from Firestore a document at a time
+type firestoreIterator struct {
+	docs *firestore.DocumentIterator
+	// fields decrypts result data stored sealed with FIELD_KMS_KEY
+	fields *encryption.FieldEncryptor
+}
+
+// NewFirestoreIterator iterates over the results the orchestrator stored for a
+// session in the given sessions collection, decrypting sealed data with fields
+func NewFirestoreIterator(ctx context.Context, client *firestore.Client, collection, sessionID string, fields *encryption.FieldEncryptor) ResultIterator {
+	docs := client.Collection(collection).Doc(sessionID).Collection(resultsCollection).Documents(ctx)
+	return &firestoreIterator{docs: docs, fields: fields}
+}
+
+// Next implements ResultIterator
+func (it *firestoreIterator) Next(ctx context.Context) (schemas.DroneResult, error) {
+	var result schemas.DroneResult
+	doc, err := it.docs.Next()
+	if err != nil {
+		if err == iterator.Done {
+			it.docs.Stop()
+		}
+		return result, err
+	}
+	if err := doc.DataTo(&result); err != nil {
+		return result, fmt.Errorf("failed to decode result %s: %w", doc.Ref.ID, err)
+	}
+	if result.SealedData != nil {
+		if err := it.fields.Open(ctx, result.SealedData, &result.Data); err != nil {
+			return result, fmt.Errorf("failed to decrypt result %s: %w", doc.Ref.ID, err)
+		}
+		result.SealedData = nil
+	}
+	return result, nil
+}
+
+// runningMoments tracks the mean and variance of a stream with Welford's
+// method, and a uniform sample of it to estimate quantiles
+type runningMoments struct {
+	count     int
+	mean      float64
+	m2        float64
+	min       float64
+	max       float64
+	reservoir []float64
+	random    *rand.Rand
+}
+
+// newRunningMoments creates an empty running summary. The sample is seeded so
+// the same stream always gives the same estimates.
+func newRunningMoments() *runningMoments {
+	return &runningMoments{random: rand.New(rand.NewSource(1))}
+}
+
+// add folds a value into the summary
+func (m *runningMoments) add(value float64) {
+	m.count++
+	if m.count == 1 {
+		m.min, m.max = value, value
+	}
+	m.min, m.max = math.Min(m.min, value), math.Max(m.max, value)
+	delta := value - m.mean
+	m.mean += delta / float64(m.count)
+	m.m2 += delta * (value - m.mean)
+
+	if len(m.reservoir) < reservoirSize {
+		m.reservoir = append(m.reservoir, value)
+	} else if i := m.random.Intn(m.count); i < reservoirSize {
+		m.reservoir[i] = value
+	}
+}
+
+// stdDev is the sample standard deviation so far
+func (m *runningMoments) stdDev() float64 {
+	if m.count < 2 {
+		return 0
+	}
+	return math.Sqrt(m.m2 / float64(m.count-1))
+}
+
+// describe summarizes the stream like describe does a sample, with quantiles
+// estimated from the reservoir once the stream outgrows it
+func (m *runningMoments) describe() map[string]interface{} {
+	if m.count == 0 {
+		return nil
+	}
+	sorted := append([]float64(nil), m.reservoir...)
+	sort.Float64s(sorted)
+	summary := map[string]interface{}{
+		"count":   m.count,
+		"mean":    m.mean,
+		"std_dev": m.stdDev(),
+		"min":     m.min,
+		"p25":     percentile(sorted, 0.25),
+		"median":  percentile(sorted, 0.5),
+		"p75":     percentile(sorted, 0.75),
+		"p90":     percentile(sorted, 0.9),
+		"max":     m.max,
+	}
+	if m.count > 1 {
+		margin := confidenceZ * m.stdDev() / math.Sqrt(float64(m.count))
+		summary["mean_ci_low"] = m.mean - margin
+		summary["mean_ci_high"] = m.mean + margin
+	}
+	if m.count > reservoirSize {
+		summary["quantiles_estimated"] = true
+	}
+	return summary
+}
+
+// RunningStatistics accumulates statistics over drone results one at a time,
+// keeping memory bounded however many results a session has
+type RunningStatistics struct {
+	total      int
+	successful int
+	dataPoints int
+	// Processing time of every result, failed ones included
+	timed       int
+	timeSeconds float64
+
+	processingTime *runningMoments
+	dataVolume     *runningMoments
+	// Co-moment of processing time and data volume, for their correlation
+	coMoment float64
+
+	errors  map[string]int
+	sources map[string]int
+}
+
+// NewRunningStatistics creates empty running statistics
+func NewRunningStatistics() *RunningStatistics {
+	return &RunningStatistics{
+		processingTime: newRunningMoments(),
+		dataVolume:     newRunningMoments(),
+		errors:         make(map[string]int),
+		sources:        make(map[string]int),
+	}
+}
+
+// Add folds a result into the statistics
+func (s *RunningStatistics) Add(result schemas.DroneResult) {
+	s.total++
+	if result.ProcessingTime > 0 {
+		s.timed++
+		s.timeSeconds += result.ProcessingTime.Seconds()
+	}
+	if result.Status != "completed" {
+		if result.Error != "" {
+			s.errors[result.Error]++
+		}
+		return
+	}
+	s.successful++
+	s.dataPoints += len(result.Data)
+
+	if sources, ok := result.Data["sources"].([]interface{}); ok {
+		for _, source := range sources {
+			if url, ok := source.(string); ok {
+				if _, tracked := s.sources[url]; tracked || len(s.sources) < maxTrackedSources {
+					s.sources[url]++
+				}
+			}
+		}
+	}
+
+	if result.ProcessingTime > 0 {
+		seconds, points := result.ProcessingTime.Seconds(), float64(len(result.Data))
+		// Update the co-moment with the volume's old mean and the time's new one
+		volumeDelta := points - s.dataVolume.mean
+		s.processingTime.add(seconds)
+		s.dataVolume.add(points)
+		s.coMoment += volumeDelta * (seconds - s.processingTime.mean)
+	}
+}
+
+// Count is how many results have been added
+func (s *RunningStatistics) Count() int {
+	return s.total
+}
+
+// Statistics returns the statistics so far, with the same names as a statistical analysis
+func (s *RunningStatistics) Statistics() map[string]interface{} {
+	stats := map[string]interface{}{
+		"total_results":             s.total,
+		"successful_results":        s.successful,
+		"failed_results":            s.total - s.successful,
+		"success_rate":              0.0,
+		"total_data_points":         s.dataPoints,
+		"avg_data_points_per_drone": 0.0,
+		"avg_processing_time":       0.0,
+		"distinct_sources":          len(s.sources),
+		"distinct_errors":           len(s.errors),
+	}
+	if s.total > 0 {
+		stats["success_rate"] = float64(s.successful) / float64(s.total)
+		stats["error_rate"] = 1 - stats["success_rate"].(float64)
+	}
+	if s.timed > 0 {
+		stats["avg_processing_time"] = s.timeSeconds / float64(s.timed)
+	}
+	if s.successful > 0 {
+		stats["avg_data_points_per_drone"] = float64(s.dataPoints) / float64(s.successful)
+	}
+	low, high := wilsonInterval(s.successful, s.total)
+	stats["success_rate_ci_low"] = low
+	stats["success_rate_ci_high"] = high
+
+	if s.processingTime.count > 0 {
+		stats["processing_time_distribution"] = s.processingTime.describe()
+		stats["data_volume_distribution"] = s.dataVolume.describe()
+	}
+	if n := s.processingTime.count; n >= 3 {
+		sdTime, sdVolume := s.processingTime.stdDev(), s.dataVolume.stdDev()
+		if sdTime > 0 && sdVolume > 0 {
+			stats["time_volume_correlation"] = s.coMoment / float64(n-1) / (sdTime * sdVolume)
+		}
+	}
+	return stats
+}
+
+// Insights describes the statistics so far
+func (s *RunningStatistics) Insights() []string {
+	stats := s.Statistics()
+	insights := []string{
+		fmt.Sprintf("Results analyzed: %d", s.total),
+		fmt.Sprintf("Success rate: %.2f%% (95%% CI %.2f%%-%.2f%%)", stats["success_rate"].(float64)*100,
+			stats["success_rate_ci_low"].(float64)*100, stats["success_rate_ci_high"].(float64)*100),
+		fmt.Sprintf("Average processing time: %.2f seconds", stats["avg_processing_time"].(float64)),
+	}
+	if r, ok := stats["time_volume_correlation"].(float64); ok {
+		insights = append(insights, fmt.Sprintf("Processing time and data volume show %s correlation (r = %.2f)", correlationStrength(r), r))
+	}
+	if top := topCounts(s.sources, 3); len(top) > 0 {
+		insights = append(insights, fmt.Sprintf("Top data sources: %s", strings.Join(top, ", ")))
+	}
+	if top := topCounts(s.errors, 3); len(top) > 0 {
+		insights = append(insights, fmt.Sprintf("Most common errors: %s", strings.Join(top, "; ")))
+	}
+	return insights
+}
+
+// topCounts lists the n most counted keys, most counted first
+func topCounts(counts map[string]int, n int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys[:min(n, len(keys))]
+}
+
+// AnalyzeStream consumes results until the iterator is exhausted, holding only
+// running statistics in memory, so sessions with thousands of results can be analyzed
+func (da *DataAnalyzer) AnalyzeStream(ctx context.Context, results ResultIterator) (*schemas.DataAnalysisResponse, error) {
+	stats := NewRunningStatistics()
+	for {
+		result, err := results.Next(ctx)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read results: %w", err)
+		}
+		stats.Add(result)
+	}
+	if stats.Count() == 0 {
+		return nil, mcperrors.InvalidInput("no data provided for analysis")
+	}
+
+	timeChart := histogram(stats.processingTime.reservoir)
+	timeChart.Title = "Processing Time Distribution (seconds)"
+
+	return &schemas.DataAnalysisResponse{
+		Summary:        fmt.Sprintf("Streaming analysis of %d research results: %d successful completions with %d total data points collected", stats.total, stats.successful, stats.dataPoints),
+		Insights:       stats.Insights(),
+		Statistics:     stats.Statistics(),
+		Visualizations: []schemas.Visualization{timeChart},
+	}, nil
+}
+
+// streamSession analyzes the results the orchestrator stored in Firestore for a session
+func (da *DataAnalyzer) streamSession(ctx context.Context, sessionID string) (*schemas.DataAnalysisResponse, error) {
+	client, err := firestore.NewClient(ctx, gcp.ProjectID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Firestore client: %w", err)
+	}
+	defer client.Close()
+	fields, err := encryption.NewFieldEncryptor(encryption.LoadConfig().FieldKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FIELD_KMS_KEY: %w", err)
+	}
+
+	return da.AnalyzeStream(ctx, NewFirestoreIterator(ctx, client, da.SessionsCollection, sessionID, fields))
+}
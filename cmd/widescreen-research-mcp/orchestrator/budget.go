@@ -0,0 +1,182 @@
+package orchestrator
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// CostRates are the unit prices used to estimate what a session has spent
+type CostRates struct {
+	// VCPUSecondUSD is the Cloud Run cost of one vCPU allocated for a second
+	VCPUSecondUSD float64
+	// GiBSecondUSD is the Cloud Run cost of 1 GiB of memory allocated for a second
+	GiBSecondUSD float64
+	// LLMPer1KTokensUSD is the cost of 1,000 LLM tokens
+	LLMPer1KTokensUSD float64
+	// ExaCallUSD is the cost of one Exa API call
+	ExaCallUSD float64
+}
+
+// LoadCostRates reads the cost rates from the environment
+func LoadCostRates() CostRates {
+	return CostRates{
+		VCPUSecondUSD:     envFloat("COST_VCPU_SECOND_USD", 0.000024),
+		GiBSecondUSD:      envFloat("COST_GIB_SECOND_USD", 0.0000025),
+		LLMPer1KTokensUSD: envFloat("COST_LLM_1K_TOKENS_USD", 0.015),
+		ExaCallUSD:        envFloat("COST_EXA_CALL_USD", 0.005),
+	}
+}
+
+// envFloat reads a non-negative float from the environment, falling back to defaultValue
+func envFloat(key string, defaultValue float64) float64 {
+	if v, err := strconv.ParseFloat(getEnvOrDefault(key, ""), 64); err == nil && v >= 0 {
+		return v
+	}
+	return defaultValue
+}
+
+// sessionBudget tracks a session's usage and its spending limit
+type sessionBudget struct {
+	rates CostRates
+	// limit is the session's max_cost_usd; 0 means unlimited
+	limit float64
+	// droneVCPU and droneGiB are the resources allocated to each of the session's drones
+	droneVCPU float64
+	droneGiB  float64
+
+	mu        sync.Mutex
+	llmTokens int
+	exaCalls  int
+}
+
+// newSessionBudget creates a budget with the given limit for drones of the given size
+func newSessionBudget(rates CostRates, limit, droneVCPU, droneGiB float64) *sessionBudget {
+	return &sessionBudget{rates: rates, limit: limit, droneVCPU: droneVCPU, droneGiB: droneGiB}
+}
+
+// addLLMTokens records LLM tokens used by the session
+func (b *sessionBudget) addLLMTokens(tokens int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.llmTokens += tokens
+}
+
+// addExaCalls records Exa API calls made for the session
+func (b *sessionBudget) addExaCalls(calls int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.exaCalls += calls
+}
+
+// spend estimates the session's cost so far. Drone runtime is counted from when
+// a drone started or the session started, whichever is later, as if drones never
+// scaled to zero, so the estimate errs high while Cloud Monitoring data lags.
+func (b *sessionBudget) spend(drones []*DroneInfo, sessionStart, now time.Time) schemas.CostBreakdown {
+	var droneSeconds float64
+	for _, drone := range drones {
+		start := drone.StartTime
+		if start.Before(sessionStart) {
+			start = sessionStart
+		}
+		if now.After(start) {
+			droneSeconds += now.Sub(start).Seconds()
+		}
+	}
+
+	b.mu.Lock()
+	llmTokens, exaCalls := b.llmTokens, b.exaCalls
+	b.mu.Unlock()
+
+	cost := schemas.CostBreakdown{
+		DroneRuntimeUSD: droneSeconds * b.dronePerSecondUSD(),
+		LLMTokensUSD:    float64(llmTokens) / 1000 * b.rates.LLMPer1KTokensUSD,
+		ExaCallsUSD:     float64(exaCalls) * b.rates.ExaCallUSD,
+		LLMTokens:       llmTokens,
+		ExaCalls:        exaCalls,
+	}
+	cost.TotalUSD = cost.DroneRuntimeUSD + cost.LLMTokensUSD + cost.ExaCallsUSD
+	return cost
+}
+
+// dronePerSecondUSD is the cost of one of the session's drones running for a second
+func (b *sessionBudget) dronePerSecondUSD() float64 {
+	return b.droneVCPU*b.rates.VCPUSecondUSD + b.droneGiB*b.rates.GiBSecondUSD
+}
+
+// exceeded reports whether cost has reached the budget limit
+func (b *sessionBudget) exceeded(cost schemas.CostBreakdown) bool {
+	return b.limit > 0 && cost.TotalUSD >= b.limit
+}
+
+// exaCallsForResult is the number of Exa calls a drone reported for its result,
+// assuming one search when the drone does not report it
+func exaCallsForResult(result schemas.DroneResult) int {
+	if calls, ok := result.Data["exa_calls"].(float64); ok && calls >= 0 {
+		return int(calls)
+	}
+	return 1
+}
+
+// sessionSpend estimates what a session has spent so far
+func (o *Orchestrator) sessionSpend(session *ResearchSession) schemas.CostBreakdown {
+	o.mu.RLock()
+	drones := make([]*DroneInfo, 0, len(session.Drones))
+	for _, drone := range session.Drones {
+		drones = append(drones, drone)
+	}
+	o.mu.RUnlock()
+
+	return session.Budget.spend(drones, session.StartTime, time.Now())
+}
+
+// enforceBudget stops a session that has reached its budget, returning true if it has
+func (o *Orchestrator) enforceBudget(session *ResearchSession) bool {
+	if o.budgetExceeded(session) {
+		return true
+	}
+
+	cost := o.sessionSpend(session)
+	if !session.Budget.exceeded(cost) {
+		return false
+	}
+
+	log.Printf("Session %s reached its $%.2f budget after spending $%.2f, stopping", session.Config.SessionID, session.Budget.limit, cost.TotalUSD)
+	if o.setSessionStatus(session, "budget_exceeded") {
+		session.cancel()
+	}
+	return true
+}
+
+// budgetExceeded reports whether the session was stopped for going over budget
+func (o *Orchestrator) budgetExceeded(session *ResearchSession) bool {
+	return o.sessionStatus(session) == "budget_exceeded"
+}
+
+// abortOverBudget reports what an over-budget session spent; its drones are
+// deleted as OrchestrateResearch returns
+func (o *Orchestrator) abortOverBudget(ctx context.Context, session *ResearchSession) *schemas.ResearchResult {
+	metrics := o.calculateMetrics(context.WithoutCancel(ctx), session)
+	log.Printf("Aborted session %s over budget: spent $%.2f (drones $%.2f, LLM $%.2f, Exa $%.2f)",
+		session.Config.SessionID, metrics.Cost.TotalUSD, metrics.Cost.DroneRuntimeUSD, metrics.Cost.LLMTokensUSD, metrics.Cost.ExaCallsUSD)
+
+	if err := o.updateProgressFile(session); err != nil {
+		log.Printf("Warning: failed to update progress file for session %s: %v", session.Config.SessionID, err)
+	}
+
+	return &schemas.ResearchResult{
+		SessionID:   session.Config.SessionID,
+		Status:      "budget_exceeded",
+		Metrics:     metrics,
+		CompletedAt: time.Now(),
+	}
+}
+
+// estimateTokens approximates the LLM token count of text at four characters per token
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
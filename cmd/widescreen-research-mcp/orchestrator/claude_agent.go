@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
@@ -14,15 +16,25 @@ type ClaudeAgent struct {
 	// In a real implementation, this would use the Claude SDK
 	// For now, we'll create a mock implementation
 	apiKey string
+	// model is the Claude model to use; empty uses the API's default
+	model string
 }
 
 // NewClaudeAgent creates a new Claude agent
 func NewClaudeAgent() *ClaudeAgent {
 	return &ClaudeAgent{
 		apiKey: getEnvOrDefault("CLAUDE_API_KEY", ""),
+		model:  getEnvOrDefault("CLAUDE_MODEL", ""),
 	}
 }
 
+// WithModel returns a copy of the agent that uses model
+func (a *ClaudeAgent) WithModel(model string) *ClaudeAgent {
+	copied := *a
+	copied.model = model
+	return &copied
+}
+
 // Initialize initializes the Claude agent
 func (a *ClaudeAgent) Initialize(ctx context.Context) error {
 	if a.apiKey == "" {
@@ -32,9 +44,18 @@ func (a *ClaudeAgent) Initialize(ctx context.Context) error {
 }
 
 // GenerateSubQueries uses the AI to break a high-level topic into specific sub-queries.
-func (a *ClaudeAgent) GenerateSubQueries(ctx context.Context, topic string, numQueries int) ([]string, error) {
+// known holds findings of earlier sessions on the topic; when given, the
+// sub-queries look for gaps in and updates to them rather than repeating them.
+func (a *ClaudeAgent) GenerateSubQueries(ctx context.Context, topic string, numQueries int, known []string) ([]string, error) {
 	// In a real implementation, this would use Claude. For now, mock data.
 	log.Printf("Generating %d mock sub-queries for topic: %s", numQueries, topic)
+	if len(known) > 0 {
+		var queries []string
+		for i := 1; i <= numQueries; i++ {
+			queries = append(queries, fmt.Sprintf("Sub-query %d for %s: gaps and updates beyond %d known findings", i, topic, len(known)))
+		}
+		return queries, nil
+	}
 	if topic == "Top 3 AI Companies" {
 		return []string{
 			"Detailed analysis of OpenAI's business model, products, and recent controversies.",
@@ -51,6 +72,24 @@ func (a *ClaudeAgent) GenerateSubQueries(ctx context.Context, topic string, numQ
 	return queries, nil
 }
 
+// GenerateFollowUpQueries turns the coverage gaps of a session's knowledge
+// graph into sub-queries for a follow-up session, one per gap
+func (a *ClaudeAgent) GenerateFollowUpQueries(ctx context.Context, topic string, gaps []schemas.CoverageGap) ([]string, error) {
+	// In a real implementation, this would use Claude. For now, mock data.
+	queries := make([]string, 0, len(gaps))
+	for _, gap := range gaps {
+		switch gap.Reason {
+		case gapNotCovered:
+			queries = append(queries, fmt.Sprintf("What is the role of %s in %s?", gap.Entity, topic))
+		case gapSingleSource:
+			queries = append(queries, fmt.Sprintf("What do independent sources report about %s in relation to %s?", gap.Entity, topic))
+		default:
+			queries = append(queries, fmt.Sprintf("How is %s connected to the other organizations, people and products in %s?", gap.Entity, topic))
+		}
+	}
+	return queries, nil
+}
+
 // GenerateReport generates a research report from collected data
 func (a *ClaudeAgent) GenerateReport(ctx context.Context, config *schemas.ResearchConfig, results []schemas.DroneResult, analysis *DataAnalysis) (*schemas.ResearchReport, error) {
 	// Process results into a structured report
@@ -68,9 +107,19 @@ func (a *ClaudeAgent) GenerateReport(ctx context.Context, config *schemas.Resear
 			DataPoints:      len(results),
 			Sources:         a.extractSources(results),
 			Metrics:         analysis.Metrics,
+			Model:           a.model,
 		},
 	}
 
+	if len(analysis.Summaries) > 0 {
+		// Report from the summaries; the raw drone data is in the per-drone result files
+		report.Data = map[string]interface{}{
+			"summaries":          analysis.Summaries,
+			"total_results":      len(results),
+			"successful_results": analysis.Metrics.DronesCompleted,
+		}
+	}
+
 	return report, nil
 }
 
@@ -103,20 +152,186 @@ func (a *ClaudeAgent) generateReportSections(config *schemas.ResearchConfig, res
 			Content:  a.generateKeyFindings(results, analysis),
 			Insights: analysis.TopInsights,
 		},
+	}
+	// Present findings by topic after the overview
+	for _, cluster := range analysis.Clusters {
+		sections = append(sections, a.generateTopicSection(cluster))
+	}
+	sections = append(sections, []schemas.ReportSection{
 		{
-			Title:   "Data Analysis",
-			Content: a.generateDataAnalysis(analysis),
-			Data:    analysis.Statistics,
+			Title:          "Data Analysis",
+			Content:        a.generateDataAnalysis(analysis),
+			Data:           analysis.Statistics,
+			Visualizations: a.generateReportCharts(results, analysis),
 		},
 		{
 			Title:   "Conclusions",
 			Content: a.generateConclusions(config, analysis),
 		},
+	}...)
+
+	if len(analysis.Contradictions) > 0 {
+		sections = append(sections, a.generateConflictingEvidenceSection(analysis.Contradictions))
+	}
+	if len(analysis.Summaries) > 0 {
+		sections = append(sections, a.generateSynthesisSection(analysis.Summaries))
+	}
+
+	if len(analysis.SourceScores) > 0 {
+		sections = append(sections, a.generateSourceAppendix(analysis.SourceScores))
 	}
 
 	return sections
 }
 
+// maxChartBars is the most drones charted with a bar each
+const maxChartBars = 30
+
+// generateReportCharts charts the drones' outcomes and output for the Data Analysis section
+func (a *ClaudeAgent) generateReportCharts(results []schemas.DroneResult, analysis *DataAnalysis) []schemas.Visualization {
+	visualizations := []schemas.Visualization{
+		{
+			Type:  "pie_chart",
+			Title: "Drone Outcomes",
+			Data: map[string]interface{}{
+				"labels": []string{"Completed", "Failed"},
+				"values": []int{analysis.Metrics.DronesCompleted, analysis.Metrics.DronesFailed},
+			},
+		},
+	}
+
+	// Data points per drone, while there are few enough drones for a bar each
+	var drones []string
+	var points []int
+	completions := make(map[string]int)
+	for _, result := range results {
+		if result.Status != "completed" {
+			continue
+		}
+		drones = append(drones, result.DroneID)
+		points = append(points, len(result.Data))
+		completions[result.CompletedAt.UTC().Format("15:04")]++
+	}
+	if len(drones) > 0 && len(drones) <= maxChartBars {
+		visualizations = append(visualizations, schemas.Visualization{
+			Type:  "bar_chart",
+			Title: "Data Points per Drone",
+			Data:  map[string]interface{}{"labels": drones, "values": points},
+		})
+	}
+
+	// Completions per minute show whether drones finished together or straggled
+	if len(completions) > 1 {
+		minutes := make([]string, 0, len(completions))
+		for minute := range completions {
+			minutes = append(minutes, minute)
+		}
+		sort.Strings(minutes)
+		counts := make([]int, len(minutes))
+		for i, minute := range minutes {
+			counts[i] = completions[minute]
+		}
+		visualizations = append(visualizations, schemas.Visualization{
+			Type:  "time_series",
+			Title: "Drone Completions per Minute (UTC)",
+			Data:  map[string]interface{}{"timestamps": minutes, "values": counts},
+		})
+	}
+
+	if len(analysis.Clusters) > 0 {
+		labels := make([]string, len(analysis.Clusters))
+		sizes := make([]int, len(analysis.Clusters))
+		for i, cluster := range analysis.Clusters {
+			labels[i] = cluster.Label
+			sizes[i] = len(cluster.Findings)
+		}
+		visualizations = append(visualizations, schemas.Visualization{
+			Type:  "bar_chart",
+			Title: "Findings by Topic",
+			Data:  map[string]interface{}{"labels": labels, "values": sizes},
+		})
+	}
+	return visualizations
+}
+
+// generateTopicSection lists a topic cluster's findings, most credible first
+func (a *ClaudeAgent) generateTopicSection(cluster schemas.TopicCluster) schemas.ReportSection {
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("%d findings about %s:\n\n", len(cluster.Findings), strings.Join(cluster.Keywords, ", ")))
+	for _, finding := range cluster.Findings {
+		content.WriteString(fmt.Sprintf("- %s (reported by %s", finding.Claim, strings.Join(finding.Drones, ", ")))
+		if len(finding.Sources) > 0 {
+			content.WriteString(fmt.Sprintf("; sources: %s", strings.Join(finding.Sources, ", ")))
+		}
+		content.WriteString(")\n")
+	}
+	return schemas.ReportSection{
+		Title:   cluster.Label,
+		Content: content.String(),
+		Data:    map[string]interface{}{"keywords": cluster.Keywords, "findings": cluster.Findings},
+	}
+}
+
+// generateConflictingEvidenceSection lists the claims drones disagree on, with who reported each version
+func (a *ClaudeAgent) generateConflictingEvidenceSection(contradictions []schemas.Contradiction) schemas.ReportSection {
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("Drones reported %d claims inconsistently; the confidence of this report is lowered accordingly.\n\n", len(contradictions)))
+	for _, contradiction := range contradictions {
+		content.WriteString(fmt.Sprintf("%s:\n", contradiction.Topic))
+		for _, claim := range contradiction.Claims {
+			content.WriteString(fmt.Sprintf("- %q, reported by %s", claim.Claim, strings.Join(claim.Drones, ", ")))
+			if len(claim.Sources) > 0 {
+				content.WriteString(fmt.Sprintf(" (sources: %s)", strings.Join(claim.Sources, ", ")))
+			}
+			content.WriteString("\n")
+		}
+	}
+	return schemas.ReportSection{
+		Title:   "Conflicting Evidence",
+		Content: content.String(),
+		Data:    map[string]interface{}{"contradictions": contradictions},
+	}
+}
+
+// generateSourceAppendix lists every cited source with its credibility score
+func (a *ClaudeAgent) generateSourceAppendix(scores []schemas.SourceScore) schemas.ReportSection {
+	var content strings.Builder
+	content.WriteString("Findings are weighted by the credibility of their sources, scored from their domain and age.\n\n")
+	for _, score := range scores {
+		content.WriteString(fmt.Sprintf("- %.2f %s", score.Score, score.URL))
+		if len(score.Reasons) > 0 {
+			content.WriteString(fmt.Sprintf(" (%s)", strings.Join(score.Reasons, ", ")))
+		}
+		content.WriteString(fmt.Sprintf(", cited by %d drones\n", score.Citations))
+	}
+	return schemas.ReportSection{
+		Title:   "Appendix: Source Credibility",
+		Content: content.String(),
+		Data:    map[string]interface{}{"sources": scores},
+	}
+}
+
+// generateSynthesisSection presents the reduce summaries the report was written from
+func (a *ClaudeAgent) generateSynthesisSection(summaries []schemas.ResultSummary) schemas.ReportSection {
+	var content strings.Builder
+	var insights []string
+	seen := make(map[string]bool)
+	for _, summary := range summaries {
+		content.WriteString(fmt.Sprintf("- %s (from %d inputs): %s\n", summary.ID, len(summary.Inputs), summary.Summary))
+		for _, finding := range summary.KeyFindings {
+			if !seen[finding] {
+				seen[finding] = true
+				insights = append(insights, finding)
+			}
+		}
+	}
+	return schemas.ReportSection{
+		Title:    "Synthesis",
+		Content:  content.String(),
+		Insights: insights,
+	}
+}
+
 // Helper methods for report generation
 
 func (a *ClaudeAgent) generateIntroduction(config *schemas.ResearchConfig) string {
@@ -138,6 +353,21 @@ func (a *ClaudeAgent) generateKeyFindings(results []schemas.DroneResult, analysi
 	
 	findings += fmt.Sprintf("- Successfully collected data from %d out of %d drones\n", successCount, len(results))
 	findings += fmt.Sprintf("- Identified %d key patterns across the dataset\n", len(analysis.Patterns))
+
+	if len(analysis.Findings) > 0 {
+		findings += fmt.Sprintf("- Merged the drones' findings into %d distinct findings\n", len(analysis.Findings))
+		// Clustered findings are listed in their topic sections instead
+		if len(analysis.Clusters) > 0 {
+			return findings + fmt.Sprintf("- Grouped the findings into %d topics, covered in the sections below\n", len(analysis.Clusters))
+		}
+		findings += "\n"
+		for i, finding := range analysis.Findings {
+			if i >= 10 {
+				break
+			}
+			findings += fmt.Sprintf("- %s (reported by %d drones, %d sources)\n", finding.Claim, len(finding.Drones), len(finding.Sources))
+		}
+	}
 	
 	return findings
 }
@@ -155,10 +385,14 @@ func (a *ClaudeAgent) generateConclusions(config *schemas.ResearchConfig, analys
 }
 
 func (a *ClaudeAgent) generateMethodologySection(config *schemas.ResearchConfig) string {
-	return fmt.Sprintf("This research employed a distributed approach using %d parallel research drones. "+
+	methodology := fmt.Sprintf("This research employed a distributed approach using %d parallel research drones. "+
 		"Each drone was tasked with specific aspects of the research topic '%s'. "+
 		"The %s-depth methodology ensured comprehensive coverage while maintaining efficiency.",
 		config.ResearcherCount, config.Topic, config.ResearchDepth)
+	if config.TemplateID != "" {
+		methodology += fmt.Sprintf(" The research followed the %s template.", config.TemplateID)
+	}
+	return methodology
 }
 
 func (a *ClaudeAgent) aggregateData(results []schemas.DroneResult) map[string]interface{} {
@@ -200,6 +434,43 @@ func (a *ClaudeAgent) extractSources(results []schemas.DroneResult) []string {
 	return sources
 }
 
+// SummarizeBatch merges a batch of drone results or lower-level summaries into one summary
+func (a *ClaudeAgent) SummarizeBatch(ctx context.Context, topic string, batch []schemas.ResultSummary) (*schemas.ResultSummary, error) {
+	// Mock implementation: a real agent would condense the batch with Claude
+	summary := &schemas.ResultSummary{}
+	findings := make(map[string]bool)
+	sources := make(map[string]bool)
+	var texts []string
+	for _, item := range batch {
+		summary.Inputs = append(summary.Inputs, item.ID)
+		if item.Summary != "" {
+			texts = append(texts, item.Summary)
+		}
+		for _, finding := range item.KeyFindings {
+			if !findings[finding] && len(summary.KeyFindings) < 10 {
+				findings[finding] = true
+				summary.KeyFindings = append(summary.KeyFindings, finding)
+			}
+		}
+		for _, source := range item.Sources {
+			if !sources[source] {
+				sources[source] = true
+				summary.Sources = append(summary.Sources, source)
+			}
+		}
+	}
+
+	summary.Summary = fmt.Sprintf("Synthesis of %d inputs on '%s' drawing on %d sources.", len(batch), topic, len(summary.Sources))
+	if len(texts) > 0 {
+		summary.Summary += " " + strings.Join(texts, " ")
+	}
+	// Keep each level's output bounded, as the real agent's would be
+	if runes := []rune(summary.Summary); len(runes) > 2000 {
+		summary.Summary = string(runes[:2000]) + "..."
+	}
+	return summary, nil
+}
+
 // AnalyzeSequentialThinking performs sequential thinking analysis
 func (a *ClaudeAgent) AnalyzeSequentialThinking(ctx context.Context, problem string, context string) (*schemas.SequentialThinkingResponse, error) {
 	// Mock implementation of sequential thinking
@@ -245,4 +516,16 @@ type DataAnalysis struct {
 	Duration          time.Duration
 	AverageConfidence float64
 	Metrics           schemas.ResearchMetrics
+	// Findings are the drones' findings with duplicates merged, most widely reported first
+	Findings []schemas.Finding
+	// SourceScores rate the sources drones cited, most credible first
+	SourceScores []schemas.SourceScore
+	// Contradictions are the findings drones disagree on
+	Contradictions []schemas.Contradiction
+	// Summaries are the top-level reduce summaries, when there were too many results to report from directly
+	Summaries []schemas.ResultSummary
+	// Clusters group the findings by topic, largest first; the report has a section per cluster
+	Clusters []schemas.TopicCluster
+	// Graph links the entities the findings name, and finds where the topic is covered thinly
+	Graph *schemas.KnowledgeGraph
 }
\ No newline at end of file
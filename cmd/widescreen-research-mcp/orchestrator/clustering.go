@@ -0,0 +1,246 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+const (
+	// minClusterFindings is the fewest findings worth clustering
+	minClusterFindings = 4
+	// clusterIterations caps the k-means refinement passes
+	clusterIterations = 25
+	// clusterLabelWords is how many keywords make up a cluster's label
+	clusterLabelWords = 3
+)
+
+// stopWords are too common to say what a claim is about
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "been": true,
+	"but": true, "by": true, "can": true, "for": true, "from": true, "has": true, "have": true, "in": true,
+	"into": true, "is": true, "it": true, "its": true, "more": true, "most": true, "of": true, "on": true,
+	"or": true, "over": true, "than": true, "that": true, "the": true, "their": true, "this": true, "to": true,
+	"was": true, "were": true, "which": true, "will": true, "with": true, "while": true, "also": true,
+	"about": true, "after": true, "all": true, "such": true, "these": true, "they": true, "up": true,
+}
+
+// clusterSettings reads the most topic clusters a report is split into
+func clusterSettings() int {
+	maxClusters, err := strconv.Atoi(getEnvOrDefault("CLUSTER_MAX", "8"))
+	if err != nil || maxClusters < 1 {
+		maxClusters = 8
+	}
+	return maxClusters
+}
+
+// clusterFindings embeds the findings' claims and groups them into topics with
+// k-means on cosine similarity. It returns nil when there are too few findings
+// to be worth splitting up.
+func clusterFindings(ctx context.Context, embedder Embedder, findings []schemas.Finding, maxClusters int) ([]schemas.TopicCluster, error) {
+	if len(findings) < minClusterFindings {
+		return nil, nil
+	}
+
+	claims := make([]string, len(findings))
+	for i, finding := range findings {
+		claims[i] = finding.Claim
+	}
+	vectors, err := embedder.Embed(ctx, claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed findings: %w", err)
+	}
+	if len(vectors) != len(findings) {
+		return nil, fmt.Errorf("got %d embeddings for %d findings", len(vectors), len(findings))
+	}
+	for _, vector := range vectors {
+		normalizeVector(vector)
+	}
+
+	k := int(math.Round(math.Sqrt(float64(len(findings)) / 2)))
+	k = max(2, min(k, maxClusters))
+	assignments := kMeans(vectors, k)
+
+	groups := make([][]int, k)
+	for i, cluster := range assignments {
+		groups[cluster] = append(groups[cluster], i)
+	}
+
+	documentFrequency := termCounts(claims)
+	var clusters []schemas.TopicCluster
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		cluster := schemas.TopicCluster{}
+		groupClaims := make([]string, len(group))
+		for i, index := range group {
+			cluster.Findings = append(cluster.Findings, findings[index])
+			groupClaims[i] = claims[index]
+		}
+		cluster.Keywords = clusterKeywords(termCounts(groupClaims), documentFrequency, len(group), len(claims))
+		cluster.Label = clusterLabel(cluster.Keywords)
+		clusters = append(clusters, cluster)
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return len(clusters[i].Findings) > len(clusters[j].Findings)
+	})
+	return clusters, nil
+}
+
+// kMeans assigns each unit vector to one of k clusters. Centroids start at
+// the first vector and then each vector farthest from those chosen, so the
+// result is deterministic.
+func kMeans(vectors [][]float64, k int) []int {
+	k = min(k, len(vectors))
+	centroids := [][]float64{append([]float64(nil), vectors[0]...)}
+	for len(centroids) < k {
+		farthest, lowest := 0, math.Inf(1)
+		for i, vector := range vectors {
+			if similarity := nearestSimilarity(vector, centroids); similarity < lowest {
+				farthest, lowest = i, similarity
+			}
+		}
+		centroids = append(centroids, append([]float64(nil), vectors[farthest]...))
+	}
+
+	assignments := make([]int, len(vectors))
+	for iteration := 0; iteration < clusterIterations; iteration++ {
+		changed := false
+		for i, vector := range vectors {
+			best, bestSimilarity := 0, math.Inf(-1)
+			for c, centroid := range centroids {
+				if similarity := dot(vector, centroid); similarity > bestSimilarity {
+					best, bestSimilarity = c, similarity
+				}
+			}
+			if iteration == 0 || assignments[i] != best {
+				assignments[i] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		for c := range centroids {
+			sum := make([]float64, len(centroids[c]))
+			members := 0
+			for i, vector := range vectors {
+				if assignments[i] != c {
+					continue
+				}
+				members++
+				for d, v := range vector {
+					sum[d] += v
+				}
+			}
+			// Keep the old centroid of a cluster that lost all its members
+			if members > 0 {
+				normalizeVector(sum)
+				centroids[c] = sum
+			}
+		}
+	}
+	return assignments
+}
+
+// nearestSimilarity is the similarity of a vector to its closest centroid
+func nearestSimilarity(vector []float64, centroids [][]float64) float64 {
+	best := math.Inf(-1)
+	for _, centroid := range centroids {
+		best = math.Max(best, dot(vector, centroid))
+	}
+	return best
+}
+
+// dot is the dot product of two vectors, their cosine similarity when both are unit length
+func dot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a[:min(len(a), len(b))] {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// contentWords splits text into lowercase words, dropping stop words and numbers
+func contentWords(text string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	}) {
+		word = strings.Trim(word, "-")
+		if len(word) < 3 || stopWords[word] || !strings.ContainsFunc(word, unicode.IsLetter) {
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// termCounts counts how many of the texts each content word appears in
+func termCounts(texts []string) map[string]int {
+	counts := make(map[string]int)
+	for _, text := range texts {
+		seen := make(map[string]bool)
+		for _, word := range contentWords(text) {
+			if !seen[word] {
+				seen[word] = true
+				counts[word]++
+			}
+		}
+	}
+	return counts
+}
+
+// clusterKeywords ranks the words a cluster's claims use more often than the
+// findings as a whole, so its label says what sets it apart
+func clusterKeywords(clusterCounts, allCounts map[string]int, clusterSize, total int) []string {
+	type keyword struct {
+		word  string
+		score float64
+	}
+	var keywords []keyword
+	for word, count := range clusterCounts {
+		inCluster := float64(count) / float64(clusterSize)
+		overall := float64(allCounts[word]) / float64(total)
+		// Favour words shared by several claims over one claim's words
+		score := inCluster * (inCluster - overall + 1.0/float64(total))
+		if count > 1 || clusterSize == 1 {
+			keywords = append(keywords, keyword{word, score})
+		}
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].score != keywords[j].score {
+			return keywords[i].score > keywords[j].score
+		}
+		return keywords[i].word < keywords[j].word
+	})
+
+	words := make([]string, 0, 5)
+	for _, keyword := range keywords[:min(5, len(keywords))] {
+		words = append(words, keyword.word)
+	}
+	return words
+}
+
+// clusterLabel titles a cluster with its leading keywords
+func clusterLabel(keywords []string) string {
+	if len(keywords) == 0 {
+		return "Other Findings"
+	}
+	words := make([]string, 0, clusterLabelWords)
+	for _, keyword := range keywords[:min(clusterLabelWords, len(keywords))] {
+		runes := []rune(keyword)
+		runes[0] = unicode.ToUpper(runes[0])
+		words = append(words, string(runes))
+	}
+	return strings.Join(words, ", ")
+}
@@ -0,0 +1,150 @@
+package orchestrator
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+var (
+	// figurePattern matches a figure in a claim, with its currency sign and scale
+	figurePattern = regexp.MustCompile(`(?i)\$?\d(?:[\d,]*\d)?(\.\d+)?(\s*%|\s*(?:percent|trillion|billion|million|thousand|bn|tn|m|k)\b)?`)
+
+	// yearPattern matches figures that are years, which say when rather than how much
+	yearPattern = regexp.MustCompile(`^(19|20)\d\d$`)
+
+	// negations flip the meaning of an otherwise matching claim
+	negations = map[string]bool{"not": true, "no": true, "never": true, "isn't": true, "wasn't": true, "didn't": true, "doesn't": true}
+
+	// figureScales are the multipliers of the scale words figures may carry
+	figureScales = map[string]float64{
+		"thousand": 1e3, "k": 1e3,
+		"million": 1e6, "m": 1e6,
+		"billion": 1e9, "bn": 1e9,
+		"trillion": 1e12, "tn": 1e12,
+	}
+)
+
+// claimShape is a finding reduced to what it says about what: the claim with
+// its figures blanked out and negations removed, and what was removed
+type claimShape struct {
+	topic   string
+	figures string
+	negated bool
+}
+
+// detectContradictions finds groups of findings from different drones that
+// make the same claim with different figures, or where one denies what
+// another asserts, e.g. two revenue numbers for the same company and year
+func detectContradictions(findings []schemas.Finding) []schemas.Contradiction {
+	groups := make(map[string][]int)
+	shapes := make([]claimShape, len(findings))
+	var topics []string
+	for i, finding := range findings {
+		shapes[i] = shapeClaim(finding.Claim)
+		// Claims this short match too much to be the same statement
+		if len(strings.Fields(shapes[i].topic)) < 3 {
+			continue
+		}
+		if groups[shapes[i].topic] == nil {
+			topics = append(topics, shapes[i].topic)
+		}
+		groups[shapes[i].topic] = append(groups[shapes[i].topic], i)
+	}
+	sort.Strings(topics)
+
+	var contradictions []schemas.Contradiction
+	for _, topic := range topics {
+		members := groups[topic]
+		if len(members) < 2 {
+			continue
+		}
+
+		versions := make(map[string]bool)
+		drones := make(map[string]bool)
+		for _, i := range members {
+			versions[shapes[i].figures+"|"+strconv.FormatBool(shapes[i].negated)] = true
+			for _, drone := range findings[i].Drones {
+				drones[drone] = true
+			}
+		}
+		// A drone disagreeing with itself is left to the drone's own confidence
+		if len(versions) < 2 || len(drones) < 2 {
+			continue
+		}
+
+		contradiction := schemas.Contradiction{Topic: topic}
+		for _, i := range members {
+			contradiction.Claims = append(contradiction.Claims, findings[i])
+		}
+		contradictions = append(contradictions, contradiction)
+	}
+	return contradictions
+}
+
+// shapeClaim splits a claim into its topic and the figures and negation it carries
+func shapeClaim(claim string) claimShape {
+	var shape claimShape
+	var figures []string
+	blanked := figurePattern.ReplaceAllStringFunc(claim, func(figure string) string {
+		if yearPattern.MatchString(figure) {
+			return figure
+		}
+		figures = append(figures, figureValue(figure))
+		return " # "
+	})
+	shape.figures = strings.Join(figures, ",")
+
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(blanked), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '#' && r != '\''
+	}) {
+		if negations[word] {
+			shape.negated = !shape.negated
+			continue
+		}
+		words = append(words, word)
+	}
+	shape.topic = strings.Join(words, " ")
+	return shape
+}
+
+// figureValue reads a figure as a number with its scale applied, so "$4.2 billion"
+// and "$4,200 million" compare equal. Percentages keep a % suffix.
+func figureValue(figure string) string {
+	figure = strings.ToLower(strings.TrimSpace(figure))
+	match := figurePattern.FindStringSubmatch(figure)
+	unit := strings.TrimSpace(match[2])
+
+	digits := strings.TrimSpace(strings.TrimSuffix(figure, unit))
+	digits = strings.NewReplacer("$", "", ",", "").Replace(digits)
+	value, err := strconv.ParseFloat(digits, 64)
+	if err != nil {
+		return figure
+	}
+
+	switch unit {
+	case "%", "percent":
+		return strconv.FormatFloat(value, 'g', 6, 64) + "%"
+	case "":
+		return strconv.FormatFloat(value, 'g', 6, 64)
+	}
+	return strconv.FormatFloat(value*figureScales[unit], 'g', 6, 64)
+}
+
+// contradictionPenalty is how much contradictions lower a session's
+// confidence: half the share of findings involved in one
+func contradictionPenalty(contradictions []schemas.Contradiction, findings int) float64 {
+	if findings == 0 {
+		return 0
+	}
+	involved := 0
+	for _, contradiction := range contradictions {
+		involved += len(contradiction.Claims)
+	}
+	return 0.5 * float64(involved) / float64(findings)
+}
@@ -0,0 +1,245 @@
+package orchestrator
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// unsourcedCredibility is the credibility of findings no source backs
+const unsourcedCredibility = 0.3
+
+// defaultTLDScores are the credibility of domains by top-level domain. Second
+// level domains such as gov.uk and ac.jp score as their first label.
+var defaultTLDScores = map[string]float64{
+	"gov": 0.9, "mil": 0.9, "edu": 0.85, "ac": 0.85, "int": 0.85,
+	"org": 0.65, "com": 0.55, "net": 0.5, "io": 0.5,
+	"biz": 0.3, "info": 0.35, "xyz": 0.25, "top": 0.25, "click": 0.2,
+}
+
+// urlDatePattern matches the /2024/05/ style dates in article URLs
+var urlDatePattern = regexp.MustCompile(`/((?:19|20)\d\d)/(0[1-9]|1[0-2])/`)
+
+// SourceScoringConfig configures how credible sources are judged
+type SourceScoringConfig struct {
+	// AllowDomains are trusted domains, scored 1 along with their subdomains
+	AllowDomains []string
+	// DenyDomains are untrusted domains, scored 0 along with their subdomains
+	DenyDomains []string
+	// TLDScores override or add to the default scores by top-level domain
+	TLDScores map[string]float64
+	// DefaultScore is the score of domains no other rule covers
+	DefaultScore float64
+	// StaleAfter is the age after which a source's score is reduced, down to half for very old sources
+	StaleAfter time.Duration
+}
+
+// LoadSourceScoringConfig reads the source scoring configuration from the environment
+func LoadSourceScoringConfig() SourceScoringConfig {
+	config := SourceScoringConfig{
+		AllowDomains: splitList(getEnvOrDefault("SOURCE_ALLOW_DOMAINS", "")),
+		DenyDomains:  splitList(getEnvOrDefault("SOURCE_DENY_DOMAINS", "")),
+		TLDScores:    make(map[string]float64),
+		DefaultScore: envFloat("SOURCE_DEFAULT_SCORE", 0.5),
+		StaleAfter:   2 * 365 * 24 * time.Hour,
+	}
+	// SOURCE_TLD_SCORES is a list such as "gov=0.95,xyz=0.1"
+	for _, entry := range splitList(getEnvOrDefault("SOURCE_TLD_SCORES", "")) {
+		tld, value, ok := strings.Cut(entry, "=")
+		if score, err := strconv.ParseFloat(strings.TrimSpace(value), 64); ok && err == nil && score >= 0 && score <= 1 {
+			config.TLDScores[strings.ToLower(strings.Trim(strings.TrimSpace(tld), "."))] = score
+		}
+	}
+	if days, err := strconv.Atoi(getEnvOrDefault("SOURCE_STALE_AFTER_DAYS", "")); err == nil && days > 0 {
+		config.StaleAfter = time.Duration(days) * 24 * time.Hour
+	}
+	return config
+}
+
+// SourceScorer rates how credible the sources drones cite are
+type SourceScorer struct {
+	config    SourceScoringConfig
+	tldScores map[string]float64
+}
+
+// NewSourceScorer creates a source scorer from its configuration
+func NewSourceScorer(config SourceScoringConfig) *SourceScorer {
+	tldScores := make(map[string]float64, len(defaultTLDScores)+len(config.TLDScores))
+	for tld, score := range defaultTLDScores {
+		tldScores[tld] = score
+	}
+	for tld, score := range config.TLDScores {
+		tldScores[tld] = score
+	}
+	return &SourceScorer{config: config, tldScores: tldScores}
+}
+
+// Score rates a source between 0 and 1 from its domain and, when known, its age
+func (s *SourceScorer) Score(source string, published time.Time) schemas.SourceScore {
+	score := schemas.SourceScore{URL: source, PublishedAt: published}
+	u, err := url.Parse(source)
+	if err != nil || u.Host == "" {
+		score.Score = s.config.DefaultScore
+		score.Reasons = append(score.Reasons, "not a URL")
+		return score
+	}
+	score.Domain = strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	switch {
+	case matchesDomain(score.Domain, s.config.DenyDomains):
+		score.Reasons = append(score.Reasons, "denied domain")
+		return score
+	case matchesDomain(score.Domain, s.config.AllowDomains):
+		score.Score = 1
+		score.Reasons = append(score.Reasons, "allowed domain")
+	default:
+		score.Score = s.config.DefaultScore
+		labels := strings.Split(score.Domain, ".")
+		for _, label := range []string{labels[len(labels)-1], secondLevelLabel(labels)} {
+			if tldScore, ok := s.tldScores[label]; ok && label != "" {
+				score.Score = tldScore
+				score.Reasons = append(score.Reasons, "."+label+" domain")
+				break
+			}
+		}
+	}
+
+	if published.IsZero() {
+		if match := urlDatePattern.FindStringSubmatch(u.Path); match != nil {
+			year, _ := strconv.Atoi(match[1])
+			month, _ := strconv.Atoi(match[2])
+			published = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+			score.PublishedAt = published
+		}
+	}
+	if age := time.Since(published); !published.IsZero() && s.config.StaleAfter > 0 && age > s.config.StaleAfter {
+		score.Score *= 0.5 + 0.5*float64(s.config.StaleAfter)/float64(age)
+		score.Reasons = append(score.Reasons, "published "+published.Format("Jan 2006"))
+	}
+	return score
+}
+
+// secondLevelLabel returns the first label of a two-part public suffix such as
+// gov.uk or ac.jp, or "" for other domains
+func secondLevelLabel(labels []string) string {
+	if len(labels) < 3 || len(labels[len(labels)-1]) != 2 {
+		return ""
+	}
+	return labels[len(labels)-2]
+}
+
+// matchesDomain reports whether domain is one of domains or a subdomain of one
+func matchesDomain(domain string, domains []string) bool {
+	for _, d := range domains {
+		d = strings.TrimPrefix(strings.ToLower(d), "www.")
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// scoreSources rates every source the completed drone results cite, most credible first
+func (s *SourceScorer) scoreSources(results []schemas.DroneResult) []schemas.SourceScore {
+	var scores []schemas.SourceScore
+	index := make(map[string]int)
+	for _, result := range results {
+		if result.Status != "completed" {
+			continue
+		}
+		cited := make(map[string]bool)
+		for _, source := range resultSources(result) {
+			key := normalizeURL(source.URL)
+			if cited[key] {
+				continue
+			}
+			cited[key] = true
+			if i, ok := index[key]; ok {
+				scores[i].Citations++
+				continue
+			}
+			score := s.Score(key, source.PublishedAt)
+			score.Citations = 1
+			index[key] = len(scores)
+			scores = append(scores, score)
+		}
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].URL < scores[j].URL
+	})
+	return scores
+}
+
+// resultSources reads the sources a drone cited under "sources", either as
+// URLs or as objects with a url and publication date, and those of its findings
+func resultSources(result schemas.DroneResult) []schemas.SourceScore {
+	var sources []schemas.SourceScore
+	items, _ := result.Data["sources"].([]interface{})
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			sources = append(sources, schemas.SourceScore{URL: v})
+		case map[string]interface{}:
+			source := schemas.SourceScore{}
+			source.URL, _ = v["url"].(string)
+			for _, field := range []string{"published_at", "published", "date"} {
+				if date, ok := v[field].(string); ok {
+					source.PublishedAt = parseSourceDate(date)
+					break
+				}
+			}
+			if source.URL != "" {
+				sources = append(sources, source)
+			}
+		}
+	}
+	for _, finding := range resultFindings(result) {
+		for _, source := range finding.Sources {
+			sources = append(sources, schemas.SourceScore{URL: source})
+		}
+	}
+	return sources
+}
+
+// parseSourceDate reads an RFC 3339 or YYYY-MM-DD date, returning the zero time otherwise
+func parseSourceDate(date string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(date)); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// weightFindings sets each finding's credibility to that of its most credible
+// source and orders findings by credibility times the number of drones reporting them
+func weightFindings(findings []schemas.Finding, scores []schemas.SourceScore) {
+	byURL := make(map[string]float64, len(scores))
+	for _, score := range scores {
+		byURL[score.URL] = score.Score
+	}
+	for i := range findings {
+		best := -1.0
+		for _, source := range findings[i].Sources {
+			if score, ok := byURL[source]; ok && score > best {
+				best = score
+			}
+		}
+		findings[i].Credibility = unsourcedCredibility
+		if best >= 0 {
+			findings[i].Credibility = best
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Credibility*float64(len(findings[i].Drones)) > findings[j].Credibility*float64(len(findings[j].Drones))
+	})
+}
@@ -0,0 +1,118 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+)
+
+// cronBits returns the bit set with the given values set
+func cronBits(values ...int) uint64 {
+	var bits uint64
+	for _, value := range values {
+		bits |= 1 << uint(value)
+	}
+	return bits
+}
+
+func TestParseCronField(t *testing.T) {
+	tests := []struct {
+		field    string
+		min, max int
+		want     uint64
+	}{
+		{"*", 0, 6, cronBits(0, 1, 2, 3, 4, 5, 6)},
+		{"5", 0, 59, cronBits(5)},
+		{"1,3,5", 0, 59, cronBits(1, 3, 5)},
+		{"1-4", 0, 59, cronBits(1, 2, 3, 4)},
+		{"*/20", 0, 59, cronBits(0, 20, 40)},
+		{"10-30/10", 0, 59, cronBits(10, 20, 30)},
+		{"5/15", 0, 59, cronBits(5, 20, 35, 50)},
+		{"1-2,10", 1, 31, cronBits(1, 2, 10)},
+	}
+	for _, tt := range tests {
+		got, err := parseCronField(tt.field, tt.min, tt.max)
+		if err != nil {
+			t.Errorf("parseCronField(%q) returned an error: %v", tt.field, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseCronField(%q) = %b, want %b", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"@fortnightly",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 0 *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1-x * * * *",
+	}
+	for _, expr := range tests {
+		if _, err := parseCron(expr, time.UTC); err == nil {
+			t.Errorf("parseCron(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// Thursday 15 January 2026
+	base := time.Date(2026, time.January, 15, 10, 7, 30, 0, time.UTC)
+	eastern := time.FixedZone("EST", -5*60*60)
+
+	tests := []struct {
+		name     string
+		expr     string
+		location *time.Location
+		from     time.Time
+		want     time.Time
+	}{
+		{"every minute", "* * * * *", time.UTC, base, time.Date(2026, 1, 15, 10, 8, 0, 0, time.UTC)},
+		{"minute step", "*/15 * * * *", time.UTC, base, time.Date(2026, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"hourly macro", "@hourly", time.UTC, base, time.Date(2026, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"exact minute is not repeated", "7 10 * * *", time.UTC, time.Date(2026, 1, 15, 10, 7, 0, 0, time.UTC), time.Date(2026, 1, 16, 10, 7, 0, 0, time.UTC)},
+		{"later today", "30 18 * * *", time.UTC, base, time.Date(2026, 1, 15, 18, 30, 0, 0, time.UTC)},
+		{"tomorrow", "30 9 * * *", time.UTC, base, time.Date(2026, 1, 16, 9, 30, 0, 0, time.UTC)},
+		{"hour range with step", "0 9-17/4 * * *", time.UTC, base, time.Date(2026, 1, 15, 13, 0, 0, 0, time.UTC)},
+		{"hour list", "0 8,20 * * *", time.UTC, base, time.Date(2026, 1, 15, 20, 0, 0, 0, time.UTC)},
+		{"day of week", "0 12 * * 1", time.UTC, base, time.Date(2026, 1, 19, 12, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 12 * * 7", time.UTC, base, time.Date(2026, 1, 18, 12, 0, 0, 0, time.UTC)},
+		{"sunday as 0", "0 12 * * 0", time.UTC, base, time.Date(2026, 1, 18, 12, 0, 0, 0, time.UTC)},
+		{"weekday range", "0 9 * * 1-5", time.UTC, time.Date(2026, 1, 16, 10, 0, 0, 0, time.UTC), time.Date(2026, 1, 19, 9, 0, 0, 0, time.UTC)},
+		{"day of month", "0 6 20 * *", time.UTC, base, time.Date(2026, 1, 20, 6, 0, 0, 0, time.UTC)},
+		{"day of month or day of week", "0 12 20 * 5", time.UTC, base, time.Date(2026, 1, 16, 12, 0, 0, 0, time.UTC)},
+		{"day of month and any weekday", "0 12 20 * *", time.UTC, base, time.Date(2026, 1, 20, 12, 0, 0, 0, time.UTC)},
+		{"month rollover", "0 0 1 * *", time.UTC, base, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"skips short months", "0 0 31 * *", time.UTC, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{"month restriction", "0 0 1 6 *", time.UTC, base, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"year rollover", "0 0 * 12 *", time.UTC, time.Date(2026, 12, 31, 23, 59, 0, 0, time.UTC), time.Date(2027, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{"yearly macro", "@yearly", time.UTC, base, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.UTC, base, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"time zone", "0 9 * * *", eastern, base, time.Date(2026, 1, 15, 9, 0, 0, 0, eastern)},
+		{"never runs", "0 0 30 2 *", time.UTC, base, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseCron(tt.expr, tt.location)
+			if err != nil {
+				t.Fatalf("parseCron(%q) returned an error: %v", tt.expr, err)
+			}
+			got := schedule.Next(tt.from)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%v) for %q = %v, want %v", tt.from, tt.expr, got, tt.want)
+			}
+		})
+	}
+}
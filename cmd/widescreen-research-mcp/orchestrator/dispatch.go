@@ -0,0 +1,196 @@
+package orchestrator
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DispatchConfig sets how many sub-queries a drone is sent at a time
+type DispatchConfig struct {
+	// BatchSize is how many sub-queries each instruction carries. A drone is sent
+	// the next batch once it has reported on all of its previous one.
+	BatchSize int
+}
+
+// LoadDispatchConfig reads the sub-query batch size from the environment
+func LoadDispatchConfig() DispatchConfig {
+	config := DispatchConfig{BatchSize: 1}
+	if n, err := strconv.Atoi(getEnvOrDefault("INSTRUCTION_BATCH_SIZE", "")); err == nil && n > 0 {
+		config.BatchSize = n
+	}
+	return config
+}
+
+// taskDispatcher hands a session's sub-queries to its drones a batch at a time,
+// so sessions with more sub-queries than drones are finished by the drones that
+// are done first instead of being cut short
+type taskDispatcher struct {
+	mu        sync.Mutex
+	batchSize int
+	tasks     int
+	pending   []map[string]interface{}
+	// assigned holds the tasks each drone has been sent and not yet reported on
+	assigned map[string][]map[string]interface{}
+}
+
+func newTaskDispatcher(tasks []map[string]interface{}, batchSize int) *taskDispatcher {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	return &taskDispatcher{
+		batchSize: batchSize,
+		tasks:     len(tasks),
+		pending:   tasks,
+		assigned:  make(map[string][]map[string]interface{}),
+	}
+}
+
+// next assigns the next batch of pending tasks to droneID. Drones still working
+// on an earlier batch get nothing.
+func (d *taskDispatcher) next(droneID string) []map[string]interface{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.assigned[droneID]) > 0 || len(d.pending) == 0 {
+		return nil
+	}
+	n := d.batchSize
+	if n > len(d.pending) {
+		n = len(d.pending)
+	}
+	batch := d.pending[:n:n]
+	d.pending = d.pending[n:]
+	d.assigned[droneID] = append([]map[string]interface{}(nil), batch...)
+	return batch
+}
+
+// requeue returns the tasks droneID has not reported on to the front of the queue
+func (d *taskDispatcher) requeue(droneID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pending = append(d.assigned[droneID], d.pending...)
+	delete(d.assigned, droneID)
+}
+
+// reassign moves the tasks from has not reported on to to, and returns them
+func (d *taskDispatcher) reassign(from, to string) []map[string]interface{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tasks := d.assigned[from]
+	delete(d.assigned, from)
+	if len(tasks) > 0 {
+		d.assigned[to] = tasks
+	}
+	return tasks
+}
+
+// reported records droneID's result for taskID, and reports whether the drone
+// has no tasks left
+func (d *taskDispatcher) reported(droneID, taskID string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tasks := d.assigned[droneID]
+	for i, task := range tasks {
+		if id, _ := task["task_id"].(string); id == taskID {
+			tasks = append(tasks[:i:i], tasks[i+1:]...)
+			break
+		}
+	}
+	if len(tasks) == 0 {
+		delete(d.assigned, droneID)
+		return true
+	}
+	d.assigned[droneID] = tasks
+	return false
+}
+
+// remaining returns how many tasks have not been sent to a drone
+func (d *taskDispatcher) remaining() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.pending)
+}
+
+// total returns how many tasks the session has
+func (d *taskDispatcher) total() int {
+	return d.tasks
+}
+
+// expectedResults returns how many results a session waits for: one per
+// sub-query once they are dispatched, and one per drone before
+func expectedResults(session *ResearchSession) int {
+	if session.dispatcher != nil {
+		return session.dispatcher.total()
+	}
+	return session.Config.ResearcherCount
+}
+
+// dispatchPending sends the next batch of pending sub-queries to each drone
+// that can take one. Batches a drone fails to accept go back to the queue for
+// the next drone.
+func (o *Orchestrator) dispatchPending(ctx context.Context, session *ResearchSession) {
+	dispatcher := session.dispatcher
+	if dispatcher == nil {
+		return
+	}
+
+	o.mu.RLock()
+	drones := make([]*DroneInfo, 0, len(session.Drones))
+	for _, drone := range session.Drones {
+		if drone.Status != "unhealthy" && drone.Status != "failed_to_instruct" && drone.Status != "unresponsive" && drone.Status != "replaced" {
+			drones = append(drones, drone)
+		}
+	}
+	o.mu.RUnlock()
+	sort.Slice(drones, func(i, j int) bool { return drones[i].ID < drones[j].ID })
+
+	for _, drone := range drones {
+		if dispatcher.remaining() == 0 {
+			return
+		}
+		batch := dispatcher.next(drone.ID)
+		if len(batch) == 0 {
+			continue
+		}
+		if err := o.sendTasksToDrone(ctx, drone, batch); err != nil {
+			log.Printf("Failed to send %d tasks to drone %s: %v", len(batch), drone.ID, err)
+			dispatcher.requeue(drone.ID)
+			o.mu.Lock()
+			drone.Status = "failed_to_instruct"
+			o.mu.Unlock()
+			continue
+		}
+		o.mu.Lock()
+		drone.Status = "running"
+		drone.Task = batch[0]
+		o.mu.Unlock()
+		log.Printf("Sent %d tasks to drone %s, %d left to dispatch", len(batch), drone.ID, dispatcher.remaining())
+	}
+
+	if n := dispatcher.remaining(); n > 0 && len(drones) == 0 {
+		log.Printf("Warning: %d tasks of session %s are waiting, but no drone can take them", n, session.Config.SessionID)
+	}
+}
+
+// sendTasksToDrone sends a batch of tasks to a drone, which researches them in
+// order and publishes each result as soon as it is ready
+func (o *Orchestrator) sendTasksToDrone(ctx context.Context, drone *DroneInfo, tasks []map[string]interface{}) error {
+	if drone.Simulated {
+		for _, task := range tasks {
+			if err := o.simulateDroneTask(drone, task); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return o.retryInstructions(ctx, drone, func() error {
+		return postCommand(ctx, drone, map[string]interface{}{
+			"type":      "research_batch",
+			"tasks":     tasks,
+			"timestamp": time.Now(),
+		})
+	})
+}
@@ -0,0 +1,170 @@
+package orchestrator
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// testTasks returns n sub-query tasks with IDs t0, t1, ...
+func testTasks(n int) []map[string]interface{} {
+	tasks := make([]map[string]interface{}, n)
+	for i := range tasks {
+		tasks[i] = map[string]interface{}{"task_id": fmt.Sprintf("t%d", i)}
+	}
+	return tasks
+}
+
+// taskIDs returns the IDs of tasks
+func taskIDs(tasks []map[string]interface{}) []string {
+	var ids []string
+	for _, task := range tasks {
+		id, _ := task["task_id"].(string)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestTaskDispatcherBatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		tasks     int
+		batchSize int
+		drones    []string
+		want      map[string][]string
+		remaining int
+	}{
+		{
+			name: "one task per drone", tasks: 3, batchSize: 1,
+			drones:    []string{"a", "b"},
+			want:      map[string][]string{"a": {"t0"}, "b": {"t1"}},
+			remaining: 1,
+		},
+		{
+			name: "batches split evenly", tasks: 6, batchSize: 3,
+			drones:    []string{"a", "b", "c"},
+			want:      map[string][]string{"a": {"t0", "t1", "t2"}, "b": {"t3", "t4", "t5"}},
+			remaining: 0,
+		},
+		{
+			name: "short last batch", tasks: 5, batchSize: 2,
+			drones:    []string{"a", "b", "c"},
+			want:      map[string][]string{"a": {"t0", "t1"}, "b": {"t2", "t3"}, "c": {"t4"}},
+			remaining: 0,
+		},
+		{
+			name: "batch larger than the tasks", tasks: 2, batchSize: 10,
+			drones:    []string{"a", "b"},
+			want:      map[string][]string{"a": {"t0", "t1"}},
+			remaining: 0,
+		},
+		{
+			name: "invalid batch size means one", tasks: 2, batchSize: 0,
+			drones:    []string{"a"},
+			want:      map[string][]string{"a": {"t0"}},
+			remaining: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTaskDispatcher(testTasks(tt.tasks), tt.batchSize)
+			got := make(map[string][]string)
+			for _, drone := range tt.drones {
+				if batch := d.next(drone); len(batch) > 0 {
+					got[drone] = taskIDs(batch)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("batches = %v, want %v", got, tt.want)
+			}
+			if n := d.remaining(); n != tt.remaining {
+				t.Errorf("remaining() = %d, want %d", n, tt.remaining)
+			}
+			if n := d.total(); n != tt.tasks {
+				t.Errorf("total() = %d, want %d", n, tt.tasks)
+			}
+		})
+	}
+}
+
+func TestTaskDispatcherNextBatchAfterReports(t *testing.T) {
+	d := newTaskDispatcher(testTasks(5), 2)
+	if got := taskIDs(d.next("a")); !reflect.DeepEqual(got, []string{"t0", "t1"}) {
+		t.Fatalf("first batch = %v, want [t0 t1]", got)
+	}
+	// A drone gets nothing more until it has reported on its whole batch
+	if batch := d.next("a"); batch != nil {
+		t.Fatalf("next() while busy = %v, want nil", taskIDs(batch))
+	}
+	if d.reported("a", "t1") {
+		t.Fatal("reported() = true with t0 outstanding, want false")
+	}
+	if batch := d.next("a"); batch != nil {
+		t.Fatalf("next() with t0 outstanding = %v, want nil", taskIDs(batch))
+	}
+	if !d.reported("a", "t0") {
+		t.Fatal("reported() = false after the whole batch, want true")
+	}
+	if got := taskIDs(d.next("a")); !reflect.DeepEqual(got, []string{"t2", "t3"}) {
+		t.Errorf("second batch = %v, want [t2 t3]", got)
+	}
+}
+
+func TestTaskDispatcherRequeueAndReassign(t *testing.T) {
+	d := newTaskDispatcher(testTasks(4), 2)
+	d.next("a")
+	d.next("b")
+
+	// a failed to accept its batch, which goes back to the front of the queue
+	d.requeue("a")
+	if n := d.remaining(); n != 2 {
+		t.Fatalf("remaining() after requeue = %d, want 2", n)
+	}
+	if got := taskIDs(d.next("c")); !reflect.DeepEqual(got, []string{"t0", "t1"}) {
+		t.Errorf("batch after requeue = %v, want [t0 t1]", got)
+	}
+
+	// b was replaced by d, which takes over what b has not reported on
+	d.reported("b", "t2")
+	if got := taskIDs(d.reassign("b", "d")); !reflect.DeepEqual(got, []string{"t3"}) {
+		t.Errorf("reassign() = %v, want [t3]", got)
+	}
+	if batch := d.next("d"); batch != nil {
+		t.Errorf("next() for the replacement while busy = %v, want nil", taskIDs(batch))
+	}
+	if !d.reported("d", "t3") {
+		t.Error("reported() by the replacement = false, want true")
+	}
+	if tasks := d.reassign("b", "e"); tasks != nil {
+		t.Errorf("reassign() of a drone with no tasks = %v, want nil", taskIDs(tasks))
+	}
+}
+
+func TestExpectedResults(t *testing.T) {
+	tests := []struct {
+		name    string
+		session *ResearchSession
+		want    int
+	}{
+		{
+			name:    "one per drone before dispatching",
+			session: &ResearchSession{Config: &schemas.ResearchConfig{ResearcherCount: 3}},
+			want:    3,
+		},
+		{
+			name: "one per sub-query once dispatched",
+			session: &ResearchSession{
+				Config:     &schemas.ResearchConfig{ResearcherCount: 3},
+				dispatcher: newTaskDispatcher(testTasks(7), 2),
+			},
+			want: 7,
+		},
+	}
+	for _, tt := range tests {
+		if got := expectedResults(tt.session); got != tt.want {
+			t.Errorf("%s: expectedResults() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
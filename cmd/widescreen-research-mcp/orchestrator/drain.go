@@ -0,0 +1,116 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrDraining is returned for research requested while the orchestrator shuts down
+var ErrDraining = errors.New("orchestrator is shutting down")
+
+// drainCleanupTimeout bounds deleting the drones and topics of sessions left after draining
+const drainCleanupTimeout = 2 * time.Minute
+
+// LoadDrainTimeout reads how long shutdown waits for active sessions to finish
+func LoadDrainTimeout() time.Duration {
+	if timeout, err := time.ParseDuration(getEnvOrDefault("SHUTDOWN_DRAIN_TIMEOUT", "")); err == nil && timeout >= 0 {
+		return timeout
+	}
+	return 30 * time.Second
+}
+
+// Drain stops new research from starting and waits until the active sessions
+// finish or ctx is done. Sessions still running are then marked interrupted,
+// keeping the results already stored for them, and every remaining session's
+// drones and topics are deleted so no Cloud Run services are left behind.
+func (o *Orchestrator) Drain(ctx context.Context) {
+	o.mu.Lock()
+	o.draining = true
+	o.mu.Unlock()
+	o.stopScheduler()
+	o.stopRetention()
+
+	log.Printf("Draining orchestrator: %d sessions running", o.runningSessions())
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+wait:
+	for o.runningSessions() > 0 {
+		select {
+		case <-ctx.Done():
+			log.Printf("Warning: Drain timed out with %d sessions running", o.runningSessions())
+			break wait
+		case <-ticker.C:
+		}
+	}
+
+	o.mu.RLock()
+	sessions := make([]*ResearchSession, 0, len(o.activeSessions))
+	for _, session := range o.activeSessions {
+		sessions = append(sessions, session)
+	}
+	o.mu.RUnlock()
+
+	cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), drainCleanupTimeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, session := range sessions {
+		if status := o.sessionStatus(session); status == "initializing" || status == "running" {
+			if o.setSessionStatus(session, "interrupted") {
+				session.cancel()
+				o.mu.RLock()
+				collected := len(session.Results)
+				o.mu.RUnlock()
+				log.Printf("Interrupted session %s with %d results collected", session.Config.SessionID, collected)
+				if err := o.updateProgressFile(session); err != nil {
+					log.Printf("Warning: failed to update progress file for session %s: %v", session.Config.SessionID, err)
+				}
+			}
+		}
+		wg.Add(1)
+		go func(session *ResearchSession) {
+			defer wg.Done()
+			o.cleanupSession(cleanupCtx, session)
+		}(session)
+	}
+	wg.Wait()
+
+	// Sessions that finished on their own may still be cleaning up
+	for o.activeSessionCount() > 0 {
+		select {
+		case <-cleanupCtx.Done():
+			log.Printf("Warning: %d sessions were not cleaned up before shutdown", o.activeSessionCount())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// isDraining reports whether Drain has been called
+func (o *Orchestrator) isDraining() bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.draining
+}
+
+// runningSessions counts the sessions that have not reached a final status
+func (o *Orchestrator) runningSessions() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	running := 0
+	for _, session := range o.activeSessions {
+		if session.Status == "initializing" || session.Status == "running" {
+			running++
+		}
+	}
+	return running
+}
+
+// activeSessionCount counts the sessions whose resources have not been cleaned up
+func (o *Orchestrator) activeSessionCount() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.activeSessions)
+}
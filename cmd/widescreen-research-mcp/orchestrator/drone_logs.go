@@ -0,0 +1,177 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	logging "google.golang.org/api/logging/v2"
+)
+
+const (
+	// defaultDroneLogWindow is how far back drone logs are read when no start is given
+	defaultDroneLogWindow = time.Hour
+	// defaultDroneLogLimit and maxDroneLogLimit bound how many entries are returned
+	defaultDroneLogLimit = 100
+	maxDroneLogLimit     = 1000
+	// sessionLogGrace keeps the drone logs written while a finished session was cleaned up
+	sessionLogGrace = 5 * time.Minute
+)
+
+// logSeverities are the Cloud Logging severities, least severe first
+var logSeverities = []string{"DEFAULT", "DEBUG", "INFO", "NOTICE", "WARNING", "ERROR", "CRITICAL", "ALERT", "EMERGENCY"}
+
+// DroneLogs returns the most recent Cloud Logging entries of a drone's Cloud
+// Run service, oldest first. With a session ID, entries are limited to the
+// time the session ran.
+func (o *Orchestrator) DroneLogs(ctx context.Context, query schemas.DroneLogQuery) (*schemas.DroneLogs, error) {
+	if query.DroneID == "" {
+		return nil, fmt.Errorf("drone_id is required")
+	}
+	if o.loggingService == nil {
+		return nil, fmt.Errorf("logging client not initialized")
+	}
+
+	if query.SessionID != "" {
+		start, end, err := o.sessionWindow(ctx, query.SessionID, query.DroneID)
+		if err != nil {
+			return nil, err
+		}
+		if query.Since.IsZero() || query.Since.Before(start) {
+			query.Since = start
+		}
+		if !end.IsZero() {
+			end = end.Add(sessionLogGrace)
+			if query.Until.IsZero() || query.Until.After(end) {
+				query.Until = end
+			}
+		}
+	}
+	if query.Since.IsZero() {
+		query.Since = time.Now().Add(-defaultDroneLogWindow)
+	}
+	if !query.Until.IsZero() && !query.Until.After(query.Since) {
+		return nil, fmt.Errorf("until must be after since")
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultDroneLogLimit
+	}
+	query.Limit = min(query.Limit, maxDroneLogLimit)
+
+	filter, err := droneLogFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read newest first so the limit keeps the most recent entries
+	resp, err := o.loggingService.Entries.List(&logging.ListLogEntriesRequest{
+		ResourceNames: []string{"projects/" + o.projectID},
+		Filter:        filter,
+		OrderBy:       "timestamp desc",
+		PageSize:      int64(query.Limit),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logs of drone %s: %w", query.DroneID, err)
+	}
+
+	logs := &schemas.DroneLogs{
+		Query:     query,
+		Entries:   make([]schemas.DroneLogEntry, 0, len(resp.Entries)),
+		Truncated: resp.NextPageToken != "",
+	}
+	for i := len(resp.Entries) - 1; i >= 0; i-- {
+		logs.Entries = append(logs.Entries, droneLogEntry(resp.Entries[i]))
+	}
+	return logs, nil
+}
+
+// droneLogFilter builds the Cloud Logging filter for a query
+func droneLogFilter(query schemas.DroneLogQuery) (string, error) {
+	clauses := []string{
+		`resource.type = "cloud_run_revision"`,
+		fmt.Sprintf("resource.labels.service_name = %q", query.DroneID),
+		fmt.Sprintf("timestamp >= %q", query.Since.UTC().Format(time.RFC3339Nano)),
+	}
+	if !query.Until.IsZero() {
+		clauses = append(clauses, fmt.Sprintf("timestamp <= %q", query.Until.UTC().Format(time.RFC3339Nano)))
+	}
+	if query.MinSeverity != "" {
+		severity := strings.ToUpper(query.MinSeverity)
+		known := false
+		for _, s := range logSeverities {
+			known = known || s == severity
+		}
+		if !known {
+			return "", fmt.Errorf("unknown severity %q, expected one of %s", query.MinSeverity, strings.Join(logSeverities, ", "))
+		}
+		clauses = append(clauses, "severity >= "+severity)
+	}
+	return strings.Join(clauses, " AND "), nil
+}
+
+// droneLogEntry reads a log entry's message from its text payload, or the
+// message field of a structured payload
+func droneLogEntry(entry *logging.LogEntry) schemas.DroneLogEntry {
+	result := schemas.DroneLogEntry{
+		Severity: entry.Severity,
+		Message:  entry.TextPayload,
+	}
+	result.Timestamp, _ = time.Parse(time.RFC3339Nano, entry.Timestamp)
+	if entry.Resource != nil {
+		result.Revision = entry.Resource.Labels["revision_name"]
+		result.Region = entry.Resource.Labels["location"]
+	}
+	if result.Message == "" && len(entry.JsonPayload) > 0 {
+		var payload map[string]interface{}
+		if err := json.Unmarshal(entry.JsonPayload, &payload); err == nil {
+			result.Message, _ = payload["message"].(string)
+		}
+		if result.Message == "" {
+			result.Message = string(entry.JsonPayload)
+		}
+	}
+	if result.Message == "" && entry.HttpRequest != nil {
+		result.Message = fmt.Sprintf("%s %s %d", entry.HttpRequest.RequestMethod, entry.HttpRequest.RequestUrl, entry.HttpRequest.Status)
+	}
+	return result
+}
+
+// sessionWindow returns when a session started and, once it has finished,
+// when it last changed. A running session must include the drone.
+func (o *Orchestrator) sessionWindow(ctx context.Context, sessionID, droneID string) (time.Time, time.Time, error) {
+	o.mu.RLock()
+	session, active := o.activeSessions[sessionID]
+	// Other tenants' sessions are as good as missing
+	active = active && session.Config.Tenant == o.tenantID(ctx)
+	var start time.Time
+	var known bool
+	if active {
+		start = session.StartTime
+		_, known = session.Drones[droneID]
+	}
+	o.mu.RUnlock()
+	if active {
+		if !known {
+			return time.Time{}, time.Time{}, fmt.Errorf("drone %s is not part of session %s", droneID, sessionID)
+		}
+		return start, time.Time{}, nil
+	}
+
+	if o.firestoreClient == nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("session %s not found", sessionID)
+	}
+	doc, err := o.collection(o.tenantID(ctx), sessionsCollection).Doc(sessionID).Get(ctx)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to get session %s: %w", sessionID, err)
+	}
+	data := doc.Data()
+	start, _ = data["started_at"].(time.Time)
+	end, _ := data["updated_at"].(time.Time)
+	if status, _ := data["status"].(string); status == "initializing" || status == "running" {
+		end = time.Time{}
+	}
+	return start, end, nil
+}
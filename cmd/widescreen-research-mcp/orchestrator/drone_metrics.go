@@ -0,0 +1,106 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// metricsTopicName returns the Pub/Sub topic drones publish a session's metrics to
+func metricsTopicName(tenantID, sessionID string) string {
+	return tenantResourceName(tenantID, fmt.Sprintf("research-metrics-%s", sessionID))
+}
+
+// receiveDroneMetrics records the metrics the session's drones publish until
+// ctx is done. Drones report cumulative counters, so only each drone's latest
+// report is kept.
+func (o *Orchestrator) receiveDroneMetrics(ctx context.Context, session *ResearchSession) error {
+	topicName := metricsTopicName(session.Config.Tenant, session.Config.SessionID)
+	topic := o.pubsubClient.Topic(topicName)
+	exists, err := topic.Exists(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check topic %s: %w", topicName, err)
+	}
+	if !exists {
+		if topic, err = o.pubsubClient.CreateTopic(ctx, topicName); err != nil {
+			return fmt.Errorf("failed to create topic %s: %w", topicName, err)
+		}
+	}
+
+	subscriptionName := tenantResourceName(session.Config.Tenant, fmt.Sprintf("research-metrics-sub-%s", session.Config.SessionID))
+	subscription := o.pubsubClient.Subscription(subscriptionName)
+	exists, err = subscription.Exists(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check subscription %s: %w", subscriptionName, err)
+	}
+	if !exists {
+		// Only the latest report of each drone matters, so old ones need not be kept
+		subscription, err = o.pubsubClient.CreateSubscription(ctx, subscriptionName, pubsub.SubscriptionConfig{
+			Topic:             topic,
+			AckDeadline:       10 * time.Second,
+			RetentionDuration: time.Hour,
+			ExpirationPolicy:  25 * time.Hour,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create subscription %s: %w", subscriptionName, err)
+		}
+	}
+
+	go func() {
+		err := subscription.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
+			msg.Ack()
+			var metrics types.DroneMetrics
+			if err := json.Unmarshal(msg.Data, &metrics); err != nil {
+				log.Printf("Warning: Failed to decode drone metrics: %v", err)
+				return
+			}
+			o.recordDroneMetrics(session, metrics)
+		})
+		if err != nil {
+			log.Printf("Warning: Stopped receiving drone metrics of session %s: %v", session.Config.SessionID, err)
+		}
+	}()
+	return nil
+}
+
+// recordDroneMetrics keeps a drone's report unless a later one was already recorded
+func (o *Orchestrator) recordDroneMetrics(session *ResearchSession, metrics types.DroneMetrics) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if previous, ok := session.droneMetrics[metrics.DroneID]; ok && previous.Timestamp.After(metrics.Timestamp) {
+		return
+	}
+	if session.droneMetrics == nil {
+		session.droneMetrics = make(map[string]types.DroneMetrics)
+	}
+	session.droneMetrics[metrics.DroneID] = metrics
+}
+
+// applyDroneMetrics adds what the session's drones reported about themselves to metrics
+func (o *Orchestrator) applyDroneMetrics(session *ResearchSession, metrics *schemas.ResearchMetrics) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	for _, reported := range session.droneMetrics {
+		metrics.DroneUsage = append(metrics.DroneUsage, schemas.DroneUsage{
+			DroneID:         reported.DroneID,
+			RequestsHandled: reported.RequestsHandled,
+			TasksCompleted:  reported.TasksCompleted,
+			TasksFailed:     reported.TasksFailed,
+			SearchCalls:     reported.SearchCalls,
+			LLMTokens:       reported.LLMTokens,
+			MemoryBytes:     reported.MemoryBytes,
+			UptimeSeconds:   reported.UptimeSeconds,
+			ReportedAt:      reported.Timestamp,
+		})
+	}
+	sort.Slice(metrics.DroneUsage, func(i, j int) bool {
+		return metrics.DroneUsage[i].DroneID < metrics.DroneUsage[j].DroneID
+	})
+}
@@ -0,0 +1,274 @@
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sendGridURL is the SendGrid v3 mail send endpoint
+const sendGridURL = "https://api.sendgrid.com/v3/mail/send"
+
+// EmailConfig selects how finished-session emails are sent
+type EmailConfig struct {
+	// Provider is "smtp" or "sendgrid"; empty disables email
+	Provider       string
+	From           string
+	SMTPHost       string
+	SMTPPort       int
+	SMTPUsername   string
+	SMTPPassword   string
+	SendGridAPIKey string
+}
+
+// LoadEmailConfig reads the email settings from the environment. The provider
+// defaults to SendGrid when SENDGRID_API_KEY is set and to SMTP when SMTP_HOST is.
+func LoadEmailConfig() EmailConfig {
+	config := EmailConfig{
+		Provider:       getEnvOrDefault("EMAIL_PROVIDER", ""),
+		From:           getEnvOrDefault("EMAIL_FROM", ""),
+		SMTPHost:       getEnvOrDefault("SMTP_HOST", ""),
+		SMTPPort:       587,
+		SMTPUsername:   getEnvOrDefault("SMTP_USERNAME", ""),
+		SMTPPassword:   getEnvOrDefault("SMTP_PASSWORD", ""),
+		SendGridAPIKey: getEnvOrDefault("SENDGRID_API_KEY", ""),
+	}
+	if port, err := strconv.Atoi(getEnvOrDefault("SMTP_PORT", "")); err == nil && port > 0 {
+		config.SMTPPort = port
+	}
+
+	if config.Provider == "" {
+		switch {
+		case config.SendGridAPIKey != "":
+			config.Provider = "sendgrid"
+		case config.SMTPHost != "":
+			config.Provider = "smtp"
+		}
+	}
+
+	return config
+}
+
+// Mailer emails session results to the addresses collected during elicitation
+type Mailer struct {
+	config EmailConfig
+	client *http.Client
+}
+
+// NewMailer creates a mailer, or returns nil if email is not configured
+func NewMailer(config EmailConfig) *Mailer {
+	if config.Provider == "" {
+		return nil
+	}
+	if config.From == "" {
+		log.Printf("Warning: EMAIL_FROM is not set, report emails are disabled")
+		return nil
+	}
+
+	switch config.Provider {
+	case "smtp":
+		if config.SMTPHost == "" {
+			log.Printf("Warning: SMTP_HOST is not set, report emails are disabled")
+			return nil
+		}
+	case "sendgrid":
+		if config.SendGridAPIKey == "" {
+			log.Printf("Warning: SENDGRID_API_KEY is not set, report emails are disabled")
+			return nil
+		}
+	default:
+		log.Printf("Warning: Unknown EMAIL_PROVIDER %q, report emails are disabled", config.Provider)
+		return nil
+	}
+
+	return &Mailer{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// enabled reports whether the mailer can send
+func (m *Mailer) enabled() bool {
+	return m != nil
+}
+
+// emailMessage is a plain-text email with an optional attachment
+type emailMessage struct {
+	To             []string
+	Subject        string
+	Body           string
+	AttachmentName string
+	Attachment     []byte
+}
+
+// SendSessionEmail emails a summary of the finished session to recipients,
+// attaching the Markdown report at reportFile if there is one
+func (m *Mailer) SendSessionEmail(ctx context.Context, recipients []string, event SessionEvent, reportFile string) error {
+	var to []string
+	for _, recipient := range recipients {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			log.Printf("Warning: Skipping invalid email address %q: %v", recipient, err)
+			continue
+		}
+		to = append(to, address.Address)
+	}
+	if len(to) == 0 {
+		return fmt.Errorf("no valid recipients")
+	}
+
+	msg := emailMessage{
+		To:      to,
+		Subject: fmt.Sprintf("Research %s: %s", strings.ReplaceAll(event.Status, "_", " "), event.Topic),
+		Body:    event.message(),
+	}
+	if reportFile != "" {
+		report, err := os.ReadFile(reportFile)
+		if err != nil {
+			log.Printf("Warning: Failed to attach report %s: %v", reportFile, err)
+		} else {
+			msg.AttachmentName = filepath.Base(reportFile)
+			msg.Attachment = report
+		}
+	}
+
+	if m.config.Provider == "sendgrid" {
+		return m.sendGrid(ctx, msg)
+	}
+	return m.sendSMTP(msg)
+}
+
+// sendSMTP sends msg through the configured SMTP server, authenticating when a username is set
+func (m *Mailer) sendSMTP(msg emailMessage) error {
+	data, err := m.mimeMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.config.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", m.config.SMTPUsername, m.config.SMTPPassword, m.config.SMTPHost)
+	}
+
+	addr := net.JoinHostPort(m.config.SMTPHost, strconv.Itoa(m.config.SMTPPort))
+	if err := smtp.SendMail(addr, auth, m.config.From, msg.To, data); err != nil {
+		return fmt.Errorf("failed to send email via %s: %w", addr, err)
+	}
+	return nil
+}
+
+// mimeMessage renders msg as a multipart MIME message
+func (m *Mailer) mimeMessage(msg emailMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", m.config.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", writer.Boundary())
+
+	body, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"8bit"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write email body: %w", err)
+	}
+	if _, err := body.Write([]byte(strings.ReplaceAll(msg.Body, "\n", "\r\n"))); err != nil {
+		return nil, fmt.Errorf("failed to write email body: %w", err)
+	}
+
+	if msg.Attachment != nil {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {"text/markdown; charset=utf-8"},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": msg.AttachmentName})},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to write email attachment: %w", err)
+		}
+		encoded := base64.StdEncoding.EncodeToString(msg.Attachment)
+		for len(encoded) > 76 {
+			fmt.Fprintf(part, "%s\r\n", encoded[:76])
+			encoded = encoded[76:]
+		}
+		fmt.Fprintf(part, "%s\r\n", encoded)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish email: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// sendGrid sends msg through the SendGrid v3 API
+func (m *Mailer) sendGrid(ctx context.Context, msg emailMessage) error {
+	type address struct {
+		Email string `json:"email"`
+	}
+	type attachment struct {
+		Content     string `json:"content"`
+		Filename    string `json:"filename"`
+		Type        string `json:"type"`
+		Disposition string `json:"disposition"`
+	}
+
+	to := make([]address, 0, len(msg.To))
+	for _, recipient := range msg.To {
+		to = append(to, address{Email: recipient})
+	}
+	payload := map[string]interface{}{
+		"personalizations": []map[string]interface{}{{"to": to}},
+		"from":             address{Email: m.config.From},
+		"subject":          msg.Subject,
+		"content":          []map[string]string{{"type": "text/plain", "value": msg.Body}},
+	}
+	if msg.Attachment != nil {
+		payload["attachments"] = []attachment{{
+			Content:     base64.StdEncoding.EncodeToString(msg.Attachment),
+			Filename:    msg.AttachmentName,
+			Type:        "text/markdown",
+			Disposition: "attachment",
+		}}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal email: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sendGridURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+m.config.SendGridAPIKey)
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send email via SendGrid: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("SendGrid returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+)
+
+// embeddingBatchSize is the most texts sent in one embedding request
+const embeddingBatchSize = 100
+
+// Embedder turns texts into embedding vectors, one per text
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// EmbeddingConfig selects the embedding model findings are clustered with
+type EmbeddingConfig struct {
+	// Provider is "vertex", "openai", "local" for hashed word counts, or "none" to disable clustering
+	Provider string
+	// Model defaults to text-embedding-004 on Vertex AI and text-embedding-3-small on OpenAI
+	Model string
+	// OpenAI settings
+	APIKey string
+	APIURL string
+	// Vertex AI settings
+	ProjectID string
+	Region    string
+}
+
+// LoadEmbeddingConfig reads the embedding settings from the environment
+func LoadEmbeddingConfig(projectID, region string) EmbeddingConfig {
+	return EmbeddingConfig{
+		Provider:  getEnvOrDefault("EMBEDDING_PROVIDER", "local"),
+		Model:     getEnvOrDefault("EMBEDDING_MODEL", ""),
+		APIKey:    getEnvOrDefault("OPENAI_API_KEY", ""),
+		APIURL:    strings.TrimSuffix(getEnvOrDefault("OPENAI_API_URL", "https://api.openai.com/v1"), "/"),
+		ProjectID: projectID,
+		Region:    getEnvOrDefault("VERTEX_AI_REGION", region),
+	}
+}
+
+// NewEmbedder creates the configured embedder, or returns nil if clustering is disabled
+func NewEmbedder(ctx context.Context, config EmbeddingConfig) (Embedder, error) {
+	switch config.Provider {
+	case "none", "":
+		return nil, nil
+	case "local":
+		return hashEmbedder{dimensions: 256}, nil
+	case "openai":
+		if config.APIKey == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY is required for OpenAI embeddings")
+		}
+		if config.Model == "" {
+			config.Model = "text-embedding-3-small"
+		}
+		return &openAIEmbedder{config: config, client: &http.Client{Timeout: 30 * time.Second}}, nil
+	case "vertex":
+		if config.Model == "" {
+			config.Model = "text-embedding-004"
+		}
+		client, _, err := htransport.NewClient(ctx, option.WithScopes("https://www.googleapis.com/auth/cloud-platform"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Vertex AI client: %w", err)
+		}
+		client.Timeout = 30 * time.Second
+		return &vertexEmbedder{config: config, client: client}, nil
+	}
+	return nil, fmt.Errorf("unknown EMBEDDING_PROVIDER %q", config.Provider)
+}
+
+// openAIEmbedder embeds texts with the OpenAI embeddings API
+type openAIEmbedder struct {
+	config EmbeddingConfig
+	client *http.Client
+}
+
+// Embed implements Embedder
+func (e *openAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	return embedInBatches(texts, func(batch []string) ([][]float64, error) {
+		var response struct {
+			Data []struct {
+				Index     int       `json:"index"`
+				Embedding []float64 `json:"embedding"`
+			} `json:"data"`
+		}
+		err := postJSON(ctx, e.client, e.config.APIURL+"/embeddings", map[string]string{
+			"Authorization": "Bearer " + e.config.APIKey,
+		}, map[string]interface{}{
+			"model": e.config.Model,
+			"input": batch,
+		}, &response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get OpenAI embeddings: %w", err)
+		}
+
+		vectors := make([][]float64, len(batch))
+		for _, item := range response.Data {
+			if item.Index >= 0 && item.Index < len(vectors) {
+				vectors[item.Index] = item.Embedding
+			}
+		}
+		return vectors, nil
+	})
+}
+
+// vertexEmbedder embeds texts with a Vertex AI text embedding model
+type vertexEmbedder struct {
+	config EmbeddingConfig
+	client *http.Client
+}
+
+// Embed implements Embedder
+func (e *vertexEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	url := fmt.Sprintf("https://%s-aiplatform.googleapis.com/v1/projects/%s/locations/%s/publishers/google/models/%s:predict",
+		e.config.Region, e.config.ProjectID, e.config.Region, e.config.Model)
+
+	return embedInBatches(texts, func(batch []string) ([][]float64, error) {
+		instances := make([]map[string]string, len(batch))
+		for i, text := range batch {
+			instances[i] = map[string]string{"content": text}
+		}
+		var response struct {
+			Predictions []struct {
+				Embeddings struct {
+					Values []float64 `json:"values"`
+				} `json:"embeddings"`
+			} `json:"predictions"`
+		}
+		if err := postJSON(ctx, e.client, url, nil, map[string]interface{}{"instances": instances}, &response); err != nil {
+			return nil, fmt.Errorf("failed to get Vertex AI embeddings: %w", err)
+		}
+
+		vectors := make([][]float64, len(batch))
+		for i, prediction := range response.Predictions {
+			if i < len(vectors) {
+				vectors[i] = prediction.Embeddings.Values
+			}
+		}
+		return vectors, nil
+	})
+}
+
+// embedInBatches embeds texts a batch at a time and checks every text got a vector
+func embedInBatches(texts []string, embed func(batch []string) ([][]float64, error)) ([][]float64, error) {
+	vectors := make([][]float64, 0, len(texts))
+	for start := 0; start < len(texts); start += embeddingBatchSize {
+		batch := texts[start:min(start+embeddingBatchSize, len(texts))]
+		batchVectors, err := embed(batch)
+		if err != nil {
+			return nil, err
+		}
+		for i, vector := range batchVectors {
+			if len(vector) == 0 {
+				return nil, fmt.Errorf("no embedding returned for text %d", start+i+1)
+			}
+		}
+		vectors = append(vectors, batchVectors...)
+	}
+	return vectors, nil
+}
+
+// postJSON posts body as JSON and decodes the JSON response into out
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// hashEmbedder embeds texts as hashed counts of their content words and word
+// pairs. It needs no model, so clustering works without an embeddings API, but
+// only groups texts that share words.
+type hashEmbedder struct {
+	dimensions int
+}
+
+// Embed implements Embedder
+func (e hashEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vector := make([]float64, e.dimensions)
+		words := contentWords(text)
+		for j, word := range words {
+			vector[hashIndex(word, e.dimensions)]++
+			if j > 0 {
+				vector[hashIndex(words[j-1]+" "+word, e.dimensions)] += 0.5
+			}
+		}
+		vectors[i] = vector
+	}
+	return vectors, nil
+}
+
+// hashIndex hashes a term to a vector index
+func hashIndex(term string, dimensions int) int {
+	h := fnv.New32a()
+	h.Write([]byte(term))
+	return int(h.Sum32() % uint32(dimensions))
+}
+
+// normalizeVector scales a vector to unit length in place
+func normalizeVector(vector []float64) {
+	norm := 0.0
+	for _, v := range vector {
+		norm += v * v
+	}
+	if norm = math.Sqrt(norm); norm > 0 {
+		for i := range vector {
+			vector[i] /= norm
+		}
+	}
+}
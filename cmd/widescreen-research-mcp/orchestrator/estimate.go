@@ -0,0 +1,128 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// coldStartMinutes is roughly how long a new Cloud Run drone takes to deploy
+const coldStartMinutes = 2.0
+
+// reportTokensPerDrone is the LLM usage of folding one drone's findings into the report
+const reportTokensPerDrone = 1500
+
+// depthProfile is the expected workload of one drone at a research depth
+type depthProfile struct {
+	minutes   float64
+	llmTokens int
+	exaCalls  int
+}
+
+// depthProfiles maps research depths to their per-drone workload
+var depthProfiles = map[string]depthProfile{
+	"basic":    {minutes: 5, llmTokens: 2000, exaCalls: 3},
+	"standard": {minutes: 15, llmTokens: 6000, exaCalls: 10},
+	"deep":     {minutes: 40, llmTokens: 15000, exaCalls: 25},
+}
+
+// EstimateResearch plans a research session and estimates its time and cost
+// without provisioning anything
+func (o *Orchestrator) EstimateResearch(ctx context.Context, config *schemas.ResearchConfig) (*schemas.ResearchEstimate, error) {
+	// Apply the same defaults as elicitation, and the budget the tenant's quota gives
+	planned := *config
+	planned.Tenant = o.tenantID(ctx)
+	if quota := o.tenants.quota(planned.Tenant); planned.MaxCostUSD == 0 {
+		planned.MaxCostUSD = quota.MaxSessionCostUSD
+	}
+	if planned.ResearcherCount == 0 {
+		planned.ResearcherCount = 10
+	}
+	if planned.ResearchDepth == "" {
+		planned.ResearchDepth = "standard"
+	}
+	if planned.TimeoutMinutes == 0 {
+		planned.TimeoutMinutes = 60
+	}
+	if planned.PriorityLevel == "" {
+		planned.PriorityLevel = "normal"
+	}
+
+	if planned.ResearcherCount < 1 || planned.ResearcherCount > 100 {
+		return nil, fmt.Errorf("researcher_count must be between 1 and 100, got %d", planned.ResearcherCount)
+	}
+	if planned.TimeoutMinutes < 0 {
+		return nil, fmt.Errorf("timeout_minutes must be positive, got %d", planned.TimeoutMinutes)
+	}
+	profile, ok := depthProfiles[planned.ResearchDepth]
+	if !ok {
+		return nil, fmt.Errorf("unknown research_depth %q", planned.ResearchDepth)
+	}
+
+	cpu := o.getCPUForPriority(planned.PriorityLevel)
+	memory := o.getMemoryForPriority(planned.PriorityLevel)
+	budget := newSessionBudget(o.costRates, planned.MaxCostUSD, cpuCores(cpu), memoryGiB(memory))
+
+	warm := 0
+	if o.usesWarmPool(planned.Tenant) {
+		warm = o.warmPool.Stats()[planned.PriorityLevel]
+	}
+	if warm > planned.ResearcherCount {
+		warm = planned.ResearcherCount
+	}
+	provisioning := 0.0
+	if warm < planned.ResearcherCount {
+		provisioning = coldStartMinutes
+	}
+	// Deployments beyond PROVISION_PARALLELISM wait for earlier ones, so the last drones start later
+	provisioningWall := provisioning * float64(o.deployThrottle.waves(planned.ResearcherCount-warm))
+
+	timeout := float64(planned.TimeoutMinutes)
+	researchMinutes := math.Min(profile.minutes, timeout)
+	drones := float64(planned.ResearcherCount)
+
+	llmTokens := planned.ResearcherCount * (profile.llmTokens + reportTokensPerDrone)
+	exaCalls := planned.ResearcherCount * profile.exaCalls
+	cost := schemas.CostBreakdown{
+		DroneRuntimeUSD: drones * (provisioning + researchMinutes) * 60 * budget.dronePerSecondUSD(),
+		LLMTokensUSD:    float64(llmTokens) / 1000 * o.costRates.LLMPer1KTokensUSD,
+		ExaCallsUSD:     float64(exaCalls) * o.costRates.ExaCallUSD,
+		LLMTokens:       llmTokens,
+		ExaCalls:        exaCalls,
+	}
+	cost.TotalUSD = cost.DroneRuntimeUSD + cost.LLMTokensUSD + cost.ExaCallsUSD
+
+	// Worst case: every drone runs until the session times out
+	maxCost := cost.TotalUSD - cost.DroneRuntimeUSD + drones*(provisioning+timeout)*60*budget.dronePerSecondUSD()
+
+	estimate := &schemas.ResearchEstimate{
+		Config:           &planned,
+		DroneCount:       planned.ResearcherCount,
+		WarmDrones:       warm,
+		DroneCPU:         cpu,
+		DroneMemory:      memory,
+		EstimatedMinutes: provisioningWall + researchMinutes,
+		MaxMinutes:       provisioningWall + timeout,
+		EstimatedCost:    cost,
+		WorstCaseCostUSD: maxCost,
+		WithinBudget:     !budget.exceeded(cost),
+	}
+
+	if profile.minutes > timeout {
+		estimate.Warnings = append(estimate.Warnings, fmt.Sprintf(
+			"%s research usually takes about %.0f minutes per drone; a %d minute timeout may cut it short", planned.ResearchDepth, profile.minutes, planned.TimeoutMinutes))
+	}
+	if planned.MaxCostUSD > 0 {
+		if !estimate.WithinBudget {
+			estimate.Warnings = append(estimate.Warnings, fmt.Sprintf(
+				"estimated cost $%.2f exceeds the $%.2f budget; the session will be stopped early", cost.TotalUSD, planned.MaxCostUSD))
+		} else if maxCost > planned.MaxCostUSD {
+			estimate.Warnings = append(estimate.Warnings, fmt.Sprintf(
+				"if drones run until the timeout the cost can reach $%.2f, over the $%.2f budget", maxCost, planned.MaxCostUSD))
+		}
+	}
+
+	return estimate, nil
+}
@@ -0,0 +1,133 @@
+package orchestrator
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// trackingParams are query parameters that do not change which page a URL points to
+var trackingParams = []string{"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content", "gclid", "fbclid", "ref"}
+
+// dedupeFindings collects the findings of completed drone results and merges
+// those with the same normalized claim text, merging their source lists. It
+// also returns how many duplicates were merged away.
+func dedupeFindings(results []schemas.DroneResult) ([]schemas.Finding, int) {
+	var findings []schemas.Finding
+	index := make(map[string]int)
+	duplicates := 0
+
+	for _, result := range results {
+		if result.Status != "completed" {
+			continue
+		}
+		for _, raw := range resultFindings(result) {
+			key := normalizeClaim(raw.Claim)
+			if key == "" {
+				continue
+			}
+			i, seen := index[key]
+			if !seen {
+				index[key] = len(findings)
+				findings = append(findings, schemas.Finding{Claim: strings.TrimSpace(raw.Claim)})
+				i = len(findings) - 1
+			} else {
+				duplicates++
+			}
+			finding := &findings[i]
+			for _, source := range raw.Sources {
+				if source = normalizeURL(source); source != "" && !containsString(finding.Sources, source) {
+					finding.Sources = append(finding.Sources, source)
+				}
+			}
+			if !containsString(finding.Drones, result.DroneID) {
+				finding.Drones = append(finding.Drones, result.DroneID)
+			}
+		}
+	}
+
+	// Findings more drones agree on come first
+	sort.SliceStable(findings, func(i, j int) bool {
+		return len(findings[i].Drones) > len(findings[j].Drones)
+	})
+	return findings, duplicates
+}
+
+// resultFindings reads the findings a drone reported under "findings" or
+// "key_findings", either as claims or as objects with a claim and its URL or
+// sources. Objects without a claim, text or finding use their title or description.
+func resultFindings(result schemas.DroneResult) []schemas.Finding {
+	var findings []schemas.Finding
+	for _, key := range []string{"findings", "key_findings"} {
+		items, _ := result.Data[key].([]interface{})
+		for _, item := range items {
+			switch v := item.(type) {
+			case string:
+				findings = append(findings, schemas.Finding{Claim: v})
+			case map[string]interface{}:
+				var finding schemas.Finding
+				for _, field := range []string{"claim", "text", "finding", "title", "description"} {
+					if claim, ok := v[field].(string); ok && claim != "" {
+						finding.Claim = claim
+						break
+					}
+				}
+				for _, field := range []string{"url", "source"} {
+					if source, ok := v[field].(string); ok && source != "" {
+						finding.Sources = append(finding.Sources, source)
+					}
+				}
+				if sources, ok := v["sources"].([]interface{}); ok {
+					for _, source := range sources {
+						if s, ok := source.(string); ok {
+							finding.Sources = append(finding.Sources, s)
+						}
+					}
+				}
+				findings = append(findings, finding)
+			}
+		}
+	}
+	return findings
+}
+
+// normalizeClaim reduces a claim to lowercase words, so claims differing only
+// in case, punctuation or spacing compare equal
+func normalizeClaim(claim string) string {
+	words := strings.FieldsFunc(strings.ToLower(claim), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '%' && r != '.'
+	})
+	for i, word := range words {
+		// Keep decimal points but not sentence-ending periods
+		words[i] = strings.Trim(word, ".")
+	}
+	return strings.Join(strings.Fields(strings.Join(words, " ")), " ")
+}
+
+// normalizeURL makes URLs to the same page compare equal: lowercase host
+// without "www.", no fragment, tracking parameters or trailing slash. Text
+// that is not an absolute URL is returned trimmed.
+func normalizeURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme == "http" {
+		u.Scheme = "https"
+	}
+	u.Host = strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	u.Fragment = ""
+	query := u.Query()
+	for _, param := range trackingParams {
+		query.Del(param)
+	}
+	u.RawQuery = query.Encode()
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u.String()
+}
@@ -0,0 +1,45 @@
+package orchestrator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+func TestResultFindings(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]interface{}
+		want []schemas.Finding
+	}{
+		{
+			name: "claims",
+			data: map[string]interface{}{"findings": []interface{}{"a", "b"}},
+			want: []schemas.Finding{{Claim: "a"}, {Claim: "b"}},
+		},
+		{
+			name: "claim with its URL",
+			data: map[string]interface{}{"key_findings": []interface{}{
+				map[string]interface{}{"claim": "a", "url": "https://example.com"},
+			}},
+			want: []schemas.Finding{{Claim: "a", Sources: []string{"https://example.com"}}},
+		},
+		{
+			name: "title and description without a claim",
+			data: map[string]interface{}{"key_findings": []interface{}{
+				map[string]interface{}{"title": "a", "description": "about a"},
+				map[string]interface{}{"description": "about b"},
+			}},
+			want: []schemas.Finding{{Claim: "a"}, {Claim: "about b"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resultFindings(schemas.DroneResult{Data: tt.data})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resultFindings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// githubIssueBodyLimit is the longest issue body GitHub accepts
+const githubIssueBodyLimit = 65536
+
+// GitHubConfig selects the repository completed reports are published to
+type GitHubConfig struct {
+	// Repo is "owner/name"; empty disables publishing
+	Repo  string
+	Token string
+	// Mode is "pr" to add the report as a file in a pull request or "issue" to open an issue
+	Mode string
+	// BaseBranch is the pull request target; empty uses the repository's default branch
+	BaseBranch string
+	// Dir is the directory reports are added to in pull requests
+	Dir string
+	// Labels are added to issues
+	Labels []string
+	// APIURL is the GitHub API root, for GitHub Enterprise
+	APIURL string
+}
+
+// LoadGitHubConfig reads the GitHub publishing settings from the environment
+func LoadGitHubConfig() GitHubConfig {
+	return GitHubConfig{
+		Repo:       getEnvOrDefault("GITHUB_REPORT_REPO", ""),
+		Token:      getEnvOrDefault("GITHUB_TOKEN", ""),
+		Mode:       getEnvOrDefault("GITHUB_REPORT_MODE", "pr"),
+		BaseBranch: getEnvOrDefault("GITHUB_BASE_BRANCH", ""),
+		Dir:        getEnvOrDefault("GITHUB_REPORT_DIR", "research"),
+		Labels:     splitList(getEnvOrDefault("GITHUB_ISSUE_LABELS", "research")),
+		APIURL:     strings.TrimSuffix(getEnvOrDefault("GITHUB_API_URL", "https://api.github.com"), "/"),
+	}
+}
+
+// GitHubPublisher opens a pull request or issue with each completed report
+type GitHubPublisher struct {
+	config GitHubConfig
+	client *http.Client
+}
+
+// NewGitHubPublisher creates a publisher, or returns nil if publishing is not configured
+func NewGitHubPublisher(config GitHubConfig) *GitHubPublisher {
+	if config.Repo == "" {
+		return nil
+	}
+	if config.Token == "" {
+		log.Printf("Warning: GITHUB_TOKEN is not set, reports will not be published to %s", config.Repo)
+		return nil
+	}
+	if !strings.Contains(config.Repo, "/") {
+		log.Printf("Warning: GITHUB_REPORT_REPO %q is not owner/name, reports will not be published", config.Repo)
+		return nil
+	}
+	if config.Mode != "pr" && config.Mode != "issue" {
+		log.Printf("Warning: Unknown GITHUB_REPORT_MODE %q, reports will not be published", config.Mode)
+		return nil
+	}
+
+	return &GitHubPublisher{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// enabled reports whether the publisher can publish
+func (g *GitHubPublisher) enabled() bool {
+	return g != nil
+}
+
+// Publish adds the session's Markdown report to the repository and returns the
+// URL of the pull request or issue
+func (g *GitHubPublisher) Publish(ctx context.Context, event SessionEvent, report []byte) (string, error) {
+	title := fmt.Sprintf("Research report: %s", event.Topic)
+	if g.config.Mode == "issue" {
+		return g.openIssue(ctx, title, report)
+	}
+	return g.openPullRequest(ctx, title, event, report)
+}
+
+// openIssue opens an issue whose body is the report, truncated to GitHub's limit
+func (g *GitHubPublisher) openIssue(ctx context.Context, title string, report []byte) (string, error) {
+	body := string(report)
+	if len(body) > githubIssueBodyLimit {
+		const notice = "\n\n_Report truncated to fit in an issue._"
+		body = body[:githubIssueBodyLimit-len(notice)] + notice
+	}
+
+	var issue struct {
+		HTMLURL string `json:"html_url"`
+	}
+	err := g.do(ctx, http.MethodPost, "/issues", map[string]interface{}{
+		"title":  title,
+		"body":   body,
+		"labels": g.config.Labels,
+	}, &issue)
+	if err != nil {
+		return "", fmt.Errorf("failed to open issue: %w", err)
+	}
+	return issue.HTMLURL, nil
+}
+
+// openPullRequest commits the report to a new branch and opens a pull request for it
+func (g *GitHubPublisher) openPullRequest(ctx context.Context, title string, event SessionEvent, report []byte) (string, error) {
+	base := g.config.BaseBranch
+	if base == "" {
+		var repo struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := g.do(ctx, http.MethodGet, "", nil, &repo); err != nil {
+			return "", fmt.Errorf("failed to get repository: %w", err)
+		}
+		base = repo.DefaultBranch
+	}
+
+	var ref struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := g.do(ctx, http.MethodGet, "/git/ref/heads/"+base, nil, &ref); err != nil {
+		return "", fmt.Errorf("failed to get branch %s: %w", base, err)
+	}
+
+	branch := "research/" + event.SessionID
+	err := g.do(ctx, http.MethodPost, "/git/refs", map[string]string{
+		"ref": "refs/heads/" + branch,
+		"sha": ref.Object.SHA,
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+
+	filePath := path.Join(g.config.Dir, fmt.Sprintf("%s-%s.md", event.FinishedAt.Format("2006-01-02"), event.SessionID))
+	err = g.do(ctx, http.MethodPut, "/contents/"+filePath, map[string]string{
+		"message": title,
+		"content": base64.StdEncoding.EncodeToString(report),
+		"branch":  branch,
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to add %s: %w", filePath, err)
+	}
+
+	var pull struct {
+		HTMLURL string `json:"html_url"`
+	}
+	err = g.do(ctx, http.MethodPost, "/pulls", map[string]string{
+		"title": title,
+		"head":  branch,
+		"base":  base,
+		"body":  event.message(),
+	}, &pull)
+	if err != nil {
+		return "", fmt.Errorf("failed to open pull request: %w", err)
+	}
+	return pull.HTMLURL, nil
+}
+
+// do calls a repository endpoint, encoding in as the request body and decoding the response into out
+func (g *GitHubPublisher) do(ctx context.Context, method, endpoint string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s%s", g.config.APIURL, g.config.Repo, endpoint)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+g.config.Token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("GitHub returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spawn-mcp/coordinator/pkg/health"
+)
+
+// statusTopic is a topic Initialize creates, which the Pub/Sub readiness check looks up
+const statusTopic = "research-status"
+
+// AddHealthChecks adds the readiness checks of the orchestrator's components
+// to checker. Clients that are not configured, as in simulation, are not checked.
+func (o *Orchestrator) AddHealthChecks(checker *health.Checker) {
+	checker.Add("orchestrator", func(ctx context.Context) error {
+		o.mu.RLock()
+		defer o.mu.RUnlock()
+		if !o.initialized {
+			return errors.New("not initialized")
+		}
+		if o.draining {
+			return ErrDraining
+		}
+		return nil
+	})
+	if o.firestoreClient != nil {
+		checker.Add("firestore", health.FirestoreCheck(o.firestoreClient))
+	}
+	if o.pubsubClient != nil {
+		checker.Add("pubsub", health.PubSubTopicCheck(o.pubsubClient.Topic(statusTopic)))
+	}
+	// Sessions run without external tools when their servers are down
+	checker.AddOptional("external_mcp", o.mcpClient.Ping)
+}
+
+// setInitialized marks the orchestrator ready to serve
+func (o *Orchestrator) setInitialized() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.initialized = true
+}
+
+// Initialized reports whether Initialize has succeeded
+func (o *Orchestrator) Initialized() bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.initialized
+}
+
+// Ping checks that every registered external MCP server answers a ping. A
+// server whose sessions are all in use is taken to be alive.
+func (c *MCPClient) Ping(ctx context.Context) error {
+	c.mu.RLock()
+	servers := make([]*externalServer, 0, len(c.servers))
+	for _, server := range c.servers {
+		servers = append(servers, server)
+	}
+	c.mu.RUnlock()
+
+	var failed []string
+	for _, server := range servers {
+		var sess *session
+		select {
+		case sess = <-server.pool.idle:
+		default:
+			continue
+		}
+		err := c.sessionClient(sess).Ping(ctx)
+		server.pool.checkin(sess)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", server.name, err))
+		}
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return errors.New(strings.Join(failed, "; "))
+	}
+	return nil
+}
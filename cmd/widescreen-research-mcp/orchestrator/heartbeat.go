@@ -0,0 +1,244 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// HeartbeatConfig configures how silent drones are detected and replaced
+type HeartbeatConfig struct {
+	// Topic is the Pub/Sub topic drones publish heartbeats to
+	Topic string
+	// Interval is how often drones publish a heartbeat
+	Interval time.Duration
+	// MissedLimit is how many heartbeats in a row a drone may miss before it is terminated
+	MissedLimit int
+	// MaxReplacements caps how many times one sub-query is moved to a new drone; 0 disables replacement
+	MaxReplacements int
+}
+
+// LoadHeartbeatConfig reads the heartbeat configuration from the environment
+func LoadHeartbeatConfig() HeartbeatConfig {
+	config := HeartbeatConfig{
+		Topic:           getEnvOrDefault("HEARTBEAT_TOPIC", types.DefaultHeartbeatTopic),
+		Interval:        15 * time.Second,
+		MissedLimit:     3,
+		MaxReplacements: 2,
+	}
+	if seconds, err := strconv.Atoi(getEnvOrDefault("HEARTBEAT_INTERVAL_SEC", "")); err == nil && seconds > 0 {
+		config.Interval = time.Duration(seconds) * time.Second
+	}
+	if limit, err := strconv.Atoi(getEnvOrDefault("HEARTBEAT_MISSED_LIMIT", "")); err == nil && limit > 0 {
+		config.MissedLimit = limit
+	}
+	if max, err := strconv.Atoi(getEnvOrDefault("DRONE_MAX_REPLACEMENTS", "")); err == nil && max >= 0 {
+		config.MaxReplacements = max
+	}
+	return config
+}
+
+// silentAfter is how long a drone may go without checking in before it is considered dead
+func (c HeartbeatConfig) silentAfter() time.Duration {
+	return c.Interval * time.Duration(c.MissedLimit)
+}
+
+// startHeartbeatReceiver records the heartbeats drones publish until Shutdown.
+// Each orchestrator instance has its own subscription, as every instance
+// needs every heartbeat.
+func (o *Orchestrator) startHeartbeatReceiver(ctx context.Context) error {
+	topic := o.pubsubClient.Topic(o.heartbeat.Topic)
+
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "orchestrator"
+	}
+	subscriptionName := fmt.Sprintf("%s-sub-%s", o.heartbeat.Topic, strings.ToLower(host))
+	subscription := o.pubsubClient.Subscription(subscriptionName)
+	exists, err := subscription.Exists(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check subscription %s: %w", subscriptionName, err)
+	}
+	if !exists {
+		// Heartbeats are only useful while fresh, and subscriptions of
+		// instances that are gone expire on their own
+		subscription, err = o.pubsubClient.CreateSubscription(ctx, subscriptionName, pubsub.SubscriptionConfig{
+			Topic:             topic,
+			AckDeadline:       10 * time.Second,
+			RetentionDuration: 10 * time.Minute,
+			ExpirationPolicy:  24 * time.Hour,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create subscription %s: %w", subscriptionName, err)
+		}
+	}
+
+	receiveCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	o.heartbeatCancel = cancel
+	o.heartbeatDone = make(chan struct{})
+	go func() {
+		defer close(o.heartbeatDone)
+		err := subscription.Receive(receiveCtx, func(ctx context.Context, msg *pubsub.Message) {
+			msg.Ack()
+			var heartbeat types.DroneHeartbeat
+			if err := json.Unmarshal(msg.Data, &heartbeat); err != nil {
+				log.Printf("Warning: Failed to decode drone heartbeat: %v", err)
+				return
+			}
+			o.recordHeartbeat(heartbeat)
+		})
+		if err != nil {
+			log.Printf("Warning: Stopped receiving drone heartbeats: %v", err)
+		}
+	}()
+	return nil
+}
+
+// stopHeartbeatReceiver stops recording heartbeats, if it was started
+func (o *Orchestrator) stopHeartbeatReceiver() {
+	if o.heartbeatCancel == nil {
+		return
+	}
+	o.heartbeatCancel()
+	<-o.heartbeatDone
+	o.heartbeatCancel = nil
+}
+
+// recordHeartbeat marks the drone that sent a heartbeat as alive
+func (o *Orchestrator) recordHeartbeat(heartbeat types.DroneHeartbeat) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, session := range o.activeSessions {
+		if drone, ok := session.Drones[heartbeat.DroneID]; ok {
+			// The drone's clock may differ from ours, so use the time of receipt
+			drone.LastCheckin = time.Now()
+			return
+		}
+	}
+}
+
+// replaceSilentDrones terminates drones working on a sub-query that have
+// neither sent a heartbeat nor passed a health check for too long, and moves
+// their sub-query to a new drone
+func (o *Orchestrator) replaceSilentDrones(ctx context.Context, session *ResearchSession) {
+	silentAfter := o.heartbeat.silentAfter()
+
+	var silent []*DroneInfo
+	o.mu.Lock()
+	// Drones of a session being cleaned up are deleted, not replaced
+	if session.cleaning {
+		o.mu.Unlock()
+		return
+	}
+	for _, drone := range session.Drones {
+		if drone.Task == nil || (drone.Status != "running" && drone.Status != "unhealthy") {
+			continue
+		}
+		if time.Since(drone.LastCheckin) > silentAfter {
+			drone.Status = "unresponsive"
+			silent = append(silent, drone)
+		}
+	}
+	session.replacements.Add(len(silent))
+	o.mu.Unlock()
+
+	for _, drone := range silent {
+		log.Printf("Drone %s missed %d heartbeats, terminating it", drone.ID, o.heartbeat.MissedLimit)
+		go func(drone *DroneInfo) {
+			defer session.replacements.Done()
+			o.replaceDrone(ctx, session, drone)
+		}(drone)
+	}
+}
+
+// replaceDrone deletes a dead drone and, unless its sub-query has been moved
+// too often or the session is out of budget, deploys a new drone in the same
+// region and sends it the same sub-query. Sessions with a dispatcher move all
+// the tasks the dead drone had not reported on, or hand them to the session's
+// other drones when no replacement is deployed.
+func (o *Orchestrator) replaceDrone(ctx context.Context, session *ResearchSession, dead *DroneInfo) {
+	if err := o.deleteDroneService(context.WithoutCancel(ctx), dead.ID, dead.Region); err != nil {
+		log.Printf("Warning: failed to delete unresponsive drone %s: %v", dead.ID, err)
+	}
+
+	o.mu.RLock()
+	dispatcher := session.dispatcher
+	o.mu.RUnlock()
+	redistribute := func(droneID string) {
+		if dispatcher != nil {
+			dispatcher.requeue(droneID)
+			o.dispatchPending(ctx, session)
+		}
+	}
+
+	droneID, attempt := replacementID(dead.ID)
+	if attempt > o.heartbeat.MaxReplacements {
+		log.Printf("Warning: not replacing drone %s, its sub-query has already been moved %d times", dead.ID, attempt-1)
+		redistribute(dead.ID)
+		return
+	}
+	if o.enforceBudget(session) {
+		return
+	}
+
+	serviceURL, err := o.deployDrone(ctx, droneID, dead.Region, session.Config)
+	if err != nil {
+		log.Printf("Warning: failed to deploy replacement for drone %s: %v", dead.ID, err)
+		redistribute(dead.ID)
+		return
+	}
+
+	o.mu.Lock()
+	dead.Status = "replaced"
+	drone := &DroneInfo{
+		ID:          droneID,
+		ServiceURL:  serviceURL,
+		Region:      dead.Region,
+		Status:      "deployed",
+		StartTime:   time.Now(),
+		LastCheckin: time.Now(),
+		Task:        dead.Task,
+	}
+	session.Drones[droneID] = drone
+	o.mu.Unlock()
+
+	if dispatcher != nil {
+		if tasks := dispatcher.reassign(dead.ID, droneID); len(tasks) > 0 {
+			err = o.sendTasksToDrone(ctx, drone, tasks)
+		}
+	} else {
+		err = o.sendInstructionsToDrone(ctx, drone, drone.Task)
+	}
+	o.mu.Lock()
+	if err != nil {
+		drone.Status = "failed_to_instruct"
+	} else {
+		drone.Status = "running"
+	}
+	o.mu.Unlock()
+	if err != nil {
+		log.Printf("Failed to send instructions to replacement drone %s: %v", droneID, err)
+		redistribute(droneID)
+		return
+	}
+	log.Printf("Replaced unresponsive drone %s with %s", dead.ID, droneID)
+}
+
+// replacementID names the drone replacing droneID, counting replacements in
+// an -rN suffix, and returns which replacement it is
+func replacementID(droneID string) (string, int) {
+	if i := strings.LastIndex(droneID, "-r"); i >= 0 {
+		if n, err := strconv.Atoi(droneID[i+2:]); err == nil && n > 0 {
+			return fmt.Sprintf("%s-r%d", droneID[:i], n+1), n + 1
+		}
+	}
+	return droneID + "-r1", 1
+}
@@ -0,0 +1,66 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// silentSession returns a simulated session with one drone that has not
+// checked in for an hour
+func silentSession() *ResearchSession {
+	return &ResearchSession{
+		Config: &schemas.ResearchConfig{SessionID: "s1", Simulate: true},
+		Drones: map[string]*DroneInfo{
+			"d1": {ID: "d1", Status: "running", LastCheckin: time.Now().Add(-time.Hour), Task: map[string]interface{}{}, Simulated: true},
+		},
+		Queue: NewResearchQueue("", "s1", 1),
+	}
+}
+
+func TestReplaceSilentDronesSkipsSessionsBeingCleanedUp(t *testing.T) {
+	o := &Orchestrator{heartbeat: HeartbeatConfig{Interval: time.Second, MissedLimit: 3}}
+	session := silentSession()
+	session.cleaning = true
+
+	o.replaceSilentDrones(context.Background(), session)
+	if status := session.Drones["d1"].Status; status != "running" {
+		t.Errorf("drone status = %q, want running: drones of a session being cleaned up are not replaced", status)
+	}
+}
+
+func TestCleanupSessionWaitsForReplacements(t *testing.T) {
+	o := &Orchestrator{activeSessions: map[string]*ResearchSession{}}
+	session := silentSession()
+	o.activeSessions["s1"] = session
+
+	// A replacement still deploying when the session ends
+	session.replacements.Add(1)
+	done := make(chan struct{})
+	go func() {
+		o.cleanupSession(context.Background(), session)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("cleanupSession() returned while a replacement was still deploying")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	o.mu.Lock()
+	session.Drones["d1-r1"] = &DroneInfo{ID: "d1-r1", Status: "running", Simulated: true}
+	o.mu.Unlock()
+	session.replacements.Done()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cleanupSession() did not return after the replacement finished")
+	}
+	if _, ok := o.activeSessions["s1"]; ok {
+		t.Error("session is still active after cleanup")
+	}
+}
@@ -0,0 +1,107 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// instructionMaxBackoff caps the wait between two attempts to instruct a drone
+const instructionMaxBackoff = 10 * time.Second
+
+// InstructionRetryConfig bounds the retries of drone instructions that fail to
+// arrive, such as while a new Cloud Run revision is still starting
+type InstructionRetryConfig struct {
+	// Attempts is how many times a task is sent before the drone is given up on
+	Attempts int
+	// Backoff is the wait before the first retry, doubled after each further one
+	Backoff time.Duration
+}
+
+// LoadInstructionRetryConfig reads the drone instruction retries from the environment
+func LoadInstructionRetryConfig() InstructionRetryConfig {
+	config := InstructionRetryConfig{
+		Attempts: 4,
+		Backoff:  500 * time.Millisecond,
+	}
+	if n, err := strconv.Atoi(getEnvOrDefault("INSTRUCTION_RETRY_ATTEMPTS", "")); err == nil && n > 0 {
+		config.Attempts = n
+	}
+	if ms, err := strconv.Atoi(getEnvOrDefault("INSTRUCTION_RETRY_BACKOFF_MS", "")); err == nil && ms >= 0 {
+		config.Backoff = time.Duration(ms) * time.Millisecond
+	}
+	return config
+}
+
+// instructionStatusError is a drone's refusal of a task
+type instructionStatusError struct {
+	status int
+}
+
+func (e *instructionStatusError) Error() string {
+	return fmt.Sprintf("failed to send instructions, status: %d", e.status)
+}
+
+// transientInstructionError reports whether sending a task again may succeed:
+// connection failures and timeouts, and responses that mean the drone is busy
+// or not serving yet. Other refusals, and the caller giving up, are final.
+func transientInstructionError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *instructionStatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.status {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests:
+			return true
+		}
+		return statusErr.status >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// retryInstructions calls send until it succeeds, it fails with an error that
+// is not transient, or the retry budget is exhausted. Waits between attempts
+// back off exponentially with full jitter, so drones started together are not
+// retried in lockstep.
+func (o *Orchestrator) retryInstructions(ctx context.Context, drone *DroneInfo, send func() error) error {
+	attempts := o.instructionRetry.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := o.instructionRetry.Backoff
+
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		if err = send(); err == nil {
+			return nil
+		}
+		if attempt >= attempts || !transientInstructionError(ctx, err) {
+			break
+		}
+
+		wait := time.Duration(0)
+		if backoff > 0 {
+			wait = time.Duration(rand.Int63n(int64(backoff)) + 1)
+		}
+		log.Printf("Warning: Failed to instruct drone %s (attempt %d of %d): %v, retrying in %v", drone.ID, attempt, attempts, err, wait.Round(time.Millisecond))
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (retry cancelled: %v)", err, ctx.Err())
+		case <-time.After(wait):
+		}
+		if backoff *= 2; backoff > instructionMaxBackoff {
+			backoff = instructionMaxBackoff
+		}
+	}
+	if attempt > 1 {
+		return fmt.Errorf("after %d attempts: %w", attempt, err)
+	}
+	return err
+}
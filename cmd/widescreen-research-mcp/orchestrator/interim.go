@@ -0,0 +1,80 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// ResultListener is told about each drone result a session collects, so
+// findings can be shown before the report is ready. It must not block.
+type ResultListener func(schemas.InterimResult)
+
+// resultListenerKey is the context key of a call's ResultListener
+type resultListenerKey struct{}
+
+// WithResultListener returns a context whose research sessions tell listener
+// about each result as it is collected
+func WithResultListener(ctx context.Context, listener ResultListener) context.Context {
+	return context.WithValue(ctx, resultListenerKey{}, listener)
+}
+
+// resultListenerFrom returns the ResultListener WithResultListener gave ctx, or nil
+func resultListenerFrom(ctx context.Context) ResultListener {
+	listener, _ := ctx.Value(resultListenerKey{}).(ResultListener)
+	return listener
+}
+
+// interimResult summarizes a collected drone result for the client
+func interimResult(session *ResearchSession, result schemas.DroneResult, collected int) schemas.InterimResult {
+	summary := leafSummary(result)
+	return schemas.InterimResult{
+		SessionID:   session.Config.SessionID,
+		DroneID:     result.DroneID,
+		TaskID:      result.TaskID,
+		Status:      result.Status,
+		Summary:     summary.Summary,
+		KeyFindings: summary.KeyFindings,
+		Sources:     summary.Sources,
+		Error:       result.Error,
+		Collected:   collected,
+		Expected:    expectedResults(session),
+		CompletedAt: result.CompletedAt,
+	}
+}
+
+// InterimResults returns the findings of each drone result a session has
+// collected so far. Sessions that are no longer running are read from Firestore.
+func (o *Orchestrator) InterimResults(ctx context.Context, sessionID string) ([]schemas.InterimResult, error) {
+	o.mu.RLock()
+	session := o.activeSessions[sessionID]
+	var results []schemas.DroneResult
+	if session != nil && session.Config.Tenant == o.tenantID(ctx) {
+		results = make([]schemas.DroneResult, len(session.Results))
+		copy(results, session.Results)
+	} else {
+		session = nil
+	}
+	o.mu.RUnlock()
+
+	if session == nil {
+		if o.firestoreClient == nil {
+			return nil, fmt.Errorf("session %s is not running", sessionID)
+		}
+		config, err := o.loadSessionConfig(ctx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if results, err = o.loadSessionResults(ctx, sessionID); err != nil {
+			return nil, err
+		}
+		session = &ResearchSession{Config: config}
+	}
+
+	interim := make([]schemas.InterimResult, 0, len(results))
+	for i, result := range results {
+		interim = append(interim, interimResult(session, result, i+1))
+	}
+	return interim, nil
+}
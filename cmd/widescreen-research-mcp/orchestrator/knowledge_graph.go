@@ -0,0 +1,433 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/entities"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+	"github.com/spawn-mcp/coordinator/pkg/payload"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// knowledgeGraphsCollection holds each session's knowledge graph, keyed by session ID
+const knowledgeGraphsCollection = "knowledge_graphs"
+
+// Reasons an entity is a coverage gap
+const (
+	gapNotCovered   = "not_covered"   // named in the topic but in no finding
+	gapSingleSource = "single_source" // its findings cite one source at most
+	gapUnconnected  = "unconnected"   // no finding relates it to another entity
+)
+
+// Graph export formats
+const (
+	GraphFormatJSON    = "json"
+	GraphFormatGraphML = "graphml"
+	GraphFormatDOT     = "dot"
+)
+
+const (
+	// mentionedWith relates entities named in the same claim without words that link them
+	mentionedWith = "mentioned_with"
+
+	// maxClaimEntities is the most entities of one claim that are related to each other
+	maxClaimEntities = 8
+	// maxPredicateWords is the most words between two entities taken as their relation
+	maxPredicateWords = 4
+	// maxRelationClaims is the most claims kept as evidence of a relation
+	maxRelationClaims = 5
+	// maxCoverageGaps is the most gaps a graph reports, so the most follow-up sub-queries
+	maxCoverageGaps = 10
+	// maxInlineGraphBytes is the largest graph stored in its Firestore document,
+	// under Firestore's 1 MiB limit; larger graphs go to RESULT_BUCKET
+	maxInlineGraphBytes = 800 * 1024
+)
+
+// claimEntity is an entity named in a claim, with where its name appears
+type claimEntity struct {
+	schemas.GraphEntity
+	start, end int
+}
+
+// buildKnowledgeGraph links the entities the findings name by the relations
+// the findings state between them, and finds where the topic is covered thinly
+func buildKnowledgeGraph(topic string, findings []schemas.Finding) *schemas.KnowledgeGraph {
+	graph := &schemas.KnowledgeGraph{
+		Topic:     topic,
+		Entities:  []schemas.GraphEntity{},
+		Relations: []schemas.GraphRelation{},
+	}
+	entityIndex := make(map[string]int)
+	relationIndex := make(map[string]int)
+
+	for _, finding := range findings {
+		named := claimEntities(finding.Claim)
+		for _, entity := range named {
+			i, seen := entityIndex[entity.ID]
+			if !seen {
+				i = len(graph.Entities)
+				entityIndex[entity.ID] = i
+				graph.Entities = append(graph.Entities, schemas.GraphEntity{ID: entity.ID, Name: entity.Name, Type: entity.Type})
+			}
+			graph.Entities[i].Mentions++
+			graph.Entities[i].Sources = mergeSources(graph.Entities[i].Sources, finding.Sources)
+		}
+
+		// Neighbouring entities are related by the words between them; the
+		// others are only known to be mentioned together
+		for a := 0; a < len(named); a++ {
+			for b := a + 1; b < len(named); b++ {
+				predicate := mentionedWith
+				if b == a+1 {
+					predicate = relationPredicate(finding.Claim, named[a], named[b])
+				}
+				key := named[a].ID + "|" + predicate + "|" + named[b].ID
+				i, seen := relationIndex[key]
+				if !seen {
+					i = len(graph.Relations)
+					relationIndex[key] = i
+					graph.Relations = append(graph.Relations, schemas.GraphRelation{Source: named[a].ID, Target: named[b].ID, Predicate: predicate})
+				}
+				relation := &graph.Relations[i]
+				if len(relation.Claims) < maxRelationClaims && !containsString(relation.Claims, finding.Claim) {
+					relation.Claims = append(relation.Claims, finding.Claim)
+				}
+				relation.Sources = mergeSources(relation.Sources, finding.Sources)
+			}
+		}
+	}
+
+	sort.SliceStable(graph.Entities, func(i, j int) bool {
+		return graph.Entities[i].Mentions > graph.Entities[j].Mentions
+	})
+	sort.SliceStable(graph.Relations, func(i, j int) bool {
+		return len(graph.Relations[i].Sources) > len(graph.Relations[j].Sources)
+	})
+	graph.Gaps = coverageGaps(graph)
+	return graph
+}
+
+// claimEntities returns the entities a claim names, in the order they appear
+func claimEntities(claim string) []claimEntity {
+	var named []claimEntity
+	seen := make(map[string]bool)
+	for _, entity := range entities.InText(claim) {
+		id := graphEntityID(entity.Type, entity.Name)
+		start := strings.Index(claim, entity.Name)
+		if seen[id] || start < 0 {
+			continue
+		}
+		seen[id] = true
+		named = append(named, claimEntity{
+			GraphEntity: schemas.GraphEntity{ID: id, Name: entity.Name, Type: entity.Type},
+			start:       start,
+			end:         start + len(entity.Name),
+		})
+	}
+	sort.Slice(named, func(i, j int) bool { return named[i].start < named[j].start })
+	if len(named) > maxClaimEntities {
+		named = named[:maxClaimEntities]
+	}
+	return named
+}
+
+// graphEntityID identifies an entity by its type and name, ignoring case
+func graphEntityID(entityType, name string) string {
+	return entityType + ":" + strings.ToLower(name)
+}
+
+// relationPredicate returns the few words linking two entities in a claim,
+// such as "acquired" or "partnered with", or mentionedWith if there are none
+func relationPredicate(claim string, from, to claimEntity) string {
+	if from.end > to.start {
+		return mentionedWith
+	}
+	between := claim[from.end:to.start]
+	words := strings.Fields(between)
+	if len(words) == 0 || len(words) > maxPredicateWords || strings.ContainsAny(between, ",;:()") {
+		return mentionedWith
+	}
+	return strings.ToLower(strings.Join(words, " "))
+}
+
+// mergeSources adds the sources not yet in merged
+func mergeSources(merged, sources []string) []string {
+	for _, source := range sources {
+		if !containsString(merged, source) {
+			merged = append(merged, source)
+		}
+	}
+	return merged
+}
+
+// coverageGaps finds the entities of the topic no finding names, then the
+// most mentioned entities that rest on a single source or relate to nothing
+func coverageGaps(graph *schemas.KnowledgeGraph) []schemas.CoverageGap {
+	var gaps []schemas.CoverageGap
+	known := make(map[string]bool, len(graph.Entities))
+	for _, entity := range graph.Entities {
+		known[strings.ToLower(entity.Name)] = true
+	}
+	for _, entity := range entities.InText(graph.Topic) {
+		if !known[strings.ToLower(entity.Name)] {
+			known[strings.ToLower(entity.Name)] = true
+			gaps = append(gaps, schemas.CoverageGap{Entity: entity.Name, Reason: gapNotCovered})
+		}
+	}
+
+	related := make(map[string]bool)
+	for _, relation := range graph.Relations {
+		related[relation.Source] = true
+		related[relation.Target] = true
+	}
+	for _, entity := range graph.Entities {
+		switch {
+		case len(entity.Sources) <= 1:
+			gaps = append(gaps, schemas.CoverageGap{EntityID: entity.ID, Entity: entity.Name, Reason: gapSingleSource})
+		case len(graph.Entities) > 1 && !related[entity.ID]:
+			gaps = append(gaps, schemas.CoverageGap{EntityID: entity.ID, Entity: entity.Name, Reason: gapUnconnected})
+		}
+	}
+
+	if len(gaps) > maxCoverageGaps {
+		gaps = gaps[:maxCoverageGaps]
+	}
+	return gaps
+}
+
+// coverageGapsSection lists a graph's gaps and the follow-up sub-queries that would close them
+func coverageGapsSection(graph *schemas.KnowledgeGraph) schemas.ReportSection {
+	reasons := map[string]string{
+		gapNotCovered:   "named in the topic but in no finding",
+		gapSingleSource: "supported by a single source",
+		gapUnconnected:  "not related to any other entity",
+	}
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("The knowledge graph of this session has %d entities and %d relations. These parts of the topic are covered thinly:\n\n",
+		len(graph.Entities), len(graph.Relations)))
+	for _, gap := range graph.Gaps {
+		content.WriteString(fmt.Sprintf("- **%s**: %s\n", gap.Entity, reasons[gap.Reason]))
+	}
+	if len(graph.FollowUpQueries) > 0 {
+		content.WriteString("\nFollow-up sub-queries:\n\n")
+		for _, query := range graph.FollowUpQueries {
+			content.WriteString(fmt.Sprintf("- %s\n", query))
+		}
+	}
+	return schemas.ReportSection{Title: "Coverage Gaps", Content: content.String()}
+}
+
+// completeGraph fills in a session's graph, asks agent for sub-queries that
+// would close its coverage gaps, and stores it
+func (o *Orchestrator) completeGraph(ctx context.Context, session *ResearchSession, agent *ClaudeAgent, graph *schemas.KnowledgeGraph) {
+	graph.SessionID = session.Config.SessionID
+	graph.Tenant = session.Config.Tenant
+	graph.CreatedAt = time.Now()
+	if len(graph.Gaps) > 0 {
+		queries, err := agent.GenerateFollowUpQueries(ctx, session.Config.Topic, graph.Gaps)
+		if err != nil {
+			log.Printf("Warning: Failed to generate follow-up sub-queries: %v", err)
+		}
+		graph.FollowUpQueries = queries
+		tokens := 0
+		for _, query := range queries {
+			tokens += estimateTokens(query)
+		}
+		session.Budget.addLLMTokens(tokens)
+	}
+	if err := o.storeGraph(ctx, graph); err != nil {
+		log.Printf("Warning: Failed to store knowledge graph of session %s: %v", graph.SessionID, err)
+	}
+}
+
+// storeGraph keeps a session's knowledge graph for export-graph. Graphs too
+// large for a Firestore document are stored in RESULT_BUCKET, next to the
+// session's offloaded results, with the document pointing to them.
+func (o *Orchestrator) storeGraph(ctx context.Context, graph *schemas.KnowledgeGraph) error {
+	o.mu.Lock()
+	o.graphs[graph.SessionID] = graph
+	o.mu.Unlock()
+	if o.firestoreClient == nil {
+		return nil
+	}
+
+	stored := *graph
+	encoded, err := json.Marshal(graph)
+	if err != nil {
+		return fmt.Errorf("failed to marshal knowledge graph: %w", err)
+	}
+	if len(encoded) > maxInlineGraphBytes {
+		if o.payloads == nil || o.payloads.Bucket() == "" {
+			return fmt.Errorf("knowledge graph of %d bytes is too large for Firestore and RESULT_BUCKET is not set", len(encoded))
+		}
+		object := path.Join(payload.ObjectPrefix(resultsTopicName(graph.Tenant, graph.SessionID)), "knowledge_graph.json.gz")
+		if stored.DataRef, err = o.payloads.Store(ctx, object, graph); err != nil {
+			return fmt.Errorf("failed to store knowledge graph: %w", err)
+		}
+		stored.Entities, stored.Relations = nil, nil
+	}
+	if _, err := o.collection(graph.Tenant, knowledgeGraphsCollection).Doc(graph.SessionID).Set(ctx, &stored); err != nil {
+		return fmt.Errorf("failed to store knowledge graph: %w", err)
+	}
+	return nil
+}
+
+// loadGraph reads a session's knowledge graph, from memory if this process built it
+func (o *Orchestrator) loadGraph(ctx context.Context, sessionID string) (*schemas.KnowledgeGraph, error) {
+	tenantID := o.tenantID(ctx)
+	o.mu.RLock()
+	graph, ok := o.graphs[sessionID]
+	o.mu.RUnlock()
+	if ok && graph.Tenant == tenantID {
+		return graph, nil
+	}
+	if o.firestoreClient == nil {
+		return nil, mcperrors.New(mcperrors.CategoryNotFound, "no knowledge graph for session %s", sessionID)
+	}
+
+	doc, err := o.collection(tenantID, knowledgeGraphsCollection).Doc(sessionID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, mcperrors.New(mcperrors.CategoryNotFound, "no knowledge graph for session %s", sessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load knowledge graph of session %s: %w", sessionID, err)
+	}
+	graph = &schemas.KnowledgeGraph{}
+	if err := doc.DataTo(graph); err != nil {
+		return nil, fmt.Errorf("failed to decode knowledge graph of session %s: %w", sessionID, err)
+	}
+	if graph.DataRef != "" {
+		dataRef := graph.DataRef
+		if err := o.payloads.Load(ctx, payload.ObjectPrefix(resultsTopicName(tenantID, sessionID)), dataRef, graph); err != nil {
+			return nil, fmt.Errorf("failed to load knowledge graph of session %s: %w", sessionID, err)
+		}
+		graph.DataRef = dataRef
+	}
+	return graph, nil
+}
+
+// ExportGraph returns a session's knowledge graph in format: the graph itself
+// for json, or a document for graphml and dot
+func (o *Orchestrator) ExportGraph(ctx context.Context, sessionID, format string) (interface{}, error) {
+	if format == "" {
+		format = GraphFormatJSON
+	}
+	if format != GraphFormatJSON && format != GraphFormatGraphML && format != GraphFormatDOT {
+		return nil, mcperrors.InvalidInput("unknown graph format %q, expected json, graphml or dot", format)
+	}
+	graph, err := o.loadGraph(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var content string
+	switch format {
+	case GraphFormatJSON:
+		return graph, nil
+	case GraphFormatGraphML:
+		content, err = graphML(graph)
+	case GraphFormatDOT:
+		content = graphDOT(graph)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"session_id": sessionID,
+		"format":     format,
+		"entities":   len(graph.Entities),
+		"relations":  len(graph.Relations),
+		"content":    content,
+	}, nil
+}
+
+// graphML renders a graph as GraphML, which Gephi, yEd and Neo4j import
+func graphML(graph *schemas.KnowledgeGraph) (string, error) {
+	type data struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+	type node struct {
+		ID   string `xml:"id,attr"`
+		Data []data `xml:"data"`
+	}
+	type edge struct {
+		Source string `xml:"source,attr"`
+		Target string `xml:"target,attr"`
+		Data   []data `xml:"data"`
+	}
+	type key struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+	document := struct {
+		XMLName xml.Name `xml:"graphml"`
+		XMLNS   string   `xml:"xmlns,attr"`
+		Keys    []key    `xml:"key"`
+		Graph   struct {
+			ID          string `xml:"id,attr"`
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []node `xml:"node"`
+			Edges       []edge `xml:"edge"`
+		} `xml:"graph"`
+	}{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []key{
+			{ID: "name", For: "node", Name: "name", Type: "string"},
+			{ID: "type", For: "node", Name: "type", Type: "string"},
+			{ID: "mentions", For: "node", Name: "mentions", Type: "int"},
+			{ID: "sources", For: "all", Name: "sources", Type: "string"},
+			{ID: "predicate", For: "edge", Name: "predicate", Type: "string"},
+			{ID: "claims", For: "edge", Name: "claims", Type: "string"},
+		},
+	}
+	document.Graph.ID = graph.SessionID
+	document.Graph.EdgeDefault = "directed"
+	for _, entity := range graph.Entities {
+		document.Graph.Nodes = append(document.Graph.Nodes, node{ID: entity.ID, Data: []data{
+			{Key: "name", Value: entity.Name},
+			{Key: "type", Value: entity.Type},
+			{Key: "mentions", Value: fmt.Sprint(entity.Mentions)},
+			{Key: "sources", Value: strings.Join(entity.Sources, " ")},
+		}})
+	}
+	for _, relation := range graph.Relations {
+		document.Graph.Edges = append(document.Graph.Edges, edge{Source: relation.Source, Target: relation.Target, Data: []data{
+			{Key: "predicate", Value: relation.Predicate},
+			{Key: "claims", Value: strings.Join(relation.Claims, "\n")},
+			{Key: "sources", Value: strings.Join(relation.Sources, " ")},
+		}})
+	}
+
+	encoded, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to render GraphML: %w", err)
+	}
+	return xml.Header + string(encoded) + "\n", nil
+}
+
+// graphDOT renders a graph in Graphviz's DOT language
+func graphDOT(graph *schemas.KnowledgeGraph) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("digraph %q {\n", graph.Topic))
+	for _, entity := range graph.Entities {
+		b.WriteString(fmt.Sprintf("  %q [label=%q, type=%q];\n", entity.ID, entity.Name, entity.Type))
+	}
+	for _, relation := range graph.Relations {
+		b.WriteString(fmt.Sprintf("  %q -> %q [label=%q];\n", relation.Source, relation.Target, relation.Predicate))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
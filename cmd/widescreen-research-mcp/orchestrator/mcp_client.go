@@ -2,35 +2,419 @@ package orchestrator
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log"
+	"os"
+	"sort"
 	"sync"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Transports supported for remote MCP servers
+const (
+	TransportStreamableHTTP = "http"
+	TransportSSE            = "sse"
 )
 
-// MCPClient manages connections to other MCP servers.
-// TODO: This implementation is a stub and needs to be fixed to align with the
-// current version of the 'github.com/mark3labs/mcp-go' library.
+// ExternalServerConfig describes how to reach an external MCP server.
+// Either Command (stdio subprocess) or URL (remote server) must be set.
+type ExternalServerConfig struct {
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	// Transport selects the remote protocol: "http" (streamable HTTP, default) or "sse"
+	Transport string `json:"transport,omitempty"`
+	// BearerToken is sent as an Authorization header; $VAR references are expanded
+	BearerToken string            `json:"bearer_token,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	// PoolSize is the number of concurrent sessions (subprocesses for stdio servers), default 1
+	PoolSize int `json:"pool_size,omitempty"`
+}
+
+// ExternalServersConfig is the on-disk format of the external MCP server registry
+type ExternalServersConfig struct {
+	Servers map[string]ExternalServerConfig `json:"mcpServers"`
+}
+
+// externalServer is a registered external MCP server and its live sessions
+type externalServer struct {
+	name   string
+	config ExternalServerConfig
+	pool   *sessionPool
+	tools  map[string]mcp.Tool
+}
+
+// ExternalTool describes a tool discovered on an external MCP server
+type ExternalTool struct {
+	Server      string              `json:"server"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	InputSchema mcp.ToolInputSchema `json:"input_schema"`
+}
+
+// MCPClient manages connections to other MCP servers
 type MCPClient struct {
-	mu sync.RWMutex
+	configPath string
+	servers    map[string]*externalServer
+	mu         sync.RWMutex
 }
 
 // NewMCPClient creates a new MCP client manager
 func NewMCPClient() *MCPClient {
-	return &MCPClient{}
+	return &MCPClient{
+		configPath: getEnvOrDefault("MCP_SERVERS_CONFIG", "mcp_servers.json"),
+		servers:    make(map[string]*externalServer),
+	}
 }
 
-// Initialize initializes the MCP client connections
+// LoadExternalServersConfig reads the external MCP server registry from a JSON file
+func LoadExternalServersConfig(path string) (*ExternalServersConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config ExternalServersConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse MCP servers config %s: %w", path, err)
+	}
+
+	for name, server := range config.Servers {
+		if server.Command == "" && server.URL == "" {
+			return nil, fmt.Errorf("MCP server %s must set either command or url", name)
+		}
+		if server.Command != "" && server.URL != "" {
+			return nil, fmt.Errorf("MCP server %s cannot set both command and url", name)
+		}
+		if server.PoolSize < 0 {
+			return nil, fmt.Errorf("MCP server %s has negative pool_size", name)
+		}
+		switch server.Transport {
+		case "", TransportStreamableHTTP, TransportSSE:
+		default:
+			return nil, fmt.Errorf("MCP server %s has unsupported transport %q", name, server.Transport)
+		}
+	}
+
+	return &config, nil
+}
+
+// Initialize loads the server registry and connects to every configured server
 func (c *MCPClient) Initialize(ctx context.Context) error {
-	log.Println("MCPClient initialization is currently stubbed out.")
+	config, err := LoadExternalServersConfig(c.configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Printf("No MCP servers config found at %s, no external MCP servers registered", c.configPath)
+			return nil
+		}
+		return err
+	}
+
+	for name, serverConfig := range config.Servers {
+		if err := c.Register(ctx, name, serverConfig); err != nil {
+			// A single unreachable server should not prevent the orchestrator from starting
+			log.Printf("Warning: failed to connect to MCP server %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Register connects to an external MCP server and adds it to the registry
+func (c *MCPClient) Register(ctx context.Context, name string, config ExternalServerConfig) error {
+	poolSize := config.PoolSize
+	if poolSize <= 0 {
+		poolSize = 1
+	}
+
+	server := &externalServer{
+		name:   name,
+		config: config,
+	}
+
+	sessions := make([]*session, 0, poolSize)
+	processes := make([]*stdioProcess, 0, poolSize)
+	closeAll := func() {
+		for _, s := range sessions {
+			s.client.Close()
+		}
+	}
+
+	for i := 0; i < poolSize; i++ {
+		mcpClient, process, err := c.connect(ctx, name, config)
+		if err != nil {
+			closeAll()
+			return err
+		}
+
+		s := &session{client: mcpClient}
+		if process != nil {
+			s.stop = make(chan struct{})
+		}
+		sessions = append(sessions, s)
+		processes = append(processes, process)
+	}
+
+	tools, err := discoverTools(ctx, sessions[0].client)
+	if err != nil {
+		closeAll()
+		return fmt.Errorf("failed to list tools on MCP server %s: %w", name, err)
+	}
+	server.tools = tools
+	server.pool = newSessionPool(sessions)
+
+	// Close the previous sessions under the lock so a concurrent restart cannot swap in a new client
+	c.mu.Lock()
+	previous := c.servers[name]
+	c.servers[name] = server
+	if previous != nil {
+		previous.close()
+	}
+	c.mu.Unlock()
+
+	for i, process := range processes {
+		if process != nil {
+			go c.supervise(server, sessions[i], process)
+		}
+	}
+
+	log.Printf("Connected to external MCP server %s (%d tools, %d sessions)", name, len(tools), poolSize)
+	return nil
+}
+
+// close stops supervision and closes every session of the server. Callers hold c.mu.
+func (s *externalServer) close() {
+	for _, sess := range s.pool.sessions {
+		if err := sess.close(); err != nil {
+			log.Printf("Error closing MCP server %s: %v", s.name, err)
+		}
+	}
+}
+
+// discoverTools calls tools/list on a connected server and indexes the result by tool name
+func discoverTools(ctx context.Context, mcpClient *client.Client) (map[string]mcp.Tool, error) {
+	result, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	tools := make(map[string]mcp.Tool, len(result.Tools))
+	for _, tool := range result.Tools {
+		tools[tool.Name] = tool
+	}
+	return tools, nil
+}
+
+// RefreshTools re-runs tool discovery for a registered server
+func (c *MCPClient) RefreshTools(ctx context.Context, serverName string) error {
+	c.mu.RLock()
+	server, exists := c.servers[serverName]
+	c.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("MCP server %s is not registered", serverName)
+	}
+
+	sess, err := server.pool.checkout(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tools on MCP server %s: %w", serverName, err)
+	}
+	tools, err := discoverTools(ctx, c.sessionClient(sess))
+	server.pool.checkin(sess)
+	if err != nil {
+		return fmt.Errorf("failed to list tools on MCP server %s: %w", serverName, err)
+	}
+
+	c.mu.Lock()
+	server.tools = tools
+	c.mu.Unlock()
+
 	return nil
 }
 
-// CallTool is a stub for calling a tool on a specific MCP server
+// ListTools returns the cached tools of every registered server, or of a single
+// server when serverName is non-empty
+func (c *MCPClient) ListTools(serverName string) ([]ExternalTool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if serverName != "" {
+		if _, exists := c.servers[serverName]; !exists {
+			return nil, fmt.Errorf("MCP server %s is not registered", serverName)
+		}
+	}
+
+	tools := make([]ExternalTool, 0)
+	for name, server := range c.servers {
+		if serverName != "" && name != serverName {
+			continue
+		}
+		for _, tool := range server.tools {
+			tools = append(tools, ExternalTool{
+				Server:      name,
+				Name:        tool.Name,
+				Description: tool.Description,
+				InputSchema: tool.InputSchema,
+			})
+		}
+	}
+
+	sort.Slice(tools, func(i, j int) bool {
+		if tools[i].Server != tools[j].Server {
+			return tools[i].Server < tools[j].Server
+		}
+		return tools[i].Name < tools[j].Name
+	})
+	return tools, nil
+}
+
+// connect starts a client for the given server config and performs the initialize handshake.
+// For stdio servers the spawned process is returned so it can be supervised.
+func (c *MCPClient) connect(ctx context.Context, name string, config ExternalServerConfig) (*client.Client, *stdioProcess, error) {
+	var mcpClient *client.Client
+	var process *stdioProcess
+	var err error
+	if config.URL != "" {
+		mcpClient, err = newRemoteClient(config)
+	} else {
+		mcpClient, process, err = startStdioProcess(name, config)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start MCP server %s: %w", name, err)
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "widescreen-research",
+		Version: "1.0.0",
+	}
+
+	if _, err := mcpClient.Initialize(ctx, initRequest); err != nil {
+		mcpClient.Close()
+		return nil, nil, fmt.Errorf("failed to initialize MCP server %s: %w", name, err)
+	}
+
+	return mcpClient, process, nil
+}
+
+// newRemoteClient creates and starts an HTTP or SSE client for a remote MCP server
+func newRemoteClient(config ExternalServerConfig) (*client.Client, error) {
+	headers := make(map[string]string, len(config.Headers)+1)
+	for k, v := range config.Headers {
+		headers[k] = os.ExpandEnv(v)
+	}
+	if config.BearerToken != "" {
+		headers["Authorization"] = "Bearer " + os.ExpandEnv(config.BearerToken)
+	}
+
+	var mcpClient *client.Client
+	var err error
+	switch config.Transport {
+	case TransportSSE:
+		mcpClient, err = client.NewSSEMCPClient(config.URL, client.WithHeaders(headers))
+	default:
+		mcpClient, err = client.NewStreamableHttpClient(config.URL, transport.WithHTTPHeaders(headers))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The SSE stream lives as long as the start context, so it is bound to the
+	// client's lifetime (ended by Close) rather than the caller's context
+	if err := mcpClient.Start(context.Background()); err != nil {
+		mcpClient.Close()
+		return nil, err
+	}
+	return mcpClient, nil
+}
+
+// ListServers returns the names of all registered external MCP servers
+func (c *MCPClient) ListServers() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.servers))
+	for name := range c.servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CallTool calls a tool on a specific MCP server
 func (c *MCPClient) CallTool(ctx context.Context, serverName string, toolName string, arguments interface{}) (interface{}, error) {
-	log.Printf("MCPClient CallTool is currently stubbed out. Call to %s on %s was ignored.", toolName, serverName)
-	return nil, nil
+	c.mu.RLock()
+	server, exists := c.servers[serverName]
+	toolExists := false
+	if exists {
+		_, toolExists = server.tools[toolName]
+	}
+	c.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("MCP server %s is not registered", serverName)
+	}
+
+	if !toolExists {
+		return nil, fmt.Errorf("MCP server %s does not provide tool %s", serverName, toolName)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = toolName
+	request.Params.Arguments = arguments
+
+	// Each call gets a session to itself so parallel pipelines spread across the pool
+	sess, err := server.pool.checkout(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call tool %s on %s: %w", toolName, serverName, err)
+	}
+	defer server.pool.checkin(sess)
+
+	result, err := c.sessionClient(sess).CallTool(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call tool %s on %s: %w", toolName, serverName, err)
+	}
+
+	if result.IsError {
+		return nil, fmt.Errorf("tool %s on %s returned an error: %s", toolName, serverName, toolResultText(result))
+	}
+
+	return result, nil
+}
+
+// sessionClient returns the current client of a session, which the supervisor may replace
+func (c *MCPClient) sessionClient(s *session) *client.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return s.client
+}
+
+// toolResultText concatenates the text content of a tool result
+func toolResultText(result *mcp.CallToolResult) string {
+	text := ""
+	for _, content := range result.Content {
+		if textContent, ok := mcp.AsTextContent(content); ok {
+			text += textContent.Text
+		}
+	}
+	return text
 }
 
 // Shutdown closes all MCP client connections
 func (c *MCPClient) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, server := range c.servers {
+		server.close()
+	}
+	c.servers = make(map[string]*externalServer)
+
 	log.Println("MCPClient shutdown.")
-}
\ No newline at end of file
+}
@@ -0,0 +1,298 @@
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Session events that can be sent to webhooks
+const (
+	EventSessionCompleted      = "completed"
+	EventSessionFailed         = "failed"
+	EventSessionTimeout        = "timeout"
+	EventSessionBudgetExceeded = "budget_exceeded"
+)
+
+// allSessionEvents are sent to webhooks that do not filter events
+var allSessionEvents = []string{EventSessionCompleted, EventSessionFailed, EventSessionTimeout, EventSessionBudgetExceeded}
+
+// WebhookConfig is a webhook to notify about finished sessions
+type WebhookConfig struct {
+	URL string `json:"url"`
+	// Format is "slack", "discord" or "json"; empty detects Slack and Discord from the URL
+	Format string `json:"format,omitempty"`
+	// Events to send; empty sends all of them
+	Events []string `json:"events,omitempty"`
+}
+
+// SessionEvent describes a research session that reached a final status
+type SessionEvent struct {
+	Event           string    `json:"event"`
+	SessionID       string    `json:"session_id"`
+	Status          string    `json:"status"`
+	Topic           string    `json:"topic"`
+	DronesCompleted int       `json:"drones_completed"`
+	DronesFailed    int       `json:"drones_failed"`
+	DronesTotal     int       `json:"drones_total"`
+	Duration        string    `json:"duration"`
+	ReportURL       string    `json:"report_url,omitempty"`
+	FinishedAt      time.Time `json:"finished_at"`
+}
+
+// Notifier posts session events to the configured webhooks
+type Notifier struct {
+	webhooks      []WebhookConfig
+	reportBaseURL string
+	client        *http.Client
+}
+
+// LoadNotifier configures webhooks from NOTIFY_WEBHOOK_URLS, filtered by
+// NOTIFY_EVENTS, and from the JSON list of WebhookConfig at NOTIFY_WEBHOOKS_CONFIG.
+// Report links are prefixed with REPORT_BASE_URL when it is set.
+func LoadNotifier() *Notifier {
+	notifier := &Notifier{
+		reportBaseURL: strings.TrimSuffix(getEnvOrDefault("REPORT_BASE_URL", ""), "/"),
+		client:        &http.Client{Timeout: 10 * time.Second},
+	}
+
+	events := splitList(getEnvOrDefault("NOTIFY_EVENTS", ""))
+	for _, webhookURL := range splitList(getEnvOrDefault("NOTIFY_WEBHOOK_URLS", "")) {
+		notifier.webhooks = append(notifier.webhooks, WebhookConfig{URL: webhookURL, Events: events})
+	}
+
+	if path := getEnvOrDefault("NOTIFY_WEBHOOKS_CONFIG", ""); path != "" {
+		var webhooks []WebhookConfig
+		data, err := os.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(data, &webhooks)
+		}
+		if err != nil {
+			log.Printf("Warning: Failed to load webhooks from %s: %v", path, err)
+		}
+		notifier.webhooks = append(notifier.webhooks, webhooks...)
+	}
+
+	for _, webhook := range notifier.webhooks {
+		for _, event := range webhook.Events {
+			if !containsString(allSessionEvents, event) {
+				log.Printf("Warning: Unknown notification event %q, expected one of %v", event, allSessionEvents)
+			}
+		}
+	}
+
+	return notifier
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionEventFor maps a final session status to the event it is notified as
+func sessionEventFor(status string) (string, bool) {
+	switch status {
+	case "completed":
+		return EventSessionCompleted, true
+	case "failed", "failed_report_generation", "interrupted", "cancelled":
+		return EventSessionFailed, true
+	case "timeout":
+		return EventSessionTimeout, true
+	case "budget_exceeded":
+		return EventSessionBudgetExceeded, true
+	}
+	return "", false
+}
+
+// reportPath is where generateReport saves a session's Markdown report
+func reportPath(sessionID string) string {
+	return fmt.Sprintf("reports/report_%s.md", sessionID)
+}
+
+// reportLink returns where a session's report can be found
+func (n *Notifier) reportLink(sessionID string) string {
+	if n == nil || n.reportBaseURL == "" {
+		return reportPath(sessionID)
+	}
+	return n.reportBaseURL + "/" + reportPath(sessionID)
+}
+
+// enabled reports whether any webhooks are configured
+func (n *Notifier) enabled() bool {
+	return n != nil && len(n.webhooks) > 0
+}
+
+// Notify posts event to every webhook that subscribed to it. Failures are logged.
+func (n *Notifier) Notify(ctx context.Context, event SessionEvent) {
+	if !n.enabled() {
+		return
+	}
+
+	for _, webhook := range n.webhooks {
+		if len(webhook.Events) > 0 && !containsString(webhook.Events, event.Event) {
+			continue
+		}
+		if err := n.post(ctx, webhook, event); err != nil {
+			log.Printf("Warning: Failed to notify webhook about session %s: %v", event.SessionID, err)
+		}
+	}
+}
+
+// post sends event to a single webhook in its format
+func (n *Notifier) post(ctx context.Context, webhook WebhookConfig, event SessionEvent) error {
+	var payload interface{}
+	switch webhookFormat(webhook) {
+	case "slack":
+		payload = map[string]string{"text": event.message()}
+	case "discord":
+		payload = map[string]string{"content": event.message()}
+	default:
+		payload = event
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// webhookFormat returns the configured format, or detects it from the webhook's host
+func webhookFormat(webhook WebhookConfig) string {
+	if webhook.Format != "" {
+		return webhook.Format
+	}
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil {
+		return "json"
+	}
+	switch host := parsed.Hostname(); {
+	case host == "hooks.slack.com":
+		return "slack"
+	case host == "discord.com" || host == "discordapp.com" || strings.HasSuffix(host, ".discord.com"):
+		return "discord"
+	}
+	return "json"
+}
+
+// message renders the event as chat text
+func (e SessionEvent) message() string {
+	var headline string
+	switch e.Event {
+	case EventSessionCompleted:
+		headline = "Research session completed"
+	case EventSessionTimeout:
+		headline = "Research session timed out"
+	case EventSessionBudgetExceeded:
+		headline = "Research session stopped over budget"
+	default:
+		headline = "Research session failed"
+	}
+
+	text := fmt.Sprintf("%s: %s (%s)\nTopic: %s\nDrones: %d completed, %d failed of %d in %s",
+		headline, e.SessionID, e.Status, e.Topic, e.DronesCompleted, e.DronesFailed, e.DronesTotal, e.Duration)
+	if e.ReportURL != "" {
+		text += "\nReport: " + e.ReportURL
+	}
+	return text
+}
+
+// notifySessionFinished tells the webhooks and the session's email recipients
+// that the session reached a final status. A completed report is first
+// published to GitHub, if configured, and linked to from the notifications.
+func (o *Orchestrator) notifySessionFinished(session *ResearchSession, status string) {
+	eventName, ok := sessionEventFor(status)
+	if !ok {
+		return
+	}
+	recipients := session.Config.NotifyEmails
+	sendEmail := len(recipients) > 0 && o.mailer.enabled()
+	publish := status == "completed" && o.github.enabled()
+	if !o.notifier.enabled() && !sendEmail && !publish {
+		return
+	}
+
+	event := SessionEvent{
+		Event:       eventName,
+		SessionID:   session.Config.SessionID,
+		Status:      status,
+		Topic:       session.Config.Topic,
+		DronesTotal: session.Config.ResearcherCount,
+		Duration:    time.Since(session.StartTime).Round(time.Second).String(),
+		FinishedAt:  time.Now(),
+	}
+	o.mu.RLock()
+	for _, result := range session.Results {
+		if result.Status == "completed" {
+			event.DronesCompleted++
+		} else {
+			event.DronesFailed++
+		}
+	}
+	attachment := ""
+	if session.Report != nil {
+		event.ReportURL = o.notifier.reportLink(session.Config.SessionID)
+		attachment = reportPath(session.Config.SessionID)
+	}
+	o.mu.RUnlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		if publish && attachment != "" {
+			if report, err := os.ReadFile(attachment); err != nil {
+				log.Printf("Warning: Failed to read report %s for GitHub: %v", attachment, err)
+			} else if link, err := o.github.Publish(ctx, event, report); err != nil {
+				log.Printf("Warning: Failed to publish report for session %s to GitHub: %v", event.SessionID, err)
+			} else {
+				log.Printf("Published report for session %s to %s", event.SessionID, link)
+				event.ReportURL = link
+			}
+		}
+		o.notifier.Notify(ctx, event)
+		if sendEmail {
+			if err := o.mailer.SendSessionEmail(ctx, recipients, event, attachment); err != nil {
+				log.Printf("Warning: Failed to email session %s to %v: %v", event.SessionID, recipients, err)
+			}
+		}
+	}()
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -15,6 +17,15 @@ import (
 	runpb "cloud.google.com/go/run/apiv2/runpb"
 	"github.com/google/uuid"
 	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/encryption"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+	"github.com/spawn-mcp/coordinator/pkg/payload"
+	"github.com/spawn-mcp/coordinator/pkg/types"
+	logging "google.golang.org/api/logging/v2"
+	monitoring "google.golang.org/api/monitoring/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
 )
 
@@ -25,17 +36,99 @@ type Orchestrator struct {
 	pubsubClient    *pubsub.Client
 	runClient       *run.ServicesClient
 
+	// Cloud Monitoring, for the Cloud Run usage of each session's drones
+	monitoringService *monitoring.Service
+
+	// Cloud Logging, for reading drone logs
+	loggingService *logging.Service
+
+	// Verified container images for drones
+	images *gcp.ImageResolver
+
+	// VPC access and ingress settings for drone services
+	network gcp.NetworkConfig
+
+	// Bounds and staggers drone deployments across all sessions
+	deployThrottle *deployThrottle
+
+	// Retries drone instructions that fail to arrive
+	instructionRetry InstructionRetryConfig
+
+	// How many sub-queries a drone is sent at a time
+	dispatch DispatchConfig
+
+	// Bounds buffered drone results and batches their Firestore writes
+	resultPipeline ResultPipelineConfig
+
+	// Decodes compressed drone results and loads data offloaded to Cloud Storage
+	payloads *payload.Codec
+
+	// Encrypts drone result data stored in Firestore with a customer-managed key
+	fields *encryption.FieldEncryptor
+
 	// MCP client for connecting to other MCP servers
 	mcpClient *MCPClient
 
 	// Claude SDK agent
 	claudeAgent *ClaudeAgent
 
+	// Pre-provisioned drones handed to new sessions
+	warmPool *DronePool
+
+	// Unit prices for estimating session spend
+	costRates CostRates
+
+	// Webhooks told when sessions finish
+	notifier *Notifier
+
+	// Emails reports to the addresses given during elicitation; nil when not configured
+	mailer *Mailer
+
+	// Opens a pull request or issue with each completed report; nil when not configured
+	github *GitHubPublisher
+
+	// Rates the credibility of the sources drones cite
+	sourceScorer *SourceScorer
+
+	// Embeds findings to group them into topics; nil when clustering is disabled
+	embedder Embedder
+
+	// Indexes the findings of finished sessions for search-findings; nil when disabled
+	vectors VectorStore
+
+	// How prior findings from vectors seed the sub-queries of new sessions
+	memory ResearchMemoryConfig
+
+	// Signal and wait for the loop that starts due schedules
+	schedulerStop chan struct{}
+	schedulerDone chan struct{}
+
+	// How long reports, results and logs are kept, and the loop purging them once expired
+	retention     RetentionConfig
+	retentionStop chan struct{}
+	retentionDone chan struct{}
+
+	// Sessions run simulated drones instead of Cloud Run; see SimulationConfig
+	simulation SimulationConfig
+
+	// Teams sharing the orchestrator, each with its own namespace and quota
+	tenants *TenantRegistry
+
+	// Detects and replaces drones that stop sending heartbeats
+	heartbeat       HeartbeatConfig
+	heartbeatCancel context.CancelFunc
+	heartbeatDone   chan struct{}
+
 	// Research management
 	activeSessions map[string]*ResearchSession
 	reports        map[string]*schemas.ResearchReport
+	graphs         map[string]*schemas.KnowledgeGraph // knowledge graphs this process built, by session ID
 	templates      map[string]*ResearchTemplate
 	mu             sync.RWMutex
+	// draining rejects new sessions once shutdown has begun; see Drain
+	draining bool
+	// initialized is set once Initialize has succeeded
+	initialized bool
 
 	// Configuration
 	projectID string
@@ -51,15 +144,48 @@ type ResearchSession struct {
 	Status      string
 	Results     []schemas.DroneResult
 	Report      *schemas.ResearchReport
+	Budget      *sessionBudget
+	// Workflow is the state of the template workflow the session runs, if any
+	Workflow    *WorkflowState
+	// dispatcher hands out the session's sub-queries; nil for sessions whose
+	// drones each get a single task, such as workflow steps
+	dispatcher  *taskDispatcher
+	// listener is told about each result as it is collected; nil when no one is listening
+	listener    ResultListener
+	// droneMetrics is the latest metrics report of each drone, guarded by the orchestrator's mu
+	droneMetrics map[string]types.DroneMetrics
+	cancel      context.CancelFunc
+	// statusMu serializes status transitions; see setSessionStatus
+	statusMu sync.Mutex
+	// cleaning is set once cleanupSession has started on the session
+	cleaning bool
+	// replacements tracks replaceDrone calls, which cleanupSession waits for
+	// so it also deletes the drones they deploy
+	replacements sync.WaitGroup
 }
 
 // DroneInfo contains information about a deployed drone
 type DroneInfo struct {
 	ID          string
 	ServiceURL  string
+	Region      string
 	Status      string
 	StartTime   time.Time
 	LastCheckin time.Time
+	// Task is the last instructions sent to the drone, resent to its replacement
+	Task map[string]interface{}
+	// Simulated drones have no Cloud Run service and report synthetic results
+	Simulated bool
+}
+
+// researchDroneType is the drone type the orchestrator deploys, used to look up its image
+const researchDroneType = "researcher"
+
+// DefaultDroneImages returns the drone images used when configuration does not override them
+func DefaultDroneImages(projectID string) map[string]string {
+	return map[string]string{
+		researchDroneType: fmt.Sprintf("gcr.io/%s/research-drone:latest", projectID),
+	}
 }
 
 // ResearchTemplate represents a pre-orchestrated workflow
@@ -68,11 +194,26 @@ type ResearchTemplate struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	Workflow    map[string]interface{} `json:"workflow"`
+	Parameters  []TemplateParameter    `json:"parameters,omitempty"` // substituted for {{name}} in the workflow
+	Builtin     bool                   `json:"builtin,omitempty"` // shipped with the orchestrator rather than saved in Firestore
+	Tenant      string                 `json:"tenant,omitempty"`  // the tenant that saved it, the only one that may change it
+	CreatedAt   time.Time              `json:"created_at,omitempty"`
+	UpdatedAt   time.Time              `json:"updated_at,omitempty"`
 }
 
 // NewOrchestrator creates a new orchestrator instance
 func NewOrchestrator() (*Orchestrator, error) {
-	projectID := getEnvOrDefault("GOOGLE_CLOUD_PROJECT", "")
+	tenants, err := LoadTenantConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tenants: %w", err)
+	}
+
+	simulation := LoadSimulationConfig()
+	if simulation.Enabled {
+		return newSimulatedOrchestrator(simulation, tenants), nil
+	}
+
+	projectID := gcp.ProjectID()
 	if projectID == "" {
 		return nil, fmt.Errorf("GOOGLE_CLOUD_PROJECT environment variable is required")
 	}
@@ -92,11 +233,39 @@ func NewOrchestrator() (*Orchestrator, error) {
 	}
 
 	// Initialize Cloud Run client
-	runClient, err := run.NewServicesClient(ctx)
+	runClient, err := run.NewServicesClient(ctx, gcp.EmulatorOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Cloud Run client: %w", err)
 	}
 
+	// Initialize Cloud Monitoring client
+	monitoringService, err := monitoring.NewService(ctx, gcp.EmulatorOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Monitoring client: %w", err)
+	}
+
+	// Initialize Cloud Logging client
+	loggingService, err := logging.NewService(ctx, gcp.EmulatorOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Logging client: %w", err)
+	}
+
+	// Encrypt stored result data when a customer-managed key is configured
+	encryptionConfig := encryption.LoadConfig()
+	fields, err := encryption.NewFieldEncryptor(encryptionConfig.FieldKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FIELD_KMS_KEY: %w", err)
+	}
+	if encryptionConfig.StorageKey != "" && !encryption.ValidKeyName(encryptionConfig.StorageKey) {
+		return nil, fmt.Errorf("invalid STORAGE_KMS_KEY: %q is not a Cloud KMS key name", encryptionConfig.StorageKey)
+	}
+
+	// Resolve drone images from configuration, defaulting to the project registry
+	images, err := gcp.NewImageResolver(ctx, DefaultDroneImages(projectID), firestoreClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load drone images: %w", err)
+	}
+
 	// Create MCP client
 	mcpClient := NewMCPClient()
 
@@ -111,10 +280,41 @@ func NewOrchestrator() (*Orchestrator, error) {
 		claudeAgent:     claudeAgent,
 		activeSessions:  make(map[string]*ResearchSession),
 		reports:         make(map[string]*schemas.ResearchReport),
+		graphs:          make(map[string]*schemas.KnowledgeGraph),
 		templates:       make(map[string]*ResearchTemplate),
 		projectID:       projectID,
 		region:          getEnvOrDefault("GOOGLE_CLOUD_REGION", "us-central1"),
+		costRates:       LoadCostRates(),
+		resultPipeline:  LoadResultPipelineConfig(),
+		payloads:        payload.NewCodec(payload.LoadConfig()),
+		fields:          fields,
+		simulation:      simulation,
+		tenants:         tenants,
+	}
+
+	orch.monitoringService = monitoringService
+	orch.loggingService = loggingService
+	orch.images = images
+	orch.network = gcp.LoadNetworkConfig()
+	orch.deployThrottle = newDeployThrottle(LoadProvisioningConfig())
+	orch.instructionRetry = LoadInstructionRetryConfig()
+	orch.dispatch = LoadDispatchConfig()
+	orch.warmPool = NewDronePool(orch, LoadWarmPoolConfig())
+	orch.notifier = LoadNotifier()
+	orch.mailer = NewMailer(LoadEmailConfig())
+	orch.github = NewGitHubPublisher(LoadGitHubConfig())
+	orch.sourceScorer = NewSourceScorer(LoadSourceScoringConfig())
+	orch.heartbeat = LoadHeartbeatConfig()
+	orch.retention = LoadRetentionConfig()
+	orch.embedder, err = NewEmbedder(ctx, LoadEmbeddingConfig(projectID, orch.region))
+	if err != nil {
+		log.Printf("Warning: Failed to create embedder, findings will not be clustered: %v", err)
+	}
+	orch.vectors, err = NewVectorStore(ctx, LoadVectorStoreConfig(orch.region), firestoreClient)
+	if err != nil {
+		log.Printf("Warning: Failed to create vector store, findings will not be indexed: %v", err)
 	}
+	orch.memory = LoadResearchMemoryConfig()
 
 	// Load templates
 	orch.loadTemplates()
@@ -134,27 +334,113 @@ func (o *Orchestrator) Initialize(ctx context.Context) error {
 		return fmt.Errorf("failed to initialize Claude agent: %w", err)
 	}
 
+	// Simulation has no Pub/Sub, Firestore or drones to keep warm
+	if o.simulation.Enabled {
+		o.setInitialized()
+		return nil
+	}
+
 	// Create required Pub/Sub topics
 	if err := o.createPubSubTopics(ctx); err != nil {
 		return fmt.Errorf("failed to create Pub/Sub topics: %w", err)
 	}
 
+	// Record drone heartbeats so silent drones can be replaced
+	if err := o.startHeartbeatReceiver(ctx); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	// Load the templates teams have saved alongside the built-in ones
+	if err := o.loadStoredTemplates(ctx); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	// Start warming drones for upcoming sessions
+	o.warmPool.Start()
+
+	// Start re-running saved research schedules when they come due, unless
+	// an external scheduler triggers them
+	if mode := getEnvOrDefault("SCHEDULER_MODE", "internal"); mode == "internal" {
+		o.startScheduler()
+	} else if mode != "external" {
+		log.Printf("Warning: Unknown SCHEDULER_MODE %q, schedules will only run when triggered", mode)
+	}
+
+	// Purge reports and session data once their retention windows pass
+	if err := o.applyLogRetention(ctx); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	o.startRetention()
+
+	o.setInitialized()
 	return nil
 }
 
 // OrchestrateResearch orchestrates the research process
 func (o *Orchestrator) OrchestrateResearch(ctx context.Context, config *schemas.ResearchConfig) (*schemas.ResearchResult, error) {
+	// Fill in the chosen template's workflow with the session's parameters
+	if config.TemplateID != "" && config.Workflow == nil {
+		workflow, err := o.InstantiateTemplate(config.TemplateID, config.TemplateParams)
+		if err != nil {
+			return nil, fmt.Errorf("failed to instantiate template: %w", err)
+		}
+		config.Workflow = workflow
+	}
+	steps, err := parseWorkflowSteps(config.Workflow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workflow: %w", err)
+	}
+
+	// The session's resources are named for the tenant that started it
+	config.Tenant = o.tenantID(ctx)
+	ctx = WithTenant(ctx, config.Tenant)
+	// The session is traced by the ID of the call that started it; scheduled runs get their own
+	config.CorrelationID = mcperrors.CorrelationID(ctx)
+	if config.CorrelationID == "" {
+		config.CorrelationID = mcperrors.NewCorrelationID()
+		ctx = mcperrors.WithCorrelationID(ctx, config.CorrelationID)
+	}
+
+	// Cancelled when the session ends or goes over budget, or when the caller cancels
+	callerCtx := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	o.mu.Lock()
+	if o.draining {
+		o.mu.Unlock()
+		return nil, ErrDraining
+	}
+	if err := o.checkTenantQuota(config); err != nil {
+		o.mu.Unlock()
+		return nil, err
+	}
 	session := &ResearchSession{
 		Config:    config,
 		Drones:    make(map[string]*DroneInfo),
-		Queue:     NewResearchQueue(config.SessionID),
+		Queue:     NewResearchQueue(config.Tenant, config.SessionID, o.resultPipeline.BufferSize),
 		StartTime: time.Now(),
 		Status:    "initializing",
 		Results:   make([]schemas.DroneResult, 0),
+		listener:  resultListenerFrom(ctx),
+		cancel:    cancel,
 	}
+	session.Budget = newSessionBudget(o.costRates, config.MaxCostUSD,
+		cpuCores(o.getCPUForPriority(config.PriorityLevel)), memoryGiB(o.getMemoryForPriority(config.PriorityLevel)))
 	o.activeSessions[config.SessionID] = session
 	o.mu.Unlock()
+	o.recordSessionStart(session)
+
+	// However the session ends, its drones and results topic are deleted
+	defer func() { go o.cleanupSession(context.WithoutCancel(ctx), session) }()
+	// A panic fails the session instead of leaving it running; the caller recovers and reports it
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Session %s failed: panic: %v", config.SessionID, r)
+			o.setSessionStatus(session, "failed")
+			panic(r)
+		}
+	}()
 
 	// Update progress file
 	if err := o.updateProgressFile(session); err != nil {
@@ -165,38 +451,58 @@ func (o *Orchestrator) OrchestrateResearch(ctx context.Context, config *schemas.
 	go o.monitorSession(ctx, session)
 
 	// Provision drones
-	log.Printf("Provisioning %d research drones for session %s", config.ResearcherCount, config.SessionID)
-	if err := o.provisionDrones(ctx, session); err != nil {
-		session.Status = "failed"
+	log.Printf("Provisioning %d research drones for session %s [%s]", config.ResearcherCount, config.SessionID, config.CorrelationID)
+	if err := o.provisionDrones(ctx, session); err != nil && !o.budgetExceeded(session) {
+		if callerCtx.Err() != nil {
+			return nil, o.cancelSession(callerCtx, session)
+		}
+		o.setSessionStatus(session, "failed")
 		return nil, fmt.Errorf("failed to provision drones: %w", err)
 	}
-
-	// Start research coordination
-	session.Status = "running"
-	if err := o.coordinateResearch(ctx, session); err != nil {
-		session.Status = "failed"
-		return nil, fmt.Errorf("failed to coordinate research: %w", err)
+	if o.budgetExceeded(session) {
+		return o.abortOverBudget(ctx, session), nil
 	}
 
-	// Wait for completion
-	_, err := o.waitForCompletion(ctx, session)
-	if err != nil {
-		session.Status = "failed"
+	// Start research coordination, unless the session was stopped while provisioning
+	if callerCtx.Err() != nil {
+		return nil, o.cancelSession(callerCtx, session)
+	}
+	if !o.setSessionStatus(session, "running") {
+		if o.budgetExceeded(session) {
+			return o.abortOverBudget(ctx, session), nil
+		}
+		return nil, fmt.Errorf("session %s ended as %s before research started", config.SessionID, o.sessionStatus(session))
+	}
+	// Run the template's workflow, or else one round of research across the drones
+	if err := o.runResearch(ctx, session, steps); err != nil {
+		if o.budgetExceeded(session) {
+			return o.abortOverBudget(ctx, session), nil
+		}
+		if callerCtx.Err() != nil {
+			return nil, o.cancelSession(callerCtx, session)
+		}
+		o.setSessionStatus(session, "failed")
 		o.updateProgressFile(session)
-		return nil, fmt.Errorf("research failed: %w", err)
+		return nil, err
 	}
 
 	// Generate report
 	log.Printf("Generating report for session %s", config.SessionID)
-	report, err := o.generateReport(ctx, session)
+	report, err := o.generateReport(ctx, session, o.claudeAgent)
 	if err != nil {
-		session.Status = "failed_report_generation"
+		if callerCtx.Err() != nil {
+			return nil, o.cancelSession(callerCtx, session)
+		}
+		o.setSessionStatus(session, "failed_report_generation")
 		o.updateProgressFile(session)
 		return nil, fmt.Errorf("failed to generate report: %w", err)
 	}
 
 	session.Report = report
-	session.Status = "completed"
+	if !o.setSessionStatus(session, "completed") {
+		o.updateProgressFile(session)
+		return nil, fmt.Errorf("session %s ended as %s before its report was completed", config.SessionID, o.sessionStatus(session))
+	}
 	o.updateProgressFile(session)
 
 	// Store report
@@ -204,34 +510,112 @@ func (o *Orchestrator) OrchestrateResearch(ctx context.Context, config *schemas.
 	o.reports[report.ID] = report
 	o.mu.Unlock()
 
-	// Clean up resources
-	go o.cleanupSession(ctx, session)
+	// Make the session's findings searchable from later sessions
+	o.indexSession(ctx, session)
 
 	reportFilePath := fmt.Sprintf("reports/report_%s.md", session.Config.SessionID)
 
-	return &schemas.ResearchResult{
+	result := &schemas.ResearchResult{
 		SessionID:   config.SessionID,
 		Status:      "completed",
 		ReportURL:   reportFilePath,
 		ReportData:  report,
-		Metrics:     o.calculateMetrics(session),
+		Metrics:     o.calculateMetrics(ctx, session),
 		CompletedAt: time.Now(),
-	}, nil
+	}
+	if session.Workflow != nil {
+		result.Workflow = session.Workflow.Steps
+	}
+	return result, nil
+}
+
+// cancelSession ends a session whose caller cancelled it. Its drones are
+// deleted as it returns, so they stop billing.
+func (o *Orchestrator) cancelSession(callerCtx context.Context, session *ResearchSession) error {
+	log.Printf("Session %s was cancelled by its caller", session.Config.SessionID)
+	o.setSessionStatus(session, "cancelled")
+	if err := o.updateProgressFile(session); err != nil {
+		log.Printf("Warning: failed to update progress file for session %s: %v", session.Config.SessionID, err)
+	}
+	return fmt.Errorf("session %s cancelled: %w", session.Config.SessionID, callerCtx.Err())
+}
+
+// runResearch runs a session's workflow steps when it has any, and otherwise
+// has each drone research one sub-query of the topic and waits for them all
+func (o *Orchestrator) runResearch(ctx context.Context, session *ResearchSession, steps []WorkflowStep) error {
+	if len(steps) > 0 {
+		if err := o.runWorkflow(ctx, session, steps); err != nil {
+			return fmt.Errorf("workflow failed: %w", err)
+		}
+		return nil
+	}
+
+	if err := o.coordinateResearch(ctx, session); err != nil {
+		return fmt.Errorf("failed to coordinate research: %w", err)
+	}
+	if _, err := o.waitForCompletion(ctx, session); err != nil {
+		return fmt.Errorf("research failed: %w", err)
+	}
+	return nil
 }
 
 // provisionDrones provisions the required number of research drones
 func (o *Orchestrator) provisionDrones(ctx context.Context, session *ResearchSession) error {
+	if o.simulating(session.Config) {
+		o.provisionSimulatedDrones(session)
+		return nil
+	}
+
+	regions := o.sessionRegions(session.Config)
+
+	// Take already running drones from the warm pool first, if the session can use its region
+	var warm []*DroneInfo
+	for _, region := range regions {
+		if region == o.region && o.usesWarmPool(session.Config.Tenant) {
+			warm = o.warmPool.Acquire(session.Config.PriorityLevel, session.Config.ResearcherCount)
+			break
+		}
+	}
+	o.mu.Lock()
+	for _, drone := range warm {
+		drone.Status = "deployed"
+		session.Drones[drone.ID] = drone
+	}
+	o.mu.Unlock()
+	if len(warm) > 0 {
+		log.Printf("Assigned %d warm drones to session %s", len(warm), session.Config.SessionID)
+	}
+	for _, drone := range warm {
+		go func(drone *DroneInfo) {
+			if err := o.labelDroneSession(ctx, drone.ID, drone.Region, session.Config.SessionID); err != nil {
+				log.Printf("Warning: failed to label warm drone %s with session %s: %v", drone.ID, session.Config.SessionID, err)
+			}
+		}(drone)
+	}
+
+	planner := newRegionPlanner(regions)
 	var wg sync.WaitGroup
 	errors := make(chan error, session.Config.ResearcherCount)
 
-	for i := 0; i < session.Config.ResearcherCount; i++ {
+	for i := len(warm); i < session.Config.ResearcherCount; i++ {
+		// Stop provisioning once the session is out of budget or cancelled
+		if o.enforceBudget(session) || ctx.Err() != nil {
+			break
+		}
+
 		wg.Add(1)
 		go func(index int) {
 			defer wg.Done()
 
-			droneID := fmt.Sprintf("drone-%s-%d", session.Config.SessionID, index)
-			serviceURL, err := o.deployDrone(ctx, droneID, session.Config)
+			droneID := tenantResourceName(session.Config.Tenant, fmt.Sprintf("drone-%s-%d", session.Config.SessionID, index))
+			serviceURL, region, err := o.deployDroneWithFailover(ctx, planner, index, droneID, session.Config)
 			if err != nil {
+				// Cloud Run may create the service after the session stopped waiting for it
+				if ctx.Err() != nil && region != "" {
+					if err := o.deleteDroneService(context.WithoutCancel(ctx), droneID, region); err != nil && status.Code(err) != codes.NotFound {
+						log.Printf("Warning: failed to delete drone %s abandoned mid-deployment: %v", droneID, err)
+					}
+				}
 				errors <- fmt.Errorf("failed to deploy drone %s: %w", droneID, err)
 				return
 			}
@@ -240,13 +624,14 @@ func (o *Orchestrator) provisionDrones(ctx context.Context, session *ResearchSes
 			session.Drones[droneID] = &DroneInfo{
 				ID:          droneID,
 				ServiceURL:  serviceURL,
+				Region:      region,
 				Status:      "deployed",
 				StartTime:   time.Now(),
 				LastCheckin: time.Now(),
 			}
 			o.mu.Unlock()
 
-			log.Printf("Successfully deployed drone %s at %s", droneID, serviceURL)
+			log.Printf("Successfully deployed drone %s in %s at %s", droneID, region, serviceURL)
 		}(i)
 	}
 
@@ -266,25 +651,35 @@ func (o *Orchestrator) provisionDrones(ctx context.Context, session *ResearchSes
 	return nil
 }
 
-// deployDrone deploys a single research drone on Cloud Run
-func (o *Orchestrator) deployDrone(ctx context.Context, droneID string, config *schemas.ResearchConfig) (string, error) {
-	// Use the drone template image
-	image := fmt.Sprintf("gcr.io/%s/research-drone:latest", o.projectID)
+// deployDrone deploys a single research drone on Cloud Run in the given region
+func (o *Orchestrator) deployDrone(ctx context.Context, droneID, region string, config *schemas.ResearchConfig) (string, error) {
+	// Wait for a deployment slot, so large sessions do not trip Cloud Run API rate limits
+	release, err := o.deployThrottle.acquire(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for a deployment slot: %w", err)
+	}
+	defer release()
+
+	// Use the configured drone image, checked to exist and pinned to its digest
+	image, err := o.images.Image(ctx, researchDroneType)
+	if err != nil {
+		return "", err
+	}
+
+	serviceAccount, err := gcp.DroneServiceAccount(o.projectID, researchDroneType, config.ServiceAccount)
+	if err != nil {
+		return "", err
+	}
 
 	// Create service configuration
 	serviceConfig := &runpb.Service{
-		Name: droneID,
+		Name:   droneID,
+		Labels: droneLabels(config.Tenant, config.SessionID),
 		Template: &runpb.RevisionTemplate{
 			Containers: []*runpb.Container{
 				{
 					Image: image,
-					Env: []*runpb.EnvVar{
-						{Name: "DRONE_ID", Values: &runpb.EnvVar_Value{Value: droneID}},
-						{Name: "SESSION_ID", Values: &runpb.EnvVar_Value{Value: config.SessionID}},
-						{Name: "GOOGLE_CLOUD_PROJECT", Values: &runpb.EnvVar_Value{Value: o.projectID}},
-						// The drone will get its instructions via HTTP, but it needs to know which topic to publish results to.
-						{Name: "PUBSUB_TOPIC", Values: &runpb.EnvVar_Value{Value: fmt.Sprintf("research-results-%s", config.SessionID)}},
-					},
+					Env:   o.droneEnv(droneID, config),
 					Resources: &runpb.ResourceRequirements{
 						Limits: map[string]string{
 							"cpu":    o.getCPUForPriority(config.PriorityLevel),
@@ -293,17 +688,29 @@ func (o *Orchestrator) deployDrone(ctx context.Context, droneID string, config *
 					},
 				},
 			},
+			ServiceAccount:                serviceAccount,
 			MaxInstanceRequestConcurrency: 1,
 			Timeout:                      &durationpb.Duration{Seconds: int64(config.TimeoutMinutes * 60)},
 		},
 	}
+	if err := gcp.ApplyNetworkConfig(serviceConfig, o.projectID, region, o.network); err != nil {
+		return "", err
+	}
 
 	// Deploy the service
 	operation, err := o.runClient.CreateService(ctx, &runpb.CreateServiceRequest{
-		Parent:    fmt.Sprintf("projects/%s/locations/%s", o.projectID, o.region),
+		Parent:    fmt.Sprintf("projects/%s/locations/%s", o.projectID, region),
 		ServiceId: droneID,
 		Service:   serviceConfig,
 	})
+	if status.Code(err) == codes.AlreadyExists {
+		// A retried deployment reuses the service the earlier attempt created
+		service, getErr := o.getDroneService(ctx, droneID, region)
+		if getErr == nil && service.Uri != "" {
+			log.Printf("Reusing existing service of drone %s in %s", droneID, region)
+			return service.Uri, nil
+		}
+	}
 	if err != nil {
 		return "", err
 	}
@@ -317,55 +724,84 @@ func (o *Orchestrator) deployDrone(ctx context.Context, droneID string, config *
 	return service.Uri, nil
 }
 
-// coordinateResearch coordinates the research process across drones
-func (o *Orchestrator) coordinateResearch(ctx context.Context, session *ResearchSession) error {
-	// 1. Break down the high-level topic into specific sub-queries.
-	log.Printf("Breaking down research topic: %s", session.Config.Topic)
-	subQueries, err := o.claudeAgent.GenerateSubQueries(ctx, session.Config.Topic, session.Config.ResearcherCount)
-	if err != nil {
-		return fmt.Errorf("failed to generate sub-queries: %w", err)
-	}
-	log.Printf("Generated %d sub-queries for topic '%s'", len(subQueries), session.Config.Topic)
-
-	// TODO: For now, we assume the number of drones matches the number of sub-queries.
-	// A more robust implementation would use a queue to distribute subQueries to available drones.
-	if len(subQueries) != len(session.Drones) {
-		log.Printf("Warning: The number of sub-queries (%d) does not match the number of drones (%d). Adjusting drone count for this session.", len(subQueries), len(session.Drones))
-		// This would be a place to dynamically adjust drone count if the architecture supported it.
-		// For now, we'll just truncate the query list to match the drone count.
-		if len(subQueries) > len(session.Drones) {
-			subQueries = subQueries[:len(session.Drones)]
-		}
+// droneEnv builds a drone's environment. Warm pool drones are deployed before
+// they belong to a session and receive their results topic with each task.
+func (o *Orchestrator) droneEnv(droneID string, config *schemas.ResearchConfig) []*runpb.EnvVar {
+	env := []*runpb.EnvVar{
+		{Name: "DRONE_ID", Values: &runpb.EnvVar_Value{Value: droneID}},
+		{Name: "GOOGLE_CLOUD_PROJECT", Values: &runpb.EnvVar_Value{Value: o.projectID}},
+		{Name: "HEARTBEAT_TOPIC", Values: &runpb.EnvVar_Value{Value: o.heartbeat.Topic}},
+		{Name: "HEARTBEAT_INTERVAL_SEC", Values: &runpb.EnvVar_Value{Value: strconv.Itoa(int(o.heartbeat.Interval.Seconds()))}},
 	}
-
-	// 2. Send a unique instruction to each drone.
-	o.mu.RLock()
-	drones := make([]*DroneInfo, 0, len(session.Drones))
-	for _, drone := range session.Drones {
-		drones = append(drones, drone)
+	if bucket := o.payloads.Bucket(); bucket != "" {
+		env = append(env, &runpb.EnvVar{Name: "RESULT_BUCKET", Values: &runpb.EnvVar_Value{Value: bucket}})
+	}
+	// Drones write offloaded result data with the same customer-managed key
+	if key := encryption.LoadConfig().StorageKey; key != "" {
+		env = append(env, &runpb.EnvVar{Name: "STORAGE_KMS_KEY", Values: &runpb.EnvVar_Value{Value: key}})
 	}
-	o.mu.RUnlock()
+	if config.SessionID != "" {
+		env = append(env,
+			&runpb.EnvVar{Name: "SESSION_ID", Values: &runpb.EnvVar_Value{Value: config.SessionID}},
+			// The drone will get its instructions via HTTP, but it needs to know which topic to publish results to.
+			&runpb.EnvVar{Name: "PUBSUB_TOPIC", Values: &runpb.EnvVar_Value{Value: resultsTopicName(config.Tenant, config.SessionID)}},
+			&runpb.EnvVar{Name: "METRICS_TOPIC", Values: &runpb.EnvVar_Value{Value: metricsTopicName(config.Tenant, config.SessionID)}},
+		)
+	}
+	return env
+}
 
-	for i, drone := range drones {
-		if i >= len(subQueries) {
-			break // Don't send instructions if we have more drones than tasks.
+// resultsTopicName returns the Pub/Sub topic drones publish a session's results to
+func resultsTopicName(tenantID, sessionID string) string {
+	return tenantResourceName(tenantID, fmt.Sprintf("research-results-%s", sessionID))
+}
+
+// coordinateResearch coordinates the research process across drones
+func (o *Orchestrator) coordinateResearch(ctx context.Context, session *ResearchSession) error {
+	// 1. Break down the high-level topic into specific sub-queries, unless a plan's were approved.
+	subQueries := session.Config.SubQueries
+	if len(subQueries) > 0 {
+		log.Printf("Using %d approved sub-queries for topic '%s'", len(subQueries), session.Config.Topic)
+	} else {
+		log.Printf("Breaking down research topic: %s", session.Config.Topic)
+		known := o.priorFindings(ctx, session.Config, session.Config.Topic)
+		var err error
+		subQueries, err = o.claudeAgent.GenerateSubQueries(ctx, session.Config.Topic, session.Config.ResearcherCount, known)
+		if err != nil {
+			return fmt.Errorf("failed to generate sub-queries: %w", err)
 		}
+		log.Printf("Generated %d sub-queries for topic '%s'", len(subQueries), session.Config.Topic)
 
-		// The drone needs to know its task ID (which can be the drone ID for simplicity)
-		// and the query. The other info is passed via env vars.
-		task := map[string]interface{}{
-			"subject": subQueries[i],
-			"run_id": session.Config.SessionID,
+		tokens := estimateTokens(session.Config.Topic)
+		for _, text := range append(known, subQueries...) {
+			tokens += estimateTokens(text)
+		}
+		session.Budget.addLLMTokens(tokens)
+		if o.enforceBudget(session) {
+			return fmt.Errorf("session %s is over budget", session.Config.SessionID)
 		}
+	}
 
-		if err := o.sendInstructionsToDrone(ctx, drone, task); err != nil {
-			log.Printf("Failed to send instructions to drone %s: %v", drone.ID, err)
-			drone.Status = "failed_to_instruct"
-		} else {
-			log.Printf("Successfully sent task '%s' to drone %s", subQueries[i], drone.ID)
-			drone.Status = "running"
+	// 2. Queue a task per sub-query. Each drone is sent a batch, and the next
+	// batch once it has reported, so drones that finish early take on the rest.
+	tasks := make([]map[string]interface{}, len(subQueries))
+	for i, query := range subQueries {
+		tasks[i] = map[string]interface{}{
+			"subject":        query,
+			"run_id":         session.Config.SessionID,
+			"task_id":        fmt.Sprintf("query-%d", i+1),
+			"pubsub_topic":   resultsTopicName(session.Config.Tenant, session.Config.SessionID),
+			"metrics_topic":  metricsTopicName(session.Config.Tenant, session.Config.SessionID),
+			"correlation_id": session.Config.CorrelationID,
 		}
 	}
+	if len(tasks) > len(session.Drones) {
+		log.Printf("Distributing %d sub-queries across %d drones", len(tasks), len(session.Drones))
+	}
+	o.mu.Lock()
+	session.dispatcher = newTaskDispatcher(tasks, o.dispatch.BatchSize)
+	o.mu.Unlock()
+	o.dispatchPending(ctx, session)
 
 	// Update progress file after dispatching all tasks
 	if err := o.updateProgressFile(session); err != nil {
@@ -394,11 +830,11 @@ func (o *Orchestrator) waitForCompletion(ctx context.Context, session *ResearchS
 			// Check completion status
 			o.mu.RLock()
 			completedCount := len(session.Results)
-			totalCount := session.Config.ResearcherCount
+			totalCount := expectedResults(session)
 			o.mu.RUnlock()
 
 			if completedCount >= totalCount {
-				log.Printf("All %d drones completed for session %s", totalCount, session.Config.SessionID)
+				log.Printf("All %d tasks completed for session %s", totalCount, session.Config.SessionID)
 				return &schemas.ResearchResult{
 					SessionID: session.Config.SessionID,
 					Status:    "completed",
@@ -409,13 +845,13 @@ func (o *Orchestrator) waitForCompletion(ctx context.Context, session *ResearchS
 				return nil, fmt.Errorf("research timeout after %v", timeout)
 			}
 
-			log.Printf("Research progress: %d/%d drones completed", completedCount, totalCount)
+			log.Printf("Research progress: %d/%d tasks completed", completedCount, totalCount)
 		}
 	}
 }
 
-// generateReport generates the final research report
-func (o *Orchestrator) generateReport(ctx context.Context, session *ResearchSession) (*schemas.ResearchReport, error) {
+// generateReport generates the final research report, written by agent
+func (o *Orchestrator) generateReport(ctx context.Context, session *ResearchSession, agent *ClaudeAgent) (*schemas.ResearchReport, error) {
 	// 1. Save individual drone results
 	resultFileDir := fmt.Sprintf("reports/results_%s", session.Config.SessionID)
 	if err := os.MkdirAll(resultFileDir, 0755); err != nil {
@@ -425,6 +861,10 @@ func (o *Orchestrator) generateReport(ctx context.Context, session *ResearchSess
 	var resultFilePaths []string
 	for _, result := range session.Results {
 		resultFilePath := fmt.Sprintf("%s/drone_%s.json", resultFileDir, result.DroneID)
+		// Drones that researched several sub-queries have a file for each
+		if result.TaskID != "" {
+			resultFilePath = fmt.Sprintf("%s/drone_%s_%s.json", resultFileDir, result.DroneID, result.TaskID)
+		}
 		jsonData, err := json.MarshalIndent(result, "", "  ")
 		if err != nil {
 			log.Printf("Warning: failed to marshal result for drone %s: %v", result.DroneID, err)
@@ -439,21 +879,70 @@ func (o *Orchestrator) generateReport(ctx context.Context, session *ResearchSess
 
 
 	// 2. Analyze collected data
-	analysis, err := o.analyzeResults(ctx, session.Results)
+	analysis, err := o.analyzeResults(ctx, session.Config.Topic, session.Results)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze results: %w", err)
 	}
+	if analysis.Graph != nil {
+		o.completeGraph(ctx, session, agent, analysis.Graph)
+		graphFilePath := fmt.Sprintf("%s/knowledge_graph.json", resultFileDir)
+		if jsonData, err := json.MarshalIndent(analysis.Graph, "", "  "); err != nil {
+			log.Printf("Warning: failed to marshal knowledge graph: %v", err)
+		} else if err := os.WriteFile(graphFilePath, jsonData, 0644); err != nil {
+			log.Printf("Warning: failed to save knowledge graph: %v", err)
+		} else {
+			resultFilePaths = append(resultFilePaths, graphFilePath)
+		}
+	}
+
+	// Merge large result sets into intermediate summaries so the report is written from bounded input
+	summaries, err := o.reduceResults(ctx, session, session.Results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reduce results: %w", err)
+	}
+	analysis.Summaries = summaries
+	if len(summaries) > 0 {
+		summaryFilePath := fmt.Sprintf("%s/summaries.json", resultFileDir)
+		if jsonData, err := json.MarshalIndent(summaries, "", "  "); err != nil {
+			log.Printf("Warning: failed to marshal result summaries: %v", err)
+		} else if err := os.WriteFile(summaryFilePath, jsonData, 0644); err != nil {
+			log.Printf("Warning: failed to save result summaries: %v", err)
+		} else {
+			resultFilePaths = append(resultFilePaths, summaryFilePath)
+		}
+	}
 
 	// 3. Generate structured report using Claude agent
-	report, err := o.claudeAgent.GenerateReport(ctx, session.Config, session.Results, analysis)
+	report, err := agent.GenerateReport(ctx, session.Config, session.Results, analysis)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate report: %w", err)
 	}
 
+	tokens := estimateTokens(report.Executive) + estimateTokens(report.Methodology)
+	for _, section := range report.Sections {
+		tokens += estimateTokens(section.Content)
+	}
+	session.Budget.addLLMTokens(tokens)
+
+	if session.Workflow != nil {
+		report.Sections = append(report.Sections, workflowSections(session.Workflow)...)
+	}
+	if analysis.Graph != nil && len(analysis.Graph.Gaps) > 0 {
+		report.Sections = append(report.Sections, coverageGapsSection(analysis.Graph))
+	}
+
+	if o.simulating(session.Config) {
+		report.Methodology = "Simulated session: the drone results this report is based on are synthetic.\n\n" + report.Methodology
+	}
+
 	report.ID = uuid.New().String()
 	report.SessionID = session.Config.SessionID
+	report.Tenant = session.Config.Tenant
 	report.CreatedAt = time.Now()
 
+	// Render the report's charts as images next to the drone results
+	o.saveReportCharts(report, resultFileDir)
+
 	// 4. Render the structured report to a user-facing Markdown file
 	markdownContent, err := o.renderReportToMarkdown(report, resultFilePaths)
 	if err != nil {
@@ -509,19 +998,22 @@ func getOrchestratorURL() string {
 	return getEnvOrDefault("ORCHESTRATOR_URL", "http://localhost:8080")
 }
 
-// GetReports returns all available reports
-func (o *Orchestrator) GetReports() []*schemas.ResearchReport {
+// GetReports returns the reports of a call's tenant
+func (o *Orchestrator) GetReports(ctx context.Context) []*schemas.ResearchReport {
+	tenantID := o.tenantID(ctx)
 	o.mu.RLock()
 	defer o.mu.RUnlock()
 
 	reports := make([]*schemas.ResearchReport, 0, len(o.reports))
 	for _, report := range o.reports {
-		reports = append(reports, report)
+		if report.Tenant == tenantID {
+			reports = append(reports, report)
+		}
 	}
 	return reports
 }
 
-// GetTemplates returns all available templates
+// GetTemplates returns all available templates, ordered by ID
 func (o *Orchestrator) GetTemplates() []*ResearchTemplate {
 	o.mu.RLock()
 	defer o.mu.RUnlock()
@@ -530,13 +1022,29 @@ func (o *Orchestrator) GetTemplates() []*ResearchTemplate {
 	for _, template := range o.templates {
 		templates = append(templates, template)
 	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].ID < templates[j].ID
+	})
 	return templates
 }
 
+// ListExternalTools returns the tools discovered on connected external MCP servers
+func (o *Orchestrator) ListExternalTools(serverName string) ([]ExternalTool, error) {
+	return o.mcpClient.ListTools(serverName)
+}
+
 // Shutdown gracefully shuts down the orchestrator
 func (o *Orchestrator) Shutdown() {
 	log.Println("Shutting down orchestrator...")
-	
+
+	// Stop starting scheduled runs before the clients they need are closed
+	o.stopScheduler()
+	o.stopRetention()
+	o.stopHeartbeatReceiver()
+
+	// Delete idle warm drones while the Cloud Run client can still reach them
+	o.warmPool.Shutdown(context.Background())
+
 	// Close clients
 	if o.firestoreClient != nil {
 		o.firestoreClient.Close()
@@ -547,7 +1055,7 @@ func (o *Orchestrator) Shutdown() {
 	if o.runClient != nil {
 		o.runClient.Close()
 	}
-	
+
 	// Shutdown MCP client
 	o.mcpClient.Shutdown()
 	
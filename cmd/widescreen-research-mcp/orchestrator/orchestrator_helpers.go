@@ -4,14 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	runpb "cloud.google.com/go/run/apiv2/runpb"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/charts"
 	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
 )
 
@@ -24,7 +27,10 @@ func (o *Orchestrator) loadTemplates() {
 		ID:          "company-research",
 		Name:        "Company Research Template",
 		Description: "Template for researching companies and organizations",
+		Builtin:     true,
 		Workflow: map[string]interface{}{
+			"subject":    "{{company_name}}",
+			"time_range": "{{time_range}}",
 			"steps": []string{
 				"company_overview",
 				"financial_data",
@@ -32,13 +38,19 @@ func (o *Orchestrator) loadTemplates() {
 				"market_position",
 			},
 		},
+		Parameters: []TemplateParameter{
+			{Name: "company_name", Type: ParamString, Description: "Company to research", Required: true},
+			{Name: "time_range", Type: ParamString, Description: "Period to cover", Default: "last 12 months"},
+		},
 	}
 
 	o.templates["academic-research"] = &ResearchTemplate{
 		ID:          "academic-research",
 		Name:        "Academic Research Template",
 		Description: "Template for academic and scientific research",
+		Builtin:     true,
 		Workflow: map[string]interface{}{
+			"time_range": "{{time_range}}",
 			"steps": []string{
 				"literature_review",
 				"methodology_analysis",
@@ -46,6 +58,9 @@ func (o *Orchestrator) loadTemplates() {
 				"peer_review",
 			},
 		},
+		Parameters: []TemplateParameter{
+			{Name: "time_range", Type: ParamString, Description: "Publication period to cover", Default: "last 5 years"},
+		},
 	}
 }
 
@@ -54,8 +69,9 @@ func (o *Orchestrator) createPubSubTopics(ctx context.Context) error {
 	// Create main orchestrator topics
 	topics := []string{
 		"research-commands",
-		"research-status",
+		statusTopic,
 		"research-metrics",
+		o.heartbeat.Topic,
 	}
 
 	for _, topicName := range topics {
@@ -90,7 +106,10 @@ func (o *Orchestrator) monitorSession(ctx context.Context, session *ResearchSess
 			o.mu.RLock()
 			drones := make([]*DroneInfo, 0, len(session.Drones))
 			for _, drone := range session.Drones {
-				drones = append(drones, drone)
+				// Unresponsive drones have been deleted
+				if drone.Status != "unresponsive" && drone.Status != "replaced" {
+					drones = append(drones, drone)
+				}
 			}
 			o.mu.RUnlock()
 
@@ -101,11 +120,19 @@ func (o *Orchestrator) monitorSession(ctx context.Context, session *ResearchSess
 					drone.Status = "unhealthy"
 				}
 			}
+			o.replaceSilentDrones(ctx, session)
+
+			// Stop the session once it has spent its budget
+			if o.enforceBudget(session) {
+				return
+			}
 
 			// Check for session timeout
 			if time.Since(session.StartTime) > time.Duration(session.Config.TimeoutMinutes)*time.Minute {
 				log.Printf("Session %s timed out", session.Config.SessionID)
-				session.Status = "timeout"
+				if o.setSessionStatus(session, "timeout") {
+					session.cancel()
+				}
 				return
 			}
 		}
@@ -114,6 +141,11 @@ func (o *Orchestrator) monitorSession(ctx context.Context, session *ResearchSess
 
 // checkDroneHealth checks the health of a drone
 func (o *Orchestrator) checkDroneHealth(ctx context.Context, drone *DroneInfo) error {
+	if drone.Simulated {
+		drone.LastCheckin = time.Now()
+		return nil
+	}
+
 	// Make HTTP health check request
 	healthURL := fmt.Sprintf("%s/health", drone.ServiceURL)
 	
@@ -139,13 +171,25 @@ func (o *Orchestrator) checkDroneHealth(ctx context.Context, drone *DroneInfo) e
 
 // sendInstructionsToDrone sends research instructions to a drone
 func (o *Orchestrator) sendInstructionsToDrone(ctx context.Context, drone *DroneInfo, task map[string]interface{}) error {
-	// Create command message
-	command := map[string]interface{}{
+	if drone.Simulated {
+		return o.simulateDroneTask(drone, task)
+	}
+	return o.retryInstructions(ctx, drone, func() error {
+		return postInstructions(ctx, drone, task)
+	})
+}
+
+// postInstructions makes one attempt to POST a task to a drone
+func postInstructions(ctx context.Context, drone *DroneInfo, task map[string]interface{}) error {
+	return postCommand(ctx, drone, map[string]interface{}{
 		"type":         "research_command",
 		"instructions": task,
 		"timestamp":    time.Now(),
-	}
+	})
+}
 
+// postCommand makes one attempt to POST a command to a drone's /instructions
+func postCommand(ctx context.Context, drone *DroneInfo, command map[string]interface{}) error {
 	// Send via HTTP POST to drone
 	instructURL := fmt.Sprintf("%s/instructions", drone.ServiceURL)
 	
@@ -167,39 +211,90 @@ func (o *Orchestrator) sendInstructionsToDrone(ctx context.Context, drone *Drone
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to send instructions, status: %d", resp.StatusCode)
+	// Drones accept tasks with 202 and run them in the background
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &instructionStatusError{status: resp.StatusCode}
 	}
 
 	return nil
 }
 
-// collectResults collects results from the research queue
+// collectResults collects results from the research queue. Results are added
+// to the session as they arrive and written to Firestore in batches of
+// FlushSize, or every FlushInterval, whichever comes first.
 func (o *Orchestrator) collectResults(ctx context.Context, session *ResearchSession) {
-	// Subscribe to results queue
-	if err := session.Queue.Subscribe(ctx, o.pubsubClient); err != nil {
-		log.Printf("Failed to subscribe to results queue: %v", err)
-		return
+	// Subscribe to results queue; simulated drones deliver to it directly
+	if !o.simulating(session.Config) {
+		if err := session.Queue.Subscribe(ctx, o.pubsubClient, o.payloads); err != nil {
+			log.Printf("Failed to subscribe to results queue: %v", err)
+			return
+		}
+		// Drone metrics are informational, so the session runs without them
+		if err := o.receiveDroneMetrics(ctx, session); err != nil {
+			log.Printf("Warning: Failed to subscribe to drone metrics: %v", err)
+		}
+	}
+
+	flushTicker := time.NewTicker(o.resultPipeline.FlushInterval)
+	defer flushTicker.Stop()
+
+	var pending []schemas.DroneResult
+	flush := func() {
+		if len(pending) > 0 {
+			o.flushResults(session, pending)
+			pending = nil
+		}
 	}
+	// Results collected before the session ended are still stored
+	defer flush()
 
 	// Process results as they arrive
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-session.Queue.Done():
+			return
+		case <-flushTicker.C:
+			flush()
 		case result := <-session.Queue.ResultChannel():
+			result, ok := o.checkResult(session, result)
+			if !ok {
+				continue
+			}
+			session.Budget.addExaCalls(exaCallsForResult(result))
+
 			o.mu.Lock()
+			// A drone that was replaced may still finish; its replacement's result is used instead
+			if drone, ok := session.Drones[result.DroneID]; ok && drone.Status == "replaced" {
+				o.mu.Unlock()
+				log.Printf("Dropping result from replaced drone %s", result.DroneID)
+				continue
+			}
 			session.Results = append(session.Results, result)
-			if drone, ok := session.Drones[result.DroneID]; ok {
+			collected := len(session.Results)
+			// Drones with tasks left keep running; idle ones can take more
+			idle := true
+			if session.dispatcher != nil {
+				idle = session.dispatcher.reported(result.DroneID, result.TaskID)
+			}
+			if drone, ok := session.Drones[result.DroneID]; ok && idle {
 				drone.Status = result.Status
 			}
 			o.mu.Unlock()
 
+			if idle && session.dispatcher != nil && session.dispatcher.remaining() > 0 {
+				go o.dispatchPending(ctx, session)
+			}
+
 			log.Printf("Collected result from drone %s with status %s", result.DroneID, result.Status)
+			if session.listener != nil {
+				session.listener(interimResult(session, result, collected))
+			}
 
-			// Update progress file
-			if err := o.updateProgressFile(session); err != nil {
-				log.Printf("Warning: failed to update progress file for session %s: %v", session.Config.SessionID, err)
+			pending = append(pending, result)
+			if len(pending) >= o.resultPipeline.FlushSize {
+				flush()
 			}
 
 		case err := <-session.Queue.ErrorChannel():
@@ -208,19 +303,23 @@ func (o *Orchestrator) collectResults(ctx context.Context, session *ResearchSess
 	}
 }
 
-// analyzeResults analyzes the collected research results
-func (o *Orchestrator) analyzeResults(ctx context.Context, results []schemas.DroneResult) (*DataAnalysis, error) {
+// analyzeResults analyzes the collected research results of a topic
+func (o *Orchestrator) analyzeResults(ctx context.Context, topic string, results []schemas.DroneResult) (*DataAnalysis, error) {
 	analysis := &DataAnalysis{
 		Patterns:    make([]schemas.Pattern, 0),
 		TopInsights: make([]string, 0),
 		Statistics:  make(map[string]interface{}),
-		Duration:    time.Since(results[0].CompletedAt),
 		Metrics: schemas.ResearchMetrics{
 			DronesProvisioned:   len(results),
 			DronesCompleted:     0,
 			DataPointsCollected: 0,
 		},
 	}
+	// Workflows without drone research steps have no results
+	if len(results) == 0 {
+		return analysis, nil
+	}
+	analysis.Duration = time.Since(results[0].CompletedAt)
 
 	// Count successful completions
 	for _, result := range results {
@@ -232,12 +331,50 @@ func (o *Orchestrator) analyzeResults(ctx context.Context, results []schemas.Dro
 		}
 	}
 
+	// Merge findings that several drones reported
+	findings, duplicates := dedupeFindings(results)
+	analysis.Findings = findings
+	analysis.Statistics["unique_findings"] = len(findings)
+	analysis.Statistics["duplicate_findings_merged"] = duplicates
+
+	// Link the entities the findings name, to export and to find coverage gaps
+	analysis.Graph = buildKnowledgeGraph(topic, findings)
+	analysis.Statistics["graph_entities"] = len(analysis.Graph.Entities)
+	analysis.Statistics["coverage_gaps"] = len(analysis.Graph.Gaps)
+
+	// Weight findings by how credible their sources are
+	if o.sourceScorer != nil {
+		analysis.SourceScores = o.sourceScorer.scoreSources(results)
+		weightFindings(findings, analysis.SourceScores)
+	}
+
+	// Group findings into topics, which structure the report's sections
+	if o.embedder != nil {
+		clusters, err := clusterFindings(ctx, o.embedder, findings, clusterSettings())
+		if err != nil {
+			log.Printf("Warning: Failed to cluster findings: %v", err)
+		}
+		analysis.Clusters = clusters
+		analysis.Statistics["topic_clusters"] = len(clusters)
+	}
+
 	// Extract patterns
 	patterns := o.extractPatterns(results)
 	analysis.Patterns = patterns
 
 	// Generate insights
 	analysis.TopInsights = o.generateInsights(patterns, results)
+	// Lead with the best supported findings
+	var credible []string
+	for _, finding := range findings {
+		if len(credible) == 3 {
+			break
+		}
+		if finding.Credibility >= 0.5 {
+			credible = append(credible, fmt.Sprintf("%s (credibility %.2f, reported by %d drones)", finding.Claim, finding.Credibility, len(finding.Drones)))
+		}
+	}
+	analysis.TopInsights = append(credible, analysis.TopInsights...)
 
 	// Calculate statistics
 	analysis.Statistics["total_data_points"] = analysis.Metrics.DataPointsCollected
@@ -252,6 +389,11 @@ func (o *Orchestrator) analyzeResults(ctx context.Context, results []schemas.Dro
 		analysis.AverageConfidence = totalConfidence / float64(len(patterns))
 	}
 
+	// Conflicting claims make the session's findings less certain
+	analysis.Contradictions = detectContradictions(findings)
+	analysis.Statistics["contradictions"] = len(analysis.Contradictions)
+	analysis.AverageConfidence *= 1 - contradictionPenalty(analysis.Contradictions, len(findings))
+
 	return analysis, nil
 }
 
@@ -282,15 +424,18 @@ func (o *Orchestrator) generateInsights(patterns []schemas.Pattern, results []sc
 }
 
 // calculateMetrics calculates final metrics for the research session
-func (o *Orchestrator) calculateMetrics(session *ResearchSession) schemas.ResearchMetrics {
+func (o *Orchestrator) calculateMetrics(ctx context.Context, session *ResearchSession) schemas.ResearchMetrics {
 	metrics := schemas.ResearchMetrics{
 		DronesProvisioned:   len(session.Drones),
 		DronesCompleted:     0,
 		DronesFailed:        0,
 		TotalDuration:       time.Since(session.StartTime),
 		DataPointsCollected: 0,
-		CostEstimate:        0.0,
+		Cost:                o.sessionSpend(session),
 	}
+	o.applyMeasuredCost(ctx, session, &metrics.Cost)
+	metrics.CostEstimate = metrics.Cost.TotalUSD
+	o.applyDroneMetrics(session, &metrics)
 
 	// Calculate from results
 	for _, result := range session.Results {
@@ -302,16 +447,15 @@ func (o *Orchestrator) calculateMetrics(session *ResearchSession) schemas.Resear
 		}
 	}
 
-	// Estimate costs based on Cloud Run pricing
-	cpuHours := float64(metrics.DronesProvisioned) * metrics.TotalDuration.Hours()
-	metrics.CostEstimate = cpuHours * 0.0000024 * 1000 // Approximate cost per vCPU-ms
-
 	return metrics
 }
 
 // storeReport stores the research report in Firestore
 func (o *Orchestrator) storeReport(ctx context.Context, report *schemas.ResearchReport) error {
-	doc := o.firestoreClient.Collection("research_reports").Doc(report.ID)
+	if o.firestoreClient == nil {
+		return nil
+	}
+	doc := o.collection(o.tenantID(ctx), reportsCollection).Doc(report.ID)
 	_, err := doc.Set(ctx, report)
 	return err
 }
@@ -339,10 +483,11 @@ func (o *Orchestrator) updateProgressFile(session *ResearchSession) error {
 	for id, drone := range session.Drones {
 		content.WriteString(fmt.Sprintf("| %s | %s |\n", id, drone.Status))
 	}
+	collected, expected := len(session.Results), expectedResults(session)
 	o.mu.RUnlock()
 
-	// Add results summary
-	content.WriteString(fmt.Sprintf("\n**Results Collected:** %d / %d\n", len(session.Results), len(session.Drones)))
+	// Add results summary; drones may take several tasks, so count tasks rather than drones
+	content.WriteString(fmt.Sprintf("\n**Results Collected:** %d / %d\n", collected, expected))
 
 	return os.WriteFile(filePath, []byte(content.String()), 0644)
 }
@@ -364,6 +509,17 @@ func (o *Orchestrator) renderReportToMarkdown(report *schemas.ResearchReport, re
 	for _, section := range report.Sections {
 		content.WriteString(fmt.Sprintf("## %s\n\n", section.Title))
 		content.WriteString(section.Content + "\n\n")
+		for _, visualization := range section.Visualizations {
+			if visualization.Image == "" {
+				continue
+			}
+			// The report is saved in reports/, so link images relative to it
+			image, err := filepath.Rel("reports", visualization.Image)
+			if err != nil {
+				image = visualization.Image
+			}
+			content.WriteString(fmt.Sprintf("![%s](./%s)\n\n", visualization.Title, filepath.ToSlash(image)))
+		}
 		if len(section.Insights) > 0 {
 			content.WriteString("### Key Insights\n\n")
 			for _, insight := range section.Insights {
@@ -385,22 +541,79 @@ func (o *Orchestrator) renderReportToMarkdown(report *schemas.ResearchReport, re
 	return content.String(), nil
 }
 
+// saveReportCharts renders the report's charts as SVG files in dir and records
+// where each was saved. Charts that fail to render are left out of the report.
+func (o *Orchestrator) saveReportCharts(report *schemas.ResearchReport, dir string) {
+	count := 0
+	for i := range report.Sections {
+		for j := range report.Sections[i].Visualizations {
+			visualization := &report.Sections[i].Visualizations[j]
+			if !charts.Supported(visualization.Type) {
+				continue
+			}
+			image, err := charts.RenderSVG(*visualization)
+			if errors.Is(err, charts.ErrNoData) {
+				continue
+			}
+			if err != nil {
+				log.Printf("Warning: Failed to render chart %q: %v", visualization.Title, err)
+				continue
+			}
+			count++
+			imagePath := fmt.Sprintf("%s/chart_%d.svg", dir, count)
+			if err := os.WriteFile(imagePath, image, 0644); err != nil {
+				log.Printf("Warning: Failed to save chart %q: %v", visualization.Title, err)
+				continue
+			}
+			visualization.Image = imagePath
+		}
+	}
+}
+
 // cleanupSession cleans up resources after a research session
 func (o *Orchestrator) cleanupSession(ctx context.Context, session *ResearchSession) {
+	// A session finishing while the orchestrator drains may be cleaned up twice
+	o.mu.Lock()
+	if session.cleaning {
+		o.mu.Unlock()
+		return
+	}
+	session.cleaning = true
+	o.mu.Unlock()
+
 	log.Printf("Cleaning up session %s", session.Config.SessionID)
 
-	// Delete Cloud Run services
+	// Replacements still deploying add their drones to the session when done
+	session.replacements.Wait()
+	var drones []*DroneInfo
+	o.mu.RLock()
 	for _, drone := range session.Drones {
-		if err := o.deleteDroneService(ctx, drone.ID); err != nil {
+		// Unresponsive drones were deleted when they were detected
+		if drone.Simulated || drone.Status == "unresponsive" || drone.Status == "replaced" {
+			continue
+		}
+		drones = append(drones, drone)
+	}
+	o.mu.RUnlock()
+
+	// Delete Cloud Run services
+	for _, drone := range drones {
+		if err := o.deleteDroneService(ctx, drone.ID, drone.Region); err != nil {
 			log.Printf("Failed to delete drone service %s: %v", drone.ID, err)
 		}
 	}
 
 	// Delete Pub/Sub resources
-	topicName := fmt.Sprintf("research-results-%s", session.Config.SessionID)
-	topic := o.pubsubClient.Topic(topicName)
-	if err := topic.Delete(ctx); err != nil {
-		log.Printf("Failed to delete topic %s: %v", topicName, err)
+	if !o.simulating(session.Config) {
+		topicName := resultsTopicName(session.Config.Tenant, session.Config.SessionID)
+		topic := o.pubsubClient.Topic(topicName)
+		if err := topic.Delete(ctx); err != nil {
+			log.Printf("Failed to delete topic %s: %v", topicName, err)
+		}
+		metricsTopic := metricsTopicName(session.Config.Tenant, session.Config.SessionID)
+		if err := o.pubsubClient.Topic(metricsTopic).Delete(ctx); err != nil {
+			log.Printf("Failed to delete topic %s: %v", metricsTopic, err)
+		}
 	}
 
 	// Close queue
@@ -413,9 +626,9 @@ func (o *Orchestrator) cleanupSession(ctx context.Context, session *ResearchSess
 }
 
 // deleteDroneService deletes a drone Cloud Run service
-func (o *Orchestrator) deleteDroneService(ctx context.Context, droneID string) error {
+func (o *Orchestrator) deleteDroneService(ctx context.Context, droneID, region string) error {
 	req := &runpb.DeleteServiceRequest{
-		Name: fmt.Sprintf("projects/%s/locations/%s/services/%s", o.projectID, o.region, droneID),
+		Name: fmt.Sprintf("projects/%s/locations/%s/services/%s", o.projectID, region, droneID),
 	}
 
 	operation, err := o.runClient.DeleteService(ctx, req)
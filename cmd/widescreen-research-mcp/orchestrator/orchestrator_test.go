@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
 )
 
 // MockGCP is a mock implementation of the GCP clients.
@@ -45,9 +46,9 @@ func TestOrchestrateResearch_E2E_Placeholder(t *testing.T) {
 // Example of a test with a real orchestrator but without full E2E simulation.
 func TestOrchestratorInitialization(t *testing.T) {
 	// This test ensures the orchestrator can be initialized.
-	// It requires GOOGLE_CLOUD_PROJECT to be set.
-	if os.Getenv("GOOGLE_CLOUD_PROJECT") == "" {
-		t.Skip("Skipping orchestrator initialization test: GOOGLE_CLOUD_PROJECT not set.")
+	// It requires GOOGLE_CLOUD_PROJECT to be set, or the Firestore and Pub/Sub emulators.
+	if os.Getenv("GOOGLE_CLOUD_PROJECT") == "" && !gcp.EmulatorMode() {
+		t.Skip("Skipping orchestrator initialization test: GOOGLE_CLOUD_PROJECT not set and no emulators configured.")
 	}
 
 	_, err := NewOrchestrator()
@@ -64,7 +65,7 @@ func TestBreakDownResearchTopicMock(t *testing.T) {
 		ResearcherCount: 3,
 	}
 
-	queries, err := agent.GenerateSubQueries(context.Background(), config.Topic, config.ResearcherCount)
+	queries, err := agent.GenerateSubQueries(context.Background(), config.Topic, config.ResearcherCount, nil)
 	if err != nil {
 		t.Fatalf("GenerateSubQueries returned an error: %v", err)
 	}
@@ -0,0 +1,136 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// tableNamePattern limits the pgvector table to names that need no quoting
+var tableNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// pgVectorStore indexes records in a PostgreSQL table with a pgvector column,
+// creating the extension and table when it starts
+type pgVectorStore struct {
+	pool  *pgxpool.Pool
+	table string
+}
+
+func newPgVectorStore(ctx context.Context, config VectorStoreConfig) (*pgVectorStore, error) {
+	if !tableNamePattern.MatchString(config.Table) {
+		return nil, fmt.Errorf("invalid VECTOR_STORE_TABLE %q", config.Table)
+	}
+	pool, err := pgxpool.New(ctx, config.PostgresURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+
+	// The vector column has no fixed dimensions, so changing the embedding
+	// model needs no migration; records of another model are not matched
+	statements := []string{
+		`CREATE EXTENSION IF NOT EXISTS vector`,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id TEXT PRIMARY KEY,
+			tenant TEXT NOT NULL,
+			session_id TEXT NOT NULL,
+			topic TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			text TEXT NOT NULL,
+			sources TEXT[] NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL,
+			embedding vector NOT NULL
+		)`, config.Table),
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS report_id TEXT NOT NULL DEFAULT ''`, config.Table),
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT ''`, config.Table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %[1]s_session ON %[1]s (tenant, session_id)`, config.Table),
+	}
+	for _, statement := range statements {
+		if _, err := pool.Exec(ctx, statement); err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("failed to create table %s: %w", config.Table, err)
+		}
+	}
+	return &pgVectorStore{pool: pool, table: config.Table}, nil
+}
+
+// vectorLiteral formats a vector as pgvector's text input, such as [0.1,0.2]
+func vectorLiteral(vector []float64) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, v := range vector {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(v, 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// Upsert implements VectorStore
+func (s *pgVectorStore) Upsert(ctx context.Context, tenantID string, records []FindingRecord) error {
+	query := fmt.Sprintf(`INSERT INTO %s (id, tenant, session_id, topic, kind, text, sources, created_at, embedding, report_id, title)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9::vector, $10, $11)
+		ON CONFLICT (id) DO UPDATE SET topic = EXCLUDED.topic, text = EXCLUDED.text, sources = EXCLUDED.sources,
+			created_at = EXCLUDED.created_at, embedding = EXCLUDED.embedding, report_id = EXCLUDED.report_id, title = EXCLUDED.title`, s.table)
+	batch := &pgx.Batch{}
+	for _, record := range records {
+		batch.Queue(query, record.ID, tenantID, record.SessionID, record.Topic, record.Kind, record.Text,
+			nonNilStrings(record.Sources), record.CreatedAt, vectorLiteral(record.Vector), record.ReportID, record.Title)
+	}
+	if err := s.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to upsert records: %w", err)
+	}
+	return nil
+}
+
+// Search implements VectorStore. Similarity is one minus the cosine distance.
+func (s *pgVectorStore) Search(ctx context.Context, tenantID string, vector []float64, kinds []string, limit int) ([]RecordMatch, error) {
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`SELECT id, session_id, topic, kind, text, sources, created_at, report_id, title, 1 - (embedding <=> $2::vector)
+		FROM %s WHERE tenant = $1 AND vector_dims(embedding) = $3 AND (cardinality($5::text[]) = 0 OR kind = ANY($5))
+		ORDER BY embedding <=> $2::vector LIMIT $4`, s.table),
+		tenantID, vectorLiteral(vector), len(vector), limit, nonNilStrings(kinds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query records: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []RecordMatch
+	for rows.Next() {
+		var match RecordMatch
+		record := &match.Record
+		if err := rows.Scan(&record.ID, &record.SessionID, &record.Topic, &record.Kind, &record.Text, &record.Sources, &record.CreatedAt,
+			&record.ReportID, &record.Title, &match.Score); err != nil {
+			return nil, fmt.Errorf("failed to read record: %w", err)
+		}
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
+
+// DeleteSession implements VectorStore
+func (s *pgVectorStore) DeleteSession(ctx context.Context, tenantID, sessionID string, kinds []string) ([]string, error) {
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`DELETE FROM %s WHERE tenant = $1 AND session_id = $2
+		AND (cardinality($3::text[]) = 0 OR kind = ANY($3)) RETURNING id`, s.table), tenantID, sessionID, nonNilStrings(kinds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete records of session %s: %w", sessionID, err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete records of session %s: %w", sessionID, err)
+	}
+	return ids, nil
+}
+
+// nonNilStrings returns values, or an empty slice so it is sent as an empty array rather than NULL
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,95 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// PlanResearch generates a research session's sub-queries and places its drones
+// without provisioning anything, so the plan can be approved before it runs.
+// Passing the plan's sub-queries back in the config runs exactly those.
+func (o *Orchestrator) PlanResearch(ctx context.Context, config *schemas.ResearchConfig) (*schemas.ResearchPlan, error) {
+	estimate, err := o.EstimateResearch(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	planned := estimate.Config
+
+	// Fill in the chosen template's workflow the way the session would
+	if planned.TemplateID != "" && planned.Workflow == nil {
+		workflow, err := o.InstantiateTemplate(planned.TemplateID, planned.TemplateParams)
+		if err != nil {
+			return nil, fmt.Errorf("failed to instantiate template: %w", err)
+		}
+		planned.Workflow = workflow
+	}
+	steps, err := parseWorkflowSteps(planned.Workflow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workflow: %w", err)
+	}
+
+	plan := &schemas.ResearchPlan{
+		Config:   planned,
+		Regions:  o.sessionRegions(planned),
+		Estimate: estimate,
+	}
+
+	// Warm drones are taken first, when the session can use the orchestrator's region
+	warm := 0
+	for _, region := range plan.Regions {
+		if region == o.region && o.usesWarmPool(planned.Tenant) {
+			warm = estimate.WarmDrones
+		}
+	}
+	planner := newRegionPlanner(plan.Regions)
+	plan.Drones = make([]schemas.PlannedDrone, planned.ResearcherCount)
+	for i := range plan.Drones {
+		plan.Drones[i] = schemas.PlannedDrone{Index: i, Region: planner.candidates(i)[0]}
+		if i < warm {
+			plan.Drones[i].Region = o.region
+			plan.Drones[i].Warm = true
+		}
+	}
+
+	if len(steps) == 0 {
+		plan.SubQueries = planned.SubQueries
+		if len(plan.SubQueries) == 0 {
+			plan.SubQueries, err = o.claudeAgent.GenerateSubQueries(ctx, planned.Topic, planned.ResearcherCount, o.priorFindings(ctx, planned, planned.Topic))
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate sub-queries: %w", err)
+			}
+		}
+		for i := range plan.Drones {
+			if i < len(plan.SubQueries) {
+				plan.Drones[i].SubQuery = plan.SubQueries[i]
+			}
+		}
+		return plan, nil
+	}
+
+	for _, step := range steps {
+		plannedStep := schemas.PlannedStep{
+			Name:      step.Name,
+			Operation: step.Operation,
+			DependsOn: step.DependsOn,
+		}
+		switch step.Operation {
+		case StepDroneResearch:
+			plannedStep.Subject = stepSubject(planned, step)
+			plannedStep.Drones = planned.ResearcherCount
+			if step.Drones > 0 && step.Drones < plannedStep.Drones {
+				plannedStep.Drones = step.Drones
+			}
+			plannedStep.SubQueries, err = o.claudeAgent.GenerateSubQueries(ctx, plannedStep.Subject, plannedStep.Drones, o.priorFindings(ctx, planned, plannedStep.Subject))
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate sub-queries for step %s: %w", step.Name, err)
+			}
+		case StepWebsets, StepSequentialThinking:
+			plannedStep.Subject = stepSubject(planned, step)
+		}
+		plan.Workflow = append(plan.Workflow, plannedStep)
+	}
+	return plan, nil
+}
@@ -0,0 +1,62 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/client"
+)
+
+// session is one connection to an external MCP server. Stdio servers get one
+// subprocess per session.
+type session struct {
+	client *client.Client
+	// stop ends the supervisor of a stdio session; nil for remote sessions
+	stop chan struct{}
+}
+
+// close stops supervision and closes the session's connection
+func (s *session) close() error {
+	if s.stop != nil {
+		close(s.stop)
+	}
+	return s.client.Close()
+}
+
+// sessionPool hands out sessions for exclusive use by a single call
+type sessionPool struct {
+	sessions []*session
+	idle     chan *session
+}
+
+// newSessionPool creates a pool with every session initially idle
+func newSessionPool(sessions []*session) *sessionPool {
+	pool := &sessionPool{
+		sessions: sessions,
+		idle:     make(chan *session, len(sessions)),
+	}
+	for _, s := range sessions {
+		pool.idle <- s
+	}
+	return pool
+}
+
+// checkout waits for an idle session or for ctx to be done
+func (p *sessionPool) checkout(ctx context.Context) (*session, error) {
+	select {
+	case s := <-p.idle:
+		return s, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for an idle session: %w", ctx.Err())
+	}
+}
+
+// checkin returns a session to the pool
+func (p *sessionPool) checkin(s *session) {
+	p.idle <- s
+}
+
+// size returns the number of sessions in the pool
+func (p *sessionPool) size() int {
+	return len(p.sessions)
+}
@@ -0,0 +1,96 @@
+package orchestrator
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ProvisioningConfig limits how fast drones are deployed, so large sessions
+// stay under the Cloud Run Admin API's rate limits
+type ProvisioningConfig struct {
+	// Parallelism is how many drone deployments may run at once, across all sessions
+	Parallelism int
+	// Stagger is the least time between the starts of two deployments
+	Stagger time.Duration
+}
+
+// LoadProvisioningConfig reads the provisioning limits from the environment
+func LoadProvisioningConfig() ProvisioningConfig {
+	config := ProvisioningConfig{
+		Parallelism: 10,
+		Stagger:     250 * time.Millisecond,
+	}
+	if n, err := strconv.Atoi(getEnvOrDefault("PROVISION_PARALLELISM", "")); err == nil && n > 0 {
+		config.Parallelism = n
+	}
+	if ms, err := strconv.Atoi(getEnvOrDefault("PROVISION_STAGGER_MS", "")); err == nil && ms >= 0 {
+		config.Stagger = time.Duration(ms) * time.Millisecond
+	}
+	return config
+}
+
+// deployThrottle bounds concurrent drone deployments with a semaphore and
+// spaces out their starts
+type deployThrottle struct {
+	slots   chan struct{}
+	stagger time.Duration
+
+	mu sync.Mutex
+	// next is the earliest time the next deployment may start
+	next time.Time
+}
+
+// newDeployThrottle creates a throttle for config
+func newDeployThrottle(config ProvisioningConfig) *deployThrottle {
+	return &deployThrottle{
+		slots:   make(chan struct{}, config.Parallelism),
+		stagger: config.Stagger,
+	}
+}
+
+// acquire waits for a deployment slot and the deployment's turn to start. The
+// returned function releases the slot once the deployment has finished. A nil
+// throttle does not limit deployments.
+func (t *deployThrottle) acquire(ctx context.Context) (func(), error) {
+	if t == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case t.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release := func() { <-t.slots }
+
+	t.mu.Lock()
+	now := time.Now()
+	start := t.next
+	if start.Before(now) {
+		start = now
+	}
+	t.next = start.Add(t.stagger)
+	t.mu.Unlock()
+
+	if wait := start.Sub(now); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+// waves returns how many rounds of deployments n drones need at the throttle's parallelism
+func (t *deployThrottle) waves(n int) int {
+	if t == nil || n <= 0 {
+		return 1
+	}
+	return (n + cap(t.slots) - 1) / cap(t.slots)
+}
@@ -2,38 +2,51 @@ package orchestrator
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
 	"cloud.google.com/go/pubsub"
 	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/payload"
 )
 
-// ResearchQueue manages the queue for collecting research results
+// ResearchQueue manages the queue for collecting research results. Its buffer
+// is bounded: once it is full, new results wait for the collector to catch up,
+// and Pub/Sub stops delivering more than the buffer can hold.
 type ResearchQueue struct {
+	tenantID      string
 	sessionID     string
 	subscription  *pubsub.Subscription
-	results       []schemas.DroneResult
+	// payloads decodes compressed results and loads offloaded result data
+	payloads      *payload.Codec
+	received      int
+	seen          map[string]bool
 	mu            sync.Mutex
 	resultChan    chan schemas.DroneResult
 	errorChan     chan error
+	done          chan struct{}
+	closed        bool
 }
 
-// NewResearchQueue creates a new research queue
-func NewResearchQueue(sessionID string) *ResearchQueue {
+// NewResearchQueue creates a new research queue for a tenant's session that
+// buffers up to bufferSize results
+func NewResearchQueue(tenantID, sessionID string, bufferSize int) *ResearchQueue {
 	return &ResearchQueue{
+		tenantID:   tenantID,
 		sessionID:  sessionID,
-		results:    make([]schemas.DroneResult, 0),
-		resultChan: make(chan schemas.DroneResult, 100),
+		seen:       make(map[string]bool),
+		resultChan: make(chan schemas.DroneResult, bufferSize),
 		errorChan:  make(chan error, 10),
+		done:       make(chan struct{}),
 	}
 }
 
-// Subscribe subscribes to the results topic
-func (q *ResearchQueue) Subscribe(ctx context.Context, client *pubsub.Client) error {
-	topicName := fmt.Sprintf("research-results-%s", q.sessionID)
+// Subscribe subscribes to the results topic, decoding results with payloads
+func (q *ResearchQueue) Subscribe(ctx context.Context, client *pubsub.Client, payloads *payload.Codec) error {
+	q.payloads = payloads
+	topicName := resultsTopicName(q.tenantID, q.sessionID)
 	topic := client.Topic(topicName)
 
 	// Create topic if it doesn't exist
@@ -49,14 +62,19 @@ func (q *ResearchQueue) Subscribe(ctx context.Context, client *pubsub.Client) er
 	}
 
 	// Create subscription
-	subscriptionName := fmt.Sprintf("research-results-sub-%s", q.sessionID)
+	subscriptionName := tenantResourceName(q.tenantID, fmt.Sprintf("research-results-sub-%s", q.sessionID))
 	q.subscription = client.Subscription(subscriptionName)
 
 	exists, err = q.subscription.Exists(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to check subscription existence: %w", err)
 	}
-	if !exists {
+	if exists {
+		// Ordering is fixed when a subscription is created
+		if config, err := q.subscription.Config(ctx); err == nil && !config.EnableMessageOrdering {
+			log.Printf("Warning: Subscription %s does not have message ordering enabled, drone results may arrive out of order", subscriptionName)
+		}
+	} else {
 		q.subscription, err = client.CreateSubscription(ctx, subscriptionName, pubsub.SubscriptionConfig{
 			Topic:                 topic,
 			AckDeadline:           30 * time.Second,
@@ -69,33 +87,40 @@ func (q *ResearchQueue) Subscribe(ctx context.Context, client *pubsub.Client) er
 		}
 	}
 
+	// Flow control: hold no more unacknowledged messages than the buffer has room for
+	q.subscription.ReceiveSettings.MaxOutstandingMessages = cap(q.resultChan)
+
 	// Start receiving messages
 	go q.receiveMessages(ctx)
 
 	return nil
 }
 
-// receiveMessages receives messages from the subscription
+// receiveMessages receives messages from the subscription. Drones publish with
+// their ID as the ordering key, so each drone's results are handled one at a
+// time in the order they were published.
 func (q *ResearchQueue) receiveMessages(ctx context.Context) {
 	err := q.subscription.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
 		// Parse the message
-		var result schemas.DroneResult
-		if err := json.Unmarshal(msg.Data, &result); err != nil {
-			q.errorChan <- fmt.Errorf("failed to unmarshal result: %w", err)
-			msg.Nack()
+		result, err := q.payloads.Decode(msg.Data, msg.Attributes)
+		if err != nil {
+			q.reportError(err)
+			// Redelivering a malformed message would hold back the rest of the drone's results
+			msg.Ack()
 			return
 		}
+		// Data too large for the message was stored in Cloud Storage
+		if err := q.payloads.Resolve(ctx, resultsTopicName(q.tenantID, q.sessionID), &result); err != nil {
+			q.reportError(err)
+			result.Status = schemas.ResultStatusFailed
+			result.Error = fmt.Sprintf("failed to load result data: %v", err)
+		}
 
-		// Add to results
-		q.mu.Lock()
-		q.results = append(q.results, result)
-		q.mu.Unlock()
-
-		// Send to channel
-		select {
-		case q.resultChan <- result:
-		default:
-			// Channel full, log warning
+		// Pub/Sub delivers at least once, so a result may arrive more than once.
+		// A result the full buffer could not take before shutdown is redelivered.
+		if !q.accept(ctx, result, resultKey(result, msg.ID)) {
+			msg.Nack()
+			return
 		}
 
 		// Acknowledge the message
@@ -103,25 +128,74 @@ func (q *ResearchQueue) receiveMessages(ctx context.Context) {
 	})
 
 	if err != nil {
-		q.errorChan <- fmt.Errorf("subscription receive error: %w", err)
+		q.reportError(fmt.Errorf("subscription receive error: %w", err))
 	}
 }
 
-// GetResults returns all collected results
-func (q *ResearchQueue) GetResults() []schemas.DroneResult {
+// Deliver adds a result that did not arrive through Pub/Sub, such as a simulated
+// drone's, waiting while the buffer is full
+func (q *ResearchQueue) Deliver(result schemas.DroneResult) {
+	q.accept(context.Background(), result, resultKey(result, "local/"+result.DroneID))
+}
+
+// accept passes a result on to ResultChannel unless one with the same key was
+// already received or the queue is closed, waiting while the buffer is full. It
+// returns false if ctx ended before the buffer had room, so the result can be
+// delivered again.
+func (q *ResearchQueue) accept(ctx context.Context, result schemas.DroneResult, key string) bool {
 	q.mu.Lock()
-	defer q.mu.Unlock()
+	if q.closed {
+		q.mu.Unlock()
+		return true
+	}
+	if q.seen[key] {
+		q.mu.Unlock()
+		log.Printf("Dropping duplicate result %s for session %s", key, q.sessionID)
+		return true
+	}
+	q.seen[key] = true
+	q.mu.Unlock()
 
-	results := make([]schemas.DroneResult, len(q.results))
-	copy(results, q.results)
-	return results
+	select {
+	case q.resultChan <- result:
+		q.mu.Lock()
+		q.received++
+		q.mu.Unlock()
+		return true
+	case <-q.done:
+		return true
+	case <-ctx.Done():
+		q.mu.Lock()
+		delete(q.seen, key)
+		q.mu.Unlock()
+		return false
+	}
 }
 
-// GetResultCount returns the number of results collected
+// reportError passes an error on to ErrorChannel, or logs it if the channel is full
+func (q *ResearchQueue) reportError(err error) {
+	select {
+	case q.errorChan <- err:
+	case <-q.done:
+	default:
+		log.Printf("Queue error for session %s: %v", q.sessionID, err)
+	}
+}
+
+// resultKey identifies a result across redeliveries: by drone and task when the
+// drone reports its task, otherwise by Pub/Sub message ID
+func resultKey(result schemas.DroneResult, messageID string) string {
+	if result.TaskID != "" {
+		return result.DroneID + "/" + result.TaskID
+	}
+	return "message/" + messageID
+}
+
+// GetResultCount returns the number of results passed on to ResultChannel
 func (q *ResearchQueue) GetResultCount() int {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	return len(q.results)
+	return q.received
 }
 
 // ResultChannel returns the channel for receiving results
@@ -134,8 +208,19 @@ func (q *ResearchQueue) ErrorChannel() <-chan error {
 	return q.errorChan
 }
 
-// Close closes the queue and cleans up resources
+// Done is closed when the queue is closed
+func (q *ResearchQueue) Done() <-chan struct{} {
+	return q.done
+}
+
+// Close closes the queue, releasing senders waiting for buffer space. Its
+// channels are left open, since a sender may still be waiting on them.
 func (q *ResearchQueue) Close() {
-	close(q.resultChan)
-	close(q.errorChan)
-}
\ No newline at end of file
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	close(q.done)
+}
@@ -0,0 +1,148 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+func TestResultKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		result    schemas.DroneResult
+		messageID string
+		want      string
+	}{
+		{"drone and task", schemas.DroneResult{DroneID: "d1", TaskID: "t1"}, "m1", "d1/t1"},
+		{"message without task", schemas.DroneResult{DroneID: "d1"}, "m1", "message/m1"},
+	}
+	for _, tt := range tests {
+		if got := resultKey(tt.result, tt.messageID); got != tt.want {
+			t.Errorf("%s: resultKey() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResearchQueueDeduplicates(t *testing.T) {
+	type delivery struct {
+		result    schemas.DroneResult
+		messageID string
+	}
+	tests := []struct {
+		name       string
+		deliveries []delivery
+		want       int
+	}{
+		{
+			name: "redelivered message",
+			deliveries: []delivery{
+				{schemas.DroneResult{DroneID: "d1"}, "m1"},
+				{schemas.DroneResult{DroneID: "d1"}, "m1"},
+			},
+			want: 1,
+		},
+		{
+			name: "same task republished under a new message ID",
+			deliveries: []delivery{
+				{schemas.DroneResult{DroneID: "d1", TaskID: "t1"}, "m1"},
+				{schemas.DroneResult{DroneID: "d1", TaskID: "t1"}, "m2"},
+			},
+			want: 1,
+		},
+		{
+			name: "one drone's separate tasks",
+			deliveries: []delivery{
+				{schemas.DroneResult{DroneID: "d1", TaskID: "t1"}, "m1"},
+				{schemas.DroneResult{DroneID: "d1", TaskID: "t2"}, "m2"},
+			},
+			want: 2,
+		},
+		{
+			name: "same task on different drones",
+			deliveries: []delivery{
+				{schemas.DroneResult{DroneID: "d1", TaskID: "t1"}, "m1"},
+				{schemas.DroneResult{DroneID: "d2", TaskID: "t1"}, "m2"},
+			},
+			want: 2,
+		},
+		{
+			name: "distinct messages without tasks",
+			deliveries: []delivery{
+				{schemas.DroneResult{DroneID: "d1"}, "m1"},
+				{schemas.DroneResult{DroneID: "d1"}, "m2"},
+			},
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewResearchQueue("", "s1", 10)
+			for _, d := range tt.deliveries {
+				if !q.accept(context.Background(), d.result, resultKey(d.result, d.messageID)) {
+					t.Fatalf("accept(%s) = false, want true", d.messageID)
+				}
+			}
+			if got := q.GetResultCount(); got != tt.want {
+				t.Errorf("GetResultCount() = %d, want %d", got, tt.want)
+			}
+			if got := len(q.ResultChannel()); got != tt.want {
+				t.Errorf("%d results buffered, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResearchQueueDeliverDeduplicatesByDrone(t *testing.T) {
+	q := NewResearchQueue("", "s1", 10)
+	q.Deliver(schemas.DroneResult{DroneID: "d1"})
+	q.Deliver(schemas.DroneResult{DroneID: "d1"})
+	q.Deliver(schemas.DroneResult{DroneID: "d2"})
+	if got := q.GetResultCount(); got != 2 {
+		t.Errorf("GetResultCount() = %d, want 2", got)
+	}
+}
+
+func TestResearchQueueRedeliversWhenFull(t *testing.T) {
+	q := NewResearchQueue("", "s1", 1)
+	first := schemas.DroneResult{DroneID: "d1", TaskID: "t1"}
+	second := schemas.DroneResult{DroneID: "d2", TaskID: "t1"}
+	if !q.accept(context.Background(), first, resultKey(first, "m1")) {
+		t.Fatal("accept(first) = false, want true")
+	}
+
+	// The buffer is full, so the second result waits until its context ends
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if q.accept(ctx, second, resultKey(second, "m2")) {
+		t.Fatal("accept() with a full buffer and ended context = true, want false so it is redelivered")
+	}
+
+	// The rejected result is not remembered, so its redelivery is taken
+	<-q.ResultChannel()
+	if !q.accept(context.Background(), second, resultKey(second, "m3")) {
+		t.Fatal("accept(redelivered) = false, want true")
+	}
+	if got := q.GetResultCount(); got != 2 {
+		t.Errorf("GetResultCount() = %d, want 2", got)
+	}
+}
+
+func TestResearchQueueClosed(t *testing.T) {
+	q := NewResearchQueue("", "s1", 1)
+	q.Close()
+	q.Close()
+
+	result := schemas.DroneResult{DroneID: "d1", TaskID: "t1"}
+	if !q.accept(context.Background(), result, resultKey(result, "m1")) {
+		t.Error("accept() on a closed queue = false, want true so the message is acknowledged")
+	}
+	if got := q.GetResultCount(); got != 0 {
+		t.Errorf("GetResultCount() = %d after close, want 0", got)
+	}
+	select {
+	case <-q.Done():
+	default:
+		t.Error("Done() is open after Close")
+	}
+}
@@ -0,0 +1,126 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// reduceSettings returns how many inputs each summary merges (REDUCE_BATCH_SIZE)
+// and how many summaries are written at once (REDUCE_PARALLELISM)
+func reduceSettings() (batchSize, parallelism int) {
+	batchSize, parallelism = 10, 4
+	if size, err := strconv.Atoi(getEnvOrDefault("REDUCE_BATCH_SIZE", "")); err == nil && size >= 2 {
+		batchSize = size
+	}
+	if n, err := strconv.Atoi(getEnvOrDefault("REDUCE_PARALLELISM", "")); err == nil && n > 0 {
+		parallelism = n
+	}
+	return batchSize, parallelism
+}
+
+// reduceResults merges the completed drone results of a large session into
+// intermediate summaries, batch by batch and level by level, until no more
+// than one batch is left for the final report. It returns nil when the
+// results already fit in one batch.
+func (o *Orchestrator) reduceResults(ctx context.Context, session *ResearchSession, results []schemas.DroneResult) ([]schemas.ResultSummary, error) {
+	batchSize, parallelism := reduceSettings()
+
+	var level []schemas.ResultSummary
+	for _, result := range results {
+		if result.Status == "completed" {
+			level = append(level, leafSummary(result))
+		}
+	}
+	if len(level) <= batchSize {
+		return nil, nil
+	}
+
+	for depth := 1; len(level) > batchSize; depth++ {
+		batches := (len(level) + batchSize - 1) / batchSize
+		log.Printf("Session %s: reducing %d inputs into %d level %d summaries", session.Config.SessionID, len(level), batches, depth)
+
+		next := make([]schemas.ResultSummary, batches)
+		errors := make(chan error, batches)
+		slots := make(chan struct{}, parallelism)
+		var wg sync.WaitGroup
+		for i := 0; i < batches; i++ {
+			batch := level[i*batchSize : min((i+1)*batchSize, len(level))]
+			wg.Add(1)
+			go func(index int, batch []schemas.ResultSummary) {
+				defer wg.Done()
+				slots <- struct{}{}
+				defer func() { <-slots }()
+
+				summary, err := o.claudeAgent.SummarizeBatch(ctx, session.Config.Topic, batch)
+				if err != nil {
+					errors <- fmt.Errorf("failed to summarize batch %d of level %d: %w", index+1, depth, err)
+					return
+				}
+				summary.ID = fmt.Sprintf("L%d-%d", depth, index+1)
+				summary.Level = depth
+
+				tokens := summaryTokens(*summary)
+				for _, item := range batch {
+					tokens += summaryTokens(item)
+				}
+				session.Budget.addLLMTokens(tokens)
+				next[index] = *summary
+			}(i, batch)
+		}
+		wg.Wait()
+		close(errors)
+		if err := <-errors; err != nil {
+			return nil, err
+		}
+
+		level = next
+		if o.enforceBudget(session) {
+			log.Printf("Warning: Session %s went over budget while reducing results, reporting from %d level %d summaries", session.Config.SessionID, len(level), depth)
+			break
+		}
+	}
+	return level, nil
+}
+
+// leafSummary reads a drone result as the input of the first reduce level
+func leafSummary(result schemas.DroneResult) schemas.ResultSummary {
+	summary := schemas.ResultSummary{ID: result.DroneID}
+	if result.TaskID != "" {
+		summary.ID += "/" + result.TaskID
+	}
+	summary.Summary, _ = result.Data["summary"].(string)
+	for _, finding := range resultFindings(result) {
+		if finding.Claim != "" {
+			summary.KeyFindings = append(summary.KeyFindings, finding.Claim)
+		}
+		for _, source := range finding.Sources {
+			if source = normalizeURL(source); !containsString(summary.Sources, source) {
+				summary.Sources = append(summary.Sources, source)
+			}
+		}
+	}
+	if sources, ok := result.Data["sources"].([]interface{}); ok {
+		for _, source := range sources {
+			if s, ok := source.(string); ok {
+				if s = normalizeURL(s); !containsString(summary.Sources, s) {
+					summary.Sources = append(summary.Sources, s)
+				}
+			}
+		}
+	}
+	return summary
+}
+
+// summaryTokens estimates the LLM tokens a summary takes up
+func summaryTokens(summary schemas.ResultSummary) int {
+	tokens := estimateTokens(summary.Summary)
+	for _, finding := range summary.KeyFindings {
+		tokens += estimateTokens(finding)
+	}
+	return tokens
+}
@@ -0,0 +1,121 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// regionPlanner spreads a session's drones across its regions and stops
+// using a region once it has run out of capacity
+type regionPlanner struct {
+	regions   []string
+	exhausted map[string]bool
+	mu        sync.Mutex
+}
+
+// newRegionPlanner creates a planner over regions in order of preference
+func newRegionPlanner(regions []string) *regionPlanner {
+	return &regionPlanner{
+		regions:   regions,
+		exhausted: make(map[string]bool),
+	}
+}
+
+// candidates returns the usable regions to try for the drone at index, starting
+// with its round-robin region and falling back to the others in order
+func (p *regionPlanner) candidates(index int) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var regions []string
+	for i := range p.regions {
+		region := p.regions[(index+i)%len(p.regions)]
+		if !p.exhausted[region] {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// markExhausted stops the planner from placing more drones in region
+func (p *regionPlanner) markExhausted(region string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.exhausted[region] = true
+}
+
+// sessionRegions returns the regions a session deploys to, defaulting to the orchestrator's region
+func (o *Orchestrator) sessionRegions(config *schemas.ResearchConfig) []string {
+	var regions []string
+	seen := make(map[string]bool)
+	for _, region := range config.Regions {
+		region = strings.TrimSpace(region)
+		if region != "" && !seen[region] {
+			seen[region] = true
+			regions = append(regions, region)
+		}
+	}
+	if len(regions) == 0 {
+		regions = []string{o.region}
+	}
+	return regions
+}
+
+// isCapacityError reports whether a Cloud Run error means the region is out of
+// quota or capacity, so provisioning should move on to another region. Other
+// errors, including transient unavailability, do not mark the region exhausted.
+func isCapacityError(err error) bool {
+	if status.Code(err) == codes.ResourceExhausted {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "quota") || strings.Contains(message, "capacity")
+}
+
+// deployDroneWithFailover deploys the drone at index in its preferred region,
+// failing over to the session's other regions on quota or capacity errors.
+// Returns the service URL and the region the drone was deployed to.
+func (o *Orchestrator) deployDroneWithFailover(ctx context.Context, planner *regionPlanner, index int, droneID string, config *schemas.ResearchConfig) (string, string, error) {
+	regions := planner.candidates(index)
+	if len(regions) == 0 {
+		return "", "", fmt.Errorf("no region with remaining capacity")
+	}
+
+	spawn, serviceURL, err := o.claimDroneSpawn(ctx, index, droneID, regions[0], config)
+	if err != nil {
+		return "", "", err
+	}
+	if serviceURL != "" {
+		return serviceURL, spawn.Region, nil
+	}
+
+	var lastErr error
+	for _, region := range regions {
+		o.moveDroneSpawn(ctx, spawn, region)
+		serviceURL, err := o.deployDrone(ctx, droneID, region, config)
+		if err == nil {
+			return serviceURL, region, nil
+		}
+		lastErr = err
+		if !isCapacityError(err) {
+			return "", region, err
+		}
+		log.Printf("Warning: region %s is out of capacity for drone %s, trying the next region: %v", region, droneID, err)
+		planner.markExhausted(region)
+
+		// A service whose rollout failed may still exist in the region
+		go func(region string) {
+			if err := o.deleteDroneService(context.WithoutCancel(ctx), droneID, region); err != nil && status.Code(err) != codes.NotFound {
+				log.Printf("Warning: failed to remove drone %s from region %s: %v", droneID, region, err)
+			}
+		}(region)
+	}
+	return "", "", fmt.Errorf("all regions out of capacity: %w", lastErr)
+}
@@ -0,0 +1,31 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsCapacityError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"resource exhausted", status.Error(codes.ResourceExhausted, "too many services"), true},
+		{"wrapped resource exhausted", fmt.Errorf("failed to create service: %w", status.Error(codes.ResourceExhausted, "limit")), true},
+		{"quota message", errors.New("Quota exceeded for quota metric 'Services'"), true},
+		{"capacity message", status.Error(codes.FailedPrecondition, "insufficient capacity in region"), true},
+		{"unavailable", status.Error(codes.Unavailable, "connection reset"), false},
+		{"zone in an unrelated message", status.Error(codes.InvalidArgument, "invalid time zone in annotation"), false},
+		{"permission denied", status.Error(codes.PermissionDenied, "caller lacks run.services.create"), false},
+	}
+	for _, tt := range tests {
+		if got := isCapacityError(tt.err); got != tt.want {
+			t.Errorf("%s: isCapacityError(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReplayOptions changes how a replayed session is reported
+type ReplayOptions struct {
+	// TemplateID reports the session as research with another template,
+	// filled in with TemplateParams
+	TemplateID     string
+	TemplateParams map[string]interface{}
+	// Model is the Claude model that writes the report instead of CLAUDE_MODEL
+	Model string
+}
+
+// ReplaySession analyzes a past session's stored drone results again and writes
+// a new report from them, without running any drones. The replay is reported as
+// a session of its own, so the original report is kept.
+func (o *Orchestrator) ReplaySession(ctx context.Context, sessionID string, options ReplayOptions) (*schemas.ResearchResult, error) {
+	if err := o.requireCloud(); err != nil {
+		return nil, err
+	}
+	if o.firestoreClient == nil {
+		return nil, fmt.Errorf("replay needs the results stored in Firestore")
+	}
+
+	config, err := o.loadSessionConfig(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	results, err := o.loadSessionResults(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("session %s has no stored results", sessionID)
+	}
+
+	if options.TemplateID != "" {
+		workflow, err := o.InstantiateTemplate(options.TemplateID, options.TemplateParams)
+		if err != nil {
+			return nil, fmt.Errorf("failed to instantiate template: %w", err)
+		}
+		config.TemplateID = options.TemplateID
+		config.TemplateParams = options.TemplateParams
+		config.Workflow = workflow
+	}
+	agent := o.claudeAgent
+	if options.Model != "" {
+		agent = agent.WithModel(options.Model)
+	}
+
+	config.SessionID = fmt.Sprintf("%s-replay-%d", sessionID, time.Now().Unix())
+	config.Tenant = o.tenantID(ctx)
+	session := &ResearchSession{
+		Config:    config,
+		Drones:    make(map[string]*DroneInfo),
+		StartTime: time.Now(),
+		Status:    "running",
+		Results:   results,
+		cancel:    func() {},
+	}
+	// A replay spends only the LLM tokens of its report
+	session.Budget = newSessionBudget(o.costRates, 0, 0, 0)
+
+	log.Printf("Replaying %d stored results of session %s as %s", len(results), sessionID, config.SessionID)
+	report, err := o.generateReport(ctx, session, agent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate report: %w", err)
+	}
+
+	o.mu.Lock()
+	o.reports[report.ID] = report
+	o.mu.Unlock()
+
+	return &schemas.ResearchResult{
+		SessionID:   config.SessionID,
+		Status:      "completed",
+		ReportURL:   fmt.Sprintf("reports/report_%s.md", config.SessionID),
+		ReportData:  report,
+		Metrics:     o.calculateMetrics(ctx, session),
+		ReplayOf:    sessionID,
+		CompletedAt: time.Now(),
+	}, nil
+}
+
+// loadSessionConfig reads the research config stored when a session started
+func (o *Orchestrator) loadSessionConfig(ctx context.Context, sessionID string) (*schemas.ResearchConfig, error) {
+	doc, err := o.collection(o.tenantID(ctx), sessionsCollection).Doc(sessionID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session %s: %w", sessionID, err)
+	}
+
+	var stored struct {
+		Config *schemas.ResearchConfig `firestore:"config"`
+	}
+	if err := doc.DataTo(&stored); err != nil {
+		return nil, fmt.Errorf("failed to decode session %s: %w", sessionID, err)
+	}
+	if stored.Config == nil {
+		return nil, fmt.Errorf("session %s has no stored config, it ran before configs were recorded", sessionID)
+	}
+	return stored.Config, nil
+}
+
+// loadSessionResults reads every drone result stored for a session
+func (o *Orchestrator) loadSessionResults(ctx context.Context, sessionID string) ([]schemas.DroneResult, error) {
+	tenantID := o.tenantID(ctx)
+	docs := o.collection(tenantID, sessionsCollection).Doc(sessionID).Collection(resultsCollection).Documents(ctx)
+	defer docs.Stop()
+
+	var results []schemas.DroneResult
+	for {
+		doc, err := docs.Next()
+		if errors.Is(err, iterator.Done) {
+			return results, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read results of session %s: %w", sessionID, err)
+		}
+		var result schemas.DroneResult
+		if err := doc.DataTo(&result); err != nil {
+			log.Printf("Warning: Skipping unreadable result %s of session %s: %v", doc.Ref.ID, sessionID, err)
+			continue
+		}
+		if err := o.openResult(ctx, &result); err != nil {
+			log.Printf("Warning: Skipping result %s of session %s: %v", doc.Ref.ID, sessionID, err)
+			continue
+		}
+		if err := o.payloads.Resolve(ctx, resultsTopicName(tenantID, sessionID), &result); err != nil {
+			log.Printf("Warning: Skipping result %s of session %s: %v", doc.Ref.ID, sessionID, err)
+			continue
+		}
+		results = append(results, result)
+	}
+}
@@ -0,0 +1,150 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// reportDiffsCollection holds the changes between consecutive runs of a schedule
+const reportDiffsCollection = "report_diffs"
+
+// loadReport reads a stored report, from memory if this process generated it
+func (o *Orchestrator) loadReport(ctx context.Context, reportID string) (*schemas.ResearchReport, error) {
+	o.mu.RLock()
+	report, ok := o.reports[reportID]
+	o.mu.RUnlock()
+	if ok {
+		return report, nil
+	}
+
+	doc, err := o.collection(o.tenantID(ctx), reportsCollection).Doc(reportID).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load report %s: %w", reportID, err)
+	}
+	report = &schemas.ResearchReport{}
+	if err := doc.DataTo(report); err != nil {
+		return nil, fmt.Errorf("failed to decode report %s: %w", reportID, err)
+	}
+	return report, nil
+}
+
+// diffReports compares two reports by section title, insight and source
+func diffReports(previous, current *schemas.ResearchReport) *schemas.ReportDiff {
+	diff := &schemas.ReportDiff{
+		PreviousReportID: previous.ID,
+		ReportID:         current.ID,
+		SummaryChanged:   strings.TrimSpace(previous.Executive) != strings.TrimSpace(current.Executive),
+		CreatedAt:        time.Now(),
+	}
+
+	previousSections := make(map[string]schemas.ReportSection, len(previous.Sections))
+	var previousInsights, currentInsights []string
+	for _, section := range previous.Sections {
+		previousSections[section.Title] = section
+		previousInsights = append(previousInsights, section.Insights...)
+	}
+
+	currentTitles := make(map[string]bool, len(current.Sections))
+	for _, section := range current.Sections {
+		currentTitles[section.Title] = true
+		currentInsights = append(currentInsights, section.Insights...)
+
+		old, existed := previousSections[section.Title]
+		switch {
+		case !existed:
+			diff.AddedSections = append(diff.AddedSections, section.Title)
+		case strings.TrimSpace(old.Content) != strings.TrimSpace(section.Content):
+			diff.ChangedSections = append(diff.ChangedSections, section.Title)
+		}
+	}
+	for _, section := range previous.Sections {
+		if !currentTitles[section.Title] {
+			diff.RemovedSections = append(diff.RemovedSections, section.Title)
+		}
+	}
+
+	diff.NewInsights, diff.DroppedInsights = setDifference(currentInsights, previousInsights)
+	diff.NewSources, diff.DroppedSources = setDifference(current.Metadata.Sources, previous.Metadata.Sources)
+	return diff
+}
+
+// setDifference returns the values only in current and the values only in previous
+func setDifference(current, previous []string) (added, removed []string) {
+	inPrevious := make(map[string]bool, len(previous))
+	for _, value := range previous {
+		inPrevious[value] = true
+	}
+	inCurrent := make(map[string]bool, len(current))
+	for _, value := range current {
+		if !inCurrent[value] && !inPrevious[value] {
+			added = append(added, value)
+		}
+		inCurrent[value] = true
+	}
+	for _, value := range previous {
+		if !inCurrent[value] {
+			removed = append(removed, value)
+			inCurrent[value] = true
+		}
+	}
+	return added, removed
+}
+
+// renderDiffMarkdown renders a diff as a Markdown summary of what changed
+func renderDiffMarkdown(topic string, diff *schemas.ReportDiff) string {
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("# What Changed: %s\n\n", topic))
+	content.WriteString(fmt.Sprintf("Compared report `%s` with the previous run's report `%s`.\n\n", diff.ReportID, diff.PreviousReportID))
+
+	if diff.SummaryChanged {
+		content.WriteString("The executive summary changed.\n\n")
+	}
+
+	lists := []struct {
+		title string
+		items []string
+	}{
+		{"New Sections", diff.AddedSections},
+		{"Changed Sections", diff.ChangedSections},
+		{"Removed Sections", diff.RemovedSections},
+		{"New Insights", diff.NewInsights},
+		{"Dropped Insights", diff.DroppedInsights},
+		{"New Sources", diff.NewSources},
+		{"Dropped Sources", diff.DroppedSources},
+	}
+	changed := diff.SummaryChanged
+	for _, list := range lists {
+		if len(list.items) == 0 {
+			continue
+		}
+		changed = true
+		content.WriteString(fmt.Sprintf("## %s\n\n", list.title))
+		for _, item := range list.items {
+			content.WriteString(fmt.Sprintf("- %s\n", item))
+		}
+		content.WriteString("\n")
+	}
+	if !changed {
+		content.WriteString("Nothing changed since the previous run.\n")
+	}
+
+	return content.String()
+}
+
+// storeReportDiff saves a diff to Firestore and as a Markdown file next to the report
+func (o *Orchestrator) storeReportDiff(ctx context.Context, sessionID, topic string, diff *schemas.ReportDiff) error {
+	diffFilePath := fmt.Sprintf("reports/diff_%s.md", sessionID)
+	if err := os.WriteFile(diffFilePath, []byte(renderDiffMarkdown(topic, diff)), 0644); err != nil {
+		return fmt.Errorf("failed to save diff: %w", err)
+	}
+
+	if _, err := o.collection(o.tenantID(ctx), reportDiffsCollection).Doc(diff.ReportID).Set(ctx, diff); err != nil {
+		return fmt.Errorf("failed to store diff: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package orchestrator
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// ResearchMemoryConfig sets how the findings of earlier sessions seed the
+// sub-queries of a new one
+type ResearchMemoryConfig struct {
+	// Findings is the most prior findings given to sub-query generation; 0 disables research memory
+	Findings int
+	// MinScore is the least similarity to the subject a prior finding needs to count as overlapping
+	MinScore float64
+}
+
+// LoadResearchMemoryConfig reads RESEARCH_MEMORY_FINDINGS and RESEARCH_MEMORY_MIN_SCORE
+func LoadResearchMemoryConfig() ResearchMemoryConfig {
+	config := ResearchMemoryConfig{Findings: 8, MinScore: envFloat("RESEARCH_MEMORY_MIN_SCORE", 0.75)}
+	if findings, err := strconv.Atoi(getEnvOrDefault("RESEARCH_MEMORY_FINDINGS", "")); err == nil && findings >= 0 {
+		config.Findings = findings
+	}
+	return config
+}
+
+// priorFindings returns the findings of the tenant's earlier sessions that
+// overlap subject, most relevant first, so sub-queries can target gaps and
+// updates instead of what is already known. It returns nil when research
+// memory is off for the session or nothing overlaps.
+func (o *Orchestrator) priorFindings(ctx context.Context, config *schemas.ResearchConfig, subject string) []string {
+	if config.NoMemory || o.memory.Findings <= 0 || o.vectors == nil || o.embedder == nil {
+		return nil
+	}
+	vectors, err := o.embedder.Embed(ctx, []string{subject})
+	if err != nil {
+		log.Printf("Warning: Failed to embed %q to recall prior findings: %v", subject, err)
+		return nil
+	}
+	normalizeVector(vectors[0])
+	matches, err := o.vectors.Search(ctx, config.Tenant, vectors[0], []string{findingKind, websetItemKind}, o.memory.Findings)
+	if err != nil {
+		log.Printf("Warning: Failed to recall prior findings for %q: %v", subject, err)
+		return nil
+	}
+
+	var findings []string
+	for _, match := range matches {
+		if match.Score < o.memory.MinScore || match.Record.SessionID == config.SessionID {
+			continue
+		}
+		findings = append(findings, match.Record.Text)
+	}
+	if len(findings) > 0 {
+		log.Printf("Seeding sub-queries for %q with %d prior findings", subject, len(findings))
+	}
+	return findings
+}
@@ -0,0 +1,188 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+)
+
+// quarantinedResultsCollection holds a session's drone results that failed validation
+const quarantinedResultsCollection = "quarantined_results"
+
+// ResultPipelineConfig bounds how many drone results are held in memory on
+// their way to Firestore
+type ResultPipelineConfig struct {
+	// BufferSize is how many received results may wait for the collector; once
+	// it is full, Pub/Sub holds back further results
+	BufferSize int
+	// FlushSize is how many collected results are written to Firestore at once
+	FlushSize int
+	// FlushInterval is the longest a collected result waits to be written
+	FlushInterval time.Duration
+}
+
+// LoadResultPipelineConfig reads the result pipeline configuration from the environment
+func LoadResultPipelineConfig() ResultPipelineConfig {
+	config := ResultPipelineConfig{
+		BufferSize:    100,
+		FlushSize:     25,
+		FlushInterval: 2 * time.Second,
+	}
+	if n, err := strconv.Atoi(getEnvOrDefault("RESULT_BUFFER_SIZE", "")); err == nil && n > 0 {
+		config.BufferSize = n
+	}
+	if n, err := strconv.Atoi(getEnvOrDefault("RESULT_FLUSH_SIZE", "")); err == nil && n > 0 {
+		config.FlushSize = n
+	}
+	if interval, err := time.ParseDuration(getEnvOrDefault("RESULT_FLUSH_INTERVAL", "")); err == nil && interval > 0 {
+		config.FlushInterval = interval
+	}
+	return config
+}
+
+// flushResults writes a batch of collected results to Firestore and the
+// progress file, and updates the session's stored progress
+func (o *Orchestrator) flushResults(session *ResearchSession, results []schemas.DroneResult) {
+	if err := o.updateProgressFile(session); err != nil {
+		log.Printf("Warning: failed to update progress file for session %s: %v", session.Config.SessionID, err)
+	}
+	o.recordSessionResults(session, results)
+	o.recordSessionProgress(session, results)
+}
+
+// recordSessionResults stores drone results under their session in one bulk
+// write. Results with a task ID replace earlier copies, so redelivered results
+// are stored once.
+func (o *Orchestrator) recordSessionResults(session *ResearchSession, results []schemas.DroneResult) {
+	if o.firestoreClient == nil || len(results) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	collection := o.collection(session.Config.Tenant, sessionsCollection).Doc(session.Config.SessionID).Collection(resultsCollection)
+	writer := o.firestoreClient.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(results))
+	for _, result := range results {
+		doc := collection.NewDoc()
+		if result.TaskID != "" {
+			doc = collection.Doc(result.DroneID + "-" + result.TaskID)
+		}
+		// Simulated drones and older drone images do not echo the correlation ID
+		if result.CorrelationID == "" {
+			result.CorrelationID = session.Config.CorrelationID
+		}
+		// Offloaded data stays in Cloud Storage; documents are limited to 1MB
+		if result.DataRef != "" {
+			result.Data = nil
+		}
+		if err := o.sealResult(ctx, &result); err != nil {
+			log.Printf("Warning: Not storing result from drone %s for session %s: %v", result.DroneID, session.Config.SessionID, err)
+			continue
+		}
+		job, err := writer.Set(doc, result)
+		if err != nil {
+			log.Printf("Warning: Failed to store result from drone %s for session %s: %v", result.DroneID, session.Config.SessionID, err)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	writer.End()
+
+	failed := 0
+	var lastErr error
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			failed++
+			lastErr = err
+		}
+	}
+	if failed > 0 {
+		log.Printf("Warning: Failed to store %d of %d results for session %s: %v", failed, len(results), session.Config.SessionID, lastErr)
+	}
+}
+
+// checkResult validates a collected result. An invalid result is quarantined
+// and replaced by a failure of the same drone and task, so the session stops
+// waiting for the task without analyzing the malformed data. ok is false when
+// the result cannot be attributed to a drone and is dropped.
+func (o *Orchestrator) checkResult(session *ResearchSession, result schemas.DroneResult) (schemas.DroneResult, bool) {
+	err := result.Validate()
+	if err == nil {
+		return result, true
+	}
+	mcpErr := mcperrors.Wrap(err)
+	log.Printf("Quarantining result for session %s [%s %s]: %v", session.Config.SessionID, mcpErr.Code, session.Config.CorrelationID, mcpErr)
+	o.quarantineResult(session, result, mcpErr)
+	if result.DroneID == "" {
+		return result, false
+	}
+	return schemas.DroneResult{
+		DroneID:       result.DroneID,
+		TaskID:        result.TaskID,
+		CorrelationID: result.CorrelationID,
+		Status:        schemas.ResultStatusFailed,
+		Error:         fmt.Sprintf("%s: %s", mcpErr.Code, mcpErr.Message),
+		CompletedAt:   time.Now(),
+	}, true
+}
+
+// quarantineResult stores an invalid result under its session, apart from the
+// results analysis reads, so it can be inspected
+func (o *Orchestrator) quarantineResult(session *ResearchSession, result schemas.DroneResult, mcpErr *mcperrors.MCPError) {
+	if o.firestoreClient == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := o.sealResult(ctx, &result); err != nil {
+		log.Printf("Warning: Not quarantining result from drone %s for session %s: %v", result.DroneID, session.Config.SessionID, err)
+		return
+	}
+	collection := o.collection(session.Config.Tenant, sessionsCollection).Doc(session.Config.SessionID).Collection(quarantinedResultsCollection)
+	_, _, err := collection.Add(ctx, map[string]interface{}{
+		"result":         result,
+		"code":           mcpErr.Code,
+		"error":          mcpErr.Message,
+		"details":        mcpErr.Details,
+		"quarantined_at": time.Now(),
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to quarantine result from drone %s for session %s: %v", result.DroneID, session.Config.SessionID, err)
+	}
+}
+
+// sealResult replaces a result's data with its encryption under FIELD_KMS_KEY,
+// when one is configured. Data that cannot be encrypted must not be stored.
+func (o *Orchestrator) sealResult(ctx context.Context, result *schemas.DroneResult) error {
+	if !o.fields.Enabled() || result.Data == nil {
+		return nil
+	}
+	sealed, err := o.fields.Seal(ctx, result.Data)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt result data: %w", err)
+	}
+	result.SealedData = sealed
+	result.Data = nil
+	return nil
+}
+
+// openResult decrypts the data of a stored result that was sealed
+func (o *Orchestrator) openResult(ctx context.Context, result *schemas.DroneResult) error {
+	if result.SealedData == nil {
+		return nil
+	}
+	if err := o.fields.Open(ctx, result.SealedData, &result.Data); err != nil {
+		return fmt.Errorf("failed to decrypt result data: %w", err)
+	}
+	result.SealedData = nil
+	return nil
+}
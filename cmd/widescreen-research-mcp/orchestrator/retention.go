@@ -0,0 +1,439 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+	"github.com/spawn-mcp/coordinator/pkg/payload"
+	logging "google.golang.org/api/logging/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxLogRetentionDays is the longest Cloud Logging keeps entries in a bucket
+const maxLogRetentionDays = 3650
+
+// sessionIDPattern matches the session IDs that can be purged: bare names such
+// as generated UUIDs and replay IDs, never paths
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// RetentionConfig sets how long research data is kept. A window of 0 keeps
+// that data until it is purged with purge-session-data.
+type RetentionConfig struct {
+	// Interval is how often expired data is purged
+	Interval time.Duration
+	// Reports is how long reports and their diffs are kept after they are written
+	Reports time.Duration
+	// Results is how long a session's drone results, offloaded result data and
+	// session document are kept after the session was last updated
+	Results time.Duration
+	// Logs is how long LogBucket keeps drone logs, rounded up to whole days
+	Logs time.Duration
+	// LogBucket is the Cloud Logging bucket drone logs are stored in; empty
+	// leaves log retention to the project's settings
+	LogBucket string
+}
+
+// DefaultRetentionConfig returns the retention used when the environment does not override it
+func DefaultRetentionConfig() RetentionConfig {
+	return RetentionConfig{
+		Interval: time.Hour,
+		Reports:  90 * 24 * time.Hour,
+		Results:  30 * 24 * time.Hour,
+		Logs:     30 * 24 * time.Hour,
+	}
+}
+
+// LoadRetentionConfig returns the default retention overridden from the
+// environment: RETENTION_INTERVAL, RETENTION_REPORTS, RETENTION_RESULTS and
+// RETENTION_LOGS are durations such as 720h, and RETENTION_LOG_BUCKET names
+// the log bucket, as projects/P/locations/L/buckets/B.
+func LoadRetentionConfig() RetentionConfig {
+	config := DefaultRetentionConfig()
+	windows := []struct {
+		key    string
+		window *time.Duration
+	}{
+		{"RETENTION_REPORTS", &config.Reports},
+		{"RETENTION_RESULTS", &config.Results},
+		{"RETENTION_LOGS", &config.Logs},
+	}
+	for _, w := range windows {
+		v := os.Getenv(w.key)
+		if v == "" {
+			continue
+		}
+		window, err := time.ParseDuration(v)
+		if err != nil || window < 0 {
+			log.Printf("Warning: Ignoring invalid %s %q", w.key, v)
+			continue
+		}
+		*w.window = window
+	}
+
+	if v := os.Getenv("RETENTION_INTERVAL"); v != "" {
+		if interval, err := time.ParseDuration(v); err == nil && interval > 0 {
+			config.Interval = interval
+		} else {
+			log.Printf("Warning: Ignoring invalid RETENTION_INTERVAL %q", v)
+		}
+	}
+	config.LogBucket = os.Getenv("RETENTION_LOG_BUCKET")
+	return config
+}
+
+// logRetentionDays returns the days LogBucket keeps logs, or 0 when log retention is left alone
+func (c RetentionConfig) logRetentionDays() int64 {
+	if c.LogBucket == "" || c.Logs <= 0 {
+		return 0
+	}
+	days := int64((c.Logs + 24*time.Hour - 1) / (24 * time.Hour))
+	return min(days, maxLogRetentionDays)
+}
+
+// applyLogRetention sets how long the configured log bucket keeps drone logs
+func (o *Orchestrator) applyLogRetention(ctx context.Context) error {
+	days := o.retention.logRetentionDays()
+	if days == 0 {
+		return nil
+	}
+	_, err := o.loggingService.Projects.Locations.Buckets.Patch(o.retention.LogBucket, &logging.LogBucket{
+		RetentionDays: days,
+	}).UpdateMask("retentionDays").Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to set retention of log bucket %s: %w", o.retention.LogBucket, err)
+	}
+	log.Printf("Log bucket %s keeps drone logs for %d days", o.retention.LogBucket, days)
+	return nil
+}
+
+// startRetention purges expired data every retention interval until stopRetention is called
+func (o *Orchestrator) startRetention() {
+	if o.retention.Reports <= 0 && o.retention.Results <= 0 {
+		return
+	}
+	o.retentionStop = make(chan struct{})
+	o.retentionDone = make(chan struct{})
+
+	go func() {
+		defer close(o.retentionDone)
+
+		ticker := time.NewTicker(o.retention.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-o.retentionStop:
+				return
+			case <-ticker.C:
+				o.purgeExpired(context.Background())
+			}
+		}
+	}()
+}
+
+// stopRetention stops purging expired data, waiting for a purge in progress
+func (o *Orchestrator) stopRetention() {
+	if o.retentionStop == nil {
+		return
+	}
+	close(o.retentionStop)
+	<-o.retentionDone
+	o.retentionStop = nil
+}
+
+// purgeExpired deletes every tenant's reports and session data older than their retention windows
+func (o *Orchestrator) purgeExpired(ctx context.Context) {
+	now := time.Now()
+	for _, tenantID := range o.tenants.IDs() {
+		if o.retention.Results > 0 {
+			o.purgeExpiredSessions(ctx, tenantID, now.Add(-o.retention.Results))
+		}
+		if o.retention.Reports > 0 {
+			o.purgeExpiredReports(ctx, tenantID, now.Add(-o.retention.Reports))
+		}
+	}
+}
+
+// purgeExpiredSessions deletes the results of a tenant's sessions last updated before cutoff
+func (o *Orchestrator) purgeExpiredSessions(ctx context.Context, tenantID string, cutoff time.Time) {
+	docs, err := o.collection(tenantID, sessionsCollection).Where("updated_at", "<", cutoff).Documents(ctx).GetAll()
+	if err != nil {
+		log.Printf("Warning: Failed to query expired sessions of tenant %q: %v", tenantID, err)
+		return
+	}
+	for _, doc := range docs {
+		if o.sessionActive(tenantID, doc.Ref.ID) {
+			continue
+		}
+		manifest := newPurgeManifest(doc.Ref.ID)
+		o.purgeSessionResults(ctx, tenantID, doc.Ref.ID, manifest)
+		logPurge("Retention purged expired session", manifest)
+	}
+}
+
+// purgeExpiredReports deletes a tenant's reports written before cutoff, with their diffs
+func (o *Orchestrator) purgeExpiredReports(ctx context.Context, tenantID string, cutoff time.Time) {
+	docs, err := o.collection(tenantID, reportsCollection).Where("CreatedAt", "<", cutoff).Documents(ctx).GetAll()
+	if err != nil {
+		log.Printf("Warning: Failed to query expired reports of tenant %q: %v", tenantID, err)
+		return
+	}
+	for _, doc := range docs {
+		sessionID, _ := doc.Data()["SessionID"].(string)
+		manifest := newPurgeManifest(sessionID)
+		o.purgeReport(ctx, tenantID, doc.Ref, sessionID, manifest)
+		logPurge("Retention purged expired report of session", manifest)
+	}
+}
+
+// PurgeSessionData deletes everything stored for a session of the call's
+// tenant: its Firestore documents, offloaded result data, Pub/Sub topics and
+// subscriptions, and local report files. It returns what was deleted; artifacts
+// that failed to delete are listed as failures rather than failing the purge.
+func (o *Orchestrator) PurgeSessionData(ctx context.Context, sessionID string) (*schemas.PurgeManifest, error) {
+	if err := o.requireCloud(); err != nil {
+		return nil, err
+	}
+	if o.firestoreClient == nil {
+		return nil, fmt.Errorf("purge needs the session stored in Firestore")
+	}
+	if sessionID == "" {
+		return nil, mcperrors.InvalidInput("session_id is required")
+	}
+	// The ID names local files and directories, so it must not be a path
+	if !sessionIDPattern.MatchString(sessionID) || strings.Contains(sessionID, "..") {
+		return nil, mcperrors.InvalidInput("invalid session_id %q", sessionID)
+	}
+	tenantID := o.tenantID(ctx)
+	if o.sessionActive(tenantID, sessionID) {
+		return nil, mcperrors.New(mcperrors.CategoryConflict, "session %s is still running; cancel it before purging its data", sessionID)
+	}
+
+	// Only a session the tenant stored may be purged, so one tenant cannot
+	// delete another's topics or local files
+	reports, err := o.collection(tenantID, reportsCollection).Where("SessionID", "==", sessionID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reports of session %s: %w", sessionID, err)
+	}
+	if len(reports) == 0 {
+		_, err := o.collection(tenantID, sessionsCollection).Doc(sessionID).Get(ctx)
+		if status.Code(err) == codes.NotFound {
+			return nil, mcperrors.New(mcperrors.CategoryNotFound, "nothing is stored for session %s", sessionID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get session %s: %w", sessionID, err)
+		}
+	}
+
+	manifest := newPurgeManifest(sessionID)
+	for _, doc := range reports {
+		o.purgeReport(ctx, tenantID, doc.Ref, sessionID, manifest)
+	}
+	o.purgeSessionResults(ctx, tenantID, sessionID, manifest)
+
+	if purgedNothing(manifest) {
+		return nil, mcperrors.New(mcperrors.CategoryNotFound, "nothing is stored for session %s", sessionID)
+	}
+	if days := o.retention.logRetentionDays(); days > 0 {
+		manifest.Retained = append(manifest.Retained, fmt.Sprintf("drone logs in %s, which expire after %d days", o.retention.LogBucket, days))
+	} else {
+		manifest.Retained = append(manifest.Retained, "drone logs in Cloud Logging, which expire with the project's log retention")
+	}
+	logPurge("Purged session", manifest)
+	return manifest, nil
+}
+
+// purgeSessionResults deletes what a session stored besides its reports
+func (o *Orchestrator) purgeSessionResults(ctx context.Context, tenantID, sessionID string, manifest *schemas.PurgeManifest) {
+	sessionRef := o.collection(tenantID, sessionsCollection).Doc(sessionID)
+	for _, name := range []string{resultsCollection, quarantinedResultsCollection} {
+		refs, err := sessionRef.Collection(name).DocumentRefs(ctx).GetAll()
+		if err != nil {
+			manifest.Failures = append(manifest.Failures, fmt.Sprintf("%s/%s: %v", documentPath(sessionRef), name, err))
+			continue
+		}
+		o.deleteDocuments(ctx, refs, manifest)
+	}
+	o.deleteDocuments(ctx, []*firestore.DocumentRef{
+		sessionRef,
+		o.collection(tenantID, knowledgeGraphsCollection).Doc(sessionID),
+	}, manifest)
+	o.mu.Lock()
+	delete(o.graphs, sessionID)
+	o.mu.Unlock()
+
+	// Offloaded results and knowledge graphs are stored under the session's results prefix
+	if o.payloads != nil {
+		objects, err := o.payloads.DeleteObjects(ctx, payload.ObjectPrefix(resultsTopicName(tenantID, sessionID)))
+		manifest.Objects = append(manifest.Objects, objects...)
+		if err != nil {
+			manifest.Failures = append(manifest.Failures, err.Error())
+		}
+	}
+
+	for _, topicName := range []string{resultsTopicName(tenantID, sessionID), metricsTopicName(tenantID, sessionID)} {
+		err := o.pubsubClient.Topic(topicName).Delete(ctx)
+		if err == nil {
+			manifest.Topics = append(manifest.Topics, topicName)
+		} else if status.Code(err) != codes.NotFound {
+			manifest.Failures = append(manifest.Failures, fmt.Sprintf("topic %s: %v", topicName, err))
+		}
+	}
+	for _, name := range []string{"research-results-sub-%s", "research-metrics-sub-%s"} {
+		subscriptionName := tenantResourceName(tenantID, fmt.Sprintf(name, sessionID))
+		err := o.pubsubClient.Subscription(subscriptionName).Delete(ctx)
+		if err == nil {
+			manifest.Subscriptions = append(manifest.Subscriptions, subscriptionName)
+		} else if status.Code(err) != codes.NotFound {
+			manifest.Failures = append(manifest.Failures, fmt.Sprintf("subscription %s: %v", subscriptionName, err))
+		}
+	}
+
+	if o.vectors != nil {
+		ids, err := o.vectors.DeleteSession(ctx, tenantID, sessionID, []string{findingKind, websetItemKind})
+		manifest.Embeddings = append(manifest.Embeddings, ids...)
+		if err != nil {
+			manifest.Failures = append(manifest.Failures, fmt.Sprintf("embeddings: %v", err))
+		}
+	}
+
+	removeFiles(manifest, fmt.Sprintf("reports/results_%s", sessionID), fmt.Sprintf("reports/progress_%s.md", sessionID))
+}
+
+// purgeReport deletes a report, its diff against the previous scheduled run,
+// its indexed sections and the session's local report files
+func (o *Orchestrator) purgeReport(ctx context.Context, tenantID string, ref *firestore.DocumentRef, sessionID string, manifest *schemas.PurgeManifest) {
+	o.deleteDocuments(ctx, []*firestore.DocumentRef{
+		ref,
+		o.collection(tenantID, reportDiffsCollection).Doc(ref.ID),
+	}, manifest)
+
+	o.mu.Lock()
+	delete(o.reports, ref.ID)
+	o.mu.Unlock()
+
+	if sessionID != "" {
+		if o.vectors != nil {
+			ids, err := o.vectors.DeleteSession(ctx, tenantID, sessionID, []string{reportSectionKind})
+			manifest.Embeddings = append(manifest.Embeddings, ids...)
+			if err != nil {
+				manifest.Failures = append(manifest.Failures, fmt.Sprintf("report embeddings: %v", err))
+			}
+		}
+		removeFiles(manifest, fmt.Sprintf("reports/report_%s.md", sessionID), fmt.Sprintf("reports/diff_%s.md", sessionID))
+	}
+}
+
+// deleteDocuments deletes the documents of refs that exist, listing them in the manifest
+func (o *Orchestrator) deleteDocuments(ctx context.Context, refs []*firestore.DocumentRef, manifest *schemas.PurgeManifest) {
+	if len(refs) == 0 {
+		return
+	}
+	// Only documents that exist are listed, so a repeated purge reports nothing
+	docs, err := o.firestoreClient.GetAll(ctx, refs)
+	if err != nil {
+		for _, ref := range refs {
+			manifest.Failures = append(manifest.Failures, fmt.Sprintf("%s: %v", documentPath(ref), err))
+		}
+		return
+	}
+
+	writer := o.firestoreClient.BulkWriter(ctx)
+	jobs := make(map[string]*firestore.BulkWriterJob)
+	for _, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+		job, err := writer.Delete(doc.Ref)
+		if err != nil {
+			manifest.Failures = append(manifest.Failures, fmt.Sprintf("%s: %v", documentPath(doc.Ref), err))
+			continue
+		}
+		jobs[documentPath(doc.Ref)] = job
+	}
+	writer.End()
+
+	for _, doc := range docs {
+		path := documentPath(doc.Ref)
+		job, ok := jobs[path]
+		if !ok {
+			continue
+		}
+		if _, err := job.Results(); err != nil {
+			manifest.Failures = append(manifest.Failures, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		manifest.Documents = append(manifest.Documents, path)
+	}
+}
+
+// sessionActive reports whether a tenant's session is running in this orchestrator
+func (o *Orchestrator) sessionActive(tenantID, sessionID string) bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	session, ok := o.activeSessions[sessionID]
+	return ok && session.Config.Tenant == tenantID
+}
+
+// newPurgeManifest returns an empty manifest for a session
+func newPurgeManifest(sessionID string) *schemas.PurgeManifest {
+	return &schemas.PurgeManifest{
+		SessionID:     sessionID,
+		PurgedAt:      time.Now(),
+		Documents:     []string{},
+		Objects:       []string{},
+		Topics:        []string{},
+		Subscriptions: []string{},
+		Files:         []string{},
+		Embeddings:    []string{},
+	}
+}
+
+// purgedNothing reports whether a purge found nothing to delete
+func purgedNothing(m *schemas.PurgeManifest) bool {
+	return len(m.Documents)+len(m.Objects)+len(m.Topics)+len(m.Subscriptions)+len(m.Files)+len(m.Embeddings)+len(m.Failures) == 0
+}
+
+// removeFiles deletes local report files and directories that exist, listing them in the manifest
+func removeFiles(manifest *schemas.PurgeManifest, paths ...string) {
+	for _, path := range paths {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			manifest.Failures = append(manifest.Failures, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		manifest.Files = append(manifest.Files, path)
+	}
+}
+
+// documentPath returns a document's path within the database
+func documentPath(ref *firestore.DocumentRef) string {
+	if _, path, ok := strings.Cut(ref.Path, "/documents/"); ok {
+		return path
+	}
+	return ref.Path
+}
+
+// logPurge logs what a purge deleted, if anything
+func logPurge(prefix string, manifest *schemas.PurgeManifest) {
+	if purgedNothing(manifest) {
+		return
+	}
+	log.Printf("%s %s: %d documents, %d objects, %d topics, %d subscriptions, %d files, %d embeddings, %d failures", prefix, manifest.SessionID,
+		len(manifest.Documents), len(manifest.Objects), len(manifest.Topics), len(manifest.Subscriptions), len(manifest.Files), len(manifest.Embeddings), len(manifest.Failures))
+	for _, failure := range manifest.Failures {
+		log.Printf("Warning: Failed to purge %s", failure)
+	}
+}
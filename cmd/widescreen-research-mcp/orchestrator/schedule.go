@@ -0,0 +1,403 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// schedulesCollection holds the saved research schedules
+	schedulesCollection = "research_schedules"
+
+	// scheduleTriggersCollection records external triggers, so a retried trigger starts one run
+	scheduleTriggersCollection = "schedule_triggers"
+
+	// schedulerInterval is how often due schedules are looked for; cron has minute resolution
+	schedulerInterval = time.Minute
+)
+
+// errScheduleNotDue is returned when another orchestrator already claimed a schedule's run
+var errScheduleNotDue = errors.New("schedule is not due")
+
+var (
+	// ErrScheduleNotFound is returned when no schedule has the given ID or name
+	ErrScheduleNotFound = errors.New("schedule not found")
+
+	// ErrScheduleAmbiguous is returned when several schedules have the given name
+	ErrScheduleAmbiguous = errors.New("more than one schedule has this name, use its ID")
+)
+
+// scheduleTrigger is the record of one external trigger of a schedule
+type scheduleTrigger struct {
+	ScheduleID string
+	Key        string
+	SessionID  string
+	CreatedAt  time.Time
+}
+
+// scheduleCron parses a schedule's cron expression in its time zone
+func scheduleCron(schedule *schemas.ResearchSchedule) (*cronSchedule, error) {
+	location := time.UTC
+	if schedule.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(schedule.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", schedule.Timezone, err)
+		}
+	}
+	return parseCron(schedule.Cron, location)
+}
+
+// CreateSchedule validates and stores a new enabled schedule, filling in its ID and first run
+func (o *Orchestrator) CreateSchedule(ctx context.Context, schedule *schemas.ResearchSchedule) error {
+	if err := o.requireCloud(); err != nil {
+		return err
+	}
+	if schedule.Config.Topic == "" {
+		return mcperrors.InvalidInput("schedule config must have a topic")
+	}
+	cron, err := scheduleCron(schedule)
+	if err != nil {
+		return mcperrors.InvalidInput("invalid schedule: %w", err)
+	}
+
+	now := time.Now()
+	schedule.NextRun = cron.Next(now)
+	if schedule.NextRun.IsZero() {
+		return mcperrors.InvalidInput("cron expression %q never runs", schedule.Cron)
+	}
+	schedule.ID = uuid.New().String()
+	schedule.Config.Tenant = o.tenantID(ctx)
+	schedule.Enabled = true
+	schedule.CreatedAt = now
+	if schedule.Name == "" {
+		schedule.Name = schedule.Config.Topic
+	}
+
+	if _, err := o.collection(o.tenantID(ctx), schedulesCollection).Doc(schedule.ID).Set(ctx, schedule); err != nil {
+		return fmt.Errorf("failed to store schedule: %w", err)
+	}
+	log.Printf("Created schedule %s (%s), next run at %s", schedule.ID, schedule.Cron, schedule.NextRun.Format(time.RFC3339))
+	return nil
+}
+
+// ListSchedules returns all saved schedules
+func (o *Orchestrator) ListSchedules(ctx context.Context) ([]*schemas.ResearchSchedule, error) {
+	if err := o.requireCloud(); err != nil {
+		return nil, err
+	}
+	docs, err := o.collection(o.tenantID(ctx), schedulesCollection).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	schedules := make([]*schemas.ResearchSchedule, 0, len(docs))
+	for _, doc := range docs {
+		var schedule schemas.ResearchSchedule
+		if err := doc.DataTo(&schedule); err != nil {
+			log.Printf("Warning: Skipping unreadable schedule %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		schedules = append(schedules, &schedule)
+	}
+	return schedules, nil
+}
+
+// DeleteSchedule removes a schedule; runs already in progress are not stopped
+func (o *Orchestrator) DeleteSchedule(ctx context.Context, scheduleID string) error {
+	if err := o.requireCloud(); err != nil {
+		return err
+	}
+	if _, err := o.collection(o.tenantID(ctx), schedulesCollection).Doc(scheduleID).Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete schedule %s: %w", scheduleID, err)
+	}
+	return nil
+}
+
+// getSchedule loads a saved schedule
+func (o *Orchestrator) getSchedule(ctx context.Context, scheduleID string) (*schemas.ResearchSchedule, error) {
+	if err := o.requireCloud(); err != nil {
+		return nil, err
+	}
+	doc, err := o.collection(o.tenantID(ctx), schedulesCollection).Doc(scheduleID).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schedule %s: %w", scheduleID, err)
+	}
+	var schedule schemas.ResearchSchedule
+	if err := doc.DataTo(&schedule); err != nil {
+		return nil, fmt.Errorf("failed to decode schedule %s: %w", scheduleID, err)
+	}
+	// Schedules run in the tenant whose collection they are stored in
+	schedule.Config.Tenant = o.tenantID(ctx)
+	return &schedule, nil
+}
+
+// RunSchedule runs a schedule's research now, outside its cadence, and waits for the result
+func (o *Orchestrator) RunSchedule(ctx context.Context, scheduleID string) (*schemas.ResearchResult, error) {
+	schedule, err := o.getSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	return o.runSchedule(ctx, schedule, scheduledSessionID(schedule, time.Now()))
+}
+
+// TriggerSchedule starts a run of the schedule with the given ID or name in
+// the background, for external triggers such as Cloud Scheduler. Triggers with
+// the same key start a single run: a repeated trigger returns the session the
+// first one started, with duplicate set.
+func (o *Orchestrator) TriggerSchedule(ctx context.Context, scheduleRef, key string) (sessionID string, duplicate bool, err error) {
+	if o.isDraining() {
+		return "", false, ErrDraining
+	}
+	schedule, err := o.findSchedule(ctx, scheduleRef)
+	if err != nil {
+		return "", false, err
+	}
+
+	now := time.Now()
+	trigger := scheduleTrigger{
+		ScheduleID: schedule.ID,
+		Key:        key,
+		SessionID:  scheduledSessionID(schedule, now),
+		CreatedAt:  now,
+	}
+	// Firestore document IDs cannot contain slashes
+	ref := o.collection(o.tenantID(ctx), scheduleTriggersCollection).Doc(schedule.ID + "_" + strings.ReplaceAll(key, "/", "_"))
+	if _, err := ref.Create(ctx, trigger); err != nil {
+		if status.Code(err) != codes.AlreadyExists {
+			return "", false, fmt.Errorf("failed to record trigger: %w", err)
+		}
+		doc, err := ref.Get(ctx)
+		if err != nil {
+			return "", true, fmt.Errorf("failed to load earlier trigger: %w", err)
+		}
+		var earlier scheduleTrigger
+		if err := doc.DataTo(&earlier); err != nil {
+			return "", true, fmt.Errorf("failed to decode earlier trigger: %w", err)
+		}
+		log.Printf("Ignoring repeated trigger %q of schedule %s", key, schedule.ID)
+		return earlier.SessionID, true, nil
+	}
+
+	// The run outlives the trigger request but keeps its correlation ID
+	runCtx := mcperrors.WithCorrelationID(context.Background(), mcperrors.CorrelationID(ctx))
+	go func() {
+		if _, err := o.runSchedule(runCtx, schedule, trigger.SessionID); err != nil {
+			log.Printf("Schedule %s: %v", schedule.ID, err)
+		}
+	}()
+	return trigger.SessionID, false, nil
+}
+
+// findSchedule loads a schedule by ID, or else by name
+func (o *Orchestrator) findSchedule(ctx context.Context, scheduleRef string) (*schemas.ResearchSchedule, error) {
+	if err := o.requireCloud(); err != nil {
+		return nil, err
+	}
+	// Names may contain slashes, which are never in an ID
+	if !strings.Contains(scheduleRef, "/") {
+		doc, err := o.collection(o.tenantID(ctx), schedulesCollection).Doc(scheduleRef).Get(ctx)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return nil, fmt.Errorf("failed to load schedule %s: %w", scheduleRef, err)
+		}
+		if err == nil {
+			var schedule schemas.ResearchSchedule
+			if err := doc.DataTo(&schedule); err != nil {
+				return nil, fmt.Errorf("failed to decode schedule %s: %w", scheduleRef, err)
+			}
+			schedule.Config.Tenant = o.tenantID(ctx)
+			return &schedule, nil
+		}
+	}
+
+	docs, err := o.collection(o.tenantID(ctx), schedulesCollection).Where("Name", "==", scheduleRef).Limit(2).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up schedule %s: %w", scheduleRef, err)
+	}
+	switch len(docs) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrScheduleNotFound, scheduleRef)
+	case 1:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrScheduleAmbiguous, scheduleRef)
+	}
+	var schedule schemas.ResearchSchedule
+	if err := docs[0].DataTo(&schedule); err != nil {
+		return nil, fmt.Errorf("failed to decode schedule %s: %w", docs[0].Ref.ID, err)
+	}
+	schedule.Config.Tenant = o.tenantID(ctx)
+	return &schedule, nil
+}
+
+// scheduledSessionID names the session of a schedule's run starting at t
+func scheduledSessionID(schedule *schemas.ResearchSchedule, t time.Time) string {
+	return fmt.Sprintf("%s-%s", schedule.ID, t.UTC().Format("20060102T150405"))
+}
+
+// runSchedule runs one session of a schedule's research and diffs its report
+// against the previous run's
+func (o *Orchestrator) runSchedule(ctx context.Context, schedule *schemas.ResearchSchedule, sessionID string) (*schemas.ResearchResult, error) {
+	// Runs belong to the tenant that created the schedule, whoever triggers them
+	ctx = WithTenant(ctx, schedule.Config.Tenant)
+	started := time.Now()
+	config := schedule.Config
+	config.SessionID = sessionID
+	config.CreatedAt = started
+
+	log.Printf("Running schedule %s (%s) as session %s", schedule.ID, schedule.Name, config.SessionID)
+	result, runErr := o.OrchestrateResearch(ctx, &config)
+
+	updates := []firestore.Update{
+		{Path: "LastRun", Value: started},
+		{Path: "LastSessionID", Value: config.SessionID},
+	}
+	if runErr != nil {
+		updates = append(updates, firestore.Update{Path: "LastStatus", Value: "failed"})
+	} else {
+		updates = append(updates, firestore.Update{Path: "LastStatus", Value: result.Status})
+		if report, ok := result.ReportData.(*schemas.ResearchReport); ok && report != nil {
+			if schedule.LastReportID != "" {
+				result.Diff = o.diffWithPreviousRun(ctx, schedule, config.SessionID, report)
+			}
+			updates = append(updates, firestore.Update{Path: "LastReportID", Value: report.ID})
+		}
+	}
+
+	if _, err := o.collection(o.tenantID(ctx), schedulesCollection).Doc(schedule.ID).Update(context.WithoutCancel(ctx), updates); err != nil {
+		log.Printf("Warning: Failed to record run of schedule %s: %v", schedule.ID, err)
+	}
+
+	if runErr != nil {
+		return nil, fmt.Errorf("scheduled run %s failed: %w", config.SessionID, runErr)
+	}
+	return result, nil
+}
+
+// diffWithPreviousRun compares report with the schedule's last report, saving
+// the diff. It returns nil if the previous report cannot be loaded.
+func (o *Orchestrator) diffWithPreviousRun(ctx context.Context, schedule *schemas.ResearchSchedule, sessionID string, report *schemas.ResearchReport) *schemas.ReportDiff {
+	previous, err := o.loadReport(ctx, schedule.LastReportID)
+	if err != nil {
+		log.Printf("Warning: Not diffing session %s against the previous run: %v", sessionID, err)
+		return nil
+	}
+
+	diff := diffReports(previous, report)
+	diff.ScheduleID = schedule.ID
+	if err := o.storeReportDiff(ctx, sessionID, schedule.Config.Topic, diff); err != nil {
+		log.Printf("Warning: Failed to save diff for session %s: %v", sessionID, err)
+	}
+	return diff
+}
+
+// startScheduler runs due schedules every minute until stopScheduler is called
+func (o *Orchestrator) startScheduler() {
+	o.schedulerStop = make(chan struct{})
+	o.schedulerDone = make(chan struct{})
+
+	go func() {
+		defer close(o.schedulerDone)
+
+		ticker := time.NewTicker(schedulerInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-o.schedulerStop:
+				return
+			case <-ticker.C:
+				o.runDueSchedules(context.Background())
+			}
+		}
+	}()
+}
+
+// stopScheduler stops looking for due schedules; runs already started continue
+func (o *Orchestrator) stopScheduler() {
+	if o.schedulerStop == nil {
+		return
+	}
+	close(o.schedulerStop)
+	<-o.schedulerDone
+	o.schedulerStop = nil
+}
+
+// runDueSchedules starts every tenant's enabled schedules whose next run has passed
+func (o *Orchestrator) runDueSchedules(ctx context.Context) {
+	now := time.Now()
+	for _, tenantID := range o.tenants.IDs() {
+		o.runDueTenantSchedules(ctx, tenantID, now)
+	}
+}
+
+// runDueTenantSchedules starts a tenant's enabled schedules whose next run has passed
+func (o *Orchestrator) runDueTenantSchedules(ctx context.Context, tenantID string, now time.Time) {
+	docs, err := o.collection(tenantID, schedulesCollection).Where("NextRun", "<=", now).Documents(ctx).GetAll()
+	if err != nil {
+		log.Printf("Warning: Failed to query due schedules of tenant %q: %v", tenantID, err)
+		return
+	}
+
+	for _, doc := range docs {
+		schedule, err := o.claimSchedule(ctx, doc.Ref, now)
+		if errors.Is(err, errScheduleNotDue) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Warning: Failed to claim schedule %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		// The schedule runs in the tenant whose collection it is stored in
+		schedule.Config.Tenant = tenantID
+
+		go func() {
+			if _, err := o.runSchedule(context.Background(), schedule, scheduledSessionID(schedule, now)); err != nil {
+				log.Printf("Schedule %s: %v", schedule.ID, err)
+			}
+		}()
+	}
+}
+
+// claimSchedule advances a due schedule to its next run in a transaction, so
+// each run is started by only one orchestrator
+func (o *Orchestrator) claimSchedule(ctx context.Context, ref *firestore.DocumentRef, now time.Time) (*schemas.ResearchSchedule, error) {
+	var schedule schemas.ResearchSchedule
+	err := o.firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		if err := doc.DataTo(&schedule); err != nil {
+			return err
+		}
+		if !schedule.Enabled || schedule.NextRun.After(now) {
+			return errScheduleNotDue
+		}
+
+		cron, err := scheduleCron(&schedule)
+		if err != nil {
+			// Stop retrying a schedule that can no longer be parsed
+			log.Printf("Warning: Disabling schedule %s: %v", schedule.ID, err)
+			schedule.Enabled = false
+			return tx.Update(ref, []firestore.Update{{Path: "Enabled", Value: false}})
+		}
+		return tx.Update(ref, []firestore.Update{{Path: "NextRun", Value: cron.Next(now)}})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !schedule.Enabled {
+		return nil, errScheduleNotDue
+	}
+	return &schedule, nil
+}
@@ -0,0 +1,142 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+)
+
+// sessionsCollection holds the current status of each research session
+const sessionsCollection = "research_sessions"
+
+// resultsCollection holds each session's drone results, under its session document,
+// so large sessions can be analyzed a result at a time
+const resultsCollection = "results"
+
+// reportsCollection holds the report of each finished session
+const reportsCollection = "research_reports"
+
+// sessionStates are the allowed moves between research session statuses. Every
+// status other than initializing and running is final; interrupted sessions
+// were stopped by an orchestrator shutdown, and cancelled ones by their caller.
+var sessionStates = gcp.StateMachine{
+	"initializing": {"running", "failed", "timeout", "budget_exceeded", "interrupted", "cancelled"},
+	"running":      {"completed", "failed", "failed_report_generation", "timeout", "budget_exceeded", "interrupted", "cancelled"},
+}
+
+// recordSessionStart stores a new session as initializing, replacing any earlier
+// run that used the same session ID
+func (o *Orchestrator) recordSessionStart(session *ResearchSession) {
+	if o.firestoreClient == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := o.collection(session.Config.Tenant, sessionsCollection).Doc(session.Config.SessionID).Set(ctx, map[string]interface{}{
+		"session_id":     session.Config.SessionID,
+		"correlation_id": session.Config.CorrelationID,
+		"status":         session.Status,
+		"drones_total":   session.Config.ResearcherCount,
+		// Kept so the session can be replayed from its stored results
+		"config":     session.Config,
+		"started_at": session.StartTime,
+		"updated_at": time.Now(),
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to store status of session %s: %v", session.Config.SessionID, err)
+	}
+}
+
+// setSessionStatus moves a session to status, in memory and in Firestore. It returns
+// false if the session already reached a final status, for example when the timeout
+// monitor and result collection race to finish the same session.
+func (o *Orchestrator) setSessionStatus(session *ResearchSession, status string) bool {
+	session.statusMu.Lock()
+	defer session.statusMu.Unlock()
+
+	current := o.sessionStatus(session)
+	if !sessionStates.CanTransition(current, status) {
+		log.Printf("Session %s is already %s, not moving it to %s", session.Config.SessionID, current, status)
+		return false
+	}
+
+	if o.firestoreClient != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		_, err := gcp.TransitionDocument(ctx, o.firestoreClient, TenantCollection(session.Config.Tenant, sessionsCollection), session.Config.SessionID, "status", status, sessionStates, map[string]interface{}{
+			"session_id": session.Config.SessionID,
+			"updated_at": time.Now(),
+		})
+		if errors.Is(err, gcp.ErrInvalidTransition) {
+			log.Printf("Session %s was finished elsewhere, not moving it to %s: %v", session.Config.SessionID, status, err)
+			return false
+		}
+		if err != nil {
+			// The in-memory status stays authoritative for this process
+			log.Printf("Warning: Failed to store status of session %s: %v", session.Config.SessionID, err)
+		}
+	}
+
+	o.mu.Lock()
+	session.Status = status
+	o.mu.Unlock()
+
+	o.notifySessionFinished(session, status)
+	return true
+}
+
+// recordSessionProgress stores how many drones have reported, so dashboards can
+// show a session's progress without access to this process. The last failure
+// among the latest results is kept as last_error.
+func (o *Orchestrator) recordSessionProgress(session *ResearchSession, latest []schemas.DroneResult) {
+	if o.firestoreClient == nil {
+		return
+	}
+
+	completed, failed := 0, 0
+	o.mu.RLock()
+	for _, result := range session.Results {
+		if result.Status == "completed" {
+			completed++
+		} else {
+			failed++
+		}
+	}
+	o.mu.RUnlock()
+
+	progress := map[string]interface{}{
+		"drones_total":     session.Config.ResearcherCount,
+		"drones_completed": completed,
+		"drones_failed":    failed,
+		"updated_at":       time.Now(),
+	}
+	for _, result := range latest {
+		if result.Status != "completed" && result.Error != "" {
+			progress["last_error"] = fmt.Sprintf("drone %s: %s", result.DroneID, result.Error)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := o.collection(session.Config.Tenant, sessionsCollection).Doc(session.Config.SessionID).Set(ctx, progress, firestore.MergeAll)
+	if err != nil {
+		log.Printf("Warning: Failed to store progress of session %s: %v", session.Config.SessionID, err)
+	}
+}
+
+// sessionStatus returns a session's current status
+func (o *Orchestrator) sessionStatus(session *ResearchSession) string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return session.Status
+}
@@ -0,0 +1,230 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/payload"
+)
+
+// ErrSimulated is returned for operations that need Google Cloud when the
+// orchestrator runs in simulation mode
+var ErrSimulated = errors.New("not available in simulation mode")
+
+// SimulationConfig configures simulated drones, which skip Cloud Run and
+// Pub/Sub and report synthetic results
+type SimulationConfig struct {
+	// Enabled simulates every session and creates no GCP clients
+	Enabled bool
+	// Delay is roughly how long a simulated drone takes to research its sub-query
+	Delay time.Duration
+	// FailureRate is the fraction of simulated drones that report a failure
+	FailureRate float64
+}
+
+// LoadSimulationConfig reads the simulation configuration from the environment
+func LoadSimulationConfig() SimulationConfig {
+	config := SimulationConfig{
+		Delay:       3 * time.Second,
+		FailureRate: 0.05,
+	}
+	config.Enabled, _ = strconv.ParseBool(getEnvOrDefault("WIDESCREEN_SIMULATE", "false"))
+	if delay, err := time.ParseDuration(getEnvOrDefault("SIMULATE_DELAY", "")); err == nil && delay >= 0 {
+		config.Delay = delay
+	}
+	if rate, err := strconv.ParseFloat(getEnvOrDefault("SIMULATE_FAILURE_RATE", ""), 64); err == nil && rate >= 0 && rate <= 1 {
+		config.FailureRate = rate
+	}
+	return config
+}
+
+// newSimulatedOrchestrator creates an orchestrator that runs every session with
+// simulated drones and needs no Google Cloud project or credentials
+func newSimulatedOrchestrator(simulation SimulationConfig, tenants *TenantRegistry) *Orchestrator {
+	orch := &Orchestrator{
+		mcpClient:      NewMCPClient(),
+		claudeAgent:    NewClaudeAgent(),
+		activeSessions: make(map[string]*ResearchSession),
+		reports:        make(map[string]*schemas.ResearchReport),
+		graphs:         make(map[string]*schemas.KnowledgeGraph),
+		templates:      make(map[string]*ResearchTemplate),
+		projectID:      gcp.ProjectID(),
+		region:         getEnvOrDefault("GOOGLE_CLOUD_REGION", "us-central1"),
+		costRates:      LoadCostRates(),
+		resultPipeline: LoadResultPipelineConfig(),
+		payloads:       payload.NewCodec(payload.LoadConfig()),
+		simulation:     simulation,
+		tenants:        tenants,
+	}
+	orch.warmPool = NewDronePool(orch, WarmPoolConfig{})
+	orch.notifier = LoadNotifier()
+	orch.sourceScorer = NewSourceScorer(LoadSourceScoringConfig())
+	orch.heartbeat = LoadHeartbeatConfig()
+	orch.dispatch = LoadDispatchConfig()
+	// Cluster findings without calling an embedding API
+	orch.embedder = hashEmbedder{dimensions: 256}
+	// Findings are searchable until the process exits
+	orch.vectors = newMemoryVectorStore()
+	orch.loadTemplates()
+
+	log.Println("Simulation mode: drones are simulated and no Google Cloud resources are created")
+	return orch
+}
+
+// simulating reports whether a session runs simulated drones
+func (o *Orchestrator) simulating(config *schemas.ResearchConfig) bool {
+	return o.simulation.Enabled || config.Simulate
+}
+
+// requireCloud fails operations that need Google Cloud in simulation mode
+func (o *Orchestrator) requireCloud() error {
+	if o.simulation.Enabled {
+		return ErrSimulated
+	}
+	return nil
+}
+
+// provisionSimulatedDrones adds the session's drones without deploying anything,
+// spread across its regions as real drones would be
+func (o *Orchestrator) provisionSimulatedDrones(session *ResearchSession) {
+	regions := o.sessionRegions(session.Config)
+	now := time.Now()
+
+	o.mu.Lock()
+	for i := 0; i < session.Config.ResearcherCount; i++ {
+		droneID := tenantResourceName(session.Config.Tenant, fmt.Sprintf("drone-%s-%d", session.Config.SessionID, i))
+		session.Drones[droneID] = &DroneInfo{
+			ID:          droneID,
+			Region:      regions[i%len(regions)],
+			Status:      "deployed",
+			StartTime:   now,
+			LastCheckin: now,
+			Simulated:   true,
+		}
+	}
+	o.mu.Unlock()
+
+	log.Printf("Simulated %d drones for session %s", session.Config.ResearcherCount, session.Config.SessionID)
+}
+
+// simulateDroneTask has a simulated drone report a synthetic result for its
+// task after the configured delay, as if it had published it to Pub/Sub
+func (o *Orchestrator) simulateDroneTask(drone *DroneInfo, task map[string]interface{}) error {
+	sessionID, _ := task["run_id"].(string)
+	o.mu.RLock()
+	session := o.activeSessions[sessionID]
+	o.mu.RUnlock()
+	if session == nil {
+		return fmt.Errorf("session %s is not active", sessionID)
+	}
+
+	subject, _ := task["subject"].(string)
+	taskID, _ := task["task_id"].(string)
+	rng := rand.New(rand.NewSource(simulationSeed(drone.ID, taskID)))
+	// Vary the delay so results arrive spread out, as real ones do
+	delay := time.Duration(float64(o.simulation.Delay) * (0.5 + rng.Float64()))
+
+	go func() {
+		time.Sleep(delay)
+		result := syntheticResult(rng, drone.ID, taskID, subject, o.simulation.FailureRate)
+		result.CorrelationID, _ = task["correlation_id"].(string)
+		result.ProcessingTime = delay
+		session.Queue.Deliver(result)
+	}()
+	return nil
+}
+
+// simulationSeed makes a simulated drone's result the same on every run
+func simulationSeed(droneID, taskID string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(droneID + "/" + taskID))
+	return int64(h.Sum64())
+}
+
+// sharedClaims are findings any simulated drone may report, so results overlap
+// the way real drones' do and deduplication has something to merge
+var sharedClaims = []string{
+	"Adoption has accelerated markedly since 2022",
+	"Regulatory scrutiny is increasing across the US and EU",
+	"Most growth is concentrated among a few large players",
+	"Costs have fallen steadily as the technology matured",
+	"Independent benchmarks show wide variation in results",
+}
+
+// subjectClaims are findings specific to a drone's sub-query
+var subjectClaims = []string{
+	"Recent reporting on %s highlights rapid change",
+	"Experts disagree on the long-term outlook for %s",
+	"Public data on %s remains limited and inconsistent",
+	"Several case studies of %s point to measurable benefits",
+	"Critics of %s cite unresolved risks",
+}
+
+// simulatedSources are the sites synthetic results cite, so credibility scoring sees a realistic mix
+var simulatedSources = []string{
+	"reuters.com",
+	"nature.com",
+	"arxiv.org",
+	"en.wikipedia.org",
+	"techcrunch.com",
+	"medium.com",
+}
+
+// syntheticResult builds a plausible drone result for subject. The result is
+// shaped like a real drone's so analysis and reporting treat it the same way.
+func syntheticResult(rng *rand.Rand, droneID, taskID, subject string, failureRate float64) schemas.DroneResult {
+	result := schemas.DroneResult{
+		DroneID:     droneID,
+		TaskID:      taskID,
+		CompletedAt: time.Now(),
+	}
+	if rng.Float64() < failureRate {
+		result.Status = "failed"
+		result.Error = "simulated drone failure"
+		result.Data = map[string]interface{}{"simulated": true}
+		return result
+	}
+
+	slug := strings.ToLower(strings.Join(strings.Fields(subject), "-"))
+	if len(slug) > 40 {
+		slug = slug[:40]
+	}
+	var sources []interface{}
+	for i, n := 0, 2+rng.Intn(3); i < n; i++ {
+		sources = append(sources, map[string]interface{}{
+			"url":          fmt.Sprintf("https://%s/simulated/%s-%d", simulatedSources[rng.Intn(len(simulatedSources))], slug, i+1),
+			"published_at": time.Now().AddDate(0, -rng.Intn(36), 0).Format("2006-01-02"),
+		})
+	}
+
+	var findings []interface{}
+	cite := func(claim string) {
+		source := sources[rng.Intn(len(sources))].(map[string]interface{})["url"]
+		findings = append(findings, map[string]interface{}{
+			"claim":   claim,
+			"sources": []interface{}{source},
+		})
+	}
+	cite(sharedClaims[rng.Intn(len(sharedClaims))])
+	for _, i := range rng.Perm(len(subjectClaims))[:2+rng.Intn(2)] {
+		cite(fmt.Sprintf(subjectClaims[i], subject))
+	}
+
+	result.Status = "completed"
+	result.Data = map[string]interface{}{
+		"summary":   fmt.Sprintf("Simulated research on %s found %d findings across %d sources.", subject, len(findings), len(sources)),
+		"findings":  findings,
+		"sources":   sources,
+		"exa_calls": float64(3 + rng.Intn(8)),
+		"simulated": true,
+	}
+	return result
+}
@@ -0,0 +1,194 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrSessionExists is returned when importing a session that is already stored
+var ErrSessionExists = errors.New("session already exists")
+
+// ExportSession bundles a session's stored config, status, drone results and
+// latest report into a snapshot
+func (o *Orchestrator) ExportSession(ctx context.Context, sessionID string) (*schemas.SessionSnapshot, error) {
+	if err := o.requireCloud(); err != nil {
+		return nil, err
+	}
+	if o.firestoreClient == nil {
+		return nil, fmt.Errorf("export needs the session stored in Firestore")
+	}
+
+	doc, err := o.collection(o.tenantID(ctx), sessionsCollection).Doc(sessionID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session %s: %w", sessionID, err)
+	}
+	var stored struct {
+		Status string                  `firestore:"status"`
+		Config *schemas.ResearchConfig `firestore:"config"`
+	}
+	if err := doc.DataTo(&stored); err != nil {
+		return nil, fmt.Errorf("failed to decode session %s: %w", sessionID, err)
+	}
+
+	results, err := o.loadSessionResults(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	report, err := o.latestSessionReport(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &schemas.SessionSnapshot{
+		Version:    schemas.SessionSnapshotVersion,
+		ExportedAt: time.Now(),
+		SessionID:  sessionID,
+		Status:     stored.Status,
+		Config:     stored.Config,
+		Results:    results,
+		Report:     report,
+	}
+	if snapshot.Results == nil {
+		snapshot.Results = []schemas.DroneResult{}
+	}
+	if report != nil {
+		snapshot.Metrics = &report.Metadata.Metrics
+	}
+	return snapshot, nil
+}
+
+// latestSessionReport returns the newest report stored for a session, or nil if there is none
+func (o *Orchestrator) latestSessionReport(ctx context.Context, sessionID string) (*schemas.ResearchReport, error) {
+	docs, err := o.collection(o.tenantID(ctx), reportsCollection).Where("SessionID", "==", sessionID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reports of session %s: %w", sessionID, err)
+	}
+
+	var latest *schemas.ResearchReport
+	for _, doc := range docs {
+		var report schemas.ResearchReport
+		if err := doc.DataTo(&report); err != nil {
+			log.Printf("Warning: Skipping unreadable report %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		if latest == nil || report.CreatedAt.After(latest.CreatedAt) {
+			latest = &report
+		}
+	}
+	return latest, nil
+}
+
+// ImportSession stores a snapshot's session, drone results and report, so the
+// session can be inspected, analyzed and replayed here. It fails with
+// ErrSessionExists if the session is already stored, unless overwrite is set.
+func (o *Orchestrator) ImportSession(ctx context.Context, snapshot *schemas.SessionSnapshot, overwrite bool) error {
+	if err := o.requireCloud(); err != nil {
+		return err
+	}
+	if o.firestoreClient == nil {
+		return fmt.Errorf("import needs Firestore to store the session")
+	}
+	if snapshot.Version != schemas.SessionSnapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d, expected %d", snapshot.Version, schemas.SessionSnapshotVersion)
+	}
+	if snapshot.SessionID == "" {
+		return fmt.Errorf("snapshot has no session_id")
+	}
+	if snapshot.Config != nil && snapshot.Config.SessionID != snapshot.SessionID {
+		return fmt.Errorf("snapshot config is for session %s, not %s", snapshot.Config.SessionID, snapshot.SessionID)
+	}
+	if snapshot.Report != nil && snapshot.Report.SessionID != snapshot.SessionID {
+		return fmt.Errorf("snapshot report is for session %s, not %s", snapshot.Report.SessionID, snapshot.SessionID)
+	}
+
+	// The session moves into the importing tenant, whichever one exported it
+	if snapshot.Config != nil {
+		snapshot.Config.Tenant = o.tenantID(ctx)
+	}
+	if snapshot.Report != nil {
+		snapshot.Report.Tenant = o.tenantID(ctx)
+	}
+
+	// Imported sessions are finished, so they are written as they were rather
+	// than moved through the session states
+	sessionRef := o.collection(o.tenantID(ctx), sessionsCollection).Doc(snapshot.SessionID)
+	session := map[string]interface{}{
+		"session_id":  snapshot.SessionID,
+		"status":      snapshot.Status,
+		"config":      snapshot.Config,
+		"imported_at": time.Now(),
+		"updated_at":  time.Now(),
+	}
+	if overwrite {
+		if _, err := sessionRef.Set(ctx, session); err != nil {
+			return fmt.Errorf("failed to store session %s: %w", snapshot.SessionID, err)
+		}
+		if err := o.deleteSessionResults(ctx, sessionRef); err != nil {
+			return err
+		}
+	} else if _, err := sessionRef.Create(ctx, session); err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return fmt.Errorf("%w: %s", ErrSessionExists, snapshot.SessionID)
+		}
+		return fmt.Errorf("failed to store session %s: %w", snapshot.SessionID, err)
+	}
+
+	// Keyed the way recordSessionResult keys them
+	results := sessionRef.Collection(resultsCollection)
+	writer := o.firestoreClient.BulkWriter(ctx)
+	for i, result := range snapshot.Results {
+		id := fmt.Sprintf("imported-%d", i)
+		if result.TaskID != "" {
+			id = result.DroneID + "-" + result.TaskID
+		}
+		if err := o.sealResult(ctx, &result); err != nil {
+			writer.End()
+			return fmt.Errorf("failed to store result %s: %w", id, err)
+		}
+		if _, err := writer.Set(results.Doc(id), result); err != nil {
+			writer.End()
+			return fmt.Errorf("failed to store result %s: %w", id, err)
+		}
+	}
+	writer.End()
+
+	if snapshot.Report != nil {
+		if err := o.storeReport(ctx, snapshot.Report); err != nil {
+			return fmt.Errorf("failed to store report %s: %w", snapshot.Report.ID, err)
+		}
+		o.mu.Lock()
+		o.reports[snapshot.Report.ID] = snapshot.Report
+		o.mu.Unlock()
+	}
+
+	log.Printf("Imported session %s with %d results", snapshot.SessionID, len(snapshot.Results))
+	return nil
+}
+
+// deleteSessionResults removes the results stored under a session before they are replaced
+func (o *Orchestrator) deleteSessionResults(ctx context.Context, sessionRef *firestore.DocumentRef) error {
+	refs, err := sessionRef.Collection(resultsCollection).DocumentRefs(ctx).GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to list results of session %s: %w", sessionRef.ID, err)
+	}
+	writer := o.firestoreClient.BulkWriter(ctx)
+	for _, ref := range refs {
+		if _, err := writer.Delete(ref); err != nil {
+			writer.End()
+			return fmt.Errorf("failed to delete result %s: %w", ref.ID, err)
+		}
+	}
+	writer.End()
+	return nil
+}
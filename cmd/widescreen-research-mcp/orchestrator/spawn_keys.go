@@ -0,0 +1,69 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	runpb "cloud.google.com/go/run/apiv2/runpb"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// claimDroneSpawn records in Firestore that the drone at index of a session is
+// being deployed to region. When an earlier attempt already deployed it and its
+// service is serving, that service's URL and region are returned instead.
+func (o *Orchestrator) claimDroneSpawn(ctx context.Context, index int, droneID, region string, config *schemas.ResearchConfig) (*gcp.SpawnRecord, string, error) {
+	if o.firestoreClient == nil || config.SessionID == "" {
+		return nil, "", nil
+	}
+
+	record, claimed, err := gcp.ClaimSpawn(ctx, o.firestoreClient, gcp.SpawnRecord{
+		Key:         gcp.SpawnKey(config.SessionID, index),
+		SessionID:   config.SessionID,
+		DroneID:     droneID,
+		ServiceName: droneID,
+		Region:      region,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if claimed {
+		return &record, "", nil
+	}
+
+	service, err := o.getDroneService(ctx, record.ServiceName, record.Region)
+	if err != nil {
+		if status.Code(err) != codes.NotFound {
+			log.Printf("Warning: failed to check drone %s from an earlier attempt: %v", record.DroneID, err)
+		}
+		return &record, "", nil
+	}
+	if service.Uri == "" {
+		return &record, "", nil
+	}
+	log.Printf("Reusing drone %s in %s deployed by an earlier attempt", record.DroneID, record.Region)
+	return &record, service.Uri, nil
+}
+
+// moveDroneSpawn updates a claimed spawn after its drone failed over to another region
+func (o *Orchestrator) moveDroneSpawn(ctx context.Context, record *gcp.SpawnRecord, region string) {
+	if record == nil || record.Region == region {
+		return
+	}
+	record.Region = region
+	if err := gcp.RecordSpawn(ctx, o.firestoreClient, *record); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// getDroneService retrieves a drone's Cloud Run service in a region
+func (o *Orchestrator) getDroneService(ctx context.Context, droneID, region string) (*runpb.Service, error) {
+	return o.runClient.GetService(ctx, &runpb.GetServiceRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/services/%s", o.projectID, region, droneID),
+	})
+}
@@ -0,0 +1,206 @@
+package orchestrator
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+)
+
+const (
+	supervisorInitialBackoff = 1 * time.Second
+	supervisorMaxBackoff     = 30 * time.Second
+	supervisorRestartTimeout = 30 * time.Second
+	// A process that stays up this long is considered healthy and resets the backoff
+	supervisorStableRun = 1 * time.Minute
+)
+
+var errSupervisorStopped = fmt.Errorf("supervisor stopped")
+
+// stdioProcess is an external MCP server subprocess owned by the orchestrator
+type stdioProcess struct {
+	cmd     *exec.Cmd
+	started time.Time
+	exited  chan struct{}
+	err     error
+}
+
+// startStdioProcess launches an MCP server subprocess and returns a client bound to its stdio.
+// The process is spawned here rather than by mcp-go so its exit can be observed.
+func startStdioProcess(name string, config ExternalServerConfig) (*client.Client, *stdioProcess, error) {
+	cmd := exec.Command(config.Command, config.Args...)
+	cmd.Env = os.Environ()
+	for k, v := range config.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	// Use os pipes instead of cmd.*Pipe so cmd.Wait does not close the client's ends
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		stdinR.Close()
+		stdinW.Close()
+		return nil, nil, err
+	}
+	stderrR, stderrW, err := os.Pipe()
+	if err != nil {
+		stdinR.Close()
+		stdinW.Close()
+		stdoutR.Close()
+		stdoutW.Close()
+		return nil, nil, err
+	}
+
+	cmd.Stdin = stdinR
+	cmd.Stdout = stdoutW
+	cmd.Stderr = stderrW
+
+	if err := cmd.Start(); err != nil {
+		for _, f := range []*os.File{stdinR, stdinW, stdoutR, stdoutW, stderrR, stderrW} {
+			f.Close()
+		}
+		return nil, nil, err
+	}
+
+	// The child holds its own copies of these ends
+	stdinR.Close()
+	stdoutW.Close()
+	stderrW.Close()
+
+	process := &stdioProcess{
+		cmd:     cmd,
+		started: time.Now(),
+		exited:  make(chan struct{}),
+	}
+	go func() {
+		process.err = cmd.Wait()
+		close(process.exited)
+	}()
+
+	go logStderr(name, stderrR)
+
+	// Closing the client closes stdin and stderr, and with them stdout
+	mcpClient := client.NewClient(transport.NewIO(stdoutR, stdinW, outputPipes{File: stderrR, stdout: stdoutR}))
+	if err := mcpClient.Start(context.Background()); err != nil {
+		mcpClient.Close()
+		return nil, nil, err
+	}
+
+	return mcpClient, process, nil
+}
+
+// outputPipes is the read end of a process's stderr that also closes the read
+// end of its stdout, which the transport never closes itself
+type outputPipes struct {
+	*os.File
+	stdout *os.File
+}
+
+func (p outputPipes) Close() error {
+	p.stdout.Close()
+	return p.File.Close()
+}
+
+// logStderr forwards a server's stderr to the orchestrator log
+func logStderr(name string, stderr *os.File) {
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		log.Printf("[%s] %s", name, scanner.Text())
+	}
+}
+
+// supervise restarts a stdio session whenever its process exits, until the session is stopped
+func (c *MCPClient) supervise(server *externalServer, sess *session, process *stdioProcess) {
+	stop := sess.stop
+	backoff := supervisorInitialBackoff
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-process.exited:
+		}
+
+		// Shutdown closes the client, which also ends the process
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		if time.Since(process.started) >= supervisorStableRun {
+			backoff = supervisorInitialBackoff
+		}
+		log.Printf("Warning: MCP server %s exited (%v), restarting in %v", server.name, process.err, backoff)
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(backoff):
+			}
+
+			next, err := c.restart(server, sess)
+			if err == nil {
+				process = next
+				break
+			}
+			if err == errSupervisorStopped {
+				return
+			}
+
+			backoff *= 2
+			if backoff > supervisorMaxBackoff {
+				backoff = supervisorMaxBackoff
+			}
+			log.Printf("Warning: failed to restart MCP server %s: %v, retrying in %v", server.name, err, backoff)
+		}
+
+		log.Printf("Restarted MCP server %s", server.name)
+	}
+}
+
+// restart relaunches a session's process, replays the initialize handshake and
+// tool discovery, and swaps the new client into the session
+func (c *MCPClient) restart(server *externalServer, sess *session) (*stdioProcess, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), supervisorRestartTimeout)
+	defer cancel()
+
+	mcpClient, process, err := c.connect(ctx, server.name, server.config)
+	if err != nil {
+		return nil, err
+	}
+
+	tools, err := discoverTools(ctx, mcpClient)
+	if err != nil {
+		mcpClient.Close()
+		return nil, fmt.Errorf("failed to list tools: %w", err)
+	}
+
+	c.mu.Lock()
+	select {
+	case <-sess.stop:
+		c.mu.Unlock()
+		mcpClient.Close()
+		return nil, errSupervisorStopped
+	default:
+	}
+	previous := sess.client
+	sess.client = mcpClient
+	server.tools = tools
+	c.mu.Unlock()
+
+	// Release the dead process's pipes
+	previous.Close()
+
+	return process, nil
+}
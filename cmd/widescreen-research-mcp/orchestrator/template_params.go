@@ -0,0 +1,284 @@
+package orchestrator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+)
+
+// Template parameter types
+const (
+	ParamString  = "string"
+	ParamNumber  = "number"
+	ParamBoolean = "boolean"
+	ParamList    = "list"
+)
+
+// TemplateParameter is a variable a template's workflow refers to as {{name}}
+type TemplateParameter struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type"` // string, number, boolean or list; empty means string
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+}
+
+var (
+	// parameterNamePattern matches valid parameter names
+	parameterNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+	// placeholderPattern matches a {{name}} placeholder, allowing spaces inside the braces
+	placeholderPattern = regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+)
+
+// validateTemplateParameters checks a template's parameter declarations and
+// that its workflow only refers to declared parameters
+func validateTemplateParameters(template *ResearchTemplate) error {
+	declared := make(map[string]bool, len(template.Parameters))
+	for i := range template.Parameters {
+		param := &template.Parameters[i]
+		if !parameterNamePattern.MatchString(param.Name) {
+			return mcperrors.InvalidInput("invalid parameter name %q", param.Name)
+		}
+		if declared[param.Name] {
+			return mcperrors.InvalidInput("parameter %s is declared twice", param.Name)
+		}
+		declared[param.Name] = true
+
+		if param.Type == "" {
+			param.Type = ParamString
+		}
+		if param.Default != nil {
+			value, err := coerceParameter(*param, param.Default)
+			if err != nil {
+				return mcperrors.InvalidInput("invalid default for parameter %s: %w", param.Name, err)
+			}
+			param.Default = value
+		}
+	}
+
+	var undeclared []string
+	for _, name := range workflowPlaceholders(template.Workflow) {
+		if !declared[name] {
+			undeclared = append(undeclared, name)
+		}
+	}
+	if len(undeclared) > 0 {
+		return mcperrors.InvalidInput("workflow refers to undeclared parameters: %s", strings.Join(undeclared, ", "))
+	}
+	return nil
+}
+
+// workflowPlaceholders returns the sorted names of the parameters a workflow refers to
+func workflowPlaceholders(workflow interface{}) []string {
+	names := make(map[string]bool)
+	walkWorkflowStrings(workflow, func(s string) {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+			names[match[1]] = true
+		}
+	})
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// walkWorkflowStrings calls visit with every string in a workflow
+func walkWorkflowStrings(value interface{}, visit func(string)) {
+	switch v := value.(type) {
+	case string:
+		visit(v)
+	case map[string]interface{}:
+		for _, item := range v {
+			walkWorkflowStrings(item, visit)
+		}
+	case []interface{}:
+		for _, item := range v {
+			walkWorkflowStrings(item, visit)
+		}
+	case []string:
+		for _, item := range v {
+			visit(item)
+		}
+	}
+}
+
+// coerceParameter converts a supplied value to the parameter's type. Strings
+// are accepted for every type, so values typed into elicitation answers work.
+func coerceParameter(param TemplateParameter, value interface{}) (interface{}, error) {
+	switch param.Type {
+	case ParamString, "":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case ParamNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case string:
+			if n, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return n, nil
+			}
+		}
+	case ParamBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				return b, nil
+			}
+		}
+	case ParamList:
+		switch v := value.(type) {
+		case []string:
+			return v, nil
+		case []interface{}:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("list items must be strings")
+				}
+				items = append(items, s)
+			}
+			return items, nil
+		case string:
+			return splitList(v), nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %q", param.Type)
+	}
+	return nil, fmt.Errorf("expected a %s, got %T", param.Type, value)
+}
+
+// resolveParameters validates supplied values against a template's parameters
+// and fills in defaults. Every missing required parameter is reported at once.
+func resolveParameters(template *ResearchTemplate, values map[string]interface{}) (map[string]interface{}, error) {
+	declared := make(map[string]TemplateParameter, len(template.Parameters))
+	for _, param := range template.Parameters {
+		declared[param.Name] = param
+	}
+	for name := range values {
+		if _, ok := declared[name]; !ok {
+			return nil, mcperrors.InvalidInput("template %s has no parameter %q", template.ID, name)
+		}
+	}
+
+	resolved := make(map[string]interface{}, len(template.Parameters))
+	var missing []string
+	for _, param := range template.Parameters {
+		value, supplied := values[param.Name]
+		if s, ok := value.(string); ok && strings.TrimSpace(s) == "" {
+			supplied = false
+		}
+		if !supplied || value == nil {
+			switch {
+			case param.Default != nil:
+				resolved[param.Name] = param.Default
+			case param.Required:
+				missing = append(missing, param.Name)
+			}
+			continue
+		}
+
+		coerced, err := coerceParameter(param, value)
+		if err != nil {
+			return nil, mcperrors.InvalidInput("invalid value for parameter %s: %w", param.Name, err)
+		}
+		resolved[param.Name] = coerced
+	}
+	if len(missing) > 0 {
+		return nil, mcperrors.InvalidInput("template %s requires parameters: %s", template.ID, strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
+
+// substituteWorkflow returns a copy of a workflow with {{name}} placeholders
+// replaced. A string that is only a placeholder takes the value itself, so
+// numbers and lists keep their type; placeholders inside longer strings are
+// formatted as text. Optional parameters without a value become empty.
+func substituteWorkflow(value interface{}, params map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if match := placeholderPattern.FindStringSubmatch(v); match != nil && match[0] == strings.TrimSpace(v) {
+			if param, ok := params[match[1]]; ok {
+				return param
+			}
+		}
+		return placeholderPattern.ReplaceAllStringFunc(v, func(placeholder string) string {
+			name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+			return formatParameter(params[name])
+		})
+	case map[string]interface{}:
+		substituted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			substituted[key] = substituteWorkflow(item, params)
+		}
+		return substituted
+	case []interface{}:
+		substituted := make([]interface{}, len(v))
+		for i, item := range v {
+			substituted[i] = substituteWorkflow(item, params)
+		}
+		return substituted
+	case []string:
+		substituted := make([]interface{}, len(v))
+		for i, item := range v {
+			substituted[i] = substituteWorkflow(item, params)
+		}
+		return substituted
+	default:
+		return v
+	}
+}
+
+// formatParameter formats a parameter value for use inside a string
+func formatParameter(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []string:
+		return strings.Join(v, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// InstantiateTemplate validates params against a template's parameters and
+// returns its workflow with the values substituted
+func (o *Orchestrator) InstantiateTemplate(templateID string, params map[string]interface{}) (map[string]interface{}, error) {
+	template := o.GetTemplate(templateID)
+	if template == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, templateID)
+	}
+
+	resolved, err := resolveParameters(template, params)
+	if err != nil {
+		return nil, err
+	}
+	workflow, _ := substituteWorkflow(template.Workflow, resolved).(map[string]interface{})
+	return workflow, nil
+}
+
+// GetTemplate returns the template with the given ID, or nil
+func (o *Orchestrator) GetTemplate(templateID string) *ResearchTemplate {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.templates[templateID]
+}
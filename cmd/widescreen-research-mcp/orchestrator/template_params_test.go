@@ -0,0 +1,196 @@
+package orchestrator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+)
+
+func TestResolveParameters(t *testing.T) {
+	template := &ResearchTemplate{
+		ID: "market-scan",
+		Parameters: []TemplateParameter{
+			{Name: "topic", Type: ParamString, Required: true},
+			{Name: "depth", Type: ParamNumber, Default: float64(2)},
+			{Name: "deep", Type: ParamBoolean},
+			{Name: "sources", Type: ParamList},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:   "defaults fill in omitted values",
+			values: map[string]interface{}{"topic": "solar"},
+			want:   map[string]interface{}{"topic": "solar", "depth": float64(2)},
+		},
+		{
+			name:   "blank strings count as omitted",
+			values: map[string]interface{}{"topic": "solar", "depth": "  "},
+			want:   map[string]interface{}{"topic": "solar", "depth": float64(2)},
+		},
+		{
+			name:   "typed values are kept",
+			values: map[string]interface{}{"topic": "solar", "depth": 3, "deep": true, "sources": []interface{}{"a", "b"}},
+			want:   map[string]interface{}{"topic": "solar", "depth": float64(3), "deep": true, "sources": []string{"a", "b"}},
+		},
+		{
+			name:   "strings are coerced",
+			values: map[string]interface{}{"topic": "solar", "depth": "4.5", "deep": "true", "sources": "a, b"},
+			want:   map[string]interface{}{"topic": "solar", "depth": 4.5, "deep": true, "sources": []string{"a", "b"}},
+		},
+		{
+			name:    "missing required parameter",
+			values:  map[string]interface{}{"depth": 1},
+			wantErr: "requires parameters: topic",
+		},
+		{
+			name:    "unknown parameter",
+			values:  map[string]interface{}{"topic": "solar", "region": "eu"},
+			wantErr: `has no parameter "region"`,
+		},
+		{
+			name:    "wrong number type",
+			values:  map[string]interface{}{"topic": "solar", "depth": "deep"},
+			wantErr: "invalid value for parameter depth",
+		},
+		{
+			name:    "wrong boolean type",
+			values:  map[string]interface{}{"topic": "solar", "deep": 1},
+			wantErr: "invalid value for parameter deep",
+		},
+		{
+			name:    "non-string list item",
+			values:  map[string]interface{}{"topic": "solar", "sources": []interface{}{"a", 1}},
+			wantErr: "invalid value for parameter sources",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveParameters(template, tt.values)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveParameters() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				var mcpErr *mcperrors.MCPError
+				if !errors.As(err, &mcpErr) || mcpErr.Category != mcperrors.CategoryInvalidInput {
+					t.Errorf("resolveParameters() error = %#v, want an invalid input error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveParameters() returned an error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveParameters() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubstituteWorkflow(t *testing.T) {
+	params := map[string]interface{}{
+		"topic":   "solar",
+		"depth":   float64(3),
+		"sources": []string{"a", "b"},
+	}
+
+	tests := []struct {
+		name     string
+		workflow interface{}
+		want     interface{}
+	}{
+		{"whole string keeps the type", "{{depth}}", float64(3)},
+		{"spaces inside braces", "{{ depth }}", float64(3)},
+		{"list keeps the type", "{{sources}}", []string{"a", "b"}},
+		{"embedded value is formatted", "{{topic}} to depth {{depth}}", "solar to depth 3"},
+		{"embedded list is joined", "from {{sources}}", "from a, b"},
+		{"missing optional value is empty", "about {{region}}", "about "},
+		{"whole missing value is empty", "{{region}}", ""},
+		{"non-strings are unchanged", true, true},
+		{
+			"nested maps and lists",
+			map[string]interface{}{
+				"steps": []interface{}{
+					map[string]interface{}{"query": "{{topic}} market", "count": "{{depth}}"},
+				},
+				"tags": []string{"{{topic}}", "fixed"},
+			},
+			map[string]interface{}{
+				"steps": []interface{}{
+					map[string]interface{}{"query": "solar market", "count": float64(3)},
+				},
+				"tags": []interface{}{"solar", "fixed"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := substituteWorkflow(tt.workflow, params)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("substituteWorkflow(%#v) = %#v, want %#v", tt.workflow, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTemplateParameters(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters []TemplateParameter
+		workflow   map[string]interface{}
+		wantErr    string
+	}{
+		{
+			name:       "declared placeholders",
+			parameters: []TemplateParameter{{Name: "topic"}},
+			workflow:   map[string]interface{}{"query": "{{topic}}"},
+		},
+		{
+			name:     "undeclared placeholder",
+			workflow: map[string]interface{}{"query": "{{topic}}"},
+			wantErr:  "undeclared parameters: topic",
+		},
+		{
+			name:       "invalid name",
+			parameters: []TemplateParameter{{Name: "1topic"}},
+			wantErr:    "invalid parameter name",
+		},
+		{
+			name:       "declared twice",
+			parameters: []TemplateParameter{{Name: "topic"}, {Name: "topic"}},
+			wantErr:    "declared twice",
+		},
+		{
+			name:       "bad default",
+			parameters: []TemplateParameter{{Name: "depth", Type: ParamNumber, Default: "deep"}},
+			wantErr:    "invalid default for parameter depth",
+		},
+		{
+			name:       "unknown type",
+			parameters: []TemplateParameter{{Name: "depth", Type: "date", Default: "today"}},
+			wantErr:    `unknown type "date"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTemplateParameters(&ResearchTemplate{Parameters: tt.parameters, Workflow: tt.workflow})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateTemplateParameters() returned an error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateTemplateParameters() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// templatesCollection holds the research templates teams have saved
+const templatesCollection = "research_templates"
+
+var (
+	// ErrTemplateNotFound is returned when no template has the given ID
+	ErrTemplateNotFound = errors.New("template not found")
+
+	// ErrTemplateExists is returned when creating a template whose ID is taken
+	ErrTemplateExists = errors.New("template already exists")
+
+	// ErrTemplateBuiltin is returned when changing one of the built-in templates
+	ErrTemplateBuiltin = errors.New("built-in templates cannot be changed")
+
+	// ErrTemplateNotOwned is returned when changing a template another tenant saved
+	ErrTemplateNotOwned = errors.New("template belongs to another tenant")
+)
+
+// templateIDPattern matches the IDs templates may have: lowercase words joined by hyphens
+var templateIDPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// templateID derives a template ID from its name, e.g. "Weekly Market Scan" becomes "weekly-market-scan"
+func templateID(name string) string {
+	var id strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if hyphen && id.Len() > 0 {
+				id.WriteByte('-')
+			}
+			id.WriteRune(r)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+	return id.String()
+}
+
+// loadStoredTemplates adds the templates saved in Firestore to the built-in ones
+func (o *Orchestrator) loadStoredTemplates(ctx context.Context) error {
+	docs, err := o.firestoreClient.Collection(templatesCollection).Documents(ctx).GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+
+	stored := make(map[string]*ResearchTemplate, len(docs))
+	for _, doc := range docs {
+		var template ResearchTemplate
+		if err := doc.DataTo(&template); err != nil {
+			log.Printf("Warning: Skipping unreadable template %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		stored[template.ID] = &template
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	// Drop templates deleted by other orchestrators since the last load
+	for id, template := range o.templates {
+		if !template.Builtin && stored[id] == nil {
+			delete(o.templates, id)
+		}
+	}
+	for id, template := range stored {
+		if existing := o.templates[id]; existing != nil && existing.Builtin {
+			log.Printf("Warning: Ignoring stored template %s, which has the ID of a built-in template", id)
+			continue
+		}
+		o.templates[id] = template
+	}
+	return nil
+}
+
+// ListTemplates returns the built-in and saved templates, refreshing the saved
+// ones from Firestore so templates other orchestrators saved are included
+func (o *Orchestrator) ListTemplates(ctx context.Context) ([]*ResearchTemplate, error) {
+	if err := o.loadStoredTemplates(ctx); err != nil {
+		return nil, err
+	}
+	return o.GetTemplates(), nil
+}
+
+// CreateTemplate validates and saves a new template. Its ID is derived from
+// its name when empty.
+func (o *Orchestrator) CreateTemplate(ctx context.Context, template *ResearchTemplate) error {
+	if err := o.requireCloud(); err != nil {
+		return err
+	}
+	if template.Name == "" {
+		return mcperrors.InvalidInput("template name is required")
+	}
+	if template.ID == "" {
+		template.ID = templateID(template.Name)
+	}
+	if !templateIDPattern.MatchString(template.ID) {
+		return mcperrors.InvalidInput("invalid template ID %q: use lowercase letters, digits and hyphens", template.ID)
+	}
+	if err := validateTemplateParameters(template); err != nil {
+		return err
+	}
+	if _, err := parseWorkflowSteps(template.Workflow); err != nil {
+		return mcperrors.InvalidInput("invalid workflow: %w", err)
+	}
+
+	o.mu.RLock()
+	existing := o.templates[template.ID]
+	o.mu.RUnlock()
+	if existing != nil && existing.Builtin {
+		return fmt.Errorf("%w: %s", ErrTemplateExists, template.ID)
+	}
+
+	template.Builtin = false
+	template.Tenant = o.tenantID(ctx)
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = template.CreatedAt
+	if _, err := o.firestoreClient.Collection(templatesCollection).Doc(template.ID).Create(ctx, template); err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return fmt.Errorf("%w: %s", ErrTemplateExists, template.ID)
+		}
+		return fmt.Errorf("failed to store template: %w", err)
+	}
+
+	o.mu.Lock()
+	o.templates[template.ID] = template
+	o.mu.Unlock()
+	return nil
+}
+
+// UpdateTemplate changes a saved template. Empty fields of changes are left as
+// they were; parameters, when given, replace all of the template's parameters.
+// Every tenant can run a saved template, but only the one that saved it can change it.
+func (o *Orchestrator) UpdateTemplate(ctx context.Context, changes *ResearchTemplate) (*ResearchTemplate, error) {
+	if err := o.checkTemplateWritable(changes.ID); err != nil {
+		return nil, err
+	}
+
+	ref := o.firestoreClient.Collection(templatesCollection).Doc(changes.ID)
+	doc, err := ref.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, changes.ID)
+		}
+		return nil, fmt.Errorf("failed to load template %s: %w", changes.ID, err)
+	}
+	var template ResearchTemplate
+	if err := doc.DataTo(&template); err != nil {
+		return nil, fmt.Errorf("failed to decode template %s: %w", changes.ID, err)
+	}
+	if template.Tenant != o.tenantID(ctx) {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotOwned, changes.ID)
+	}
+
+	if changes.Name != "" {
+		template.Name = changes.Name
+	}
+	if changes.Description != "" {
+		template.Description = changes.Description
+	}
+	if changes.Workflow != nil {
+		template.Workflow = changes.Workflow
+	}
+	if changes.Parameters != nil {
+		template.Parameters = changes.Parameters
+	}
+	if err := validateTemplateParameters(&template); err != nil {
+		return nil, err
+	}
+	if _, err := parseWorkflowSteps(template.Workflow); err != nil {
+		return nil, mcperrors.InvalidInput("invalid workflow: %w", err)
+	}
+	template.UpdatedAt = time.Now()
+
+	if _, err := ref.Set(ctx, &template); err != nil {
+		return nil, fmt.Errorf("failed to store template: %w", err)
+	}
+
+	o.mu.Lock()
+	o.templates[template.ID] = &template
+	o.mu.Unlock()
+	return &template, nil
+}
+
+// DeleteTemplate deletes a saved template the caller's tenant saved
+func (o *Orchestrator) DeleteTemplate(ctx context.Context, id string) error {
+	if err := o.checkTemplateWritable(id); err != nil {
+		return err
+	}
+
+	ref := o.firestoreClient.Collection(templatesCollection).Doc(id)
+	doc, err := ref.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("%w: %s", ErrTemplateNotFound, id)
+		}
+		return fmt.Errorf("failed to load template %s: %w", id, err)
+	}
+	var template ResearchTemplate
+	if err := doc.DataTo(&template); err != nil {
+		return fmt.Errorf("failed to decode template %s: %w", id, err)
+	}
+	if template.Tenant != o.tenantID(ctx) {
+		return fmt.Errorf("%w: %s", ErrTemplateNotOwned, id)
+	}
+	if _, err := ref.Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("%w: %s", ErrTemplateNotFound, id)
+		}
+		return fmt.Errorf("failed to delete template %s: %w", id, err)
+	}
+
+	o.mu.Lock()
+	delete(o.templates, id)
+	o.mu.Unlock()
+	return nil
+}
+
+// checkTemplateWritable rejects missing, malformed and built-in template IDs,
+// and any change in simulation mode
+func (o *Orchestrator) checkTemplateWritable(id string) error {
+	if err := o.requireCloud(); err != nil {
+		return err
+	}
+	if id == "" {
+		return mcperrors.InvalidInput("template id is required")
+	}
+	if !templateIDPattern.MatchString(id) {
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, id)
+	}
+	o.mu.RLock()
+	existing := o.templates[id]
+	o.mu.RUnlock()
+	if existing != nil && existing.Builtin {
+		return fmt.Errorf("%w: %s", ErrTemplateBuiltin, id)
+	}
+	return nil
+}
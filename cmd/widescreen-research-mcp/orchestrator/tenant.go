@@ -0,0 +1,272 @@
+package orchestrator
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"cloud.google.com/go/firestore"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+var (
+	// ErrAPIKeyRequired is returned when tenants are configured without a default and a call has no API key
+	ErrAPIKeyRequired = errors.New("api_key is required")
+
+	// ErrInvalidAPIKey is returned when an API key belongs to no tenant
+	ErrInvalidAPIKey = errors.New("invalid api_key")
+
+	// ErrTenantQuota is returned when starting a session would exceed its tenant's quota
+	ErrTenantQuota = errors.New("tenant quota exceeded")
+)
+
+// tenantIDPattern matches tenant IDs. They prefix Cloud Run service and Pub/Sub
+// topic names, so they are short, lowercase and start with a letter.
+var tenantIDPattern = regexp.MustCompile(`^[a-z]([a-z0-9-]{0,10}[a-z0-9])?$`)
+
+// Tenant is a team sharing the orchestrator. Its sessions, reports and
+// schedules are kept in Firestore collections prefixed with its ID, and its
+// drone services and Pub/Sub topics are named with it.
+type Tenant struct {
+	ID string `json:"id"`
+	// APIKeys identify the tenant's calls; $VAR references are expanded
+	APIKeys []string    `json:"api_keys,omitempty"`
+	Quota   TenantQuota `json:"quota"`
+}
+
+// TenantQuota limits what a tenant runs at once; zero values are unlimited
+type TenantQuota struct {
+	// MaxActiveSessions is how many of the tenant's sessions may run at once
+	MaxActiveSessions int `json:"max_active_sessions,omitempty"`
+	// MaxDrones is how many researchers the tenant's running sessions may have in total
+	MaxDrones int `json:"max_drones,omitempty"`
+	// MaxSessionCostUSD caps each session's budget, and is its budget when none is set
+	MaxSessionCostUSD float64 `json:"max_session_cost_usd,omitempty"`
+}
+
+// TenantRegistry maps API keys to the tenants sharing the orchestrator
+type TenantRegistry struct {
+	// defaultTenant serves calls without an API key; nil when they are rejected
+	defaultTenant *Tenant
+	tenants       map[string]*Tenant
+	keys          map[string]*Tenant
+}
+
+// LoadTenantConfig reads the tenants from the JSON list of Tenant at
+// WIDESCREEN_TENANTS_FILE. Calls without an API key belong to WIDESCREEN_TENANT;
+// when it is unset they use unprefixed names, or are rejected if a tenants
+// file is configured.
+func LoadTenantConfig() (*TenantRegistry, error) {
+	registry := &TenantRegistry{
+		tenants: make(map[string]*Tenant),
+		keys:    make(map[string]*Tenant),
+	}
+
+	path := getEnvOrDefault("WIDESCREEN_TENANTS_FILE", "")
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tenants file: %w", err)
+		}
+		var tenants []*Tenant
+		if err := json.Unmarshal(data, &tenants); err != nil {
+			return nil, fmt.Errorf("failed to parse tenants file %s: %w", path, err)
+		}
+		for _, tenant := range tenants {
+			if err := registry.add(tenant); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if id := getEnvOrDefault("WIDESCREEN_TENANT", ""); id != "" {
+		registry.defaultTenant = registry.tenants[id]
+		if registry.defaultTenant == nil {
+			registry.defaultTenant = &Tenant{ID: id}
+			if err := registry.add(registry.defaultTenant); err != nil {
+				return nil, fmt.Errorf("invalid WIDESCREEN_TENANT: %w", err)
+			}
+		}
+	} else if path == "" {
+		registry.defaultTenant = &Tenant{}
+	}
+	return registry, nil
+}
+
+// add validates a tenant and registers it with its API keys
+func (r *TenantRegistry) add(tenant *Tenant) error {
+	if !tenantIDPattern.MatchString(tenant.ID) {
+		return fmt.Errorf("invalid tenant ID %q: use up to 12 lowercase letters, digits and hyphens, starting with a letter", tenant.ID)
+	}
+	if r.tenants[tenant.ID] != nil {
+		return fmt.Errorf("tenant %s is defined more than once", tenant.ID)
+	}
+	if tenant.Quota.MaxActiveSessions < 0 || tenant.Quota.MaxDrones < 0 || tenant.Quota.MaxSessionCostUSD < 0 {
+		return fmt.Errorf("tenant %s has a negative quota", tenant.ID)
+	}
+	for _, key := range tenant.APIKeys {
+		key = os.ExpandEnv(key)
+		if key == "" {
+			return fmt.Errorf("tenant %s has an empty API key", tenant.ID)
+		}
+		if r.keys[key] != nil {
+			return fmt.Errorf("tenants %s and %s share an API key", r.keys[key].ID, tenant.ID)
+		}
+		r.keys[key] = tenant
+	}
+	r.tenants[tenant.ID] = tenant
+	return nil
+}
+
+// Resolve returns the tenant an API key belongs to, or the default tenant when the key is empty
+func (r *TenantRegistry) Resolve(apiKey string) (*Tenant, error) {
+	if apiKey == "" {
+		if r.defaultTenant == nil {
+			return nil, ErrAPIKeyRequired
+		}
+		return r.defaultTenant, nil
+	}
+	// Compare every key, so the time taken does not reveal how close a guess was
+	var match *Tenant
+	for key, tenant := range r.keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+			match = tenant
+		}
+	}
+	if match == nil {
+		return nil, ErrInvalidAPIKey
+	}
+	return match, nil
+}
+
+// IDs returns the IDs of every tenant, including the unprefixed namespace when
+// calls without an API key use it
+func (r *TenantRegistry) IDs() []string {
+	var ids []string
+	if r.defaultTenant != nil && r.defaultTenant.ID == "" {
+		ids = append(ids, "")
+	}
+	for id := range r.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// quota returns a tenant's quota; unknown tenants are unlimited
+func (r *TenantRegistry) quota(id string) TenantQuota {
+	if tenant := r.tenants[id]; tenant != nil {
+		return tenant.Quota
+	}
+	return TenantQuota{}
+}
+
+// ResolveTenant returns the ID of the tenant an API key belongs to, for use
+// with WithTenant. An empty key resolves to the default tenant.
+func (o *Orchestrator) ResolveTenant(apiKey string) (string, error) {
+	tenant, err := o.tenants.Resolve(apiKey)
+	if err != nil {
+		return "", err
+	}
+	return tenant.ID, nil
+}
+
+// tenantKey is the context key of the tenant a call belongs to
+type tenantKey struct{}
+
+// WithTenant returns a context for calls made on behalf of a tenant
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant WithTenant gave ctx, if any
+func TenantFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok
+}
+
+// tenantID returns the tenant of a call, or the default tenant when ctx names none
+func (o *Orchestrator) tenantID(ctx context.Context) string {
+	if id, ok := TenantFromContext(ctx); ok {
+		return id
+	}
+	return o.defaultTenantID()
+}
+
+// defaultTenantID returns the tenant of calls without an API key, which also
+// owns the warm pool and the CLI's sessions
+func (o *Orchestrator) defaultTenantID() string {
+	if o.tenants.defaultTenant != nil {
+		return o.tenants.defaultTenant.ID
+	}
+	return ""
+}
+
+// usesWarmPool reports whether a tenant's sessions may take warm drones. Warm
+// drones are deployed before they have a session, so they belong to the default tenant.
+func (o *Orchestrator) usesWarmPool(tenantID string) bool {
+	return tenantID == o.defaultTenantID()
+}
+
+// tenantResourceName prefixes the name of a Cloud Run service, Pub/Sub topic
+// or subscription with its tenant
+func tenantResourceName(tenantID, name string) string {
+	if tenantID == "" {
+		return name
+	}
+	return tenantID + "-" + name
+}
+
+// collection returns a tenant's Firestore collection
+func (o *Orchestrator) collection(tenantID, name string) *firestore.CollectionRef {
+	return o.firestoreClient.Collection(TenantCollection(tenantID, name))
+}
+
+// SessionsCollection returns the Firestore collection of the sessions of a call's tenant
+func (o *Orchestrator) SessionsCollection(ctx context.Context) string {
+	return TenantCollection(o.tenantID(ctx), sessionsCollection)
+}
+
+// TenantCollection returns the name of a tenant's Firestore collection
+func TenantCollection(tenantID, name string) string {
+	if tenantID == "" {
+		return name
+	}
+	return tenantID + "_" + name
+}
+
+// checkTenantQuota rejects a session that would take its tenant over quota,
+// and caps its budget at the tenant's per-session limit. The caller holds o.mu.
+func (o *Orchestrator) checkTenantQuota(config *schemas.ResearchConfig) error {
+	quota := o.tenants.quota(config.Tenant)
+
+	sessions, drones := 0, 0
+	for _, session := range o.activeSessions {
+		if session.Config.Tenant == config.Tenant {
+			sessions++
+			drones += session.Config.ResearcherCount
+		}
+	}
+	if quota.MaxActiveSessions > 0 && sessions >= quota.MaxActiveSessions {
+		return fmt.Errorf("%w: tenant %s already has %d active sessions", ErrTenantQuota, config.Tenant, sessions)
+	}
+	if quota.MaxDrones > 0 && drones+config.ResearcherCount > quota.MaxDrones {
+		return fmt.Errorf("%w: %d more researchers would give tenant %s %d drones, over its limit of %d",
+			ErrTenantQuota, config.ResearcherCount, config.Tenant, drones+config.ResearcherCount, quota.MaxDrones)
+	}
+	if quota.MaxSessionCostUSD > 0 {
+		if config.MaxCostUSD > quota.MaxSessionCostUSD {
+			return fmt.Errorf("%w: max_cost_usd %.2f is over tenant %s's limit of %.2f per session",
+				ErrTenantQuota, config.MaxCostUSD, config.Tenant, quota.MaxSessionCostUSD)
+		}
+		if config.MaxCostUSD == 0 {
+			config.MaxCostUSD = quota.MaxSessionCostUSD
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	runpb "cloud.google.com/go/run/apiv2/runpb"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	monitoring "google.golang.org/api/monitoring/v3"
+)
+
+const (
+	// sessionLabel is the Cloud Run service label tying a drone to its research session
+	sessionLabel = "session_id"
+
+	// tenantLabel is the Cloud Run service label naming the tenant a drone works for
+	tenantLabel = "tenant"
+
+	cpuAllocationMetric    = "run.googleapis.com/container/cpu/allocation_time"
+	memoryAllocationMetric = "run.googleapis.com/container/memory/allocation_time"
+)
+
+// invalidLabelChars matches characters not allowed in GCP label values
+var invalidLabelChars = regexp.MustCompile(`[^a-z0-9_-]`)
+
+// labelValue converts s to a valid GCP label value
+func labelValue(s string) string {
+	v := invalidLabelChars.ReplaceAllString(strings.ToLower(s), "_")
+	if len(v) > 63 {
+		v = v[:63]
+	}
+	return v
+}
+
+// droneLabels returns the labels for a drone's Cloud Run service. Warm drones
+// have no session until they are assigned one.
+func droneLabels(tenantID, sessionID string) map[string]string {
+	labels := map[string]string{"app": "widescreen-research-drone"}
+	if tenantID != "" {
+		labels[tenantLabel] = tenantID
+	}
+	if sessionID != "" {
+		labels[sessionLabel] = labelValue(sessionID)
+	}
+	return labels
+}
+
+// labelDroneSession tags an already deployed drone's service with the session it was assigned to
+func (o *Orchestrator) labelDroneSession(ctx context.Context, droneID, region, sessionID string) error {
+	name := fmt.Sprintf("projects/%s/locations/%s/services/%s", o.projectID, region, droneID)
+	service, err := o.runClient.GetService(ctx, &runpb.GetServiceRequest{Name: name})
+	if err != nil {
+		return fmt.Errorf("failed to get service: %w", err)
+	}
+
+	if service.Labels == nil {
+		service.Labels = make(map[string]string)
+	}
+	service.Labels[sessionLabel] = labelValue(sessionID)
+
+	operation, err := o.runClient.UpdateService(ctx, &runpb.UpdateServiceRequest{Service: service})
+	if err != nil {
+		return fmt.Errorf("failed to update service labels: %w", err)
+	}
+	if _, err := operation.Wait(ctx); err != nil {
+		return fmt.Errorf("failed to update service labels: %w", err)
+	}
+	return nil
+}
+
+// measureDroneCost returns the Cloud Run cost of the given drone services between
+// start and end, from the vCPU and memory allocation time Cloud Monitoring recorded
+func (o *Orchestrator) measureDroneCost(ctx context.Context, droneIDs []string, start, end time.Time) (float64, error) {
+	if len(droneIDs) == 0 {
+		return 0, nil
+	}
+	if o.monitoringService == nil {
+		return 0, fmt.Errorf("monitoring client not initialized")
+	}
+
+	vcpuSeconds, err := o.sumMetric(ctx, cpuAllocationMetric, droneIDs, start, end)
+	if err != nil {
+		return 0, err
+	}
+	gibSeconds, err := o.sumMetric(ctx, memoryAllocationMetric, droneIDs, start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	return vcpuSeconds*o.costRates.VCPUSecondUSD + gibSeconds*o.costRates.GiBSecondUSD, nil
+}
+
+// sumMetric totals a Cloud Run delta metric across the given services over a time window
+func (o *Orchestrator) sumMetric(ctx context.Context, metricType string, services []string, start, end time.Time) (float64, error) {
+	quoted := make([]string, len(services))
+	for i, service := range services {
+		quoted[i] = strconv.Quote(service)
+	}
+	filter := fmt.Sprintf(`metric.type = %q AND resource.type = "cloud_run_revision" AND resource.labels.service_name = one_of(%s)`,
+		metricType, strings.Join(quoted, ", "))
+
+	// A single alignment period covering the whole window yields one summed point per series
+	period := end.Sub(start).Truncate(time.Second)
+	if period < time.Minute {
+		period = time.Minute
+		start = end.Add(-period)
+	}
+
+	var total float64
+	err := o.monitoringService.Projects.TimeSeries.List("projects/"+o.projectID).
+		Filter(filter).
+		IntervalStartTime(start.UTC().Format(time.RFC3339)).
+		IntervalEndTime(end.UTC().Format(time.RFC3339)).
+		AggregationAlignmentPeriod(fmt.Sprintf("%ds", int64(period.Seconds()))).
+		AggregationPerSeriesAligner("ALIGN_SUM").
+		AggregationCrossSeriesReducer("REDUCE_SUM").
+		Pages(ctx, func(resp *monitoring.ListTimeSeriesResponse) error {
+			for _, series := range resp.TimeSeries {
+				for _, point := range series.Points {
+					if point.Value != nil && point.Value.DoubleValue != nil {
+						total += *point.Value.DoubleValue
+					}
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", metricType, err)
+	}
+	return total, nil
+}
+
+// applyMeasuredCost replaces the estimated drone runtime cost in metrics with the
+// usage Cloud Monitoring recorded for the session's drones, keeping the estimate
+// if usage cannot be read
+func (o *Orchestrator) applyMeasuredCost(ctx context.Context, session *ResearchSession, cost *schemas.CostBreakdown) {
+	// Simulated drones used nothing, so keep the estimate of what real ones would cost
+	if o.simulating(session.Config) {
+		return
+	}
+
+	o.mu.RLock()
+	droneIDs := make([]string, 0, len(session.Drones))
+	for id := range session.Drones {
+		droneIDs = append(droneIDs, id)
+	}
+	o.mu.RUnlock()
+
+	measured, err := o.measureDroneCost(ctx, droneIDs, session.StartTime, time.Now())
+	if err != nil {
+		log.Printf("Warning: using estimated drone cost for session %s: %v", session.Config.SessionID, err)
+		return
+	}
+
+	cost.DroneRuntimeUSD = measured
+	cost.TotalUSD = cost.DroneRuntimeUSD + cost.LLMTokensUSD + cost.ExaCallsUSD
+}
+
+// cpuCores parses a Cloud Run CPU limit such as "1000m" or "2" into cores
+func cpuCores(limit string) float64 {
+	if strings.HasSuffix(limit, "m") {
+		millis, err := strconv.ParseFloat(strings.TrimSuffix(limit, "m"), 64)
+		if err != nil {
+			return 0
+		}
+		return millis / 1000
+	}
+	cores, err := strconv.ParseFloat(limit, 64)
+	if err != nil {
+		return 0
+	}
+	return cores
+}
+
+// memoryGiB parses a Cloud Run memory limit such as "512Mi" or "2Gi" into GiB
+func memoryGiB(limit string) float64 {
+	units := map[string]float64{"Ki": 1.0 / (1 << 20), "Mi": 1.0 / 1024, "Gi": 1, "M": 1e6 / (1 << 30), "G": 1e9 / (1 << 30)}
+	for _, suffix := range []string{"Ki", "Mi", "Gi", "M", "G"} {
+		if strings.HasSuffix(limit, suffix) {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(limit, suffix), 64)
+			if err != nil {
+				return 0
+			}
+			return v * units[suffix]
+		}
+	}
+	bytes, err := strconv.ParseFloat(limit, 64)
+	if err != nil {
+		return 0
+	}
+	return bytes / (1 << 30)
+}
@@ -0,0 +1,439 @@
+package orchestrator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"cloud.google.com/go/firestore"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+)
+
+const (
+	// Kinds of indexed records
+	findingKind       = "finding"
+	websetItemKind    = "webset_item"
+	reportSectionKind = "report_section"
+
+	// maxRecordText is the most text of a record that is embedded and stored
+	maxRecordText = 8000
+	// maxExcerptLength is the most of a report section a search returns
+	maxExcerptLength = 500
+
+	// defaultSearchLimit and maxSearchLimit bound how many matches a search returns
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+)
+
+// FindingRecord is a finding, webset item or report section with its
+// embedding, as stored in a VectorStore
+type FindingRecord struct {
+	ID        string    `firestore:"id"`
+	SessionID string    `firestore:"session_id"`
+	Topic     string    `firestore:"topic"`
+	Kind      string    `firestore:"kind"`
+	Text      string    `firestore:"text"`
+	Sources   []string  `firestore:"sources"`
+	CreatedAt time.Time `firestore:"created_at"`
+	// ReportID and Title locate a report section
+	ReportID string `firestore:"report_id"`
+	Title    string `firestore:"title"`
+	// Vector is the unit-length embedding of Text
+	Vector []float64 `firestore:"-"`
+}
+
+// RecordMatch is a record found by a search, scored by its similarity to the query
+type RecordMatch struct {
+	Record FindingRecord
+	Score  float64
+}
+
+// VectorStore indexes the findings and reports of every session by embedding,
+// so they can be searched by meaning. Each tenant's records are kept apart.
+type VectorStore interface {
+	// Upsert stores records, replacing those with the same ID
+	Upsert(ctx context.Context, tenantID string, records []FindingRecord) error
+	// Search returns the records of the given kinds most similar to a
+	// unit-length vector, most similar first
+	Search(ctx context.Context, tenantID string, vector []float64, kinds []string, limit int) ([]RecordMatch, error)
+	// DeleteSession deletes a session's records of the given kinds and returns their IDs
+	DeleteSession(ctx context.Context, tenantID, sessionID string, kinds []string) ([]string, error)
+}
+
+// VectorStoreConfig selects where findings are indexed
+type VectorStoreConfig struct {
+	// Provider is "pgvector", "vertex" for Vertex AI Vector Search, "memory"
+	// for an index kept by this process, or "none" to disable indexing
+	Provider string
+	// pgvector settings
+	PostgresURL string
+	Table       string
+	// Vertex AI Vector Search settings. Vectors are upserted to Index and
+	// searched on DeployedIndexID of IndexEndpoint, served at EndpointHost.
+	Index           string
+	IndexEndpoint   string
+	DeployedIndexID string
+	EndpointHost    string
+	Region          string
+}
+
+// LoadVectorStoreConfig reads the vector store settings from the environment
+func LoadVectorStoreConfig(region string) VectorStoreConfig {
+	region = getEnvOrDefault("VERTEX_AI_REGION", region)
+	return VectorStoreConfig{
+		Provider:        getEnvOrDefault("VECTOR_STORE", "none"),
+		PostgresURL:     getEnvOrDefault("VECTOR_STORE_POSTGRES_URL", ""),
+		Table:           getEnvOrDefault("VECTOR_STORE_TABLE", "finding_vectors"),
+		Index:           getEnvOrDefault("VECTOR_SEARCH_INDEX", ""),
+		IndexEndpoint:   getEnvOrDefault("VECTOR_SEARCH_INDEX_ENDPOINT", ""),
+		DeployedIndexID: getEnvOrDefault("VECTOR_SEARCH_DEPLOYED_INDEX_ID", ""),
+		EndpointHost:    getEnvOrDefault("VECTOR_SEARCH_ENDPOINT_HOST", region+"-aiplatform.googleapis.com"),
+		Region:          region,
+	}
+}
+
+// NewVectorStore creates the configured vector store, or returns nil if
+// indexing is disabled. Vertex AI Vector Search keeps only vectors, so the
+// records themselves are stored in Firestore.
+func NewVectorStore(ctx context.Context, config VectorStoreConfig, firestoreClient *firestore.Client) (VectorStore, error) {
+	switch config.Provider {
+	case "none", "":
+		return nil, nil
+	case "memory":
+		return newMemoryVectorStore(), nil
+	case "pgvector":
+		if config.PostgresURL == "" {
+			return nil, fmt.Errorf("VECTOR_STORE_POSTGRES_URL is required for pgvector")
+		}
+		return newPgVectorStore(ctx, config)
+	case "vertex":
+		if config.Index == "" || config.IndexEndpoint == "" || config.DeployedIndexID == "" {
+			return nil, fmt.Errorf("VECTOR_SEARCH_INDEX, VECTOR_SEARCH_INDEX_ENDPOINT and VECTOR_SEARCH_DEPLOYED_INDEX_ID are required for Vertex AI Vector Search")
+		}
+		if firestoreClient == nil {
+			return nil, fmt.Errorf("Vertex AI Vector Search needs Firestore to store the indexed findings")
+		}
+		return newVertexVectorStore(ctx, config, firestoreClient)
+	}
+	return nil, fmt.Errorf("unknown VECTOR_STORE %q", config.Provider)
+}
+
+// indexSession adds the findings of a finished session, the items of the
+// websets its workflow built and the sections of its report to the vector store
+func (o *Orchestrator) indexSession(ctx context.Context, session *ResearchSession) {
+	if o.vectors == nil || o.embedder == nil {
+		return
+	}
+	records := sessionRecords(session)
+	if len(records) == 0 {
+		return
+	}
+
+	texts := make([]string, len(records))
+	for i, record := range records {
+		texts[i] = record.Text
+	}
+	vectors, err := o.embedder.Embed(ctx, texts)
+	if err != nil {
+		log.Printf("Warning: Failed to embed findings of session %s: %v", session.Config.SessionID, err)
+		return
+	}
+	for i := range records {
+		normalizeVector(vectors[i])
+		records[i].Vector = vectors[i]
+	}
+
+	if err := o.vectors.Upsert(ctx, session.Config.Tenant, records); err != nil {
+		log.Printf("Warning: Failed to index findings of session %s: %v", session.Config.SessionID, err)
+		return
+	}
+	log.Printf("Indexed %d findings and report sections of session %s", len(records), session.Config.SessionID)
+}
+
+// sessionRecords returns the records to index for a session: its merged
+// findings, the items of its websets, then its report's sections
+func sessionRecords(session *ResearchSession) []FindingRecord {
+	config := session.Config
+	now := time.Now()
+	var records []FindingRecord
+	seen := make(map[string]bool)
+	add := func(record FindingRecord) {
+		record.Text = truncateText(strings.TrimSpace(record.Text), maxRecordText)
+		record.ID = recordID(config.SessionID, record.Kind, record.Text)
+		if record.Text == "" || seen[record.ID] {
+			return
+		}
+		seen[record.ID] = true
+		record.SessionID = config.SessionID
+		record.Topic = config.Topic
+		record.CreatedAt = now
+		records = append(records, record)
+	}
+
+	findings, _ := dedupeFindings(session.Results)
+	for _, finding := range findings {
+		add(FindingRecord{Kind: findingKind, Text: finding.Claim, Sources: finding.Sources})
+	}
+
+	if session.Workflow != nil {
+		for _, step := range session.Workflow.Steps {
+			if step.Operation != StepWebsets {
+				continue
+			}
+			output, _ := step.Output.(map[string]interface{})
+			text, _ := output["output"].(string)
+			for _, item := range websetItems(text) {
+				var sources []string
+				if item.URL != "" {
+					sources = []string{normalizeURL(item.URL)}
+				}
+				add(FindingRecord{Kind: websetItemKind, Text: item.text(), Sources: sources})
+			}
+		}
+	}
+
+	if report := session.Report; report != nil {
+		add(FindingRecord{Kind: reportSectionKind, ReportID: report.ID, Title: "Executive Summary", Text: report.Executive})
+		for _, section := range report.Sections {
+			text := section.Content
+			if len(section.Insights) > 0 {
+				text += "\n\n" + strings.Join(section.Insights, "\n")
+			}
+			add(FindingRecord{Kind: reportSectionKind, ReportID: report.ID, Title: section.Title, Text: section.Title + "\n\n" + text})
+		}
+	}
+	return records
+}
+
+// matchesKind reports whether a record kind is one of kinds; no kinds match every kind
+func matchesKind(kinds []string, kind string) bool {
+	return len(kinds) == 0 || containsString(kinds, kind)
+}
+
+// truncateText shortens text to at most limit bytes, at a rune boundary
+func truncateText(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	for limit > 0 && !utf8.RuneStart(text[limit]) {
+		limit--
+	}
+	return text[:limit]
+}
+
+// recordID identifies a session's record by its text, so indexing a session again replaces its records
+func recordID(sessionID, kind, text string) string {
+	sum := sha256.Sum256([]byte(sessionID + "\x00" + kind + "\x00" + normalizeClaim(text)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// websetItem is an entity a websets server found
+type websetItem struct {
+	Title       string `json:"title"`
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	Description string `json:"description"`
+	Summary     string `json:"summary"`
+}
+
+// text describes the item for embedding
+func (i websetItem) text() string {
+	title := i.Title
+	if title == "" {
+		title = i.Name
+	}
+	description := i.Description
+	if description == "" {
+		description = i.Summary
+	}
+	if title == "" || description == "" {
+		return title + description
+	}
+	return title + ": " + description
+}
+
+// websetItems reads the items of a webset from a websets tool's output, a
+// JSON list of items or an object listing them under "items", "results" or
+// "data". Output in another shape has no items.
+func websetItems(output string) []websetItem {
+	var items []websetItem
+	if err := json.Unmarshal([]byte(output), &items); err == nil {
+		return items
+	}
+	var wrapped map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(output), &wrapped); err != nil {
+		return nil
+	}
+	for _, key := range []string{"items", "results", "data"} {
+		if err := json.Unmarshal(wrapped[key], &items); err == nil && len(items) > 0 {
+			return items
+		}
+	}
+	return nil
+}
+
+// SearchFindings returns the indexed findings and webset items of the call's
+// tenant closest in meaning to query, across every past session
+func (o *Orchestrator) SearchFindings(ctx context.Context, query string, limit int) (*schemas.FindingSearchResults, error) {
+	found, err := o.searchRecords(ctx, query, []string{findingKind, websetItemKind}, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]schemas.FindingMatch, len(found))
+	for i, match := range found {
+		matches[i] = schemas.FindingMatch{
+			ID:        match.Record.ID,
+			Kind:      match.Record.Kind,
+			Text:      match.Record.Text,
+			Sources:   match.Record.Sources,
+			SessionID: match.Record.SessionID,
+			Topic:     match.Record.Topic,
+			Score:     match.Score,
+			CreatedAt: match.Record.CreatedAt,
+		}
+	}
+	return &schemas.FindingSearchResults{Query: query, Matches: matches}, nil
+}
+
+// SearchReports returns the sections of the call's tenant's past reports
+// closest in meaning to query, so existing research on a topic can be found
+// before a new session is launched
+func (o *Orchestrator) SearchReports(ctx context.Context, query string, limit int) (*schemas.ReportSearchResults, error) {
+	found, err := o.searchRecords(ctx, query, []string{reportSectionKind}, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]schemas.ReportMatch, len(found))
+	for i, match := range found {
+		record := match.Record
+		// Sections are indexed as their title followed by their content
+		excerpt := strings.TrimSpace(strings.TrimPrefix(record.Text, record.Title))
+		if len(excerpt) > maxExcerptLength {
+			excerpt = truncateText(excerpt, maxExcerptLength) + "..."
+		}
+		matches[i] = schemas.ReportMatch{
+			ReportID:  record.ReportID,
+			SessionID: record.SessionID,
+			Topic:     record.Topic,
+			Section:   record.Title,
+			Excerpt:   excerpt,
+			Link:      o.notifier.reportLink(record.SessionID) + "#" + headingAnchor(record.Title),
+			Score:     match.Score,
+			CreatedAt: record.CreatedAt,
+		}
+	}
+	return &schemas.ReportSearchResults{Query: query, Matches: matches}, nil
+}
+
+// searchRecords embeds query and returns the indexed records of kinds closest to it
+func (o *Orchestrator) searchRecords(ctx context.Context, query string, kinds []string, limit int) ([]RecordMatch, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, mcperrors.InvalidInput("query is required")
+	}
+	if o.vectors == nil || o.embedder == nil {
+		return nil, mcperrors.New(mcperrors.CategoryUnavailable, "findings are not indexed; set VECTOR_STORE and EMBEDDING_PROVIDER")
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+
+	vectors, err := o.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	normalizeVector(vectors[0])
+	matches, err := o.vectors.Search(ctx, o.tenantID(ctx), vectors[0], kinds, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search the vector store: %w", err)
+	}
+	return matches, nil
+}
+
+// headingAnchor returns the anchor Markdown renderers give a heading, such as
+// executive-summary for "Executive Summary"
+func headingAnchor(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(title)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// memoryVectorStore keeps records in this process and searches them exhaustively.
+// It suits development and simulation; records are lost when the process exits.
+type memoryVectorStore struct {
+	mu      sync.RWMutex
+	records map[string]map[string]FindingRecord // by tenant, then ID
+}
+
+func newMemoryVectorStore() *memoryVectorStore {
+	return &memoryVectorStore{records: make(map[string]map[string]FindingRecord)}
+}
+
+// Upsert implements VectorStore
+func (s *memoryVectorStore) Upsert(ctx context.Context, tenantID string, records []FindingRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tenant, ok := s.records[tenantID]
+	if !ok {
+		tenant = make(map[string]FindingRecord)
+		s.records[tenantID] = tenant
+	}
+	for _, record := range records {
+		tenant[record.ID] = record
+	}
+	return nil
+}
+
+// Search implements VectorStore
+func (s *memoryVectorStore) Search(ctx context.Context, tenantID string, vector []float64, kinds []string, limit int) ([]RecordMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var matches []RecordMatch
+	for _, record := range s.records[tenantID] {
+		if len(record.Vector) != len(vector) || !matchesKind(kinds, record.Kind) {
+			continue
+		}
+		matches = append(matches, RecordMatch{Record: record, Score: dot(record.Vector, vector)})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// DeleteSession implements VectorStore
+func (s *memoryVectorStore) DeleteSession(ctx context.Context, tenantID, sessionID string, kinds []string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var deleted []string
+	for id, record := range s.records[tenantID] {
+		if record.SessionID == sessionID && matchesKind(kinds, record.Kind) {
+			delete(s.records[tenantID], id)
+			deleted = append(deleted, id)
+		}
+	}
+	sort.Strings(deleted)
+	return deleted, nil
+}
@@ -0,0 +1,190 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+)
+
+// findingVectorsCollection holds the records indexed in Vertex AI Vector
+// Search, which keeps only their vectors, keyed by datapoint ID
+const findingVectorsCollection = "finding_vectors"
+
+// Restrict namespaces: tenant keeps each tenant's datapoints apart, and kind
+// lets a search ask for findings or report sections only
+const (
+	tenantNamespace = "tenant"
+	kindNamespace   = "kind"
+)
+
+// vertexVectorStore indexes records in a Vertex AI Vector Search index with
+// streaming updates, and stores the records in each tenant's Firestore collection
+type vertexVectorStore struct {
+	config    VectorStoreConfig
+	client    *http.Client
+	firestore *firestore.Client
+}
+
+func newVertexVectorStore(ctx context.Context, config VectorStoreConfig, firestoreClient *firestore.Client) (*vertexVectorStore, error) {
+	client, _, err := htransport.NewClient(ctx, option.WithScopes("https://www.googleapis.com/auth/cloud-platform"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Vertex AI client: %w", err)
+	}
+	client.Timeout = 30 * time.Second
+	return &vertexVectorStore{config: config, client: client, firestore: firestoreClient}, nil
+}
+
+// tenantRestrict returns the restrict value of a tenant; the unprefixed tenant has an empty ID
+func tenantRestrict(tenantID string) string {
+	if tenantID == "" {
+		return "default"
+	}
+	return tenantID
+}
+
+// Upsert implements VectorStore
+func (s *vertexVectorStore) Upsert(ctx context.Context, tenantID string, records []FindingRecord) error {
+	// Records are stored first, so every datapoint a search returns can be read
+	collection := s.firestore.Collection(TenantCollection(tenantID, findingVectorsCollection))
+	writer := s.firestore.BulkWriter(ctx)
+	for _, record := range records {
+		if _, err := writer.Set(collection.Doc(record.ID), record); err != nil {
+			writer.End()
+			return fmt.Errorf("failed to store record %s: %w", record.ID, err)
+		}
+	}
+	writer.End()
+
+	url := fmt.Sprintf("https://%s-aiplatform.googleapis.com/v1/%s:upsertDatapoints", s.config.Region, s.config.Index)
+	return upsertInBatches(records, func(batch []FindingRecord) error {
+		datapoints := make([]map[string]interface{}, len(batch))
+		for i, record := range batch {
+			datapoints[i] = map[string]interface{}{
+				"datapointId":   record.ID,
+				"featureVector": record.Vector,
+				"restricts": []map[string]interface{}{
+					{"namespace": tenantNamespace, "allowList": []string{tenantRestrict(tenantID)}},
+					{"namespace": kindNamespace, "allowList": []string{record.Kind}},
+				},
+			}
+		}
+		var response struct{}
+		if err := postJSON(ctx, s.client, url, nil, map[string]interface{}{"datapoints": datapoints}, &response); err != nil {
+			return fmt.Errorf("failed to upsert datapoints: %w", err)
+		}
+		return nil
+	})
+}
+
+// Search implements VectorStore
+func (s *vertexVectorStore) Search(ctx context.Context, tenantID string, vector []float64, kinds []string, limit int) ([]RecordMatch, error) {
+	restricts := []map[string]interface{}{
+		{"namespace": tenantNamespace, "allowList": []string{tenantRestrict(tenantID)}},
+	}
+	if len(kinds) > 0 {
+		restricts = append(restricts, map[string]interface{}{"namespace": kindNamespace, "allowList": kinds})
+	}
+	url := fmt.Sprintf("https://%s/v1/%s:findNeighbors", s.config.EndpointHost, s.config.IndexEndpoint)
+	var response struct {
+		NearestNeighbors []struct {
+			Neighbors []struct {
+				Datapoint struct {
+					DatapointID string `json:"datapointId"`
+				} `json:"datapoint"`
+				Distance float64 `json:"distance"`
+			} `json:"neighbors"`
+		} `json:"nearestNeighbors"`
+	}
+	err := postJSON(ctx, s.client, url, nil, map[string]interface{}{
+		"deployedIndexId": s.config.DeployedIndexID,
+		"queries": []map[string]interface{}{{
+			"datapoint": map[string]interface{}{
+				"featureVector": vector,
+				"restricts":     restricts,
+			},
+			"neighborCount": limit,
+		}},
+	}, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find neighbors: %w", err)
+	}
+	if len(response.NearestNeighbors) == 0 || len(response.NearestNeighbors[0].Neighbors) == 0 {
+		return nil, nil
+	}
+
+	neighbors := response.NearestNeighbors[0].Neighbors
+	collection := s.firestore.Collection(TenantCollection(tenantID, findingVectorsCollection))
+	refs := make([]*firestore.DocumentRef, len(neighbors))
+	for i, neighbor := range neighbors {
+		refs[i] = collection.Doc(neighbor.Datapoint.DatapointID)
+	}
+	docs, err := s.firestore.GetAll(ctx, refs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read matched records: %w", err)
+	}
+
+	// Neighbors come closest first. With unit-length vectors and a dot product
+	// or cosine index, the distance is the similarity.
+	matches := make([]RecordMatch, 0, len(docs))
+	for i, doc := range docs {
+		if !doc.Exists() {
+			continue // deleted since it was indexed
+		}
+		var record FindingRecord
+		if err := doc.DataTo(&record); err != nil || !matchesKind(kinds, record.Kind) {
+			continue
+		}
+		matches = append(matches, RecordMatch{Record: record, Score: neighbors[i].Distance})
+	}
+	return matches, nil
+}
+
+// DeleteSession implements VectorStore
+func (s *vertexVectorStore) DeleteSession(ctx context.Context, tenantID, sessionID string, kinds []string) ([]string, error) {
+	query := s.firestore.Collection(TenantCollection(tenantID, findingVectorsCollection)).Where("session_id", "==", sessionID)
+	if len(kinds) > 0 {
+		query = query.Where("kind", "in", kinds)
+	}
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records of session %s: %w", sessionID, err)
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.Ref.ID
+	}
+
+	url := fmt.Sprintf("https://%s-aiplatform.googleapis.com/v1/%s:removeDatapoints", s.config.Region, s.config.Index)
+	var response struct{}
+	if err := postJSON(ctx, s.client, url, nil, map[string]interface{}{"datapointIds": ids}, &response); err != nil {
+		return nil, fmt.Errorf("failed to remove datapoints: %w", err)
+	}
+
+	writer := s.firestore.BulkWriter(ctx)
+	for _, doc := range docs {
+		if _, err := writer.Delete(doc.Ref); err != nil {
+			writer.End()
+			return ids, fmt.Errorf("failed to delete record %s: %w", doc.Ref.ID, err)
+		}
+	}
+	writer.End()
+	return ids, nil
+}
+
+// upsertInBatches calls send with records a batch of embeddingBatchSize at a time
+func upsertInBatches(records []FindingRecord, send func(batch []FindingRecord) error) error {
+	for start := 0; start < len(records); start += embeddingBatchSize {
+		if err := send(records[start:min(start+embeddingBatchSize, len(records))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+const (
+	// warmPoolCheckInterval is how often the pool reaps expired drones and replenishes
+	warmPoolCheckInterval = 30 * time.Second
+	// warmDroneTimeoutMinutes is the request timeout for drones deployed ahead of a session
+	warmDroneTimeoutMinutes = 60
+	// warmPoolMaxBackoff caps how long a priority level waits to deploy again after failures
+	warmPoolMaxBackoff = 30 * time.Minute
+)
+
+// WarmPoolConfig configures the pool of pre-provisioned drones
+type WarmPoolConfig struct {
+	// Size is the number of idle drones kept per priority level; 0 disables the pool
+	Size int
+	// MaxSize caps the idle plus deploying drones across all priority levels
+	MaxSize int
+	// IdleExpiry is how long the pool stays warm without demand before idle drones are removed
+	IdleExpiry time.Duration
+	// Priorities are the research priority levels to keep warm drones for
+	Priorities []string
+}
+
+// LoadWarmPoolConfig reads the warm pool configuration from the environment
+func LoadWarmPoolConfig() WarmPoolConfig {
+	config := WarmPoolConfig{
+		IdleExpiry: 30 * time.Minute,
+		Priorities: []string{"normal"},
+	}
+
+	if size, err := strconv.Atoi(getEnvOrDefault("WARM_POOL_SIZE", "0")); err == nil && size > 0 {
+		config.Size = size
+	}
+	if maxSize, err := strconv.Atoi(getEnvOrDefault("WARM_POOL_MAX_SIZE", "")); err == nil && maxSize > 0 {
+		config.MaxSize = maxSize
+	}
+	if expiry, err := time.ParseDuration(getEnvOrDefault("WARM_POOL_IDLE_EXPIRY", "")); err == nil && expiry > 0 {
+		config.IdleExpiry = expiry
+	}
+	if priorities := getEnvOrDefault("WARM_POOL_PRIORITIES", ""); priorities != "" {
+		config.Priorities = nil
+		for _, priority := range strings.Split(priorities, ",") {
+			if priority = strings.TrimSpace(priority); priority != "" {
+				config.Priorities = append(config.Priorities, priority)
+			}
+		}
+	}
+
+	if config.MaxSize == 0 {
+		config.MaxSize = config.Size * len(config.Priorities)
+	}
+
+	return config
+}
+
+// DronePool keeps idle drones deployed so sessions can skip Cloud Run cold starts
+type DronePool struct {
+	orchestrator *Orchestrator
+	config       WarmPoolConfig
+
+	idle       map[string][]*DroneInfo
+	deploying  map[string]int
+	lastDemand time.Time
+	// failures counts each priority level's failed deploys in a row, and
+	// retryAt is when it may deploy again
+	failures map[string]int
+	retryAt  map[string]time.Time
+	mu       sync.Mutex
+	// deploys tracks deployWarmDrone calls, which Shutdown waits for
+	deploys sync.WaitGroup
+
+	replenish chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewDronePool creates a warm pool for the orchestrator
+func NewDronePool(o *Orchestrator, config WarmPoolConfig) *DronePool {
+	return &DronePool{
+		orchestrator: o,
+		config:       config,
+		idle:         make(map[string][]*DroneInfo),
+		deploying:    make(map[string]int),
+		failures:     make(map[string]int),
+		retryAt:      make(map[string]time.Time),
+		replenish:    make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Enabled reports whether the pool keeps any drones warm
+func (p *DronePool) Enabled() bool {
+	return p.config.Size > 0
+}
+
+// Start begins filling the pool and maintaining it in the background
+func (p *DronePool) Start() {
+	if !p.Enabled() {
+		close(p.done)
+		return
+	}
+
+	p.mu.Lock()
+	p.lastDemand = time.Now()
+	p.mu.Unlock()
+
+	log.Printf("Starting warm drone pool: %d per priority %v, max %d, idle expiry %v",
+		p.config.Size, p.config.Priorities, p.config.MaxSize, p.config.IdleExpiry)
+
+	go p.run()
+	p.signalReplenish()
+}
+
+// Acquire takes up to count idle drones for the given priority level. The
+// returned drones are removed from the pool and the pool is refilled in the background.
+func (p *DronePool) Acquire(priority string, count int) []*DroneInfo {
+	if !p.Enabled() {
+		return nil
+	}
+
+	p.mu.Lock()
+	p.lastDemand = time.Now()
+	idle := p.idle[priority]
+	if count > len(idle) {
+		count = len(idle)
+	}
+	acquired := idle[:count]
+	p.idle[priority] = append([]*DroneInfo(nil), idle[count:]...)
+	p.mu.Unlock()
+
+	p.signalReplenish()
+	return acquired
+}
+
+// Stats returns the number of idle drones per priority level
+func (p *DronePool) Stats() map[string]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make(map[string]int, len(p.idle))
+	for priority, drones := range p.idle {
+		stats[priority] = len(drones)
+	}
+	return stats
+}
+
+// Shutdown stops maintenance, waits for drones still deploying, and deletes
+// the idle drones so they stop billing
+func (p *DronePool) Shutdown(ctx context.Context) {
+	if !p.Enabled() {
+		return
+	}
+
+	close(p.stop)
+	<-p.done
+
+	deployed := make(chan struct{})
+	go func() {
+		p.deploys.Wait()
+		close(deployed)
+	}()
+	select {
+	case <-deployed:
+	case <-ctx.Done():
+		log.Printf("Warning: warm drones still deploying at shutdown will not be deleted: %v", ctx.Err())
+	}
+
+	p.mu.Lock()
+	var drones []*DroneInfo
+	for _, idle := range p.idle {
+		drones = append(drones, idle...)
+	}
+	p.idle = make(map[string][]*DroneInfo)
+	p.mu.Unlock()
+
+	for _, drone := range drones {
+		if err := p.orchestrator.deleteDroneService(ctx, drone.ID, drone.Region); err != nil {
+			log.Printf("Failed to delete warm drone %s: %v", drone.ID, err)
+		}
+	}
+}
+
+// signalReplenish wakes the maintenance loop without blocking
+func (p *DronePool) signalReplenish() {
+	select {
+	case p.replenish <- struct{}{}:
+	default:
+	}
+}
+
+// run reaps expired drones and tops the pool back up until stopped
+func (p *DronePool) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(warmPoolCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		case <-p.replenish:
+		}
+
+		p.reapExpired()
+		p.fill()
+	}
+}
+
+// reapExpired removes every idle drone once the pool has gone IdleExpiry without demand
+func (p *DronePool) reapExpired() {
+	p.mu.Lock()
+	if time.Since(p.lastDemand) < p.config.IdleExpiry {
+		p.mu.Unlock()
+		return
+	}
+	var expired []*DroneInfo
+	for priority, idle := range p.idle {
+		expired = append(expired, idle...)
+		delete(p.idle, priority)
+	}
+	p.mu.Unlock()
+
+	if len(expired) == 0 {
+		return
+	}
+
+	log.Printf("Warm drone pool idle for %v, removing %d drones", p.config.IdleExpiry, len(expired))
+	for _, drone := range expired {
+		if err := p.orchestrator.deleteDroneService(context.Background(), drone.ID, drone.Region); err != nil {
+			log.Printf("Failed to delete warm drone %s: %v", drone.ID, err)
+		}
+	}
+}
+
+// deployBackoff is how long a priority level waits to deploy again after
+// failures deploys in a row: one check interval, doubling up to warmPoolMaxBackoff
+func deployBackoff(failures int) time.Duration {
+	backoff := warmPoolCheckInterval
+	for i := 1; i < failures && backoff < warmPoolMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > warmPoolMaxBackoff {
+		backoff = warmPoolMaxBackoff
+	}
+	return backoff
+}
+
+// fill starts deployments until every priority level has Size drones idle or
+// deploying, skipping levels backing off after failed deploys
+func (p *DronePool) fill() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// A pool nobody has used recently stays empty until the next Acquire
+	if time.Since(p.lastDemand) >= p.config.IdleExpiry {
+		return
+	}
+
+	total := 0
+	for _, priority := range p.config.Priorities {
+		total += len(p.idle[priority]) + p.deploying[priority]
+	}
+
+	now := time.Now()
+	for _, priority := range p.config.Priorities {
+		if now.Before(p.retryAt[priority]) {
+			continue
+		}
+		missing := p.config.Size - len(p.idle[priority]) - p.deploying[priority]
+		for i := 0; i < missing && total < p.config.MaxSize; i++ {
+			p.deploying[priority]++
+			total++
+			p.deploys.Add(1)
+			go p.deployWarmDrone(priority)
+		}
+	}
+}
+
+// deployWarmDrone deploys one session-less drone and adds it to the pool
+func (p *DronePool) deployWarmDrone(priority string) {
+	defer p.deploys.Done()
+	tenantID := p.orchestrator.defaultTenantID()
+	droneID := tenantResourceName(tenantID, fmt.Sprintf("drone-warm-%s", uuid.New().String()[:8]))
+	config := &schemas.ResearchConfig{
+		PriorityLevel:  priority,
+		TimeoutMinutes: warmDroneTimeoutMinutes,
+		Tenant:         tenantID,
+	}
+
+	serviceURL, err := p.orchestrator.deployDrone(context.Background(), droneID, p.orchestrator.region, config)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.deploying[priority]--
+
+	if err != nil {
+		p.failures[priority]++
+		backoff := deployBackoff(p.failures[priority])
+		p.retryAt[priority] = time.Now().Add(backoff)
+		log.Printf("Warning: failed to deploy warm drone %s, retrying %s priority in %v: %v", droneID, priority, backoff, err)
+		return
+	}
+	delete(p.failures, priority)
+	delete(p.retryAt, priority)
+
+	// A drone finishing after Shutdown stopped the pool is deleted with the idle ones
+	p.idle[priority] = append(p.idle[priority], &DroneInfo{
+		ID:          droneID,
+		ServiceURL:  serviceURL,
+		Region:      p.orchestrator.region,
+		Status:      "warm",
+		StartTime:   time.Now(),
+		LastCheckin: time.Now(),
+	})
+	log.Printf("Warm drone %s ready (%s priority)", droneID, priority)
+}
@@ -0,0 +1,70 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeployBackoff(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, warmPoolCheckInterval},
+		{2, 2 * warmPoolCheckInterval},
+		{3, 4 * warmPoolCheckInterval},
+		{6, 32 * warmPoolCheckInterval},
+		{7, warmPoolMaxBackoff},
+		{100, warmPoolMaxBackoff},
+	}
+	for _, tt := range tests {
+		if got := deployBackoff(tt.failures); got != tt.want {
+			t.Errorf("deployBackoff(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestDronePoolFillSkipsPrioritiesBackingOff(t *testing.T) {
+	p := NewDronePool(nil, WarmPoolConfig{Size: 2, MaxSize: 2, IdleExpiry: time.Hour, Priorities: []string{"normal"}})
+	p.lastDemand = time.Now()
+	p.retryAt["normal"] = time.Now().Add(time.Minute)
+
+	p.fill()
+	if n := p.deploying["normal"]; n != 0 {
+		t.Errorf("fill() started %d deploys for a priority backing off, want 0", n)
+	}
+}
+
+func TestDronePoolShutdownWaitsForDeploys(t *testing.T) {
+	p := NewDronePool(nil, WarmPoolConfig{Size: 1, MaxSize: 1, IdleExpiry: time.Hour, Priorities: []string{"normal"}})
+	close(p.done)
+
+	// A drone still deploying when the pool shuts down
+	p.deploys.Add(1)
+	done := make(chan struct{})
+	go func() {
+		p.Shutdown(context.Background())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Shutdown() returned while a drone was still deploying")
+	case <-time.After(50 * time.Millisecond):
+	}
+	p.deploys.Done()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown() did not return after the deploy finished")
+	}
+
+	// Shutdown gives up on deploys when its context ends
+	p = NewDronePool(nil, WarmPoolConfig{Size: 1, MaxSize: 1, IdleExpiry: time.Hour, Priorities: []string{"normal"}})
+	close(p.done)
+	p.deploys.Add(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	p.Shutdown(ctx)
+}
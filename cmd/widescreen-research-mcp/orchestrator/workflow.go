@@ -0,0 +1,769 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// Operations a workflow step can run
+const (
+	// StepDroneResearch splits the step's subject into sub-queries and researches them on the session's drones
+	StepDroneResearch = "drone_research"
+	// StepWebsets builds an Exa webset for the step's subject through the websets MCP server
+	StepWebsets = "websets"
+	// StepAnalysis analyzes the drone results of the steps it depends on
+	StepAnalysis = "analysis"
+	// StepSequentialThinking reasons step by step about the step's subject and earlier results
+	StepSequentialThinking = "sequential_thinking"
+	// StepMCPTool calls any tool on a connected MCP server
+	StepMCPTool = "mcp_tool"
+)
+
+// What happens when a workflow step fails
+const (
+	// FailWorkflow stops the workflow, failing the session
+	FailWorkflow = "fail"
+	// ContinueOnFailure runs the step's dependents as if it had completed
+	ContinueOnFailure = "continue"
+	// SkipDependents skips every step that depends on the failed one and runs the rest
+	SkipDependents = "skip_dependents"
+)
+
+// stepOperationsByName maps the step names of the built-in templates that are
+// not drone research to their operations
+var stepOperationsByName = map[string]string{
+	"methodology_analysis": StepSequentialThinking,
+	"peer_review":          StepAnalysis,
+}
+
+// WorkflowStep is one step of a template workflow. Steps may be given as just
+// a name, which chooses the operation, or as an object:
+//
+//	{"name": "enrich", "operation": "drone_research", "depends_on": ["collect"], "drones": 3, "on_failure": "skip_dependents"}
+type WorkflowStep struct {
+	Name      string
+	Operation string
+	Params    map[string]interface{}
+	// DependsOn names the steps that must finish first and whose outputs the step builds on
+	DependsOn []string
+	// Drones caps how many drones a drone research step uses; 0 uses all of them
+	Drones int
+	// OnFailure is FailWorkflow, ContinueOnFailure or SkipDependents
+	OnFailure string
+}
+
+// stepExecutor runs one workflow step and returns its output
+type stepExecutor func(ctx context.Context, session *ResearchSession, step WorkflowStep, state *WorkflowState) (interface{}, error)
+
+// WorkflowState is the intermediate state of a running workflow: the steps
+// finished so far and their outputs, by step name
+type WorkflowState struct {
+	Steps   []schemas.WorkflowStepResult
+	Outputs map[string]interface{}
+
+	// results holds the drone results of each drone research step
+	results map[string][]schemas.DroneResult
+	// ancestors holds the steps each step depends on, directly or not
+	ancestors map[string]map[string]bool
+	mu        sync.RWMutex
+}
+
+// newWorkflowState creates the state of a workflow about to run
+func newWorkflowState(ancestors map[string]map[string]bool) *WorkflowState {
+	return &WorkflowState{
+		Outputs:   make(map[string]interface{}),
+		results:   make(map[string][]schemas.DroneResult),
+		ancestors: ancestors,
+	}
+}
+
+// record adds a finished step, and its output if it completed
+func (s *WorkflowState) record(result schemas.WorkflowStepResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Steps = append(s.Steps, result)
+	if result.Status == "completed" {
+		s.Outputs[result.Name] = result.Output
+	}
+}
+
+// summary describes the finished steps the named step depends on, so it can
+// build on their outputs
+func (s *WorkflowState) summary(name string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var lines []string
+	for _, step := range s.Steps {
+		if !s.ancestors[name][step.Name] {
+			continue
+		}
+		line := fmt.Sprintf("%s (%s): %s", step.Name, step.Operation, step.Status)
+		if detail := outputSummary(step.Output); detail != "" {
+			line += " - " + detail
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ancestorResults returns the drone results collected by the steps the named step depends on
+func (s *WorkflowState) ancestorResults(name string) []schemas.DroneResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var results []schemas.DroneResult
+	for _, step := range s.Steps {
+		if s.ancestors[name][step.Name] {
+			results = append(results, s.results[step.Name]...)
+		}
+	}
+	return results
+}
+
+// outputSummary condenses a step's output to a line
+func outputSummary(output interface{}) string {
+	switch v := output.(type) {
+	case *DataAnalysis:
+		return strings.Join(v.TopInsights, "; ")
+	case *schemas.SequentialThinkingResponse:
+		return v.Solution
+	case map[string]interface{}:
+		if text, ok := v["output"].(string); ok {
+			const maxLen = 500
+			if runes := []rune(strings.TrimSpace(text)); len(runes) > maxLen {
+				return string(runes[:maxLen]) + "..."
+			}
+			return strings.TrimSpace(text)
+		}
+		if results, ok := v["results"].([]map[string]interface{}); ok {
+			return fmt.Sprintf("%d drone results for %q", len(results), v["subject"])
+		}
+	}
+	return ""
+}
+
+// parseWorkflowSteps reads the "steps" of a workflow. It returns no steps if
+// the workflow has none, and an error if a step is malformed, has an unknown
+// operation or failure policy, or the dependencies form a cycle. When no step
+// declares depends_on, each step depends on the one before it.
+func parseWorkflowSteps(workflow map[string]interface{}) ([]WorkflowStep, error) {
+	var raw []interface{}
+	switch v := workflow["steps"].(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		raw = v
+	case []string:
+		for _, name := range v {
+			raw = append(raw, name)
+		}
+	default:
+		return nil, fmt.Errorf("workflow steps must be a list")
+	}
+
+	steps := make([]WorkflowStep, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	dag := false
+	for i, item := range raw {
+		var step WorkflowStep
+		switch v := item.(type) {
+		case string:
+			step.Name = v
+		case map[string]interface{}:
+			step.Name, _ = v["name"].(string)
+			step.Operation, _ = v["operation"].(string)
+			step.Params, _ = v["params"].(map[string]interface{})
+			step.OnFailure, _ = v["on_failure"].(string)
+			if continueOnError, _ := v["continue_on_error"].(bool); continueOnError && step.OnFailure == "" {
+				step.OnFailure = ContinueOnFailure
+			}
+
+			dependsOn, ok := stepNames(v["depends_on"])
+			if !ok {
+				return nil, fmt.Errorf("workflow step %d: depends_on must be a step name or a list of them", i+1)
+			}
+			step.DependsOn = dependsOn
+			if v["depends_on"] != nil {
+				dag = true
+			}
+
+			switch drones := v["drones"].(type) {
+			case nil:
+			case float64:
+				step.Drones = int(drones)
+			case int:
+				step.Drones = drones
+			case int64:
+				step.Drones = int(drones)
+			default:
+				return nil, fmt.Errorf("workflow step %d: drones must be a number", i+1)
+			}
+			if step.Drones < 0 {
+				return nil, fmt.Errorf("workflow step %d: drones cannot be negative", i+1)
+			}
+		default:
+			return nil, fmt.Errorf("workflow step %d must be a name or an object", i+1)
+		}
+
+		if step.Name == "" {
+			return nil, fmt.Errorf("workflow step %d has no name", i+1)
+		}
+		if seen[step.Name] {
+			return nil, fmt.Errorf("workflow step %s appears twice", step.Name)
+		}
+		seen[step.Name] = true
+
+		if step.Operation == "" {
+			step.Operation = stepOperationsByName[step.Name]
+		}
+		if step.Operation == "" {
+			step.Operation = StepDroneResearch
+		}
+		switch step.Operation {
+		case StepDroneResearch, StepWebsets, StepAnalysis, StepSequentialThinking, StepMCPTool:
+		default:
+			return nil, fmt.Errorf("workflow step %s has unknown operation %q", step.Name, step.Operation)
+		}
+		switch step.OnFailure {
+		case "":
+			step.OnFailure = FailWorkflow
+		case FailWorkflow, ContinueOnFailure, SkipDependents:
+		default:
+			return nil, fmt.Errorf("workflow step %s has unknown on_failure %q", step.Name, step.OnFailure)
+		}
+		if step.Params == nil {
+			step.Params = map[string]interface{}{}
+		}
+		steps = append(steps, step)
+	}
+
+	if !dag {
+		for i := 1; i < len(steps); i++ {
+			steps[i].DependsOn = []string{steps[i-1].Name}
+		}
+		return steps, nil
+	}
+	for _, step := range steps {
+		for _, dependency := range step.DependsOn {
+			if !seen[dependency] {
+				return nil, fmt.Errorf("workflow step %s depends on unknown step %s", step.Name, dependency)
+			}
+		}
+	}
+	if _, err := workflowAncestors(steps); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+// stepNames reads a depends_on value: a step name or a list of them
+func stepNames(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, true
+	case string:
+		return []string{v}, true
+	case []string:
+		return v, true
+	case []interface{}:
+		names := make([]string, 0, len(v))
+		for _, item := range v {
+			name, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			names = append(names, name)
+		}
+		return names, true
+	}
+	return nil, false
+}
+
+// workflowAncestors returns the steps each step depends on, directly or
+// through other steps, or an error if the dependencies form a cycle
+func workflowAncestors(steps []WorkflowStep) (map[string]map[string]bool, error) {
+	byName := make(map[string]WorkflowStep, len(steps))
+	for _, step := range steps {
+		byName[step.Name] = step
+	}
+
+	ancestors := make(map[string]map[string]bool, len(steps))
+	visiting := make(map[string]bool)
+	var visit func(name string) error
+	visit = func(name string) error {
+		if ancestors[name] != nil {
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("workflow steps form a cycle through %s", name)
+		}
+		visiting[name] = true
+
+		found := make(map[string]bool)
+		for _, dependency := range byName[name].DependsOn {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+			found[dependency] = true
+			for ancestor := range ancestors[dependency] {
+				found[ancestor] = true
+			}
+		}
+		visiting[name] = false
+		ancestors[name] = found
+		return nil
+	}
+
+	for _, step := range steps {
+		if err := visit(step.Name); err != nil {
+			return nil, err
+		}
+	}
+	return ancestors, nil
+}
+
+// stepExecutors returns the executor of each step operation
+func (o *Orchestrator) stepExecutors() map[string]stepExecutor {
+	return map[string]stepExecutor{
+		StepDroneResearch:      o.runDroneResearchStep,
+		StepWebsets:            o.runWebsetsStep,
+		StepAnalysis:           o.runAnalysisStep,
+		StepSequentialThinking: o.runSequentialThinkingStep,
+		StepMCPTool:            o.runMCPToolStep,
+	}
+}
+
+// stepOutcome is a finished step, as reported by the goroutine that ran it
+type stepOutcome struct {
+	step   WorkflowStep
+	result schemas.WorkflowStepResult
+	err    error
+}
+
+// runWorkflow runs a session's workflow steps in place of the default single
+// round of drone research. Each step starts once the steps it depends on have
+// finished, so independent steps run at the same time. A failed step fails the
+// workflow, lets its dependents run or skips them, according to its OnFailure.
+func (o *Orchestrator) runWorkflow(ctx context.Context, session *ResearchSession, steps []WorkflowStep) error {
+	ancestors, err := workflowAncestors(steps)
+	if err != nil {
+		return err
+	}
+	state := newWorkflowState(ancestors)
+	o.mu.Lock()
+	session.Workflow = state
+	o.mu.Unlock()
+
+	// Drone research steps wait for their results as they arrive
+	go o.collectResults(ctx, session)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	executors := o.stepExecutors()
+	outcomes := make(chan stepOutcome)
+	// finished holds whether each finished step's dependents may run
+	finished := make(map[string]bool, len(steps))
+	started := make(map[string]bool, len(steps))
+	running := 0
+	var failure error
+
+	for {
+		for progressed := true; progressed; {
+			progressed = false
+			for _, step := range steps {
+				if started[step.Name] {
+					continue
+				}
+				ready, blockedBy := true, ""
+				for _, dependency := range step.DependsOn {
+					runnable, done := finished[dependency]
+					if !done {
+						ready = false
+					} else if !runnable {
+						blockedBy = dependency
+					}
+				}
+
+				switch {
+				case blockedBy != "":
+					started[step.Name] = true
+					finished[step.Name] = false
+					now := time.Now()
+					state.record(schemas.WorkflowStepResult{
+						Name:        step.Name,
+						Operation:   step.Operation,
+						DependsOn:   step.DependsOn,
+						Status:      "skipped",
+						Error:       fmt.Sprintf("skipped because %s did not complete", blockedBy),
+						StartedAt:   now,
+						CompletedAt: now,
+					})
+					o.recordWorkflowProgress(session, state)
+					progressed = true
+				case ready && failure == nil:
+					started[step.Name] = true
+					running++
+					log.Printf("Session %s: starting workflow step %s (%s)", session.Config.SessionID, step.Name, step.Operation)
+					go func(step WorkflowStep) {
+						result := schemas.WorkflowStepResult{
+							Name:      step.Name,
+							Operation: step.Operation,
+							DependsOn: step.DependsOn,
+							StartedAt: time.Now(),
+						}
+						output, err := executors[step.Operation](ctx, session, step, state)
+						result.CompletedAt = time.Now()
+						if err != nil {
+							result.Status = "failed"
+							result.Error = err.Error()
+						} else {
+							result.Status = "completed"
+							result.Output = output
+						}
+						outcomes <- stepOutcome{step: step, result: result, err: err}
+					}(step)
+				}
+			}
+		}
+		if running == 0 {
+			break
+		}
+
+		outcome := <-outcomes
+		running--
+		state.record(outcome.result)
+		o.recordWorkflowProgress(session, state)
+
+		step := outcome.step
+		if outcome.err == nil {
+			finished[step.Name] = true
+			continue
+		}
+		switch {
+		case failure != nil:
+			// Already stopping; later failures are usually the cancellation
+			finished[step.Name] = false
+		case step.OnFailure == FailWorkflow || o.enforceBudget(session):
+			failure = fmt.Errorf("workflow step %s failed: %w", step.Name, outcome.err)
+			finished[step.Name] = false
+			cancel()
+		case step.OnFailure == SkipDependents:
+			log.Printf("Warning: Workflow step %s of session %s failed, skipping the steps that depend on it: %v", step.Name, session.Config.SessionID, outcome.err)
+			finished[step.Name] = false
+		default:
+			log.Printf("Warning: Workflow step %s of session %s failed, continuing: %v", step.Name, session.Config.SessionID, outcome.err)
+			finished[step.Name] = true
+		}
+	}
+
+	if failure != nil {
+		return failure
+	}
+	return ctx.Err()
+}
+
+// recordWorkflowProgress stores which workflow steps have finished, alongside the session's status
+func (o *Orchestrator) recordWorkflowProgress(session *ResearchSession, state *WorkflowState) {
+	if o.firestoreClient == nil {
+		return
+	}
+
+	state.mu.RLock()
+	steps := make([]map[string]interface{}, 0, len(state.Steps))
+	for _, step := range state.Steps {
+		steps = append(steps, map[string]interface{}{
+			"name":       step.Name,
+			"operation":  step.Operation,
+			"depends_on": step.DependsOn,
+			"status":     step.Status,
+			"error":      step.Error,
+		})
+	}
+	state.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := o.collection(session.Config.Tenant, sessionsCollection).Doc(session.Config.SessionID).Set(ctx, map[string]interface{}{
+		"workflow_steps": steps,
+		"updated_at":     time.Now(),
+	}, firestore.MergeAll)
+	if err != nil {
+		log.Printf("Warning: Failed to store workflow progress of session %s: %v", session.Config.SessionID, err)
+	}
+}
+
+// stepSubject is what a step researches: its "subject" param, or the step's
+// name applied to the workflow's subject (or else the session topic) and time range
+func stepSubject(config *schemas.ResearchConfig, step WorkflowStep) string {
+	if subject, ok := step.Params["subject"].(string); ok && subject != "" {
+		return subject
+	}
+
+	base := config.Topic
+	if subject, ok := config.Workflow["subject"].(string); ok && subject != "" {
+		base = subject
+	}
+	subject := fmt.Sprintf("%s: %s", base, strings.ReplaceAll(step.Name, "_", " "))
+	if timeRange, ok := config.Workflow["time_range"].(string); ok && timeRange != "" {
+		subject += fmt.Sprintf(" (%s)", timeRange)
+	}
+	return subject
+}
+
+// runDroneResearchStep researches a sub-query of the step's subject on each
+// available drone, or the first Drones of them, and waits for their results
+func (o *Orchestrator) runDroneResearchStep(ctx context.Context, session *ResearchSession, step WorkflowStep, state *WorkflowState) (interface{}, error) {
+	o.mu.RLock()
+	drones := make([]*DroneInfo, 0, len(session.Drones))
+	for _, drone := range session.Drones {
+		if drone.Status != "unhealthy" && drone.Status != "failed_to_instruct" && drone.Status != "unresponsive" && drone.Status != "replaced" {
+			drones = append(drones, drone)
+		}
+	}
+	o.mu.RUnlock()
+	if len(drones) == 0 {
+		return nil, fmt.Errorf("no drones are available")
+	}
+	sort.Slice(drones, func(i, j int) bool { return drones[i].ID < drones[j].ID })
+	if step.Drones > 0 && step.Drones < len(drones) {
+		drones = drones[:step.Drones]
+	}
+
+	subject := stepSubject(session.Config, step)
+	known := o.priorFindings(ctx, session.Config, subject)
+	queries, err := o.claudeAgent.GenerateSubQueries(ctx, subject, len(drones), known)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate sub-queries: %w", err)
+	}
+	tokens := estimateTokens(subject)
+	for _, text := range append(known, queries...) {
+		tokens += estimateTokens(text)
+	}
+	session.Budget.addLLMTokens(tokens)
+	if o.enforceBudget(session) {
+		return nil, fmt.Errorf("session %s is over budget", session.Config.SessionID)
+	}
+
+	previous := state.summary(step.Name)
+	taskIDs := make(map[string]bool, len(drones))
+	var sent []string
+	for i, drone := range drones {
+		if i >= len(queries) {
+			break
+		}
+		taskID := fmt.Sprintf("%s-%d", step.Name, i+1)
+		task := map[string]interface{}{
+			"subject":        queries[i],
+			"run_id":         session.Config.SessionID,
+			"pubsub_topic":   resultsTopicName(session.Config.Tenant, session.Config.SessionID),
+			"metrics_topic":  metricsTopicName(session.Config.Tenant, session.Config.SessionID),
+			"task_id":        taskID,
+			"workflow_step":  step.Name,
+			"correlation_id": session.Config.CorrelationID,
+		}
+		if previous != "" {
+			task["context"] = previous
+		}
+		if err := o.sendInstructionsToDrone(ctx, drone, task); err != nil {
+			log.Printf("Failed to send step %s to drone %s: %v", step.Name, drone.ID, err)
+			continue
+		}
+		o.mu.Lock()
+		drone.Status = "running"
+		drone.Task = task
+		o.mu.Unlock()
+		taskIDs[taskID] = true
+		sent = append(sent, queries[i])
+	}
+	if len(sent) == 0 {
+		return nil, fmt.Errorf("no drone accepted the step's instructions")
+	}
+
+	results, err := o.waitForStepResults(ctx, session, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	state.mu.Lock()
+	state.results[step.Name] = results
+	state.mu.Unlock()
+
+	data := make([]map[string]interface{}, 0, len(results))
+	failed := 0
+	for _, result := range results {
+		if result.Status != "completed" {
+			failed++
+			continue
+		}
+		data = append(data, result.Data)
+	}
+	if failed == len(results) {
+		return nil, fmt.Errorf("all %d drones failed", failed)
+	}
+	return map[string]interface{}{
+		"subject": subject,
+		"queries": sent,
+		"results": data,
+		"failed":  failed,
+	}, nil
+}
+
+// waitForStepResults waits until the results of the given tasks have arrived,
+// or the session's time runs out, and returns them. Steps running at the same
+// time share the session's results, so they are told apart by task ID.
+func (o *Orchestrator) waitForStepResults(ctx context.Context, session *ResearchSession, taskIDs map[string]bool) ([]schemas.DroneResult, error) {
+	deadline := session.StartTime.Add(time.Duration(session.Config.TimeoutMinutes) * time.Minute)
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		var results []schemas.DroneResult
+		o.mu.RLock()
+		for _, result := range session.Results {
+			if taskIDs[result.TaskID] {
+				results = append(results, result)
+			}
+		}
+		o.mu.RUnlock()
+		if len(results) >= len(taskIDs) {
+			return results, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out with %d of %d results", len(results), len(taskIDs))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// runWebsetsStep asks the websets MCP server to build a webset for the step's
+// subject. The server and tool default to WORKFLOW_WEBSETS_SERVER and
+// WORKFLOW_WEBSETS_TOOL; "arguments" replaces the default query and count.
+func (o *Orchestrator) runWebsetsStep(ctx context.Context, session *ResearchSession, step WorkflowStep, state *WorkflowState) (interface{}, error) {
+	server := stringParam(step.Params, "server", getEnvOrDefault("WORKFLOW_WEBSETS_SERVER", "websets"))
+	tool := stringParam(step.Params, "tool", getEnvOrDefault("WORKFLOW_WEBSETS_TOOL", "create_webset"))
+
+	arguments, ok := step.Params["arguments"].(map[string]interface{})
+	if !ok {
+		arguments = map[string]interface{}{"query": stepSubject(session.Config, step)}
+		if count, ok := step.Params["count"]; ok {
+			arguments["count"] = count
+		}
+	}
+
+	output, err := o.callStepTool(ctx, server, tool, arguments)
+	session.Budget.addExaCalls(1)
+	return output, err
+}
+
+// runAnalysisStep analyzes the drone results of the steps it depends on, or
+// every drone result collected so far if none of them researched on drones
+func (o *Orchestrator) runAnalysisStep(ctx context.Context, session *ResearchSession, step WorkflowStep, state *WorkflowState) (interface{}, error) {
+	results := state.ancestorResults(step.Name)
+	if len(results) == 0 {
+		o.mu.RLock()
+		results = make([]schemas.DroneResult, len(session.Results))
+		copy(results, session.Results)
+		o.mu.RUnlock()
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("there are no drone results to analyze")
+	}
+	return o.analyzeResults(ctx, stepSubject(session.Config, step), results)
+}
+
+// runSequentialThinkingStep reasons about the step's subject in light of the earlier steps
+func (o *Orchestrator) runSequentialThinkingStep(ctx context.Context, session *ResearchSession, step WorkflowStep, state *WorkflowState) (interface{}, error) {
+	subject := stepSubject(session.Config, step)
+	response, err := o.claudeAgent.AnalyzeSequentialThinking(ctx, subject, state.summary(step.Name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reason about %s: %w", subject, err)
+	}
+
+	tokens := estimateTokens(subject) + estimateTokens(response.Solution)
+	for _, thought := range response.Thoughts {
+		tokens += estimateTokens(thought.Thought) + estimateTokens(thought.Reasoning)
+	}
+	session.Budget.addLLMTokens(tokens)
+	return response, nil
+}
+
+// runMCPToolStep calls the "tool" on the "server" given in the step's params with its "arguments"
+func (o *Orchestrator) runMCPToolStep(ctx context.Context, session *ResearchSession, step WorkflowStep, state *WorkflowState) (interface{}, error) {
+	server := stringParam(step.Params, "server", "")
+	tool := stringParam(step.Params, "tool", "")
+	if server == "" || tool == "" {
+		return nil, fmt.Errorf("mcp_tool steps need a server and tool")
+	}
+	arguments, _ := step.Params["arguments"].(map[string]interface{})
+	return o.callStepTool(ctx, server, tool, arguments)
+}
+
+// callStepTool calls a tool on an external MCP server and returns its text output
+func (o *Orchestrator) callStepTool(ctx context.Context, server, tool string, arguments map[string]interface{}) (interface{}, error) {
+	result, err := o.mcpClient.CallTool(ctx, server, tool, arguments)
+	if err != nil {
+		return nil, err
+	}
+	output := ""
+	if toolResult, ok := result.(*mcp.CallToolResult); ok {
+		output = toolResultText(toolResult)
+	}
+	return map[string]interface{}{
+		"server": server,
+		"tool":   tool,
+		"output": output,
+	}, nil
+}
+
+// stringParam returns a string step param, or defaultValue when it is missing or empty
+func stringParam(params map[string]interface{}, key, defaultValue string) string {
+	if value, ok := params[key].(string); ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// workflowSections renders the outcome of each workflow step as a report section
+func workflowSections(state *WorkflowState) []schemas.ReportSection {
+	sections := make([]schemas.ReportSection, 0, len(state.Steps))
+	for _, step := range state.Steps {
+		title := strings.ToUpper(step.Name[:1]) + strings.ReplaceAll(step.Name[1:], "_", " ")
+		section := schemas.ReportSection{Title: title}
+
+		switch output := step.Output.(type) {
+		case nil:
+			section.Content = fmt.Sprintf("This step (%s) %s: %s", step.Operation, step.Status, step.Error)
+		case *DataAnalysis:
+			section.Content = fmt.Sprintf("Analyzed the drone results with %.0f%% average pattern confidence.", output.AverageConfidence*100)
+			section.Insights = output.TopInsights
+		case *schemas.SequentialThinkingResponse:
+			section.Content = output.Solution
+		case map[string]interface{}:
+			if text, ok := output["output"].(string); ok {
+				section.Content = text
+			} else if results, ok := output["results"].([]map[string]interface{}); ok {
+				section.Content = fmt.Sprintf("Researched %q on %d drones (%v failed).", output["subject"], len(results), output["failed"])
+			}
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
@@ -1,6 +1,11 @@
 package schemas
 
-import "time"
+import (
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/encryption"
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
 
 // WidescreenResearchInput represents the input for the widescreen-research tool
 type WidescreenResearchInput struct {
@@ -8,6 +13,7 @@ type WidescreenResearchInput struct {
 	SessionID          string                 `json:"session_id,omitempty"`
 	ElicitationAnswers map[string]interface{} `json:"elicitation_answers,omitempty"`
 	Parameters         map[string]interface{} `json:"parameters,omitempty"`
+	APIKey             string                 `json:"api_key,omitempty"` // identifies the caller's tenant
 }
 
 // ElicitationQuestion represents a question in the elicitation process
@@ -45,7 +51,19 @@ type ResearchConfig struct {
 	TimeoutMinutes    int       `json:"timeout_minutes"`
 	PriorityLevel     string    `json:"priority_level"`
 	WorkflowTemplates string    `json:"workflow_templates,omitempty"`
+	TemplateID        string    `json:"template_id,omitempty"` // saved research template chosen during elicitation
+	TemplateParams    map[string]interface{} `json:"template_params,omitempty"` // values for the template's parameters
+	Workflow          map[string]interface{} `json:"workflow,omitempty"`        // the template's workflow with its parameters substituted
 	SpecificSources   string    `json:"specific_sources,omitempty"`
+	MaxCostUSD        float64   `json:"max_cost_usd,omitempty"` // 0 means no budget
+	Regions           []string  `json:"regions,omitempty"`      // in order of preference; empty uses the orchestrator's region
+	ServiceAccount    string    `json:"service_account,omitempty"` // overrides the account configured for research drones
+	NotifyEmails      []string  `json:"notify_emails,omitempty"`   // emailed the report when the session finishes
+	SubQueries        []string  `json:"sub_queries,omitempty"`     // approved from a plan; used instead of generating new ones
+	Simulate          bool      `json:"simulate,omitempty"`        // synthetic drone results instead of Cloud Run; no cloud spend
+	NoMemory          bool      `json:"no_memory,omitempty"`       // generate sub-queries without the findings of earlier sessions
+	Tenant            string    `json:"tenant,omitempty"`          // set by the orchestrator to the tenant that started the session
+	CorrelationID     string    `json:"correlation_id,omitempty"`  // set by the orchestrator to the ID of the call that started the session
 	CreatedAt         time.Time `json:"created_at"`
 }
 
@@ -55,10 +73,25 @@ type ResearchResult struct {
 	Status       string                 `json:"status"`
 	ReportURL    string                 `json:"report_url,omitempty"`
 	ReportData   interface{}            `json:"report_data,omitempty"`
+	Diff         *ReportDiff            `json:"diff,omitempty"` // changes since the previous run of a schedule
+	Workflow     []WorkflowStepResult   `json:"workflow,omitempty"` // the template workflow steps that ran
 	Metrics      ResearchMetrics        `json:"metrics"`
+	ReplayOf     string                 `json:"replay_of,omitempty"` // the session whose stored results were re-reported
 	CompletedAt  time.Time              `json:"completed_at"`
 }
 
+// WorkflowStepResult records one step of a template workflow that ran in a session
+type WorkflowStepResult struct {
+	Name        string      `json:"name"`
+	Operation   string      `json:"operation"`
+	DependsOn   []string    `json:"depends_on,omitempty"`
+	Status      string      `json:"status"` // completed, failed or skipped
+	Output      interface{} `json:"output,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	StartedAt   time.Time   `json:"started_at"`
+	CompletedAt time.Time   `json:"completed_at"`
+}
+
 // ResearchMetrics contains metrics about the research process
 type ResearchMetrics struct {
 	DronesProvisioned int           `json:"drones_provisioned"`
@@ -67,6 +100,75 @@ type ResearchMetrics struct {
 	TotalDuration     time.Duration `json:"total_duration"`
 	DataPointsCollected int         `json:"data_points_collected"`
 	CostEstimate      float64       `json:"cost_estimate"`
+	Cost              CostBreakdown `json:"cost"`
+	DroneUsage        []DroneUsage  `json:"drone_usage,omitempty"` // what each drone last reported about itself
+}
+
+// DroneUsage is the work and resources one drone reported for a session
+type DroneUsage struct {
+	DroneID         string    `json:"drone_id"`
+	RequestsHandled int64     `json:"requests_handled"`
+	TasksCompleted  int64     `json:"tasks_completed"`
+	TasksFailed     int64     `json:"tasks_failed"`
+	SearchCalls     int64     `json:"search_calls"`
+	LLMTokens       int64     `json:"llm_tokens"`
+	MemoryBytes     uint64    `json:"memory_bytes"`
+	UptimeSeconds   float64   `json:"uptime_seconds"`
+	ReportedAt      time.Time `json:"reported_at"`
+}
+
+// CostBreakdown itemizes the estimated spend of a research session
+type CostBreakdown struct {
+	DroneRuntimeUSD float64 `json:"drone_runtime_usd"`
+	LLMTokensUSD    float64 `json:"llm_tokens_usd"`
+	ExaCallsUSD     float64 `json:"exa_calls_usd"`
+	TotalUSD        float64 `json:"total_usd"`
+	LLMTokens       int     `json:"llm_tokens"`
+	ExaCalls        int     `json:"exa_calls"`
+}
+
+// ResearchEstimate is the plan and time/cost estimate for a research config, made without provisioning anything
+type ResearchEstimate struct {
+	Config           *ResearchConfig `json:"config"`
+	DroneCount       int             `json:"drone_count"`
+	WarmDrones       int             `json:"warm_drones"`
+	DroneCPU         string          `json:"drone_cpu"`
+	DroneMemory      string          `json:"drone_memory"`
+	EstimatedMinutes float64         `json:"estimated_minutes"`
+	MaxMinutes       float64         `json:"max_minutes"`
+	EstimatedCost    CostBreakdown   `json:"estimated_cost"`
+	WorstCaseCostUSD float64         `json:"worst_case_cost_usd"`
+	WithinBudget     bool            `json:"within_budget"`
+	Warnings         []string        `json:"warnings,omitempty"`
+}
+
+// ResearchPlan is what a research session would do, made by orchestrate-research
+// with plan_only so it can be approved before anything is provisioned
+type ResearchPlan struct {
+	Config     *ResearchConfig   `json:"config"`
+	SubQueries []string          `json:"sub_queries,omitempty"`
+	Drones     []PlannedDrone    `json:"drones"`
+	Regions    []string          `json:"regions"`
+	Workflow   []PlannedStep     `json:"workflow,omitempty"` // the template workflow's steps
+	Estimate   *ResearchEstimate `json:"estimate"`
+}
+
+// PlannedDrone is one drone a research plan would provision
+type PlannedDrone struct {
+	Index    int    `json:"index"`
+	Region   string `json:"region"` // first choice; provisioning falls back to the session's other regions
+	Warm     bool   `json:"warm"`   // taken from the warm pool instead of deployed
+	SubQuery string `json:"sub_query,omitempty"`
+}
+
+// PlannedStep is one template workflow step a research plan would run
+type PlannedStep struct {
+	Name       string   `json:"name"`
+	Operation  string   `json:"operation"`
+	Subject    string   `json:"subject,omitempty"`
+	DependsOn  []string `json:"depends_on,omitempty"`
+	Drones     int      `json:"drones,omitempty"`
+	SubQueries []string `json:"sub_queries,omitempty"`
 }
 
 // DroneTask represents the input for a single research drone
@@ -80,19 +182,58 @@ type DroneTask struct {
 // DroneResult represents the result from a single research drone
 type DroneResult struct {
 	DroneID      string                 `json:"drone_id"`
+	// TaskID identifies the task the result answers, so redelivered results can be dropped
+	TaskID       string                 `json:"task_id,omitempty"`
+	// CorrelationID is the ID of the call that started the result's session
+	CorrelationID string                `json:"correlation_id,omitempty"`
 	Status       string                 `json:"status"`
 	Data         map[string]interface{} `json:"data"`
+	// DataRef is the gs:// URI of Data when it was too large to publish
+	DataRef      string                 `json:"data_ref,omitempty"`
+	// SealedData is Data encrypted with FIELD_KMS_KEY, as stored in Firestore
+	SealedData   *encryption.Envelope   `json:"sealed_data,omitempty"`
 	Error        string                 `json:"error,omitempty"`
 	CompletedAt  time.Time              `json:"completed_at"`
 	ProcessingTime time.Duration        `json:"processing_time"`
 }
 
+// DroneLogQuery selects the log entries of a drone
+type DroneLogQuery struct {
+	DroneID string `json:"drone_id"`
+	// SessionID limits entries to the time the session ran
+	SessionID string    `json:"session_id,omitempty"`
+	Since     time.Time `json:"since,omitempty"`
+	Until     time.Time `json:"until,omitempty"`
+	// MinSeverity drops entries below a Cloud Logging severity such as WARNING
+	MinSeverity string `json:"min_severity,omitempty"`
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// DroneLogEntry is a log entry written by a drone's Cloud Run service
+type DroneLogEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Severity  string    `json:"severity"`
+	Message   string    `json:"message"`
+	Revision  string    `json:"revision,omitempty"`
+	Region    string    `json:"region,omitempty"`
+}
+
+// DroneLogs are the most recent log entries matching a query, oldest first
+type DroneLogs struct {
+	Query   DroneLogQuery   `json:"query"`
+	Entries []DroneLogEntry `json:"entries"`
+	// Truncated is set when more entries matched than the limit
+	Truncated bool `json:"truncated"`
+}
+
 // GCPProvisionRequest represents a request to provision GCP resources
 type GCPProvisionRequest struct {
 	ResourceType string                 `json:"resource_type"` // cloud_run, pubsub, firestore
 	Count        int                    `json:"count"`
 	Region       string                 `json:"region"`
 	Config       map[string]interface{} `json:"config"`
+	// Scaling overrides the Cloud Run scaling defaults, for cloud_run resources
+	Scaling types.ScalingConfig `json:"scaling"`
 }
 
 // GCPProvisionResponse represents the response from GCP provisioning
@@ -140,6 +281,13 @@ type DataAnalysisRequest struct {
 	Data       []DroneResult `json:"data"`
 	AnalysisType string      `json:"analysis_type"`
 	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Export     *AnalysisExport        `json:"export,omitempty"`
+}
+
+// AnalysisExport asks for an analysis to be written to files for notebooks
+type AnalysisExport struct {
+	Format      string `json:"format"`      // csv (the default) or jsonl
+	Destination string `json:"destination"` // a local directory or gs://bucket/prefix
 }
 
 // DataAnalysisResponse represents the response from data analysis
@@ -149,6 +297,44 @@ type DataAnalysisResponse struct {
 	Patterns   []Pattern              `json:"patterns"`
 	Statistics map[string]interface{} `json:"statistics"`
 	Visualizations []Visualization    `json:"visualizations,omitempty"`
+	Entities   []Entity               `json:"entities,omitempty"`
+	Sentiment  []SentimentScore       `json:"sentiment,omitempty"`
+	Keywords   []Keyword              `json:"keywords,omitempty"`
+	Exports    []string               `json:"exports,omitempty"` // the files the analysis was exported to
+	Quality    []DroneQuality         `json:"quality,omitempty"`
+}
+
+// DroneQuality breaks down the quality score of one drone's data
+type DroneQuality struct {
+	DroneID  string             `json:"drone_id"`
+	Score    float64            `json:"score"`    // from 0 to 10
+	Criteria map[string]float64 `json:"criteria"` // each criterion's score, from 0 to 1
+	Notes    []string           `json:"notes,omitempty"` // what cost the drone points
+}
+
+// Keyword is a word or phrase that characterizes the collected content
+type Keyword struct {
+	Term      string  `json:"term"`
+	Kind      string  `json:"kind"`      // term, ranked by TF-IDF, or phrase, ranked by RAKE
+	Score     float64 `json:"score"`     // relative to the top keyword of its kind, which scores 1
+	Documents int     `json:"documents"` // how many results it appears in
+}
+
+// SentimentScore is the average sentiment of the content about one sub-query or source
+type SentimentScore struct {
+	Subject string  `json:"subject"` // the sub-query or source URL
+	Kind    string  `json:"kind"`    // query or source
+	Score   float64 `json:"score"`   // from -1 (negative) to 1 (positive)
+	Label   string  `json:"label"`   // positive, neutral or negative
+	Texts   int     `json:"texts"`   // how many results or findings were scored
+}
+
+// Entity is a person, organization, location or product the drones mentioned
+type Entity struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`     // person, organization, location or product
+	Mentions int      `json:"mentions"` // across every drone result
+	Drones   []string `json:"drones"`   // the drones that mentioned it, linking entities they found together
 }
 
 // Pattern represents a discovered pattern in the data
@@ -159,18 +345,97 @@ type Pattern struct {
 	Confidence  float64 `json:"confidence"`
 }
 
+// Finding is a claim drones reported, merged across every drone that reported it
+type Finding struct {
+	Claim   string   `json:"claim"`
+	Sources []string `json:"sources,omitempty"` // normalized URLs backing the claim
+	Drones  []string `json:"drones"`            // the drones that reported it
+	// Credibility is the score of the claim's most credible source, between 0 and 1
+	Credibility float64 `json:"credibility"`
+}
+
+// SourceScore rates how credible a source drones cited is
+type SourceScore struct {
+	URL         string    `json:"url"`
+	Domain      string    `json:"domain,omitempty"`
+	Score       float64   `json:"score"`             // between 0 and 1
+	Reasons     []string  `json:"reasons,omitempty"` // the rules that set the score
+	PublishedAt time.Time `json:"published_at,omitempty"`
+	Citations   int       `json:"citations"` // how many drones cited it
+}
+
+// Contradiction is a claim that drones reported with different figures, or
+// that one drone denied and another asserted
+type Contradiction struct {
+	Topic  string    `json:"topic"` // the claim with its figures blanked out as #
+	Claims []Finding `json:"claims"`
+}
+
+// TopicCluster is a group of findings about the same topic, found by embedding
+// their claims and clustering the embeddings
+type TopicCluster struct {
+	Label    string    `json:"label"`
+	Keywords []string  `json:"keywords"` // the words that set the cluster's findings apart from the rest
+	Findings []Finding `json:"findings"`
+}
+
+// KnowledgeGraph links the entities a session's findings name by the relations
+// the findings state between them, with the sources supporting each
+type KnowledgeGraph struct {
+	SessionID string          `json:"session_id"`
+	Tenant    string          `json:"tenant,omitempty"`
+	Topic     string          `json:"topic"`
+	Entities  []GraphEntity   `json:"entities"`
+	Relations []GraphRelation `json:"relations"`
+	// Gaps are the parts of the topic the findings cover thinly or not at all
+	Gaps []CoverageGap `json:"gaps,omitempty"`
+	// FollowUpQueries are sub-queries that would close the gaps, for a follow-up session
+	FollowUpQueries []string `json:"follow_up_queries,omitempty"`
+	// DataRef is the gs:// URI of the whole graph when it is too large to store in Firestore
+	DataRef   string    `json:"data_ref,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GraphEntity is a person, organization, location or product findings name
+type GraphEntity struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Mentions int      `json:"mentions"` // findings naming it
+	Sources  []string `json:"sources,omitempty"`
+}
+
+// GraphRelation links two entities named in the same findings
+type GraphRelation struct {
+	Source    string   `json:"source"` // entity IDs
+	Target    string   `json:"target"`
+	Predicate string   `json:"predicate"` // the words between them in a claim, or "mentioned_with"
+	Claims    []string `json:"claims"`
+	Sources   []string `json:"sources,omitempty"`
+}
+
+// CoverageGap is an entity the findings say too little about
+type CoverageGap struct {
+	EntityID string `json:"entity_id,omitempty"` // empty when no finding names the entity
+	Entity   string `json:"entity"`
+	Reason   string `json:"reason"` // not_covered, single_source or unconnected
+}
+
 // Visualization represents a data visualization
 type Visualization struct {
 	Type   string                 `json:"type"`
 	Title  string                 `json:"title"`
 	Data   interface{}            `json:"data"`
 	Config map[string]interface{} `json:"config,omitempty"`
+	// Image is where the chart was saved once rendered as SVG
+	Image string `json:"image,omitempty"`
 }
 
 // ResearchReport represents a final research report
 type ResearchReport struct {
 	ID          string                 `json:"id"`
 	SessionID   string                 `json:"session_id"`
+	Tenant      string                 `json:"tenant,omitempty"`
 	Title       string                 `json:"title"`
 	Executive   string                 `json:"executive_summary"`
 	Sections    []ReportSection        `json:"sections"`
@@ -186,6 +451,34 @@ type ReportSection struct {
 	Content  string                 `json:"content"`
 	Data     map[string]interface{} `json:"data,omitempty"`
 	Insights []string               `json:"insights,omitempty"`
+	Visualizations []Visualization  `json:"visualizations,omitempty"`
+}
+
+// InterimResult is one drone's findings, passed to the client as soon as the
+// drone reports rather than when the session's report is ready
+type InterimResult struct {
+	SessionID   string    `json:"session_id"`
+	DroneID     string    `json:"drone_id"`
+	TaskID      string    `json:"task_id,omitempty"`
+	Status      string    `json:"status"`
+	Summary     string    `json:"summary,omitempty"`
+	KeyFindings []string  `json:"key_findings,omitempty"`
+	Sources     []string  `json:"sources,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Collected   int       `json:"collected"` // results the session had collected with this one
+	Expected    int       `json:"expected"`  // results the session expects from its drones
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// ResultSummary merges a batch of drone results, or of lower-level summaries,
+// during the reduce phase of a large session
+type ResultSummary struct {
+	ID          string   `json:"id"`
+	Level       int      `json:"level"`  // 1 summarizes drone results, 2 summarizes level 1 summaries, and so on
+	Inputs      []string `json:"inputs"` // the drone IDs or summary IDs merged
+	Summary     string   `json:"summary"`
+	KeyFindings []string `json:"key_findings,omitempty"`
+	Sources     []string `json:"sources,omitempty"`
 }
 
 // ReportMetadata contains metadata about the research report
@@ -196,4 +489,107 @@ type ReportMetadata struct {
 	DataPoints      int             `json:"data_points"`
 	Sources         []string        `json:"sources"`
 	Metrics         ResearchMetrics `json:"metrics"`
-}
\ No newline at end of file
+	Model           string          `json:"model,omitempty"` // the Claude model that wrote the report
+}
+// SessionSnapshotVersion is the format version of the snapshots export-session writes
+const SessionSnapshotVersion = 1
+
+// SessionSnapshot bundles everything stored about a research session, so it can
+// be moved between environments or attached to a support ticket
+type SessionSnapshot struct {
+	Version    int              `json:"version"`
+	ExportedAt time.Time        `json:"exported_at"`
+	SessionID  string           `json:"session_id"`
+	Status     string           `json:"status"`
+	Config     *ResearchConfig  `json:"config,omitempty"`
+	Results    []DroneResult    `json:"results"`
+	Report     *ResearchReport  `json:"report,omitempty"` // the latest report, if the session finished
+	Metrics    *ResearchMetrics `json:"metrics,omitempty"`
+}
+
+// PurgeManifest lists every artifact deleted for a research session
+type PurgeManifest struct {
+	SessionID     string    `json:"session_id"`
+	PurgedAt      time.Time `json:"purged_at"`
+	Documents     []string  `json:"documents"`     // Firestore document paths
+	Objects       []string  `json:"objects"`       // gs:// URIs of offloaded result data
+	Topics        []string  `json:"topics"`        // Pub/Sub topics
+	Subscriptions []string  `json:"subscriptions"` // Pub/Sub subscriptions
+	Files         []string  `json:"files"`         // reports and results written to the local reports directory
+	Embeddings    []string  `json:"embeddings"`    // IDs of the session's findings and report sections in the vector store
+	// Failures are artifacts that could not be deleted; purging again retries them
+	Failures []string `json:"failures,omitempty"`
+	// Retained describes data that cannot be deleted by session and expires on its own
+	Retained []string `json:"retained,omitempty"`
+}
+
+// FindingMatch is an indexed finding or webset item similar to a search query
+type FindingMatch struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"` // finding or webset_item
+	Text      string    `json:"text"`
+	Sources   []string  `json:"sources,omitempty"`
+	SessionID string    `json:"session_id"`
+	Topic     string    `json:"topic"`
+	Score     float64   `json:"score"` // similarity to the query, higher is closer
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FindingSearchResults are the past findings closest in meaning to a query
+type FindingSearchResults struct {
+	Query   string         `json:"query"`
+	Matches []FindingMatch `json:"matches"`
+}
+
+// ReportMatch is a section of a past report similar to a search query
+type ReportMatch struct {
+	ReportID  string    `json:"report_id"`
+	SessionID string    `json:"session_id"`
+	Topic     string    `json:"topic"`
+	Section   string    `json:"section"`
+	Excerpt   string    `json:"excerpt"`
+	Link      string    `json:"link"`  // the report, anchored at the section
+	Score     float64   `json:"score"` // similarity to the query, higher is closer
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReportSearchResults are the past report sections closest in meaning to a query
+type ReportSearchResults struct {
+	Query   string        `json:"query"`
+	Matches []ReportMatch `json:"matches"`
+}
+
+// ResearchSchedule re-runs a saved research config on a cron cadence
+type ResearchSchedule struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Cron is a five-field cron expression or @hourly, @daily, @weekly, @monthly or @yearly
+	Cron string `json:"cron"`
+	// Timezone is the IANA time zone Cron is evaluated in; defaults to UTC
+	Timezone      string         `json:"timezone,omitempty"`
+	Config        ResearchConfig `json:"config"`
+	Enabled       bool           `json:"enabled"`
+	NextRun       time.Time      `json:"next_run"`
+	LastRun       time.Time      `json:"last_run,omitempty"`
+	LastSessionID string         `json:"last_session_id,omitempty"`
+	LastStatus    string         `json:"last_status,omitempty"`
+	// LastReportID is the report later runs are diffed against
+	LastReportID string    `json:"last_report_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ReportDiff describes what changed between two reports of the same schedule
+type ReportDiff struct {
+	ScheduleID       string    `json:"schedule_id"`
+	PreviousReportID string    `json:"previous_report_id"`
+	ReportID         string    `json:"report_id"`
+	SummaryChanged   bool      `json:"summary_changed"`
+	AddedSections    []string  `json:"added_sections,omitempty"`
+	RemovedSections  []string  `json:"removed_sections,omitempty"`
+	ChangedSections  []string  `json:"changed_sections,omitempty"`
+	NewInsights      []string  `json:"new_insights,omitempty"`
+	DroppedInsights  []string  `json:"dropped_insights,omitempty"`
+	NewSources       []string  `json:"new_sources,omitempty"`
+	DroppedSources   []string  `json:"dropped_sources,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
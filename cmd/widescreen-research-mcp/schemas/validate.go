@@ -0,0 +1,81 @@
+package schemas
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+)
+
+// Drone result statuses. Drones report success, simulated drones completed.
+const (
+	ResultStatusSuccess   = "success"
+	ResultStatusCompleted = "completed"
+	ResultStatusFailed    = "failed"
+)
+
+// Validate checks a drone result as decoded from JSON against what the
+// orchestrator's analysis reads. It returns an MCP-3003 error listing every
+// problem in its details, or nil for a valid result.
+func (r DroneResult) Validate() error {
+	var problems []string
+	if r.DroneID == "" {
+		problems = append(problems, "drone_id is required")
+	}
+	switch r.Status {
+	case ResultStatusSuccess, ResultStatusCompleted:
+		if r.Data == nil && r.DataRef == "" {
+			problems = append(problems, "data or data_ref is required unless the result failed")
+		}
+	case ResultStatusFailed:
+		if r.Error == "" {
+			problems = append(problems, "error is required for a failed result")
+		}
+	case "":
+		problems = append(problems, "status is required")
+	default:
+		problems = append(problems, fmt.Sprintf("unknown status %q", r.Status))
+	}
+	if r.CompletedAt.IsZero() {
+		problems = append(problems, "completed_at is required")
+	}
+	if r.ProcessingTime < 0 {
+		problems = append(problems, "processing_time is negative")
+	}
+
+	// Findings and sources are lists of claims or URLs, or of objects describing them
+	for _, key := range []string{"findings", "key_findings", "sources"} {
+		value, ok := r.Data[key]
+		if !ok || value == nil {
+			continue
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			problems = append(problems, fmt.Sprintf("data.%s is not a list", key))
+			continue
+		}
+		for i, item := range items {
+			switch item.(type) {
+			case string, map[string]interface{}:
+			default:
+				problems = append(problems, fmt.Sprintf("data.%s[%d] is neither text nor an object", key, i))
+			}
+		}
+	}
+	if value, ok := r.Data["confidence"]; ok && value != nil {
+		if _, ok := value.(float64); !ok {
+			problems = append(problems, "data.confidence is not a number")
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	err := mcperrors.New(mcperrors.CategoryInvalidResult, "invalid result from drone %q: %s", r.DroneID, strings.Join(problems, "; "))
+	err.Details = map[string]interface{}{
+		"drone_id": r.DroneID,
+		"task_id":  r.TaskID,
+		"problems": problems,
+	}
+	return err
+}
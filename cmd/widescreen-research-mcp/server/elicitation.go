@@ -2,10 +2,12 @@ package server
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/orchestrator"
 	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
 )
 
@@ -13,6 +15,9 @@ import (
 type ElicitationManager struct {
 	sessions map[string]*ElicitationSession
 	mu       sync.RWMutex
+
+	// templates lists the research templates offered as workflow choices
+	templates func() []*orchestrator.ResearchTemplate
 }
 
 // ElicitationSession represents an active elicitation session
@@ -24,10 +29,12 @@ type ElicitationSession struct {
 	LastUpdated time.Time
 }
 
-// NewElicitationManager creates a new elicitation manager
-func NewElicitationManager() *ElicitationManager {
+// NewElicitationManager creates a new elicitation manager that offers the
+// research templates returned by templates
+func NewElicitationManager(templates func() []*orchestrator.ResearchTemplate) *ElicitationManager {
 	return &ElicitationManager{
-		sessions: make(map[string]*ElicitationSession),
+		sessions:  make(map[string]*ElicitationSession),
+		templates: templates,
 	}
 }
 
@@ -134,7 +141,12 @@ func (em *ElicitationManager) ProcessAnswers(sessionID string, answers map[strin
 
 // getWorkflowQuestions returns workflow-related questions
 func (em *ElicitationManager) getWorkflowQuestions() []schemas.ElicitationQuestion {
-	return []schemas.ElicitationQuestion{
+	var questions []schemas.ElicitationQuestion
+	if question, ok := em.templateQuestion(); ok {
+		questions = append(questions, question)
+	}
+
+	return append(questions, []schemas.ElicitationQuestion{
 		{
 			ID:       "workflow_templates",
 			Question: "Do you have any pre-orchestrated workflows you want the researchers to use? If yes, paste them below:",
@@ -157,7 +169,36 @@ func (em *ElicitationManager) getWorkflowQuestions() []schemas.ElicitationQuesti
 				{Value: "raw_data", Label: "Raw Data"},
 			},
 		},
+	}...)
+}
+
+// templateQuestion offers the available research templates as choices, if there are any
+func (em *ElicitationManager) templateQuestion() (schemas.ElicitationQuestion, bool) {
+	if em.templates == nil {
+		return schemas.ElicitationQuestion{}, false
+	}
+	templates := em.templates()
+	if len(templates) == 0 {
+		return schemas.ElicitationQuestion{}, false
+	}
+
+	options := make([]schemas.ElicitationOption, 0, len(templates)+1)
+	options = append(options, schemas.ElicitationOption{Value: "", Label: "None - Plan the research from scratch"})
+	for _, template := range templates {
+		label := template.Name
+		if template.Description != "" {
+			label = fmt.Sprintf("%s - %s", template.Name, template.Description)
+		}
+		options = append(options, schemas.ElicitationOption{Value: template.ID, Label: label})
 	}
+
+	return schemas.ElicitationQuestion{
+		ID:       "research_template",
+		Question: "Would you like to use one of your team's saved research templates?",
+		Type:     "select",
+		Required: false,
+		Options:  options,
+	}, true
 }
 
 // getAdvancedQuestions returns advanced configuration questions
@@ -185,8 +226,48 @@ func (em *ElicitationManager) getAdvancedQuestions(session *ElicitationSession)
 				{Value: "high", Label: "High - Performance-optimized"},
 			},
 		},
+		{
+			ID:       "max_cost_usd",
+			Question: "Maximum budget for this research in USD? The session is stopped when it is reached.",
+			Type:     "number",
+			Required: false,
+			Metadata: map[string]interface{}{
+				"min":         0,
+				"placeholder": "Leave empty for no limit",
+			},
+		},
+		{
+			ID:       "regions",
+			Question: "Which GCP regions should drones run in? Drones are spread across them and fail over when a region is out of capacity.",
+			Type:     "text",
+			Required: false,
+			Metadata: map[string]interface{}{
+				"placeholder": "e.g., us-central1, us-east1 (leave empty for the default region)",
+			},
+		},
+		{
+			ID:       "service_account",
+			Question: "Service account for the research drones to run as?",
+			Type:     "text",
+			Required: false,
+			Metadata: map[string]interface{}{
+				"placeholder": "e.g., researcher@project.iam.gserviceaccount.com (leave empty for the configured default)",
+			},
+		},
+		{
+			ID:       "notify_emails",
+			Question: "Who should be emailed the report when the research finishes?",
+			Type:     "text",
+			Required: false,
+			Metadata: map[string]interface{}{
+				"placeholder": "e.g., alice@example.com, team@example.com (leave empty for no email)",
+			},
+		},
 	}
 
+	// Ask for the parameters of the chosen template
+	questions = append(questions, em.templateParameterQuestions(session)...)
+
 	// Add conditional questions based on research topic
 	if topic, ok := session.Answers["research_topic"].(string); ok && topic != "" {
 		questions = append(questions, schemas.ElicitationQuestion{
@@ -203,6 +284,70 @@ func (em *ElicitationManager) getAdvancedQuestions(session *ElicitationSession)
 	return questions
 }
 
+// templateParameterPrefix prefixes the IDs of questions asking for template parameters
+const templateParameterPrefix = "template_param."
+
+// templateParameterQuestions asks for the parameters of the template chosen in the workflow step
+func (em *ElicitationManager) templateParameterQuestions(session *ElicitationSession) []schemas.ElicitationQuestion {
+	templateID, _ := session.Answers["research_template"].(string)
+	if templateID == "" || em.templates == nil {
+		return nil
+	}
+
+	var template *orchestrator.ResearchTemplate
+	for _, candidate := range em.templates() {
+		if candidate.ID == templateID {
+			template = candidate
+			break
+		}
+	}
+	if template == nil {
+		return nil
+	}
+
+	questions := make([]schemas.ElicitationQuestion, 0, len(template.Parameters))
+	for _, param := range template.Parameters {
+		question := param.Description
+		if question == "" {
+			question = param.Name
+		}
+		questionType := "text"
+		if param.Type == orchestrator.ParamNumber {
+			questionType = "number"
+		}
+
+		metadata := map[string]interface{}{}
+		if param.Default != nil {
+			metadata["default"] = param.Default
+		}
+		if param.Type == orchestrator.ParamList {
+			metadata["placeholder"] = "Comma-separated values"
+		}
+		questions = append(questions, schemas.ElicitationQuestion{
+			ID:       templateParameterPrefix + param.Name,
+			Question: fmt.Sprintf("%s (for the %s template)", question, template.Name),
+			Type:     questionType,
+			Required: param.Required && param.Default == nil,
+			Metadata: metadata,
+		})
+	}
+	return questions
+}
+
+// getTemplateParams collects the answers to the template parameter questions
+func (em *ElicitationManager) getTemplateParams(session *ElicitationSession) map[string]interface{} {
+	var params map[string]interface{}
+	for key, value := range session.Answers {
+		if name, ok := strings.CutPrefix(key, templateParameterPrefix); ok {
+			if params == nil {
+				params = make(map[string]interface{})
+			}
+			params[name] = value
+		}
+	}
+	return params
+}
+
 // GetResearchConfig builds the research configuration from session answers
 func (em *ElicitationManager) GetResearchConfig(sessionID string) *schemas.ResearchConfig {
 	em.mu.RLock()
@@ -223,7 +368,13 @@ func (em *ElicitationManager) GetResearchConfig(sessionID string) *schemas.Resea
 		TimeoutMinutes:  em.getIntAnswer(session, "timeout_minutes", 60),
 		PriorityLevel:   em.getStringAnswer(session, "priority_level", "normal"),
 		WorkflowTemplates: em.getStringAnswer(session, "workflow_templates", ""),
+		TemplateID:       em.getStringAnswer(session, "research_template", ""),
+		TemplateParams:   em.getTemplateParams(session),
 		SpecificSources:  em.getStringAnswer(session, "specific_sources", ""),
+		MaxCostUSD:       em.getFloatAnswer(session, "max_cost_usd", 0),
+		Regions:          em.getListAnswer(session, "regions"),
+		ServiceAccount:   em.getStringAnswer(session, "service_account", ""),
+		NotifyEmails:     em.getListAnswer(session, "notify_emails"),
 		CreatedAt:       session.StartTime,
 	}
 
@@ -249,6 +400,31 @@ func (em *ElicitationManager) getIntAnswer(session *ElicitationSession, key stri
 	return defaultValue
 }
 
+func (em *ElicitationManager) getFloatAnswer(session *ElicitationSession, key string, defaultValue float64) float64 {
+	if val, ok := session.Answers[key].(float64); ok {
+		return val
+	}
+	if val, ok := session.Answers[key].(int); ok {
+		return float64(val)
+	}
+	return defaultValue
+}
+
+// getListAnswer reads a comma-separated answer as a list
+func (em *ElicitationManager) getListAnswer(session *ElicitationSession, key string) []string {
+	val, ok := session.Answers[key].(string)
+	if !ok {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // cleanupOldSessions removes sessions older than 1 hour
 func (em *ElicitationManager) cleanupOldSessions() {
 	em.mu.Lock()
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/orchestrator"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+)
+
+// errShuttingDown rejects tool calls once shutdown has begun
+var errShuttingDown = mcperrors.New(mcperrors.CategoryUnavailable, "server is shutting down")
+
+// orchestratorErrors are the categories of the orchestrator's errors
+var orchestratorErrors = []struct {
+	err      error
+	category mcperrors.Category
+}{
+	{orchestrator.ErrDraining, mcperrors.CategoryUnavailable},
+	{orchestrator.ErrSimulated, mcperrors.CategoryInvalidInput},
+	{orchestrator.ErrSessionExists, mcperrors.CategoryConflict},
+	{orchestrator.ErrAPIKeyRequired, mcperrors.CategoryUnauthorized},
+	{orchestrator.ErrInvalidAPIKey, mcperrors.CategoryUnauthorized},
+	{orchestrator.ErrTenantQuota, mcperrors.CategoryQuotaExceeded},
+	{orchestrator.ErrTemplateNotFound, mcperrors.CategoryNotFound},
+	{orchestrator.ErrTemplateExists, mcperrors.CategoryConflict},
+	{orchestrator.ErrTemplateBuiltin, mcperrors.CategoryInvalidInput},
+	{orchestrator.ErrTemplateNotOwned, mcperrors.CategoryUnauthorized},
+	{orchestrator.ErrScheduleNotFound, mcperrors.CategoryNotFound},
+	{orchestrator.ErrScheduleAmbiguous, mcperrors.CategoryInvalidInput},
+}
+
+// classifyError returns err as an MCPError, classifying the orchestrator's errors by their cause
+func classifyError(err error) *mcperrors.MCPError {
+	var mcpErr *mcperrors.MCPError
+	if !errors.As(err, &mcpErr) {
+		for _, known := range orchestratorErrors {
+			if errors.Is(err, known.err) {
+				return mcperrors.New(known.category, "%w", err)
+			}
+		}
+	}
+	return mcperrors.Wrap(err)
+}
+
+// toolError returns a failed call of operation as a tool result whose text is
+// the JSON of its MCPError. The error is logged under the call's correlation
+// ID, which the result carries.
+func toolError(ctx context.Context, operation string, err error) *mcp.CallToolResult {
+	mcpErr := *classifyError(err)
+	mcpErr.CorrelationID = mcperrors.CorrelationID(ctx)
+	if mcpErr.CorrelationID == "" {
+		mcpErr.CorrelationID = mcperrors.NewCorrelationID()
+	}
+	if operation == "" {
+		operation = "tool call"
+	}
+	log.Printf("%s failed [%s %s]: %v", operation, mcpErr.Code, mcpErr.CorrelationID, err)
+	return withCorrelationID(mcp.NewToolResultError(mcpErr.JSON()), mcpErr.CorrelationID)
+}
+
+// withCorrelationID returns result with the correlation ID of its call in its _meta
+func withCorrelationID(result *mcp.CallToolResult, correlationID string) *mcp.CallToolResult {
+	if correlationID == "" {
+		return result
+	}
+	if result.Meta == nil {
+		result.Meta = make(map[string]interface{})
+	}
+	result.Meta[mcperrors.CorrelationAttribute] = correlationID
+	return result
+}
+
+// traceToolCalls gives each call of handler a correlation ID, which traces it
+// through the logs, its sessions' drones and their documents. A panic that no
+// operation recovered from, such as in elicitation, becomes an MCP-5004 error
+// result instead of ending the server.
+func traceToolCalls(handler mcpserver.ToolHandlerFunc) mcpserver.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
+		ctx = mcperrors.WithCorrelationID(ctx, mcperrors.NewCorrelationID())
+		defer func() {
+			if r := recover(); r != nil {
+				name := request.Params.Name
+				result, err = toolError(ctx, name, mcperrors.Recover(ctx, r, "tool "+name)), nil
+			}
+		}()
+		return handler(ctx, request)
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/orchestrator"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+// interimLogger names the logging notifications that carry interim findings
+const interimLogger = "widescreen-research/interim"
+
+// interimNotifier sends each drone's findings to the client that called the
+// tool as they are collected: as a progress notification when the call asked
+// for progress, and always as a logging notification carrying the findings
+func (s *WidescreenResearchServer) interimNotifier(ctx context.Context, request mcp.CallToolRequest) orchestrator.ResultListener {
+	var progressToken mcp.ProgressToken
+	if request.Params.Meta != nil {
+		progressToken = request.Params.Meta.ProgressToken
+	}
+
+	return func(result schemas.InterimResult) {
+		// Notifications are dropped when the client is gone or not keeping up;
+		// the findings are still in the report and the interim resource
+		if progressToken != nil {
+			_ = s.server.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+				"progressToken": progressToken,
+				"progress":      result.Collected,
+				"total":         result.Expected,
+				"message":       interimMessage(result),
+			})
+		}
+		_ = s.server.SendNotificationToClient(ctx, "notifications/message", map[string]any{
+			"level":  mcp.LoggingLevelInfo,
+			"logger": interimLogger,
+			"data":   result,
+		})
+	}
+}
+
+// interimMessage describes an interim result in one line
+func interimMessage(result schemas.InterimResult) string {
+	if result.Status != "completed" {
+		return fmt.Sprintf("Drone %s %s: %s", result.DroneID, result.Status, result.Error)
+	}
+	if result.Summary != "" {
+		return fmt.Sprintf("Drone %s: %s", result.DroneID, result.Summary)
+	}
+	return fmt.Sprintf("Drone %s reported %d findings", result.DroneID, len(result.KeyFindings))
+}
+
+// registerInterimResource registers the resource of each session's findings so far
+func (s *WidescreenResearchServer) registerInterimResource() {
+	template := mcp.NewResourceTemplate("research://sessions/{session_id}/interim", "Interim Findings",
+		mcp.WithTemplateDescription("Each drone's findings in a session, as they are collected and before the report is ready"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.server.AddResourceTemplate(template, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		var sessionID string
+		switch value := request.Params.Arguments["session_id"].(type) {
+		case string:
+			sessionID = value
+		case []string:
+			if len(value) > 0 {
+				sessionID = value[0]
+			}
+		}
+		if sessionID == "" {
+			return nil, fmt.Errorf("session_id is required")
+		}
+
+		// Resource reads carry no API key, so they see the default tenant's sessions
+		if _, err := s.orchestrator.ResolveTenant(""); err != nil {
+			return nil, fmt.Errorf("sessions are kept per tenant: %w", err)
+		}
+		interim, err := s.orchestrator.InterimResults(ctx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		return jsonResourceContents(request.Params.URI, interim)
+	})
+}
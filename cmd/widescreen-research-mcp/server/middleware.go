@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/operations"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/orchestrator"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+)
+
+// defaultOperationTimeout bounds operations that do not set a timeout of their own
+const defaultOperationTimeout = 5 * time.Minute
+
+// loadOperationTimeout reads the default operation timeout from
+// OPERATION_TIMEOUT; "0" turns the default off
+func loadOperationTimeout() time.Duration {
+	value := os.Getenv("OPERATION_TIMEOUT")
+	if value == "" {
+		return defaultOperationTimeout
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return defaultOperationTimeout
+	}
+	return timeout
+}
+
+// useOperationMiddleware sets up the chain every operation call runs through:
+// metrics and logging see every call, including ones that panicked, then calls
+// are authorized and validated before the handler runs under its timeout
+func (s *WidescreenResearchServer) useOperationMiddleware() {
+	s.operations.Use(
+		operations.MetricsMiddleware(s.usage),
+		operations.LoggingMiddleware(),
+		operations.RecoveryMiddleware(),
+		s.authMiddleware(),
+		operations.ValidationMiddleware(),
+		operations.TimeoutMiddleware(loadOperationTimeout()),
+	)
+}
+
+// authMiddleware rejects calls without a resolved tenant, so no operation can
+// reach another tenant's data, and calls made once shutdown has begun
+func (s *WidescreenResearchServer) authMiddleware() operations.Middleware {
+	return func(operation *operations.Operation, next operations.OperationHandler) operations.OperationHandler {
+		return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			if s.draining.Load() {
+				return nil, errShuttingDown
+			}
+			if _, ok := orchestrator.TenantFromContext(ctx); !ok {
+				return nil, mcperrors.New(mcperrors.CategoryUnauthorized, "operation %s requires a tenant", operation.Name)
+			}
+			return next(ctx, params)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+	"golang.org/x/time/rate"
+)
+
+// callerIdleTTL is how long an idle caller's limits are kept before they are forgotten
+const callerIdleTTL = 10 * time.Minute
+
+// costlyOperations deploy drones or provision cloud resources, so they have a limit of their own
+var costlyOperations = map[string]bool{
+	"orchestrate-research": true,
+	"gcp-provision":        true,
+}
+
+// RateLimitConfig limits tool calls, in calls per minute; zero disables a limit
+type RateLimitConfig struct {
+	// PerCaller limits each caller's calls of any operation
+	PerCaller int
+	// PerCallerCostly limits each caller's orchestrate-research and gcp-provision calls
+	PerCallerCostly int
+	// Global limits the calls of all callers together
+	Global int
+}
+
+// loadRateLimitConfig reads the rate limits from the environment
+func loadRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		PerCaller:       envPerMinute("RATE_LIMIT_PER_MINUTE", 60),
+		PerCallerCostly: envPerMinute("RATE_LIMIT_COSTLY_PER_MINUTE", 5),
+		Global:          envPerMinute("RATE_LIMIT_GLOBAL_PER_MINUTE", 300),
+	}
+}
+
+// envPerMinute reads a calls-per-minute limit, falling back to def when it is unset or invalid
+func envPerMinute(name string, def int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Printf("Warning: Ignoring invalid %s %q", name, value)
+		return def
+	}
+	return n
+}
+
+// rateLimiter enforces RateLimitConfig with token buckets that refill over a
+// minute, so a caller may burst up to its whole per-minute limit
+type rateLimiter struct {
+	config RateLimitConfig
+	global *rate.Limiter
+
+	mu      sync.Mutex
+	callers map[string]*callerLimits
+}
+
+// callerLimits are one caller's token buckets
+type callerLimits struct {
+	calls    *rate.Limiter
+	costly   *rate.Limiter
+	lastSeen time.Time
+}
+
+// newRateLimiter creates a rate limiter, or returns nil when every limit is disabled
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	if config.PerCaller == 0 && config.PerCallerCostly == 0 && config.Global == 0 {
+		return nil
+	}
+	return &rateLimiter{
+		config:  config,
+		global:  perMinuteLimiter(config.Global),
+		callers: make(map[string]*callerLimits),
+	}
+}
+
+// perMinuteLimiter returns a token bucket of n calls per minute, or nil when n is zero
+func perMinuteLimiter(n int) *rate.Limiter {
+	if n == 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(float64(n)/60), n)
+}
+
+// rateLimitError reports a rate limited call and when it may be retried
+type rateLimitError struct {
+	// Scope is the limit that was hit: caller, costly or global
+	Scope      string
+	Operation  string
+	RetryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	limit := "your calls"
+	switch e.Scope {
+	case "costly":
+		limit = "your " + e.Operation + " calls"
+	case "global":
+		limit = "calls from all callers"
+	}
+	return fmt.Sprintf("rate limit exceeded for %s, retry %s after %ds", limit, e.Operation, e.retryAfterSeconds())
+}
+
+// retryAfterSeconds rounds RetryAfter up, so a retry after it is allowed
+func (e *rateLimitError) retryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// mcpError returns the error with the wait an agent needs to back off
+func (e *rateLimitError) mcpError() *mcperrors.MCPError {
+	err := mcperrors.New(mcperrors.CategoryRateLimited, "%s", e.Error())
+	err.RetryAfter = e.retryAfterSeconds()
+	err.Details = map[string]interface{}{
+		"operation": e.Operation,
+		"scope":     e.Scope,
+	}
+	return err
+}
+
+// allow takes a token from each bucket a call of operation by caller is
+// limited by, or takes none and returns the error of the bucket to wait longest for.
+// It returns nil when the call is allowed.
+func (l *rateLimiter) allow(caller, operation string) *rateLimitError {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limits := l.callerLimits(caller, now)
+
+	type bucket struct {
+		scope   string
+		limiter *rate.Limiter
+	}
+	buckets := []bucket{{"caller", limits.calls}, {"global", l.global}}
+	if costlyOperations[operation] {
+		buckets = append(buckets, bucket{"costly", limits.costly})
+	}
+
+	var reservations []*rate.Reservation
+	var limited *rateLimitError
+	for _, b := range buckets {
+		if b.limiter == nil {
+			continue
+		}
+		reservation := b.limiter.ReserveN(now, 1)
+		reservations = append(reservations, reservation)
+		if delay := reservation.DelayFrom(now); delay > 0 && (limited == nil || delay > limited.RetryAfter) {
+			limited = &rateLimitError{Scope: b.scope, Operation: operation, RetryAfter: delay}
+		}
+	}
+	if limited != nil {
+		// Return the tokens, so rejected calls do not push back the next allowed one
+		for _, reservation := range reservations {
+			reservation.CancelAt(now)
+		}
+		return limited
+	}
+	return nil
+}
+
+// callerLimits returns a caller's buckets, creating them on its first call and
+// forgetting callers that have gone idle. The caller holds l.mu.
+func (l *rateLimiter) callerLimits(caller string, now time.Time) *callerLimits {
+	limits := l.callers[caller]
+	if limits == nil {
+		for id, idle := range l.callers {
+			if now.Sub(idle.lastSeen) > callerIdleTTL {
+				delete(l.callers, id)
+			}
+		}
+		limits = &callerLimits{
+			calls:  perMinuteLimiter(l.config.PerCaller),
+			costly: perMinuteLimiter(l.config.PerCallerCostly),
+		}
+		l.callers[caller] = limits
+	}
+	limits.lastSeen = now
+	return limits
+}
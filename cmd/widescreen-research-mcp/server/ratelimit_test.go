@@ -0,0 +1,158 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewRateLimiterDisabled(t *testing.T) {
+	if l := newRateLimiter(RateLimitConfig{}); l != nil {
+		t.Errorf("newRateLimiter() with no limits = %v, want nil", l)
+	}
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	type call struct {
+		caller, operation string
+		// wantScope is the limit the call hits, or empty when it is allowed
+		wantScope string
+	}
+	tests := []struct {
+		name   string
+		config RateLimitConfig
+		calls  []call
+	}{
+		{
+			name:   "caller bursts up to its limit",
+			config: RateLimitConfig{PerCaller: 3},
+			calls: []call{
+				{"a", "list-templates", ""},
+				{"a", "list-templates", ""},
+				{"a", "list-templates", ""},
+				{"a", "list-templates", "caller"},
+				{"b", "list-templates", ""},
+			},
+		},
+		{
+			name:   "global limit spans callers",
+			config: RateLimitConfig{Global: 2},
+			calls: []call{
+				{"a", "list-templates", ""},
+				{"b", "list-templates", ""},
+				{"c", "list-templates", "global"},
+			},
+		},
+		{
+			name:   "costly limit only applies to costly operations",
+			config: RateLimitConfig{PerCaller: 10, PerCallerCostly: 1},
+			calls: []call{
+				{"a", "orchestrate-research", ""},
+				{"a", "orchestrate-research", "costly"},
+				{"a", "gcp-provision", "costly"},
+				{"a", "list-templates", ""},
+				{"b", "gcp-provision", ""},
+			},
+		},
+		{
+			name:   "costly limit reported while the global limit has tokens",
+			config: RateLimitConfig{PerCallerCostly: 1, Global: 10},
+			calls: []call{
+				{"a", "orchestrate-research", ""},
+				{"a", "orchestrate-research", "costly"},
+			},
+		},
+		{
+			name:   "global limit reported while the costly limit has tokens",
+			config: RateLimitConfig{PerCallerCostly: 10, Global: 1},
+			calls: []call{
+				{"a", "orchestrate-research", ""},
+				{"a", "orchestrate-research", "global"},
+			},
+		},
+		{
+			// Both are empty; a costly token refills in a minute and a global one in 30s
+			name:   "longest wait wins when several limits are hit",
+			config: RateLimitConfig{PerCallerCostly: 1, Global: 2},
+			calls: []call{
+				{"a", "orchestrate-research", ""},
+				{"b", "list-templates", ""},
+				{"a", "orchestrate-research", "costly"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRateLimiter(tt.config)
+			for i, c := range tt.calls {
+				err := l.allow(c.caller, c.operation)
+				switch {
+				case c.wantScope == "" && err != nil:
+					t.Fatalf("call %d (%s %s) was limited: %v", i, c.caller, c.operation, err)
+				case c.wantScope != "" && err == nil:
+					t.Fatalf("call %d (%s %s) was allowed, want the %s limit", i, c.caller, c.operation, c.wantScope)
+				case err != nil && err.Scope != c.wantScope:
+					t.Fatalf("call %d (%s %s) hit the %s limit, want %s", i, c.caller, c.operation, err.Scope, c.wantScope)
+				case err != nil && err.RetryAfter <= 0:
+					t.Errorf("call %d (%s %s) has RetryAfter %v, want a positive wait", i, c.caller, c.operation, err.RetryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterRejectedCallsKeepTokens(t *testing.T) {
+	l := newRateLimiter(RateLimitConfig{PerCaller: 5, PerCallerCostly: 5, Global: 1})
+	if err := l.allow("a", "orchestrate-research"); err != nil {
+		t.Fatalf("first call was limited: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := l.allow("a", "orchestrate-research"); err == nil || err.Scope != "global" {
+			t.Fatalf("call %d error = %v, want the global limit", i+2, err)
+		}
+	}
+
+	limits := l.callers["a"]
+	now := time.Now()
+	for scope, limiter := range map[string]interface{ TokensAt(time.Time) float64 }{
+		"caller": limits.calls,
+		"costly": limits.costly,
+	} {
+		// One token was taken by the allowed call; refill since then is well under one more
+		if tokens := limiter.TokensAt(now); tokens < 3.9 || tokens > 4.5 {
+			t.Errorf("%s bucket has %.2f tokens after rejected calls, want about 4", scope, tokens)
+		}
+	}
+}
+
+func TestRateLimiterEvictsIdleCallers(t *testing.T) {
+	l := newRateLimiter(RateLimitConfig{PerCaller: 1})
+	for _, caller := range []string{"idle", "active"} {
+		if err := l.allow(caller, "list-templates"); err != nil {
+			t.Fatalf("first call by %s was limited: %v", caller, err)
+		}
+	}
+	l.callers["idle"].lastSeen = time.Now().Add(-callerIdleTTL - time.Minute)
+
+	// Only a new caller triggers eviction
+	if err := l.allow("active", "list-templates"); err == nil {
+		t.Fatal("second call by active was allowed, want the caller limit")
+	}
+	if _, ok := l.callers["idle"]; !ok {
+		t.Fatal("idle caller was evicted by a known caller's call")
+	}
+
+	if err := l.allow("new", "list-templates"); err != nil {
+		t.Fatalf("first call by new was limited: %v", err)
+	}
+	if _, ok := l.callers["idle"]; ok {
+		t.Error("idle caller was not evicted")
+	}
+	if _, ok := l.callers["active"]; !ok {
+		t.Error("active caller was evicted")
+	}
+
+	// An evicted caller starts again with a full bucket
+	if err := l.allow("idle", "list-templates"); err != nil {
+		t.Errorf("call by evicted caller was limited: %v", err)
+	}
+}
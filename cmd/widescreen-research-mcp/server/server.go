@@ -5,33 +5,54 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"sort"
+	"sync/atomic"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
 	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/operations"
 	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/orchestrator"
 	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/doctor"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+	"github.com/spawn-mcp/coordinator/pkg/usage"
+	"github.com/spawn-mcp/coordinator/pkg/version"
 )
 
 // WidescreenResearchServer is the main MCP server that provides widescreen research capabilities
 type WidescreenResearchServer struct {
-	server       *mcp.Server
+	server       *mcpserver.MCPServer
 	orchestrator *orchestrator.Orchestrator
 	operations   *operations.OperationRegistry
 	elicitation  *ElicitationManager
+
+	// Calls, errors and latencies of each operation
+	usage *usage.Recorder
+
+	// Serves Cloud Scheduler and Pub/Sub schedule triggers; nil when disabled
+	trigger *http.Server
+
+	// Limits tool calls per caller and overall; nil when disabled
+	limiter *rateLimiter
+
+	// draining rejects tool calls once shutdown has begun
+	draining atomic.Bool
 }
 
 // NewWidescreenResearchServer creates a new instance of the widescreen research server
 func NewWidescreenResearchServer() (*WidescreenResearchServer, error) {
 	// Create MCP server
-	mcpServer := mcp.NewServer(
+	mcpServer := mcpserver.NewMCPServer(
 		"widescreen-research",
-		"1.0.0",
-		mcp.WithCapabilities([]string{
-			"tools",
-			"prompts",
-			"resources",
-			"experimental/elicitation",
-		}),
+		version.Version,
+		mcpserver.WithToolCapabilities(true),
+		mcpserver.WithResourceCapabilities(false, false),
+		mcpserver.WithPromptCapabilities(false),
+		mcpserver.WithLogging(),
 	)
 
 	// Create orchestrator
@@ -44,14 +65,17 @@ func NewWidescreenResearchServer() (*WidescreenResearchServer, error) {
 	opRegistry := operations.NewOperationRegistry()
 
 	// Create elicitation manager
-	elicitManager := NewElicitationManager()
+	elicitManager := NewElicitationManager(orch.GetTemplates)
 
 	srv := &WidescreenResearchServer{
 		server:       mcpServer,
 		orchestrator: orch,
 		operations:   opRegistry,
 		elicitation:  elicitManager,
+		usage:        usage.NewRecorder(),
+		limiter:      newRateLimiter(loadRateLimitConfig()),
 	}
+	srv.useOperationMiddleware()
 
 	// Register the main widescreen-research tool
 	srv.registerWidescreenResearchTool()
@@ -70,25 +94,106 @@ func NewWidescreenResearchServer() (*WidescreenResearchServer, error) {
 
 // registerWidescreenResearchTool registers the main tool that handles all operations
 func (s *WidescreenResearchServer) registerWidescreenResearchTool() {
-	s.server.RegisterTool("widescreen-research", mcp.Tool{
-		Description: "Perform comprehensive widescreen research using distributed research drones",
-		InputSchema: schemas.WidescreenResearchInput{},
-		Handler: func(ctx context.Context, request interface{}) (interface{}, error) {
-			input, ok := request.(*schemas.WidescreenResearchInput)
-			if !ok {
-				return nil, fmt.Errorf("invalid input type")
-			}
+	tool := mcp.NewTool("widescreen-research",
+		mcp.WithDescription("Perform comprehensive widescreen research using distributed research drones"),
+		mcp.WithString("operation",
+			mcp.Description("Operation to execute; omit or use 'start' to begin elicitation"),
+		),
+		mcp.WithString("session_id",
+			mcp.Description("Elicitation/research session ID"),
+		),
+		mcp.WithObject("elicitation_answers",
+			mcp.Description("Answers to the previous elicitation questions, keyed by question ID"),
+		),
+		mcp.WithObject("parameters",
+			mcp.Description("Operation-specific parameters"),
+		),
+		mcp.WithString("api_key",
+			mcp.Description("API key of your team's tenant, when the server is shared by several teams"),
+		),
+	)
 
-			// Check if we need elicitation
-			if input.Operation == "" || input.Operation == "start" {
-				// Start elicitation process
-				return s.handleElicitation(ctx, input)
+	s.server.AddTool(tool, traceToolCalls(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if s.draining.Load() {
+			return toolError(ctx, "", errShuttingDown), nil
+		}
+		input, err := decodeInput(request)
+		if err != nil {
+			return toolError(ctx, "", mcperrors.InvalidInput("invalid input: %w", err)), nil
+		}
+		// Every operation sees only the sessions, reports and schedules of the caller's tenant
+		tenantID, err := s.orchestrator.ResolveTenant(input.APIKey)
+		if err != nil {
+			return toolError(ctx, operationName(input), err), nil
+		}
+		ctx = orchestrator.WithTenant(ctx, tenantID)
+		// Sessions started by this call send their findings as drones report them
+		ctx = orchestrator.WithResultListener(ctx, s.interimNotifier(ctx, request))
+
+		// Stop runaway agent loops before they reach the orchestrator or GCP
+		if s.limiter != nil {
+			if limited := s.limiter.allow(callerID(ctx, tenantID), operationName(input)); limited != nil {
+				return toolError(ctx, limited.Operation, limited.mcpError()), nil
 			}
-
+		}
+
+		var result interface{}
+		// Check if we need elicitation
+		if input.Operation == "" || input.Operation == "start" {
+			// Start elicitation process
+			start := time.Now()
+			result, err = s.handleElicitation(ctx, input)
+			s.usage.Record("start", start, err)
+		} else {
 			// Execute the requested operation
-			return s.executeOperation(ctx, input)
-		},
-	})
+			result, err = s.executeOperation(ctx, input)
+		}
+		if err != nil {
+			return toolError(ctx, operationName(input), err), nil
+		}
+
+		return toolResultJSON(ctx, result)
+	}))
+}
+
+// callerID identifies a caller for rate limiting by its tenant and MCP client session
+func callerID(ctx context.Context, tenantID string) string {
+	if session := mcpserver.ClientSessionFromContext(ctx); session != nil {
+		return tenantID + "/" + session.SessionID()
+	}
+	return tenantID
+}
+
+// operationName returns the operation a tool call runs, naming elicitation "start"
+func operationName(input *schemas.WidescreenResearchInput) string {
+	if input.Operation == "" {
+		return "start"
+	}
+	return input.Operation
+}
+
+// decodeInput converts raw tool arguments into a WidescreenResearchInput
+func decodeInput(request mcp.CallToolRequest) (*schemas.WidescreenResearchInput, error) {
+	raw, err := json.Marshal(request.GetArguments())
+	if err != nil {
+		return nil, err
+	}
+
+	var input schemas.WidescreenResearchInput
+	if err := json.Unmarshal(raw, &input); err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+// toolResultJSON serializes an operation result as the text content of a tool
+// result, with the call's correlation ID in its _meta
+func toolResultJSON(ctx context.Context, result interface{}) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolError(ctx, "", fmt.Errorf("failed to encode result: %w", err)), nil
+	}
+	return withCorrelationID(mcp.NewToolResultText(string(data)), mcperrors.CorrelationID(ctx)), nil
 }
 
 // handleElicitation manages the elicitation process
@@ -127,26 +232,17 @@ func (s *WidescreenResearchServer) handleElicitation(ctx context.Context, input
 	}, nil
 }
 
-// executeOperation executes the requested operation
+// executeOperation executes the requested operation through the registry's
+// middleware, passing the tool call's session ID as the "session_id" parameter
 func (s *WidescreenResearchServer) executeOperation(ctx context.Context, input *schemas.WidescreenResearchInput) (interface{}, error) {
-	operation := s.operations.GetOperation(input.Operation)
-	if operation == nil {
-		return nil, fmt.Errorf("unknown operation: %s", input.Operation)
+	params := make(map[string]interface{}, len(input.Parameters)+1)
+	for name, value := range input.Parameters {
+		params[name] = value
 	}
-
-	// Execute operation based on type
-	switch input.Operation {
-	case "orchestrate-research":
-		return s.handleOrchestrateResearch(ctx, input)
-	case "sequential-thinking":
-		return s.handleSequentialThinking(ctx, input)
-	case "gcp-provision":
-		return s.handleGCPProvision(ctx, input)
-	case "analyze-findings":
-		return s.handleAnalyzeFindings(ctx, input)
-	default:
-		return operation.Execute(ctx, input.Parameters)
+	if input.SessionID != "" {
+		params["session_id"] = input.SessionID
 	}
+	return s.operations.Execute(ctx, input.Operation, params)
 }
 
 // handleOrchestrateResearch handles the main research orchestration
@@ -154,7 +250,33 @@ func (s *WidescreenResearchServer) handleOrchestrateResearch(ctx context.Context
 	// Get research configuration from elicitation
 	config := s.elicitation.GetResearchConfig(input.SessionID)
 	if config == nil {
-		return nil, fmt.Errorf("no research configuration found for session")
+		return nil, mcperrors.New(mcperrors.CategoryNotFound, "no research configuration found for session")
+	}
+
+	// Run the sub-queries of an approved plan
+	if raw, ok := input.Parameters["sub_queries"]; ok {
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return nil, mcperrors.InvalidInput("invalid sub_queries: %w", err)
+		}
+		if err := json.Unmarshal(data, &config.SubQueries); err != nil {
+			return nil, mcperrors.InvalidInput("invalid sub_queries: %w", err)
+		}
+	}
+
+	// Exercise the pipeline with synthetic drone results and no cloud spend
+	if simulate, _ := input.Parameters["simulate"].(bool); simulate {
+		config.Simulate = true
+	}
+
+	// Research the topic from scratch, ignoring what earlier sessions found
+	if noMemory, _ := input.Parameters["no_memory"].(bool); noMemory {
+		config.NoMemory = true
+	}
+
+	// Stop at the plan so it can be approved before anything is provisioned
+	if planOnly, _ := input.Parameters["plan_only"].(bool); planOnly {
+		return s.orchestrator.PlanResearch(ctx, config)
 	}
 
 	// Start orchestration
@@ -175,12 +297,14 @@ func (s *WidescreenResearchServer) handleSequentialThinking(ctx context.Context,
 // handleGCPProvision handles GCP resource provisioning
 func (s *WidescreenResearchServer) handleGCPProvision(ctx context.Context, input *schemas.WidescreenResearchInput) (interface{}, error) {
 	provisioner := operations.NewGCPProvisioner()
+	provisioner.Tenant, _ = orchestrator.TenantFromContext(ctx)
 	return provisioner.Execute(ctx, input.Parameters)
 }
 
 // handleAnalyzeFindings handles data analysis of research findings
 func (s *WidescreenResearchServer) handleAnalyzeFindings(ctx context.Context, input *schemas.WidescreenResearchInput) (interface{}, error) {
 	analyzer := operations.NewDataAnalyzer()
+	analyzer.SessionsCollection = s.orchestrator.SessionsCollection(ctx)
 	return analyzer.Execute(ctx, input.Parameters)
 }
 
@@ -190,100 +314,653 @@ func (s *WidescreenResearchServer) registerOperations() {
 	s.operations.Register("orchestrate-research", &operations.Operation{
 		Name:        "orchestrate-research",
 		Description: "Orchestrate distributed research using multiple drones",
-		Handler:     s.handleOrchestrateResearch,
+		Handler:     s.operationHandler("orchestrate-research", s.handleOrchestrateResearch),
+		// Sessions are bounded by their own timeout and budget
+		Timeout: -1,
 	})
 
 	s.operations.Register("sequential-thinking", &operations.Operation{
 		Name:        "sequential-thinking",
 		Description: "Perform sequential thinking style reasoning",
-		Handler:     s.handleSequentialThinking,
+		Handler:     s.operationHandler("sequential-thinking", s.handleSequentialThinking),
 	})
 
 	s.operations.Register("gcp-provision", &operations.Operation{
 		Name:        "gcp-provision",
 		Description: "Provision GCP resources for research",
-		Handler:     s.handleGCPProvision,
+		Handler:     s.operationHandler("gcp-provision", s.handleGCPProvision),
+		Timeout:     15 * time.Minute,
 	})
 
 	s.operations.Register("analyze-findings", &operations.Operation{
 		Name:        "analyze-findings",
 		Description: "Analyze research findings from drones",
-		Handler:     s.handleAnalyzeFindings,
+		Handler:     s.operationHandler("analyze-findings", s.handleAnalyzeFindings),
+	})
+
+	s.operations.Register("list-external-tools", &operations.Operation{
+		Name:        "list-external-tools",
+		Description: "List tools discovered on connected external MCP servers",
+		Handler:     s.handleListExternalTools,
+	})
+
+	s.operations.Register("estimate-research-cost", &operations.Operation{
+		Name:        "estimate-research-cost",
+		Description: "Estimate the drones, time and cost of a research config without provisioning anything",
+		Handler:     s.operationHandler("estimate-research-cost", s.handleEstimateResearchCost),
+	})
+
+	s.operations.Register("replay-session", &operations.Operation{
+		Name:           "replay-session",
+		Description:    "Analyze a past session's stored drone results again and write a new report, optionally with another template or model, without running drones",
+		Handler:        s.operationHandler("replay-session", s.handleReplaySession),
+		RequiredParams: []string{"session_id"},
+		Timeout:        15 * time.Minute,
+	})
+
+	s.operations.Register("export-session", &operations.Operation{
+		Name:           "export-session",
+		Description:    "Bundle a session's config, drone results, report and metrics into a snapshot that import-session can load elsewhere",
+		Handler:        s.operationHandler("export-session", s.handleExportSession),
+		RequiredParams: []string{"session_id"},
+	})
+
+	s.operations.Register("export-graph", &operations.Operation{
+		Name:           "export-graph",
+		Description:    "Export a session's knowledge graph of entities, relations and their sources as json, graphml or dot, with its coverage gaps and follow-up sub-queries",
+		Handler:        s.operationHandler("export-graph", s.handleExportGraph),
+		RequiredParams: []string{"session_id"},
+	})
+
+	s.operations.Register("import-session", &operations.Operation{
+		Name:           "import-session",
+		Description:    "Store a session snapshot from export-session, so it can be inspected and replayed in this environment",
+		Handler:        s.handleImportSession,
+		RequiredParams: []string{"snapshot"},
+	})
+
+	s.operations.Register("purge-session-data", &operations.Operation{
+		Name:           "purge-session-data",
+		Description:    "Delete every artifact stored for a session, its Firestore documents, Cloud Storage objects and Pub/Sub topics, and list what was deleted",
+		Handler:        s.operationHandler("purge-session-data", s.handlePurgeSessionData),
+		RequiredParams: []string{"session_id"},
+		Timeout:        10 * time.Minute,
+	})
+
+	s.operations.Register("search-findings", &operations.Operation{
+		Name:           "search-findings",
+		Description:    "Search the findings and webset items of every past session by meaning, to see what was already found about a subject",
+		Handler:        s.handleSearchFindings,
+		RequiredParams: []string{"query"},
+	})
+
+	s.operations.Register("search-reports", &operations.Operation{
+		Name:           "search-reports",
+		Description:    "Search the sections of past reports by meaning, with links, to check whether a topic was already researched before launching a new session",
+		Handler:        s.handleSearchReports,
+		RequiredParams: []string{"query"},
+	})
+
+	s.operations.Register("list-templates", &operations.Operation{
+		Name:        "list-templates",
+		Description: "List the built-in and saved research templates",
+		Handler:     s.handleListTemplates,
+	})
+
+	s.operations.Register("create-template", &operations.Operation{
+		Name:           "create-template",
+		Description:    "Save a research template for the team to reuse",
+		Handler:        s.handleCreateTemplate,
+		RequiredParams: []string{"template"},
 	})
+
+	s.operations.Register("update-template", &operations.Operation{
+		Name:           "update-template",
+		Description:    "Change the name, description or workflow of a saved research template",
+		Handler:        s.handleUpdateTemplate,
+		RequiredParams: []string{"template"},
+	})
+
+	s.operations.Register("instantiate-template", &operations.Operation{
+		Name:           "instantiate-template",
+		Description:    "Preview a template's workflow with parameter values substituted",
+		Handler:        s.handleInstantiateTemplate,
+		RequiredParams: []string{"template_id"},
+	})
+
+	s.operations.Register("delete-template", &operations.Operation{
+		Name:           "delete-template",
+		Description:    "Delete a saved research template",
+		Handler:        s.handleDeleteTemplate,
+		RequiredParams: []string{"template_id"},
+	})
+
+	s.operations.Register("create-schedule", &operations.Operation{
+		Name:           "create-schedule",
+		Description:    "Re-run a research config on a cron schedule, diffing each report against the previous run",
+		Handler:        s.operationHandler("create-schedule", s.handleCreateSchedule),
+		RequiredParams: []string{"cron"},
+	})
+
+	s.operations.Register("list-schedules", &operations.Operation{
+		Name:        "list-schedules",
+		Description: "List saved research schedules",
+		Handler:     s.handleListSchedules,
+	})
+
+	s.operations.Register("delete-schedule", &operations.Operation{
+		Name:           "delete-schedule",
+		Description:    "Delete a research schedule",
+		Handler:        s.handleDeleteSchedule,
+		RequiredParams: []string{"schedule_id"},
+	})
+
+	s.operations.Register("run-schedule", &operations.Operation{
+		Name:           "run-schedule",
+		Description:    "Run a research schedule now and report what changed since its previous run",
+		Handler:        s.handleRunSchedule,
+		RequiredParams: []string{"schedule_id"},
+		// A run is a whole research session, bounded by its own timeout
+		Timeout: -1,
+	})
+
+	s.operations.Register("get-drone-logs", &operations.Operation{
+		Name:           "get-drone-logs",
+		Description:    "Fetch a drone's recent Cloud Logging entries, optionally limited to a session and time range",
+		Handler:        s.handleGetDroneLogs,
+		RequiredParams: []string{"drone_id"},
+	})
+
+	s.operations.Register("doctor", &operations.Operation{
+		Name:        "doctor",
+		Description: "Check credentials, enabled APIs, IAM permissions, drone images and API keys, with steps to fix what would make research fail",
+		Handler:     s.handleDoctor,
+	})
+}
+
+// operationHandler adapts a tool-input handler to the registry's parameter-based signature
+func (s *WidescreenResearchServer) operationHandler(name string, handler func(context.Context, *schemas.WidescreenResearchInput) (interface{}, error)) operations.OperationHandler {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		input := &schemas.WidescreenResearchInput{
+			Operation:  name,
+			Parameters: params,
+		}
+		if sessionID, ok := params["session_id"].(string); ok {
+			input.SessionID = sessionID
+		}
+		return handler(ctx, input)
+	}
+}
+
+// handleListExternalTools lists the tools available on external MCP servers
+func (s *WidescreenResearchServer) handleListExternalTools(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	serverName := ""
+	if name, ok := params["server"].(string); ok {
+		serverName = name
+	}
+
+	tools, err := s.orchestrator.ListExternalTools(serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"tools": tools,
+		"count": len(tools),
+	}, nil
+}
+
+// handleEstimateResearchCost returns a dry-run plan and estimate for a research config,
+// given directly in the "config" parameter or from a completed elicitation session
+func (s *WidescreenResearchServer) handleEstimateResearchCost(ctx context.Context, input *schemas.WidescreenResearchInput) (interface{}, error) {
+	config, err := s.researchConfigFromInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.orchestrator.EstimateResearch(ctx, config)
+}
+
+// handleReplaySession re-reports a past session from its stored results
+func (s *WidescreenResearchServer) handleReplaySession(ctx context.Context, input *schemas.WidescreenResearchInput) (interface{}, error) {
+	if input.SessionID == "" {
+		return nil, mcperrors.InvalidInput("session_id is required")
+	}
+	options := orchestrator.ReplayOptions{}
+	options.TemplateID, _ = input.Parameters["template_id"].(string)
+	options.TemplateParams, _ = input.Parameters["template_params"].(map[string]interface{})
+	options.Model, _ = input.Parameters["model"].(string)
+
+	return s.orchestrator.ReplaySession(ctx, input.SessionID, options)
+}
+
+// handleExportSession returns the snapshot of a stored session
+func (s *WidescreenResearchServer) handleExportSession(ctx context.Context, input *schemas.WidescreenResearchInput) (interface{}, error) {
+	if input.SessionID == "" {
+		return nil, mcperrors.InvalidInput("session_id is required")
+	}
+	return s.orchestrator.ExportSession(ctx, input.SessionID)
+}
+
+// handleExportGraph returns a session's knowledge graph in the "format" parameter
+func (s *WidescreenResearchServer) handleExportGraph(ctx context.Context, input *schemas.WidescreenResearchInput) (interface{}, error) {
+	if input.SessionID == "" {
+		return nil, mcperrors.InvalidInput("session_id is required")
+	}
+	format, _ := input.Parameters["format"].(string)
+	return s.orchestrator.ExportGraph(ctx, input.SessionID, format)
+}
+
+// handleImportSession stores the session in the "snapshot" parameter, replacing
+// a stored session with the same ID only when "overwrite" is set
+func (s *WidescreenResearchServer) handleImportSession(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	raw, ok := params["snapshot"]
+	if !ok {
+		return nil, mcperrors.InvalidInput("snapshot is required")
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, mcperrors.InvalidInput("invalid snapshot: %w", err)
+	}
+	snapshot := &schemas.SessionSnapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, mcperrors.InvalidInput("invalid snapshot: %w", err)
+	}
+	overwrite, _ := params["overwrite"].(bool)
+
+	if err := s.orchestrator.ImportSession(ctx, snapshot, overwrite); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"session_id": snapshot.SessionID,
+		"results":    len(snapshot.Results),
+		"imported":   true,
+	}, nil
+}
+
+// handlePurgeSessionData deletes a stored session and returns the manifest of what was deleted
+func (s *WidescreenResearchServer) handlePurgeSessionData(ctx context.Context, input *schemas.WidescreenResearchInput) (interface{}, error) {
+	if input.SessionID == "" {
+		return nil, mcperrors.InvalidInput("session_id is required")
+	}
+	return s.orchestrator.PurgeSessionData(ctx, input.SessionID)
+}
+
+// handleSearchFindings returns the past findings closest in meaning to the "query"
+// parameter, at most "limit" of them
+func (s *WidescreenResearchServer) handleSearchFindings(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	query, _ := params["query"].(string)
+	limit := 0
+	if value, ok := params["limit"].(float64); ok {
+		limit = int(value)
+	}
+	return s.orchestrator.SearchFindings(ctx, query, limit)
+}
+
+// handleSearchReports returns the past report sections closest in meaning to
+// the "query" parameter, at most "limit" of them
+func (s *WidescreenResearchServer) handleSearchReports(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	query, _ := params["query"].(string)
+	limit := 0
+	if value, ok := params["limit"].(float64); ok {
+		limit = int(value)
+	}
+	return s.orchestrator.SearchReports(ctx, query, limit)
+}
+
+// researchConfigFromInput reads a research config from the "config" parameter,
+// or from the completed elicitation session when there is none
+func (s *WidescreenResearchServer) researchConfigFromInput(input *schemas.WidescreenResearchInput) (*schemas.ResearchConfig, error) {
+	if raw, ok := input.Parameters["config"]; ok {
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return nil, mcperrors.InvalidInput("invalid config: %w", err)
+		}
+		config := &schemas.ResearchConfig{}
+		if err := json.Unmarshal(data, config); err != nil {
+			return nil, mcperrors.InvalidInput("invalid config: %w", err)
+		}
+		return config, nil
+	}
+
+	if input.SessionID == "" {
+		return nil, mcperrors.InvalidInput("either config or session_id is required")
+	}
+	config := s.elicitation.GetResearchConfig(input.SessionID)
+	if config == nil {
+		return nil, mcperrors.New(mcperrors.CategoryNotFound, "no completed elicitation found for session %s", input.SessionID)
+	}
+	return config, nil
+}
+
+// templateFromParams decodes the "template" parameter
+func templateFromParams(params map[string]interface{}) (*orchestrator.ResearchTemplate, error) {
+	raw, ok := params["template"]
+	if !ok {
+		return nil, mcperrors.InvalidInput("template is required")
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, mcperrors.InvalidInput("invalid template: %w", err)
+	}
+	template := &orchestrator.ResearchTemplate{}
+	if err := json.Unmarshal(data, template); err != nil {
+		return nil, mcperrors.InvalidInput("invalid template: %w", err)
+	}
+	return template, nil
+}
+
+// handleListTemplates lists the built-in and saved research templates
+func (s *WidescreenResearchServer) handleListTemplates(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	templates, err := s.orchestrator.ListTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"templates": templates,
+		"count":     len(templates),
+	}, nil
+}
+
+// handleCreateTemplate saves the template given in the "template" parameter
+func (s *WidescreenResearchServer) handleCreateTemplate(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	template, err := templateFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.orchestrator.CreateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// handleUpdateTemplate applies the non-empty fields of the "template" parameter
+// to the saved template with its ID
+func (s *WidescreenResearchServer) handleUpdateTemplate(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	changes, err := templateFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.orchestrator.UpdateTemplate(ctx, changes)
+}
+
+// handleInstantiateTemplate returns the workflow of the template given by
+// "template_id" with the values in "params" substituted
+func (s *WidescreenResearchServer) handleInstantiateTemplate(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	templateID, _ := params["template_id"].(string)
+	if templateID == "" {
+		return nil, mcperrors.InvalidInput("template_id is required")
+	}
+	values, _ := params["params"].(map[string]interface{})
+
+	workflow, err := s.orchestrator.InstantiateTemplate(templateID, values)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"template_id": templateID,
+		"workflow":    workflow,
+	}, nil
+}
+
+// handleDeleteTemplate deletes the saved template given by "template_id"
+func (s *WidescreenResearchServer) handleDeleteTemplate(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	templateID, _ := params["template_id"].(string)
+	if templateID == "" {
+		return nil, mcperrors.InvalidInput("template_id is required")
+	}
+
+	if err := s.orchestrator.DeleteTemplate(ctx, templateID); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"template_id": templateID,
+		"deleted":     true,
+	}, nil
+}
+
+// handleCreateSchedule saves a research config to re-run on a cron schedule
+func (s *WidescreenResearchServer) handleCreateSchedule(ctx context.Context, input *schemas.WidescreenResearchInput) (interface{}, error) {
+	config, err := s.researchConfigFromInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &schemas.ResearchSchedule{Config: *config}
+	schedule.Cron, _ = input.Parameters["cron"].(string)
+	schedule.Name, _ = input.Parameters["name"].(string)
+	schedule.Timezone, _ = input.Parameters["timezone"].(string)
+	if schedule.Cron == "" {
+		return nil, mcperrors.InvalidInput("cron is required")
+	}
+
+	if err := s.orchestrator.CreateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// handleListSchedules lists the saved research schedules
+func (s *WidescreenResearchServer) handleListSchedules(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	schedules, err := s.orchestrator.ListSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"schedules": schedules,
+		"count":     len(schedules),
+	}, nil
+}
+
+// handleDeleteSchedule deletes the schedule given by "schedule_id"
+func (s *WidescreenResearchServer) handleDeleteSchedule(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	scheduleID, _ := params["schedule_id"].(string)
+	if scheduleID == "" {
+		return nil, mcperrors.InvalidInput("schedule_id is required")
+	}
+
+	if err := s.orchestrator.DeleteSchedule(ctx, scheduleID); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"schedule_id": scheduleID,
+		"deleted":     true,
+	}, nil
+}
+
+// handleRunSchedule runs the schedule given by "schedule_id" now and returns its result,
+// including what changed since the previous run
+func (s *WidescreenResearchServer) handleRunSchedule(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	scheduleID, _ := params["schedule_id"].(string)
+	if scheduleID == "" {
+		return nil, mcperrors.InvalidInput("schedule_id is required")
+	}
+
+	return s.orchestrator.RunSchedule(ctx, scheduleID)
+}
+
+// handleGetDroneLogs returns recent log entries of a drone. since and until
+// are RFC 3339 times or durations before now, such as "30m".
+func (s *WidescreenResearchServer) handleGetDroneLogs(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	query := schemas.DroneLogQuery{}
+	query.DroneID, _ = params["drone_id"].(string)
+	query.SessionID, _ = params["session_id"].(string)
+	query.MinSeverity, _ = params["min_severity"].(string)
+	if limit, ok := params["limit"].(float64); ok {
+		query.Limit = int(limit)
+	}
+
+	now := time.Now()
+	for name, field := range map[string]*time.Time{"since": &query.Since, "until": &query.Until} {
+		value, _ := params[name].(string)
+		if value == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			*field = t
+		} else if ago, err := time.ParseDuration(value); err == nil && ago >= 0 {
+			*field = now.Add(-ago)
+		} else {
+			return nil, mcperrors.InvalidInput("invalid %s %q: expected an RFC 3339 time or a duration such as 30m", name, value)
+		}
+	}
+
+	return s.orchestrator.DroneLogs(ctx, query)
+}
+
+// handleDoctor runs the preflight checks for this server's project
+func (s *WidescreenResearchServer) handleDoctor(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	projectID := gcp.ProjectID()
+	return doctor.Run(ctx, doctor.Options{ProjectID: projectID, Images: orchestrator.DefaultDroneImages(projectID)}), nil
 }
 
 // registerResources registers available resources
 func (s *WidescreenResearchServer) registerResources() {
 	// Register research reports resource
-	s.server.RegisterResource("research-reports", mcp.Resource{
-		URI:         "research://reports",
-		Name:        "Research Reports",
-		Description: "Access completed research reports",
-		MimeType:    "application/json",
-		Handler: func(ctx context.Context, uri string) (interface{}, error) {
-			// Return list of available reports
-			reports := s.orchestrator.GetReports()
-			return json.Marshal(reports)
-		},
+	reports := mcp.NewResource("research://reports", "Research Reports",
+		mcp.WithResourceDescription("Access completed research reports"),
+		mcp.WithMIMEType("application/json"),
+	)
+	s.server.AddResource(reports, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		// Resource reads carry no API key, so they list the default tenant's reports
+		if _, err := s.orchestrator.ResolveTenant(""); err != nil {
+			return nil, fmt.Errorf("reports are kept per tenant: %w", err)
+		}
+		return jsonResourceContents(request.Params.URI, s.orchestrator.GetReports(ctx))
 	})
 
 	// Register research templates resource
-	s.server.RegisterResource("research-templates", mcp.Resource{
-		URI:         "research://templates",
-		Name:        "Research Templates",
-		Description: "Pre-orchestrated research workflows",
-		MimeType:    "application/json",
-		Handler: func(ctx context.Context, uri string) (interface{}, error) {
-			// Return available templates
-			templates := s.orchestrator.GetTemplates()
-			return json.Marshal(templates)
-		},
+	templates := mcp.NewResource("research://templates", "Research Templates",
+		mcp.WithResourceDescription("Pre-orchestrated research workflows"),
+		mcp.WithMIMEType("application/json"),
+	)
+	s.server.AddResource(templates, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		// Return available templates
+		return jsonResourceContents(request.Params.URI, s.orchestrator.GetTemplates())
 	})
+
+	// Register the findings of sessions still running
+	s.registerInterimResource()
+
+	// Register the operations with their call metrics
+	ops := mcp.NewResource("research://operations", "Operations",
+		mcp.WithResourceDescription("The operations of the widescreen-research tool, with their calls, errors and durations since the server started"),
+		mcp.WithMIMEType("application/json"),
+	)
+	s.server.AddResource(ops, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return jsonResourceContents(request.Params.URI, s.operationSummaries())
+	})
+}
+
+// operationSummary describes a registered operation and its calls so far
+type operationSummary struct {
+	Name           string      `json:"name"`
+	Description    string      `json:"description"`
+	RequiredParams []string    `json:"required_params,omitempty"`
+	Stats          usage.Stats `json:"stats"`
+}
+
+// operationSummaries describes every registered operation, sorted by name
+func (s *WidescreenResearchServer) operationSummaries() []operationSummary {
+	stats := s.usage.Snapshot()
+	summaries := []operationSummary{}
+	for name, op := range s.operations.ListOperations() {
+		summaries = append(summaries, operationSummary{
+			Name:           name,
+			Description:    op.Description,
+			RequiredParams: op.RequiredParams,
+			Stats:          stats[name],
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries
+}
+
+// jsonResourceContents encodes a value as a single JSON text resource
+func jsonResourceContents(uri string, value interface{}) ([]mcp.ResourceContents, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
 }
 
 // registerPrompts registers available prompts
 func (s *WidescreenResearchServer) registerPrompts() {
 	// Register research planning prompt
-	s.server.RegisterPrompt("research-planning", mcp.Prompt{
-		Name:        "Research Planning",
-		Description: "Plan a comprehensive research strategy",
-		Arguments: []mcp.PromptArgument{
-			{
-				Name:        "topic",
-				Description: "Research topic",
-				Required:    true,
-			},
-			{
-				Name:        "scope",
-				Description: "Research scope",
-				Required:    false,
-			},
-		},
-		Handler: func(ctx context.Context, args map[string]interface{}) (string, error) {
-			topic := args["topic"].(string)
-			scope := ""
-			if s, ok := args["scope"].(string); ok {
-				scope = s
-			}
-			return fmt.Sprintf("Research Plan for: %s\nScope: %s\n\n[Planning template here]", topic, scope), nil
-		},
+	prompt := mcp.NewPrompt("research-planning",
+		mcp.WithPromptDescription("Plan a comprehensive research strategy"),
+		mcp.WithArgument("topic",
+			mcp.ArgumentDescription("Research topic"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("scope",
+			mcp.ArgumentDescription("Research scope"),
+		),
+	)
+	s.server.AddPrompt(prompt, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		topic := request.Params.Arguments["topic"]
+		if topic == "" {
+			return nil, fmt.Errorf("topic argument is required")
+		}
+		scope := request.Params.Arguments["scope"]
+
+		plan := fmt.Sprintf("Research Plan for: %s\nScope: %s\n\n[Planning template here]", topic, scope)
+		return mcp.NewGetPromptResult("Research Planning", []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(plan)),
+		}), nil
 	})
 }
 
 // Start starts the MCP server
 func (s *WidescreenResearchServer) Start(ctx context.Context) error {
+	// Start the schedule trigger endpoint first, so health probes see the
+	// orchestrator initializing rather than nothing listening
+	s.trigger = newTriggerServer(s.orchestrator, s.usage)
+	if s.trigger != nil {
+		go func() {
+			log.Printf("Schedule trigger endpoint listening on %s", s.trigger.Addr)
+			if err := s.trigger.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Warning: Schedule trigger endpoint stopped: %v", err)
+			}
+		}()
+	}
+
 	// Initialize orchestrator
 	if err := s.orchestrator.Initialize(ctx); err != nil {
 		return fmt.Errorf("failed to initialize orchestrator: %w", err)
 	}
 
-	// Start the MCP server
-	return s.server.Serve(ctx)
+	// Start the MCP server; requests run concurrently so clients can cancel them
+	return newStdioTransport(s.server).Listen(ctx, os.Stdin, os.Stdout)
+}
+
+// Drain stops accepting tool calls and schedule triggers, then waits until
+// active research sessions finish or ctx is done; see Orchestrator.Drain
+func (s *WidescreenResearchServer) Drain(ctx context.Context) {
+	s.draining.Store(true)
+	s.orchestrator.Drain(ctx)
 }
 
 // Shutdown gracefully shuts down the server
 func (s *WidescreenResearchServer) Shutdown() {
 	log.Println("Shutting down widescreen research server...")
+	if s.trigger != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.trigger.Shutdown(ctx); err != nil {
+			log.Printf("Warning: Failed to shut down schedule trigger endpoint: %v", err)
+		}
+	}
 	s.orchestrator.Shutdown()
-	s.server.Close()
-}
\ No newline at end of file
+}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+// cancelledMethod is the notification a client sends to cancel one of its requests
+const cancelledMethod = "notifications/cancelled"
+
+// stdioTransport serves the MCP server over stdin and stdout. Unlike
+// mcpserver.StdioServer it handles each request in a goroutine of its own, so a
+// notifications/cancelled from the client reaches a running tool call and
+// cancels its context.
+type stdioTransport struct {
+	server  *mcpserver.MCPServer
+	session *stdioSession
+
+	// writeMu keeps responses and notifications from interleaving on stdout
+	writeMu sync.Mutex
+	out     io.Writer
+
+	// inflight cancels the requests still being handled, keyed by their JSON-RPC ID
+	mu       sync.Mutex
+	inflight map[string]*inflightRequest
+}
+
+// inflightRequest is a request being handled
+type inflightRequest struct {
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// stdioSession is the single client of a stdio transport
+type stdioSession struct {
+	notifications chan mcp.JSONRPCNotification
+	initialized   atomic.Bool
+	loggingLevel  atomic.Value
+}
+
+func (s *stdioSession) SessionID() string { return "stdio" }
+
+func (s *stdioSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func (s *stdioSession) Initialize()       { s.initialized.Store(true) }
+func (s *stdioSession) Initialized() bool { return s.initialized.Load() }
+
+func (s *stdioSession) SetLogLevel(level mcp.LoggingLevel) { s.loggingLevel.Store(level) }
+
+func (s *stdioSession) GetLogLevel() mcp.LoggingLevel {
+	if level, ok := s.loggingLevel.Load().(mcp.LoggingLevel); ok {
+		return level
+	}
+	return mcp.LoggingLevelInfo
+}
+
+// newStdioTransport creates a stdio transport for server
+func newStdioTransport(server *mcpserver.MCPServer) *stdioTransport {
+	return &stdioTransport{
+		server:   server,
+		session:  &stdioSession{notifications: make(chan mcp.JSONRPCNotification, 100)},
+		inflight: make(map[string]*inflightRequest),
+	}
+}
+
+// Listen handles the JSON-RPC messages read from in, writing responses and
+// notifications to out, until in is closed or ctx is done
+func (t *stdioTransport) Listen(ctx context.Context, in io.Reader, out io.Writer) error {
+	t.out = out
+	if err := t.server.RegisterSession(ctx, t.session); err != nil {
+		return fmt.Errorf("failed to register stdio session: %w", err)
+	}
+	defer t.server.UnregisterSession(ctx, t.session.SessionID())
+	ctx = t.server.WithContext(ctx, t.session)
+
+	go t.writeNotifications(ctx)
+
+	lines := make(chan string)
+	readErr := make(chan error, 1)
+	go func() {
+		reader := bufio.NewReader(in)
+		for {
+			line, err := reader.ReadString('\n')
+			if strings.TrimSpace(line) != "" {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-readErr:
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to read from stdin: %w", err)
+		case line := <-lines:
+			t.handleLine(ctx, line)
+		}
+	}
+}
+
+// handleLine dispatches one JSON-RPC message. Requests are handled in the
+// background; notifications and responses are handled in order.
+func (t *stdioTransport) handleLine(ctx context.Context, line string) {
+	var message struct {
+		ID     json.RawMessage `json:"id,omitempty"`
+		Method string          `json:"method"`
+		Params struct {
+			RequestID json.RawMessage `json:"requestId"`
+			Reason    string          `json:"reason"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal([]byte(line), &message); err != nil {
+		t.write(mcp.JSONRPCError{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			Error: struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+				Data    any    `json:"data,omitempty"`
+			}{Code: mcp.PARSE_ERROR, Message: "Parse error"},
+		})
+		return
+	}
+
+	if message.Method == cancelledMethod {
+		t.cancel(string(message.Params.RequestID), message.Params.Reason)
+		return
+	}
+	if len(message.ID) == 0 || message.Method == "" {
+		if response := t.server.HandleMessage(ctx, json.RawMessage(line)); response != nil {
+			t.write(response)
+		}
+		return
+	}
+
+	id := string(message.ID)
+	requestCtx, cancel := context.WithCancel(ctx)
+	request := &inflightRequest{cancel: cancel}
+	t.mu.Lock()
+	t.inflight[id] = request
+	t.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		response := t.server.HandleMessage(requestCtx, json.RawMessage(line))
+
+		t.mu.Lock()
+		delete(t.inflight, id)
+		cancelled := request.cancelled
+		t.mu.Unlock()
+
+		// A cancelled request gets no response
+		if response != nil && !cancelled {
+			t.write(response)
+		}
+	}()
+}
+
+// cancel cancels the context of a request the client no longer wants
+func (t *stdioTransport) cancel(id, reason string) {
+	t.mu.Lock()
+	request := t.inflight[id]
+	if request != nil {
+		request.cancelled = true
+	}
+	t.mu.Unlock()
+
+	if request == nil {
+		return
+	}
+	if reason != "" {
+		log.Printf("Client cancelled request %s: %s", id, reason)
+	} else {
+		log.Printf("Client cancelled request %s", id)
+	}
+	request.cancel()
+}
+
+// writeNotifications writes the notifications sent to the session until ctx is done
+func (t *stdioTransport) writeNotifications(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-t.session.notifications:
+			t.write(notification)
+		}
+	}
+}
+
+// write writes one JSON-RPC message as a line
+func (t *stdioTransport) write(message interface{}) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Warning: Failed to encode JSON-RPC message: %v", err)
+		return
+	}
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := fmt.Fprintf(t.out, "%s\n", data); err != nil {
+		log.Printf("Warning: Failed to write JSON-RPC message: %v", err)
+	}
+}
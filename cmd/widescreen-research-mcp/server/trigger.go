@@ -0,0 +1,255 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/orchestrator"
+	"github.com/spawn-mcp/coordinator/pkg/health"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+	"github.com/spawn-mcp/coordinator/pkg/usage"
+)
+
+// triggerTokenHeader carries TRIGGER_TOKEN; Authorization is left to Cloud Run's OIDC check
+const triggerTokenHeader = "X-Trigger-Token"
+
+// apiKeyHeader carries the API key of the tenant whose schedule is triggered
+const apiKeyHeader = "X-API-Key"
+
+// correlationHeader returns the correlation ID of a triggered run, which its logs and documents carry
+const correlationHeader = "X-Correlation-ID"
+
+// triggerAPI starts saved research schedules from Cloud Scheduler HTTP jobs and Pub/Sub push subscriptions
+type triggerAPI struct {
+	orchestrator *orchestrator.Orchestrator
+	usage        *usage.Recorder
+	token        string
+}
+
+// pushEnvelope is the body of a Pub/Sub push request
+type pushEnvelope struct {
+	Message struct {
+		Data       []byte            `json:"data"`
+		Attributes map[string]string `json:"attributes"`
+		MessageID  string            `json:"messageId"`
+	} `json:"message"`
+	Subscription string `json:"subscription"`
+}
+
+// triggerAddr returns the trigger endpoint's listen address: TRIGGER_HTTP_ADDR,
+// else Cloud Run's PORT, else empty to disable it
+func triggerAddr() string {
+	if addr := os.Getenv("TRIGGER_HTTP_ADDR"); addr != "" {
+		return addr
+	}
+	if port := os.Getenv("PORT"); port != "" {
+		return ":" + port
+	}
+	return ""
+}
+
+// newTriggerServer creates the HTTP server for schedule triggers, operation
+// metrics and health probes, or returns nil if no listen address is configured
+func newTriggerServer(orch *orchestrator.Orchestrator, recorder *usage.Recorder) *http.Server {
+	addr := triggerAddr()
+	if addr == "" {
+		return nil
+	}
+
+	api := &triggerAPI{orchestrator: orch, usage: recorder, token: os.Getenv("TRIGGER_TOKEN")}
+	if api.token == "" {
+		log.Printf("Warning: TRIGGER_TOKEN is not set, schedule triggers are only protected by Cloud Run IAM")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	checker := health.NewChecker()
+	orch.AddHealthChecks(checker)
+	checker.Register(mux)
+	mux.HandleFunc("GET /metrics", api.tokenRequired(api.metrics))
+	mux.HandleFunc("POST /schedules/{schedule}/trigger", api.authorized(api.trigger))
+	mux.HandleFunc("POST /pubsub/schedules", api.authorized(api.pubsubPush))
+
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// authorized requires the trigger token, as tokenRequired does. Schedules are
+// looked up in the tenant of the API key in the X-API-Key header or api_key
+// query parameter, if any.
+func (a *triggerAPI) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return a.tokenRequired(func(w http.ResponseWriter, r *http.Request) {
+		// The endpoint listens while the orchestrator is still initializing
+		if !a.orchestrator.Initialized() {
+			writeError(w, http.StatusServiceUnavailable, errors.New("orchestrator is still initializing"))
+			return
+		}
+
+		apiKey := r.Header.Get(apiKeyHeader)
+		if apiKey == "" {
+			apiKey = r.URL.Query().Get("api_key")
+		}
+		tenantID, err := a.orchestrator.ResolveTenant(apiKey)
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, err)
+			return
+		}
+		correlationID := mcperrors.NewCorrelationID()
+		w.Header().Set(correlationHeader, correlationID)
+		ctx := mcperrors.WithCorrelationID(orchestrator.WithTenant(r.Context(), tenantID), correlationID)
+		next(w, r.WithContext(ctx))
+	})
+}
+
+// tokenRequired requires TRIGGER_TOKEN in the X-Trigger-Token header or token
+// query parameter when it is set
+func (a *triggerAPI) tokenRequired(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if a.token != "" {
+			given := r.Header.Get(triggerTokenHeader)
+			if given == "" {
+				// Pub/Sub push subscriptions cannot set headers
+				given = r.URL.Query().Get("token")
+			}
+			if subtle.ConstantTimeCompare([]byte(given), []byte(a.token)) != 1 {
+				writeError(w, http.StatusUnauthorized, errors.New("missing or invalid trigger token"))
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// metrics returns the calls, error rate and latency percentiles of each operation
+func (a *triggerAPI) metrics(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"operations": a.usage.Snapshot(),
+	})
+}
+
+// trigger starts the schedule named in the path. The run is keyed by the
+// Idempotency-Key header, or the X-CloudScheduler-ScheduleTime header Cloud
+// Scheduler sends, so retries of one job execution start a single run.
+func (a *triggerAPI) trigger(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" {
+		key = r.Header.Get("X-CloudScheduler-ScheduleTime")
+	}
+	if key == "" {
+		writeError(w, http.StatusBadRequest, errors.New("an Idempotency-Key or X-CloudScheduler-ScheduleTime header is required"))
+		return
+	}
+
+	sessionID, duplicate, err := a.orchestrator.TriggerSchedule(r.Context(), r.PathValue("schedule"), key)
+	if err != nil {
+		writeError(w, triggerErrorStatus(err), err)
+		return
+	}
+	writeTriggered(w, sessionID, duplicate)
+}
+
+// pubsubPush starts the schedule named by a Pub/Sub message's "schedule"
+// attribute, JSON data such as {"schedule": "weekly-competitor-scan"}, or
+// plain-text data. Runs are
+// keyed by the "idempotency_key" attribute or else the message ID, so
+// redeliveries start a single run. Messages that can never succeed are
+// acknowledged with a 2xx so Pub/Sub stops redelivering them.
+func (a *triggerAPI) pubsubPush(w http.ResponseWriter, r *http.Request) {
+	var envelope pushEnvelope
+	if err := json.NewDecoder(r.Body).Decode(&envelope); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid push request: %w", err))
+		return
+	}
+	message := envelope.Message
+
+	schedule := message.Attributes["schedule"]
+	if schedule == "" && len(message.Data) > 0 {
+		var data struct {
+			Schedule string `json:"schedule"`
+		}
+		if err := json.Unmarshal(message.Data, &data); err == nil {
+			schedule = data.Schedule
+		} else {
+			schedule = strings.TrimSpace(string(message.Data))
+		}
+	}
+	if schedule == "" {
+		log.Printf("Warning: Dropping Pub/Sub message %s without a schedule", message.MessageID)
+		writeJSON(w, http.StatusOK, map[string]string{"error": "message names no schedule"})
+		return
+	}
+
+	key := message.Attributes["idempotency_key"]
+	if key == "" {
+		key = message.MessageID
+	}
+	if key == "" {
+		writeError(w, http.StatusBadRequest, errors.New("push request has no message ID"))
+		return
+	}
+
+	sessionID, duplicate, err := a.orchestrator.TriggerSchedule(r.Context(), schedule, key)
+	if err != nil {
+		status := triggerErrorStatus(err)
+		if status != http.StatusInternalServerError && status != http.StatusServiceUnavailable {
+			log.Printf("Warning: Dropping Pub/Sub message %s: %v", message.MessageID, err)
+			writeJSON(w, http.StatusOK, map[string]string{"error": err.Error()})
+			return
+		}
+		// Anything else may be transient, or another instance can run it; fail so Pub/Sub redelivers
+		writeError(w, status, err)
+		return
+	}
+	writeTriggered(w, sessionID, duplicate)
+}
+
+// triggerErrorStatus maps a TriggerSchedule error to an HTTP status
+func triggerErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, orchestrator.ErrScheduleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, orchestrator.ErrScheduleAmbiguous):
+		return http.StatusConflict
+	case errors.Is(err, orchestrator.ErrDraining):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// writeTriggered reports the session a trigger started, or the one an earlier identical trigger started
+func writeTriggered(w http.ResponseWriter, sessionID string, duplicate bool) {
+	status := http.StatusAccepted
+	if duplicate {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, map[string]interface{}{
+		"session_id": sessionID,
+		"duplicate":  duplicate,
+	})
+}
+
+// writeJSON writes v as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Warning: Failed to write response: %v", err)
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/coordinator"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/mcp"
+	"github.com/spawn-mcp/coordinator/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+// newCoordinatorCommand runs the drone fleet coordinator
+func newCoordinatorCommand() *cobra.Command {
+	var stdio bool
+	cmd := &cobra.Command{
+		Use:   "coordinator",
+		Short: "Run the drone fleet coordinator",
+		Long: "Run the drone fleet coordinator, serving its gRPC and HTTP admin APIs.\n" +
+			"With --stdio it serves its MCP tools on stdio instead.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCoordinator(cmd.Context(), stdio)
+		},
+	}
+	cmd.Flags().BoolVar(&stdio, "stdio", false, "Serve the coordinator's MCP tools on stdio instead of the admin APIs")
+	return cmd
+}
+
+func runCoordinator(ctx context.Context, stdio bool) error {
+	log.Printf("Starting Spawn MCP Coordinator %s...", version.Get())
+
+	if gcp.EmulatorMode() {
+		log.Println("Running against local Firestore and Pub/Sub emulators")
+	}
+
+	// Get configuration from environment variables
+	projectID := gcp.ProjectID()
+	if projectID == "" {
+		return errors.New("GOOGLE_CLOUD_PROJECT environment variable or --project is required")
+	}
+
+	region := os.Getenv("GOOGLE_CLOUD_REGION")
+	if region == "" {
+		region = "us-central1" // Default region
+	}
+
+	// Act as the configured identity, or Application Default Credentials
+	credentials := gcp.LoadCredentialConfig()
+	opts, err := credentials.ClientOptions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load GCP credentials: %w", err)
+	}
+	if credentials.ImpersonateServiceAccount != "" {
+		log.Printf("Impersonating service account %s", credentials.ImpersonateServiceAccount)
+	}
+
+	// Initialize GCP client
+	gcpClient, err := gcp.NewClient(ctx, projectID, region, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create GCP client: %w", err)
+	}
+	defer func() {
+		if err := gcpClient.Close(); err != nil {
+			log.Printf("Error closing GCP client: %v", err)
+		}
+	}()
+
+	log.Printf("Initialized GCP client for project %s in region %s", projectID, region)
+
+	// Create coordinator server
+	server := coordinator.NewServer(gcpClient)
+
+	// Re-adopt drones created before a restart so their services are not orphaned
+	if err := server.RecoverDrones(ctx); err != nil {
+		log.Printf("Warning: Failed to recover drones: %v", err)
+	}
+
+	// Periodically delete stale plans, results and session data
+	server.StartRetention(ctx, coordinator.LoadRetentionConfig())
+
+	// Fail queued tasks no drone picked up in time
+	server.StartQueueExpiry(ctx)
+
+	sigChan := shutdownSignals()
+	serverErr := make(chan error, 1)
+
+	var restServer *http.Server
+	if stdio {
+		mcpServer := mcp.NewMCPServer(server)
+		defer mcpServer.Close()
+		go func() {
+			log.Println("Starting MCP server on stdio...")
+			serverErr <- mcpServer.Start(ctx)
+		}()
+	} else {
+		go func() {
+			serverErr <- server.Serve()
+		}()
+
+		// Serve the JSON admin API alongside the gRPC one
+		restServer = newRESTServer(server)
+		go func() {
+			log.Printf("HTTP admin API listening on %s", restServer.Addr)
+			if err := restServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				serverErr <- fmt.Errorf("HTTP admin API stopped: %w", err)
+			}
+		}()
+	}
+
+	// Wait for shutdown signal or server error
+	select {
+	case sig := <-sigChan:
+		log.Printf("Received signal %v, shutting down gracefully...", sig)
+		if restServer != nil {
+			shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			if err := restServer.Shutdown(shutdownCtx); err != nil {
+				log.Printf("Error shutting down HTTP admin API: %v", err)
+			}
+			cancel()
+			server.Stop()
+		}
+
+		// Let running tasks finish, and record those that cannot, before exiting
+		server.Drain(ctx, coordinator.LoadDrainConfig())
+	case err := <-serverErr:
+		if err != nil {
+			log.Printf("Server error: %v", err)
+			return err
+		}
+	}
+
+	log.Println("Coordinator MCP Server stopped")
+	return nil
+}
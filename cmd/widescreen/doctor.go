@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/orchestrator"
+	"github.com/spawn-mcp/coordinator/pkg/doctor"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spf13/cobra"
+)
+
+// newDoctorCommand checks the environment and project before research is run
+func newDoctorCommand() *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check credentials, APIs, IAM permissions, drone images and API keys",
+		Long: "Check that credentials work, the required APIs are enabled, the credentials\n" +
+			"hold the IAM permissions research needs, the drone images exist and the API\n" +
+			"keys are set, printing how to fix each problem. Exits non-zero if a check fails.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectID := gcp.ProjectID()
+			report := doctor.Run(cmd.Context(), doctor.Options{
+				ProjectID: projectID,
+				Images:    orchestrator.DefaultDroneImages(projectID),
+			})
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				encoder := json.NewEncoder(out)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				for _, check := range report.Checks {
+					fmt.Fprintf(out, "%-6s %s: %s\n", "["+check.Status+"]", check.Name, check.Detail)
+					if check.Remedy != "" {
+						fmt.Fprintf(out, "       fix: %s\n", check.Remedy)
+					}
+				}
+			}
+
+			if report.Failed() {
+				return fmt.Errorf("preflight checks failed for project %q", projectID)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON")
+	return cmd
+}
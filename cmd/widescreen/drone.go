@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spawn-mcp/coordinator/pkg/drone"
+	"github.com/spawn-mcp/coordinator/pkg/types"
+	"github.com/spawn-mcp/coordinator/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+// newDroneCommand runs a drone of the type the coordinator deployed
+func newDroneCommand() *cobra.Command {
+	var addr, droneType string
+	cmd := &cobra.Command{
+		Use:   "drone",
+		Short: "Run a drone's HTTP server",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDrone(addr, types.DroneType(droneType))
+		},
+	}
+	cmd.Flags().StringVar(&addr, "addr", ":8080", "Address the drone listens on")
+	cmd.Flags().StringVar(&droneType, "type", os.Getenv("DRONE_TYPE"), "Drone type: researcher, analyst, writer or coder (default: $DRONE_TYPE, else researcher)")
+	return cmd
+}
+
+func runDrone(addr string, droneType types.DroneType) error {
+	log.Printf("Starting Drone MCP Server %s...", version.Get())
+
+	d, err := drone.New(droneType)
+	if err != nil {
+		return fmt.Errorf("failed to create drone: %w", err)
+	}
+	defer func() {
+		if err := d.Close(); err != nil {
+			log.Printf("Error closing drone: %v", err)
+		}
+	}()
+	log.Printf("Drone %s is a %s drone", d.ID(), d.Type())
+
+	// Report liveness so the orchestrator can replace the drone if it stops responding
+	d.StartHeartbeat()
+	// Report resource usage so the orchestrator can include it in the session's metrics
+	d.StartMetricsReporting()
+
+	sigChan := shutdownSignals()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- d.StartHTTPServer(addr)
+	}()
+
+	// Wait for shutdown signal or server error
+	select {
+	case sig := <-sigChan:
+		log.Printf("Received signal %v, shutting down gracefully...", sig)
+	case err := <-serverErr:
+		if err != nil {
+			log.Printf("Server error: %v", err)
+			return err
+		}
+	}
+
+	log.Println("Drone MCP Server stopped")
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spawn-mcp/coordinator/pkg/config"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+// globalFlags are the settings every subcommand shares. Flags that are set
+// override the config file and the environment.
+type globalFlags struct {
+	configFile string
+	project    string
+	region     string
+	emulator   bool
+	simulate   bool
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newRootCommand builds the widescreen command and its subcommands
+func newRootCommand() *cobra.Command {
+	flags := &globalFlags{}
+	cfg := &config.Config{}
+	root := &cobra.Command{
+		Use:          "widescreen",
+		Short:        "Widescreen research coordinator, drones and MCP server",
+		SilenceUsage: true,
+		Version:      version.Get().String(),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			loaded, err := flags.load(cmd)
+			if err != nil {
+				return err
+			}
+			*cfg = *loaded
+			return nil
+		},
+	}
+	root.PersistentFlags().StringVar(&flags.configFile, "config", os.Getenv("WIDESCREEN_CONFIG"), "YAML config file (default $WIDESCREEN_CONFIG)")
+	root.PersistentFlags().StringVar(&flags.project, "project", "", "Google Cloud project (default $GOOGLE_CLOUD_PROJECT)")
+	root.PersistentFlags().StringVar(&flags.region, "region", "", "Google Cloud region (default $GOOGLE_CLOUD_REGION, or us-central1)")
+	root.PersistentFlags().BoolVar(&flags.emulator, "emulator", false, "Use local Firestore and Pub/Sub emulators")
+	root.PersistentFlags().BoolVar(&flags.simulate, "simulate", false, "Simulate drones with synthetic results instead of deploying them to Cloud Run")
+
+	root.AddCommand(
+		newServeCommand(),
+		newCoordinatorCommand(),
+		newDroneCommand(),
+		newResearchCommand(),
+		newDoctorCommand(),
+		newPrintConfigCommand(cfg),
+	)
+	return root
+}
+
+// load reads the config file and environment, overrides them with the flags
+// that were set, and validates the result before exporting it to the
+// environment the packages read their settings from
+func (f *globalFlags) load(cmd *cobra.Command) (*config.Config, error) {
+	cfg, err := config.Load(f.configFile)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.Flags().Changed("project") {
+		cfg.Project = f.project
+	}
+	if cmd.Flags().Changed("region") {
+		cfg.Region = f.region
+	}
+	if cmd.Flags().Changed("emulator") {
+		cfg.Emulator = f.emulator
+	}
+	if cmd.Flags().Changed("simulate") {
+		cfg.Simulate = f.simulate
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := cfg.Apply(); err != nil {
+		return nil, fmt.Errorf("failed to apply configuration: %w", err)
+	}
+	if cfg.Emulator {
+		gcp.EnableEmulators()
+	}
+	return cfg, nil
+}
+
+// shutdownSignals delivers SIGINT and SIGTERM
+func shutdownSignals() <-chan os.Signal {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	return sigChan
+}
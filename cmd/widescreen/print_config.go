@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spawn-mcp/coordinator/pkg/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// newPrintConfigCommand prints the configuration the other commands would run
+// with, after the config file, environment and flags are combined
+func newPrintConfigCommand(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "print-config",
+		Short: "Print the effective configuration as YAML, with secrets redacted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := yaml.Marshal(cfg.Redacted())
+			if err != nil {
+				return fmt.Errorf("failed to encode configuration: %w", err)
+			}
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		},
+	}
+}
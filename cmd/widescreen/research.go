@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/orchestrator"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spf13/cobra"
+)
+
+// newResearchCommand groups the commands that run research without an MCP client
+func newResearchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "research",
+		Short: "Run research sessions from the command line",
+	}
+	cmd.AddCommand(newResearchRunCommand())
+	cmd.AddCommand(newResearchExportCommand())
+	cmd.AddCommand(newResearchImportCommand())
+	return cmd
+}
+
+// newResearchRunCommand runs one research session and prints its result as JSON
+func newResearchRunCommand() *cobra.Command {
+	config := &schemas.ResearchConfig{}
+	var regions, notifyEmails string
+	var planOnly bool
+	cmd := &cobra.Command{
+		Use:   "run <topic>",
+		Short: "Run a research session and print its result as JSON",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config.Topic = args[0]
+			config.Regions = splitList(regions)
+			config.NotifyEmails = splitList(notifyEmails)
+			return runResearch(cmd.Context(), config, planOnly)
+		},
+	}
+	flags := cmd.Flags()
+	flags.IntVar(&config.ResearcherCount, "researchers", 10, "Number of research drones")
+	flags.StringVar(&config.ResearchDepth, "depth", "standard", "Research depth: basic, standard or deep")
+	flags.StringVar(&config.OutputFormat, "format", "structured_json", "Report format: structured_json, markdown_report, executive_summary or raw_data")
+	flags.IntVar(&config.TimeoutMinutes, "timeout", 60, "Session timeout in minutes")
+	flags.StringVar(&config.PriorityLevel, "priority", "normal", "Priority level: low, normal or high")
+	flags.StringVar(&config.TemplateID, "template", "", "Saved research template to run")
+	flags.StringVar(&config.SpecificSources, "sources", "", "Sources to focus on")
+	flags.Float64Var(&config.MaxCostUSD, "max-cost", 0, "Stop the session once it costs this much in USD (0 means no budget)")
+	flags.StringVar(&regions, "regions", "", "Comma-separated regions to deploy drones to, in order of preference")
+	flags.StringVar(&config.ServiceAccount, "service-account", "", "Service account the drones run as")
+	flags.StringVar(&notifyEmails, "notify", "", "Comma-separated addresses emailed the report")
+	flags.BoolVar(&planOnly, "plan-only", false, "Print the session's plan and estimate without provisioning anything")
+	return cmd
+}
+
+func runResearch(ctx context.Context, config *schemas.ResearchConfig, planOnly bool) error {
+	if config.ResearcherCount <= 0 {
+		return errors.New("--researchers must be positive")
+	}
+	config.SessionID = uuid.New().String()
+	config.CreatedAt = time.Now()
+
+	orch, err := orchestrator.NewOrchestrator()
+	if err != nil {
+		return fmt.Errorf("failed to create orchestrator: %w", err)
+	}
+	defer orch.Shutdown()
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if planOnly {
+		plan, err := orch.PlanResearch(ctx, config)
+		if err != nil {
+			return fmt.Errorf("failed to plan research: %w", err)
+		}
+		return encoder.Encode(plan)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The first signal interrupts the session, deleting its drones
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case sig := <-shutdownSignals():
+			log.Printf("Received signal %v, interrupting session %s...", sig, config.SessionID)
+			drainCtx, cancelDrain := context.WithTimeout(context.Background(), orchestrator.LoadDrainTimeout())
+			orch.Drain(drainCtx)
+			cancelDrain()
+			cancel()
+		case <-done:
+		}
+	}()
+
+	// Log each drone's findings as it reports, long before the report is ready
+	ctx = orchestrator.WithResultListener(ctx, func(result schemas.InterimResult) {
+		if result.Status != "completed" {
+			log.Printf("[%d/%d] Drone %s %s: %s", result.Collected, result.Expected, result.DroneID, result.Status, result.Error)
+			return
+		}
+		log.Printf("[%d/%d] Drone %s: %s", result.Collected, result.Expected, result.DroneID, result.Summary)
+		for _, finding := range result.KeyFindings {
+			log.Printf("  - %s", finding)
+		}
+	})
+
+	log.Printf("Starting research session %s on %q", config.SessionID, config.Topic)
+	result, err := orch.OrchestrateResearch(ctx, config)
+	if err != nil {
+		return fmt.Errorf("research session %s failed: %w", config.SessionID, err)
+	}
+	return encoder.Encode(result)
+}
+
+// newResearchExportCommand writes a stored session's snapshot as JSON
+func newResearchExportCommand() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "export <session-id>",
+		Short: "Export a session's config, results, report and metrics as a JSON snapshot",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			orch, err := orchestrator.NewOrchestrator()
+			if err != nil {
+				return fmt.Errorf("failed to create orchestrator: %w", err)
+			}
+			defer orch.Shutdown()
+
+			snapshot, err := orch.ExportSession(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("failed to export session %s: %w", args[0], err)
+			}
+
+			out := os.Stdout
+			if output != "" {
+				file, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", output, err)
+				}
+				defer file.Close()
+				out = file
+			}
+			encoder := json.NewEncoder(out)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(snapshot); err != nil {
+				return fmt.Errorf("failed to write snapshot: %w", err)
+			}
+			if output != "" {
+				log.Printf("Exported session %s with %d results to %s", args[0], len(snapshot.Results), output)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write the snapshot to instead of stdout")
+	return cmd
+}
+
+// newResearchImportCommand stores a session snapshot written by export
+func newResearchImportCommand() *cobra.Command {
+	var overwrite bool
+	cmd := &cobra.Command{
+		Use:   "import <snapshot.json>",
+		Short: "Import a session snapshot so it can be inspected and replayed here",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read snapshot: %w", err)
+			}
+			snapshot := &schemas.SessionSnapshot{}
+			if err := json.Unmarshal(data, snapshot); err != nil {
+				return fmt.Errorf("failed to parse snapshot: %w", err)
+			}
+
+			orch, err := orchestrator.NewOrchestrator()
+			if err != nil {
+				return fmt.Errorf("failed to create orchestrator: %w", err)
+			}
+			defer orch.Shutdown()
+
+			if err := orch.ImportSession(cmd.Context(), snapshot, overwrite); err != nil {
+				if errors.Is(err, orchestrator.ErrSessionExists) {
+					return fmt.Errorf("%w (use --overwrite to replace it)", err)
+				}
+				return fmt.Errorf("failed to import session: %w", err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace the session if it is already stored")
+	return cmd
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/coordinator"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/health"
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// restAPI serves JSON endpoints for fleet management on top of the coordinator
+type restAPI struct {
+	coordinator *coordinator.Server
+}
+
+// newRESTServer creates the HTTP admin server. Everything except /health,
+// /healthz and /readyz requires the admin token.
+func newRESTServer(server *coordinator.Server) *http.Server {
+	api := &restAPI{coordinator: server}
+
+	admin := http.NewServeMux()
+	admin.HandleFunc("GET /drones", api.listDrones)
+	admin.HandleFunc("POST /drones", api.spawnDrone)
+	admin.HandleFunc("POST /drones/scale", api.scaleDrones)
+	admin.HandleFunc("GET /drones/{id}", api.getDrone)
+	admin.HandleFunc("DELETE /drones/{id}", api.terminateDrone)
+	admin.HandleFunc("GET /tasks", api.queryTasks)
+	admin.HandleFunc("POST /tasks", api.executeTask)
+	admin.HandleFunc("GET /tasks/{id}", api.getTask)
+	admin.HandleFunc("GET /sessions/{id}", api.sessionStatus)
+	admin.HandleFunc("POST /sessions/{id}/launch", api.launchSession)
+	admin.HandleFunc("POST /sessions/{id}/abort", api.abortSession)
+	admin.HandleFunc("DELETE /sessions/{id}", api.purgeSession)
+	admin.HandleFunc("GET /dashboard", api.dashboard)
+	admin.HandleFunc("GET /dashboard/events", api.dashboardEvents)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", api.health)
+	checker := health.NewChecker()
+	server.AddHealthChecks(checker)
+	checker.Register(mux)
+	mux.Handle("/", coordinator.AdminAuth(admin))
+
+	addr := os.Getenv("ADMIN_HTTP_ADDR")
+	if addr == "" {
+		addr = ":8081"
+	}
+
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// writeJSON writes v as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Warning: Failed to write response: %v", err)
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// decodeJSON decodes the request body into v, rejecting unknown fields
+func decodeJSON(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// transitionStatus maps state machine errors to 409 Conflict
+func transitionStatus(err error) int {
+	if errors.Is(err, gcp.ErrInvalidTransition) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+func (a *restAPI) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":        "ok",
+		"active_drones": len(a.coordinator.ListActiveDrones()),
+		"queued_tasks":  a.coordinator.QueuedTasks(),
+	})
+}
+
+func (a *restAPI) listDrones(w http.ResponseWriter, r *http.Request) {
+	droneType := r.URL.Query().Get("type")
+
+	drones := []*types.DroneInfo{}
+	for _, drone := range a.coordinator.ListActiveDrones() {
+		if droneType == "" || drone.Type == droneType {
+			drones = append(drones, drone)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"drones": drones})
+}
+
+func (a *restAPI) spawnDrone(w http.ResponseWriter, r *http.Request) {
+	var config types.DroneConfig
+	if err := decodeJSON(r, &config); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if config.Type == "" {
+		writeError(w, http.StatusBadRequest, errors.New("type is required"))
+		return
+	}
+
+	droneID, err := a.coordinator.SpawnDrone(r.Context(), config)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"drone_id": droneID})
+}
+
+func (a *restAPI) scaleDrones(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Type        types.DroneType `json:"type"`
+		TargetCount int             `json:"target_count"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Type == "" || req.TargetCount < 0 {
+		writeError(w, http.StatusBadRequest, errors.New("type and a non-negative target_count are required"))
+		return
+	}
+
+	if err := a.coordinator.ScaleDrones(r.Context(), req.Type, req.TargetCount); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	active := 0
+	for _, drone := range a.coordinator.ListActiveDrones() {
+		if drone.Type == string(req.Type) {
+			active++
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"active_count": active})
+}
+
+func (a *restAPI) getDrone(w http.ResponseWriter, r *http.Request) {
+	drone, err := a.coordinator.GetDroneStatus(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, drone)
+}
+
+func (a *restAPI) terminateDrone(w http.ResponseWriter, r *http.Request) {
+	droneID := r.PathValue("id")
+	if _, err := a.coordinator.GetDroneStatus(r.Context(), droneID); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if err := a.coordinator.TerminateDrone(r.Context(), droneID); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *restAPI) executeTask(w http.ResponseWriter, r *http.Request) {
+	var task types.Task
+	if err := decodeJSON(r, &task); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if task.Type == "" || task.Description == "" {
+		writeError(w, http.StatusBadRequest, errors.New("type and description are required"))
+		return
+	}
+	if task.MaxDrones <= 0 {
+		task.MaxDrones = 3
+	}
+
+	taskID, err := a.coordinator.ExecuteTask(r.Context(), task)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"task_id": taskID})
+}
+
+func (a *restAPI) getTask(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	results, err := a.coordinator.GetTaskResults(taskID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"task_id": taskID, "results": results})
+}
+
+func (a *restAPI) queryTasks(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := types.TaskResultQuery{
+		TaskID:  params.Get("task_id"),
+		DroneID: params.Get("drone_id"),
+		Status:  params.Get("status"),
+		Limit:   50,
+		Cursor:  params.Get("cursor"),
+	}
+
+	var err error
+	if limit := params.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil || query.Limit < 1 || query.Limit > 500 {
+			writeError(w, http.StatusBadRequest, errors.New("limit must be between 1 and 500"))
+			return
+		}
+	}
+	if offset := params.Get("offset"); offset != "" {
+		if query.Offset, err = strconv.Atoi(offset); err != nil || query.Offset < 0 {
+			writeError(w, http.StatusBadRequest, errors.New("offset must not be negative"))
+			return
+		}
+	}
+	if since := params.Get("since"); since != "" {
+		if query.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid since: %w", err))
+			return
+		}
+	}
+	if until := params.Get("until"); until != "" {
+		if query.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid until: %w", err))
+			return
+		}
+	}
+
+	results, nextCursor, err := a.coordinator.QueryTaskResults(r.Context(), query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"results": results, "next_cursor": nextCursor})
+}
+
+func (a *restAPI) sessionStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := a.coordinator.FleetStatus(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (a *restAPI) launchSession(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TargetWorkers int `json:"target_workers"`
+	}
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r, &req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	statusID, err := a.coordinator.LaunchFleet(r.Context(), r.PathValue("id"), req.TargetWorkers)
+	if err != nil {
+		writeError(w, transitionStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"status_id": statusID})
+}
+
+func (a *restAPI) abortSession(w http.ResponseWriter, r *http.Request) {
+	if err := a.coordinator.AbortRun(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, transitionStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *restAPI) purgeSession(w http.ResponseWriter, r *http.Request) {
+	deleted, err := a.coordinator.PurgeSession(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"deleted": deleted})
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/orchestrator"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/server"
+	"github.com/spf13/cobra"
+)
+
+// newServeCommand runs the widescreen-research MCP server on stdio
+func newServeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Run the widescreen-research MCP server on stdio",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(cmd.Context())
+		},
+	}
+}
+
+func runServe(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sigChan := shutdownSignals()
+
+	srv, err := server.NewWidescreenResearchServer()
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		if err := srv.Start(ctx); err != nil {
+			errChan <- err
+		}
+	}()
+
+	var serveErr error
+	select {
+	case sig := <-sigChan:
+		log.Printf("Received signal %v, shutting down...", sig)
+	case serveErr = <-errChan:
+		log.Printf("Server error: %v", serveErr)
+	}
+
+	// Graceful shutdown: let active research finish, or interrupt it and delete its drones
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), orchestrator.LoadDrainTimeout())
+	srv.Drain(drainCtx)
+	cancelDrain()
+	srv.Shutdown()
+	return serveErr
+}
@@ -0,0 +1,267 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.3
+// source: admin.proto
+
+package adminpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	CoordinatorAdmin_SpawnDrone_FullMethodName  = "/coordinator.admin.v1.CoordinatorAdmin/SpawnDrone"
+	CoordinatorAdmin_ListDrones_FullMethodName  = "/coordinator.admin.v1.CoordinatorAdmin/ListDrones"
+	CoordinatorAdmin_ExecuteTask_FullMethodName = "/coordinator.admin.v1.CoordinatorAdmin/ExecuteTask"
+	CoordinatorAdmin_GetResults_FullMethodName  = "/coordinator.admin.v1.CoordinatorAdmin/GetResults"
+	CoordinatorAdmin_Scale_FullMethodName       = "/coordinator.admin.v1.CoordinatorAdmin/Scale"
+)
+
+// CoordinatorAdminClient is the client API for CoordinatorAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CoordinatorAdminClient interface {
+	// SpawnDrone deploys a new drone and waits for it to be ready
+	SpawnDrone(ctx context.Context, in *SpawnDroneRequest, opts ...grpc.CallOption) (*SpawnDroneResponse, error)
+	// ListDrones returns the active drones
+	ListDrones(ctx context.Context, in *ListDronesRequest, opts ...grpc.CallOption) (*ListDronesResponse, error)
+	// ExecuteTask queues a task on the fleet and returns its ID
+	ExecuteTask(ctx context.Context, in *ExecuteTaskRequest, opts ...grpc.CallOption) (*ExecuteTaskResponse, error)
+	// GetResults returns the results collected so far for a task
+	GetResults(ctx context.Context, in *GetResultsRequest, opts ...grpc.CallOption) (*GetResultsResponse, error)
+	// Scale spawns or terminates drones of a type to reach a target count
+	Scale(ctx context.Context, in *ScaleRequest, opts ...grpc.CallOption) (*ScaleResponse, error)
+}
+
+type coordinatorAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCoordinatorAdminClient(cc grpc.ClientConnInterface) CoordinatorAdminClient {
+	return &coordinatorAdminClient{cc}
+}
+
+func (c *coordinatorAdminClient) SpawnDrone(ctx context.Context, in *SpawnDroneRequest, opts ...grpc.CallOption) (*SpawnDroneResponse, error) {
+	out := new(SpawnDroneResponse)
+	err := c.cc.Invoke(ctx, CoordinatorAdmin_SpawnDrone_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coordinatorAdminClient) ListDrones(ctx context.Context, in *ListDronesRequest, opts ...grpc.CallOption) (*ListDronesResponse, error) {
+	out := new(ListDronesResponse)
+	err := c.cc.Invoke(ctx, CoordinatorAdmin_ListDrones_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coordinatorAdminClient) ExecuteTask(ctx context.Context, in *ExecuteTaskRequest, opts ...grpc.CallOption) (*ExecuteTaskResponse, error) {
+	out := new(ExecuteTaskResponse)
+	err := c.cc.Invoke(ctx, CoordinatorAdmin_ExecuteTask_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coordinatorAdminClient) GetResults(ctx context.Context, in *GetResultsRequest, opts ...grpc.CallOption) (*GetResultsResponse, error) {
+	out := new(GetResultsResponse)
+	err := c.cc.Invoke(ctx, CoordinatorAdmin_GetResults_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coordinatorAdminClient) Scale(ctx context.Context, in *ScaleRequest, opts ...grpc.CallOption) (*ScaleResponse, error) {
+	out := new(ScaleResponse)
+	err := c.cc.Invoke(ctx, CoordinatorAdmin_Scale_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CoordinatorAdminServer is the server API for CoordinatorAdmin service.
+// All implementations must embed UnimplementedCoordinatorAdminServer
+// for forward compatibility
+type CoordinatorAdminServer interface {
+	// SpawnDrone deploys a new drone and waits for it to be ready
+	SpawnDrone(context.Context, *SpawnDroneRequest) (*SpawnDroneResponse, error)
+	// ListDrones returns the active drones
+	ListDrones(context.Context, *ListDronesRequest) (*ListDronesResponse, error)
+	// ExecuteTask queues a task on the fleet and returns its ID
+	ExecuteTask(context.Context, *ExecuteTaskRequest) (*ExecuteTaskResponse, error)
+	// GetResults returns the results collected so far for a task
+	GetResults(context.Context, *GetResultsRequest) (*GetResultsResponse, error)
+	// Scale spawns or terminates drones of a type to reach a target count
+	Scale(context.Context, *ScaleRequest) (*ScaleResponse, error)
+	mustEmbedUnimplementedCoordinatorAdminServer()
+}
+
+// UnimplementedCoordinatorAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedCoordinatorAdminServer struct {
+}
+
+func (UnimplementedCoordinatorAdminServer) SpawnDrone(context.Context, *SpawnDroneRequest) (*SpawnDroneResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SpawnDrone not implemented")
+}
+func (UnimplementedCoordinatorAdminServer) ListDrones(context.Context, *ListDronesRequest) (*ListDronesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDrones not implemented")
+}
+func (UnimplementedCoordinatorAdminServer) ExecuteTask(context.Context, *ExecuteTaskRequest) (*ExecuteTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteTask not implemented")
+}
+func (UnimplementedCoordinatorAdminServer) GetResults(context.Context, *GetResultsRequest) (*GetResultsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetResults not implemented")
+}
+func (UnimplementedCoordinatorAdminServer) Scale(context.Context, *ScaleRequest) (*ScaleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Scale not implemented")
+}
+func (UnimplementedCoordinatorAdminServer) mustEmbedUnimplementedCoordinatorAdminServer() {}
+
+// UnsafeCoordinatorAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CoordinatorAdminServer will
+// result in compilation errors.
+type UnsafeCoordinatorAdminServer interface {
+	mustEmbedUnimplementedCoordinatorAdminServer()
+}
+
+func RegisterCoordinatorAdminServer(s grpc.ServiceRegistrar, srv CoordinatorAdminServer) {
+	s.RegisterService(&CoordinatorAdmin_ServiceDesc, srv)
+}
+
+func _CoordinatorAdmin_SpawnDrone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpawnDroneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorAdminServer).SpawnDrone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CoordinatorAdmin_SpawnDrone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorAdminServer).SpawnDrone(ctx, req.(*SpawnDroneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CoordinatorAdmin_ListDrones_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDronesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorAdminServer).ListDrones(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CoordinatorAdmin_ListDrones_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorAdminServer).ListDrones(ctx, req.(*ListDronesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CoordinatorAdmin_ExecuteTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorAdminServer).ExecuteTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CoordinatorAdmin_ExecuteTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorAdminServer).ExecuteTask(ctx, req.(*ExecuteTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CoordinatorAdmin_GetResults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetResultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorAdminServer).GetResults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CoordinatorAdmin_GetResults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorAdminServer).GetResults(ctx, req.(*GetResultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CoordinatorAdmin_Scale_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScaleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorAdminServer).Scale(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CoordinatorAdmin_Scale_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorAdminServer).Scale(ctx, req.(*ScaleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CoordinatorAdmin_ServiceDesc is the grpc.ServiceDesc for CoordinatorAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CoordinatorAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "coordinator.admin.v1.CoordinatorAdmin",
+	HandlerType: (*CoordinatorAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SpawnDrone",
+			Handler:    _CoordinatorAdmin_SpawnDrone_Handler,
+		},
+		{
+			MethodName: "ListDrones",
+			Handler:    _CoordinatorAdmin_ListDrones_Handler,
+		},
+		{
+			MethodName: "ExecuteTask",
+			Handler:    _CoordinatorAdmin_ExecuteTask_Handler,
+		},
+		{
+			MethodName: "GetResults",
+			Handler:    _CoordinatorAdmin_GetResults_Handler,
+		},
+		{
+			MethodName: "Scale",
+			Handler:    _CoordinatorAdmin_Scale_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
@@ -0,0 +1,4 @@
+// Package adminpb contains the generated gRPC admin API for the coordinator.
+package adminpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative admin.proto
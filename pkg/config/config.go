@@ -0,0 +1,251 @@
+// Package config loads the settings shared by every widescreen command from a
+// YAML file, the environment and command-line flags.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/encryption"
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secrets when a config is printed
+const redacted = "<redacted>"
+
+// envName matches the names of environment variables
+var envName = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+// Config is the configuration of every widescreen command. Each field is read
+// from the environment variable in its env tag, which overrides the file.
+// Packages read their settings from the environment, so Apply exports the
+// loaded config there.
+type Config struct {
+	Project  string `yaml:"project,omitempty" env:"GOOGLE_CLOUD_PROJECT"`
+	Region   string `yaml:"region,omitempty" env:"GOOGLE_CLOUD_REGION"`
+	Emulator bool   `yaml:"emulator,omitempty"`
+	Simulate bool   `yaml:"simulate,omitempty" env:"WIDESCREEN_SIMULATE"`
+
+	Tenant      string `yaml:"tenant,omitempty" env:"WIDESCREEN_TENANT"`
+	TenantsFile string `yaml:"tenants_file,omitempty" env:"WIDESCREEN_TENANTS_FILE"`
+
+	CredentialsFile           string `yaml:"credentials_file,omitempty" env:"GCP_CREDENTIALS_FILE"`
+	ImpersonateServiceAccount string `yaml:"impersonate_service_account,omitempty" env:"GCP_IMPERSONATE_SERVICE_ACCOUNT"`
+
+	ExaAPIKey string `yaml:"exa_api_key,omitempty" env:"EXA_API_KEY" secret:"true"`
+	// SearchProvider is the web search researcher drones use: exa, tavily, brave or serpapi
+	SearchProvider string `yaml:"search_provider,omitempty" env:"SEARCH_PROVIDER"`
+	TavilyAPIKey   string `yaml:"tavily_api_key,omitempty" env:"TAVILY_API_KEY" secret:"true"`
+	BraveAPIKey    string `yaml:"brave_api_key,omitempty" env:"BRAVE_API_KEY" secret:"true"`
+	SerpAPIKey     string `yaml:"serpapi_api_key,omitempty" env:"SERPAPI_API_KEY" secret:"true"`
+	ClaudeAPIKey   string `yaml:"claude_api_key,omitempty" env:"CLAUDE_API_KEY" secret:"true"`
+	OpenAIAPIKey   string `yaml:"openai_api_key,omitempty" env:"OPENAI_API_KEY" secret:"true"`
+
+	OrchestratorURL string `yaml:"orchestrator_url,omitempty" env:"ORCHESTRATOR_URL"`
+	CoordinatorURL  string `yaml:"coordinator_url,omitempty" env:"COORDINATOR_URL"`
+	ReportBaseURL   string `yaml:"report_base_url,omitempty" env:"REPORT_BASE_URL"`
+
+	AdminAPIToken   string `yaml:"admin_api_token,omitempty" env:"ADMIN_API_TOKEN" secret:"true"`
+	AdminHTTPAddr   string `yaml:"admin_http_addr,omitempty" env:"ADMIN_HTTP_ADDR"`
+	AdminGRPCAddr   string `yaml:"admin_grpc_addr,omitempty" env:"ADMIN_GRPC_ADDR"`
+	TriggerHTTPAddr string `yaml:"trigger_http_addr,omitempty" env:"TRIGGER_HTTP_ADDR"`
+	TriggerToken    string `yaml:"trigger_token,omitempty" env:"TRIGGER_TOKEN" secret:"true"`
+
+	// StorageKMSKey and FieldKMSKey are customer-managed Cloud KMS keys for
+	// objects written to Cloud Storage and result data stored in Firestore
+	StorageKMSKey string `yaml:"storage_kms_key,omitempty" env:"STORAGE_KMS_KEY"`
+	FieldKMSKey   string `yaml:"field_kms_key,omitempty" env:"FIELD_KMS_KEY"`
+
+	ShutdownDrainTimeout time.Duration `yaml:"shutdown_drain_timeout,omitempty" env:"SHUTDOWN_DRAIN_TIMEOUT"`
+	WarmPoolSize         int           `yaml:"warm_pool_size,omitempty" env:"WARM_POOL_SIZE"`
+
+	// Env sets any other environment variable the commands read, e.g.
+	// HEARTBEAT_MISSED_LIMIT. Variables already in the environment win.
+	Env map[string]string `yaml:"env,omitempty"`
+}
+
+// Load reads the config file at path, if any, and overrides it with the environment
+func Load(path string) (*Config, error) {
+	config := &Config{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	var errs []error
+	config.fields(func(field reflect.Value, env string, secret bool) {
+		value, ok := os.LookupEnv(env)
+		if !ok || value == "" {
+			return
+		}
+		if err := setField(field, value); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s %q: %w", env, value, err))
+		}
+	})
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Validate reports every setting that cannot work
+func (c *Config) Validate() error {
+	var errs []error
+	for name, value := range map[string]string{
+		"orchestrator_url": c.OrchestratorURL,
+		"coordinator_url":  c.CoordinatorURL,
+		"report_base_url":  c.ReportBaseURL,
+	} {
+		if value == "" {
+			continue
+		}
+		if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%s %q is not an http or https URL", name, value))
+		}
+	}
+	for name, value := range map[string]string{
+		"admin_http_addr":   c.AdminHTTPAddr,
+		"admin_grpc_addr":   c.AdminGRPCAddr,
+		"trigger_http_addr": c.TriggerHTTPAddr,
+	} {
+		if value == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s %q is not a host:port address", name, value))
+		}
+	}
+	switch c.SearchProvider {
+	case "", "exa", "tavily", "brave", "serpapi":
+	default:
+		errs = append(errs, fmt.Errorf("search_provider %q is not exa, tavily, brave or serpapi", c.SearchProvider))
+	}
+	for name, value := range map[string]string{
+		"storage_kms_key": c.StorageKMSKey,
+		"field_kms_key":   c.FieldKMSKey,
+	} {
+		if value != "" && !encryption.ValidKeyName(value) {
+			errs = append(errs, fmt.Errorf("%s %q is not a Cloud KMS key name", name, value))
+		}
+	}
+	if c.ShutdownDrainTimeout < 0 {
+		errs = append(errs, fmt.Errorf("shutdown_drain_timeout must not be negative"))
+	}
+	if c.WarmPoolSize < 0 {
+		errs = append(errs, fmt.Errorf("warm_pool_size must not be negative"))
+	}
+	for name := range c.Env {
+		if !envName.MatchString(name) {
+			errs = append(errs, fmt.Errorf("env %q is not a valid environment variable name", name))
+		}
+	}
+	// Sort so the same config always reports the same error
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errors.Join(errs...)
+}
+
+// Apply exports the config to the environment the commands read their settings from
+func (c *Config) Apply() error {
+	var errs []error
+	c.fields(func(field reflect.Value, env string, secret bool) {
+		if field.IsZero() {
+			return
+		}
+		if err := os.Setenv(env, formatField(field)); err != nil {
+			errs = append(errs, err)
+		}
+	})
+	for name, value := range c.Env {
+		if _, ok := os.LookupEnv(name); ok {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy of the config with its secrets hidden, for printing
+func (c *Config) Redacted() *Config {
+	copied := *c
+	copied.fields(func(field reflect.Value, env string, secret bool) {
+		if secret && !field.IsZero() {
+			field.SetString(redacted)
+		}
+	})
+	return &copied
+}
+
+// fields calls fn with each field read from the environment
+func (c *Config) fields(fn func(field reflect.Value, env string, secret bool)) {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		env := t.Field(i).Tag.Get("env")
+		if env == "" {
+			continue
+		}
+		fn(v.Field(i), env, t.Field(i).Tag.Get("secret") == "true")
+	}
+}
+
+// setField parses an environment variable into a field
+func setField(field reflect.Value, value string) error {
+	switch field.Interface().(type) {
+	case string:
+		field.SetString(value)
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+	case int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(n))
+	default:
+		return fmt.Errorf("unsupported config field type %s", field.Type())
+	}
+	return nil
+}
+
+// formatField formats a field the way setField parses it
+func formatField(field reflect.Value) string {
+	switch v := field.Interface().(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Duration:
+		return v.String()
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return field.String()
+	}
+}
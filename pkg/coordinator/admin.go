@@ -0,0 +1,254 @@
+package coordinator
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spawn-mcp/coordinator/pkg/adminpb"
+	"github.com/spawn-mcp/coordinator/pkg/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// adminServer exposes the coordinator over the CoordinatorAdmin gRPC service
+type adminServer struct {
+	adminpb.UnimplementedCoordinatorAdminServer
+	coordinator *Server
+}
+
+// adminAddr returns the address the admin API listens on, from ADMIN_GRPC_ADDR
+// or else the PORT Cloud Run assigns. Without ADMIN_API_TOKEN the API is only
+// reachable from the same host, whatever address is configured.
+func adminAddr() (string, error) {
+	addr := os.Getenv("ADMIN_GRPC_ADDR")
+	if addr == "" {
+		port := os.Getenv("PORT")
+		if port == "" {
+			port = "8080"
+		}
+		addr = ":" + port
+	}
+	if os.Getenv("ADMIN_API_TOKEN") != "" {
+		return addr, nil
+	}
+
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid admin API address %q: %w", addr, err)
+	}
+	log.Printf("Warning: ADMIN_API_TOKEN is not set, the admin API only listens on localhost")
+	return net.JoinHostPort("127.0.0.1", port), nil
+}
+
+// validAdminToken reports whether any of the authorization values is "Bearer <token>"
+func validAdminToken(authorization []string, token string) bool {
+	for _, value := range authorization {
+		provided, ok := strings.CutPrefix(value, "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenInterceptor rejects calls that do not carry "authorization: Bearer <token>"
+func tokenInterceptor(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if !validAdminToken(md.Get("authorization"), token) {
+			return nil, status.Error(codes.Unauthenticated, "missing or invalid admin token")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AdminAuth wraps an HTTP handler so requests must carry an
+// "Authorization: Bearer <token>" header matching ADMIN_API_TOKEN, the same
+// token the gRPC admin API requires. Browsers, whose event streams cannot set
+// headers, may pass it as an access_token query parameter instead. Every
+// request is rejected when it is unset.
+func AdminAuth(next http.Handler) http.Handler {
+	token := os.Getenv("ADMIN_API_TOKEN")
+	if token == "" {
+		log.Printf("Warning: ADMIN_API_TOKEN is not set, the HTTP admin API rejects every request")
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "admin API is disabled: ADMIN_API_TOKEN is not set", http.StatusUnauthorized)
+		})
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Values("Authorization")
+		if queryToken := r.URL.Query().Get("access_token"); queryToken != "" {
+			authorization = append(authorization, "Bearer "+queryToken)
+		}
+		if !validAdminToken(authorization, token) {
+			http.Error(w, "missing or invalid admin token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newAdminGRPCServer creates a gRPC server with the admin service registered,
+// requiring ADMIN_API_TOKEN on every call when it is set
+func newAdminGRPCServer(s *Server) *grpc.Server {
+	var opts []grpc.ServerOption
+	if token := os.Getenv("ADMIN_API_TOKEN"); token != "" {
+		opts = append(opts, grpc.UnaryInterceptor(tokenInterceptor(token)))
+	}
+
+	grpcServer := grpc.NewServer(opts...)
+	adminpb.RegisterCoordinatorAdminServer(grpcServer, &adminServer{coordinator: s})
+	return grpcServer
+}
+
+// SpawnDrone deploys a new drone
+func (a *adminServer) SpawnDrone(ctx context.Context, req *adminpb.SpawnDroneRequest) (*adminpb.SpawnDroneResponse, error) {
+	if req.DroneType == "" {
+		return nil, status.Error(codes.InvalidArgument, "drone_type is required")
+	}
+
+	droneType := types.DroneType(req.DroneType)
+	region := req.Region
+	if region == "" {
+		region = a.coordinator.gcpClient.Region
+	}
+
+	droneID, err := a.coordinator.SpawnDrone(ctx, types.DroneConfig{
+		Type:           droneType,
+		Region:         region,
+		Capabilities:   a.coordinator.getDefaultCapabilities(droneType),
+		Environment:    make(map[string]string),
+		SessionID:      req.SessionId,
+		ServiceAccount: req.ServiceAccount,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to spawn drone: %v", err)
+	}
+
+	return &adminpb.SpawnDroneResponse{DroneId: droneID}, nil
+}
+
+// ListDrones returns the active drones, optionally of a single type
+func (a *adminServer) ListDrones(ctx context.Context, req *adminpb.ListDronesRequest) (*adminpb.ListDronesResponse, error) {
+	resp := &adminpb.ListDronesResponse{}
+	for _, drone := range a.coordinator.ListActiveDrones() {
+		if req.DroneType != "" && drone.Type != req.DroneType {
+			continue
+		}
+		resp.Drones = append(resp.Drones, &adminpb.Drone{
+			Id:             drone.ID,
+			Type:           drone.Type,
+			Status:         drone.Status,
+			Region:         drone.Region,
+			ServiceUrl:     drone.ServiceURL,
+			Capabilities:   drone.Capabilities,
+			TasksCompleted: int32(drone.TasksCompleted),
+			CreatedAt:      timestamppb.New(drone.CreatedAt),
+			LastSeen:       timestamppb.New(drone.LastSeen),
+		})
+	}
+	return resp, nil
+}
+
+// ExecuteTask queues a task on the fleet
+func (a *adminServer) ExecuteTask(ctx context.Context, req *adminpb.ExecuteTaskRequest) (*adminpb.ExecuteTaskResponse, error) {
+	if req.Type == "" || req.Description == "" {
+		return nil, status.Error(codes.InvalidArgument, "type and description are required")
+	}
+
+	maxDrones := int(req.MaxDrones)
+	if maxDrones <= 0 {
+		maxDrones = 3
+	}
+	priority := types.TaskPriority(req.Priority)
+	if priority == "" {
+		priority = types.TaskPriorityNormal
+	}
+	if _, ok := priorityRank[priority]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid priority %q", req.Priority)
+	}
+
+	task := types.Task{
+		Type:                 req.Type,
+		Description:          req.Description,
+		MaxDrones:            maxDrones,
+		RequiredCapabilities: req.RequiredCapabilities,
+		Priority:             priority,
+		Concurrency:          int(req.Concurrency),
+	}
+	if req.MaxRetries > 0 {
+		task.Checkpoint = types.CheckpointConfig{
+			Enabled:         true,
+			IntervalSeconds: 30,
+			MaxRetries:      int(req.MaxRetries),
+		}
+	}
+
+	taskID, err := a.coordinator.ExecuteTask(ctx, task)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to execute task: %v", err)
+	}
+
+	return &adminpb.ExecuteTaskResponse{TaskId: taskID}, nil
+}
+
+// GetResults returns the results collected so far for a task
+func (a *adminServer) GetResults(ctx context.Context, req *adminpb.GetResultsRequest) (*adminpb.GetResultsResponse, error) {
+	if req.TaskId == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+
+	results, err := a.coordinator.GetTaskResults(req.TaskId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+
+	resp := &adminpb.GetResultsResponse{}
+	for _, result := range results {
+		data, err := json.Marshal(result.Data)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encode result from drone %s: %v", result.DroneID, err)
+		}
+		resp.Results = append(resp.Results, &adminpb.TaskResult{
+			TaskId:    result.TaskID,
+			DroneId:   result.DroneID,
+			Status:    result.Status,
+			DataJson:  string(data),
+			Error:     result.Error,
+			Timestamp: timestamppb.New(result.Timestamp),
+		})
+	}
+	return resp, nil
+}
+
+// Scale spawns or terminates drones of a type to reach a target count
+func (a *adminServer) Scale(ctx context.Context, req *adminpb.ScaleRequest) (*adminpb.ScaleResponse, error) {
+	if req.DroneType == "" {
+		return nil, status.Error(codes.InvalidArgument, "drone_type is required")
+	}
+	if req.TargetCount < 0 {
+		return nil, status.Error(codes.InvalidArgument, "target_count must not be negative")
+	}
+
+	if err := a.coordinator.ScaleDrones(ctx, types.DroneType(req.DroneType), int(req.TargetCount)); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to scale drones: %v", err)
+	}
+
+	var active int32
+	for _, drone := range a.coordinator.ListActiveDrones() {
+		if drone.Type == req.DroneType {
+			active++
+		}
+	}
+	return &adminpb.ScaleResponse{ActiveCount: active}, nil
+}
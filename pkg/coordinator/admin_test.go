@@ -0,0 +1,80 @@
+package coordinator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminAddr(t *testing.T) {
+	tests := []struct {
+		name  string
+		addr  string
+		port  string
+		token string
+		want  string
+	}{
+		{"default port with a token", "", "", "secret", ":8080"},
+		{"Cloud Run port with a token", "", "9090", "secret", ":9090"},
+		{"configured address with a token", "0.0.0.0:7000", "9090", "secret", "0.0.0.0:7000"},
+		{"default port without a token", "", "", "", "127.0.0.1:8080"},
+		{"Cloud Run port without a token", "", "9090", "", "127.0.0.1:9090"},
+		{"configured address without a token", "0.0.0.0:7000", "", "", "127.0.0.1:7000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ADMIN_GRPC_ADDR", tt.addr)
+			t.Setenv("PORT", tt.port)
+			t.Setenv("ADMIN_API_TOKEN", tt.token)
+			got, err := adminAddr()
+			if err != nil {
+				t.Fatalf("adminAddr() returned an error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("adminAddr() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Setenv("ADMIN_GRPC_ADDR", "no-port")
+	t.Setenv("ADMIN_API_TOKEN", "")
+	if _, err := adminAddr(); err == nil {
+		t.Error("adminAddr() with an address without a port and no token returned no error")
+	}
+}
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		query  string
+		want   int
+	}{
+		{"valid header", "secret", "Bearer secret", "", http.StatusOK},
+		{"valid query parameter", "secret", "", "secret", http.StatusOK},
+		{"wrong token", "secret", "Bearer other", "", http.StatusUnauthorized},
+		{"no credentials", "secret", "", "", http.StatusUnauthorized},
+		{"no token configured", "", "", "", http.StatusUnauthorized},
+		{"empty bearer without a token configured", "", "Bearer ", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ADMIN_API_TOKEN", tt.token)
+			handler := AdminAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			req := httptest.NewRequest(http.MethodGet, "/drones", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if tt.query != "" {
+				req.URL.RawQuery = "access_token=" + tt.query
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
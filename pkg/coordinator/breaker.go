@@ -0,0 +1,158 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned for calls to a drone whose recent calls failed,
+// until its cooldown has passed and a trial call may try it again
+var ErrCircuitOpen = errors.New("drone circuit breaker is open")
+
+// BreakerConfig configures the circuit breaker kept for each drone URL
+type BreakerConfig struct {
+	// Threshold is how many calls in a row must fail to open a drone's circuit
+	Threshold int
+	// Cooldown is how long an open circuit rejects calls before letting one through
+	Cooldown time.Duration
+}
+
+// LoadBreakerConfig reads the drone circuit breaker configuration from the environment
+func LoadBreakerConfig() BreakerConfig {
+	config := BreakerConfig{Threshold: 3, Cooldown: 30 * time.Second}
+	if threshold, err := strconv.Atoi(os.Getenv("DRONE_BREAKER_THRESHOLD")); err == nil && threshold > 0 {
+		config.Threshold = threshold
+	}
+	if cooldown, err := time.ParseDuration(os.Getenv("DRONE_BREAKER_COOLDOWN")); err == nil && cooldown > 0 {
+		config.Cooldown = cooldown
+	}
+	return config
+}
+
+// circuit is the breaker state of one drone URL
+type circuit struct {
+	failures int
+	// openedAt is when the circuit last opened; zero while it is closed
+	openedAt time.Time
+	// trial is set while the one call let through after the cooldown is in flight
+	trial bool
+}
+
+// circuitBreakers track failing drones, so calls to a drone that keeps timing
+// out fail at once instead of each waiting for the HTTP timeout. A circuit
+// opens after Threshold failed calls in a row. Once Cooldown has passed, a
+// single trial call is let through while other calls are still rejected: its
+// success closes the circuit, and its failure opens it for another cooldown.
+type circuitBreakers struct {
+	config   BreakerConfig
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// newCircuitBreakers creates breakers with every circuit closed
+func newCircuitBreakers(config BreakerConfig) *circuitBreakers {
+	return &circuitBreakers{config: config, circuits: make(map[string]*circuit)}
+}
+
+// allow returns ErrCircuitOpen if calls to url are being rejected
+func (b *circuitBreakers) allow(url string) error {
+	return b.admit(url, false)
+}
+
+// acquire is allow for a call about to be sent. When an open circuit's
+// cooldown has passed, the call becomes its trial call, and other calls are
+// rejected until the trial's outcome is recorded or it is released.
+func (b *circuitBreakers) acquire(url string) error {
+	return b.admit(url, true)
+}
+
+// admit returns ErrCircuitOpen if calls to url are being rejected, and
+// otherwise makes the call the circuit's trial call when trial is set
+func (b *circuitBreakers) admit(url string, trial bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuits[url]
+	if c == nil || c.openedAt.IsZero() {
+		return nil
+	}
+	if wait := time.Until(c.openedAt.Add(b.config.Cooldown)); wait > 0 {
+		return fmt.Errorf("%w for %s, retry after %v", ErrCircuitOpen, url, wait.Round(time.Second))
+	}
+	if c.trial {
+		return fmt.Errorf("%w for %s, a trial call is in progress", ErrCircuitOpen, url)
+	}
+	c.trial = trial
+	return nil
+}
+
+// record counts the outcome of a call to url
+func (b *circuitBreakers) record(url string, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuits[url]
+	if !failed {
+		if c != nil && !c.openedAt.IsZero() {
+			log.Printf("Drone %s recovered, closing its circuit", url)
+		}
+		delete(b.circuits, url)
+		return
+	}
+	if c == nil {
+		c = &circuit{}
+		b.circuits[url] = c
+	}
+	c.failures++
+	c.trial = false
+	// A failed trial call reopens the circuit for another cooldown
+	if !c.openedAt.IsZero() || c.failures >= b.config.Threshold {
+		if c.openedAt.IsZero() {
+			log.Printf("Warning: Opening circuit of drone %s after %d failed calls", url, c.failures)
+		}
+		c.openedAt = time.Now()
+	}
+}
+
+// release lets another call be the trial call of url's circuit, for a trial
+// call whose outcome is not recorded
+func (b *circuitBreakers) release(url string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c := b.circuits[url]; c != nil {
+		c.trial = false
+	}
+}
+
+// forget drops the circuit of url
+func (b *circuitBreakers) forget(url string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.circuits, url)
+}
+
+// send sends a request to the drone at droneURL through its circuit breaker.
+// Transport errors and 5xx responses count as failures; calls the caller
+// cancelled do not count either way.
+func (c *MCPClient) send(client *http.Client, req *http.Request, droneURL string) (*http.Response, error) {
+	if err := c.breakers.acquire(droneURL); err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil && errors.Is(req.Context().Err(), context.Canceled) {
+		c.breakers.release(droneURL)
+		return nil, err
+	}
+	c.breakers.record(droneURL, err != nil || resp.StatusCode >= http.StatusInternalServerError)
+	return resp, err
+}
+
+// Available reports whether calls to the drone at droneURL are let through by its circuit breaker
+func (c *MCPClient) Available(droneURL string) bool {
+	return c.breakers.allow(droneURL) == nil
+}
@@ -0,0 +1,182 @@
+package coordinator
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testDrone = "https://drone.example"
+
+// expireCooldown moves the open circuit of url back past its cooldown
+func expireCooldown(b *circuitBreakers, url string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.circuits[url].openedAt = time.Now().Add(-b.config.Cooldown - time.Second)
+}
+
+func TestCircuitBreakerOpensAtThreshold(t *testing.T) {
+	b := newCircuitBreakers(BreakerConfig{Threshold: 3, Cooldown: time.Minute})
+	for i := 0; i < 2; i++ {
+		b.record(testDrone, true)
+		if err := b.allow(testDrone); err != nil {
+			t.Fatalf("circuit opened after %d failures, want 3: %v", i+1, err)
+		}
+	}
+
+	// A success resets the count of failures in a row
+	b.record(testDrone, false)
+	b.record(testDrone, true)
+	b.record(testDrone, true)
+	if err := b.allow(testDrone); err != nil {
+		t.Fatalf("circuit opened after a success and 2 failures: %v", err)
+	}
+
+	b.record(testDrone, true)
+	if err := b.allow(testDrone); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow() after 3 failures in a row = %v, want ErrCircuitOpen", err)
+	}
+	if err := b.allow("https://other.example"); err != nil {
+		t.Errorf("another drone's circuit opened: %v", err)
+	}
+}
+
+func TestCircuitBreakerRejectsDuringCooldown(t *testing.T) {
+	b := newCircuitBreakers(BreakerConfig{Threshold: 1, Cooldown: time.Minute})
+	b.record(testDrone, true)
+	for i := 0; i < 3; i++ {
+		if err := b.allow(testDrone); !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("allow() during cooldown = %v, want ErrCircuitOpen", err)
+		}
+		if err := b.acquire(testDrone); !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("acquire() during cooldown = %v, want ErrCircuitOpen", err)
+		}
+	}
+
+	b.forget(testDrone)
+	if err := b.allow(testDrone); err != nil {
+		t.Errorf("allow() after forget = %v, want nil", err)
+	}
+}
+
+func TestCircuitBreakerTrialCall(t *testing.T) {
+	tests := []struct {
+		name string
+		// outcome ends the trial call: "success", "failure" or "release"
+		outcome  string
+		wantOpen bool
+		// wantTrial is whether a new trial call may be made right after
+		wantTrial bool
+	}{
+		{"success closes the circuit", "success", false, true},
+		{"failure reopens the circuit", "failure", true, false},
+		{"released trial lets another through", "release", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newCircuitBreakers(BreakerConfig{Threshold: 2, Cooldown: time.Minute})
+			b.record(testDrone, true)
+			b.record(testDrone, true)
+			expireCooldown(b, testDrone)
+
+			// Checking does not take the trial
+			if err := b.allow(testDrone); err != nil {
+				t.Fatalf("allow() after the cooldown = %v, want nil", err)
+			}
+			if err := b.acquire(testDrone); err != nil {
+				t.Fatalf("acquire() after the cooldown = %v, want nil", err)
+			}
+			if err := b.acquire(testDrone); !errors.Is(err, ErrCircuitOpen) {
+				t.Fatalf("second acquire() during the trial = %v, want ErrCircuitOpen", err)
+			}
+			if err := b.allow(testDrone); !errors.Is(err, ErrCircuitOpen) {
+				t.Fatalf("allow() during the trial = %v, want ErrCircuitOpen", err)
+			}
+
+			switch tt.outcome {
+			case "success":
+				b.record(testDrone, false)
+			case "failure":
+				b.record(testDrone, true)
+			case "release":
+				b.release(testDrone)
+			}
+
+			b.mu.Lock()
+			c := b.circuits[testDrone]
+			open := c != nil && !c.openedAt.IsZero()
+			b.mu.Unlock()
+			if open != tt.wantOpen {
+				t.Errorf("circuit open = %v, want %v", open, tt.wantOpen)
+			}
+			if err := b.acquire(testDrone); (err == nil) != tt.wantTrial {
+				t.Errorf("acquire() after the trial = %v, want allowed %v", err, tt.wantTrial)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerSingleTrialUnderConcurrency(t *testing.T) {
+	b := newCircuitBreakers(BreakerConfig{Threshold: 1, Cooldown: time.Minute})
+	b.record(testDrone, true)
+	expireCooldown(b, testDrone)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	admitted := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if b.acquire(testDrone) == nil {
+				mu.Lock()
+				admitted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if admitted != 1 {
+		t.Errorf("%d concurrent calls were let through after the cooldown, want 1", admitted)
+	}
+}
+
+func TestSendRecordsOutcome(t *testing.T) {
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	c := &MCPClient{breakers: newCircuitBreakers(BreakerConfig{Threshold: 1, Cooldown: time.Minute})}
+	get := func() error {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := c.send(server.Client(), req, server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := get(); err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	if c.Available(server.URL) {
+		t.Fatal("drone is available after a 5xx response, want its circuit open")
+	}
+	if err := get(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("call during cooldown = %v, want ErrCircuitOpen", err)
+	}
+
+	expireCooldown(c.breakers, server.URL)
+	status = http.StatusOK
+	if err := get(); err != nil {
+		t.Fatalf("trial call failed: %v", err)
+	}
+	if !c.Available(server.URL) {
+		t.Error("drone is unavailable after a successful trial call")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
 	"github.com/spawn-mcp/coordinator/pkg/types"
 )
 
@@ -54,22 +55,32 @@ func (s *Server) PlanCampaign(ctx context.Context, spec types.CampaignSpec) (*ty
 	return plan, nil
 }
 
+// campaignStates are the allowed moves between campaign run states
+var campaignStates = gcp.StateMachine{
+	"":          {"launching", "aborted"},
+	"launching": {"running", "aborted"},
+	"running":   {"completed", "aborted"},
+}
+
 // LaunchFleet provisions workers and seeds queue for the given run.
 func (s *Server) LaunchFleet(ctx context.Context, runID string, targetWorkers int) (string, error) {
 	if targetWorkers <= 0 {
 		targetWorkers = 10
 	}
-	// Placeholder: spawn research drones using existing SpawnDrone
-	for i := 0; i < targetWorkers; i++ {
-		_, _ = s.SpawnDrone(ctx, types.DroneConfig{Type: types.DroneTypeResearcher, Region: s.gcpClient.Region})
-	}
-	statusID := fmt.Sprintf("status-%s", runID)
-	_ = s.gcpClient.StoreDocument(ctx, "campaign_status", runID, map[string]any{
+	// Claim the run first so a run cannot be launched twice or after it was aborted
+	_, err := s.gcpClient.TransitionDocument(ctx, "campaign_status", runID, "state", "launching", campaignStates, map[string]interface{}{
 		"run_id": runID,
 		"workers": targetWorkers,
-		"state": "launching",
 		"updated_at": time.Now(),
 	})
+	if err != nil {
+		return "", fmt.Errorf("launch run %s: %w", runID, err)
+	}
+	// Placeholder: spawn research drones using existing SpawnDrone
+	for i := 0; i < targetWorkers; i++ {
+		_, _ = s.SpawnDrone(ctx, types.DroneConfig{Type: types.DroneTypeResearcher, Region: s.gcpClient.Region, SessionID: runID, IdempotencyKey: gcp.SpawnKey(runID, i)})
+	}
+	statusID := fmt.Sprintf("status-%s", runID)
 	return statusID, nil
 }
 
@@ -86,11 +97,11 @@ func (s *Server) FleetStatus(ctx context.Context, runID string) (map[string]any,
 // AbortRun scales down workers and marks run aborted.
 func (s *Server) AbortRun(ctx context.Context, runID string) error {
 	// Placeholder: no-op beyond status marker
-	return s.gcpClient.StoreDocument(ctx, "campaign_status", runID, map[string]any{
+	_, err := s.gcpClient.TransitionDocument(ctx, "campaign_status", runID, "state", "aborted", campaignStates, map[string]interface{}{
 		"run_id": runID,
-		"state": "aborted",
 		"updated_at": time.Now(),
 	})
+	return err
 }
 
 // ExportGraph placeholder; in MVP this would read mem0 and dump edges.
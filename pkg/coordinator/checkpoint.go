@@ -0,0 +1,124 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// saveCheckpoint stores a checkpoint for the drone it names
+func (s *Server) saveCheckpoint(ctx context.Context, checkpoint *types.TaskCheckpoint) {
+	checkpoint.Timestamp = time.Now()
+	if s.gcpClient.FirestoreClient == nil {
+		return
+	}
+	err := s.gcpClient.StoreDocument(ctx, types.CheckpointCollection, types.CheckpointID(checkpoint.TaskID, checkpoint.DroneID), checkpoint)
+	if err != nil {
+		log.Printf("Warning: Failed to store checkpoint for task %s on drone %s: %v", checkpoint.TaskID, checkpoint.DroneID, err)
+	}
+}
+
+// loadCheckpoint returns the last checkpoint a drone reported for a task
+func (s *Server) loadCheckpoint(ctx context.Context, taskID, droneID string) (*types.TaskCheckpoint, error) {
+	if s.gcpClient.FirestoreClient == nil {
+		return nil, fmt.Errorf("checkpoints are not stored without Firestore")
+	}
+	var checkpoint types.TaskCheckpoint
+	if err := s.gcpClient.GetDocument(ctx, types.CheckpointCollection, types.CheckpointID(taskID, droneID), &checkpoint); err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// claimReplacement marks the failed drone unhealthy and claims the best idle
+// drone that can take over its share of the task
+func (s *Server) claimReplacement(task types.Task, failed *types.DroneInfo) *types.DroneInfo {
+	s.dronesMutex.Lock()
+	defer s.dronesMutex.Unlock()
+
+	failed.Status = "unhealthy"
+
+	candidates := matchDrones(s.activeDrones, task)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	replacement := candidates[0]
+	replacement.Status = string(types.DroneStatusBusy)
+	return replacement
+}
+
+// runOnDrone executes a drone's share of a task. With checkpointing enabled,
+// a failed drone's work is resumed on a replacement drone from its last
+// checkpoint, which is sent along with the task, up to MaxRetries times. Returns the result and any replacement
+// drones that were claimed so the caller can release them.
+func (s *Server) runOnDrone(ctx context.Context, taskID string, task types.Task, drone *types.DroneInfo) (*types.TaskResult, []*types.DroneInfo) {
+	result := &types.TaskResult{
+		TaskID:    taskID,
+		DroneID:   drone.ID,
+		Status:    "executing",
+		Timestamp: time.Now(),
+	}
+
+	checkpoint := &types.TaskCheckpoint{
+		TaskID:  taskID,
+		DroneID: drone.ID,
+		State:   make(map[string]interface{}),
+	}
+
+	var claimed []*types.DroneInfo
+	for {
+		// The drone resumes from this record and updates it as it makes progress
+		if task.Checkpoint.Enabled {
+			s.saveCheckpoint(ctx, checkpoint)
+		}
+
+		req := DroneTask{Subject: task.Description, RunID: taskID}
+		if task.Checkpoint.Enabled {
+			req.TaskID = taskID
+			req.CheckpointIntervalSec = task.Checkpoint.IntervalSeconds
+			if checkpoint.RetryCount > 0 {
+				req.Resume = checkpoint
+			}
+		}
+		accepted, err := s.mcpClient.SendTask(ctx, drone.ServiceURL, req)
+		if err == nil {
+			result.DroneID = drone.ID
+			result.Status = "completed"
+			result.Data = map[string]interface{}{"message": accepted}
+			result.Error = ""
+			log.Printf("Drone %s accepted task %s", drone.ID, taskID)
+			return result, claimed
+		}
+
+		result.Status = "failed"
+		result.Error = err.Error()
+		log.Printf("Failed to call drone %s: %v", drone.ID, err)
+
+		if !task.Checkpoint.Enabled || checkpoint.RetryCount >= task.Checkpoint.MaxRetries {
+			return result, claimed
+		}
+
+		// Pick up from whatever progress the failed drone last reported
+		if last, err := s.loadCheckpoint(ctx, taskID, drone.ID); err == nil {
+			checkpoint = last
+		}
+
+		replacement := s.claimReplacement(task, drone)
+		if replacement == nil {
+			log.Printf("No replacement drone available to resume task %s from drone %s", taskID, drone.ID)
+			return result, claimed
+		}
+		claimed = append(claimed, replacement)
+
+		checkpoint.RetryCount++
+		checkpoint.DroneID = replacement.ID
+		log.Printf("Resuming task %s from drone %s on drone %s at %.0f%% (retry %d/%d)",
+			taskID, drone.ID, replacement.ID, checkpoint.Progress*100, checkpoint.RetryCount, task.Checkpoint.MaxRetries)
+
+		drone = replacement
+	}
+}
@@ -0,0 +1,72 @@
+package coordinator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+func TestRunOnDroneResumesOnReplacement(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "drone crashed", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	received := make(chan DroneTask, 1)
+	replacing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/task" {
+			http.NotFound(w, r)
+			return
+		}
+		var task DroneTask
+		if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+			http.Error(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+		received <- task
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer replacing.Close()
+
+	client := &MCPClient{
+		breakers: newCircuitBreakers(BreakerConfig{Threshold: 5, Cooldown: time.Minute}),
+		clients: map[string]*http.Client{
+			failing.URL:   failing.Client(),
+			replacing.URL: replacing.Client(),
+		},
+	}
+	failed := &types.DroneInfo{ID: "d1", Type: "research", Status: string(types.DroneStatusBusy), ServiceURL: failing.URL}
+	replacement := &types.DroneInfo{ID: "d2", Type: "research", Status: "active", ServiceURL: replacing.URL, Capabilities: []string{"web-search", "information-extraction"}}
+	s := &Server{
+		gcpClient:    &gcp.Client{},
+		mcpClient:    client,
+		activeDrones: map[string]*types.DroneInfo{"d1": failed, "d2": replacement},
+	}
+
+	task := types.Task{
+		Type:        "research",
+		Description: "quantum networking",
+		Checkpoint:  types.CheckpointConfig{Enabled: true, IntervalSeconds: 10, MaxRetries: 1},
+	}
+	result, claimed := s.runOnDrone(context.Background(), "task-1", task, failed)
+	if result.Status != "completed" || result.DroneID != "d2" {
+		t.Fatalf("result = %s on %s (%s), want completed on d2", result.Status, result.DroneID, result.Error)
+	}
+	if len(claimed) != 1 || claimed[0] != replacement {
+		t.Errorf("claimed = %v, want the replacement", claimed)
+	}
+
+	sent := <-received
+	if sent.TaskID != "task-1" || sent.Subject != "quantum networking" || sent.CheckpointIntervalSec != 10 {
+		t.Errorf("replacement got task %+v, want task-1 on the task's subject with its checkpoint interval", sent)
+	}
+	if sent.Resume == nil || sent.Resume.TaskID != "task-1" || sent.Resume.DroneID != "d2" || sent.Resume.RetryCount != 1 {
+		t.Errorf("replacement resumes from %+v, want the task's checkpoint handed to d2 on retry 1", sent.Resume)
+	}
+}
@@ -0,0 +1,159 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// dashboardErrorLimit caps how many recent errors a dashboard snapshot lists
+const dashboardErrorLimit = 20
+
+// DashboardSession is a running research session or campaign run
+type DashboardSession struct {
+	ID     string `json:"id"`
+	Kind   string `json:"kind"`
+	Status string `json:"status"`
+	// Total is 0 when the session does not report drone progress
+	Total     int       `json:"total"`
+	Completed int       `json:"completed"`
+	Failed    int       `json:"failed"`
+	LastError string    `json:"lastError,omitempty"`
+	StartedAt time.Time `json:"startedAt,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+// DashboardError is a recent failure shown on the dashboard
+type DashboardError struct {
+	Source  string    `json:"source"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// DashboardSnapshot is the fleet and session state shown on the status page
+type DashboardSnapshot struct {
+	GeneratedAt time.Time          `json:"generatedAt"`
+	Drones      []*types.DroneInfo `json:"drones"`
+	QueuedTasks int                `json:"queuedTasks"`
+	Sessions    []DashboardSession `json:"sessions"`
+	Errors      []DashboardError   `json:"errors"`
+}
+
+// Dashboard returns the current fleet and session state. Firestore failures are
+// logged and leave the affected sections empty, so the page keeps updating.
+func (s *Server) Dashboard(ctx context.Context) *DashboardSnapshot {
+	snapshot := &DashboardSnapshot{
+		GeneratedAt: time.Now(),
+		Drones:      s.ListActiveDrones(),
+		QueuedTasks: s.QueuedTasks(),
+		Sessions:    []DashboardSession{},
+		Errors:      []DashboardError{},
+	}
+	sort.Slice(snapshot.Drones, func(i, j int) bool {
+		return snapshot.Drones[i].ID < snapshot.Drones[j].ID
+	})
+
+	for _, drone := range snapshot.Drones {
+		if drone.Status == "unhealthy" || drone.Status == string(types.DroneStatusFailed) {
+			snapshot.Errors = append(snapshot.Errors, DashboardError{
+				Source:  "drone " + drone.ID,
+				Message: "drone is " + drone.Status,
+				Time:    drone.LastPing,
+			})
+		}
+	}
+
+	if s.gcpClient.FirestoreClient == nil {
+		return snapshot
+	}
+
+	sessions, err := s.runningSessions(ctx)
+	if err != nil {
+		log.Printf("Warning: Failed to load sessions for dashboard: %v", err)
+	}
+	snapshot.Sessions = append(snapshot.Sessions, sessions...)
+	for _, session := range sessions {
+		if session.LastError != "" {
+			snapshot.Errors = append(snapshot.Errors, DashboardError{
+				Source:  session.Kind + " " + session.ID,
+				Message: session.LastError,
+				Time:    session.UpdatedAt,
+			})
+		}
+	}
+
+	failed, _, err := s.QueryTaskResults(ctx, types.TaskResultQuery{Status: "failed", Limit: dashboardErrorLimit})
+	if err != nil {
+		log.Printf("Warning: Failed to load failed task results for dashboard: %v", err)
+	}
+	for _, result := range failed {
+		snapshot.Errors = append(snapshot.Errors, DashboardError{
+			Source:  fmt.Sprintf("task %s on drone %s", result.TaskID, result.DroneID),
+			Message: result.Error,
+			Time:    result.Timestamp,
+		})
+	}
+
+	sort.Slice(snapshot.Errors, func(i, j int) bool {
+		return snapshot.Errors[i].Time.After(snapshot.Errors[j].Time)
+	})
+	if len(snapshot.Errors) > dashboardErrorLimit {
+		snapshot.Errors = snapshot.Errors[:dashboardErrorLimit]
+	}
+
+	return snapshot
+}
+
+// runningSessions returns the research sessions and campaign runs that have not finished
+func (s *Server) runningSessions(ctx context.Context) ([]DashboardSession, error) {
+	research, err := s.gcpClient.QueryDocuments(ctx, "research_sessions", gcp.DocumentQuery{
+		Filters: []gcp.QueryFilter{{Path: "status", Op: "in", Value: []string{"initializing", "running"}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query research sessions: %w", err)
+	}
+	campaigns, err := s.gcpClient.QueryDocuments(ctx, "campaign_status", gcp.DocumentQuery{
+		Filters: []gcp.QueryFilter{{Path: "state", Op: "in", Value: []string{"launching", "running"}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query campaign runs: %w", err)
+	}
+
+	sessions := make([]DashboardSession, 0, len(research)+len(campaigns))
+	for _, doc := range research {
+		sessions = append(sessions, dashboardSession(doc, "research", "status"))
+	}
+	for _, doc := range campaigns {
+		sessions = append(sessions, dashboardSession(doc, "campaign", "state"))
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt.After(sessions[j].StartedAt)
+	})
+	return sessions, nil
+}
+
+// dashboardSession reads a session document, whose status is stored in statusField
+func dashboardSession(doc *firestore.DocumentSnapshot, kind, statusField string) DashboardSession {
+	data := doc.Data()
+	session := DashboardSession{ID: doc.Ref.ID, Kind: kind}
+	session.Status, _ = data[statusField].(string)
+	session.LastError, _ = data["last_error"].(string)
+	session.StartedAt, _ = data["started_at"].(time.Time)
+	session.UpdatedAt, _ = data["updated_at"].(time.Time)
+	session.Total = intField(data, "drones_total")
+	session.Completed = intField(data, "drones_completed")
+	session.Failed = intField(data, "drones_failed")
+	return session
+}
+
+// intField reads an integer field, which Firestore returns as int64
+func intField(data map[string]interface{}, field string) int {
+	value, _ := data[field].(int64)
+	return int(value)
+}
@@ -0,0 +1,109 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// ErrDraining is returned for tasks submitted while the coordinator shuts down
+var ErrDraining = errors.New("coordinator is shutting down")
+
+// DrainConfig configures how the coordinator shuts down
+type DrainConfig struct {
+	// Timeout bounds how long shutdown waits for running tasks
+	Timeout time.Duration
+	// TerminateDrones deletes every drone after draining. Otherwise drones stay
+	// deployed and are adopted again when the coordinator restarts.
+	TerminateDrones bool
+}
+
+// LoadDrainConfig reads the shutdown configuration from the environment
+func LoadDrainConfig() DrainConfig {
+	config := DrainConfig{Timeout: 30 * time.Second}
+	if timeout, err := time.ParseDuration(os.Getenv("SHUTDOWN_DRAIN_TIMEOUT")); err == nil && timeout >= 0 {
+		config.Timeout = timeout
+	}
+	if terminate, err := strconv.ParseBool(os.Getenv("SHUTDOWN_TERMINATE_DRONES")); err == nil {
+		config.TerminateDrones = terminate
+	}
+	return config
+}
+
+// Drain stops the scheduler from starting queued tasks and waits, up to the
+// configured timeout, for running tasks to finish. Tasks that are still queued
+// or running then get an "interrupted" result, so clients polling for them
+// are not left waiting; drones keep their own checkpoints of running tasks.
+func (s *Server) Drain(ctx context.Context, config DrainConfig) {
+	s.scheduler.mu.Lock()
+	s.scheduler.draining = true
+	queued := s.scheduler.queue
+	s.scheduler.queue = nil
+	s.scheduler.mu.Unlock()
+
+	log.Printf("Draining coordinator: %d tasks running, %d queued", s.runningTasks(), len(queued))
+
+	done := make(chan struct{})
+	go func() {
+		s.inFlight.Wait()
+		close(done)
+	}()
+	waitCtx, cancel := context.WithTimeout(ctx, config.Timeout)
+	defer cancel()
+	select {
+	case <-done:
+	case <-waitCtx.Done():
+		log.Printf("Warning: Drain timed out with %d tasks still running", s.runningTasks())
+	}
+
+	// Record what did not finish
+	now := time.Now()
+	var interrupted []*types.TaskResult
+	s.scheduler.mu.Lock()
+	for taskID := range s.scheduler.running {
+		interrupted = append(interrupted, interruptedResult(taskID, now))
+	}
+	s.scheduler.mu.Unlock()
+	for _, task := range queued {
+		interrupted = append(interrupted, interruptedResult(task.id, now))
+	}
+	if len(interrupted) > 0 {
+		s.resultsMutex.Lock()
+		for _, result := range interrupted {
+			s.taskResults[result.TaskID] = []*types.TaskResult{result}
+		}
+		s.resultsMutex.Unlock()
+		s.persistResults(context.WithoutCancel(ctx), interrupted)
+	}
+
+	if config.TerminateDrones {
+		for _, drone := range s.ListActiveDrones() {
+			if err := s.TerminateDrone(context.WithoutCancel(ctx), drone.ID); err != nil {
+				log.Printf("Warning: Failed to terminate drone %s: %v", drone.ID, err)
+			}
+		}
+	}
+	log.Printf("Coordinator drained, %d tasks interrupted", len(interrupted))
+}
+
+// runningTasks returns how many dispatched tasks have not finished
+func (s *Server) runningTasks() int {
+	s.scheduler.mu.Lock()
+	defer s.scheduler.mu.Unlock()
+	return len(s.scheduler.running)
+}
+
+// interruptedResult is the result recorded for a task the coordinator stopped
+func interruptedResult(taskID string, now time.Time) *types.TaskResult {
+	return &types.TaskResult{
+		TaskID:    taskID,
+		Status:    "interrupted",
+		Error:     ErrDraining.Error(),
+		Timestamp: now,
+	}
+}
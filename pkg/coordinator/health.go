@@ -0,0 +1,26 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spawn-mcp/coordinator/pkg/health"
+)
+
+// AddHealthChecks adds the readiness checks of the coordinator's components to checker
+func (s *Server) AddHealthChecks(checker *health.Checker) {
+	checker.Add("coordinator", func(ctx context.Context) error {
+		if s.draining() {
+			return errors.New("draining")
+		}
+		s.adminMutex.Lock()
+		defer s.adminMutex.Unlock()
+		if s.admin == nil {
+			return errors.New("admin API is not serving")
+		}
+		return nil
+	})
+	if s.gcpClient.FirestoreClient != nil {
+		checker.Add("firestore", health.FirestoreCheck(s.gcpClient.FirestoreClient))
+	}
+}
@@ -0,0 +1,110 @@
+package coordinator
+
+import (
+	"sort"
+
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// taskTypeCapabilities lists the capabilities a task type needs when the task does not specify its own
+var taskTypeCapabilities = map[string][]string{
+	"research":  {"web-search", "information-extraction"},
+	"analysis":  {"data-analysis"},
+	"synthesis": {"summarization", "synthesis"},
+	"writing":   {"document-writing"},
+	"coding":    {"code-generation", "code-execution"},
+}
+
+// droneCandidate is a drone that can run a task, with its fit score
+type droneCandidate struct {
+	drone *types.DroneInfo
+	score int
+}
+
+// requiredCapabilities returns the capabilities a task needs
+func requiredCapabilities(task types.Task) []string {
+	if len(task.RequiredCapabilities) > 0 {
+		return task.RequiredCapabilities
+	}
+	return taskTypeCapabilities[task.Type]
+}
+
+// scoreDrone reports whether a drone can run a task and how well it fits.
+// A drone must have every required capability; among those, drones of the
+// task's own type and drones with fewer unused capabilities score higher so
+// specialists are kept free for the tasks that need them.
+func scoreDrone(drone *types.DroneInfo, task types.Task, required []string) (int, bool) {
+	// Without capability requirements, fall back to matching on type
+	if len(required) == 0 {
+		return 0, drone.Type == task.Type
+	}
+
+	has := make(map[string]bool, len(drone.Capabilities))
+	for _, capability := range drone.Capabilities {
+		has[capability] = true
+	}
+	for _, capability := range required {
+		if !has[capability] {
+			return 0, false
+		}
+	}
+
+	score := 0
+	if drone.Type == task.Type {
+		score += 5
+	}
+	score -= len(has) - len(required)
+
+	return score, true
+}
+
+// isIdle reports whether a drone is reachable and free to take a task
+func isIdle(drone *types.DroneInfo) bool {
+	return drone.Status == "active" && drone.ServiceURL != ""
+}
+
+// fleetCanRun reports whether any idle or busy drone could run a task
+func fleetCanRun(drones map[string]*types.DroneInfo, task types.Task) bool {
+	required := requiredCapabilities(task)
+	for _, drone := range drones {
+		if !isIdle(drone) && drone.Status != string(types.DroneStatusBusy) {
+			continue
+		}
+		if _, ok := scoreDrone(drone, task, required); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchDrones returns the idle drones able to run a task, best fit first
+func matchDrones(drones map[string]*types.DroneInfo, task types.Task) []*types.DroneInfo {
+	required := requiredCapabilities(task)
+
+	var candidates []droneCandidate
+	for _, drone := range drones {
+		if !isIdle(drone) {
+			continue
+		}
+		if score, ok := scoreDrone(drone, task, required); ok {
+			candidates = append(candidates, droneCandidate{drone: drone, score: score})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		// Prefer the drone heard from most recently, then a stable order
+		if !candidates[i].drone.LastPing.Equal(candidates[j].drone.LastPing) {
+			return candidates[i].drone.LastPing.After(candidates[j].drone.LastPing)
+		}
+		return candidates[i].drone.ID < candidates[j].drone.ID
+	})
+
+	matched := make([]*types.DroneInfo, len(candidates))
+	for i, candidate := range candidates {
+		matched[i] = candidate.drone
+	}
+	return matched
+}
@@ -0,0 +1,45 @@
+package coordinator
+
+import (
+	"testing"
+
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+func TestMatchDronesRoutesToDefaultCapabilities(t *testing.T) {
+	s := &Server{}
+	drones := make(map[string]*types.DroneInfo)
+	for _, droneType := range []types.DroneType{
+		types.DroneTypeWorker,
+		types.DroneTypeAnalyst,
+		types.DroneTypeWriter,
+		types.DroneTypeCoder,
+	} {
+		drones[string(droneType)] = &types.DroneInfo{
+			ID:           string(droneType),
+			Type:         string(droneType),
+			Status:       "active",
+			ServiceURL:   "http://" + string(droneType),
+			Capabilities: s.getDefaultCapabilities(droneType),
+		}
+	}
+
+	tests := []struct {
+		taskType string
+		want     string
+	}{
+		{taskType: "analysis", want: "analyst"},
+		{taskType: "writing", want: "writer"},
+		{taskType: "coding", want: "coder"},
+	}
+	for _, tt := range tests {
+		matched := matchDrones(drones, types.Task{Type: tt.taskType})
+		if len(matched) != 1 || matched[0].ID != tt.want {
+			var ids []string
+			for _, drone := range matched {
+				ids = append(ids, drone.ID)
+			}
+			t.Errorf("matchDrones() for a %s task = %v, want [%s]", tt.taskType, ids, tt.want)
+		}
+	}
+}
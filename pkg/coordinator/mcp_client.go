@@ -7,8 +7,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/spawn-mcp/coordinator/pkg/types"
+	"golang.org/x/oauth2"
 	"google.golang.org/api/idtoken"
 )
 
@@ -16,6 +19,12 @@ import (
 type MCPClient struct {
 	httpClient *http.Client
 	projectID  string
+	// breakers skip drones whose recent calls failed
+	breakers *circuitBreakers
+	// clients are the authenticated clients of each drone URL, whose ID tokens
+	// are reused until they expire
+	clients   map[string]*http.Client
+	clientsMu sync.Mutex
 }
 
 // NewMCPClient creates a new MCP client for communicating with drones
@@ -25,6 +34,8 @@ func NewMCPClient(projectID string) *MCPClient {
 			Timeout: 30 * time.Second,
 		},
 		projectID: projectID,
+		breakers:  newCircuitBreakers(LoadBreakerConfig()),
+		clients:   make(map[string]*http.Client),
 	}
 }
 
@@ -53,6 +64,11 @@ type MCPError struct {
 
 // CallTool calls a tool on a remote MCP server (drone)
 func (c *MCPClient) CallTool(ctx context.Context, droneURL, toolName string, arguments map[string]interface{}) (*MCPResponse, error) {
+	// Fail fast, before fetching a token, when the drone's circuit is open
+	if err := c.breakers.allow(droneURL); err != nil {
+		return nil, err
+	}
+
 	// Create authenticated HTTP client for service-to-service communication
 	client, err := c.createAuthenticatedClient(ctx, droneURL)
 	if err != nil {
@@ -85,7 +101,7 @@ func (c *MCPClient) CallTool(ctx context.Context, droneURL, toolName string, arg
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	// Send request
-	resp, err := client.Do(httpReq)
+	resp, err := c.send(client, httpReq, droneURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -113,6 +129,10 @@ func (c *MCPClient) CallTool(ctx context.Context, droneURL, toolName string, arg
 
 // ListTools lists available tools on a remote MCP server (drone)
 func (c *MCPClient) ListTools(ctx context.Context, droneURL string) (*MCPResponse, error) {
+	if err := c.breakers.allow(droneURL); err != nil {
+		return nil, err
+	}
+
 	// Create authenticated HTTP client
 	client, err := c.createAuthenticatedClient(ctx, droneURL)
 	if err != nil {
@@ -141,7 +161,7 @@ func (c *MCPClient) ListTools(ctx context.Context, droneURL string) (*MCPRespons
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	// Send request
-	resp, err := client.Do(httpReq)
+	resp, err := c.send(client, httpReq, droneURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -167,50 +187,129 @@ func (c *MCPClient) ListTools(ctx context.Context, droneURL string) (*MCPRespons
 	return &mcpResponse, nil
 }
 
-// createAuthenticatedClient creates an HTTP client with OIDC authentication for service-to-service communication
-func (c *MCPClient) createAuthenticatedClient(ctx context.Context, targetURL string) (*http.Client, error) {
-	// Create ID token source for the target audience (drone service URL)
-	tokenSource, err := idtoken.NewTokenSource(ctx, targetURL)
+// DroneTask is the body of a drone's /task endpoint
+type DroneTask struct {
+	Subject string `json:"subject"`
+	RunID   string `json:"run_id,omitempty"`
+	// TaskID enables the drone's progress checkpoints for the task
+	TaskID                string `json:"task_id,omitempty"`
+	CheckpointIntervalSec int    `json:"checkpoint_interval_sec,omitempty"`
+	// Resume is the checkpoint a replacement drone continues the task from
+	Resume *types.TaskCheckpoint `json:"resume,omitempty"`
+}
+
+// SendTask runs a task on a drone, which publishes the result once it is
+// accepted. Returns the drone's acknowledgement.
+func (c *MCPClient) SendTask(ctx context.Context, droneURL string, task DroneTask) (string, error) {
+	if err := c.breakers.allow(droneURL); err != nil {
+		return "", err
+	}
+
+	client, err := c.createAuthenticatedClient(ctx, droneURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create token source: %w", err)
+		return "", fmt.Errorf("failed to create authenticated client: %w", err)
 	}
 
-	// Get ID token
-	token, err := tokenSource.Token()
+	requestBody, err := json.Marshal(task)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get ID token: %w", err)
+		return "", fmt.Errorf("failed to marshal task: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", droneURL+"/task", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.send(client, httpReq, droneURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to send task: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP error %d: %s", resp.StatusCode, string(responseBody))
+	}
+	return string(responseBody), nil
+}
+
+// createAuthenticatedClient returns an HTTP client with OIDC authentication for
+// service-to-service communication with targetURL. Clients are cached per
+// audience, and their token source mints a new ID token only when the last one
+// expires, instead of calling the metadata server on every request.
+func (c *MCPClient) createAuthenticatedClient(ctx context.Context, targetURL string) (*http.Client, error) {
+	c.clientsMu.Lock()
+	client, ok := c.clients[targetURL]
+	c.clientsMu.Unlock()
+	if ok {
+		return client, nil
+	}
+
+	// The source refreshes tokens long after this call, so it must not use its context
+	tokenSource, err := idtoken.NewTokenSource(context.Background(), targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token source: %w", err)
 	}
 
 	// Create HTTP client with authentication
-	client := &http.Client{
+	client = &http.Client{
 		Timeout: 30 * time.Second,
 		Transport: &authenticatedTransport{
-			base:  http.DefaultTransport,
-			token: token.AccessToken,
+			base:   http.DefaultTransport,
+			source: tokenSource,
 		},
 	}
 
+	// A concurrent call may have cached a client first
+	c.clientsMu.Lock()
+	defer c.clientsMu.Unlock()
+	if cached, ok := c.clients[targetURL]; ok {
+		return cached, nil
+	}
+	c.clients[targetURL] = client
 	return client, nil
 }
 
+// Forget drops the cached client and circuit breaker of a drone that was terminated
+func (c *MCPClient) Forget(droneURL string) {
+	c.clientsMu.Lock()
+	delete(c.clients, droneURL)
+	c.clientsMu.Unlock()
+	c.breakers.forget(droneURL)
+}
+
 // authenticatedTransport adds authentication headers to HTTP requests
 type authenticatedTransport struct {
-	base  http.RoundTripper
-	token string
+	base   http.RoundTripper
+	source oauth2.TokenSource
 }
 
 func (t *authenticatedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Get ID token, cached by the source until it expires
+	token, err := t.source.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ID token: %w", err)
+	}
+
 	// Clone the request to avoid modifying the original
 	reqClone := req.Clone(req.Context())
 
 	// Add authorization header
-	reqClone.Header.Set("Authorization", "Bearer "+t.token)
+	reqClone.Header.Set("Authorization", "Bearer "+token.AccessToken)
 
 	return t.base.RoundTrip(reqClone)
 }
 
 // HealthCheck performs a health check on a drone
 func (c *MCPClient) HealthCheck(ctx context.Context, droneURL string) error {
+	if err := c.breakers.allow(droneURL); err != nil {
+		return err
+	}
+
 	// Create authenticated HTTP client
 	client, err := c.createAuthenticatedClient(ctx, droneURL)
 	if err != nil {
@@ -224,7 +323,7 @@ func (c *MCPClient) HealthCheck(ctx context.Context, droneURL string) error {
 	}
 
 	// Send request
-	resp, err := client.Do(httpReq)
+	resp, err := c.send(client, httpReq, droneURL)
 	if err != nil {
 		return fmt.Errorf("failed to send health check request: %w", err)
 	}
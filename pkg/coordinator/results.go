@@ -0,0 +1,89 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+const (
+	// taskResultsCollection holds one document per drone result
+	taskResultsCollection = "task_results"
+
+	defaultResultsLimit = 50
+	maxResultsLimit     = 500
+)
+
+// persistResults stores task results in Firestore so they survive restarts and can be queried.
+// A task's results are written in one batch.
+func (s *Server) persistResults(ctx context.Context, results []*types.TaskResult) {
+	batch := gcp.NewBatchWriter(s.gcpClient.FirestoreClient)
+	for _, result := range results {
+		batch.Set(taskResultsCollection, fmt.Sprintf("%s_%s", result.TaskID, result.DroneID), result)
+	}
+	if err := batch.Flush(ctx); err != nil {
+		log.Printf("Warning: Failed to store task results: %v", err)
+	}
+}
+
+// QueryTaskResults returns stored task results matching the query, newest first,
+// and the cursor for the next page, which is empty on the last page
+func (s *Server) QueryTaskResults(ctx context.Context, query types.TaskResultQuery) ([]*types.TaskResult, string, error) {
+	if query.Limit <= 0 {
+		query.Limit = defaultResultsLimit
+	}
+	if query.Limit > maxResultsLimit {
+		query.Limit = maxResultsLimit
+	}
+	if query.Offset < 0 {
+		return nil, "", fmt.Errorf("offset must not be negative")
+	}
+	if !query.Since.IsZero() && !query.Until.IsZero() && query.Until.Before(query.Since) {
+		return nil, "", fmt.Errorf("until must not be before since")
+	}
+
+	// Results are stored without firestore tags, so fields use their Go names
+	var filters []gcp.QueryFilter
+	if query.TaskID != "" {
+		filters = append(filters, gcp.QueryFilter{Path: "TaskID", Op: "==", Value: query.TaskID})
+	}
+	if query.DroneID != "" {
+		filters = append(filters, gcp.QueryFilter{Path: "DroneID", Op: "==", Value: query.DroneID})
+	}
+	if query.Status != "" {
+		filters = append(filters, gcp.QueryFilter{Path: "Status", Op: "==", Value: query.Status})
+	}
+	if !query.Since.IsZero() {
+		filters = append(filters, gcp.QueryFilter{Path: "Timestamp", Op: ">=", Value: query.Since})
+	}
+	if !query.Until.IsZero() {
+		filters = append(filters, gcp.QueryFilter{Path: "Timestamp", Op: "<=", Value: query.Until})
+	}
+
+	docs, err := s.gcpClient.QueryDocuments(ctx, taskResultsCollection, gcp.DocumentQuery{
+		Filters:    filters,
+		OrderBy:    "Timestamp",
+		Descending: true,
+		Limit:      query.Limit,
+		Offset:     query.Offset,
+		StartAfter: query.Cursor,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query task results: %w", err)
+	}
+
+	results := make([]*types.TaskResult, 0, len(docs))
+	for _, doc := range docs {
+		var result types.TaskResult
+		if err := doc.DataTo(&result); err != nil {
+			log.Printf("Warning: Skipping unreadable task result %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		results = append(results, &result)
+	}
+
+	return results, gcp.NextCursor(docs, query.Limit), nil
+}
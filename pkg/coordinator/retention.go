@@ -0,0 +1,176 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+)
+
+// retentionBatchSize is how many expired documents are deleted per query
+const retentionBatchSize = 200
+
+// RetentionPolicy keeps documents in a collection for TTL after the time stored in TimeField
+type RetentionPolicy struct {
+	Collection string
+	TimeField  string
+	TTL        time.Duration
+}
+
+// RetentionConfig configures the periodic cleanup of stale Firestore data
+type RetentionConfig struct {
+	Interval time.Duration
+	Policies []RetentionPolicy
+}
+
+// DefaultRetentionConfig returns the default retention for every collection the coordinator writes.
+// The active "drones" collection is never expired; terminated drones age out of "drones_history".
+func DefaultRetentionConfig() RetentionConfig {
+	return RetentionConfig{
+		Interval: time.Hour,
+		Policies: []RetentionPolicy{
+			{Collection: "execution_plans", TimeField: "CreatedAt", TTL: 7 * 24 * time.Hour},
+			{Collection: "drones_history", TimeField: "LastSeen", TTL: 30 * 24 * time.Hour},
+			{Collection: taskResultsCollection, TimeField: "Timestamp", TTL: 30 * 24 * time.Hour},
+			{Collection: "task_checkpoints", TimeField: "Timestamp", TTL: 7 * 24 * time.Hour},
+			{Collection: "campaign_specs", TimeField: "CreatedAt", TTL: 30 * 24 * time.Hour},
+			{Collection: "campaign_plans", TimeField: "Spec.CreatedAt", TTL: 30 * 24 * time.Hour},
+			{Collection: "campaign_status", TimeField: "updated_at", TTL: 30 * 24 * time.Hour},
+			{Collection: "research_reports", TimeField: "CreatedAt", TTL: 90 * 24 * time.Hour},
+			{Collection: "research_sessions", TimeField: "updated_at", TTL: 30 * 24 * time.Hour},
+			{Collection: gcp.SpawnKeysCollection, TimeField: "CreatedAt", TTL: 7 * 24 * time.Hour},
+		},
+	}
+}
+
+// LoadRetentionConfig returns the default retention overridden from the environment.
+// RETENTION_INTERVAL sets how often cleanup runs, and RETENTION_TTL_<COLLECTION>
+// (e.g. RETENTION_TTL_TASK_RESULTS=72h) sets a collection's TTL; a TTL of 0 keeps its documents forever.
+func LoadRetentionConfig() RetentionConfig {
+	config := DefaultRetentionConfig()
+
+	if v := os.Getenv("RETENTION_INTERVAL"); v != "" {
+		if interval, err := time.ParseDuration(v); err == nil && interval > 0 {
+			config.Interval = interval
+		} else {
+			log.Printf("Warning: Ignoring invalid RETENTION_INTERVAL %q", v)
+		}
+	}
+
+	for i, policy := range config.Policies {
+		key := "RETENTION_TTL_" + strings.ToUpper(policy.Collection)
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl < 0 {
+			log.Printf("Warning: Ignoring invalid %s %q", key, v)
+			continue
+		}
+		config.Policies[i].TTL = ttl
+	}
+
+	return config
+}
+
+// StartRetention runs CleanupExpired every config.Interval until ctx is cancelled
+func (s *Server) StartRetention(ctx context.Context, config RetentionConfig) {
+	go func() {
+		ticker := time.NewTicker(config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				deleted, err := s.CleanupExpired(ctx, config.Policies)
+				if err != nil {
+					log.Printf("Warning: Retention cleanup failed: %v", err)
+				}
+				if deleted > 0 {
+					log.Printf("Retention cleanup deleted %d expired documents", deleted)
+				}
+			}
+		}
+	}()
+}
+
+// CleanupExpired deletes documents older than their collection's TTL and returns how many were deleted.
+// It keeps going after a failing collection and reports the first error.
+func (s *Server) CleanupExpired(ctx context.Context, policies []RetentionPolicy) (int, error) {
+	var firstErr error
+	total := 0
+	for _, policy := range policies {
+		if policy.TTL <= 0 {
+			continue
+		}
+		cutoff := time.Now().Add(-policy.TTL)
+		filter := gcp.QueryFilter{Path: policy.TimeField, Op: "<", Value: cutoff}
+		deleted, err := s.deleteMatching(ctx, policy.Collection, []gcp.QueryFilter{filter})
+		total += deleted
+		if err != nil {
+			log.Printf("Warning: Failed to clean up %s: %v", policy.Collection, err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to clean up %s: %w", policy.Collection, err)
+			}
+		}
+	}
+	return total, firstErr
+}
+
+// PurgeSession deletes everything the coordinator stored for a campaign run,
+// its spec, plan, status and drone spawn keys, and returns how many documents
+// were deleted. Research sessions belong to a tenant and are purged by the
+// research server's purge-session-data, with their results and reports.
+func (s *Server) PurgeSession(ctx context.Context, sessionID string) (int, error) {
+	if sessionID == "" {
+		return 0, fmt.Errorf("session ID is required")
+	}
+
+	related := []struct {
+		collection string
+		field      string
+	}{
+		{"campaign_specs", "RunID"},
+		{"campaign_plans", "RunID"},
+		{"campaign_status", "run_id"},
+		{gcp.SpawnKeysCollection, "SessionID"},
+	}
+
+	deleted := 0
+	for _, r := range related {
+		n, err := s.deleteMatching(ctx, r.collection, []gcp.QueryFilter{{Path: r.field, Op: "==", Value: sessionID}})
+		deleted += n
+		if err != nil {
+			return deleted, fmt.Errorf("failed to purge %s: %w", r.collection, err)
+		}
+	}
+
+	log.Printf("Purged campaign run %s (%d documents)", sessionID, deleted)
+	return deleted, nil
+}
+
+// deleteMatching deletes all documents in a collection matching filters, in batches
+func (s *Server) deleteMatching(ctx context.Context, collection string, filters []gcp.QueryFilter) (int, error) {
+	deleted := 0
+	for {
+		docs, err := s.gcpClient.QueryDocuments(ctx, collection, gcp.DocumentQuery{Filters: filters, Limit: retentionBatchSize})
+		if err != nil {
+			return deleted, err
+		}
+		for _, doc := range docs {
+			if err := s.gcpClient.DeleteDocument(ctx, collection, doc.Ref.ID); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+		if len(docs) < retentionBatchSize {
+			return deleted, nil
+		}
+	}
+}
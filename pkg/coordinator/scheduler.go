@@ -0,0 +1,258 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// priorityAgingInterval is how long a queued task waits before its effective
+// priority rises one level, so low-priority work cannot be starved forever
+const priorityAgingInterval = 2 * time.Minute
+
+// defaultMaxQueueAge is how long a task may wait for drones before it fails
+const defaultMaxQueueAge = 30 * time.Minute
+
+// queueSweepInterval is how often queued tasks are checked for expiry when
+// nothing else triggers dispatching
+const queueSweepInterval = time.Minute
+
+// priorityRank orders task priorities; an empty priority counts as normal
+var priorityRank = map[types.TaskPriority]int{
+	types.TaskPriorityLow:    0,
+	"":                       1,
+	types.TaskPriorityNormal: 1,
+	types.TaskPriorityHigh:   2,
+}
+
+// queuedTask is a task waiting for drones
+type queuedTask struct {
+	ctx       context.Context
+	id        string
+	task      types.Task
+	submitted time.Time
+}
+
+// effectivePriority is the task's priority rank plus one level per aging interval waited
+func (q *queuedTask) effectivePriority(now time.Time) int {
+	return priorityRank[q.task.Priority] + int(now.Sub(q.submitted)/priorityAgingInterval)
+}
+
+// taskScheduler holds tasks waiting for drones and tracks those running
+type taskScheduler struct {
+	mu      sync.Mutex
+	queue   []*queuedTask
+	running map[string]bool
+	// draining stops queued tasks from starting; see Server.Drain
+	draining bool
+	// maxAge is how long a task may stay queued before it fails; 0 is forever
+	maxAge time.Duration
+}
+
+// newTaskScheduler creates an empty scheduler whose tasks fail after waiting maxAge
+func newTaskScheduler(maxAge time.Duration) *taskScheduler {
+	return &taskScheduler{running: make(map[string]bool), maxAge: maxAge}
+}
+
+// LoadMaxQueueAge reads how long a task may wait for drones from
+// TASK_MAX_QUEUE_AGE, e.g. 10m; 0 lets tasks wait forever
+func LoadMaxQueueAge() time.Duration {
+	v := os.Getenv("TASK_MAX_QUEUE_AGE")
+	if v == "" {
+		return defaultMaxQueueAge
+	}
+	maxAge, err := time.ParseDuration(v)
+	if err != nil || maxAge < 0 {
+		log.Printf("Warning: Ignoring invalid TASK_MAX_QUEUE_AGE %q", v)
+		return defaultMaxQueueAge
+	}
+	return maxAge
+}
+
+// enqueue adds a task to the queue, unless the coordinator is draining
+func (ts *taskScheduler) enqueue(ctx context.Context, id string, task types.Task) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.draining {
+		return ErrDraining
+	}
+	ts.queue = append(ts.queue, &queuedTask{
+		ctx:       ctx,
+		id:        id,
+		task:      task,
+		submitted: time.Now(),
+	})
+	return nil
+}
+
+// ordered returns the queue sorted by effective priority, oldest first within a level.
+// Callers hold ts.mu.
+func (ts *taskScheduler) ordered(now time.Time) []*queuedTask {
+	ordered := make([]*queuedTask, len(ts.queue))
+	copy(ordered, ts.queue)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		pi, pj := ordered[i].effectivePriority(now), ordered[j].effectivePriority(now)
+		if pi != pj {
+			return pi > pj
+		}
+		return ordered[i].submitted.Before(ordered[j].submitted)
+	})
+	return ordered
+}
+
+// expire removes queued tasks whose context has ended or that have waited
+// longer than maxAge, and returns a failed result for each. Callers hold ts.mu.
+func (ts *taskScheduler) expire(now time.Time) []*types.TaskResult {
+	var expired []*types.TaskResult
+	kept := ts.queue[:0]
+	for _, queued := range ts.queue {
+		var reason string
+		if err := queued.ctx.Err(); err != nil {
+			reason = fmt.Sprintf("task was cancelled while queued: %v", err)
+		} else if waited := now.Sub(queued.submitted); ts.maxAge > 0 && waited > ts.maxAge {
+			reason = fmt.Sprintf("no drone could run the task within %s", ts.maxAge)
+		}
+		if reason == "" {
+			kept = append(kept, queued)
+			continue
+		}
+		expired = append(expired, &types.TaskResult{
+			TaskID:    queued.id,
+			Status:    "failed",
+			Error:     reason,
+			Timestamp: now,
+		})
+	}
+	// Clear the tail so expired tasks can be collected
+	for i := len(kept); i < len(ts.queue); i++ {
+		ts.queue[i] = nil
+	}
+	ts.queue = kept
+	return expired
+}
+
+// QueuedTasks returns the number of tasks waiting for drones
+func (s *Server) QueuedTasks() int {
+	s.scheduler.mu.Lock()
+	defer s.scheduler.mu.Unlock()
+	return len(s.scheduler.queue)
+}
+
+// dispatchTasks assigns idle drones to queued tasks in priority order. Higher
+// priority tasks claim idle drones first; a task whose drones are all busy
+// stays queued without blocking lower priority tasks that can run. Tasks that
+// were cancelled or have waited too long fail instead.
+func (s *Server) dispatchTasks() {
+	expired := s.startQueued()
+	if len(expired) == 0 {
+		return
+	}
+
+	s.resultsMutex.Lock()
+	for _, result := range expired {
+		log.Printf("Warning: Task %s failed in the queue: %s", result.TaskID, result.Error)
+		s.taskResults[result.TaskID] = []*types.TaskResult{result}
+	}
+	s.resultsMutex.Unlock()
+	s.persistResults(context.Background(), expired)
+}
+
+// StartQueueExpiry fails queued tasks that have waited too long, checking
+// every queueSweepInterval until ctx is cancelled
+func (s *Server) StartQueueExpiry(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(queueSweepInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.dispatchTasks()
+			}
+		}
+	}()
+}
+
+// startQueued starts the queued tasks that have drones to run on, and returns
+// the results of those that expired
+func (s *Server) startQueued() []*types.TaskResult {
+	s.scheduler.mu.Lock()
+	defer s.scheduler.mu.Unlock()
+
+	if len(s.scheduler.queue) == 0 || s.scheduler.draining {
+		return nil
+	}
+	now := time.Now()
+	expired := s.scheduler.expire(now)
+
+	s.dronesMutex.Lock()
+	defer s.dronesMutex.Unlock()
+
+	var waiting []*queuedTask
+	for _, queued := range s.scheduler.ordered(now) {
+		drones := matchDrones(s.activeDrones, queued.task)
+		if len(drones) == 0 {
+			waiting = append(waiting, queued)
+			continue
+		}
+
+		// Limit to maxDrones if specified
+		if queued.task.MaxDrones > 0 && len(drones) > queued.task.MaxDrones {
+			drones = drones[:queued.task.MaxDrones]
+		}
+
+		for _, drone := range drones {
+			drone.Status = string(types.DroneStatusBusy)
+		}
+
+		s.scheduler.running[queued.id] = true
+		s.inFlight.Add(1)
+		go s.runTask(queued.ctx, queued.id, queued.task, drones)
+	}
+
+	// Keep submission order for the remaining tasks
+	sort.SliceStable(waiting, func(i, j int) bool {
+		return waiting[i].submitted.Before(waiting[j].submitted)
+	})
+	s.scheduler.queue = waiting
+	return expired
+}
+
+// taskFinished stops tracking a task dispatchTasks started
+func (s *Server) taskFinished(taskID string) {
+	s.scheduler.mu.Lock()
+	delete(s.scheduler.running, taskID)
+	s.scheduler.mu.Unlock()
+	s.inFlight.Done()
+}
+
+// draining reports whether the coordinator has started shutting down
+func (s *Server) draining() bool {
+	s.scheduler.mu.Lock()
+	defer s.scheduler.mu.Unlock()
+	return s.scheduler.draining
+}
+
+// releaseDrones returns drones assigned to a finished task to the idle pool
+func (s *Server) releaseDrones(drones []*types.DroneInfo) {
+	s.dronesMutex.Lock()
+	defer s.dronesMutex.Unlock()
+
+	for _, drone := range drones {
+		drone.TasksCompleted++
+		drone.LastSeen = time.Now()
+		// Drones marked unhealthy or terminated while working keep that status
+		if drone.Status == string(types.DroneStatusBusy) {
+			drone.Status = "active"
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package coordinator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+func TestTaskSchedulerExpire(t *testing.T) {
+	now := time.Now()
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ts := newTaskScheduler(10 * time.Minute)
+	ts.queue = []*queuedTask{
+		{ctx: context.Background(), id: "fresh", submitted: now.Add(-time.Minute)},
+		{ctx: context.Background(), id: "stale", submitted: now.Add(-11 * time.Minute)},
+		{ctx: cancelled, id: "cancelled", submitted: now},
+		{ctx: context.Background(), id: "waiting", submitted: now.Add(-9 * time.Minute)},
+	}
+
+	expired := ts.expire(now)
+	got := make(map[string]*types.TaskResult)
+	for _, result := range expired {
+		got[result.TaskID] = result
+	}
+	if len(got) != 2 || got["stale"] == nil || got["cancelled"] == nil {
+		t.Fatalf("expired tasks = %v, want stale and cancelled", got)
+	}
+	for id, result := range got {
+		if result.Status != "failed" || result.Error == "" {
+			t.Errorf("result of %s = %s (%q), want failed with a reason", id, result.Status, result.Error)
+		}
+	}
+	if len(ts.queue) != 2 || ts.queue[0].id != "fresh" || ts.queue[1].id != "waiting" {
+		t.Errorf("queue after expiry has %d tasks, want fresh and waiting in order", len(ts.queue))
+	}
+
+	// Without a maximum age, only cancelled tasks expire
+	forever := newTaskScheduler(0)
+	forever.queue = []*queuedTask{{ctx: context.Background(), id: "old", submitted: now.Add(-24 * time.Hour)}}
+	if expired := forever.expire(now); len(expired) != 0 {
+		t.Errorf("expire() with no maximum age = %d results, want 0", len(expired))
+	}
+}
+
+func TestLoadMaxQueueAge(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", defaultMaxQueueAge},
+		{"5m", 5 * time.Minute},
+		{"0", 0},
+		{"-1m", defaultMaxQueueAge},
+		{"soon", defaultMaxQueueAge},
+	}
+	for _, tt := range tests {
+		t.Setenv("TASK_MAX_QUEUE_AGE", tt.value)
+		if got := LoadMaxQueueAge(); got != tt.want {
+			t.Errorf("LoadMaxQueueAge() with %q = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -4,13 +4,23 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
+	"os"
+	"sort"
 	"sync"
 	"time"
 
+	runpb "cloud.google.com/go/run/apiv2/runpb"
 	"github.com/spawn-mcp/coordinator/pkg/gcp"
 	"github.com/spawn-mcp/coordinator/pkg/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// defaultTaskConcurrency caps concurrent drone calls for tasks that do not set Concurrency
+const defaultTaskConcurrency = 10
+
 // Server represents the coordinator MCP server
 type Server struct {
 	gcpClient    *gcp.Client
@@ -19,6 +29,15 @@ type Server struct {
 	dronesMutex  sync.RWMutex
 	taskResults  map[string][]*types.TaskResult
 	resultsMutex sync.RWMutex
+	scheduler    *taskScheduler
+	images       *gcp.ImageResolver
+	imagesErr    error
+	imagesOnce   sync.Once
+	network      gcp.NetworkConfig
+	admin        *grpc.Server
+	adminMutex   sync.Mutex
+	// inFlight counts dispatched tasks, so shutdown can wait for them
+	inFlight sync.WaitGroup
 }
 
 // NewServer creates a new coordinator MCP server
@@ -28,6 +47,8 @@ func NewServer(gcpClient *gcp.Client) *Server {
 		mcpClient:    NewMCPClient(gcpClient.ProjectID),
 		activeDrones: make(map[string]*types.DroneInfo),
 		taskResults:  make(map[string][]*types.TaskResult),
+		scheduler:    newTaskScheduler(LoadMaxQueueAge()),
+		network:      gcp.LoadNetworkConfig(),
 	}
 
 	return server
@@ -64,6 +85,7 @@ func (s *Server) PlanDistributedTask(taskDescription string, parameters map[stri
 		EstimatedCost: s.estimateTaskCost(droneCount, timeConstraint),
 		EstimatedTime: time.Duration(timeConstraint) * time.Minute,
 		Strategy:      "parallel-execution",
+		CreatedAt:     time.Now(),
 	}
 
 	// Store plan in Firestore
@@ -92,25 +114,27 @@ func (s *Server) calculateDroneRequirements(description string, params map[strin
 	return baseCount
 }
 
-func (s *Server) getDroneImageURI(droneType types.DroneType) string {
-	// TODO: Make these configurable via environment variables or config file
-	baseRegistry := "gcr.io/" + s.gcpClient.ProjectID + "/spawn-mcp"
+// getDroneImageURI returns the verified, digest-pinned image for a drone type
+func (s *Server) getDroneImageURI(ctx context.Context, droneType types.DroneType) (string, error) {
+	s.imagesOnce.Do(func() {
+		// Images not overridden by configuration come from the project registry
+		baseRegistry := "gcr.io/" + s.gcpClient.ProjectID + "/spawn-mcp"
+		defaults := make(map[string]string)
+		for _, t := range []types.DroneType{types.DroneTypeWorker, types.DroneTypeAnalyzer, types.DroneTypeProcessor, types.DroneTypeResearcher, types.DroneTypeSynthesizer, types.DroneTypeAnalyst, types.DroneTypeWriter, types.DroneTypeCoder} {
+			defaults[string(t)] = fmt.Sprintf("%s/drone-%s:latest", baseRegistry, t)
+		}
+		// The resolver outlives this request
+		s.images, s.imagesErr = gcp.NewImageResolver(context.WithoutCancel(ctx), defaults, s.gcpClient.FirestoreClient)
+	})
+	if s.imagesErr != nil {
+		return "", fmt.Errorf("failed to load drone images: %w", s.imagesErr)
+	}
 
-	switch droneType {
-	case types.DroneTypeWorker:
-		return baseRegistry + "/drone-worker:latest"
-	case types.DroneTypeAnalyzer:
-		return baseRegistry + "/drone-analyzer:latest"
-	case types.DroneTypeProcessor:
-		return baseRegistry + "/drone-processor:latest"
-	case types.DroneTypeResearcher:
-		return baseRegistry + "/drone-researcher:latest"
-	case types.DroneTypeSynthesizer:
-		return baseRegistry + "/drone-synthesizer:latest"
-	default:
+	if !s.images.Configured(string(droneType)) {
 		// Default to worker type
-		return baseRegistry + "/drone-worker:latest"
+		droneType = types.DroneTypeWorker
 	}
+	return s.images.Image(ctx, string(droneType))
 }
 
 func (s *Server) estimateTaskCost(droneCount, durationMinutes int) float64 {
@@ -143,12 +167,38 @@ func contains(text string, keywords []string) bool {
 
 // SpawnDrone spawns a new drone with the given configuration
 func (s *Server) SpawnDrone(ctx context.Context, config types.DroneConfig) (string, error) {
+	// Runs after the lock is released so queued tasks can use the new drone
+	defer s.dispatchTasks()
+
 	s.dronesMutex.Lock()
 	defer s.dronesMutex.Unlock()
 
 	droneID := fmt.Sprintf("drone-%s-%d", config.Type, time.Now().Unix())
 	serviceName := fmt.Sprintf("drone-%s-%d", config.Type, time.Now().Unix())
 
+	// A retried spawn reuses the names, and the service if it exists, of the first attempt
+	retried := false
+	if config.IdempotencyKey != "" {
+		record, claimed, err := gcp.ClaimSpawn(ctx, s.gcpClient.FirestoreClient, gcp.SpawnRecord{
+			Key:         config.IdempotencyKey,
+			SessionID:   config.SessionID,
+			DroneID:     droneID,
+			ServiceName: serviceName,
+			Region:      s.gcpClient.Region,
+			CreatedAt:   time.Now(),
+		})
+		if err != nil {
+			return "", err
+		}
+		if !claimed {
+			droneID, serviceName, retried = record.DroneID, record.ServiceName, true
+			if _, ok := s.activeDrones[droneID]; ok {
+				log.Printf("Drone %s was already spawned for key %s", droneID, config.IdempotencyKey)
+				return droneID, nil
+			}
+		}
+	}
+
 	// Create drone info
 	drone := &types.DroneInfo{
 		ID:             droneID,
@@ -170,7 +220,10 @@ func (s *Server) SpawnDrone(ctx context.Context, config types.DroneConfig) (stri
 	env := make(map[string]string)
 	env["DRONE_ID"] = droneID
 	env["DRONE_TYPE"] = string(config.Type)
-	env["COORDINATOR_URL"] = "https://coordinator-service-url" // TODO: Make this configurable
+	env["COORDINATOR_URL"] = "https://coordinator-service-url"
+	if coordinatorURL := os.Getenv("COORDINATOR_URL"); coordinatorURL != "" {
+		env["COORDINATOR_URL"] = coordinatorURL
+	}
 
 	// Add any custom environment variables from config
 	for key, value := range config.Environment {
@@ -178,16 +231,51 @@ func (s *Server) SpawnDrone(ctx context.Context, config types.DroneConfig) (stri
 	}
 
 	// Determine the container image based on drone type
-	imageURI := s.getDroneImageURI(config.Type)
-
-	log.Printf("Creating Cloud Run service for drone %s (service: %s)", droneID, serviceName)
+	imageURI, err := s.getDroneImageURI(ctx, config.Type)
+	if err != nil {
+		delete(s.activeDrones, droneID)
+		return "", fmt.Errorf("failed to resolve image for drone %s: %w", droneID, err)
+	}
 
-	// Create the Cloud Run service
-	service, err := s.gcpClient.CreateCloudRunService(ctx, serviceName, imageURI, env)
+	serviceAccount, err := gcp.DroneServiceAccount(s.gcpClient.ProjectID, string(config.Type), config.ServiceAccount)
 	if err != nil {
-		// Remove from active drones on failure
 		delete(s.activeDrones, droneID)
-		return "", fmt.Errorf("failed to create Cloud Run service for drone %s: %w", droneID, err)
+		return "", fmt.Errorf("failed to resolve service account for drone %s: %w", droneID, err)
+	}
+
+	// Labels let Cloud Run usage be broken down by drone type and session
+	labels := map[string]string{"drone_type": string(config.Type)}
+	if config.SessionID != "" {
+		labels["session_id"] = config.SessionID
+	}
+
+	var service *runpb.Service
+	if retried {
+		service, err = s.gcpClient.GetCloudRunService(ctx, serviceName)
+		if err == nil {
+			log.Printf("Reusing Cloud Run service %s created for key %s", serviceName, config.IdempotencyKey)
+		} else if status.Code(err) != codes.NotFound {
+			delete(s.activeDrones, droneID)
+			return "", fmt.Errorf("failed to check Cloud Run service for drone %s: %w", droneID, err)
+		}
+	}
+
+	// Coders run generated code, which they cut off the network with Linux
+	// namespaces that only the second generation sandbox provides
+	if config.Type == types.DroneTypeCoder && config.Scaling.ExecutionEnvironment == "" {
+		config.Scaling.ExecutionEnvironment = "gen2"
+	}
+
+	if service == nil {
+		log.Printf("Creating Cloud Run service for drone %s (service: %s)", droneID, serviceName)
+
+		// Create the Cloud Run service
+		service, err = s.gcpClient.CreateCloudRunService(ctx, serviceName, imageURI, serviceAccount, env, labels, s.network, config.Scaling)
+		if err != nil {
+			// Remove from active drones on failure
+			delete(s.activeDrones, droneID)
+			return "", fmt.Errorf("failed to create Cloud Run service for drone %s: %w", droneID, err)
+		}
 	}
 
 	// Wait for the service to be ready
@@ -236,68 +324,96 @@ func (s *Server) ListActiveDrones() []*types.DroneInfo {
 	return drones
 }
 
-// ExecuteTask executes a task across the drone fleet
+// ExecuteTask queues a task for the drone fleet and returns its ID. The task
+// starts as soon as the scheduler can assign it drones; results are available
+// from GetTaskResults once it completes.
 func (s *Server) ExecuteTask(ctx context.Context, task types.Task) (string, error) {
-	taskID := fmt.Sprintf("task-%s-%d", task.Type, time.Now().Unix())
-
-	log.Printf("Executing task %s: %s", taskID, task.Description)
+	if _, ok := priorityRank[task.Priority]; !ok {
+		return "", fmt.Errorf("invalid task priority %q", task.Priority)
+	}
+	taskID := fmt.Sprintf("task-%s-%d", task.Type, time.Now().UnixNano())
 
-	// Find available drones of the required type
+	// Reject tasks that no drone in the fleet could ever run, busy or not
 	s.dronesMutex.RLock()
-	var availableDrones []*types.DroneInfo
-	for _, drone := range s.activeDrones {
-		if drone.Type == task.Type && drone.Status == "active" && drone.ServiceURL != "" {
-			availableDrones = append(availableDrones, drone)
-		}
-	}
+	runnable := fleetCanRun(s.activeDrones, task)
 	s.dronesMutex.RUnlock()
 
-	if len(availableDrones) == 0 {
+	if !runnable {
+		if required := requiredCapabilities(task); len(required) > 0 {
+			return "", fmt.Errorf("no available drones with capabilities %v", required)
+		}
 		return "", fmt.Errorf("no available drones of type %s", task.Type)
 	}
 
-	// Limit to maxDrones if specified
-	if task.MaxDrones > 0 && len(availableDrones) > task.MaxDrones {
-		availableDrones = availableDrones[:task.MaxDrones]
+	// The task outlives the request that submitted it
+	if err := s.scheduler.enqueue(context.WithoutCancel(ctx), taskID, task); err != nil {
+		return "", err
 	}
+	log.Printf("Queueing task %s (priority %s): %s", taskID, task.Priority, task.Description)
 
-	log.Printf("Distributing task %s to %d drones", taskID, len(availableDrones))
+	s.resultsMutex.Lock()
+	if _, ok := s.taskResults[taskID]; !ok {
+		s.taskResults[taskID] = []*types.TaskResult{}
+	}
+	s.resultsMutex.Unlock()
+	s.dispatchTasks()
 
-	// Execute task on each drone (for now, just list their tools)
-	var results []*types.TaskResult
-	for _, drone := range availableDrones {
-		result := &types.TaskResult{
-			TaskID:    taskID,
-			DroneID:   drone.ID,
-			Status:    "executing",
-			Timestamp: time.Now(),
-		}
+	return taskID, nil
+}
 
-		// Call the drone to list its tools (as a test)
-		response, err := s.mcpClient.ListTools(ctx, drone.ServiceURL)
-		if err != nil {
-			result.Status = "failed"
-			result.Error = err.Error()
-			log.Printf("Failed to call drone %s: %v", drone.ID, err)
-		} else {
-			result.Status = "completed"
-			result.Data = response.Result
-			log.Printf("Successfully called drone %s", drone.ID)
+// runTask executes a dispatched task on its assigned drones and releases them afterwards
+func (s *Server) runTask(ctx context.Context, taskID string, task types.Task, drones []*types.DroneInfo) {
+	defer s.taskFinished(taskID)
+	log.Printf("Distributing task %s to %d drones", taskID, len(drones))
+
+	concurrency := task.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultTaskConcurrency
+	}
+
+	// Call drones concurrently, keeping results in drone order
+	results := make([]*types.TaskResult, len(drones))
+	replaced := make([][]*types.DroneInfo, len(drones))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, drone := range drones {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, drone *types.DroneInfo) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i], replaced[i] = s.runOnDrone(ctx, taskID, task, drone)
+		}(i, drone)
+	}
+	wg.Wait()
+
+	assigned := append([]*types.DroneInfo(nil), drones...)
+	failed := 0
+	for i, result := range results {
+		assigned = append(assigned, replaced[i]...)
+		if result.Status == "failed" {
+			failed++
 		}
-
-		results = append(results, result)
+	}
+	if failed > 0 {
+		log.Printf("Warning: Task %s failed on %d of %d drones", taskID, failed, len(results))
 	}
 
 	// Store results
 	s.resultsMutex.Lock()
 	s.taskResults[taskID] = results
 	s.resultsMutex.Unlock()
+	s.persistResults(ctx, results)
 
-	return taskID, nil
+	s.releaseDrones(assigned)
+	s.dispatchTasks()
 }
 
 // ExecuteResearchTask executes a specific research task using Exa tools on research drones
 func (s *Server) ExecuteResearchTask(ctx context.Context, toolName string, arguments map[string]interface{}) (string, error) {
+	if s.draining() {
+		return "", ErrDraining
+	}
 	taskID := fmt.Sprintf("research-task-%d", time.Now().Unix())
 
 	log.Printf("Executing research task %s with tool %s", taskID, toolName)
@@ -306,7 +422,7 @@ func (s *Server) ExecuteResearchTask(ctx context.Context, toolName string, argum
 	s.dronesMutex.RLock()
 	var researchDrones []*types.DroneInfo
 	for _, drone := range s.activeDrones {
-		if drone.Type == "research" && drone.Status == "active" && drone.ServiceURL != "" {
+		if drone.Type == "research" && drone.Status == "active" && drone.ServiceURL != "" && s.mcpClient.Available(drone.ServiceURL) {
 			researchDrones = append(researchDrones, drone)
 		}
 	}
@@ -343,6 +459,7 @@ func (s *Server) ExecuteResearchTask(ctx context.Context, toolName string, argum
 	s.resultsMutex.Lock()
 	s.taskResults[taskID] = []*types.TaskResult{result}
 	s.resultsMutex.Unlock()
+	s.persistResults(ctx, []*types.TaskResult{result})
 
 	log.Printf("Research task %s completed with status: %s", taskID, result.Status)
 
@@ -377,6 +494,16 @@ func (s *Server) GetDroneStatus(ctx context.Context, droneID string) (*types.Dro
 
 // CheckDroneHealth checks the health of a specific drone and updates its status
 func (s *Server) CheckDroneHealth(ctx context.Context, droneID string) error {
+	batch := gcp.NewBatchWriter(s.gcpClient.FirestoreClient)
+	if err := s.checkDroneHealth(ctx, droneID, batch); err != nil {
+		return err
+	}
+	s.flushDroneUpdates(ctx, batch)
+	return nil
+}
+
+// checkDroneHealth checks a drone and queues its updated record on batch
+func (s *Server) checkDroneHealth(ctx context.Context, droneID string, batch *gcp.BatchWriter) error {
 	s.dronesMutex.Lock()
 	defer s.dronesMutex.Unlock()
 
@@ -392,20 +519,28 @@ func (s *Server) CheckDroneHealth(ctx context.Context, droneID string) error {
 			log.Printf("Health check failed for drone %s: %v", droneID, err)
 			drone.Status = "unhealthy"
 		} else {
-			drone.Status = "active"
+			// A busy drone stays busy until the scheduler releases it
+			if drone.Status != string(types.DroneStatusBusy) {
+				drone.Status = "active"
+			}
 			drone.LastPing = time.Now()
 		}
 
-		// Update in Firestore
-		err = s.gcpClient.StoreDocument(ctx, "drones", droneID, drone)
-		if err != nil {
-			log.Printf("Warning: Failed to update drone health in Firestore: %v", err)
-		}
+		// Queue a copy, as the drone may change again before the batch is flushed
+		snapshot := *drone
+		batch.Set("drones", droneID, &snapshot)
 	}
 
 	return nil
 }
 
+// flushDroneUpdates writes queued drone records to Firestore
+func (s *Server) flushDroneUpdates(ctx context.Context, batch *gcp.BatchWriter) {
+	if err := batch.Flush(ctx); err != nil {
+		log.Printf("Warning: Failed to update drone records in Firestore: %v", err)
+	}
+}
+
 // CheckAllDroneHealth checks the health of all active drones
 func (s *Server) CheckAllDroneHealth(ctx context.Context) {
 	s.dronesMutex.RLock()
@@ -415,11 +550,14 @@ func (s *Server) CheckAllDroneHealth(ctx context.Context) {
 	}
 	s.dronesMutex.RUnlock()
 
+	// Status updates for the whole fleet are written together
+	batch := gcp.NewBatchWriter(s.gcpClient.FirestoreClient)
 	for _, droneID := range droneIDs {
-		if err := s.CheckDroneHealth(ctx, droneID); err != nil {
+		if err := s.checkDroneHealth(ctx, droneID, batch); err != nil {
 			log.Printf("Health check failed for drone %s: %v", droneID, err)
 		}
 	}
+	s.flushDroneUpdates(ctx, batch)
 }
 
 // StartHealthCheckRoutine starts a background routine to periodically check drone health
@@ -443,7 +581,7 @@ func (s *Server) ScaleDrones(ctx context.Context, droneType types.DroneType, tar
 	s.dronesMutex.RLock()
 	currentCount := 0
 	for _, drone := range s.activeDrones {
-		if drone.Type == string(droneType) && drone.Status == "active" {
+		if drone.Type == string(droneType) && (drone.Status == "active" || drone.Status == string(types.DroneStatusBusy)) {
 			currentCount++
 		}
 	}
@@ -478,7 +616,8 @@ func (s *Server) ScaleDrones(ctx context.Context, droneType types.DroneType, tar
 		excess := currentCount - targetCount
 		log.Printf("Scaling down %s drones: need to remove %d", droneType, excess)
 
-		// Find drones to terminate (prefer least recently used)
+		// Find drones to terminate (prefer least recently used). Busy drones
+		// have in-flight tasks and are never candidates.
 		s.dronesMutex.RLock()
 		var dronesOfType []*types.DroneInfo
 		for _, drone := range s.activeDrones {
@@ -486,17 +625,25 @@ func (s *Server) ScaleDrones(ctx context.Context, droneType types.DroneType, tar
 				dronesOfType = append(dronesOfType, drone)
 			}
 		}
-		s.dronesMutex.RUnlock()
 
-		// Sort by last seen (oldest first)
-		// TODO: Implement proper sorting
+		// Sort by last seen (oldest first), then by fewest tasks completed
+		sort.Slice(dronesOfType, func(i, j int) bool {
+			if !dronesOfType[i].LastSeen.Equal(dronesOfType[j].LastSeen) {
+				return dronesOfType[i].LastSeen.Before(dronesOfType[j].LastSeen)
+			}
+			return dronesOfType[i].TasksCompleted < dronesOfType[j].TasksCompleted
+		})
+		s.dronesMutex.RUnlock()
 
-		// Terminate excess drones
-		for i := 0; i < excess && i < len(dronesOfType); i++ {
-			err := s.TerminateDrone(ctx, dronesOfType[i].ID)
+		// Terminate excess drones, skipping any that picked up a task since selection
+		terminated := 0
+		for i := 0; terminated < excess && i < len(dronesOfType); i++ {
+			err := s.terminateDrone(ctx, dronesOfType[i].ID, true)
 			if err != nil {
 				log.Printf("Failed to terminate drone %s: %v", dronesOfType[i].ID, err)
+				continue
 			}
+			terminated++
 		}
 	}
 
@@ -516,6 +663,12 @@ func (s *Server) getDefaultCapabilities(droneType types.DroneType) []string {
 		return []string{"web-search", "document-analysis", "information-extraction"}
 	case types.DroneTypeSynthesizer:
 		return []string{"content-generation", "summarization", "synthesis"}
+	case types.DroneTypeAnalyst:
+		return []string{"data-analysis", "trend-detection", "statistical-processing"}
+	case types.DroneTypeWriter:
+		return []string{"content-generation", "document-writing", "summarization"}
+	case types.DroneTypeCoder:
+		return []string{"code-generation", "code-execution"}
 	default:
 		return []string{"basic-processing"}
 	}
@@ -523,6 +676,11 @@ func (s *Server) getDefaultCapabilities(droneType types.DroneType) []string {
 
 // TerminateDrone terminates a specific drone
 func (s *Server) TerminateDrone(ctx context.Context, droneID string) error {
+	return s.terminateDrone(ctx, droneID, false)
+}
+
+// terminateDrone terminates a drone, refusing busy drones when onlyIfIdle is set
+func (s *Server) terminateDrone(ctx context.Context, droneID string, onlyIfIdle bool) error {
 	s.dronesMutex.Lock()
 	defer s.dronesMutex.Unlock()
 
@@ -531,6 +689,10 @@ func (s *Server) TerminateDrone(ctx context.Context, droneID string) error {
 		return fmt.Errorf("drone %s not found", droneID)
 	}
 
+	if onlyIfIdle && drone.Status == string(types.DroneStatusBusy) {
+		return fmt.Errorf("drone %s has an in-flight task", droneID)
+	}
+
 	log.Printf("Terminating drone %s (service: %s)", droneID, drone.ServiceName)
 
 	// Update status to terminating
@@ -548,22 +710,158 @@ func (s *Server) TerminateDrone(ctx context.Context, droneID string) error {
 	// Remove from active drones
 	delete(s.activeDrones, droneID)
 
+	s.archiveDrone(ctx, drone)
+
+	log.Printf("Successfully terminated drone %s", droneID)
+
+	return nil
+}
+
+// archiveDrone moves a terminated drone's record from the active "drones"
+// collection to "drones_history" so it is not recovered on restart
+func (s *Server) archiveDrone(ctx context.Context, drone *types.DroneInfo) {
 	// Update status in Firestore (mark as terminated rather than delete)
 	drone.Status = "terminated"
 	drone.LastSeen = time.Now()
-	err := s.gcpClient.StoreDocument(ctx, "drones_history", droneID, drone)
+	err := s.gcpClient.StoreDocument(ctx, "drones_history", drone.ID, drone)
 	if err != nil {
 		log.Printf("Warning: Failed to store terminated drone info: %v", err)
 	}
 
-	log.Printf("Successfully terminated drone %s", droneID)
+	if err := s.gcpClient.DeleteDocument(ctx, "drones", drone.ID); err != nil {
+		log.Printf("Warning: Failed to remove drone %s from active drones collection: %v", drone.ID, err)
+	}
+	if drone.ServiceURL != "" {
+		s.mcpClient.Forget(drone.ServiceURL)
+	}
+}
 
+// RecoverDrones reloads drones persisted in Firestore after a coordinator
+// restart. Each drone's service is health checked: healthy drones are adopted
+// back into the active registry and unreachable ones are terminated so their
+// Cloud Run services stop billing.
+func (s *Server) RecoverDrones(ctx context.Context) error {
+	docs, err := s.gcpClient.ListDocuments(ctx, "drones")
+	if err != nil {
+		return fmt.Errorf("failed to load persisted drones: %w", err)
+	}
+
+	batch := gcp.NewBatchWriter(s.gcpClient.FirestoreClient)
+	adopted, terminated := 0, 0
+	for _, doc := range docs {
+		var drone types.DroneInfo
+		if err := doc.DataTo(&drone); err != nil {
+			log.Printf("Warning: Skipping unreadable drone record %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		if drone.ID == "" {
+			drone.ID = doc.Ref.ID
+		}
+
+		s.dronesMutex.RLock()
+		_, known := s.activeDrones[drone.ID]
+		s.dronesMutex.RUnlock()
+		if known {
+			continue
+		}
+
+		if s.adoptDrone(ctx, &drone, batch) {
+			adopted++
+		} else {
+			terminated++
+		}
+	}
+
+	s.flushDroneUpdates(ctx, batch)
+	log.Printf("Recovered drone registry: %d adopted, %d terminated", adopted, terminated)
+
+	if adopted > 0 {
+		s.dispatchTasks()
+	}
 	return nil
 }
 
-// Serve starts the coordinator server
+// adoptDrone health checks a persisted drone and either re-registers it or
+// tears down its service. The adopted drone's record is queued on batch.
+// Returns true if the drone was adopted.
+func (s *Server) adoptDrone(ctx context.Context, drone *types.DroneInfo, batch *gcp.BatchWriter) bool {
+	if drone.ServiceURL == "" && drone.ServiceName != "" {
+		serviceURL, err := s.gcpClient.GetServiceURL(ctx, drone.ServiceName)
+		if err != nil {
+			log.Printf("Warning: Could not get service URL for %s: %v", drone.ServiceName, err)
+		}
+		drone.ServiceURL = serviceURL
+	}
+
+	var healthErr error
+	if drone.ServiceURL == "" {
+		healthErr = fmt.Errorf("no service URL")
+	} else {
+		healthErr = s.mcpClient.HealthCheck(ctx, drone.ServiceURL)
+	}
+
+	if healthErr != nil {
+		log.Printf("Terminating unreachable drone %s (service: %s): %v", drone.ID, drone.ServiceName, healthErr)
+		if drone.ServiceName != "" {
+			if err := s.gcpClient.DeleteCloudRunService(ctx, drone.ServiceName); err != nil {
+				log.Printf("Warning: Failed to delete Cloud Run service %s: %v", drone.ServiceName, err)
+			}
+		}
+		s.archiveDrone(ctx, drone)
+		return false
+	}
+
+	// Any task the drone was running was lost with the previous coordinator
+	drone.Status = "active"
+	drone.LastPing = time.Now()
+	drone.LastSeen = time.Now()
+	if drone.Metadata == nil {
+		drone.Metadata = make(map[string]interface{})
+	}
+
+	snapshot := *drone
+	batch.Set("drones", drone.ID, &snapshot)
+
+	s.dronesMutex.Lock()
+	s.activeDrones[drone.ID] = drone
+	s.dronesMutex.Unlock()
+
+	log.Printf("Adopted drone %s of type %s at %s", drone.ID, drone.Type, drone.ServiceURL)
+	return true
+}
+
+// Serve starts the coordinator server, serving the gRPC admin API until Stop is called
 func (s *Server) Serve() error {
 	log.Println("Starting Coordinator Server...")
-	// For now, just keep running
-	select {}
+
+	addr, err := adminAddr()
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	admin := newAdminGRPCServer(s)
+	s.adminMutex.Lock()
+	s.admin = admin
+	s.adminMutex.Unlock()
+
+	log.Printf("Admin API listening on %s", listener.Addr())
+	if err := admin.Serve(listener); err != nil {
+		return fmt.Errorf("admin API stopped: %w", err)
+	}
+	return nil
+}
+
+// Stop gracefully stops the admin API, letting in-flight calls finish
+func (s *Server) Stop() {
+	s.adminMutex.Lock()
+	admin := s.admin
+	s.adminMutex.Unlock()
+
+	if admin != nil {
+		admin.GracefulStop()
+	}
 }
@@ -0,0 +1,305 @@
+// Package doctor checks that the environment and Google Cloud project are set
+// up to run research, and explains how to fix what is not.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/search"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/option"
+	serviceusage "google.golang.org/api/serviceusage/v1"
+	"google.golang.org/api/transport"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Check is the result of one preflight check
+type Check struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail"`
+	// Remedy says how to fix a failed or warning check
+	Remedy string `json:"remedy,omitempty"`
+}
+
+// Report is the result of every check
+type Report struct {
+	ProjectID string  `json:"project_id"`
+	Checks    []Check `json:"checks"`
+}
+
+// Failed reports whether any check failed
+func (r *Report) Failed() bool {
+	for _, check := range r.Checks {
+		if check.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Options configures the checks
+type Options struct {
+	ProjectID string
+	// Images are the default images of the drone types to check, which
+	// configuration may override
+	Images map[string]string
+}
+
+// cloudPlatformScope is the scope the clients use
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// requiredAPIs are the services research cannot run without
+var requiredAPIs = []string{"run.googleapis.com", "firestore.googleapis.com", "pubsub.googleapis.com"}
+
+// optionalAPIs are the services only some features use, and what for
+var optionalAPIs = map[string]string{
+	"logging.googleapis.com":    "get-drone-logs",
+	"monitoring.googleapis.com": "drone metrics",
+}
+
+// requiredPermissions are the IAM permissions research needs, with the role that grants each
+var requiredPermissions = map[string]string{
+	"run.services.create":         "roles/run.admin",
+	"run.services.get":            "roles/run.admin",
+	"run.services.delete":         "roles/run.admin",
+	"datastore.entities.create":   "roles/datastore.user",
+	"datastore.entities.get":      "roles/datastore.user",
+	"pubsub.topics.create":        "roles/pubsub.editor",
+	"pubsub.topics.publish":       "roles/pubsub.editor",
+	"pubsub.subscriptions.create": "roles/pubsub.editor",
+	"iam.serviceAccounts.actAs":   "roles/iam.serviceAccountUser",
+	"logging.logEntries.list":     "roles/logging.viewer",
+}
+
+// Run checks the project, credentials, APIs, IAM permissions, drone images and
+// API keys. Checks that need credentials are skipped when there are none.
+func Run(ctx context.Context, options Options) *Report {
+	report := &Report{ProjectID: options.ProjectID}
+	add := func(check Check) { report.Checks = append(report.Checks, check) }
+
+	if options.ProjectID == "" {
+		add(Check{
+			Name:   "project",
+			Status: StatusFail,
+			Detail: "no Google Cloud project is configured",
+			Remedy: "Set GOOGLE_CLOUD_PROJECT, pass --project, or set project in the config file",
+		})
+	} else {
+		add(Check{Name: "project", Status: StatusOK, Detail: options.ProjectID})
+	}
+
+	opts, credentials := checkCredentials(ctx)
+	add(credentials)
+	if credentials.Status == StatusFail || options.ProjectID == "" {
+		add(Check{Name: "cloud", Status: StatusWarn, Detail: "skipped API, IAM and image checks", Remedy: "Fix the checks above and run doctor again"})
+	} else {
+		for _, check := range checkAPIs(ctx, options.ProjectID, opts) {
+			add(check)
+		}
+		add(checkPermissions(ctx, options.ProjectID, opts))
+		for _, check := range checkImages(ctx, options.Images) {
+			add(check)
+		}
+	}
+
+	add(checkKey("CLAUDE_API_KEY", "sub-queries and synthesis use a mock agent", "Set CLAUDE_API_KEY, or claude_api_key in the config file"))
+	provider := search.LoadConfig().Provider
+	if keyEnv := search.KeyEnv(provider); keyEnv != "" {
+		add(checkKey(keyEnv, "drones cannot search the web through "+provider+" and report mock results", fmt.Sprintf("Set %s, or %s in the config file, where drones are deployed from", keyEnv, strings.ToLower(keyEnv))))
+	}
+	return report
+}
+
+// checkCredentials finds the configured credentials and gets a token with them
+func checkCredentials(ctx context.Context) ([]option.ClientOption, Check) {
+	check := Check{Name: "credentials"}
+	config := gcp.LoadCredentialConfig()
+	opts, err := config.ClientOptions(ctx)
+	if err == nil {
+		err = checkToken(ctx, opts)
+	}
+	if err != nil {
+		check.Status = StatusFail
+		check.Detail = err.Error()
+		check.Remedy = "Run `gcloud auth application-default login`, or set GCP_CREDENTIALS_FILE to a service account key"
+		return nil, check
+	}
+
+	check.Status = StatusOK
+	check.Detail = "Application Default Credentials"
+	if config.CredentialsFile != "" {
+		check.Detail = "credentials file " + config.CredentialsFile
+	}
+	if config.ImpersonateServiceAccount != "" {
+		check.Detail += ", impersonating " + config.ImpersonateServiceAccount
+	}
+	return opts, check
+}
+
+// checkToken gets an access token with the credentials in opts
+func checkToken(ctx context.Context, opts []option.ClientOption) error {
+	credentials, err := transport.Creds(ctx, append(opts, option.WithScopes(cloudPlatformScope))...)
+	if err != nil {
+		return err
+	}
+	if _, err := credentials.TokenSource.Token(); err != nil {
+		return fmt.Errorf("failed to get an access token: %w", err)
+	}
+	return nil
+}
+
+// checkAPIs checks the required and optional services are enabled in the project
+func checkAPIs(ctx context.Context, projectID string, opts []option.ClientOption) []Check {
+	service, err := serviceusage.NewService(ctx, opts...)
+	if err != nil {
+		return []Check{{Name: "apis", Status: StatusFail, Detail: fmt.Sprintf("failed to create Service Usage client: %v", err)}}
+	}
+
+	apis := append([]string{}, requiredAPIs...)
+	for api := range optionalAPIs {
+		apis = append(apis, api)
+	}
+	sort.Strings(apis[len(requiredAPIs):])
+	names := make([]string, len(apis))
+	for i, api := range apis {
+		names[i] = fmt.Sprintf("projects/%s/services/%s", projectID, api)
+	}
+	resp, err := service.Services.BatchGet("projects/" + projectID).Names(names...).Context(ctx).Do()
+	if err != nil {
+		return []Check{{
+			Name:   "apis",
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("failed to check which APIs are enabled: %v", err),
+			Remedy: "Enable the Service Usage API with `gcloud services enable serviceusage.googleapis.com --project " + projectID + "`, or grant roles/serviceusage.serviceUsageViewer",
+		}}
+	}
+	enabled := make(map[string]bool)
+	for _, s := range resp.Services {
+		enabled[s.Name[strings.LastIndex(s.Name, "/")+1:]] = s.State == "ENABLED"
+	}
+
+	checks := make([]Check, 0, len(apis))
+	for _, api := range apis {
+		check := Check{Name: "api " + api, Status: StatusOK, Detail: "enabled"}
+		if !enabled[api] {
+			check.Status = StatusFail
+			check.Detail = "disabled"
+			if feature, optional := optionalAPIs[api]; optional {
+				check.Status = StatusWarn
+				check.Detail = "disabled, needed for " + feature
+			}
+			check.Remedy = fmt.Sprintf("gcloud services enable %s --project %s", api, projectID)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkPermissions checks the credentials hold every permission research needs on the project
+func checkPermissions(ctx context.Context, projectID string, opts []option.ClientOption) Check {
+	check := Check{Name: "iam"}
+	service, err := cloudresourcemanager.NewService(ctx, opts...)
+	if err != nil {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("failed to create Resource Manager client: %v", err)
+		return check
+	}
+
+	permissions := make([]string, 0, len(requiredPermissions))
+	for permission := range requiredPermissions {
+		permissions = append(permissions, permission)
+	}
+	sort.Strings(permissions)
+	resp, err := service.Projects.TestIamPermissions(projectID, &cloudresourcemanager.TestIamPermissionsRequest{
+		Permissions: permissions,
+	}).Context(ctx).Do()
+	if err != nil {
+		check.Status = StatusWarn
+		check.Detail = fmt.Sprintf("failed to test IAM permissions: %v", err)
+		check.Remedy = fmt.Sprintf("gcloud services enable cloudresourcemanager.googleapis.com --project %s", projectID)
+		return check
+	}
+
+	granted := make(map[string]bool)
+	for _, permission := range resp.Permissions {
+		granted[permission] = true
+	}
+	var missing []string
+	roles := make(map[string]bool)
+	for _, permission := range permissions {
+		if !granted[permission] {
+			missing = append(missing, permission)
+			roles[requiredPermissions[permission]] = true
+		}
+	}
+	if len(missing) == 0 {
+		check.Status = StatusOK
+		check.Detail = fmt.Sprintf("all %d permissions granted", len(permissions))
+		return check
+	}
+
+	grant := make([]string, 0, len(roles))
+	for role := range roles {
+		grant = append(grant, role)
+	}
+	sort.Strings(grant)
+	check.Status = StatusFail
+	check.Detail = "missing " + strings.Join(missing, ", ")
+	check.Remedy = fmt.Sprintf("Grant the credentials %s on project %s, e.g. `gcloud projects add-iam-policy-binding %s --member=<principal> --role=%s`",
+		strings.Join(grant, ", "), projectID, projectID, grant[0])
+	return check
+}
+
+// checkImages checks each drone type's image exists in its registry
+func checkImages(ctx context.Context, images map[string]string) []Check {
+	if len(images) == 0 {
+		return nil
+	}
+	resolver, err := gcp.NewImageResolver(ctx, images, nil)
+	if err != nil {
+		return []Check{{Name: "images", Status: StatusFail, Detail: err.Error(), Remedy: "Fix the JSON file at DRONE_IMAGES_CONFIG"}}
+	}
+
+	droneTypes := make([]string, 0, len(images))
+	for droneType := range images {
+		droneTypes = append(droneTypes, droneType)
+	}
+	sort.Strings(droneTypes)
+	checks := make([]Check, 0, len(droneTypes))
+	for _, droneType := range droneTypes {
+		check := Check{Name: "image " + droneType}
+		image, err := resolver.Image(ctx, droneType)
+		if err != nil {
+			check.Status = StatusFail
+			check.Detail = err.Error()
+			check.Remedy = fmt.Sprintf("Build and push the %s drone image, or point DRONE_IMAGE_%s at an existing one", droneType, strings.ToUpper(droneType))
+		} else {
+			check.Status = StatusOK
+			check.Detail = image
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkKey warns when an API key is not set
+func checkKey(name, without, remedy string) Check {
+	if os.Getenv(name) != "" {
+		return Check{Name: strings.ToLower(name), Status: StatusOK, Detail: "set"}
+	}
+	return Check{Name: strings.ToLower(name), Status: StatusWarn, Detail: "not set, " + without, Remedy: remedy}
+}
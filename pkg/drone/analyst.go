@@ -0,0 +1,182 @@
+package drone
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// trendTolerance is the relative change per step below which a series is flat
+const trendTolerance = 0.01
+
+// AnalystDrone represents a drone that computes statistics and trends over data
+type AnalystDrone struct {
+	*baseDrone
+}
+
+// NewAnalystDrone creates a new analyst drone MCP server
+func NewAnalystDrone() (*AnalystDrone, error) {
+	base, err := newBaseDrone(types.DroneTypeAnalyst)
+	if err != nil {
+		return nil, err
+	}
+	d := &AnalystDrone{baseDrone: base}
+	base.tools = []Tool{
+		{
+			Name:        "analyze_data",
+			Description: "Describe data: count, mean, median, min, max and standard deviation of a list of numbers, or of each numeric field of a list of records",
+			Run: func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+				return d.AnalyzeData(args["data"])
+			},
+		},
+		{
+			Name:        "detect_trends",
+			Description: "Fit a linear trend to an ordered list of numbers, or to one field of a list of records",
+			Run: func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+				return d.DetectTrends(args["data"], stringArg(args, "field"))
+			},
+		},
+	}
+	return d, nil
+}
+
+// AnalyzeData describes a list of numbers, or each numeric field of a list of records
+func (d *AnalystDrone) AnalyzeData(data interface{}) (map[string]interface{}, error) {
+	columns, err := numericColumns(data)
+	if err != nil {
+		return nil, err
+	}
+
+	statistics := make(map[string]interface{}, len(columns))
+	for name, values := range columns {
+		statistics[name] = describe(values)
+	}
+	return map[string]interface{}{
+		"statistics": statistics,
+		"summary":    fmt.Sprintf("Described %d numeric fields", len(columns)),
+		"droneId":    d.droneID,
+		"timestamp":  time.Now(),
+	}, nil
+}
+
+// DetectTrends fits a least-squares line to a series and reports its direction
+func (d *AnalystDrone) DetectTrends(data interface{}, field string) (map[string]interface{}, error) {
+	columns, err := numericColumns(data)
+	if err != nil {
+		return nil, err
+	}
+	if field == "" {
+		field = "value"
+	}
+	series, ok := columns[field]
+	if !ok {
+		return nil, fmt.Errorf("data has no numeric field %q", field)
+	}
+	if len(series) < 2 {
+		return nil, fmt.Errorf("a trend needs at least 2 values, got %d", len(series))
+	}
+
+	slope, intercept := linearFit(series)
+	direction := "flat"
+	if mean := math.Abs(describe(series)["mean"]); mean > 0 && math.Abs(slope)/mean > trendTolerance {
+		direction = "rising"
+		if slope < 0 {
+			direction = "falling"
+		}
+	}
+	first, last := series[0], series[len(series)-1]
+	trend := map[string]interface{}{
+		"field":     field,
+		"slope":     slope,
+		"intercept": intercept,
+		"direction": direction,
+		"points":    len(series),
+		"summary":   fmt.Sprintf("%s is %s, changing by %.3g per step", field, direction, slope),
+		"droneId":   d.droneID,
+		"timestamp": time.Now(),
+	}
+	if first != 0 {
+		trend["change_percent"] = (last - first) / math.Abs(first) * 100
+	}
+	return trend, nil
+}
+
+// numericColumns reads a list of numbers as the column "value", or a list of
+// records as a column per numeric field
+func numericColumns(data interface{}) (map[string][]float64, error) {
+	items, ok := data.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("data must be a non-empty list of numbers or records")
+	}
+
+	columns := make(map[string][]float64)
+	for i, item := range items {
+		switch value := item.(type) {
+		case float64:
+			columns["value"] = append(columns["value"], value)
+		case map[string]interface{}:
+			for name, field := range value {
+				if number, ok := field.(float64); ok {
+					columns[name] = append(columns[name], number)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("data item %d is neither a number nor a record", i)
+		}
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("data has no numeric values")
+	}
+	return columns, nil
+}
+
+// describe returns the summary statistics of values
+func describe(values []float64) map[string]float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+	n := float64(len(sorted))
+	mean := sum / n
+
+	variance := 0.0
+	for _, v := range sorted {
+		variance += (v - mean) * (v - mean)
+	}
+
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + median) / 2
+	}
+	return map[string]float64{
+		"count":  n,
+		"mean":   mean,
+		"median": median,
+		"min":    sorted[0],
+		"max":    sorted[len(sorted)-1],
+		"stddev": math.Sqrt(variance / n),
+	}
+}
+
+// linearFit returns the least-squares line through values at x = 0, 1, 2, ...
+func linearFit(values []float64) (slope, intercept float64) {
+	n := float64(len(values))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, y := range values {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	slope = (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	intercept = (sumY - slope*sumX) / n
+	return slope, intercept
+}
@@ -0,0 +1,127 @@
+package drone
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// defaultCheckpointInterval is used when a task does not specify one
+const defaultCheckpointInterval = 30 * time.Second
+
+// checkpointer periodically reports a task's progress to Firestore so the
+// coordinator can resume the task on another drone if this one fails
+type checkpointer struct {
+	drone      *baseDrone
+	checkpoint types.TaskCheckpoint
+	mu         sync.Mutex
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// loadCheckpoint returns the checkpoint the coordinator left for this drone, if any
+func (d *baseDrone) loadCheckpoint(ctx context.Context, taskID string) *types.TaskCheckpoint {
+	if d.firestoreClient == nil {
+		return nil
+	}
+
+	doc, err := d.firestoreClient.Collection(types.CheckpointCollection).Doc(types.CheckpointID(taskID, d.droneID)).Get(ctx)
+	if err != nil {
+		return nil
+	}
+
+	var checkpoint types.TaskCheckpoint
+	if err := doc.DataTo(&checkpoint); err != nil {
+		log.Printf("Warning: Failed to read checkpoint for task %s: %v", taskID, err)
+		return nil
+	}
+	return &checkpoint
+}
+
+// startCheckpointing begins reporting progress for a task every interval,
+// continuing from resume when the task was handed over from a failed drone
+func (d *baseDrone) startCheckpointing(taskID string, interval time.Duration, resume *types.TaskCheckpoint) *checkpointer {
+	if interval <= 0 {
+		interval = defaultCheckpointInterval
+	}
+
+	c := &checkpointer{
+		drone: d,
+		checkpoint: types.TaskCheckpoint{
+			TaskID:  taskID,
+			DroneID: d.droneID,
+			State:   make(map[string]interface{}),
+		},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	if resume != nil {
+		c.checkpoint.Progress = resume.Progress
+		c.checkpoint.RetryCount = resume.RetryCount
+		if resume.State != nil {
+			c.checkpoint.State = resume.State
+		}
+	}
+
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				c.save()
+			}
+		}
+	}()
+
+	return c
+}
+
+// Update records progress and state to be written at the next checkpoint
+func (c *checkpointer) Update(progress float64, state map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checkpoint.Progress = progress
+	for k, v := range state {
+		c.checkpoint.State[k] = v
+	}
+}
+
+// Finish stops periodic reporting and writes a final checkpoint
+func (c *checkpointer) Finish() {
+	close(c.stop)
+	<-c.done
+	c.save()
+}
+
+// save writes the current checkpoint to Firestore
+func (c *checkpointer) save() {
+	if c.drone.firestoreClient == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.checkpoint.Timestamp = time.Now()
+	checkpoint := c.checkpoint
+	state := make(map[string]interface{}, len(c.checkpoint.State))
+	for k, v := range c.checkpoint.State {
+		state[k] = v
+	}
+	checkpoint.State = state
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	docID := types.CheckpointID(checkpoint.TaskID, checkpoint.DroneID)
+	if _, err := c.drone.firestoreClient.Collection(types.CheckpointCollection).Doc(docID).Set(ctx, checkpoint); err != nil {
+		log.Printf("Warning: Failed to save checkpoint for task %s: %v", checkpoint.TaskID, err)
+	}
+}
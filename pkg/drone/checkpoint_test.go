@@ -0,0 +1,75 @@
+package drone
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// testDrone returns a drone without clients whose one tool counts its runs
+func testDrone(runs *int) *baseDrone {
+	return &baseDrone{
+		droneID: "d2",
+		metrics: newDroneMetrics(),
+		tools: []Tool{{
+			Name: "research",
+			Run: func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+				*runs++
+				return map[string]interface{}{"summary": "fresh"}, nil
+			},
+		}},
+	}
+}
+
+func TestStartTaskResumesFromCheckpoint(t *testing.T) {
+	saved := map[string]interface{}{"summary": "from the failed drone"}
+	tests := []struct {
+		name     string
+		resume   *types.TaskCheckpoint
+		want     map[string]interface{}
+		wantRuns int
+	}{
+		{
+			name:     "no checkpoint",
+			want:     map[string]interface{}{"summary": "fresh"},
+			wantRuns: 1,
+		},
+		{
+			name:     "checkpoint before the research finished",
+			resume:   &types.TaskCheckpoint{TaskID: "task-1", DroneID: "d2", Progress: 0.1, RetryCount: 1, State: map[string]interface{}{"stage": "researching"}},
+			want:     map[string]interface{}{"summary": "fresh"},
+			wantRuns: 1,
+		},
+		{
+			name:     "checkpoint with the finished research",
+			resume:   &types.TaskCheckpoint{TaskID: "task-1", DroneID: "d2", Progress: 0.9, RetryCount: 1, State: map[string]interface{}{"stage": "publishing", "result": saved}},
+			want:     saved,
+			wantRuns: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := 0
+			d := testDrone(&runs)
+			taskID, cp, res, err := d.startTask(context.Background(), researchRequest{Subject: "s", TaskID: "task-1", Resume: tt.resume})
+			if err != nil {
+				t.Fatalf("startTask() returned an error: %v", err)
+			}
+			defer cp.Finish()
+			if taskID != "task-1" {
+				t.Errorf("task ID = %q, want task-1", taskID)
+			}
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("result = %v, want %v", res, tt.want)
+			}
+			if runs != tt.wantRuns {
+				t.Errorf("tool ran %d times, want %d", runs, tt.wantRuns)
+			}
+			if tt.resume != nil && cp.checkpoint.RetryCount != tt.resume.RetryCount {
+				t.Errorf("checkpoint retry count = %d, want %d carried over", cp.checkpoint.RetryCount, tt.resume.RetryCount)
+			}
+		})
+	}
+}
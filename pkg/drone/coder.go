@@ -0,0 +1,229 @@
+package drone
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+const (
+	// defaultExecTimeout bounds code runs that do not set a timeout
+	defaultExecTimeout = 30 * time.Second
+	// maxExecTimeout bounds every code run
+	maxExecTimeout = 5 * time.Minute
+	// maxExecOutput is how much of a run's stdout and stderr is kept
+	maxExecOutput = 64 << 10
+)
+
+// codeRunner is how the coder drone runs a language: the program to run it
+// with, its arguments before the source file, and the file's name
+type codeRunner struct {
+	command string
+	args    []string
+	file    string
+}
+
+// codeRunners are the languages execute_code supports. Each needs its
+// interpreter or toolchain installed in the drone's image.
+var codeRunners = map[string]codeRunner{
+	"python":     {command: "python3", file: "main.py"},
+	"go":         {command: "go", args: []string{"run"}, file: "main.go"},
+	"javascript": {command: "node", file: "main.js"},
+	"bash":       {command: "bash", file: "main.sh"},
+}
+
+// codeTemplates are the skeletons generate_code starts from
+var codeTemplates = map[string]string{
+	"python":     "# %s\n\n\ndef main():\n    raise NotImplementedError(%q)\n\n\nif __name__ == \"__main__\":\n    main()\n",
+	"go":         "// %s\npackage main\n\nfunc main() {\n\tpanic(%q)\n}\n",
+	"javascript": "// %s\n\nfunction main() {\n  throw new Error(%q);\n}\n\nmain();\n",
+	"bash":       "#!/usr/bin/env bash\n# %s\nset -euo pipefail\n\necho %q >&2\nexit 1\n",
+}
+
+// CoderDrone represents a drone that generates and runs code
+type CoderDrone struct {
+	*baseDrone
+	sandbox SandboxConfig
+}
+
+// NewCoderDrone creates a new coder drone MCP server
+func NewCoderDrone() (*CoderDrone, error) {
+	base, err := newBaseDrone(types.DroneTypeCoder)
+	if err != nil {
+		return nil, err
+	}
+	d := &CoderDrone{baseDrone: base, sandbox: LoadSandboxConfig()}
+	base.tools = []Tool{
+		{
+			Name:        "generate_code",
+			Description: "Generate a program skeleton for a specification in python, go, javascript or bash",
+			Run: func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+				return d.GenerateCode(stringArg(args, "subject"), stringArg(args, "language"))
+			},
+		},
+		{
+			Name:        "execute_code",
+			Description: "Run a program in python, go, javascript or bash without network, and return its exit code, output and the files it wrote to $ARTIFACTS_DIR",
+			Run: func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+				timeout := time.Duration(intArg(args, "timeout_sec", 0)) * time.Second
+				network, _ := args["network"].(bool)
+				return d.ExecuteCode(ctx, stringArg(args, "language"), stringArg(args, "code"), timeout, network)
+			},
+		},
+	}
+	return d, nil
+}
+
+// GenerateCode returns a program skeleton for spec, documented with the spec
+func (d *CoderDrone) GenerateCode(spec, language string) (map[string]interface{}, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("subject is required")
+	}
+	if language == "" {
+		language = "python"
+	}
+	template, ok := codeTemplates[language]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q", language)
+	}
+
+	return map[string]interface{}{
+		"language":  language,
+		"file":      codeRunners[language].file,
+		"code":      fmt.Sprintf(template, spec, "not implemented: "+spec),
+		"summary":   fmt.Sprintf("Generated a %s skeleton for %s", language, spec),
+		"droneId":   d.droneID,
+		"timestamp": time.Now(),
+	}, nil
+}
+
+// ExecuteCode runs code in a scratch directory, stopping it after timeout. The
+// program has no network unless it asks for it and the drone allows it, and
+// runs with the sandbox's resource limits. A program that fails still returns
+// its exit code and output; only a program the drone cannot run is an error.
+func (d *CoderDrone) ExecuteCode(ctx context.Context, language, code string, timeout time.Duration, network bool) (map[string]interface{}, error) {
+	if code == "" {
+		return nil, fmt.Errorf("code is required")
+	}
+	if network && !d.sandbox.AllowNetwork {
+		return nil, fmt.Errorf("network access is disabled on this drone")
+	}
+	runner, ok := codeRunners[language]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q", language)
+	}
+	command, err := exec.LookPath(runner.command)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not installed on this drone: %w", runner.command, err)
+	}
+	if timeout <= 0 {
+		timeout = defaultExecTimeout
+	}
+	if timeout > maxExecTimeout {
+		timeout = maxExecTimeout
+	}
+
+	dir, err := os.MkdirTemp("", "coder-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	source := filepath.Join(dir, runner.file)
+	if err := os.WriteFile(source, []byte(code), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write source: %w", err)
+	}
+	artifactsDir := filepath.Join(dir, artifactsDirName)
+	if err := os.Mkdir(artifactsDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, command, append(runner.args, source)...)
+	cmd.Dir = dir
+	// Children of a stopped program may hold its output open
+	cmd.WaitDelay = time.Second
+	// Programs get a scratch home and none of the drone's environment, which
+	// isolate keeps them from reading through /proc either
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH"), "HOME=" + dir, "TMPDIR=" + dir, "GOCACHE=" + filepath.Join(dir, ".cache"), "ARTIFACTS_DIR=" + artifactsDir}
+	stdout := &limitedBuffer{limit: maxExecOutput}
+	stderr := &limitedBuffer{limit: maxExecOutput}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if err := isolate(cmd, network); err != nil {
+		return nil, fmt.Errorf("failed to sandbox %s: %w", runner.command, err)
+	}
+	if err := limit(cmd, d.sandbox, timeout); err != nil {
+		return nil, fmt.Errorf("failed to sandbox %s: %w", runner.command, err)
+	}
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w", runner.command, err)
+	}
+	runErr := cmd.Wait()
+	exitCode := 0
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(runErr, &exitErr):
+		exitCode = exitErr.ExitCode()
+	case errors.Is(runErr, exec.ErrWaitDelay):
+	case runErr != nil:
+		return nil, fmt.Errorf("failed to run %s: %w", runner.command, runErr)
+	}
+	timedOut := ctx.Err() == context.DeadlineExceeded
+	log.Printf("Drone %s ran %s code: exit %d in %v", d.droneID, language, exitCode, time.Since(start))
+
+	summary := fmt.Sprintf("%s program exited with code %d", language, exitCode)
+	if timedOut {
+		summary = fmt.Sprintf("%s program was stopped after %v", language, timeout)
+	}
+	return map[string]interface{}{
+		"language":    language,
+		"exit_code":   exitCode,
+		"stdout":      stdout.String(),
+		"stderr":      stderr.String(),
+		"truncated":   stdout.truncated || stderr.truncated,
+		"timed_out":   timedOut,
+		"network":     network,
+		"artifacts":   collectArtifacts(artifactsDir, d.sandbox.MaxArtifactBytes),
+		"duration_ms": time.Since(start).Milliseconds(),
+		"summary":     summary,
+		"droneId":     d.droneID,
+		"timestamp":   time.Now(),
+	}, nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it and drops the rest
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// String returns what was kept, noting when output was dropped
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return strings.TrimRight(b.buf.String(), "\n") + "\n[output truncated]"
+	}
+	return b.buf.String()
+}
@@ -0,0 +1,340 @@
+package drone
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/pubsub"
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+	"github.com/spawn-mcp/coordinator/pkg/payload"
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// Drone is a drone MCP server of any type. Every type takes tasks over HTTP,
+// reports heartbeats and publishes its results to Pub/Sub; they differ in the
+// tools they run tasks with.
+type Drone interface {
+	// ID returns the drone's DRONE_ID
+	ID() string
+	// Type returns the kind of work the drone does
+	Type() types.DroneType
+	// Tools returns the tools tasks can ask for, the default one first
+	Tools() []Tool
+	StartHeartbeat()
+	StartMetricsReporting()
+	StartHTTPServer(addr string) error
+	Close() error
+}
+
+// Tool is something a drone can do for a task. Arguments come from the task's
+// arguments, with its subject, sources and context added when not given.
+type Tool struct {
+	Name        string
+	Description string
+	Run         func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error)
+}
+
+// New creates a drone of the given type. Types without their own drone, such
+// as workers, run as researchers, as every drone did before the other types.
+func New(droneType types.DroneType) (Drone, error) {
+	switch droneType {
+	case types.DroneTypeResearcher, "":
+		return NewResearcherDrone()
+	case types.DroneTypeAnalyst:
+		return NewAnalystDrone()
+	case types.DroneTypeWriter:
+		return NewWriterDrone()
+	case types.DroneTypeCoder:
+		return NewCoderDrone()
+	default:
+		log.Printf("Warning: no %q drone, running a researcher", droneType)
+		return NewResearcherDrone()
+	}
+}
+
+// baseDrone is what every drone type shares: its clients, task handling,
+// checkpoints and heartbeats
+type baseDrone struct {
+	droneID        string
+	droneType      types.DroneType
+	tools          []Tool
+	coordinatorURL string
+	taskID         string
+	pubsubClient   *pubsub.Client
+	pubsubTopic    *pubsub.Topic
+	// taskTopics reuses per-task topic publishers so each drone's results stay ordered
+	taskTopics   map[string]*pubsub.Topic
+	taskTopicsMu sync.Mutex
+	// firestoreClient stores task checkpoints; nil disables checkpointing
+	firestoreClient *firestore.Client
+	// currentTask is the task heartbeats report, empty while idle
+	currentTask   string
+	currentTaskMu sync.Mutex
+	// Signal and wait for the heartbeat loop
+	heartbeatStop chan struct{}
+	heartbeatDone chan struct{}
+	// metrics counts the drone's work for /metrics and its session
+	metrics *droneMetrics
+	// payloads encodes results too large to publish as they are
+	payloads *payload.Codec
+}
+
+// newBaseDrone creates the clients of a drone of the given type from the environment
+func newBaseDrone(droneType types.DroneType) (*baseDrone, error) {
+	ctx := context.Background()
+	// Get configuration from environment
+	droneID := os.Getenv("DRONE_ID")
+	if droneID == "" {
+		return nil, fmt.Errorf("DRONE_ID environment variable is required")
+	}
+
+	coordinatorURL := os.Getenv("COORDINATOR_URL")
+	taskID := os.Getenv("TASK_ID")
+
+	projectID := gcp.ProjectID()
+	if projectID == "" {
+		return nil, fmt.Errorf("GOOGLE_CLOUD_PROJECT environment variable is required")
+	}
+
+	// Warm pool drones start without a session and receive their topic with each task
+	topicID := os.Getenv("PUBSUB_TOPIC")
+
+	pubsubClient, err := pubsub.NewClient(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pubsub client: %w", err)
+	}
+
+	var topic *pubsub.Topic
+	if topicID != "" {
+		topic = pubsubClient.Topic(topicID)
+		topic.EnableMessageOrdering = true
+	}
+
+	firestoreClient, err := firestore.NewClient(ctx, projectID)
+	if err != nil {
+		log.Printf("Warning: failed to create Firestore client, checkpointing disabled: %v", err)
+		firestoreClient = nil
+	}
+
+	return &baseDrone{
+		droneID:         droneID,
+		droneType:       droneType,
+		coordinatorURL:  coordinatorURL,
+		taskID:          taskID,
+		pubsubClient:    pubsubClient,
+		pubsubTopic:     topic,
+		taskTopics:      make(map[string]*pubsub.Topic),
+		firestoreClient: firestoreClient,
+		metrics:         newDroneMetrics(),
+		payloads:        payload.NewCodec(payload.LoadConfig()),
+	}, nil
+}
+
+// ID returns the drone's ID
+func (d *baseDrone) ID() string {
+	return d.droneID
+}
+
+// Type returns the drone's type
+func (d *baseDrone) Type() types.DroneType {
+	return d.droneType
+}
+
+// Tools returns the drone's tools
+func (d *baseDrone) Tools() []Tool {
+	return d.tools
+}
+
+// runTask runs the tool a task names, or the drone's default tool
+func (d *baseDrone) runTask(ctx context.Context, req researchRequest) (map[string]interface{}, error) {
+	if len(d.tools) == 0 {
+		return nil, fmt.Errorf("%s drone has no tools", d.droneType)
+	}
+	tool := d.tools[0]
+	if req.Tool != "" {
+		found := false
+		for _, t := range d.tools {
+			if t.Name == req.Tool {
+				tool, found = t, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s drone has no tool %q", d.droneType, req.Tool)
+		}
+	}
+
+	args := make(map[string]interface{}, len(req.Arguments)+3)
+	for name, value := range req.Arguments {
+		args[name] = value
+	}
+	if _, ok := args["subject"]; !ok && req.Subject != "" {
+		args["subject"] = req.Subject
+	}
+	if _, ok := args["sources"]; !ok && len(req.Sources) > 0 {
+		args["sources"] = req.Sources
+	}
+	if _, ok := args["context"]; !ok && req.Context != "" {
+		args["context"] = req.Context
+	}
+
+	log.Printf("Drone %s running %s [%s]", d.droneID, tool.Name, req.CorrelationID)
+	res, err := tool.Run(ctx, args)
+	d.metrics.recordTask(res, err)
+	return res, err
+}
+
+// Close closes the drone and cleans up resources
+func (d *baseDrone) Close() error {
+	d.stopHeartbeat()
+	d.stopMetricsReporting()
+	if d.pubsubClient != nil {
+		d.pubsubClient.Close()
+	}
+	if d.firestoreClient != nil {
+		d.firestoreClient.Close()
+	}
+	return nil
+}
+
+// resultTopic returns the publisher for a task's topic, or the drone's default topic
+func (d *baseDrone) resultTopic(topicID string) *pubsub.Topic {
+	if topicID == "" {
+		return d.pubsubTopic
+	}
+
+	d.taskTopicsMu.Lock()
+	defer d.taskTopicsMu.Unlock()
+	topic, ok := d.taskTopics[topicID]
+	if !ok {
+		topic = d.pubsubClient.Topic(topicID)
+		topic.EnableMessageOrdering = true
+		d.taskTopics[topicID] = topic
+	}
+	return topic
+}
+
+// publishResult publishes the research result to the task's topic, or the drone's default topic.
+// Results are keyed by drone ID so the orchestrator receives each drone's results in order, and
+// carry the correlation ID of the call that started the task.
+func (d *baseDrone) publishResult(ctx context.Context, topicID, taskID, correlationID string, resultData map[string]interface{}) error {
+	// We need to wrap the raw result data in the DroneResult schema
+	// to be consistent with what the orchestrator expects.
+	err := d.publish(ctx, topicID, schemas.DroneResult{
+		DroneID:        d.droneID,
+		TaskID:         taskID,
+		CorrelationID:  correlationID,
+		Status:         "success", // Assuming success if this method is called
+		Data:           resultData,
+		CompletedAt:    time.Now(),
+		ProcessingTime: 0, // This can be properly calculated in the http worker
+	})
+	// A result the orchestrator could not analyze is reported as a failure, so it does not wait for it
+	var mcpErr *mcperrors.MCPError
+	if errors.As(err, &mcpErr) && mcpErr.Category == mcperrors.CategoryInvalidResult {
+		log.Printf("ERROR: Task %s produced an invalid result [%s %s]: %v", taskID, mcpErr.Code, correlationID, err)
+		return d.publishFailure(ctx, topicID, taskID, correlationID, fmt.Errorf("%s: %w", mcpErr.Code, err))
+	}
+	return err
+}
+
+// publishFailure publishes a failed result for a task, so the orchestrator does
+// not wait for it until the session times out
+func (d *baseDrone) publishFailure(ctx context.Context, topicID, taskID, correlationID string, taskErr error) error {
+	return d.publish(ctx, topicID, schemas.DroneResult{
+		DroneID:       d.droneID,
+		TaskID:        taskID,
+		CorrelationID: correlationID,
+		Status:        "failed",
+		Error:         taskErr.Error(),
+		CompletedAt:   time.Now(),
+	})
+}
+
+// publish publishes a drone result to the task's topic, or the drone's default topic
+func (d *baseDrone) publish(ctx context.Context, topicID string, result schemas.DroneResult) error {
+	topic := d.resultTopic(topicID)
+	if topic == nil {
+		return fmt.Errorf("no Pub/Sub topic configured for results")
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		return mcperrors.New(mcperrors.CategoryInvalidResult, "failed to marshal result: %w", err)
+	}
+	// Validate the result as the orchestrator will decode it
+	var decoded schemas.DroneResult
+	if err := json.Unmarshal(jsonData, &decoded); err != nil {
+		return mcperrors.New(mcperrors.CategoryInvalidResult, "failed to decode marshaled result: %w", err)
+	}
+	if err := decoded.Validate(); err != nil {
+		return err
+	}
+
+	// Large results are compressed, and their data offloaded to Cloud Storage if still too large
+	data, attributes, err := d.payloads.Encode(ctx, topic.ID(), result)
+	if err != nil {
+		return err
+	}
+	attributes["drone_id"] = d.droneID
+	if result.CorrelationID != "" {
+		attributes[mcperrors.CorrelationAttribute] = result.CorrelationID
+	}
+	msg := &pubsub.Message{
+		Data:        data,
+		Attributes:  attributes,
+		OrderingKey: d.droneID,
+	}
+
+	if _, err := topic.Publish(ctx, msg).Get(ctx); err != nil {
+		// A failed ordered publish pauses the key until it is resumed
+		topic.ResumePublish(d.droneID)
+		return fmt.Errorf("failed to publish result: %w", err)
+	}
+
+	log.Printf("Drone %s published result of task %s to topic %s (%d bytes) [%s]", d.droneID, result.TaskID, topic.String(), len(data), result.CorrelationID)
+	return nil
+}
+
+// stringArg returns a string argument, or "" when it is missing
+func stringArg(args map[string]interface{}, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
+
+// stringsArg returns a list-of-strings argument, skipping items that are not strings
+func stringsArg(args map[string]interface{}, name string) []string {
+	switch value := args[name].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// intArg returns a number argument as an int, or def when it is missing
+func intArg(args map[string]interface{}, name string, def int) int {
+	switch value := args[name].(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	}
+	return def
+}
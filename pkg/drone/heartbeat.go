@@ -0,0 +1,103 @@
+package drone
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// defaultHeartbeatInterval is used when HEARTBEAT_INTERVAL_SEC is not set
+const defaultHeartbeatInterval = 15 * time.Second
+
+// heartbeatInterval reads how often the drone reports that it is alive
+func heartbeatInterval() time.Duration {
+	if seconds, err := strconv.Atoi(os.Getenv("HEARTBEAT_INTERVAL_SEC")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultHeartbeatInterval
+}
+
+// StartHeartbeat publishes a heartbeat to HEARTBEAT_TOPIC now and every
+// HEARTBEAT_INTERVAL_SEC until the drone is closed, so the orchestrator can
+// replace drones that stop responding
+func (d *baseDrone) StartHeartbeat() {
+	topicID := os.Getenv("HEARTBEAT_TOPIC")
+	if topicID == "" {
+		topicID = types.DefaultHeartbeatTopic
+	}
+	topic := d.pubsubClient.Topic(topicID)
+	interval := heartbeatInterval()
+
+	d.heartbeatStop = make(chan struct{})
+	d.heartbeatDone = make(chan struct{})
+	go func() {
+		defer close(d.heartbeatDone)
+		defer topic.Stop()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			d.publishHeartbeat(topic, interval)
+			select {
+			case <-d.heartbeatStop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Printf("Drone %s publishing heartbeats to %s every %v", d.droneID, topicID, interval)
+}
+
+// stopHeartbeat stops publishing heartbeats, if they were started
+func (d *baseDrone) stopHeartbeat() {
+	if d.heartbeatStop == nil {
+		return
+	}
+	close(d.heartbeatStop)
+	<-d.heartbeatDone
+	d.heartbeatStop = nil
+}
+
+// publishHeartbeat reports the drone's current task, waiting at most one interval
+func (d *baseDrone) publishHeartbeat(topic *pubsub.Topic, interval time.Duration) {
+	heartbeat := types.DroneHeartbeat{
+		DroneID:   d.droneID,
+		Status:    "idle",
+		Timestamp: time.Now(),
+	}
+	if taskID := d.currentTaskID(); taskID != "" {
+		heartbeat.TaskID = taskID
+		heartbeat.Status = "working"
+	}
+	data, err := json.Marshal(heartbeat)
+	if err != nil {
+		log.Printf("Warning: Failed to encode heartbeat: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), interval)
+	defer cancel()
+	if _, err := topic.Publish(ctx, &pubsub.Message{Data: data}).Get(ctx); err != nil {
+		log.Printf("Warning: Failed to publish heartbeat: %v", err)
+	}
+}
+
+// setCurrentTask records the task heartbeats report; "" marks the drone idle
+func (d *baseDrone) setCurrentTask(taskID string) {
+	d.currentTaskMu.Lock()
+	defer d.currentTaskMu.Unlock()
+	d.currentTask = taskID
+}
+
+// currentTaskID returns the task the drone is working on
+func (d *baseDrone) currentTaskID() string {
+	d.currentTaskMu.Lock()
+	defer d.currentTaskMu.Unlock()
+	return d.currentTask
+}
@@ -3,10 +3,12 @@ package drone
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"time"
 
+	"github.com/spawn-mcp/coordinator/pkg/health"
 	"github.com/spawn-mcp/coordinator/pkg/types"
 )
 
@@ -18,6 +20,23 @@ type researchRequest struct {
 	Sources   []string          `json:"sources,omitempty"`
 	RunID     string            `json:"run_id,omitempty"`
 	Meta      map[string]string `json:"meta,omitempty"`
+	// PubSubTopic overrides the drone's PUBSUB_TOPIC for this task's result
+	PubSubTopic string `json:"pubsub_topic,omitempty"`
+	// MetricsTopic overrides the drone's METRICS_TOPIC from this task on
+	MetricsTopic string `json:"metrics_topic,omitempty"`
+	// TaskID enables progress checkpoints for the task
+	TaskID                string `json:"task_id,omitempty"`
+	CheckpointIntervalSec int    `json:"checkpoint_interval_sec,omitempty"`
+	// Resume is the checkpoint the coordinator hands over from a failed drone;
+	// the drone's own checkpoint in Firestore is used when it is not sent
+	Resume *types.TaskCheckpoint `json:"resume,omitempty"`
+	// CorrelationID traces the task to the tool call that started it
+	CorrelationID string `json:"correlation_id,omitempty"`
+	// Context is what earlier workflow steps found
+	Context string `json:"context,omitempty"`
+	// Tool names the drone tool to run; the drone's default tool when empty
+	Tool      string                 `json:"tool,omitempty"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
 }
 
 // researchResponse is the structured output including summary, citations, entities, triples.
@@ -32,55 +51,184 @@ type researchResponse struct {
 	Timestamp time.Time          `json:"timestamp"`
 }
 
-func (d *ResearcherDrone) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+// instructionCommand is the body the orchestrator POSTs to /instructions: one
+// task in Instructions, or a batch in Tasks that the drone works through in order
+type instructionCommand struct {
+	Type         string            `json:"type"`
+	Instructions *researchRequest  `json:"instructions,omitempty"`
+	Tasks        []researchRequest `json:"tasks,omitempty"`
+}
+
+func (d *baseDrone) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
-		if r.URL.Path == "/health" {
+		switch r.URL.Path {
+		case "/health":
 			w.WriteHeader(http.StatusOK)
 			_, _ = w.Write([]byte("ok"))
-			return
+		case "/metrics":
+			d.serveMetrics(w)
+		default:
+			w.WriteHeader(http.StatusNotFound)
 		}
-		w.WriteHeader(http.StatusNotFound)
 		return
 	case http.MethodPost:
-		if r.URL.Path != "/task" {
+		d.metrics.requestsHandled.Add(1)
+		switch r.URL.Path {
+		case "/task":
+			d.serveTask(w, r)
+		case "/instructions":
+			d.serveInstructions(w, r)
+		default:
 			w.WriteHeader(http.StatusNotFound)
-			return
 		}
-		var req researchRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid json", http.StatusBadRequest)
-			return
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// serveTask researches one task and publishes its result in the background
+func (d *baseDrone) serveTask(w http.ResponseWriter, r *http.Request) {
+	var req researchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	taskID, cp, res, err := d.startTask(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Publish the result to Pub/Sub asynchronously
+	go d.finishTask(context.Background(), req, taskID, cp, res)
+
+	// Respond immediately with 202 Accepted
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = w.Write([]byte("Task accepted for processing."))
+}
+
+// serveInstructions accepts a task or a batch of tasks from the orchestrator
+// and works through them in the background, publishing each result as soon
+// as it is ready
+func (d *baseDrone) serveInstructions(w http.ResponseWriter, r *http.Request) {
+	var command instructionCommand
+	if err := json.NewDecoder(r.Body).Decode(&command); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	tasks := command.Tasks
+	if command.Instructions != nil {
+		tasks = append([]researchRequest{*command.Instructions}, tasks...)
+	}
+	if len(tasks) == 0 {
+		http.Error(w, "no tasks", http.StatusBadRequest)
+		return
+	}
+
+	go func() {
+		ctx := context.Background()
+		for _, req := range tasks {
+			taskID, cp, res, err := d.startTask(ctx, req)
+			if err != nil {
+				log.Printf("ERROR: Research failed for subject '%s' [%s]: %v", req.Subject, req.CorrelationID, err)
+				// The orchestrator waits for a result of every task it sent
+				if err := d.publishFailure(ctx, req.PubSubTopic, taskID, req.CorrelationID, err); err != nil {
+					log.Printf("ERROR: Failed to publish failure of task %s: %v", taskID, err)
+				}
+				continue
+			}
+			d.finishTask(ctx, req, taskID, cp, res)
+		}
+	}()
+
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = fmt.Fprintf(w, "Accepted %d tasks for processing.", len(tasks))
+}
+
+// startTask researches a task, resuming from its checkpoint if an earlier
+// attempt finished the research. It returns the task's ID, its checkpointer
+// (nil without a task ID) and the research result.
+func (d *baseDrone) startTask(ctx context.Context, req researchRequest) (string, *checkpointer, map[string]interface{}, error) {
+	// Orchestrator tasks are identified by their run, as each drone answers one subject per run
+	taskID := req.TaskID
+	if taskID == "" {
+		taskID = req.RunID
+	}
+	d.setCurrentTask(taskID)
+	d.metrics.useSession(req.MetricsTopic, req.RunID)
+	log.Printf("Drone %s accepted task %s [%s]", d.droneID, taskID, req.CorrelationID)
+
+	var cp *checkpointer
+	var res map[string]interface{}
+	if req.TaskID != "" {
+		resume := req.Resume
+		if resume == nil {
+			resume = d.loadCheckpoint(ctx, req.TaskID)
 		}
+		cp = d.startCheckpointing(req.TaskID, time.Duration(req.CheckpointIntervalSec)*time.Second, resume)
+		// A failed drone may already have finished the research before it could publish
+		if resume != nil {
+			if saved, ok := resume.State["result"].(map[string]interface{}); ok {
+				log.Printf("Resuming task %s from checkpoint at %.0f%%", req.TaskID, resume.Progress*100)
+				res = saved
+			}
+		}
+		cp.Update(0.1, map[string]interface{}{"subject": req.Subject, "stage": "researching"})
+	}
 
-		// For MVP: call ConductResearch with basic mapping
-		res, err := d.ConductResearch(req.Subject, "", req.Sources, 5)
+	if res == nil {
+		var err error
+		res, err = d.runTask(ctx, req)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+			if cp != nil {
+				cp.Finish()
+			}
+			d.setCurrentTask("")
+			return taskID, nil, nil, err
 		}
+	}
+	if cp != nil {
+		cp.Update(0.9, map[string]interface{}{"stage": "publishing", "result": res})
+	}
+	return taskID, cp, res, nil
+}
 
-		// Publish the result to Pub/Sub asynchronously
-		go func() {
-			ctx := context.Background()
-			if err := d.publishResult(ctx, res); err != nil {
-				log.Printf("ERROR: Failed to publish research result for subject '%s': %v", req.Subject, err)
-			}
-		}()
+// finishTask publishes a task's result and completes its checkpoint
+func (d *baseDrone) finishTask(ctx context.Context, req researchRequest, taskID string, cp *checkpointer, res map[string]interface{}) {
+	if err := d.publishResult(ctx, req.PubSubTopic, taskID, req.CorrelationID, res); err != nil {
+		log.Printf("ERROR: Failed to publish research result for subject '%s' [%s]: %v", req.Subject, req.CorrelationID, err)
+	} else if cp != nil {
+		cp.Update(1.0, map[string]interface{}{"stage": "completed"})
+	}
+	if cp != nil {
+		cp.Finish()
+	}
+	d.setCurrentTask("")
+}
 
-		// Respond immediately with 202 Accepted
-		w.WriteHeader(http.StatusAccepted)
-		_, _ = w.Write([]byte("Task accepted for processing."))
-	default:
-		w.WriteHeader(http.StatusMethodNotAllowed)
+// healthChecker checks the drone's clients. Both are optional: tasks may name
+// their own topic, and checkpointing is skipped without Firestore.
+func (d *baseDrone) healthChecker() *health.Checker {
+	checker := health.NewChecker()
+	if d.pubsubTopic != nil {
+		checker.AddOptional("pubsub", health.PubSubTopicCheck(d.pubsubTopic))
+	}
+	if d.firestoreClient != nil {
+		checker.AddOptional("firestore", health.FirestoreCheck(d.firestoreClient))
 	}
+	return checker
 }
 
 // StartHTTPServer starts the HTTP server for the researcher drone.
-func (d *ResearcherDrone) StartHTTPServer(addr string) error {
+func (d *baseDrone) StartHTTPServer(addr string) error {
 	mux := http.NewServeMux()
 	mux.Handle("/health", d)
 	mux.Handle("/task", d)
-	log.Printf("Researcher Drone HTTP listening on %s", addr)
+	mux.Handle("/instructions", d)
+	mux.Handle("/metrics", d)
+	d.healthChecker().Register(mux)
+	log.Printf("%s drone %s HTTP listening on %s", d.droneType, d.droneID, addr)
 	return http.ListenAndServe(addr, mux)
 }
\ No newline at end of file
@@ -0,0 +1,188 @@
+package drone
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// defaultMetricsInterval is used when METRICS_INTERVAL_SEC is not set
+const defaultMetricsInterval = 30 * time.Second
+
+// metricsInterval reads how often the drone reports its metrics
+func metricsInterval() time.Duration {
+	if seconds, err := strconv.Atoi(os.Getenv("METRICS_INTERVAL_SEC")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultMetricsInterval
+}
+
+// droneMetrics counts what a drone has done since it started
+type droneMetrics struct {
+	startedAt       time.Time
+	requestsHandled atomic.Int64
+	tasksCompleted  atomic.Int64
+	tasksFailed     atomic.Int64
+	searchCalls     atomic.Int64
+	llmTokens       atomic.Int64
+
+	// topicID and sessionID are where metrics are published: the drone's
+	// METRICS_TOPIC, or that of the latest task for warm pool drones
+	mu        sync.Mutex
+	topicID   string
+	sessionID string
+	topics    map[string]*pubsub.Topic
+
+	// Signal and wait for the reporting loop
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newDroneMetrics starts counting, reporting to the session in the environment until a task names its own
+func newDroneMetrics() *droneMetrics {
+	return &droneMetrics{
+		startedAt: time.Now(),
+		topicID:   os.Getenv("METRICS_TOPIC"),
+		sessionID: os.Getenv("SESSION_ID"),
+		topics:    make(map[string]*pubsub.Topic),
+	}
+}
+
+// recordTask counts a finished task and the usage its result reports
+func (m *droneMetrics) recordTask(res map[string]interface{}, err error) {
+	if err != nil {
+		m.tasksFailed.Add(1)
+		return
+	}
+	m.tasksCompleted.Add(1)
+	m.searchCalls.Add(int64(intArg(res, "search_calls", 0)))
+	m.llmTokens.Add(int64(intArg(res, "llm_tokens", 0)))
+}
+
+// useSession reports metrics to a task's session, when the task names a metrics topic
+func (m *droneMetrics) useSession(topicID, sessionID string) {
+	if topicID == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.topicID = topicID
+	m.sessionID = sessionID
+}
+
+// metricsSnapshot returns the drone's metrics as of now
+func (d *baseDrone) metricsSnapshot() types.DroneMetrics {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	m := d.metrics
+	m.mu.Lock()
+	sessionID := m.sessionID
+	m.mu.Unlock()
+	return types.DroneMetrics{
+		DroneID:         d.droneID,
+		SessionID:       sessionID,
+		RequestsHandled: m.requestsHandled.Load(),
+		TasksCompleted:  m.tasksCompleted.Load(),
+		TasksFailed:     m.tasksFailed.Load(),
+		SearchCalls:     m.searchCalls.Load(),
+		LLMTokens:       m.llmTokens.Load(),
+		MemoryBytes:     mem.Sys,
+		Goroutines:      runtime.NumGoroutine(),
+		UptimeSeconds:   time.Since(m.startedAt).Seconds(),
+		Timestamp:       time.Now(),
+	}
+}
+
+// serveMetrics writes the drone's metrics as JSON
+func (d *baseDrone) serveMetrics(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(d.metricsSnapshot()); err != nil {
+		log.Printf("Warning: Failed to encode metrics: %v", err)
+	}
+}
+
+// StartMetricsReporting publishes the drone's metrics to its session's
+// metrics topic every METRICS_INTERVAL_SEC until the drone is closed, so the
+// orchestrator can report the resources each drone used. Nothing is published
+// while the drone has no session.
+func (d *baseDrone) StartMetricsReporting() {
+	interval := metricsInterval()
+	m := d.metrics
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stop:
+				// Report the final counts before the drone goes away
+				d.publishMetrics(interval)
+				return
+			case <-ticker.C:
+				d.publishMetrics(interval)
+			}
+		}
+	}()
+	log.Printf("Drone %s reporting metrics every %v", d.droneID, interval)
+}
+
+// stopMetricsReporting stops publishing metrics, if it was started
+func (d *baseDrone) stopMetricsReporting() {
+	m := d.metrics
+	if m.stop == nil {
+		return
+	}
+	close(m.stop)
+	<-m.done
+	m.stop = nil
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, topic := range m.topics {
+		topic.Stop()
+	}
+}
+
+// publishMetrics publishes a metrics snapshot to the current session's topic, waiting at most one interval
+func (d *baseDrone) publishMetrics(interval time.Duration) {
+	m := d.metrics
+	m.mu.Lock()
+	topicID := m.topicID
+	var topic *pubsub.Topic
+	if topicID != "" {
+		var ok bool
+		if topic, ok = m.topics[topicID]; !ok {
+			topic = d.pubsubClient.Topic(topicID)
+			m.topics[topicID] = topic
+		}
+	}
+	m.mu.Unlock()
+	if topic == nil {
+		return
+	}
+
+	data, err := json.Marshal(d.metricsSnapshot())
+	if err != nil {
+		log.Printf("Warning: Failed to encode metrics: %v", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), interval)
+	defer cancel()
+	msg := &pubsub.Message{Data: data, Attributes: map[string]string{"drone_id": d.droneID}}
+	if _, err := topic.Publish(ctx, msg).Get(ctx); err != nil {
+		log.Printf("Warning: Failed to publish metrics to %s: %v", topicID, err)
+	}
+}
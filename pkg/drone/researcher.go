@@ -2,68 +2,71 @@ package drone
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
-	"os"
+	"strings"
 	"time"
 
-	"cloud.google.com/go/pubsub"
-	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/search"
+	"github.com/spawn-mcp/coordinator/pkg/types"
 )
 
 // ResearcherDrone represents a research-focused drone MCP server
 type ResearcherDrone struct {
-	droneID        string
-	coordinatorURL string
-	taskID         string
-	pubsubClient   *pubsub.Client
-	pubsubTopic    *pubsub.Topic
+	*baseDrone
+	// search finds sources on the web; nil when no search provider is configured
+	search search.Provider
+	// searchCache reuses results of repeated queries; nil disables caching
+	searchCache *searchCache
 }
 
 // NewResearcherDrone creates a new researcher drone MCP server
 func NewResearcherDrone() (*ResearcherDrone, error) {
-	ctx := context.Background()
-	// Get configuration from environment
-	droneID := os.Getenv("DRONE_ID")
-	if droneID == "" {
-		return nil, fmt.Errorf("DRONE_ID environment variable is required")
-	}
-
-	coordinatorURL := os.Getenv("COORDINATOR_URL")
-	taskID := os.Getenv("TASK_ID")
-
-	projectID := os.Getenv("GOOGLE_CLOUD_PROJECT")
-	if projectID == "" {
-		return nil, fmt.Errorf("GOOGLE_CLOUD_PROJECT environment variable is required")
-	}
-
-	topicID := os.Getenv("PUBSUB_TOPIC")
-	if topicID == "" {
-		return nil, fmt.Errorf("PUBSUB_TOPIC environment variable is required")
+	base, err := newBaseDrone(types.DroneTypeResearcher)
+	if err != nil {
+		return nil, err
 	}
+	d := &ResearcherDrone{baseDrone: base}
 
-	pubsubClient, err := pubsub.NewClient(ctx, projectID)
+	config := search.LoadConfig()
+	d.search, err = search.New(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create pubsub client: %w", err)
+		return nil, fmt.Errorf("failed to create search provider: %w", err)
 	}
-
-	topic := pubsubClient.Topic(topicID)
-
-	drone := &ResearcherDrone{
-		droneID:        droneID,
-		coordinatorURL: coordinatorURL,
-		taskID:         taskID,
-		pubsubClient:   pubsubClient,
-		pubsubTopic:    topic,
+	if d.search == nil {
+		log.Printf("Warning: %s is not set, research results are mocked", search.KeyEnv(config.Provider))
+	} else {
+		log.Printf("Drone %s searches with %s", d.droneID, config.Provider)
+		d.searchCache = newSearchCache(LoadSearchCacheConfig(), base.firestoreClient)
 	}
 
-	return drone, nil
+	base.tools = []Tool{
+		{
+			Name:        "conduct_research",
+			Description: "Research a subject, optionally within a time frame and from given sources",
+			Run: func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+				return d.ConductResearch(ctx, stringArg(args, "subject"), stringArg(args, "time_frame"), stringsArg(args, "sources"), intArg(args, "max_results", 5))
+			},
+		},
+		{
+			Name:        "analyze_historical_period",
+			Description: "Find the significant events between two years",
+			Run: func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+				return d.AnalyzeHistoricalPeriod(intArg(args, "start_year", 0), intArg(args, "end_year", 0), stringsArg(args, "regions"), stringsArg(args, "event_types"))
+			},
+		},
+	}
+	return d, nil
 }
 
-// ConductResearch performs research on a given topic
-func (d *ResearcherDrone) ConductResearch(topic, timeFrame string, sources []string, maxResults int) (map[string]interface{}, error) {
+// ConductResearch performs research on a given topic with the drone's search
+// provider. Sources that are domains, such as "arxiv.org", restrict the search
+// to those sites.
+func (d *ResearcherDrone) ConductResearch(ctx context.Context, topic, timeFrame string, sources []string, maxResults int) (map[string]interface{}, error) {
 	log.Printf("Drone %s conducting research on: %s", d.droneID, topic)
+	if d.search != nil {
+		return d.searchResearch(ctx, topic, timeFrame, sources, maxResults)
+	}
 
 	// Simulate research process
 	time.Sleep(2 * time.Second) // Simulate research time
@@ -95,6 +98,69 @@ func (d *ResearcherDrone) ConductResearch(topic, timeFrame string, sources []str
 	return results, nil
 }
 
+// searchResearch researches a topic from web search results. Each result is a
+// finding citing its page, in the shape the orchestrator's analysis reads.
+func (d *ResearcherDrone) searchResearch(ctx context.Context, topic, timeFrame string, sources []string, maxResults int) (map[string]interface{}, error) {
+	query := search.Query{Text: topic, MaxResults: maxResults}
+	if timeFrame != "" {
+		query.Text = fmt.Sprintf("%s (%s)", topic, timeFrame)
+	}
+	for _, source := range sources {
+		if strings.Contains(source, ".") {
+			query.Domains = append(query.Domains, source)
+		}
+	}
+
+	hits, cached, err := d.searchCache.search(ctx, d.search, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for %s: %w", topic, err)
+	}
+
+	findings := make([]map[string]interface{}, 0, len(hits))
+	cited := make([]map[string]interface{}, 0, len(hits))
+	confidence := 0.0
+	for _, hit := range hits {
+		findings = append(findings, map[string]interface{}{
+			"claim":       hit.Title,
+			"title":       hit.Title,
+			"description": hit.Snippet,
+			"relevance":   hit.Score,
+			"sources":     []string{hit.URL},
+		})
+		source := map[string]interface{}{"url": hit.URL, "title": hit.Title}
+		if hit.PublishedAt != "" {
+			source["published_at"] = hit.PublishedAt
+		}
+		cited = append(cited, source)
+		confidence += hit.Score
+	}
+	if len(hits) > 0 {
+		confidence /= float64(len(hits))
+	}
+
+	results := map[string]interface{}{
+		"topic":           topic,
+		"timeFrame":       timeFrame,
+		"findings":        findings,
+		"sources":         cited,
+		"summary":         fmt.Sprintf("Found %d sources on %s with %s", len(hits), topic, d.search.Name()),
+		"confidence":      confidence,
+		"search_provider": d.search.Name(),
+		"search_calls":    1,
+		"search_cached":   cached,
+		"droneId":         d.droneID,
+		"timestamp":       time.Now(),
+	}
+	if cached {
+		results["search_calls"] = 0
+	}
+	// Session budgets price Exa calls
+	if d.search.Name() == search.ProviderExa {
+		results["exa_calls"] = results["search_calls"]
+	}
+	return results, nil
+}
+
 // AnalyzeHistoricalPeriod analyzes events in a specific historical period
 func (d *ResearcherDrone) AnalyzeHistoricalPeriod(startYear, endYear int, regions, eventTypes []string) (map[string]interface{}, error) {
 	log.Printf("Drone %s analyzing period %d-%d", d.droneID, startYear, endYear)
@@ -148,40 +214,3 @@ func (d *ResearcherDrone) Serve() error {
 	// For now, just keep running
 	select {}
 }
-
-// Close closes the drone and cleans up resources
-func (d *ResearcherDrone) Close() error {
-	if d.pubsubClient != nil {
-		d.pubsubClient.Close()
-	}
-	return nil
-}
-
-// publishResult publishes the research result to the Pub/Sub topic.
-func (d *ResearcherDrone) publishResult(ctx context.Context, resultData map[string]interface{}) error {
-	// We need to wrap the raw result data in the DroneResult schema
-	// to be consistent with what the orchestrator expects.
-	result := schemas.DroneResult{
-		DroneID:        d.droneID,
-		Status:         "success", // Assuming success if this method is called
-		Data:           resultData,
-		CompletedAt:    time.Now(),
-		ProcessingTime: 0, // This can be properly calculated in the http worker
-	}
-
-	jsonData, err := json.Marshal(result)
-	if err != nil {
-		return fmt.Errorf("failed to marshal result: %w", err)
-	}
-
-	msg := &pubsub.Message{
-		Data: jsonData,
-	}
-
-	if _, err := d.pubsubTopic.Publish(ctx, msg).Get(ctx); err != nil {
-		return fmt.Errorf("failed to publish result: %w", err)
-	}
-
-	log.Printf("Drone %s published result to topic %s", d.droneID, d.pubsubTopic.String())
-	return nil
-}
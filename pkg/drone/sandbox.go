@@ -0,0 +1,81 @@
+package drone
+
+import (
+	"encoding/base64"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"unicode/utf8"
+)
+
+// artifactsDirName is the directory, inside a run's scratch directory, whose
+// files are returned with the run's result. Programs find it in $ARTIFACTS_DIR.
+const artifactsDirName = "artifacts"
+
+// SandboxConfig constrains the programs coder drones run
+type SandboxConfig struct {
+	// AllowNetwork lets tasks that ask for it reach the network; programs have
+	// no network otherwise
+	AllowNetwork bool
+	// MemoryMB caps a program's address space; 0 leaves only the container's memory limit
+	MemoryMB int
+	// MaxFileMB caps the size of any file a program writes
+	MaxFileMB int
+	// MaxArtifactBytes caps the total size of the artifacts returned with a result
+	MaxArtifactBytes int
+}
+
+// LoadSandboxConfig reads the code sandbox limits from the environment
+func LoadSandboxConfig() SandboxConfig {
+	config := SandboxConfig{MaxFileMB: 64, MaxArtifactBytes: 1 << 20}
+	config.AllowNetwork, _ = strconv.ParseBool(os.Getenv("CODE_EXEC_ALLOW_NETWORK"))
+	if n, err := strconv.Atoi(os.Getenv("CODE_EXEC_MEMORY_MB")); err == nil && n >= 0 {
+		config.MemoryMB = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("CODE_EXEC_MAX_FILE_MB")); err == nil && n > 0 {
+		config.MaxFileMB = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("CODE_EXEC_MAX_ARTIFACT_KB")); err == nil && n >= 0 {
+		config.MaxArtifactBytes = n << 10
+	}
+	return config
+}
+
+// collectArtifacts reads the files a program left in dir, text as is and other
+// files base64-encoded. Files that do not fit in limit are listed without content.
+func collectArtifacts(dir string, limit int) []map[string]interface{} {
+	var artifacts []map[string]interface{}
+	remaining := limit
+	filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		name, _ := filepath.Rel(dir, path)
+		artifact := map[string]interface{}{"name": name, "size": info.Size()}
+		artifacts = append(artifacts, artifact)
+		if info.Size() > int64(remaining) {
+			artifact["omitted"] = true
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			artifact["omitted"] = true
+			return nil
+		}
+		remaining -= len(content)
+		if utf8.Valid(content) {
+			artifact["encoding"] = "utf-8"
+			artifact["content"] = string(content)
+		} else {
+			artifact["encoding"] = "base64"
+			artifact["content"] = base64.StdEncoding.EncodeToString(content)
+		}
+		return nil
+	})
+	return artifacts
+}
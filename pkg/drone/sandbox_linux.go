@@ -0,0 +1,119 @@
+//go:build linux
+
+package drone
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// isolate runs cmd in its own process group, so a stopped program's children
+// stop with it, and in new user, PID and mount namespaces, where the sandbox
+// shim mounts a /proc that shows only the program's own processes, so it
+// cannot read the drone's environment or command line. Without network it
+// also gets a new network namespace that has no interfaces but loopback.
+func isolate(cmd *exec.Cmd, network bool) error {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid:    true,
+		Pdeathsig:  syscall.SIGKILL,
+		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWPID | syscall.CLONE_NEWNS,
+		// The program keeps the drone's IDs inside its namespace
+		UidMappings:                []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}},
+		GidMappings:                []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}},
+		GidMappingsEnableSetgroups: false,
+	}
+	if !network {
+		cmd.SysProcAttr.Cloneflags |= syscall.CLONE_NEWNET
+	}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	return nil
+}
+
+// sandboxShimEnv holds the resource limits of a sandbox shim: the drone's own
+// binary, started in place of a program, which sets the limits, mounts a
+// private /proc when isolate gave it a PID namespace, and then execs the
+// program, so the program never runs without them
+const sandboxShimEnv = "DRONE_SANDBOX_RLIMITS"
+
+func init() {
+	if limits, ok := os.LookupEnv(sandboxShimEnv); ok {
+		runSandboxShim(limits)
+	}
+}
+
+// limit makes cmd start through the sandbox shim, which caps the program's CPU
+// time, file sizes and, if configured, memory before it runs. It must be
+// called before cmd is started.
+func limit(cmd *exec.Cmd, config SandboxConfig, timeout time.Duration) error {
+	if cmd.Err != nil {
+		// Start reports the failed lookup of the program
+		return nil
+	}
+	cpu := uint64(timeout.Seconds()) + 1
+	limits := map[int]uint64{
+		unix.RLIMIT_CPU:   cpu,
+		unix.RLIMIT_FSIZE: uint64(config.MaxFileMB) << 20,
+		unix.RLIMIT_CORE:  0,
+	}
+	if config.MemoryMB > 0 {
+		limits[unix.RLIMIT_AS] = uint64(config.MemoryMB) << 20
+	}
+	encoded := make([]string, 0, len(limits))
+	for resource, value := range limits {
+		encoded = append(encoded, fmt.Sprintf("%d=%d", resource, value))
+	}
+
+	cmd.Args = append([]string{cmd.Args[0], cmd.Path}, cmd.Args...)
+	cmd.Path = "/proc/self/exe"
+	cmd.Env = append(cmd.Env, sandboxShimEnv+"="+strings.Join(encoded, ","))
+	return nil
+}
+
+// runSandboxShim sets limits, as encoded by limit, and execs the program named
+// by the arguments, or exits with status 126 if either fails. As the first
+// process of a new PID namespace it first replaces /proc, which still shows
+// the drone's processes, with one of its own namespace.
+func runSandboxShim(limits string) {
+	fail := func(err error) {
+		fmt.Fprintf(os.Stderr, "sandbox: %v\n", err)
+		os.Exit(126)
+	}
+	if len(os.Args) < 3 {
+		fail(fmt.Errorf("no program to run"))
+	}
+	if os.Getpid() == 1 {
+		// Keep the new /proc out of the drone's mount namespace
+		if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+			fail(fmt.Errorf("failed to make mounts private: %w", err))
+		}
+		if err := unix.Mount("proc", "/proc", "proc", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, ""); err != nil {
+			fail(fmt.Errorf("failed to mount /proc: %w", err))
+		}
+	}
+	for _, entry := range strings.Split(limits, ",") {
+		var resource int
+		var value uint64
+		if _, err := fmt.Sscanf(entry, "%d=%d", &resource, &value); err != nil {
+			fail(fmt.Errorf("invalid resource limit %q", entry))
+		}
+		if err := unix.Setrlimit(resource, &unix.Rlimit{Cur: value, Max: value}); err != nil {
+			fail(fmt.Errorf("failed to set resource limit %d: %w", resource, err))
+		}
+	}
+
+	env := make([]string, 0, len(os.Environ()))
+	for _, variable := range os.Environ() {
+		if !strings.HasPrefix(variable, sandboxShimEnv+"=") {
+			env = append(env, variable)
+		}
+	}
+	fail(syscall.Exec(os.Args[1], os.Args[2:], env))
+}
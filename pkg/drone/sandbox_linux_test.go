@@ -0,0 +1,92 @@
+//go:build linux
+
+package drone
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// runSandboxed runs a shell script the way ExecuteCode runs programs, skipping
+// the test where the kernel does not allow the sandbox's namespaces
+func runSandboxed(t *testing.T, script string, network bool) string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", script)
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH")}
+	var output bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &output, &output
+	if err := isolate(cmd, network); err != nil {
+		t.Fatalf("isolate() returned an error: %v", err)
+	}
+	if err := limit(cmd, SandboxConfig{MaxFileMB: 1}, 5*time.Second); err != nil {
+		t.Fatalf("limit() returned an error: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		if errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOSYS) {
+			t.Skipf("user namespaces are not available: %v", err)
+		}
+		t.Fatalf("failed to start the sandboxed program: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("sandboxed program failed: %v\n%s", err, output.String())
+	}
+	return output.String()
+}
+
+func TestSandboxHidesDroneEnvironment(t *testing.T) {
+	// /proc shows the environment a process started with, so look for the
+	// test's own; the shell sets the variables skipped here for itself
+	environ, err := os.ReadFile("/proc/self/environ")
+	if err != nil {
+		t.Skipf("cannot read the test's environment: %v", err)
+	}
+	var secrets []string
+	for _, variable := range strings.Split(string(environ), "\x00") {
+		switch name, _, _ := strings.Cut(variable, "="); name {
+		case "", "PATH", "PWD", "OLDPWD", "SHLVL", "_":
+		default:
+			secrets = append(secrets, variable)
+		}
+	}
+	if len(secrets) == 0 {
+		t.Skip("the test has no environment to look for")
+	}
+
+	for _, network := range []bool{false, true} {
+		t.Run(fmt.Sprintf("network=%v", network), func(t *testing.T) {
+			script := fmt.Sprintf(`cat /proc/%d/environ /proc/%d/cmdline 2>/dev/null; cat /proc/[0-9]*/environ 2>/dev/null; env; echo; echo pid=$$`, os.Getpid(), os.Getpid())
+			output := runSandboxed(t, script, network)
+			for _, secret := range secrets {
+				if strings.Contains(output, secret) {
+					t.Fatalf("sandboxed program read the drone's environment:\n%s", output)
+				}
+			}
+			if strings.Contains(output, sandboxShimEnv) {
+				t.Errorf("sandboxed program sees the shim's variable:\n%s", output)
+			}
+			// The program is the first process of its own PID namespace
+			if !strings.Contains(output, "pid=1\n") {
+				t.Errorf("sandboxed program is not PID 1 of a new namespace:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestSandboxAppliesLimits(t *testing.T) {
+	output := runSandboxed(t, "ulimit -f; ulimit -c; ulimit -t", false)
+	// ulimit -f counts 1024-byte blocks in most shells and 512-byte ones in POSIX mode
+	fields := strings.Fields(output)
+	if len(fields) != 3 || (fields[0] != "1024" && fields[0] != "2048") || fields[1] != "0" || fields[2] != "6" {
+		t.Errorf("limits inside the sandbox = %q, want file size 1 MiB, no core dumps and 6s of CPU", output)
+	}
+}
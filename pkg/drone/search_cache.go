@@ -0,0 +1,202 @@
+package drone
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/spawn-mcp/coordinator/pkg/search"
+)
+
+// searchCacheCollection holds search results shared by all drones
+const searchCacheCollection = "search_cache"
+
+// SearchCacheConfig bounds the researcher's cache of search results
+type SearchCacheConfig struct {
+	// TTL is how long results are reused
+	TTL time.Duration
+	// Size is how many queries the drone keeps in memory
+	Size int
+	// Firestore shares results between drones when a Firestore client is available
+	Firestore bool
+}
+
+// LoadSearchCacheConfig reads the search cache configuration from the environment
+func LoadSearchCacheConfig() SearchCacheConfig {
+	config := SearchCacheConfig{TTL: time.Hour, Size: 256, Firestore: true}
+	if ttl, err := time.ParseDuration(os.Getenv("SEARCH_CACHE_TTL")); err == nil && ttl >= 0 {
+		config.TTL = ttl
+	}
+	if n, err := strconv.Atoi(os.Getenv("SEARCH_CACHE_SIZE")); err == nil && n >= 0 {
+		config.Size = n
+	}
+	if enabled, err := strconv.ParseBool(os.Getenv("SEARCH_CACHE_FIRESTORE")); err == nil {
+		config.Firestore = enabled
+	}
+	return config
+}
+
+// searchCache remembers search results by query, so overlapping sub-queries
+// do not repeat the same external API call. Results are kept in memory, and
+// in Firestore for the session's other drones.
+type searchCache struct {
+	ttl time.Duration
+	// firestoreClient shares results between drones; nil keeps them in memory only
+	firestoreClient *firestore.Client
+
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	// order has the most recently used entry at the front
+	order *list.List
+}
+
+// cachedSearch is one query's results, as kept in memory and in Firestore
+type cachedSearch struct {
+	Key       string          `firestore:"key"`
+	Provider  string          `firestore:"provider"`
+	Query     string          `firestore:"query"`
+	Results   []search.Result `firestore:"results"`
+	CreatedAt time.Time       `firestore:"created_at"`
+	// ExpiresAt can drive a Firestore TTL policy on the collection
+	ExpiresAt time.Time `firestore:"expires_at"`
+}
+
+// newSearchCache creates a search cache, or returns nil when caching is disabled
+func newSearchCache(config SearchCacheConfig, firestoreClient *firestore.Client) *searchCache {
+	if config.TTL == 0 || (config.Size == 0 && (!config.Firestore || firestoreClient == nil)) {
+		return nil
+	}
+	cache := &searchCache{
+		ttl:     config.TTL,
+		size:    config.Size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+	if config.Firestore {
+		cache.firestoreClient = firestoreClient
+	}
+	return cache
+}
+
+// searchCacheKey identifies a query to a provider, ignoring case, spacing and domain order
+func searchCacheKey(provider string, query search.Query) string {
+	domains := append([]string(nil), query.Domains...)
+	sort.Strings(domains)
+	text := strings.Join(strings.Fields(strings.ToLower(query.Text)), " ")
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d\x00%s", provider, text, query.MaxResults, strings.Join(domains, ","))))
+	return hex.EncodeToString(sum[:])
+}
+
+// search returns the cached results of query, or searches with provider and
+// caches what it finds. cached reports whether no external call was made.
+func (c *searchCache) search(ctx context.Context, provider search.Provider, query search.Query) (results []search.Result, cached bool, err error) {
+	if c == nil {
+		results, err = provider.Search(ctx, query)
+		return results, false, err
+	}
+
+	key := searchCacheKey(provider.Name(), query)
+	if entry, ok := c.memory(key); ok {
+		return entry.Results, true, nil
+	}
+	if entry, ok := c.shared(ctx, key); ok {
+		c.remember(entry)
+		return entry.Results, true, nil
+	}
+
+	results, err = provider.Search(ctx, query)
+	if err != nil {
+		return nil, false, err
+	}
+	now := time.Now()
+	entry := &cachedSearch{
+		Key:       key,
+		Provider:  provider.Name(),
+		Query:     query.Text,
+		Results:   results,
+		CreatedAt: now,
+		ExpiresAt: now.Add(c.ttl),
+	}
+	c.remember(entry)
+	c.share(ctx, entry)
+	return results, false, nil
+}
+
+// memory returns an unexpired entry from memory
+func (c *searchCache) memory(key string) (*cachedSearch, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*cachedSearch)
+	if time.Now().After(entry.ExpiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry, true
+}
+
+// remember keeps an entry in memory, evicting the least recently used beyond the cache's size
+func (c *searchCache) remember(entry *cachedSearch) {
+	if c.size == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[entry.Key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[entry.Key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedSearch).Key)
+	}
+}
+
+// shared returns an unexpired entry another drone stored in Firestore
+func (c *searchCache) shared(ctx context.Context, key string) (*cachedSearch, bool) {
+	if c.firestoreClient == nil {
+		return nil, false
+	}
+	doc, err := c.firestoreClient.Collection(searchCacheCollection).Doc(key).Get(ctx)
+	if err != nil {
+		return nil, false
+	}
+	var entry cachedSearch
+	if err := doc.DataTo(&entry); err != nil {
+		log.Printf("Warning: Failed to read cached search %s: %v", key, err)
+		return nil, false
+	}
+	if time.Now().After(entry.ExpiresAt) {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// share stores an entry in Firestore for other drones. Failures only cost a repeated search.
+func (c *searchCache) share(ctx context.Context, entry *cachedSearch) {
+	if c.firestoreClient == nil {
+		return
+	}
+	if _, err := c.firestoreClient.Collection(searchCacheCollection).Doc(entry.Key).Set(ctx, entry); err != nil {
+		log.Printf("Warning: Failed to cache search results: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package drone
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// defaultOutline is the sections of a document written without an outline
+var defaultOutline = []string{"Introduction", "Background", "Analysis", "Conclusion"}
+
+// WriterDrone represents a drone that writes long-form documents
+type WriterDrone struct {
+	*baseDrone
+}
+
+// NewWriterDrone creates a new writer drone MCP server
+func NewWriterDrone() (*WriterDrone, error) {
+	base, err := newBaseDrone(types.DroneTypeWriter)
+	if err != nil {
+		return nil, err
+	}
+	d := &WriterDrone{baseDrone: base}
+	base.tools = []Tool{
+		{
+			Name:        "write_document",
+			Description: "Write a long-form Markdown document on a subject, following an outline and drawing on context",
+			Run: func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+				return d.WriteDocument(stringArg(args, "subject"), stringsArg(args, "outline"), stringArg(args, "context"))
+			},
+		},
+		{
+			Name:        "summarize",
+			Description: "Shorten a text to its first sentences",
+			Run: func(ctx context.Context, args map[string]interface{}) (map[string]interface{}, error) {
+				return d.Summarize(stringArg(args, "text"), intArg(args, "max_sentences", 3))
+			},
+		},
+	}
+	return d, nil
+}
+
+// WriteDocument writes a Markdown document with a section per outline entry
+func (d *WriterDrone) WriteDocument(subject string, outline []string, context string) (map[string]interface{}, error) {
+	if subject == "" {
+		return nil, fmt.Errorf("subject is required")
+	}
+	if len(outline) == 0 {
+		outline = defaultOutline
+	}
+
+	var doc strings.Builder
+	fmt.Fprintf(&doc, "# %s\n", subject)
+	sections := make([]map[string]interface{}, 0, len(outline))
+	for i, heading := range outline {
+		content := fmt.Sprintf("This section covers %s as it relates to %s.", strings.ToLower(heading), subject)
+		// Earlier findings ground the opening section
+		if i == 0 && context != "" {
+			content += "\n\n" + context
+		}
+		fmt.Fprintf(&doc, "\n## %s\n\n%s\n", heading, content)
+		sections = append(sections, map[string]interface{}{"heading": heading, "content": content})
+	}
+
+	markdown := doc.String()
+	return map[string]interface{}{
+		"subject":    subject,
+		"document":   markdown,
+		"sections":   sections,
+		"word_count": len(strings.Fields(markdown)),
+		"summary":    fmt.Sprintf("Wrote a %d-section document on %s", len(sections), subject),
+		"droneId":    d.droneID,
+		"timestamp":  time.Now(),
+	}, nil
+}
+
+// Summarize keeps the first maxSentences sentences of text
+func (d *WriterDrone) Summarize(text string, maxSentences int) (map[string]interface{}, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("text is required")
+	}
+	if maxSentences < 1 {
+		maxSentences = 1
+	}
+
+	var sentences []string
+	start := 0
+	for i, r := range text {
+		if r == '.' || r == '!' || r == '?' {
+			if sentence := strings.TrimSpace(text[start : i+1]); sentence != "" {
+				sentences = append(sentences, sentence)
+			}
+			start = i + 1
+		}
+	}
+	if rest := strings.TrimSpace(text[start:]); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	if len(sentences) > maxSentences {
+		sentences = sentences[:maxSentences]
+	}
+
+	return map[string]interface{}{
+		"summary":   strings.Join(sentences, " "),
+		"sentences": len(sentences),
+		"droneId":   d.droneID,
+		"timestamp": time.Now(),
+	}, nil
+}
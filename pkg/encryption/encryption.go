@@ -0,0 +1,211 @@
+// Package encryption protects research data with customer-managed Cloud KMS
+// keys: objects written to Cloud Storage name a CMEK key, and sensitive fields
+// stored in Firestore are envelope-encrypted with a data key that KMS wraps.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	cloudkms "google.golang.org/api/cloudkms/v1"
+)
+
+// keyNamePattern matches the resource name of a Cloud KMS crypto key
+var keyNamePattern = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+$`)
+
+// ValidKeyName reports whether name is the resource name of a Cloud KMS crypto key
+func ValidKeyName(name string) bool {
+	return keyNamePattern.MatchString(name)
+}
+
+// Config names the customer-managed keys research data is encrypted with
+type Config struct {
+	// StorageKey is the CMEK key of objects written to Cloud Storage; empty uses the bucket's default
+	StorageKey string
+	// FieldKey encrypts sensitive fields stored in Firestore; empty stores them in the clear
+	FieldKey string
+}
+
+// LoadConfig reads the encryption keys from the environment
+func LoadConfig() Config {
+	return Config{
+		StorageKey: os.Getenv("STORAGE_KMS_KEY"),
+		FieldKey:   os.Getenv("FIELD_KMS_KEY"),
+	}
+}
+
+// Envelope is a value encrypted with AES-256-GCM under a data key, which is
+// itself encrypted with a Cloud KMS key
+type Envelope struct {
+	// KeyName is the KMS key that wrapped the data key
+	KeyName    string `json:"key_name" firestore:"key_name"`
+	WrappedKey []byte `json:"wrapped_key" firestore:"wrapped_key"`
+	Nonce      []byte `json:"nonce" firestore:"nonce"`
+	Ciphertext []byte `json:"ciphertext" firestore:"ciphertext"`
+}
+
+// FieldEncryptor seals values with a data key it generates once and has KMS
+// wrap, so sealing costs one KMS call per process. Opened data keys are
+// cached by their wrapped form.
+type FieldEncryptor struct {
+	keyName string
+
+	mu      sync.Mutex
+	kms     *cloudkms.Service
+	dataKey []byte
+	wrapped []byte
+	opened  map[string][]byte
+}
+
+// NewFieldEncryptor creates an encryptor sealing with keyName. With no key it
+// seals nothing, but can still open values sealed earlier.
+func NewFieldEncryptor(keyName string) (*FieldEncryptor, error) {
+	if keyName != "" && !ValidKeyName(keyName) {
+		return nil, fmt.Errorf("%q is not a Cloud KMS key name (projects/P/locations/L/keyRings/R/cryptoKeys/K)", keyName)
+	}
+	return &FieldEncryptor{keyName: keyName, opened: make(map[string][]byte)}, nil
+}
+
+// Enabled reports whether the encryptor seals values
+func (e *FieldEncryptor) Enabled() bool {
+	return e != nil && e.keyName != ""
+}
+
+// Seal encrypts the JSON of value
+func (e *FieldEncryptor) Seal(ctx context.Context, value interface{}) (*Envelope, error) {
+	if !e.Enabled() {
+		return nil, fmt.Errorf("no field encryption key is configured")
+	}
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value: %w", err)
+	}
+	dataKey, wrapped, err := e.currentKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return &Envelope{
+		KeyName:    e.keyName,
+		WrappedKey: wrapped,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, []byte(e.keyName)),
+	}, nil
+}
+
+// Open decrypts an envelope into the value it was sealed from, using the KMS
+// key that sealed it
+func (e *FieldEncryptor) Open(ctx context.Context, envelope *Envelope, value interface{}) error {
+	dataKey, err := e.openKey(ctx, envelope)
+	if err != nil {
+		return err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+	plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Ciphertext, []byte(envelope.KeyName))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	if err := json.Unmarshal(plaintext, value); err != nil {
+		return fmt.Errorf("failed to decode value: %w", err)
+	}
+	return nil
+}
+
+// currentKey returns the data key values are sealed with and its wrapped
+// form, generating and wrapping it on first use
+func (e *FieldEncryptor) currentKey(ctx context.Context) ([]byte, []byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.dataKey != nil {
+		return e.dataKey, e.wrapped, nil
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	service, err := e.service(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := service.Projects.Locations.KeyRings.CryptoKeys.Encrypt(e.keyName, &cloudkms.EncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(dataKey),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to wrap data key with %s: %w", e.keyName, err)
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(resp.Ciphertext)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read wrapped data key: %w", err)
+	}
+	e.dataKey, e.wrapped = dataKey, wrapped
+	e.opened[string(wrapped)] = dataKey
+	return dataKey, wrapped, nil
+}
+
+// openKey returns the data key of an envelope, having KMS unwrap it unless it was opened before
+func (e *FieldEncryptor) openKey(ctx context.Context, envelope *Envelope) ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if dataKey, ok := e.opened[string(envelope.WrappedKey)]; ok {
+		return dataKey, nil
+	}
+
+	service, err := e.service(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := service.Projects.Locations.KeyRings.CryptoKeys.Decrypt(envelope.KeyName, &cloudkms.DecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(envelope.WrappedKey),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key with %s: %w", envelope.KeyName, err)
+	}
+	dataKey, err := base64.StdEncoding.DecodeString(resp.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read unwrapped data key: %w", err)
+	}
+	e.opened[string(envelope.WrappedKey)] = dataKey
+	return dataKey, nil
+}
+
+// service returns the Cloud KMS client, creating it on first use. Callers hold mu.
+func (e *FieldEncryptor) service(ctx context.Context) (*cloudkms.Service, error) {
+	if e.kms != nil {
+		return e.kms, nil
+	}
+	service, err := cloudkms.NewService(context.WithoutCancel(ctx), gcp.EmulatorOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud KMS client: %w", err)
+	}
+	e.kms = service
+	return service, nil
+}
+
+// newAEAD returns AES-GCM with a data key
+func newAEAD(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
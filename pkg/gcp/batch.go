@@ -0,0 +1,97 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"cloud.google.com/go/firestore"
+)
+
+// pendingWrite is a document write waiting for the next flush
+type pendingWrite struct {
+	collection string
+	docID      string
+	data       interface{}
+}
+
+// BatchWriter collects Firestore writes, such as the results and drone updates of
+// one session, and sends them together with a BulkWriter. Writes to the same
+// document before a flush are coalesced into the last one.
+type BatchWriter struct {
+	client *firestore.Client
+
+	mu      sync.Mutex
+	pending map[string]*pendingWrite
+	order   []string
+}
+
+// NewBatchWriter creates a batch writer for the given Firestore client
+func NewBatchWriter(client *firestore.Client) *BatchWriter {
+	return &BatchWriter{
+		client:  client,
+		pending: make(map[string]*pendingWrite),
+	}
+}
+
+// Set queues data to be stored as collection/docID at the next flush
+func (b *BatchWriter) Set(collection, docID string, data interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	path := collection + "/" + docID
+	if write, ok := b.pending[path]; ok {
+		write.data = data
+		return
+	}
+	b.pending[path] = &pendingWrite{collection: collection, docID: docID, data: data}
+	b.order = append(b.order, path)
+}
+
+// Pending returns the number of documents waiting to be written
+func (b *BatchWriter) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.order)
+}
+
+// Flush writes all queued documents and waits for them to complete. Writes are
+// not atomic: the error lists every document that failed, while the rest are stored.
+func (b *BatchWriter) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	writes := make([]*pendingWrite, 0, len(b.order))
+	for _, path := range b.order {
+		writes = append(writes, b.pending[path])
+	}
+	b.pending = make(map[string]*pendingWrite)
+	b.order = nil
+	b.mu.Unlock()
+
+	if len(writes) == 0 {
+		return nil
+	}
+
+	bulk := b.client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, len(writes))
+	var errs []error
+	for i, write := range writes {
+		job, err := bulk.Set(b.client.Collection(write.collection).Doc(write.docID), write.data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to queue %s/%s: %w", write.collection, write.docID, err))
+			continue
+		}
+		jobs[i] = job
+	}
+	bulk.End()
+
+	for i, job := range jobs {
+		if job == nil {
+			continue
+		}
+		if _, err := job.Results(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write %s/%s: %w", writes[i].collection, writes[i].docID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -10,6 +10,7 @@ import (
 	"cloud.google.com/go/pubsub"
 	run "cloud.google.com/go/run/apiv2"
 	runpb "cloud.google.com/go/run/apiv2/runpb"
+	"github.com/spawn-mcp/coordinator/pkg/types"
 	"google.golang.org/api/option"
 	"google.golang.org/protobuf/types/known/durationpb"
 )
@@ -23,8 +24,13 @@ type Client struct {
 	PubSubClient    *pubsub.Client
 }
 
-// NewClient creates a new GCP client with all necessary services
+// NewClient creates a new GCP client with all necessary services. Pass the options
+// from CredentialConfig.ClientOptions to act as an identity other than ADC, e.g. per
+// tenant. In emulator mode Firestore and Pub/Sub use the local emulators and no
+// credentials are required.
 func NewClient(ctx context.Context, projectID, region string, opts ...option.ClientOption) (*Client, error) {
+	opts = append(opts, EmulatorOptions()...)
+
 	// Initialize Cloud Run client
 	runClient, err := run.NewServicesClient(ctx, opts...)
 	if err != nil {
@@ -79,7 +85,10 @@ func (c *Client) Close() error {
 }
 
 // CreateCloudRunService creates a new Cloud Run service for a drone
-func (c *Client) CreateCloudRunService(ctx context.Context, serviceName, imageURI string, env map[string]string) (*runpb.Service, error) {
+// The service runs as serviceAccount, which should come from DroneServiceAccount,
+// with VPC access and ingress set from network. Non-zero scaling fields override
+// the default of 0-10 instances.
+func (c *Client) CreateCloudRunService(ctx context.Context, serviceName, imageURI, serviceAccount string, env, labels map[string]string, network NetworkConfig, scaling types.ScalingConfig) (*runpb.Service, error) {
 	log.Printf("Creating Cloud Run service: %s with image: %s", serviceName, imageURI)
 
 	// Convert env map to EnvVar slice with correct structure
@@ -98,6 +107,7 @@ func (c *Client) CreateCloudRunService(ctx context.Context, serviceName, imageUR
 		Parent:    fmt.Sprintf("projects/%s/locations/%s", c.ProjectID, c.Region),
 		ServiceId: serviceName,
 		Service: &runpb.Service{
+			Labels: labels,
 			Template: &runpb.RevisionTemplate{
 				Containers: []*runpb.Container{
 					{
@@ -121,14 +131,20 @@ func (c *Client) CreateCloudRunService(ctx context.Context, serviceName, imageUR
 					MinInstanceCount: 0,
 					MaxInstanceCount: 10,
 				},
-				ServiceAccount: fmt.Sprintf("drone-service-account@%s.iam.gserviceaccount.com", c.ProjectID),
+				ServiceAccount: serviceAccount,
 				Timeout:        durationpb.New(5 * time.Minute), // 5 minute timeout
 			},
 			// Configure IAM policy for service-to-service authentication
 			// This allows the coordinator to invoke the drone service
-			Ingress: runpb.IngressTraffic_INGRESS_TRAFFIC_ALL, // Allow all traffic for now
+			Ingress: runpb.IngressTraffic_INGRESS_TRAFFIC_ALL, // Allow all traffic unless the network config restricts it
 		},
 	}
+	if err := ApplyNetworkConfig(req.Service, c.ProjectID, c.Region, network); err != nil {
+		return nil, fmt.Errorf("failed to configure service network: %w", err)
+	}
+	if err := ApplyScaling(req.Service, scaling); err != nil {
+		return nil, fmt.Errorf("failed to configure service scaling: %w", err)
+	}
 
 	// Create the service (returns long-running operation)
 	op, err := c.RunClient.CreateService(ctx, req)
@@ -169,6 +185,19 @@ func (c *Client) DeleteCloudRunService(ctx context.Context, serviceName string)
 	return nil
 }
 
+// GetCloudRunService retrieves a Cloud Run service
+func (c *Client) GetCloudRunService(ctx context.Context, serviceName string) (*runpb.Service, error) {
+	req := &runpb.GetServiceRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/services/%s", c.ProjectID, c.Region, serviceName),
+	}
+
+	service, err := c.RunClient.GetService(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	return service, nil
+}
+
 // GetServiceURL retrieves the URL for a Cloud Run service
 func (c *Client) GetServiceURL(ctx context.Context, serviceName string) (string, error) {
 	req := &runpb.GetServiceRequest{
@@ -253,41 +282,157 @@ func (c *Client) GetDocument(ctx context.Context, collection, docID string, dest
 	return nil
 }
 
-// PublishMessage publishes a message to a Pub/Sub topic
-func (c *Client) PublishMessage(ctx context.Context, topicName string, data []byte, attributes map[string]string) error {
-	topic := c.PubSubClient.Topic(topicName)
-
-	// Check if topic exists, create if it doesn't
-	exists, err := topic.Exists(ctx)
+// ListDocuments retrieves every document in a Firestore collection
+func (c *Client) ListDocuments(ctx context.Context, collection string) ([]*firestore.DocumentSnapshot, error) {
+	docs, err := c.FirestoreClient.Collection(collection).Documents(ctx).GetAll()
 	if err != nil {
-		return fmt.Errorf("failed to check topic existence: %w", err)
+		return nil, fmt.Errorf("failed to list documents: %w", err)
 	}
+	return docs, nil
+}
 
-	if !exists {
-		_, err = c.PubSubClient.CreateTopic(ctx, topicName)
+// QueryFilter is a single field condition in a Firestore query, e.g. {"Status", "==", "failed"}
+type QueryFilter struct {
+	Path  string
+	Op    string
+	Value interface{}
+}
+
+// DocumentQuery selects, orders and pages documents in a collection
+type DocumentQuery struct {
+	Filters    []QueryFilter
+	OrderBy    string
+	Descending bool
+	// Limit caps the number of documents returned; 0 returns all matches
+	Limit  int
+	Offset int
+	// StartAfter is the ID of the last document of the previous page
+	StartAfter string
+}
+
+// QueryDocuments runs a filtered, ordered and paginated query against a Firestore collection.
+// For large collections prefer StartAfter over Offset, since skipped documents are still read.
+func (c *Client) QueryDocuments(ctx context.Context, collection string, q DocumentQuery) ([]*firestore.DocumentSnapshot, error) {
+	ref := c.FirestoreClient.Collection(collection)
+	query := ref.Query
+	for _, filter := range q.Filters {
+		query = query.Where(filter.Path, filter.Op, filter.Value)
+	}
+	if q.OrderBy != "" {
+		direction := firestore.Asc
+		if q.Descending {
+			direction = firestore.Desc
+		}
+		query = query.OrderBy(q.OrderBy, direction)
+	}
+	if q.StartAfter != "" {
+		cursor, err := ref.Doc(q.StartAfter).Get(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to create topic: %w", err)
+			return nil, fmt.Errorf("failed to load cursor document %s: %w", q.StartAfter, err)
 		}
+		query = query.StartAfter(cursor)
+	}
+	if q.Offset > 0 {
+		query = query.Offset(q.Offset)
+	}
+	if q.Limit > 0 {
+		query = query.Limit(q.Limit)
+	}
+
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query documents: %w", err)
+	}
+	return docs, nil
+}
+
+// NextCursor returns the StartAfter value for the page following docs, or ""
+// when docs is shorter than limit and so is the last page
+func NextCursor(docs []*firestore.DocumentSnapshot, limit int) string {
+	if limit <= 0 || len(docs) < limit {
+		return ""
+	}
+	return docs[len(docs)-1].Ref.ID
+}
+
+// DeleteDocument deletes a document from Firestore
+func (c *Client) DeleteDocument(ctx context.Context, collection, docID string) error {
+	_, err := c.FirestoreClient.Collection(collection).Doc(docID).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	return nil
+}
+
+// PublishMessage publishes a message to a Pub/Sub topic. Messages with the same
+// non-empty orderingKey are delivered in publish order to subscriptions with
+// message ordering enabled.
+func (c *Client) PublishMessage(ctx context.Context, topicName string, data []byte, attributes map[string]string, orderingKey string) error {
+	topic, err := c.ensureTopic(ctx, topicName)
+	if err != nil {
+		return err
 	}
 
 	msg := &pubsub.Message{
-		Data:       data,
-		Attributes: attributes,
+		Data:        data,
+		Attributes:  attributes,
+		OrderingKey: orderingKey,
 	}
+	topic.EnableMessageOrdering = orderingKey != ""
+	defer topic.Stop()
 
 	result := topic.Publish(ctx, msg)
 
 	// Wait for publish to complete
 	_, err = result.Get(ctx)
 	if err != nil {
+		if orderingKey != "" {
+			// A failed ordered publish pauses the key until it is resumed
+			topic.ResumePublish(orderingKey)
+		}
 		return fmt.Errorf("failed to publish message: %w", err)
 	}
 
 	return nil
 }
 
-// SubscribeToTopic subscribes to a Pub/Sub topic with a callback
-func (c *Client) SubscribeToTopic(ctx context.Context, subscriptionName string, callback func(ctx context.Context, msg *pubsub.Message)) error {
+// ensureTopic returns a topic, creating it if it doesn't exist
+func (c *Client) ensureTopic(ctx context.Context, topicName string) (*pubsub.Topic, error) {
+	topic := c.PubSubClient.Topic(topicName)
+
+	exists, err := topic.Exists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check topic existence: %w", err)
+	}
+
+	if !exists {
+		topic, err = c.PubSubClient.CreateTopic(ctx, topicName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create topic: %w", err)
+		}
+	}
+	return topic, nil
+}
+
+// SubscriptionOptions configures the subscription SubscribeToTopic creates when it is missing
+type SubscriptionOptions struct {
+	// Topic the subscription reads from; created if it doesn't exist
+	Topic string
+	// AckDeadline defaults to 30 seconds and RetentionDuration to 24 hours
+	AckDeadline       time.Duration
+	RetentionDuration time.Duration
+	// EnableMessageOrdering delivers messages with the same ordering key in order
+	EnableMessageOrdering bool
+	// DeadLetterTopic receives messages that were delivered MaxDeliveryAttempts
+	// times (default 5) without being acked. The Pub/Sub service agent needs
+	// publish access to it and subscribe access to the subscription.
+	DeadLetterTopic     string
+	MaxDeliveryAttempts int
+}
+
+// SubscribeToTopic subscribes to a Pub/Sub topic with a callback. A missing
+// subscription is an error unless opts is given, in which case it is created.
+func (c *Client) SubscribeToTopic(ctx context.Context, subscriptionName string, opts *SubscriptionOptions, callback func(ctx context.Context, msg *pubsub.Message)) error {
 	sub := c.PubSubClient.Subscription(subscriptionName)
 
 	// Check if subscription exists, create if it doesn't
@@ -297,7 +442,12 @@ func (c *Client) SubscribeToTopic(ctx context.Context, subscriptionName string,
 	}
 
 	if !exists {
-		return fmt.Errorf("subscription %s does not exist", subscriptionName)
+		if opts == nil {
+			return fmt.Errorf("subscription %s does not exist", subscriptionName)
+		}
+		if sub, err = c.createSubscription(ctx, subscriptionName, *opts); err != nil {
+			return err
+		}
 	}
 
 	// Configure subscription settings
@@ -312,6 +462,51 @@ func (c *Client) SubscribeToTopic(ctx context.Context, subscriptionName string,
 	return nil
 }
 
+// createSubscription creates a subscription, and its topics if needed, from opts
+func (c *Client) createSubscription(ctx context.Context, subscriptionName string, opts SubscriptionOptions) (*pubsub.Subscription, error) {
+	if opts.Topic == "" {
+		return nil, fmt.Errorf("a topic is required to create subscription %s", subscriptionName)
+	}
+	topic, err := c.ensureTopic(ctx, opts.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	config := pubsub.SubscriptionConfig{
+		Topic:                 topic,
+		AckDeadline:           opts.AckDeadline,
+		RetentionDuration:     opts.RetentionDuration,
+		EnableMessageOrdering: opts.EnableMessageOrdering,
+	}
+	if config.AckDeadline == 0 {
+		config.AckDeadline = 30 * time.Second
+	}
+	if config.RetentionDuration == 0 {
+		config.RetentionDuration = 24 * time.Hour
+	}
+
+	if opts.DeadLetterTopic != "" {
+		deadLetter, err := c.ensureTopic(ctx, opts.DeadLetterTopic)
+		if err != nil {
+			return nil, err
+		}
+		attempts := opts.MaxDeliveryAttempts
+		if attempts == 0 {
+			attempts = 5
+		}
+		config.DeadLetterPolicy = &pubsub.DeadLetterPolicy{
+			DeadLetterTopic:     deadLetter.String(),
+			MaxDeliveryAttempts: attempts,
+		}
+	}
+
+	sub, err := c.PubSubClient.CreateSubscription(ctx, subscriptionName, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+	return sub, nil
+}
+
 // WaitForServiceReady waits for a Cloud Run service to be ready
 func (c *Client) WaitForServiceReady(ctx context.Context, serviceName string, timeout time.Duration) error {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
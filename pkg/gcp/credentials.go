@@ -0,0 +1,88 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+)
+
+// cloudPlatformScope grants access to every service the clients use
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// CredentialConfig selects the identity GCP clients act as. The zero value uses
+// Application Default Credentials.
+type CredentialConfig struct {
+	// CredentialsFile is a service account JSON key used instead of ADC
+	CredentialsFile string
+	// ImpersonateServiceAccount is a service account email to act as, using the
+	// base credentials to mint its tokens
+	ImpersonateServiceAccount string
+	// Delegates is the chain of service accounts to impersonate through, if any
+	Delegates []string
+	// QuotaProject bills API quota to a project other than the credentials' own
+	QuotaProject string
+}
+
+// LoadCredentialConfig reads credential settings from GCP_CREDENTIALS_FILE,
+// GCP_IMPERSONATE_SERVICE_ACCOUNT, GCP_IMPERSONATE_DELEGATES (comma-separated)
+// and GCP_QUOTA_PROJECT
+func LoadCredentialConfig() CredentialConfig {
+	config := CredentialConfig{
+		CredentialsFile:           os.Getenv("GCP_CREDENTIALS_FILE"),
+		ImpersonateServiceAccount: os.Getenv("GCP_IMPERSONATE_SERVICE_ACCOUNT"),
+		QuotaProject:              os.Getenv("GCP_QUOTA_PROJECT"),
+	}
+	for _, delegate := range strings.Split(os.Getenv("GCP_IMPERSONATE_DELEGATES"), ",") {
+		if delegate = strings.TrimSpace(delegate); delegate != "" {
+			config.Delegates = append(config.Delegates, delegate)
+		}
+	}
+	return config
+}
+
+// ClientOptions returns the client options for config, to pass to NewClient.
+// Credentials are ignored in emulator mode, which needs none.
+func (config CredentialConfig) ClientOptions(ctx context.Context) ([]option.ClientOption, error) {
+	if EmulatorMode() {
+		return nil, nil
+	}
+
+	var opts []option.ClientOption
+	if config.QuotaProject != "" {
+		opts = append(opts, option.WithQuotaProject(config.QuotaProject))
+	}
+
+	var base []option.ClientOption
+	if config.CredentialsFile != "" {
+		if _, err := os.Stat(config.CredentialsFile); err != nil {
+			return nil, fmt.Errorf("failed to read credentials file: %w", err)
+		}
+		base = append(base, option.WithCredentialsFile(config.CredentialsFile))
+	}
+
+	if config.ImpersonateServiceAccount == "" {
+		return append(opts, base...), nil
+	}
+	if err := validateServiceAccount(config.ImpersonateServiceAccount); err != nil {
+		return nil, err
+	}
+	for _, delegate := range config.Delegates {
+		if err := validateServiceAccount(delegate); err != nil {
+			return nil, fmt.Errorf("invalid delegate: %w", err)
+		}
+	}
+
+	tokens, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: config.ImpersonateServiceAccount,
+		Scopes:          []string{cloudPlatformScope},
+		Delegates:       config.Delegates,
+	}, base...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to impersonate %s: %w", config.ImpersonateServiceAccount, err)
+	}
+	return append(opts, option.WithTokenSource(tokens)), nil
+}
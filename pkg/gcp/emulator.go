@@ -0,0 +1,56 @@
+package gcp
+
+import (
+	"os"
+
+	"google.golang.org/api/option"
+)
+
+const (
+	firestoreEmulatorEnv = "FIRESTORE_EMULATOR_HOST"
+	pubsubEmulatorEnv    = "PUBSUB_EMULATOR_HOST"
+
+	// defaultFirestoreEmulatorHost and defaultPubSubEmulatorHost are the gcloud emulators' default addresses
+	defaultFirestoreEmulatorHost = "localhost:8080"
+	defaultPubSubEmulatorHost    = "localhost:8085"
+
+	// EmulatorProjectID is the project used against emulators when GOOGLE_CLOUD_PROJECT is unset
+	EmulatorProjectID = "demo-widescreen"
+)
+
+// EmulatorMode reports whether Firestore or Pub/Sub are served by local emulators
+func EmulatorMode() bool {
+	return os.Getenv(firestoreEmulatorEnv) != "" || os.Getenv(pubsubEmulatorEnv) != ""
+}
+
+// EnableEmulators points Firestore and Pub/Sub at local emulators on their default
+// addresses, keeping any emulator host that is already set
+func EnableEmulators() {
+	if os.Getenv(firestoreEmulatorEnv) == "" {
+		os.Setenv(firestoreEmulatorEnv, defaultFirestoreEmulatorHost)
+	}
+	if os.Getenv(pubsubEmulatorEnv) == "" {
+		os.Setenv(pubsubEmulatorEnv, defaultPubSubEmulatorHost)
+	}
+}
+
+// ProjectID returns GOOGLE_CLOUD_PROJECT, falling back to EmulatorProjectID in emulator mode
+func ProjectID() string {
+	if projectID := os.Getenv("GOOGLE_CLOUD_PROJECT"); projectID != "" {
+		return projectID
+	}
+	if EmulatorMode() {
+		return EmulatorProjectID
+	}
+	return ""
+}
+
+// EmulatorOptions returns client options for services without an emulator, such as
+// Cloud Run and Cloud Monitoring, so they can be created without credentials in
+// emulator mode. Calls to them still fail; only Firestore and Pub/Sub run locally.
+func EmulatorOptions() []option.ClientOption {
+	if !EmulatorMode() {
+		return nil
+	}
+	return []option.ClientOption{option.WithoutAuthentication()}
+}
@@ -0,0 +1,218 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// imageConfigCollection and imageConfigDoc hold live image overrides, one field per drone type
+	imageConfigCollection = "config"
+	imageConfigDoc        = "drone_images"
+
+	// imageCacheTTL is how long a verified image digest is reused before the registry is checked again
+	imageCacheTTL = 5 * time.Minute
+)
+
+// manifestMediaTypes are the manifest formats accepted when checking an image exists
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// resolvedImage is a verified image pinned to its digest
+type resolvedImage struct {
+	pinned  string
+	expires time.Time
+}
+
+// ImageResolver picks the container image for each drone type and verifies it
+// exists in its registry before it is deployed. Images come from, in increasing
+// precedence: the defaults, the JSON file at DRONE_IMAGES_CONFIG, DRONE_IMAGE_<TYPE>
+// environment variables, and the config/drone_images Firestore document.
+type ImageResolver struct {
+	images    map[string]string
+	firestore *firestore.Client
+	http      *http.Client
+
+	mu    sync.Mutex
+	cache map[string]resolvedImage
+}
+
+// NewImageResolver creates a resolver over the given default images. fs may be nil
+// to disable Firestore overrides.
+func NewImageResolver(ctx context.Context, defaults map[string]string, fs *firestore.Client) (*ImageResolver, error) {
+	images := make(map[string]string, len(defaults))
+	for droneType, image := range defaults {
+		images[droneType] = image
+	}
+
+	if path := os.Getenv("DRONE_IMAGES_CONFIG"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read drone images config: %w", err)
+		}
+		var fileImages map[string]string
+		if err := json.Unmarshal(data, &fileImages); err != nil {
+			return nil, fmt.Errorf("failed to parse drone images config: %w", err)
+		}
+		for droneType, image := range fileImages {
+			images[droneType] = image
+		}
+	}
+
+	for _, kv := range os.Environ() {
+		key, image, _ := strings.Cut(kv, "=")
+		if droneType, ok := strings.CutPrefix(key, "DRONE_IMAGE_"); ok && image != "" {
+			images[strings.ToLower(droneType)] = image
+		}
+	}
+
+	for droneType, image := range images {
+		if _, _, _, err := parseImageRef(image); err != nil {
+			return nil, fmt.Errorf("invalid image for drone type %s: %w", droneType, err)
+		}
+	}
+
+	opts := append([]option.ClientOption{option.WithScopes("https://www.googleapis.com/auth/cloud-platform")}, EmulatorOptions()...)
+	httpClient, _, err := htransport.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %w", err)
+	}
+
+	return &ImageResolver{
+		images:    images,
+		firestore: fs,
+		http:      httpClient,
+		cache:     make(map[string]resolvedImage),
+	}, nil
+}
+
+// Configured reports whether an image is configured for a drone type
+func (r *ImageResolver) Configured(droneType string) bool {
+	return r.images[droneType] != ""
+}
+
+// Image returns the image for a drone type pinned to a digest, after checking it exists
+func (r *ImageResolver) Image(ctx context.Context, droneType string) (string, error) {
+	image := r.images[droneType]
+	if override := r.firestoreImage(ctx, droneType); override != "" {
+		image = override
+	}
+	if image == "" {
+		return "", fmt.Errorf("no image configured for drone type %s", droneType)
+	}
+
+	return r.verify(ctx, image)
+}
+
+// firestoreImage returns the live override for a drone type, if any
+func (r *ImageResolver) firestoreImage(ctx context.Context, droneType string) string {
+	if r.firestore == nil {
+		return ""
+	}
+
+	doc, err := r.firestore.Collection(imageConfigCollection).Doc(imageConfigDoc).Get(ctx)
+	if err != nil {
+		if status.Code(err) != codes.NotFound {
+			log.Printf("Warning: Failed to read drone image overrides: %v", err)
+		}
+		return ""
+	}
+
+	image, _ := doc.Data()[droneType].(string)
+	return image
+}
+
+// verify checks the image exists in its registry and returns it pinned to its digest.
+// Images outside Google registries cannot be checked and are returned unchanged.
+func (r *ImageResolver) verify(ctx context.Context, image string) (string, error) {
+	r.mu.Lock()
+	cached, ok := r.cache[image]
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.pinned, nil
+	}
+
+	registry, repository, reference, err := parseImageRef(image)
+	if err != nil {
+		return "", err
+	}
+	if !isGoogleRegistry(registry) {
+		log.Printf("Warning: Cannot verify image %s outside Google registries, deploying as is", image)
+		return image, nil
+	}
+
+	url := fmt.Sprintf("https://%s/v2/%s/manifests/%s", registry, repository, reference)
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to check image %s: %w", image, err)
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to check image %s: %w", image, err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", fmt.Errorf("image %s does not exist", image)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("failed to check image %s: registry returned status %d", image, resp.StatusCode)
+	}
+
+	pinned := image
+	if digest := resp.Header.Get("Docker-Content-Digest"); digest != "" {
+		pinned = fmt.Sprintf("%s/%s@%s", registry, repository, digest)
+	}
+
+	r.mu.Lock()
+	r.cache[image] = resolvedImage{pinned: pinned, expires: time.Now().Add(imageCacheTTL)}
+	r.mu.Unlock()
+
+	return pinned, nil
+}
+
+// parseImageRef splits an image reference such as gcr.io/p/drone:v1 or
+// us-docker.pkg.dev/p/r/drone@sha256:... into registry, repository and tag or digest
+func parseImageRef(image string) (registry, repository, reference string, err error) {
+	registry, rest, ok := strings.Cut(image, "/")
+	if !ok || !strings.ContainsAny(registry, ".:") || rest == "" {
+		return "", "", "", fmt.Errorf("image %q must include a registry host", image)
+	}
+
+	if repo, digest, ok := strings.Cut(rest, "@"); ok {
+		if !strings.HasPrefix(digest, "sha256:") {
+			return "", "", "", fmt.Errorf("image %q has an invalid digest", image)
+		}
+		return registry, repo, digest, nil
+	}
+
+	repository, reference = rest, "latest"
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		repository, reference = rest[:i], rest[i+1:]
+	}
+	return registry, repository, reference, nil
+}
+
+// isGoogleRegistry reports whether a registry host is Container Registry or Artifact Registry
+func isGoogleRegistry(registry string) bool {
+	return registry == "gcr.io" || strings.HasSuffix(registry, ".gcr.io") || strings.HasSuffix(registry, "-docker.pkg.dev")
+}
@@ -0,0 +1,82 @@
+package gcp
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	runpb "cloud.google.com/go/run/apiv2/runpb"
+)
+
+// NetworkConfig controls how drone services reach other networks and who can reach them.
+// Empty fields keep Cloud Run's defaults: no VPC access and public ingress.
+type NetworkConfig struct {
+	// VPCConnector is a Serverless VPC Access connector name or full resource name
+	VPCConnector string
+	// Egress is "private-ranges-only" or "all-traffic"; requires VPCConnector
+	Egress string
+	// Ingress is "all", "internal" or "internal-and-cloud-load-balancing"
+	Ingress string
+}
+
+// LoadNetworkConfig reads drone network settings from DRONE_VPC_CONNECTOR,
+// DRONE_VPC_EGRESS and DRONE_INGRESS
+func LoadNetworkConfig() NetworkConfig {
+	return NetworkConfig{
+		VPCConnector: os.Getenv("DRONE_VPC_CONNECTOR"),
+		Egress:       os.Getenv("DRONE_VPC_EGRESS"),
+		Ingress:      os.Getenv("DRONE_INGRESS"),
+	}
+}
+
+var egressSettings = map[string]runpb.VpcAccess_VpcEgress{
+	"private-ranges-only": runpb.VpcAccess_PRIVATE_RANGES_ONLY,
+	"all-traffic":         runpb.VpcAccess_ALL_TRAFFIC,
+}
+
+var ingressSettings = map[string]runpb.IngressTraffic{
+	"all":                               runpb.IngressTraffic_INGRESS_TRAFFIC_ALL,
+	"internal":                          runpb.IngressTraffic_INGRESS_TRAFFIC_INTERNAL_ONLY,
+	"internal-and-cloud-load-balancing": runpb.IngressTraffic_INGRESS_TRAFFIC_INTERNAL_LOAD_BALANCER,
+}
+
+// ApplyNetworkConfig sets the VPC access and ingress of a Cloud Run service being
+// created in the given project and region
+func ApplyNetworkConfig(service *runpb.Service, projectID, region string, network NetworkConfig) error {
+	if network.Ingress != "" {
+		ingress, ok := ingressSettings[network.Ingress]
+		if !ok {
+			return fmt.Errorf("invalid ingress %q: must be all, internal or internal-and-cloud-load-balancing", network.Ingress)
+		}
+		service.Ingress = ingress
+	}
+
+	if network.VPCConnector == "" {
+		if network.Egress != "" {
+			return fmt.Errorf("egress %q requires a VPC connector", network.Egress)
+		}
+		return nil
+	}
+
+	connector := network.VPCConnector
+	if !strings.HasPrefix(connector, "projects/") {
+		connector = fmt.Sprintf("projects/%s/locations/%s/connectors/%s", projectID, region, connector)
+	}
+
+	egress := runpb.VpcAccess_PRIVATE_RANGES_ONLY
+	if network.Egress != "" {
+		var ok bool
+		if egress, ok = egressSettings[network.Egress]; !ok {
+			return fmt.Errorf("invalid egress %q: must be private-ranges-only or all-traffic", network.Egress)
+		}
+	}
+
+	if service.Template == nil {
+		service.Template = &runpb.RevisionTemplate{}
+	}
+	service.Template.VpcAccess = &runpb.VpcAccess{
+		Connector: connector,
+		Egress:    egress,
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package gcp
+
+import (
+	"fmt"
+
+	runpb "cloud.google.com/go/run/apiv2/runpb"
+	"github.com/spawn-mcp/coordinator/pkg/types"
+)
+
+// maxRequestConcurrency is the highest per-instance concurrency Cloud Run allows
+const maxRequestConcurrency = 1000
+
+var executionEnvironments = map[string]runpb.ExecutionEnvironment{
+	"gen1": runpb.ExecutionEnvironment_EXECUTION_ENVIRONMENT_GEN1,
+	"gen2": runpb.ExecutionEnvironment_EXECUTION_ENVIRONMENT_GEN2,
+}
+
+// ApplyScaling overrides the scaling settings of a Cloud Run service being created
+// with the non-zero fields of scaling
+func ApplyScaling(service *runpb.Service, scaling types.ScalingConfig) error {
+	if service.Template == nil {
+		service.Template = &runpb.RevisionTemplate{}
+	}
+	template := service.Template
+	if template.Scaling == nil {
+		template.Scaling = &runpb.RevisionScaling{}
+	}
+
+	if scaling.MinInstances < 0 || scaling.MaxInstances < 0 {
+		return fmt.Errorf("instance counts must not be negative")
+	}
+	if scaling.MinInstances > 0 {
+		template.Scaling.MinInstanceCount = int32(scaling.MinInstances)
+	}
+	if scaling.MaxInstances > 0 {
+		template.Scaling.MaxInstanceCount = int32(scaling.MaxInstances)
+	}
+	if limit := template.Scaling.MaxInstanceCount; limit > 0 && template.Scaling.MinInstanceCount > limit {
+		return fmt.Errorf("min instances %d exceeds max instances %d", template.Scaling.MinInstanceCount, limit)
+	}
+
+	if scaling.Concurrency < 0 || scaling.Concurrency > maxRequestConcurrency {
+		return fmt.Errorf("concurrency must be between 1 and %d", maxRequestConcurrency)
+	}
+	if scaling.Concurrency > 0 {
+		template.MaxInstanceRequestConcurrency = int32(scaling.Concurrency)
+	}
+
+	switch scaling.CPUAllocation {
+	case "":
+	case "request", "always":
+		// CPU idle lets Cloud Run throttle the CPU when no request is in flight
+		for _, container := range template.Containers {
+			if container.Resources == nil {
+				container.Resources = &runpb.ResourceRequirements{}
+			}
+			container.Resources.CpuIdle = scaling.CPUAllocation == "request"
+		}
+	default:
+		return fmt.Errorf("invalid CPU allocation %q: must be request or always", scaling.CPUAllocation)
+	}
+
+	if scaling.ExecutionEnvironment != "" {
+		env, ok := executionEnvironments[scaling.ExecutionEnvironment]
+		if !ok {
+			return fmt.Errorf("invalid execution environment %q: must be gen1 or gen2", scaling.ExecutionEnvironment)
+		}
+		template.ExecutionEnvironment = env
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package gcp
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DroneServiceAccount returns the service account a drone runs as. An explicit
+// account (e.g. per session) wins, then DRONE_SERVICE_ACCOUNT_<TYPE> for the
+// drone type, then DRONE_SERVICE_ACCOUNT, then drone-service-account in the project.
+func DroneServiceAccount(projectID, droneType, account string) (string, error) {
+	if account == "" {
+		account = os.Getenv("DRONE_SERVICE_ACCOUNT_" + strings.ToUpper(droneType))
+	}
+	if account == "" {
+		account = os.Getenv("DRONE_SERVICE_ACCOUNT")
+	}
+	if account == "" {
+		account = fmt.Sprintf("drone-service-account@%s.iam.gserviceaccount.com", projectID)
+	}
+
+	if err := validateServiceAccount(account); err != nil {
+		return "", err
+	}
+	return account, nil
+}
+
+// validateServiceAccount checks that account is a service account email
+func validateServiceAccount(account string) error {
+	name, domain, ok := strings.Cut(account, "@")
+	if !ok || name == "" || !strings.HasSuffix(domain, ".gserviceaccount.com") {
+		return fmt.Errorf("invalid service account %q: must be a service account email", account)
+	}
+	return nil
+}
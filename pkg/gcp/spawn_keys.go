@@ -0,0 +1,68 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SpawnKeysCollection records the Cloud Run service created for each drone spawn idempotency key
+const SpawnKeysCollection = "drone_spawns"
+
+// SpawnRecord is the drone service an idempotency key was claimed for
+type SpawnRecord struct {
+	Key         string
+	SessionID   string
+	DroneID     string
+	ServiceName string
+	Region      string
+	CreatedAt   time.Time
+}
+
+// SpawnKey is the idempotency key of the index-th drone spawned for a session
+func SpawnKey(sessionID string, index int) string {
+	return fmt.Sprintf("%s-%d", sessionID, index)
+}
+
+// ClaimSpawn atomically records that record.Key creates record's service.
+// When an earlier attempt already claimed the key, its record is returned
+// with claimed false, so the caller can reuse that attempt's service instead
+// of creating a duplicate.
+func ClaimSpawn(ctx context.Context, client *firestore.Client, record SpawnRecord) (SpawnRecord, bool, error) {
+	doc := client.Collection(SpawnKeysCollection).Doc(spawnDocID(record.Key))
+	_, err := doc.Create(ctx, record)
+	if err == nil {
+		return record, true, nil
+	}
+	if status.Code(err) != codes.AlreadyExists {
+		return SpawnRecord{}, false, fmt.Errorf("failed to claim spawn key %s: %w", record.Key, err)
+	}
+
+	snapshot, err := doc.Get(ctx)
+	if err != nil {
+		return SpawnRecord{}, false, fmt.Errorf("failed to get spawn key %s: %w", record.Key, err)
+	}
+	var existing SpawnRecord
+	if err := snapshot.DataTo(&existing); err != nil {
+		return SpawnRecord{}, false, fmt.Errorf("failed to unmarshal spawn key %s: %w", record.Key, err)
+	}
+	return existing, false, nil
+}
+
+// RecordSpawn overwrites the record of a key, e.g. when its drone moved to another region
+func RecordSpawn(ctx context.Context, client *firestore.Client, record SpawnRecord) error {
+	if _, err := client.Collection(SpawnKeysCollection).Doc(spawnDocID(record.Key)).Set(ctx, record); err != nil {
+		return fmt.Errorf("failed to record spawn key %s: %w", record.Key, err)
+	}
+	return nil
+}
+
+// spawnDocID makes a key usable as a Firestore document ID
+func spawnDocID(key string) string {
+	return strings.ReplaceAll(key, "/", "_")
+}
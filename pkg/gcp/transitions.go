@@ -0,0 +1,74 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrInvalidTransition is returned when a document is not in a state that may
+// move to the requested one
+var ErrInvalidTransition = errors.New("invalid state transition")
+
+// StateMachine lists the states each state may move to. States without an entry
+// are terminal. The empty state is the state of a document that does not exist yet.
+type StateMachine map[string][]string
+
+// CanTransition reports whether a document in state from may move to state to
+func (m StateMachine) CanTransition(from, to string) bool {
+	for _, next := range m[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionDocument atomically moves the state held in field of collection/docID
+// to the state to, merging data into the document in the same write. Concurrent
+// transitions are serialized by a Firestore transaction, so a document can never
+// take a transition the machine does not allow. Returns the previous state.
+func TransitionDocument(ctx context.Context, client *firestore.Client, collection, docID, field, to string, machine StateMachine, data map[string]interface{}) (string, error) {
+	ref := client.Collection(collection).Doc(docID)
+
+	var from string
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		from = ""
+		doc, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if current, err := doc.DataAt(field); err == nil {
+				from, _ = current.(string)
+			}
+		}
+
+		if !machine.CanTransition(from, to) {
+			return fmt.Errorf("%w: %s/%s cannot move from %q to %q", ErrInvalidTransition, collection, docID, from, to)
+		}
+
+		update := make(map[string]interface{}, len(data)+1)
+		for k, v := range data {
+			update[k] = v
+		}
+		update[field] = to
+		return tx.Set(ref, update, firestore.MergeAll)
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidTransition) {
+			return from, err
+		}
+		return from, fmt.Errorf("failed to transition %s/%s: %w", collection, docID, err)
+	}
+	return from, nil
+}
+
+// TransitionDocument atomically moves a document's state; see the package-level TransitionDocument
+func (c *Client) TransitionDocument(ctx context.Context, collection, docID, field, to string, machine StateMachine, data map[string]interface{}) (string, error) {
+	return TransitionDocument(ctx, c.FirestoreClient, collection, docID, field, to, machine, data)
+}
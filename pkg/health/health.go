@@ -0,0 +1,172 @@
+// Package health serves the /healthz and /readyz endpoints that Cloud Run and
+// Kubernetes probes and uptime checks call.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/pubsub"
+	"github.com/spawn-mcp/coordinator/pkg/version"
+	"google.golang.org/api/iterator"
+)
+
+// checkTimeout bounds each readiness check, so a hung dependency fails the
+// probe instead of stalling it
+const checkTimeout = 5 * time.Second
+
+// Check reports whether a component can serve requests
+type Check func(ctx context.Context) error
+
+// ComponentStatus is the result of one component's check
+type ComponentStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the body of a health or readiness response
+type Report struct {
+	Status     string                     `json:"status"`
+	Version    string                     `json:"version"`
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+}
+
+// component is a checked component of a binary
+type component struct {
+	check Check
+	// optional components only degrade the binary when they fail
+	optional bool
+}
+
+// Checker runs the readiness checks of a binary's components
+type Checker struct {
+	mu         sync.RWMutex
+	components map[string]component
+}
+
+// NewChecker creates a checker with no checks
+func NewChecker() *Checker {
+	return &Checker{components: make(map[string]component)}
+}
+
+// Add adds the check of a component the binary cannot serve without,
+// replacing any check of the same name
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.components[name] = component{check: check}
+}
+
+// AddOptional adds the check of a component the binary can serve without,
+// such as an external MCP server; its failure is reported but leaves the
+// binary ready
+func (c *Checker) AddOptional(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.components[name] = component{check: check, optional: true}
+}
+
+// Ready runs every check at once and reports each component's status. The
+// report's status is "ok" if every check passed, "degraded" if only optional
+// ones failed, and "unavailable" otherwise.
+func (c *Checker) Ready(ctx context.Context) Report {
+	c.mu.RLock()
+	names := make([]string, 0, len(c.components))
+	for name := range c.components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	components := make([]component, len(names))
+	for i, name := range names {
+		components[i] = c.components[name]
+	}
+	c.mu.RUnlock()
+
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, comp := range components {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+			errs[i] = check(ctx)
+		}(i, comp.check)
+	}
+	wg.Wait()
+
+	report := Report{Status: "ok", Version: version.Version, Components: make(map[string]ComponentStatus, len(names))}
+	for i, name := range names {
+		if errs[i] == nil {
+			report.Components[name] = ComponentStatus{Status: "ok"}
+			continue
+		}
+		report.Components[name] = ComponentStatus{Status: "unavailable", Error: errs[i].Error()}
+		if !components[i].optional {
+			report.Status = "unavailable"
+		} else if report.Status == "ok" {
+			report.Status = "degraded"
+		}
+	}
+	return report
+}
+
+// Register serves GET /healthz and GET /readyz on mux. /healthz answers as long
+// as the process is serving HTTP, so a liveness probe does not restart it over
+// a dependency outage; /readyz runs the checks and answers 503 when a required
+// one fails.
+func (c *Checker) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, http.StatusOK, Report{Status: "ok", Version: version.Version})
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		report := c.Ready(r.Context())
+		status := http.StatusOK
+		if report.Status == "unavailable" {
+			status = http.StatusServiceUnavailable
+		}
+		writeReport(w, status, report)
+	})
+}
+
+// writeReport writes a report as a JSON response
+func writeReport(w http.ResponseWriter, status int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Printf("Warning: Failed to write health report: %v", err)
+	}
+}
+
+// FirestoreCheck checks that Firestore answers a request
+func FirestoreCheck(client *firestore.Client) Check {
+	return func(ctx context.Context) error {
+		if _, err := client.Collections(ctx).Next(); err != nil && !errors.Is(err, iterator.Done) {
+			return err
+		}
+		return nil
+	}
+}
+
+// PubSubTopicCheck checks that a Pub/Sub topic can be reached and exists
+func PubSubTopicCheck(topic *pubsub.Topic) Check {
+	return func(ctx context.Context) error {
+		exists, err := topic.Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("topic %s does not exist", topic.ID())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"errors"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spawn-mcp/coordinator/pkg/coordinator"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+)
+
+// coordinatorErrors are the categories of the coordinator's errors
+var coordinatorErrors = []struct {
+	err      error
+	category mcperrors.Category
+}{
+	{coordinator.ErrDraining, mcperrors.CategoryUnavailable},
+	{coordinator.ErrCircuitOpen, mcperrors.CategoryUnavailable},
+	{gcp.ErrInvalidTransition, mcperrors.CategoryConflict},
+}
+
+// toolError returns a failed call of tool as a tool result whose text is the
+// JSON of its MCPError. The error is logged under the call's correlation ID,
+// which the result carries.
+func toolError(tool, correlationID string, err error) *mcp.CallToolResult {
+	var mcpErr *mcperrors.MCPError
+	if !errors.As(err, &mcpErr) {
+		for _, known := range coordinatorErrors {
+			if errors.Is(err, known.err) {
+				err = mcperrors.New(known.category, "%w", err)
+				break
+			}
+		}
+	}
+	result := *mcperrors.Wrap(err)
+	result.CorrelationID = correlationID
+	log.Printf("Tool %s failed [%s %s]: %v", tool, result.Code, result.CorrelationID, err)
+	return mcp.NewToolResultError(result.JSON())
+}
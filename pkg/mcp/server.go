@@ -2,33 +2,42 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/spawn-mcp/coordinator/pkg/coordinator"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
 	"github.com/spawn-mcp/coordinator/pkg/types"
+	"github.com/spawn-mcp/coordinator/pkg/usage"
+	"github.com/spawn-mcp/coordinator/pkg/version"
 )
 
 // MCPServer wraps the coordinator with MCP protocol support
 type MCPServer struct {
 	coordinator *coordinator.Server
 	mcpServer   *server.MCPServer
+
+	// Calls, errors and latencies of each tool
+	usage *usage.Recorder
 }
 
 // NewMCPServer creates a new MCP server that exposes coordinator functionality
 func NewMCPServer(coord *coordinator.Server) *MCPServer {
 	mcpServer := server.NewMCPServer(
 		"Spawn MCP Coordinator",
-		"1.0.0",
+		version.Version,
 		server.WithToolCapabilities(true),
-		server.WithRecovery(),
 	)
 
 	s := &MCPServer{
 		coordinator: coord,
 		mcpServer:   mcpServer,
+		usage:       usage.NewRecorder(),
 	}
 
 	// Register MCP tools
@@ -51,16 +60,50 @@ func (s *MCPServer) registerTools() {
 			mcp.Description("GCP region to deploy to"),
 			mcp.DefaultString("us-central1"),
 		),
+		mcp.WithString("service_account",
+			mcp.Description("Service account email the drone runs as (defaults to the account configured for the drone type)"),
+		),
+		mcp.WithNumber("min_instances",
+			mcp.Description("Minimum instances kept running (default: 0)"),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("max_instances",
+			mcp.Description("Maximum instances the service scales to (default: 10)"),
+			mcp.Min(1),
+		),
+		mcp.WithNumber("concurrency",
+			mcp.Description("Maximum concurrent requests per instance"),
+			mcp.Min(1),
+			mcp.Max(1000),
+		),
+		mcp.WithString("cpu_allocation",
+			mcp.Description("Whether CPU is allocated only during requests or always"),
+			mcp.Enum("request", "always"),
+		),
+		mcp.WithString("execution_environment",
+			mcp.Description("Cloud Run execution environment"),
+			mcp.Enum("gen1", "gen2"),
+		),
+		mcp.WithString("idempotency_key",
+			mcp.Description("Key that makes a retried call return the drone the first call created instead of spawning another; defaults to session_id and index when both are given"),
+		),
+		mcp.WithString("session_id",
+			mcp.Description("Session the drone belongs to"),
+		),
+		mcp.WithNumber("index",
+			mcp.Description("Position of the drone within its session"),
+			mcp.Min(0),
+		),
 	)
 
-	s.mcpServer.AddTool(spawnDroneTool, s.handleSpawnDrone)
+	s.addTool(spawnDroneTool, s.handleSpawnDrone)
 
 	// Tool: List Active Drones
 	listDronesTool := mcp.NewTool("list_active_drones",
 		mcp.WithDescription("List all currently active drone servers"),
 	)
 
-	s.mcpServer.AddTool(listDronesTool, s.handleListDrones)
+	s.addTool(listDronesTool, s.handleListDrones)
 
 	// Tool: Execute Distributed Task
 	executeTaskTool := mcp.NewTool("execute_distributed_task",
@@ -68,7 +111,7 @@ func (s *MCPServer) registerTools() {
 		mcp.WithString("task_type",
 			mcp.Required(),
 			mcp.Description("Type of task to execute"),
-			mcp.Enum("research", "analysis", "synthesis", "coding"),
+			mcp.Enum("research", "analysis", "synthesis", "writing", "coding"),
 		),
 		mcp.WithString("description",
 			mcp.Required(),
@@ -80,9 +123,29 @@ func (s *MCPServer) registerTools() {
 			mcp.Min(1),
 			mcp.Max(10),
 		),
+		mcp.WithArray("required_capabilities",
+			mcp.Description("Capabilities each drone must have (e.g. web-search, summarization)"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithString("priority",
+			mcp.Description("Scheduling priority; high priority tasks are assigned idle drones first"),
+			mcp.Enum("low", "normal", "high"),
+			mcp.DefaultString("normal"),
+		),
+		mcp.WithNumber("max_retries",
+			mcp.Description("Resume a failed drone's work on another drone from its last checkpoint up to this many times (0 disables checkpointing)"),
+			mcp.DefaultNumber(0),
+			mcp.Min(0),
+			mcp.Max(10),
+		),
+		mcp.WithNumber("concurrency",
+			mcp.Description("Maximum number of drones to call at once (0 uses the coordinator default)"),
+			mcp.DefaultNumber(0),
+			mcp.Min(0),
+		),
 	)
 
-	s.mcpServer.AddTool(executeTaskTool, s.handleExecuteTask)
+	s.addTool(executeTaskTool, s.handleExecuteTask)
 
 	// Tool: Get Drone Status
 	getDroneStatusTool := mcp.NewTool("get_drone_status",
@@ -93,7 +156,7 @@ func (s *MCPServer) registerTools() {
 		),
 	)
 
-	s.mcpServer.AddTool(getDroneStatusTool, s.handleGetDroneStatus)
+	s.addTool(getDroneStatusTool, s.handleGetDroneStatus)
 
 	// Tool: Terminate Drone
 	terminateDroneTool := mcp.NewTool("terminate_drone",
@@ -104,7 +167,44 @@ func (s *MCPServer) registerTools() {
 		),
 	)
 
-	s.mcpServer.AddTool(terminateDroneTool, s.handleTerminateDrone)
+	s.addTool(terminateDroneTool, s.handleTerminateDrone)
+
+	// Tool: Query Task Results
+	queryResultsTool := mcp.NewTool("query_task_results",
+		mcp.WithDescription("Search stored task results by task, drone, status and time range"),
+		mcp.WithString("task_id",
+			mcp.Description("Only results for this task"),
+		),
+		mcp.WithString("drone_id",
+			mcp.Description("Only results from this drone"),
+		),
+		mcp.WithString("status",
+			mcp.Description("Only results with this status"),
+			mcp.Enum("completed", "failed"),
+		),
+		mcp.WithString("since",
+			mcp.Description("Only results at or after this time (RFC 3339)"),
+		),
+		mcp.WithString("until",
+			mcp.Description("Only results at or before this time (RFC 3339)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of results to return"),
+			mcp.DefaultNumber(50),
+			mcp.Min(1),
+			mcp.Max(500),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of matching results to skip"),
+			mcp.DefaultNumber(0),
+			mcp.Min(0),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("next_cursor from a previous call, to fetch the following page"),
+		),
+	)
+
+	s.addTool(queryResultsTool, s.handleQueryTaskResults)
 
 	// New tools for campaign orchestration
 	planCampaign := mcp.NewTool("plan_campaign",
@@ -114,40 +214,83 @@ func (s *MCPServer) registerTools() {
 			mcp.Description("JSON-encoded CampaignSpec"),
 		),
 	)
-	s.mcpServer.AddTool(planCampaign, s.handlePlanCampaign)
+	s.addTool(planCampaign, s.handlePlanCampaign)
 
 	launchFleet := mcp.NewTool("launch_fleet",
 		mcp.WithDescription("Provision worker fleet and seed queue for a campaign run"),
 		mcp.WithString("run_id", mcp.Required()),
 		mcp.WithNumber("target_workers", mcp.DefaultNumber(10), mcp.Min(1), mcp.Max(100)),
 	)
-	s.mcpServer.AddTool(launchFleet, s.handleLaunchFleet)
+	s.addTool(launchFleet, s.handleLaunchFleet)
 
 	fleetStatus := mcp.NewTool("fleet_status",
 		mcp.WithDescription("Get current status and progress for a campaign run"),
 		mcp.WithString("run_id", mcp.Required()),
 	)
-	s.mcpServer.AddTool(fleetStatus, s.handleFleetStatus)
+	s.addTool(fleetStatus, s.handleFleetStatus)
 
 	abort := mcp.NewTool("abort",
 		mcp.WithDescription("Abort a campaign run and scale down workers"),
 		mcp.WithString("run_id", mcp.Required()),
 	)
-	s.mcpServer.AddTool(abort, s.handleAbort)
+	s.addTool(abort, s.handleAbort)
 
 	exportGraph := mcp.NewTool("export_graph",
 		mcp.WithDescription("Export collected graph for a mem0 space or run"),
 		mcp.WithString("mem0_space", mcp.Required()),
 		mcp.WithString("format", mcp.DefaultString("jsonl"), mcp.Enum("jsonl", "csv")),
 	)
-	s.mcpServer.AddTool(exportGraph, s.handleExportGraph)
+	s.addTool(exportGraph, s.handleExportGraph)
+
+	purgeSession := mcp.NewTool("purge_session",
+		mcp.WithDescription("Delete the stored spec, plan, status and drone spawn keys of a campaign run; research sessions are purged with the research server's purge-session-data"),
+		mcp.WithString("session_id", mcp.Required()),
+	)
+	s.addTool(purgeSession, s.handlePurgeSession)
+
+	systemStatus := mcp.NewTool("get_system_status",
+		mcp.WithDescription("Get the coordinator's build version, active drones, queued tasks, and the calls, error rate and latency percentiles of each tool"),
+	)
+	s.addTool(systemStatus, s.handleGetSystemStatus)
+}
+
+// addTool registers a tool whose calls are recorded in the server's usage
+// stats and carry a correlation ID in their result's _meta. A handler's error
+// is returned as a structured error result, and a panic as an MCP-5004 one;
+// see toolError.
+func (s *MCPServer) addTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	s.mcpServer.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		correlationID := mcperrors.NewCorrelationID()
+		ctx = mcperrors.WithCorrelationID(ctx, correlationID)
+		start := time.Now()
+		result, err := callTool(ctx, tool.Name, handler, request)
+		s.usage.Record(tool.Name, start, err)
+		if err != nil {
+			result = toolError(tool.Name, correlationID, err)
+		}
+		if result.Meta == nil {
+			result.Meta = make(map[string]interface{})
+		}
+		result.Meta[mcperrors.CorrelationAttribute] = correlationID
+		return result, nil
+	})
+}
+
+// callTool calls handler, recovering from a panic as an error
+func callTool(ctx context.Context, name string, handler server.ToolHandlerFunc, request mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, mcperrors.Recover(ctx, r, "tool "+name)
+		}
+	}()
+	return handler(ctx, request)
 }
 
 // handleSpawnDrone handles the spawn_drone_server tool call
 func (s *MCPServer) handleSpawnDrone(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	droneType, err := request.RequireString("drone_type")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid drone_type: %v", err)), nil
+		return nil, mcperrors.InvalidInput("invalid drone_type: %w", err)
 	}
 
 	region := request.GetString("region", "us-central1")
@@ -156,19 +299,32 @@ func (s *MCPServer) handleSpawnDrone(ctx context.Context, request mcp.CallToolRe
 
 	// Create drone configuration
 	droneConfig := types.DroneConfig{
-		Type:   types.DroneType(droneType),
-		Region: region,
+		Type:           types.DroneType(droneType),
+		Region:         region,
+		ServiceAccount: request.GetString("service_account", ""),
+		SessionID:      request.GetString("session_id", ""),
+		IdempotencyKey: request.GetString("idempotency_key", ""),
+		Scaling: types.ScalingConfig{
+			MinInstances:         request.GetInt("min_instances", 0),
+			MaxInstances:         request.GetInt("max_instances", 0),
+			Concurrency:          request.GetInt("concurrency", 0),
+			CPUAllocation:        request.GetString("cpu_allocation", ""),
+			ExecutionEnvironment: request.GetString("execution_environment", ""),
+		},
 		Capabilities: []string{
 			"web_search",
 			"data_analysis",
 			"text_generation",
 		},
 	}
+	if index := request.GetInt("index", -1); droneConfig.IdempotencyKey == "" && droneConfig.SessionID != "" && index >= 0 {
+		droneConfig.IdempotencyKey = gcp.SpawnKey(droneConfig.SessionID, index)
+	}
 
 	// Spawn the drone using coordinator
 	droneID, err := s.coordinator.SpawnDrone(ctx, droneConfig)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to spawn drone: %v", err)), nil
+		return nil, fmt.Errorf("failed to spawn drone: %w", err)
 	}
 
 	result := fmt.Sprintf("Successfully spawned drone %s of type %s in region %s", droneID, droneType, region)
@@ -196,12 +352,12 @@ func (s *MCPServer) handleListDrones(ctx context.Context, request mcp.CallToolRe
 func (s *MCPServer) handleExecuteTask(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	taskType, err := request.RequireString("task_type")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid task_type: %v", err)), nil
+		return nil, mcperrors.InvalidInput("invalid task_type: %w", err)
 	}
 
 	description, err := request.RequireString("description")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid description: %v", err)), nil
+		return nil, mcperrors.InvalidInput("invalid description: %w", err)
 	}
 
 	maxDrones := int(request.GetFloat("max_drones", 3))
@@ -210,31 +366,97 @@ func (s *MCPServer) handleExecuteTask(ctx context.Context, request mcp.CallToolR
 
 	// Create task configuration
 	task := types.Task{
-		Type:        taskType,
-		Description: description,
-		MaxDrones:   maxDrones,
+		Type:                 taskType,
+		Description:          description,
+		MaxDrones:            maxDrones,
+		RequiredCapabilities: request.GetStringSlice("required_capabilities", nil),
+		Priority:             types.TaskPriority(request.GetString("priority", "normal")),
+		Concurrency:          request.GetInt("concurrency", 0),
+	}
+	if maxRetries := request.GetInt("max_retries", 0); maxRetries > 0 {
+		task.Checkpoint = types.CheckpointConfig{
+			Enabled:         true,
+			IntervalSeconds: 30,
+			MaxRetries:      maxRetries,
+		}
 	}
 
 	// Execute the task using coordinator
 	taskID, err := s.coordinator.ExecuteTask(ctx, task)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to execute task: %v", err)), nil
+		return nil, fmt.Errorf("failed to execute task: %w", err)
 	}
 
-	result := fmt.Sprintf("Successfully started task %s of type %s using up to %d drones", taskID, taskType, maxDrones)
+	result := fmt.Sprintf("Successfully queued task %s of type %s using up to %d drones", taskID, taskType, maxDrones)
 	return mcp.NewToolResultText(result), nil
 }
 
+// handleQueryTaskResults handles the query_task_results tool call
+func (s *MCPServer) handleQueryTaskResults(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := types.TaskResultQuery{
+		TaskID:  request.GetString("task_id", ""),
+		DroneID: request.GetString("drone_id", ""),
+		Status:  request.GetString("status", ""),
+		Limit:   request.GetInt("limit", 50),
+		Offset:  request.GetInt("offset", 0),
+		Cursor:  request.GetString("cursor", ""),
+	}
+
+	var err error
+	if since := request.GetString("since", ""); since != "" {
+		if query.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return nil, mcperrors.InvalidInput("invalid since: %w", err)
+		}
+	}
+	if until := request.GetString("until", ""); until != "" {
+		if query.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			return nil, mcperrors.InvalidInput("invalid until: %w", err)
+		}
+	}
+
+	results, nextCursor, err := s.coordinator.QueryTaskResults(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task results: %w", err)
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"results":     results,
+		"count":       len(results),
+		"offset":      query.Offset,
+		"next_cursor": nextCursor,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode results: %w", err)
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// handleGetSystemStatus handles the get_system_status tool call. The build
+// identifies which version of the coordinator produced a result, and the tool
+// stats show which tools are failing or slow.
+func (s *MCPServer) handleGetSystemStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	data, err := json.Marshal(map[string]interface{}{
+		"build":         version.Get(),
+		"active_drones": len(s.coordinator.ListActiveDrones()),
+		"queued_tasks":  s.coordinator.QueuedTasks(),
+		"tools":         s.usage.Snapshot(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode status: %w", err)
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
 // handleGetDroneStatus handles the get_drone_status tool call
 func (s *MCPServer) handleGetDroneStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	droneID, err := request.RequireString("drone_id")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid drone_id: %v", err)), nil
+		return nil, mcperrors.InvalidInput("invalid drone_id: %w", err)
 	}
 
 	drone, err := s.coordinator.GetDroneStatus(ctx, droneID)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get drone status: %v", err)), nil
+		return nil, fmt.Errorf("failed to get drone status: %w", err)
 	}
 
 	result := fmt.Sprintf("Drone Status:\n"+
@@ -257,12 +479,12 @@ func (s *MCPServer) handleGetDroneStatus(ctx context.Context, request mcp.CallTo
 func (s *MCPServer) handleTerminateDrone(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	droneID, err := request.RequireString("drone_id")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid drone_id: %v", err)), nil
+		return nil, mcperrors.InvalidInput("invalid drone_id: %w", err)
 	}
 
 	err = s.coordinator.TerminateDrone(ctx, droneID)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to terminate drone: %v", err)), nil
+		return nil, fmt.Errorf("failed to terminate drone: %w", err)
 	}
 
 	result := fmt.Sprintf("Successfully terminated drone %s", droneID)
@@ -3,24 +3,24 @@ package mcp
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
 	"github.com/spawn-mcp/coordinator/pkg/types"
 )
 
 func (s *MCPServer) handlePlanCampaign(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	specJSON, err := request.RequireString("spec_json")
 	if err != nil {
-		return mcp.NewToolResultError("spec_json required"), nil
+		return nil, mcperrors.InvalidInput("spec_json is required")
 	}
 	var spec types.CampaignSpec
 	if err := json.Unmarshal([]byte(specJSON), &spec); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("invalid spec_json: %v", err)), nil
+		return nil, mcperrors.InvalidInput("invalid spec_json: %w", err)
 	}
 	plan, err := s.coordinator.PlanCampaign(ctx, spec)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return nil, err
 	}
 	resBytes, _ := json.Marshal(plan)
 	return mcp.NewToolResultText(string(resBytes)), nil
@@ -28,34 +28,42 @@ func (s *MCPServer) handlePlanCampaign(ctx context.Context, request mcp.CallTool
 
 func (s *MCPServer) handleLaunchFleet(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	runID, err := request.RequireString("run_id")
-	if err != nil { return mcp.NewToolResultError("run_id required"), nil }
+	if err != nil { return nil, mcperrors.InvalidInput("run_id is required") }
 	tw := int(request.GetFloat("target_workers", 10))
 	statusID, err := s.coordinator.LaunchFleet(ctx, runID, tw)
-	if err != nil { return mcp.NewToolResultError(err.Error()), nil }
+	if err != nil { return nil, err }
 	return mcp.NewToolResultText(statusID), nil
 }
 
 func (s *MCPServer) handleFleetStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	runID, err := request.RequireString("run_id")
-	if err != nil { return mcp.NewToolResultError("run_id required"), nil }
+	if err != nil { return nil, mcperrors.InvalidInput("run_id is required") }
 	status, err := s.coordinator.FleetStatus(ctx, runID)
-	if err != nil { return mcp.NewToolResultError(err.Error()), nil }
+	if err != nil { return nil, err }
 	b, _ := json.Marshal(status)
 	return mcp.NewToolResultText(string(b)), nil
 }
 
 func (s *MCPServer) handleAbort(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	runID, err := request.RequireString("run_id")
-	if err != nil { return mcp.NewToolResultError("run_id required"), nil }
-	if err := s.coordinator.AbortRun(ctx, runID); err != nil { return mcp.NewToolResultError(err.Error()), nil }
+	if err != nil { return nil, mcperrors.InvalidInput("run_id is required") }
+	if err := s.coordinator.AbortRun(ctx, runID); err != nil { return nil, err }
 	return mcp.NewToolResultText("aborted"), nil
 }
 
 func (s *MCPServer) handleExportGraph(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	space, err := request.RequireString("mem0_space")
-	if err != nil { return mcp.NewToolResultError("mem0_space required"), nil }
+	if err != nil { return nil, mcperrors.InvalidInput("mem0_space is required") }
 	format := request.GetString("format", "jsonl")
 	uri, err := s.coordinator.ExportGraph(ctx, space, format)
-	if err != nil { return mcp.NewToolResultError(err.Error()), nil }
+	if err != nil { return nil, err }
 	return mcp.NewToolResultText(uri), nil
-}
\ No newline at end of file
+}
+func (s *MCPServer) handlePurgeSession(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sessionID, err := request.RequireString("session_id")
+	if err != nil { return nil, mcperrors.InvalidInput("session_id is required") }
+	deleted, err := s.coordinator.PurgeSession(ctx, sessionID)
+	if err != nil { return nil, err }
+	b, _ := json.Marshal(map[string]any{"session_id": sessionID, "deleted": deleted})
+	return mcp.NewToolResultText(string(b)), nil
+}
@@ -0,0 +1,31 @@
+package mcperrors
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CorrelationAttribute names the correlation ID in Pub/Sub message attributes,
+// drone instructions and Firestore documents
+const CorrelationAttribute = "correlation_id"
+
+// NewCorrelationID returns an ID to trace a tool call through the server's
+// logs, its drones and the documents it stores
+func NewCorrelationID() string {
+	return uuid.NewString()
+}
+
+// correlationKey is the context key of a call's correlation ID
+type correlationKey struct{}
+
+// WithCorrelationID returns a context for the work of the call identified by id
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the ID WithCorrelationID gave ctx, or "" if none
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
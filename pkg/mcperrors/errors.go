@@ -0,0 +1,202 @@
+// Package mcperrors classifies the failures of MCP tool calls, so agents get a
+// stable code, a category and whether to retry instead of parsing free-form
+// error strings.
+package mcperrors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Category groups failures by what the caller can do about them
+type Category string
+
+const (
+	CategoryInternal      Category = "internal"
+	CategoryInvalidInput  Category = "invalid_input"
+	CategoryUnauthorized  Category = "unauthorized"
+	CategoryRateLimited   Category = "rate_limited"
+	CategoryNotFound      Category = "not_found"
+	CategoryQuotaExceeded Category = "quota_exceeded"
+	CategoryTimeout       Category = "timeout"
+	CategoryCancelled     Category = "cancelled"
+	CategoryUnavailable   Category = "unavailable"
+	CategoryUpstream      Category = "upstream"
+	CategoryConflict      Category = "conflict"
+	// CategoryInvalidResult is a drone result that does not match the DroneResult schema
+	CategoryInvalidResult Category = "invalid_result"
+	// CategoryPanic is a handler that panicked; the server recovered and reported it
+	CategoryPanic Category = "panic"
+)
+
+// categoryCodes are the stable error codes of each category
+var categoryCodes = map[Category]string{
+	CategoryInternal:      "MCP-1000",
+	CategoryInvalidInput:  "MCP-1001",
+	CategoryUnauthorized:  "MCP-1002",
+	CategoryRateLimited:   "MCP-1003",
+	CategoryNotFound:      "MCP-1004",
+	CategoryQuotaExceeded: "MCP-1005",
+	CategoryTimeout:       "MCP-1006",
+	CategoryCancelled:     "MCP-1007",
+	CategoryUnavailable:   "MCP-1008",
+	CategoryUpstream:      "MCP-1009",
+	CategoryConflict:      "MCP-1010",
+	CategoryInvalidResult: "MCP-3003",
+	CategoryPanic:         "MCP-5004",
+}
+
+// retryableCategories are the categories whose calls may succeed if repeated unchanged
+var retryableCategories = map[Category]bool{
+	CategoryRateLimited: true,
+	CategoryTimeout:     true,
+	CategoryUnavailable: true,
+	CategoryUpstream:    true,
+}
+
+// MCPError is a classified tool call failure. It is serialized as the text of
+// the tool result.
+type MCPError struct {
+	Code      string   `json:"code"`
+	Category  Category `json:"category"`
+	Message   string   `json:"error"`
+	Retryable bool     `json:"retryable"`
+	// RetryAfter is how many seconds to wait before retrying, when known
+	RetryAfter int `json:"retry_after,omitempty"`
+	// CorrelationID identifies the call in the server's logs
+	CorrelationID string                 `json:"correlation_id,omitempty"`
+	Details       map[string]interface{} `json:"details,omitempty"`
+
+	// Err is the underlying error, if any
+	Err error `json:"-"`
+}
+
+func (e *MCPError) Error() string {
+	return e.Message
+}
+
+func (e *MCPError) Unwrap() error {
+	return e.Err
+}
+
+// JSON returns the error as the JSON text of a tool result
+func (e *MCPError) JSON() string {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Sprintf(`{"code":%q,"category":%q,"error":%q}`, e.Code, e.Category, e.Message)
+	}
+	return string(data)
+}
+
+// New creates an error of category. The message is formatted as by
+// fmt.Errorf, so %w wraps an underlying error.
+func New(category Category, format string, args ...interface{}) *MCPError {
+	err := fmt.Errorf(format, args...)
+	return &MCPError{
+		Code:      categoryCodes[category],
+		Category:  category,
+		Message:   err.Error(),
+		Retryable: retryableCategories[category],
+		Err:       errors.Unwrap(err),
+	}
+}
+
+// InvalidInput creates an error for a call with a missing or malformed argument
+func InvalidInput(format string, args ...interface{}) *MCPError {
+	return New(CategoryInvalidInput, format, args...)
+}
+
+// Wrap returns err as an MCPError: err itself or the MCPError it wraps if
+// there is one, else err classified by its context, gRPC or HTTP status.
+// Unrecognized errors are internal. Wrap returns nil for a nil err.
+func Wrap(err error) *MCPError {
+	if err == nil {
+		return nil
+	}
+	var mcpErr *MCPError
+	if errors.As(err, &mcpErr) {
+		// Keep the outer message, which says what was being done
+		if mcpErr.Message != err.Error() {
+			wrapped := *mcpErr
+			wrapped.Message = err.Error()
+			return &wrapped
+		}
+		return mcpErr
+	}
+	category := classify(err)
+	return &MCPError{
+		Code:      categoryCodes[category],
+		Category:  category,
+		Message:   err.Error(),
+		Retryable: retryableCategories[category],
+		Err:       err,
+	}
+}
+
+// classify picks the category of an error that is not an MCPError
+func classify(err error) Category {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return CategoryTimeout
+	case errors.Is(err, context.Canceled):
+		return CategoryCancelled
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return httpCategory(apiErr.Code)
+	}
+	if s, ok := status.FromError(err); ok && s.Code() != codes.OK && s.Code() != codes.Unknown {
+		return grpcCategory(s.Code())
+	}
+	return CategoryInternal
+}
+
+// grpcCategory maps a gRPC status code, as returned by Google Cloud clients, to a category
+func grpcCategory(code codes.Code) Category {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return CategoryInvalidInput
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return CategoryUnauthorized
+	case codes.NotFound:
+		return CategoryNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return CategoryConflict
+	case codes.ResourceExhausted:
+		return CategoryRateLimited
+	case codes.DeadlineExceeded:
+		return CategoryTimeout
+	case codes.Canceled:
+		return CategoryCancelled
+	case codes.Unavailable, codes.Internal, codes.DataLoss:
+		return CategoryUpstream
+	}
+	return CategoryInternal
+}
+
+// httpCategory maps the HTTP status of a Google API error to a category
+func httpCategory(code int) Category {
+	switch {
+	case code == http.StatusBadRequest:
+		return CategoryInvalidInput
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return CategoryUnauthorized
+	case code == http.StatusNotFound:
+		return CategoryNotFound
+	case code == http.StatusConflict:
+		return CategoryConflict
+	case code == http.StatusTooManyRequests:
+		return CategoryRateLimited
+	case code >= 500:
+		return CategoryUpstream
+	}
+	return CategoryInternal
+}
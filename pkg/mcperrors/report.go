@@ -0,0 +1,76 @@
+package mcperrors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+
+	"github.com/spawn-mcp/coordinator/pkg/version"
+)
+
+// reportedErrorEventType marks a log entry as an error event for Cloud Error Reporting
+const reportedErrorEventType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// errorEvent is a structured log entry that Cloud Error Reporting groups and alerts on
+type errorEvent struct {
+	Severity       string            `json:"severity"`
+	Type           string            `json:"@type"`
+	Message        string            `json:"message"`
+	ServiceContext serviceContext    `json:"serviceContext"`
+	Labels         map[string]string `json:"logging.googleapis.com/labels,omitempty"`
+}
+
+// serviceContext names the binary an error event came from
+type serviceContext struct {
+	Service string `json:"service"`
+	Version string `json:"version"`
+}
+
+// Recover turns a panic recovered while running what into a CategoryPanic
+// error with the stack trace in its details, and reports it. Call it with the
+// result of recover() in a deferred function.
+func Recover(ctx context.Context, value interface{}, what string) *MCPError {
+	stack := debug.Stack()
+	ReportPanic(ctx, value, stack)
+	err := New(CategoryPanic, "%s panicked: %v", what, value)
+	err.Details = map[string]interface{}{"stack": string(stack)}
+	return err
+}
+
+// ReportPanic reports a recovered panic to Cloud Error Reporting, by writing it
+// to stderr in the format Cloud Run's log agent forwards to it, labelled with
+// the call's correlation ID. The message is in the form of an unrecovered Go
+// panic, so the event is grouped with crashes at the same place.
+func ReportPanic(ctx context.Context, value interface{}, stack []byte) {
+	event := errorEvent{
+		Severity: "ERROR",
+		Type:     reportedErrorEventType,
+		Message:  fmt.Sprintf("panic: %v\n\n%s", value, stack),
+		ServiceContext: serviceContext{
+			Service: serviceName(),
+			Version: version.Version,
+		},
+	}
+	if id := CorrelationID(ctx); id != "" {
+		event.Labels = map[string]string{CorrelationAttribute: id}
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Warning: Failed to report panic %v: %v\n%s", value, err, stack)
+		return
+	}
+	// Not through log, whose prefix would stop the line parsing as JSON; stdout may carry MCP
+	fmt.Fprintln(os.Stderr, string(data))
+}
+
+// serviceName is the Cloud Run service the binary runs as, or else the binary's name
+func serviceName() string {
+	if service := os.Getenv("K_SERVICE"); service != "" {
+		return service
+	}
+	return filepath.Base(os.Args[0])
+}
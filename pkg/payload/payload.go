@@ -0,0 +1,337 @@
+// Package payload encodes drone results as Pub/Sub messages, which are limited
+// to 10MB. Large results are gzip-compressed, and the data of results still too
+// large is stored in Cloud Storage with a pointer to it in the message.
+package payload
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+	"github.com/spawn-mcp/coordinator/pkg/encryption"
+	"github.com/spawn-mcp/coordinator/pkg/gcp"
+	"github.com/spawn-mcp/coordinator/pkg/mcperrors"
+	"google.golang.org/api/googleapi"
+	storage "google.golang.org/api/storage/v1"
+)
+
+const (
+	// MaxMessageBytes is the most a Pub/Sub message's data may hold
+	MaxMessageBytes = 10 * 1000 * 1000
+	// MaxDecodedBytes is the most a message or offloaded object may hold once
+	// decompressed, so a small compressed payload cannot exhaust memory
+	MaxDecodedBytes = 4 * MaxMessageBytes
+
+	// EncodingAttribute is the message attribute naming how its data is encoded
+	EncodingAttribute = "content_encoding"
+	// EncodingGzip marks message data compressed with gzip
+	EncodingGzip = "gzip"
+
+	// objectPrefix is where offloaded result data is stored in the bucket
+	objectPrefix = "results"
+)
+
+// Config configures how drone results are encoded
+type Config struct {
+	// Bucket stores the data of results too large for a message; empty disables offloading
+	Bucket string
+	// CompressAbove is the encoded size, in bytes, above which results are compressed
+	CompressAbove int
+	// OffloadAbove is the message size, in bytes, above which result data is stored in Bucket
+	OffloadAbove int
+	// KMSKey is the customer-managed key offloaded data is encrypted with; empty uses the bucket's default
+	KMSKey string
+}
+
+// LoadConfig reads the result encoding configuration from the environment
+func LoadConfig() Config {
+	config := Config{
+		Bucket:        os.Getenv("RESULT_BUCKET"),
+		CompressAbove: 256 * 1024,
+		OffloadAbove:  8 * 1000 * 1000,
+		KMSKey:        encryption.LoadConfig().StorageKey,
+	}
+	if kb, err := strconv.Atoi(os.Getenv("RESULT_COMPRESS_ABOVE_KB")); err == nil && kb >= 0 {
+		config.CompressAbove = kb * 1024
+	}
+	if kb, err := strconv.Atoi(os.Getenv("RESULT_OFFLOAD_ABOVE_KB")); err == nil && kb > 0 && kb*1024 <= MaxMessageBytes {
+		config.OffloadAbove = kb * 1024
+	}
+	return config
+}
+
+// Codec encodes drone results as messages and decodes them again
+type Codec struct {
+	config Config
+	// storage holds offloaded data; nil until first needed
+	storage   *storage.Service
+	storageMu sync.Mutex
+}
+
+// NewCodec creates a codec. The Cloud Storage client is created when results
+// are first offloaded or resolved.
+func NewCodec(config Config) *Codec {
+	return &Codec{config: config}
+}
+
+// Bucket returns the bucket result data is offloaded to, or "" when offloading is disabled
+func (c *Codec) Bucket() string {
+	return c.config.Bucket
+}
+
+// ObjectPrefix returns the prefix under which the data of a topic's results is stored
+func ObjectPrefix(topicID string) string {
+	return path.Join(objectPrefix, topicID) + "/"
+}
+
+// Encode returns the message data and attributes for a result published to
+// topicID. A result whose data would make the message too large, compressed or
+// not, has the data stored in the codec's bucket and DataRef pointing to it.
+func (c *Codec) Encode(ctx context.Context, topicID string, result schemas.DroneResult) ([]byte, map[string]string, error) {
+	data, attributes, size, err := c.encode(result)
+	if err != nil {
+		return nil, nil, err
+	}
+	if (len(data) <= c.config.OffloadAbove && size <= MaxDecodedBytes) || result.Data == nil {
+		return c.checkSize(data, attributes, size)
+	}
+	if c.config.Bucket == "" {
+		return nil, nil, mcperrors.New(mcperrors.CategoryInvalidResult, "result of %d bytes is too large to publish and RESULT_BUCKET is not set", len(data))
+	}
+
+	taskID := result.TaskID
+	if taskID == "" {
+		taskID = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	object := path.Join(ObjectPrefix(topicID), result.DroneID, taskID+".json.gz")
+	ref, err := c.offload(ctx, object, result.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.Data = nil
+	result.DataRef = ref
+	if data, attributes, size, err = c.encode(result); err != nil {
+		return nil, nil, err
+	}
+	return c.checkSize(data, attributes, size)
+}
+
+// encode marshals a result, compressing it above CompressAbove, and returns
+// its size before compression with it
+func (c *Codec) encode(result schemas.DroneResult) ([]byte, map[string]string, int, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, nil, 0, mcperrors.New(mcperrors.CategoryInvalidResult, "failed to marshal result: %w", err)
+	}
+	size := len(data)
+	attributes := map[string]string{}
+	if size > c.config.CompressAbove {
+		if data, err = compress(data); err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to compress result: %w", err)
+		}
+		attributes[EncodingAttribute] = EncodingGzip
+	}
+	return data, attributes, size, nil
+}
+
+// checkSize rejects message data Pub/Sub would not accept, or that Decode
+// would refuse to decompress
+func (c *Codec) checkSize(data []byte, attributes map[string]string, size int) ([]byte, map[string]string, error) {
+	if len(data) > MaxMessageBytes {
+		return nil, nil, mcperrors.New(mcperrors.CategoryInvalidResult, "encoded result of %d bytes exceeds the %d byte message limit", len(data), MaxMessageBytes)
+	}
+	if size > MaxDecodedBytes {
+		return nil, nil, mcperrors.New(mcperrors.CategoryInvalidResult, "result of %d bytes exceeds the %d byte decoded limit", size, MaxDecodedBytes)
+	}
+	return data, attributes, nil
+}
+
+// Decode reads a result from message data and attributes. Offloaded data is
+// not loaded; see Resolve.
+func (c *Codec) Decode(data []byte, attributes map[string]string) (schemas.DroneResult, error) {
+	var result schemas.DroneResult
+	if attributes[EncodingAttribute] == EncodingGzip {
+		var err error
+		if data, err = decompress(data); err != nil {
+			return result, mcperrors.New(mcperrors.CategoryInvalidResult, "failed to decompress result: %w", err)
+		}
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, mcperrors.New(mcperrors.CategoryInvalidResult, "failed to unmarshal result: %w", err)
+	}
+	return result, nil
+}
+
+// Resolve loads the data of a result published to topicID that was offloaded
+// to Cloud Storage. DataRef is kept, so the result can be stored without its
+// data again.
+func (c *Codec) Resolve(ctx context.Context, topicID string, result *schemas.DroneResult) error {
+	if result.DataRef == "" || result.Data != nil {
+		return nil
+	}
+	return c.Load(ctx, ObjectPrefix(topicID), result.DataRef, &result.Data)
+}
+
+// Store saves value in the codec's bucket at object as compressed JSON, and
+// returns its gs:// URI for Load
+func (c *Codec) Store(ctx context.Context, object string, value interface{}) (string, error) {
+	if c.config.Bucket == "" {
+		return "", fmt.Errorf("RESULT_BUCKET is not set")
+	}
+	return c.offload(ctx, object, value)
+}
+
+// Load reads the compressed JSON at a gs:// URI, as saved by Store or an
+// offloaded result, into value. The URI comes from drones, so only objects
+// under prefix in the codec's bucket are read.
+func (c *Codec) Load(ctx context.Context, prefix, uri string, value interface{}) error {
+	object, err := c.objectName(prefix, uri)
+	if err != nil {
+		return err
+	}
+	service, err := c.service(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := service.Objects.Get(c.config.Bucket, object).Context(ctx).Download()
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", uri, err)
+	}
+	defer resp.Body.Close()
+	compressed, err := io.ReadAll(io.LimitReader(resp.Body, MaxDecodedBytes))
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", uri, err)
+	}
+	data, err := decompress(compressed)
+	if err != nil {
+		return mcperrors.New(mcperrors.CategoryInvalidResult, "failed to decompress %s: %w", uri, err)
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return mcperrors.New(mcperrors.CategoryInvalidResult, "failed to unmarshal %s: %w", uri, err)
+	}
+	return nil
+}
+
+// objectName returns the object a gs:// URI names, if it is under prefix in
+// the codec's bucket
+func (c *Codec) objectName(prefix, uri string) (string, error) {
+	rest, ok := strings.CutPrefix(uri, "gs://")
+	if !ok {
+		return "", mcperrors.New(mcperrors.CategoryInvalidResult, "data_ref %q is not a gs:// URI", uri)
+	}
+	bucket, object, _ := strings.Cut(rest, "/")
+	if c.config.Bucket == "" || bucket != c.config.Bucket || !strings.HasPrefix(object, prefix) || len(object) == len(prefix) {
+		return "", mcperrors.New(mcperrors.CategoryInvalidResult, "data_ref %q is not under gs://%s/%s", uri, c.config.Bucket, prefix)
+	}
+	return object, nil
+}
+
+// DeleteObjects deletes the offloaded data stored under prefix and returns the
+// gs:// URIs of the deleted objects. Objects deleted before a failure are
+// returned with the error.
+func (c *Codec) DeleteObjects(ctx context.Context, prefix string) ([]string, error) {
+	if c.config.Bucket == "" {
+		return nil, nil
+	}
+	service, err := c.service(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var deleted []string
+	err = service.Objects.List(c.config.Bucket).Prefix(prefix).Pages(ctx, func(objects *storage.Objects) error {
+		for _, object := range objects.Items {
+			if err := service.Objects.Delete(c.config.Bucket, object.Name).Context(ctx).Do(); err != nil {
+				return fmt.Errorf("failed to delete gs://%s/%s: %w", c.config.Bucket, object.Name, err)
+			}
+			deleted = append(deleted, fmt.Sprintf("gs://%s/%s", c.config.Bucket, object.Name))
+		}
+		return nil
+	})
+	if err != nil {
+		return deleted, fmt.Errorf("failed to delete objects under gs://%s/%s: %w", c.config.Bucket, prefix, err)
+	}
+	return deleted, nil
+}
+
+// offload stores data, compressed, and returns its gs:// URI
+func (c *Codec) offload(ctx context.Context, object string, data interface{}) (string, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", mcperrors.New(mcperrors.CategoryInvalidResult, "failed to marshal result data: %w", err)
+	}
+	if len(encoded) > MaxDecodedBytes {
+		return "", mcperrors.New(mcperrors.CategoryInvalidResult, "result data of %d bytes exceeds the %d byte decoded limit", len(encoded), MaxDecodedBytes)
+	}
+	if encoded, err = compress(encoded); err != nil {
+		return "", fmt.Errorf("failed to compress result data: %w", err)
+	}
+	service, err := c.service(ctx)
+	if err != nil {
+		return "", err
+	}
+	insert := service.Objects.Insert(c.config.Bucket, &storage.Object{Name: object}).
+		Media(bytes.NewReader(encoded), googleapi.ContentType("application/gzip"))
+	if c.config.KMSKey != "" {
+		insert = insert.KmsKeyName(c.config.KMSKey)
+	}
+	_, err = insert.Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to upload result data to gs://%s/%s: %w", c.config.Bucket, object, err)
+	}
+	return fmt.Sprintf("gs://%s/%s", c.config.Bucket, object), nil
+}
+
+// service returns the Cloud Storage client, creating it on first use
+func (c *Codec) service(ctx context.Context) (*storage.Service, error) {
+	c.storageMu.Lock()
+	defer c.storageMu.Unlock()
+	if c.storage != nil {
+		return c.storage, nil
+	}
+	service, err := storage.NewService(context.WithoutCancel(ctx), gcp.EmulatorOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Storage client: %w", err)
+	}
+	c.storage = service
+	return service, nil
+}
+
+// compress gzips data
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress gunzips data, failing once it exceeds MaxDecodedBytes
+func decompress(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	decoded, err := io.ReadAll(io.LimitReader(reader, MaxDecodedBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) > MaxDecodedBytes {
+		return nil, fmt.Errorf("decompressed data exceeds %d bytes", MaxDecodedBytes)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,68 @@
+package payload
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/spawn-mcp/coordinator/cmd/widescreen-research-mcp/schemas"
+)
+
+func TestObjectName(t *testing.T) {
+	c := NewCodec(Config{Bucket: "results-bucket"})
+	prefix := ObjectPrefix("research-results-s1")
+	tests := []struct {
+		name string
+		uri  string
+		want string
+		ok   bool
+	}{
+		{"object under the prefix", "gs://results-bucket/results/research-results-s1/d1/t1.json.gz", "results/research-results-s1/d1/t1.json.gz", true},
+		{"another bucket", "gs://other-bucket/results/research-results-s1/d1/t1.json.gz", "", false},
+		{"another session's prefix", "gs://results-bucket/results/research-results-s2/d1/t1.json.gz", "", false},
+		{"prefix of a longer topic", "gs://results-bucket/results/research-results-s10/d1/t1.json.gz", "", false},
+		{"the prefix itself", "gs://results-bucket/results/research-results-s1/", "", false},
+		{"not a gs:// URI", "https://results-bucket/results/research-results-s1/d1/t1.json.gz", "", false},
+		{"bucket only", "gs://results-bucket", "", false},
+	}
+	for _, tt := range tests {
+		got, err := c.objectName(prefix, tt.uri)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("%s: objectName(%q) = (%q, %v), want (%q, ok %v)", tt.name, tt.uri, got, err, tt.want, tt.ok)
+		}
+	}
+
+	if _, err := NewCodec(Config{}).objectName(prefix, "gs:///results/research-results-s1/d1/t1.json.gz"); err == nil {
+		t.Error("objectName() without a bucket configured returned no error")
+	}
+}
+
+func TestDecompressLimit(t *testing.T) {
+	small, err := compress([]byte(`{"drone_id":"d1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := decompress(small); err != nil || string(got) != `{"drone_id":"d1"}` {
+		t.Errorf("decompress() = (%q, %v), want the original data", got, err)
+	}
+
+	// A few tens of kilobytes that expand past the limit
+	bomb, err := compress(bytes.Repeat([]byte{0}, MaxDecodedBytes+1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decompress(bomb); err == nil {
+		t.Errorf("decompress() of %d bytes expanding past %d returned no error", len(bomb), MaxDecodedBytes)
+	}
+}
+
+func TestEncodeOffloadsResultsTooLargeToDecode(t *testing.T) {
+	// Compresses to well under a message, but Decode would refuse it
+	result := schemas.DroneResult{DroneID: "d1", Data: map[string]interface{}{
+		"blob": string(bytes.Repeat([]byte{'a'}, MaxDecodedBytes)),
+	}}
+	c := NewCodec(Config{CompressAbove: 1024, OffloadAbove: MaxMessageBytes})
+	if _, _, err := c.Encode(context.Background(), "research-results-s1", result); err == nil {
+		t.Error("Encode() of a result too large to decode, without a bucket, returned no error")
+	}
+}
@@ -0,0 +1,171 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// exaProvider searches with Exa's neural search
+type exaProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+func (p *exaProvider) Name() string { return ProviderExa }
+
+func (p *exaProvider) Search(ctx context.Context, query Query) ([]Result, error) {
+	body := map[string]interface{}{
+		"query":      query.Text,
+		"numResults": maxResults(query, 100),
+		"contents":   map[string]interface{}{"text": map[string]interface{}{"maxCharacters": 1000}},
+	}
+	if len(query.Domains) > 0 {
+		body["includeDomains"] = query.Domains
+	}
+	req, err := postJSON(ctx, "https://api.exa.ai/search", body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-api-key", p.apiKey)
+
+	var resp struct {
+		Results []struct {
+			Title         string  `json:"title"`
+			URL           string  `json:"url"`
+			Text          string  `json:"text"`
+			PublishedDate string  `json:"publishedDate"`
+			Score         float64 `json:"score"`
+		} `json:"results"`
+	}
+	if err := do(p.client, req, ProviderExa, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(resp.Results))
+	for i, r := range resp.Results {
+		results[i] = Result{Title: r.Title, URL: r.URL, Snippet: r.Text, PublishedAt: r.PublishedDate, Score: r.Score, Provider: ProviderExa}
+	}
+	return results, nil
+}
+
+// tavilyProvider searches with Tavily's search API
+type tavilyProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+func (p *tavilyProvider) Name() string { return ProviderTavily }
+
+func (p *tavilyProvider) Search(ctx context.Context, query Query) ([]Result, error) {
+	body := map[string]interface{}{
+		"query":       query.Text,
+		"max_results": maxResults(query, 20),
+	}
+	if len(query.Domains) > 0 {
+		body["include_domains"] = query.Domains
+	}
+	req, err := postJSON(ctx, "https://api.tavily.com/search", body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	var resp struct {
+		Results []struct {
+			Title         string  `json:"title"`
+			URL           string  `json:"url"`
+			Content       string  `json:"content"`
+			PublishedDate string  `json:"published_date"`
+			Score         float64 `json:"score"`
+		} `json:"results"`
+	}
+	if err := do(p.client, req, ProviderTavily, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(resp.Results))
+	for i, r := range resp.Results {
+		results[i] = Result{Title: r.Title, URL: r.URL, Snippet: r.Content, PublishedAt: r.PublishedDate, Score: r.Score, Provider: ProviderTavily}
+	}
+	return results, nil
+}
+
+// braveProvider searches with the Brave Search web API
+type braveProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+func (p *braveProvider) Name() string { return ProviderBrave }
+
+func (p *braveProvider) Search(ctx context.Context, query Query) ([]Result, error) {
+	params := url.Values{}
+	params.Set("q", withSites(query.Text, query.Domains))
+	params.Set("count", strconv.Itoa(maxResults(query, 20)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.search.brave.com/res/v1/web/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Subscription-Token", p.apiKey)
+
+	var resp struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+				PageAge     string `json:"page_age"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	if err := do(p.client, req, ProviderBrave, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(resp.Web.Results))
+	for i, r := range resp.Web.Results {
+		results[i] = Result{Title: r.Title, URL: r.URL, Snippet: r.Description, PublishedAt: r.PageAge, Score: rankScore(i, len(resp.Web.Results)), Provider: ProviderBrave}
+	}
+	return results, nil
+}
+
+// serpAPIProvider searches Google through SerpAPI
+type serpAPIProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+func (p *serpAPIProvider) Name() string { return ProviderSerpAPI }
+
+func (p *serpAPIProvider) Search(ctx context.Context, query Query) ([]Result, error) {
+	params := url.Values{}
+	params.Set("engine", "google")
+	params.Set("q", withSites(query.Text, query.Domains))
+	params.Set("num", strconv.Itoa(maxResults(query, 100)))
+	params.Set("api_key", p.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://serpapi.com/search.json?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		OrganicResults []struct {
+			Title   string `json:"title"`
+			Link    string `json:"link"`
+			Snippet string `json:"snippet"`
+			Date    string `json:"date"`
+		} `json:"organic_results"`
+	}
+	if err := do(p.client, req, ProviderSerpAPI, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(resp.OrganicResults))
+	for i, r := range resp.OrganicResults {
+		results[i] = Result{Title: r.Title, URL: r.Link, Snippet: r.Snippet, PublishedAt: r.Date, Score: rankScore(i, len(resp.OrganicResults)), Provider: ProviderSerpAPI}
+	}
+	return results, nil
+}
@@ -0,0 +1,238 @@
+// Package search queries web search APIs (Exa, Tavily, Brave and SerpAPI)
+// behind one interface, so drones are not tied to a single vendor. Every
+// provider returns the same Result shape and is rate limited per vendor.
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Provider names, as set in SEARCH_PROVIDER
+const (
+	ProviderExa     = "exa"
+	ProviderTavily  = "tavily"
+	ProviderBrave   = "brave"
+	ProviderSerpAPI = "serpapi"
+)
+
+// providers are each provider's API key variable and default calls per minute
+var providers = map[string]struct {
+	keyEnv    string
+	perMinute int
+}{
+	ProviderExa:     {keyEnv: "EXA_API_KEY", perMinute: 300},
+	ProviderTavily:  {keyEnv: "TAVILY_API_KEY", perMinute: 100},
+	ProviderBrave:   {keyEnv: "BRAVE_API_KEY", perMinute: 60},
+	ProviderSerpAPI: {keyEnv: "SERPAPI_API_KEY", perMinute: 60},
+}
+
+// Query is a web search
+type Query struct {
+	Text       string
+	MaxResults int
+	// Domains restricts results to these sites, e.g. "arxiv.org"
+	Domains []string
+}
+
+// Result is one search hit, in the same shape whichever provider found it
+type Result struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Snippet string `json:"snippet,omitempty"`
+	// PublishedAt is the publication date as the provider gave it, if known
+	PublishedAt string `json:"published_at,omitempty"`
+	// Score is the provider's relevance score, or a rank-based one between 0 and 1
+	Score    float64 `json:"score"`
+	Provider string  `json:"provider"`
+}
+
+// Provider searches the web
+type Provider interface {
+	Name() string
+	Search(ctx context.Context, query Query) ([]Result, error)
+}
+
+// Config selects the search provider
+type Config struct {
+	// Provider is exa, tavily, brave or serpapi
+	Provider string
+	APIKey   string
+	// PerMinute limits the provider's calls from this process
+	PerMinute int
+}
+
+// LoadConfig reads the search provider from SEARCH_PROVIDER (default exa), its
+// key from the provider's API key variable, and its rate limit from
+// SEARCH_RATE_LIMIT_PER_MINUTE
+func LoadConfig() Config {
+	config := Config{Provider: strings.ToLower(os.Getenv("SEARCH_PROVIDER"))}
+	if config.Provider == "" {
+		config.Provider = ProviderExa
+	}
+	if p, ok := providers[config.Provider]; ok {
+		config.APIKey = os.Getenv(p.keyEnv)
+		config.PerMinute = p.perMinute
+	}
+	if value := os.Getenv("SEARCH_RATE_LIMIT_PER_MINUTE"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			config.PerMinute = n
+		} else {
+			log.Printf("Warning: Ignoring invalid SEARCH_RATE_LIMIT_PER_MINUTE %q", value)
+		}
+	}
+	return config
+}
+
+// KeyEnv returns the environment variable holding a provider's API key
+func KeyEnv(provider string) string {
+	return providers[provider].keyEnv
+}
+
+// New creates the configured provider, rate limited. It returns nil when the
+// provider has no API key, so callers can fall back to working without search.
+func New(config Config) (Provider, error) {
+	if _, ok := providers[config.Provider]; !ok {
+		return nil, fmt.Errorf("unknown search provider %q", config.Provider)
+	}
+	if config.APIKey == "" {
+		return nil, nil
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	var provider Provider
+	switch config.Provider {
+	case ProviderExa:
+		provider = &exaProvider{apiKey: config.APIKey, client: client}
+	case ProviderTavily:
+		provider = &tavilyProvider{apiKey: config.APIKey, client: client}
+	case ProviderBrave:
+		provider = &braveProvider{apiKey: config.APIKey, client: client}
+	case ProviderSerpAPI:
+		provider = &serpAPIProvider{apiKey: config.APIKey, client: client}
+	}
+	return &rateLimited{Provider: provider, limiter: limiterFor(config.Provider, config.PerMinute)}, nil
+}
+
+var (
+	limitersMu sync.Mutex
+	// limiters are shared by every provider of the same vendor in this process
+	limiters = make(map[string]*rate.Limiter)
+)
+
+// limiterFor returns the vendor's token bucket of perMinute calls per minute
+func limiterFor(provider string, perMinute int) *rate.Limiter {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	limiter, ok := limiters[provider]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(float64(perMinute)/60), perMinute)
+		limiters[provider] = limiter
+	}
+	return limiter
+}
+
+// rateLimited waits for the vendor's rate limit before each search
+type rateLimited struct {
+	Provider
+	limiter *rate.Limiter
+}
+
+func (r *rateLimited) Search(ctx context.Context, query Query) ([]Result, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("failed to wait for %s rate limit: %w", r.Name(), err)
+	}
+	return r.Provider.Search(ctx, query)
+}
+
+// StatusError is a search API's refusal of a request
+type StatusError struct {
+	Provider string
+	Status   int
+	Body     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s search failed with status %d: %s", e.Provider, e.Status, e.Body)
+}
+
+// do sends a search request and decodes its JSON response into out
+func do(client *http.Client, req *http.Request, provider string, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		// The request URL may carry the API key
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to call %s: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{Provider: provider, Status: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", provider, err)
+	}
+	return nil
+}
+
+// postJSON builds a POST request with a JSON body
+func postJSON(ctx context.Context, endpoint string, body interface{}) (*http.Request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// withSites restricts a query to domains with site: operators, for providers
+// without a domain filter
+func withSites(text string, domains []string) string {
+	if len(domains) == 0 {
+		return text
+	}
+	sites := make([]string, len(domains))
+	for i, domain := range domains {
+		sites[i] = "site:" + domain
+	}
+	return fmt.Sprintf("%s (%s)", text, strings.Join(sites, " OR "))
+}
+
+// rankScore scores results of providers without relevance scores by rank
+func rankScore(rank, total int) float64 {
+	return 1 - float64(rank)/float64(total)
+}
+
+// maxResults defaults a query's result count
+func maxResults(query Query, limit int) int {
+	n := query.MaxResults
+	if n <= 0 {
+		n = 10
+	}
+	if n > limit {
+		n = limit
+	}
+	return n
+}
@@ -13,6 +13,9 @@ const (
 	DroneTypeProcessor   DroneType = "processor"
 	DroneTypeResearcher  DroneType = "researcher"
 	DroneTypeSynthesizer DroneType = "synthesizer"
+	DroneTypeAnalyst     DroneType = "analyst"
+	DroneTypeWriter      DroneType = "writer"
+	DroneTypeCoder       DroneType = "coder"
 )
 
 // DroneStatus represents the current state of a drone
@@ -33,6 +36,28 @@ type DroneConfig struct {
 	Resources    ResourceRequirements `json:"resources"`
 	Capabilities []string             `json:"capabilities"`
 	Environment  map[string]string    `json:"environment"`
+	// SessionID labels the drone's Cloud Run service so its usage can be attributed to a campaign run
+	SessionID string `json:"sessionId,omitempty"`
+	// ServiceAccount overrides the service account configured for the drone type
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// Scaling overrides the Cloud Run scaling defaults for the drone's service
+	Scaling ScalingConfig `json:"scaling,omitempty"`
+	// IdempotencyKey makes retried spawns reuse the drone an earlier attempt
+	// created; see gcp.SpawnKey
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+}
+
+// ScalingConfig tunes how a Cloud Run service scales. Zero values keep the
+// defaults of whatever is creating the service.
+type ScalingConfig struct {
+	MinInstances int `json:"minInstances,omitempty"`
+	MaxInstances int `json:"maxInstances,omitempty"`
+	// Concurrency is the maximum number of requests each instance handles at once
+	Concurrency int `json:"concurrency,omitempty"`
+	// CPUAllocation is "request" to throttle CPU between requests or "always" to keep it allocated
+	CPUAllocation string `json:"cpuAllocation,omitempty"`
+	// ExecutionEnvironment is "gen1" or "gen2"
+	ExecutionEnvironment string `json:"executionEnvironment,omitempty"`
 }
 
 // ResourceRequirements specifies CPU and memory requirements
@@ -86,6 +111,44 @@ type TaskCheckpoint struct {
 	RetryCount int                    `json:"retryCount"`
 }
 
+// CheckpointCollection is the Firestore collection holding task checkpoints
+const CheckpointCollection = "task_checkpoints"
+
+// CheckpointID returns the document ID of a drone's checkpoint for a task
+func CheckpointID(taskID, droneID string) string {
+	return taskID + "_" + droneID
+}
+
+// DroneHeartbeat is the liveness signal a drone publishes while it runs
+type DroneHeartbeat struct {
+	DroneID string `json:"droneId"`
+	// TaskID is the task the drone is working on, empty when idle
+	TaskID    string    `json:"taskId,omitempty"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// DefaultHeartbeatTopic is the Pub/Sub topic drones publish heartbeats to
+const DefaultHeartbeatTopic = "drone-heartbeats"
+
+// DroneMetrics is a drone's own account of the work it has done and the
+// resources it uses. Counters are totals since the drone started, so the
+// latest report of a drone supersedes its earlier ones.
+type DroneMetrics struct {
+	DroneID string `json:"droneId"`
+	// SessionID is the session the drone's latest task belonged to
+	SessionID       string    `json:"sessionId,omitempty"`
+	RequestsHandled int64     `json:"requestsHandled"`
+	TasksCompleted  int64     `json:"tasksCompleted"`
+	TasksFailed     int64     `json:"tasksFailed"`
+	SearchCalls     int64     `json:"searchCalls"`
+	LLMTokens       int64     `json:"llmTokens"`
+	MemoryBytes     uint64    `json:"memoryBytes"`
+	Goroutines      int       `json:"goroutines"`
+	UptimeSeconds   float64   `json:"uptimeSeconds"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
 // TaskResult represents the output from a drone
 type TaskResult struct {
 	TaskID    string      `json:"taskId"`
@@ -96,6 +159,19 @@ type TaskResult struct {
 	Timestamp time.Time   `json:"timestamp"`
 }
 
+// TaskResultQuery filters stored task results; zero-valued fields are not filtered on
+type TaskResultQuery struct {
+	TaskID  string    `json:"taskId,omitempty"`
+	DroneID string    `json:"droneId,omitempty"`
+	Status  string    `json:"status,omitempty"`
+	Since   time.Time `json:"since,omitempty"`
+	Until   time.Time `json:"until,omitempty"`
+	Limit   int       `json:"limit,omitempty"`
+	Offset  int       `json:"offset,omitempty"`
+	// Cursor continues from the last result of a previous page
+	Cursor string `json:"cursor,omitempty"`
+}
+
 // ExecutionPlan represents a plan for distributed execution
 type ExecutionPlan struct {
 	ID               string            `json:"id"`
@@ -105,6 +181,7 @@ type ExecutionPlan struct {
 	EstimatedTime    time.Duration     `json:"estimatedTime"`
 	Strategy         string            `json:"strategy"`
 	DroneAllocations []DroneAllocation `json:"droneAllocations"`
+	CreatedAt        time.Time         `json:"createdAt"`
 }
 
 // DroneAllocation represents how work is allocated to a drone
@@ -122,9 +199,26 @@ type CostEstimate struct {
 	Confidence float64            `json:"confidence"`
 }
 
+// TaskPriority controls the order in which queued tasks are assigned drones
+type TaskPriority string
+
+const (
+	TaskPriorityLow    TaskPriority = "low"
+	TaskPriorityNormal TaskPriority = "normal"
+	TaskPriorityHigh   TaskPriority = "high"
+)
+
 // Task represents a task to be executed by drones
 type Task struct {
 	Type        string `json:"type"`
 	Description string `json:"description"`
 	MaxDrones   int    `json:"maxDrones"`
+	// RequiredCapabilities lists capabilities a drone must have to run the task
+	RequiredCapabilities []string `json:"requiredCapabilities,omitempty"`
+	// Priority defaults to normal when empty
+	Priority TaskPriority `json:"priority,omitempty"`
+	// Checkpoint controls progress checkpoints and resumption on drone failure
+	Checkpoint CheckpointConfig `json:"checkpoint"`
+	// Concurrency caps how many drones are called at once; 0 uses the coordinator default
+	Concurrency int `json:"concurrency,omitempty"`
 }
@@ -0,0 +1,124 @@
+// Package usage records how often each tool or operation is called, how often
+// it fails and how long it takes, so operators can see which ones are failing
+// or slow.
+package usage
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// window is how many of a tool's most recent calls its latency percentiles cover
+const window = 1024
+
+// Stats are the calls, errors and latencies of one tool
+type Stats struct {
+	Calls     int64   `json:"calls"`
+	Errors    int64   `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+	// Latency percentiles over the most recent calls, in milliseconds
+	P50Millis  float64 `json:"p50_ms"`
+	P90Millis  float64 `json:"p90_ms"`
+	P99Millis  float64 `json:"p99_ms"`
+	MaxMillis  float64 `json:"max_ms"`
+	LastCalled string  `json:"last_called,omitempty"`
+	LastError  string  `json:"last_error,omitempty"`
+}
+
+// toolUsage is what a Recorder keeps for one tool
+type toolUsage struct {
+	calls     int64
+	errors    int64
+	max       time.Duration
+	last      time.Time
+	lastError string
+	// latencies is a ring of the most recent call durations
+	latencies []time.Duration
+	next      int
+}
+
+// Recorder records the calls of each tool. It is safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	tools map[string]*toolUsage
+}
+
+// NewRecorder creates an empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{tools: make(map[string]*toolUsage)}
+}
+
+// Record adds a call of tool that started at started and failed with err, if not nil
+func (r *Recorder) Record(tool string, started time.Time, err error) {
+	elapsed := time.Since(started)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u := r.tools[tool]
+	if u == nil {
+		u = &toolUsage{}
+		r.tools[tool] = u
+	}
+	u.calls++
+	if err != nil {
+		u.errors++
+		u.lastError = err.Error()
+	}
+	if elapsed > u.max {
+		u.max = elapsed
+	}
+	u.last = started
+	if len(u.latencies) < window {
+		u.latencies = append(u.latencies, elapsed)
+	} else {
+		u.latencies[u.next] = elapsed
+		u.next = (u.next + 1) % window
+	}
+}
+
+// Snapshot returns the stats of every tool called so far, by name
+func (r *Recorder) Snapshot() map[string]Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snapshot := make(map[string]Stats, len(r.tools))
+	for name, u := range r.tools {
+		latencies := make([]time.Duration, len(u.latencies))
+		copy(latencies, u.latencies)
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		snapshot[name] = Stats{
+			Calls:      u.calls,
+			Errors:     u.errors,
+			ErrorRate:  float64(u.errors) / float64(u.calls),
+			P50Millis:  millis(percentile(latencies, 0.50)),
+			P90Millis:  millis(percentile(latencies, 0.90)),
+			P99Millis:  millis(percentile(latencies, 0.99)),
+			MaxMillis:  millis(u.max),
+			LastCalled: u.last.UTC().Format(time.RFC3339),
+			LastError:  u.lastError,
+		}
+	}
+	return snapshot
+}
+
+// percentile returns the p-th percentile of sorted latencies by the nearest-rank method
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// millis converts a duration to milliseconds, keeping sub-millisecond precision
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,80 @@
+// Package version reports which build of widescreen is running. Release builds
+// set the variables with -ldflags, e.g.
+//
+//	go build -ldflags "-X github.com/spawn-mcp/coordinator/pkg/version.Version=v1.2.0 \
+//	  -X github.com/spawn-mcp/coordinator/pkg/version.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/spawn-mcp/coordinator/pkg/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/widescreen
+//
+// Builds without them fall back to the VCS information the Go toolchain embeds.
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	// Version is the release version
+	Version = "dev"
+	// Commit is the git commit the build was made from
+	Commit = ""
+	// BuildDate is when the build was made, in RFC 3339
+	BuildDate = ""
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+	Modified  bool   `json:"modified,omitempty"` // built from a tree with uncommitted changes
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the running build's information
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	return info
+}
+
+// String formats the build information on one line
+func (i Info) String() string {
+	s := i.Version
+	if i.Commit != "" {
+		commit := i.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		if i.Modified {
+			commit += "-dirty"
+		}
+		s += " (" + commit
+		if i.BuildDate != "" {
+			s += ", built " + i.BuildDate
+		}
+		s += ")"
+	}
+	return fmt.Sprintf("%s %s", s, i.GoVersion)
+}
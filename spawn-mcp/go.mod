@@ -1,3 +0,0 @@
-module github.com/spawn-mcp/coordinator
-
-go 1.23.6
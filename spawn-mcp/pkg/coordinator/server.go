@@ -1,14 +0,0 @@
-package coordinator
-
-import "log"
-
-type Server struct{}
-
-func NewServer() *Server {
-	return &Server{}
-}
-
-func (s *Server) Serve() error {
-	log.Println("Coordinator running...")
-	select {}
-}
\ No newline at end of file